@@ -1,42 +1,311 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"poke-battles/internal/audit"
+	"poke-battles/internal/backplane"
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
 	"poke-battles/internal/middleware"
+	"poke-battles/internal/replay"
 	"poke-battles/internal/routes"
 	"poke-battles/internal/services"
+	"poke-battles/internal/sessions"
 	"poke-battles/internal/websocket"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
 	server := gin.Default()
 
-	// Middleware
-	server.Use(middleware.CORS())
+	// Client addresses, as counted against MAX_CONNECTIONS_PER_IP, come from X-Forwarded-For only on requests from
+	// the comma-separated TRUSTED_PROXIES (addresses or CIDR ranges, e.g. "10.0.0.0/8"); by default none are trusted
+	// and every client's address is the one it connected from, so a client cannot pick its own
+	var trustedProxies []string
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		for _, proxy := range strings.Split(value, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				trustedProxies = append(trustedProxies, proxy)
+			}
+		}
+	}
+	if err := server.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err)
+	}
 
-	// Services
-	lobbyService := services.NewLobbyService()
+	// Middleware, allowing browsers on the comma-separated ALLOWED_ORIGINS (e.g. "https://*.example.com") to call the API
+	allowedOrigins := middleware.DefaultAllowedOrigins
+	if value := os.Getenv("ALLOWED_ORIGINS"); value != "" {
+		allowedOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				allowedOrigins = append(allowedOrigins, origin)
+			}
+		}
+	}
+	server.Use(middleware.CORS(allowedOrigins))
 
-	// WebSocket Hub
+	// Services, letting each player play in MAX_LOBBIES_PER_PLAYER lobbies at once (0 for no limit)
+	// and generating room codes ROOM_CODE_LENGTH characters long, publishing their changes on the event bus
+	bus := events.NewBus()
+	lobbyConfig := services.LobbyServiceConfig{
+		MaxLobbiesPerPlayer: services.DefaultMaxLobbiesPerPlayer,
+		RoomCodeLength:      game.DefaultRoomCodeLength,
+		Events:              bus,
+	}
+	if value := os.Getenv("MAX_LOBBIES_PER_PLAYER"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		lobbyConfig.MaxLobbiesPerPlayer = limit
+	}
+	if value := os.Getenv("ROOM_CODE_LENGTH"); value != "" {
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		if !game.ValidRoomCodeLength(length) {
+			panic(fmt.Sprintf("ROOM_CODE_LENGTH must be between %d and %d", game.MinRoomCodeLength, game.MaxRoomCodeLength))
+		}
+		lobbyConfig.RoomCodeLength = length
+	}
+	lobbyService := services.NewLobbyServiceWithConfig(lobbyConfig)
+	replayStore := replay.NewMemoryStore()
+	auditTrail := audit.NewMemoryTrail(audit.DefaultCapacity)
+	battleService := services.NewBattleService(replayStore, auditTrail, bus)
+
+	// WebSocket Hub, capping simultaneous connections at MAX_CONNECTIONS_PER_PLAYER per player
+	// and MAX_CONNECTIONS_PER_IP per address (0 for no limit)
 	hub := websocket.NewHub()
+	connectionLimits := websocket.ConnectionLimits{
+		PerPlayer: websocket.DefaultMaxConnectionsPerPlayer,
+		PerIP:     websocket.DefaultMaxConnectionsPerIP,
+	}
+	if value := os.Getenv("MAX_CONNECTIONS_PER_PLAYER"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		connectionLimits.PerPlayer = limit
+	}
+	if value := os.Getenv("MAX_CONNECTIONS_PER_IP"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		connectionLimits.PerIP = limit
+	}
+	hub.SetConnectionLimits(connectionLimits)
+
+	// Websocket logs, as JSON on stdout at LOG_LEVEL (debug, info, warn or error; debug traces every message)
+	logLevel := slog.LevelInfo
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := logLevel.UnmarshalText([]byte(value)); err != nil {
+			panic(err)
+		}
+	}
+	hub.SetLogger(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})))
+
+	// Connection keepalive and lifetimes, e.g. longer waits for mobile networks: WS_WRITE_WAIT, WS_PONG_WAIT,
+	// WS_PING_PERIOD (9/10 of the pong wait unless set), WS_SESSION_DURATION, WS_RECONNECT_TOKEN_DURATION and
+	// WS_AUTH_TIMEOUT (e.g. "90s"), and WS_MAX_MESSAGE_SIZE in bytes and WS_SEND_BUFFER_SIZE in messages
+	connectionConfig := websocket.DefaultConnectionConfig()
+	for name, setting := range map[string]*time.Duration{
+		"WS_WRITE_WAIT":               &connectionConfig.WriteWait,
+		"WS_PONG_WAIT":                &connectionConfig.PongWait,
+		"WS_PING_PERIOD":              &connectionConfig.PingPeriod,
+		"WS_SESSION_DURATION":         &connectionConfig.SessionDuration,
+		"WS_RECONNECT_TOKEN_DURATION": &connectionConfig.ReconnectTokenDuration,
+		"WS_AUTH_TIMEOUT":             &connectionConfig.AuthTimeout,
+	} {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				panic(err)
+			}
+			*setting = d
+		}
+	}
+	if os.Getenv("WS_PING_PERIOD") == "" {
+		connectionConfig.PingPeriod = connectionConfig.PongWait * 9 / 10
+	}
+	if value := os.Getenv("WS_MAX_MESSAGE_SIZE"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+		connectionConfig.MaxMessageSize = size
+	}
+	if value := os.Getenv("WS_SEND_BUFFER_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		connectionConfig.SendBufferSize = size
+	}
+	if err := hub.SetConnectionConfig(connectionConfig); err != nil {
+		panic(err)
+	}
+
+	// Backplane and session store, sharing lobby broadcasts and players' sessions with the other instances behind
+	// the load balancer through the Redis at REDIS_URL (e.g. "redis://localhost:6379/0"), under channels and keys
+	// named with REDIS_PREFIX
+	if value := os.Getenv("REDIS_URL"); value != "" {
+		options, err := redis.ParseURL(value)
+		if err != nil {
+			panic(err)
+		}
+		prefix := backplane.DefaultRedisPrefix
+		if value := os.Getenv("REDIS_PREFIX"); value != "" {
+			prefix = value
+		}
+		redisClient := redis.NewClient(options)
+		defer redisClient.Close()
+		redisBackplane := backplane.NewRedis(redisClient, prefix)
+		defer redisBackplane.Close()
+		hub.SetBackplane(redisBackplane)
+		hub.SetSessionStore(sessions.NewRedisStore(redisClient, prefix, connectionConfig.SessionDuration))
+	}
 	go hub.Run()
 
-	// WebSocket Handler
-	wsHandler := websocket.NewHandler(hub, lobbyService)
+	// WebSocket Handler, giving players who drop out of a battle DISCONNECT_GRACE (e.g. "90s") to return before they lose
+	wsHandler := websocket.NewHandler(hub, lobbyService, battleService)
+	wsHandler.Subscribe(bus)
+	if value := os.Getenv("DISCONNECT_GRACE"); value != "" {
+		grace, err := time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+		wsHandler.SetDisconnectGrace(grace)
+	}
+
+	// Websockets may be opened from the same origins as the API, or from any origin when WS_ALLOW_ANY_ORIGIN is
+	// set to true, which is for development only
+	originPolicy := websocket.OriginPolicy{AllowedOrigins: allowedOrigins}
+	if value := os.Getenv("WS_ALLOW_ANY_ORIGIN"); value != "" {
+		allowAny, err := strconv.ParseBool(value)
+		if err != nil {
+			panic(err)
+		}
+		originPolicy.AllowAny = allowAny
+	}
+	wsHandler.SetOriginPolicy(originPolicy)
+
+	// Session tokens: with SESSION_TOKEN_SECRET set, players must present a token signed with it (see README).
+	// Without it every player_id is trusted, which is only for development, so WS_REQUIRE_SESSION_AUTH set to true
+	// refuses to start without a secret.
+	var sessionAuth websocket.SessionAuth
+	if secret := os.Getenv("SESSION_TOKEN_SECRET"); secret != "" {
+		validate, err := websocket.NewSignedSessionValidator([]byte(secret))
+		if err != nil {
+			panic(err)
+		}
+		sessionAuth.Validate = validate
+	}
+	if value := os.Getenv("WS_REQUIRE_SESSION_AUTH"); value != "" {
+		required, err := strconv.ParseBool(value)
+		if err != nil {
+			panic(err)
+		}
+		if required && sessionAuth.Validate == nil {
+			panic("WS_REQUIRE_SESSION_AUTH is set but SESSION_TOKEN_SECRET is not")
+		}
+		sessionAuth.Required = required
+	}
+	wsHandler.SetSessionAuth(sessionAuth)
+
+	// Compression for clients that negotiate permessage-deflate, at deflate level WS_COMPRESSION_LEVEL (-2 to 9)
+	// for messages of at least WS_COMPRESSION_THRESHOLD bytes
+	compressionLevel, compressionThreshold := websocket.DefaultCompressionLevel, websocket.DefaultCompressionThreshold
+	if value := os.Getenv("WS_COMPRESSION_LEVEL"); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		compressionLevel = level
+	}
+	if value := os.Getenv("WS_COMPRESSION_THRESHOLD"); value != "" {
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		compressionThreshold = threshold
+	}
+	if err := wsHandler.SetCompression(compressionLevel, compressionThreshold); err != nil {
+		panic(err)
+	}
+
+	// Lobby janitor, removing abandoned lobbies after LOBBY_IDLE_TTL (e.g. "15m")
+	idleTTL := services.DefaultLobbyIdleTTL
+	if value := os.Getenv("LOBBY_IDLE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+		idleTTL = ttl
+	}
+	stopJanitor := lobbyService.StartJanitor(services.LobbyJanitorConfig{
+		IdleTTL:           idleTTL,
+		Interval:          services.DefaultJanitorInterval,
+		IsPlayerConnected: hub.IsPlayerConnected,
+	})
+	defer stopJanitor()
 
 	// Routes
-	routes.RegisterRoutes(server, lobbyService, wsHandler)
+	// Operator-only routes such as /ws/connections need ADMIN_TOKEN as a bearer token, and are closed without one
+	routes.RegisterRoutes(server, lobbyService, battleService, replayStore, wsHandler, os.Getenv("ADMIN_TOKEN"))
+
+	// On shutdown, websocket clients get up to SHUTDOWN_TIMEOUT (e.g. "15s") to be sent what is queued
+	// for them before their connections close, and are told to reconnect after SHUTDOWN_RECONNECT_AFTER
+	shutdownTimeout := 10 * time.Second
+	if value := os.Getenv("SHUTDOWN_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+		shutdownTimeout = timeout
+	}
+	if value := os.Getenv("SHUTDOWN_RECONNECT_AFTER"); value != "" {
+		after, err := time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+		hub.SetShutdownReconnectAfter(after)
+	}
 
-	// Run server
+	// Run server until SIGINT or SIGTERM
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
+	httpServer := &http.Server{Addr: ":" + port, Handler: server}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
 
-	if err := server.Run(":" + port); err != nil {
-		panic(err)
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	// Drain websocket clients before the HTTP server stops, since it does not track hijacked connections
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	hub.Shutdown(shutdownCtx)
+	httpServer.Shutdown(shutdownCtx)
 }
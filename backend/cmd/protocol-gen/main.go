@@ -0,0 +1,49 @@
+// Command protocol-gen writes TypeScript definitions and a JSON Schema of the websocket protocol, generated
+// from the message types in internal/websocket, so the frontend and bots always have types matching the server.
+// From the backend directory, run it with:
+//
+//	go generate ./internal/websocket
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	src := flag.String("src", "internal/websocket", "directory of the websocket package, read for doc comments and enum values")
+	tsPath := flag.String("ts", "../frontend/src/types/protocol.ts", "file to write the TypeScript definitions to")
+	schemaPath := flag.String("schema", "../frontend/src/types/protocol.schema.json", "file to write the JSON Schema to")
+	flag.Parse()
+
+	ts, schema, err := generate(*src)
+	if err == nil {
+		err = os.WriteFile(*tsPath, ts, 0o644)
+	}
+	if err == nil {
+		err = os.WriteFile(*schemaPath, schema, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "protocol-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate describes the protocol, reading the websocket package's source in dir,
+// and returns its TypeScript definitions and JSON Schema
+func generate(dir string) (ts, schema []byte, err error) {
+	src, err := loadSource(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := buildProtocol(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err = writeSchema(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return writeTypeScript(p), schema, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"poke-battles/internal/websocket"
+)
+
+func TestGenerate(t *testing.T) {
+	ts, schema, err := generate("../../internal/websocket")
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	// Every message is typed by its payload, and string types with constants list their values
+	for _, want := range []string{
+		"  authenticate: AuthenticatePayload;\n",
+		"  chat_message: ChatMessageBroadcastPayload;\n",
+		"  capabilities?: ClientCapabilities;\n",
+		"export type HeartbeatPayload = Record<string, never>;\n",
+		"export type ErrorCode =\n  | \"AUTH_REQUIRED\"",
+		"export interface MoveUsedEventData {\n",
+	} {
+		if !strings.Contains(string(ts), want) {
+			t.Errorf("expected the TypeScript to contain %q", want)
+		}
+	}
+
+	var parsed struct {
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		t.Fatalf("expected the schema to be JSON: %v", err)
+	}
+	for _, msg := range append(websocket.ClientMessages(), websocket.ServerMessages()...) {
+		if !bytes.Contains(schema, []byte(`"const": "`+string(msg.Type)+`"`)) {
+			t.Errorf("expected the schema to describe %s", msg.Type)
+		}
+	}
+	for _, name := range []string{"Envelope", "ClientMessage", "ServerMessage", "AuthenticatePayload", "LobbyEvent"} {
+		if _, ok := parsed.Defs[name]; !ok {
+			t.Errorf("expected the schema to define %s", name)
+		}
+	}
+}
+
+// TestGenerate_UpToDate fails when the message types have changed without the frontend's copy being regenerated
+func TestGenerate_UpToDate(t *testing.T) {
+	committedTS, err := os.ReadFile("../../../frontend/src/types/protocol.ts")
+	if os.IsNotExist(err) {
+		t.Skip("frontend not checked out")
+	}
+	if err != nil {
+		t.Fatalf("failed to read the TypeScript: %v", err)
+	}
+	committedSchema, err := os.ReadFile("../../../frontend/src/types/protocol.schema.json")
+	if err != nil {
+		t.Fatalf("failed to read the schema: %v", err)
+	}
+
+	ts, schema, err := generate("../../internal/websocket")
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !bytes.Equal(ts, committedTS) || !bytes.Equal(schema, committedSchema) {
+		t.Error("protocol types are out of date, run `go generate ./internal/websocket`")
+	}
+}
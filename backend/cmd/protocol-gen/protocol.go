@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"poke-battles/internal/websocket"
+)
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	websocketPkg   = reflect.TypeOf(websocket.Envelope{}).PkgPath()
+)
+
+// kind is what sort of JSON value a type is sent as
+type kind int
+
+const (
+	kindString kind = iota
+	kindInteger
+	kindNumber
+	kindBoolean
+	kindAny
+	kindArray
+	kindMap
+	kindObject // An unnamed struct, described where it is used
+	kindRef    // A named type of the websocket package, described in its own definition
+)
+
+// typeRef describes the type of a value
+type typeRef struct {
+	kind     kind
+	name     string   // The definition's name, for kindRef
+	elem     *typeRef // The type of array elements and map values
+	fields   []field  // The fields of an unnamed struct
+	nullable bool     // Sent as null when unset
+}
+
+// field is a field of an object as it is sent
+type field struct {
+	name     string // As in JSON
+	doc      string
+	typ      typeRef
+	optional bool // Left out when empty
+}
+
+// definition is a named type of the protocol: an object, or another type under a name, such as a string
+// with a fixed set of values
+type definition struct {
+	name   string
+	doc    string
+	object bool
+	fields []field  // An object's fields
+	alias  typeRef  // What any other type is sent as
+	enum   []string // The values a string type can have, if the package declares any
+}
+
+// message is a message type and the payload it carries
+type message struct {
+	msgType string
+	payload typeRef
+}
+
+// protocol describes every message of the websocket protocol and the types they carry
+type protocol struct {
+	source   *source
+	envelope definition
+	client   []message
+	server   []message
+	defs     []*definition // In the order they were first used
+	byName   map[string]*definition
+}
+
+// buildProtocol describes the messages the websocket package lists and every type they carry
+func buildProtocol(src *source) (*protocol, error) {
+	p := &protocol{source: src, byName: make(map[string]*definition)}
+
+	envelopeType := reflect.TypeOf(websocket.Envelope{})
+	fields, err := p.structFields(envelopeType)
+	if err != nil {
+		return nil, err
+	}
+	p.envelope = definition{name: envelopeType.Name(), doc: src.typeDocs[envelopeType.Name()], object: true, fields: fields}
+
+	if p.client, err = p.messages(websocket.ClientMessages()); err != nil {
+		return nil, err
+	}
+	if p.server, err = p.messages(websocket.ServerMessages()); err != nil {
+		return nil, err
+	}
+	for _, value := range websocket.ProtocolTypes() {
+		if _, err := p.refFor(reflect.TypeOf(value)); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// messages describes the listed messages
+func (p *protocol) messages(listed []websocket.ProtocolMessage) ([]message, error) {
+	messages := make([]message, len(listed))
+	for i, msg := range listed {
+		payload, err := p.refFor(reflect.TypeOf(msg.Payload))
+		if err != nil {
+			return nil, fmt.Errorf("%s payload: %w", msg.Type, err)
+		}
+		messages[i] = message{msgType: string(msg.Type), payload: payload}
+	}
+	return messages, nil
+}
+
+// refFor describes a type, defining it first if it is a named type of the websocket package
+func (p *protocol) refFor(t reflect.Type) (typeRef, error) {
+	switch {
+	case t == rawMessageType:
+		return typeRef{kind: kindAny}, nil
+	case t.Kind() == reflect.Pointer:
+		ref, err := p.refFor(t.Elem())
+		ref.nullable = true
+		return ref, err
+	case t.Name() != "" && t.PkgPath() == websocketPkg:
+		return typeRef{kind: kindRef, name: t.Name()}, p.define(t)
+	}
+	return p.underlyingRef(t)
+}
+
+// underlyingRef describes what a type is sent as, whatever its name
+func (p *protocol) underlyingRef(t reflect.Type) (typeRef, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return typeRef{kind: kindString}, nil
+	case reflect.Bool:
+		return typeRef{kind: kindBoolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typeRef{kind: kindInteger}, nil
+	case reflect.Float32, reflect.Float64:
+		return typeRef{kind: kindNumber}, nil
+	case reflect.Interface:
+		return typeRef{kind: kindAny}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return typeRef{kind: kindString}, nil // Sent base64 encoded
+		}
+		elem, err := p.refFor(t.Elem())
+		return typeRef{kind: kindArray, elem: &elem}, err
+	case reflect.Map:
+		elem, err := p.refFor(t.Elem())
+		return typeRef{kind: kindMap, elem: &elem}, err
+	case reflect.Struct:
+		fields, err := p.structFields(t)
+		return typeRef{kind: kindObject, fields: fields}, err
+	}
+	return typeRef{}, fmt.Errorf("%s cannot be described", t)
+}
+
+// define adds a definition of a named type, unless it already has one
+func (p *protocol) define(t reflect.Type) error {
+	if _, ok := p.byName[t.Name()]; ok {
+		return nil
+	}
+	def := &definition{name: t.Name(), doc: p.source.typeDocs[t.Name()]}
+	p.byName[def.name] = def // Before its fields, so types that refer to themselves are defined once
+	p.defs = append(p.defs, def)
+
+	var err error
+	if t.Kind() == reflect.Struct {
+		def.object = true
+		def.fields, err = p.structFields(t)
+		return err
+	}
+	def.alias, err = p.underlyingRef(t)
+	if t.Kind() == reflect.String {
+		def.enum = p.source.enums[t.Name()]
+	}
+	return err
+}
+
+// structFields describes the fields of a struct as encoding/json sends them, with the fields of embedded
+// structs in their place unless the struct has its own by the same name
+func (p *protocol) structFields(t reflect.Type) ([]field, error) {
+	var fields []field
+	promoted := make(map[int]bool)
+	own := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		embedded := f.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if f.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			inner, err := p.structFields(embedded)
+			if err != nil {
+				return nil, err
+			}
+			for _, innerField := range inner {
+				promoted[len(fields)] = true
+				fields = append(fields, innerField)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		ref, err := p.refFor(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		optional := strings.Contains(","+options+",", ",omitempty,")
+		if optional {
+			ref.nullable = false // Left out rather than sent as null
+		}
+		own[name] = true
+		fields = append(fields, field{name: name, doc: p.source.fieldDocs[t.Name()][f.Name], typ: ref, optional: optional})
+	}
+
+	kept := fields[:0]
+	for i, f := range fields {
+		if !promoted[i] || !own[f.name] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// schemaObject is a JSON Schema, or part of one. encoding/json writes its keys sorted, so the output is stable.
+type schemaObject map[string]interface{}
+
+// writeSchema returns a JSON Schema that every message of the protocol, from either side, is valid against
+func writeSchema(p *protocol) ([]byte, error) {
+	defs := schemaObject{}
+	for _, def := range p.defs {
+		defs[def.name] = definitionSchema(def)
+	}
+	defs["Envelope"] = definitionSchema(&p.envelope)
+	defs["ClientMessage"] = messagesSchema("A message clients send", p.client)
+	defs["ServerMessage"] = messagesSchema("A message the server sends", p.server)
+
+	schema := schemaObject{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "Poke Battles websocket protocol",
+		"description": "Generated by protocol-gen from backend/internal/websocket. Do not edit; run `go generate ./internal/websocket` in backend to update it.",
+		"anyOf":       []interface{}{refSchema("ClientMessage"), refSchema("ServerMessage")},
+		"$defs":       defs,
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// messagesSchema returns the schema of the messages one side sends, each an envelope of its type and payload
+func messagesSchema(description string, messages []message) schemaObject {
+	variants := make([]interface{}, len(messages))
+	for i, msg := range messages {
+		variants[i] = schemaObject{
+			"allOf": []interface{}{
+				refSchema("Envelope"),
+				schemaObject{
+					"properties": schemaObject{
+						"type":    schemaObject{"const": msg.msgType},
+						"payload": typeSchema(msg.payload),
+					},
+				},
+			},
+		}
+	}
+	return schemaObject{"description": description, "oneOf": variants}
+}
+
+// definitionSchema returns the schema of a named type
+func definitionSchema(def *definition) schemaObject {
+	var schema schemaObject
+	if def.object {
+		schema = objectSchema(def.fields)
+	} else {
+		schema = typeSchema(def.alias)
+		if len(def.enum) > 0 {
+			schema["enum"] = def.enum
+		}
+	}
+	if def.doc != "" {
+		schema["description"] = def.doc
+	}
+	return schema
+}
+
+// objectSchema returns the schema of an object with the fields, requiring those never left out
+func objectSchema(fields []field) schemaObject {
+	properties := schemaObject{}
+	required := []string{}
+	for _, f := range fields {
+		property := typeSchema(f.typ)
+		if f.doc != "" {
+			property["description"] = f.doc
+		}
+		properties[f.name] = property
+		if !f.optional {
+			required = append(required, f.name)
+		}
+	}
+	schema := schemaObject{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// typeSchema returns the schema of a type
+func typeSchema(ref typeRef) schemaObject {
+	var schema schemaObject
+	switch ref.kind {
+	case kindString:
+		schema = schemaObject{"type": "string"}
+	case kindInteger:
+		schema = schemaObject{"type": "integer"}
+	case kindNumber:
+		schema = schemaObject{"type": "number"}
+	case kindBoolean:
+		schema = schemaObject{"type": "boolean"}
+	case kindAny:
+		schema = schemaObject{}
+	case kindArray:
+		schema = schemaObject{"type": "array", "items": typeSchema(*ref.elem)}
+	case kindMap:
+		schema = schemaObject{"type": "object", "additionalProperties": typeSchema(*ref.elem)}
+	case kindObject:
+		schema = objectSchema(ref.fields)
+	case kindRef:
+		schema = refSchema(ref.name)
+	}
+	if ref.nullable {
+		return schemaObject{"anyOf": []interface{}{schema, schemaObject{"type": "null"}}}
+	}
+	return schema
+}
+
+// refSchema refers to a definition
+func refSchema(name string) schemaObject {
+	return schemaObject{"$ref": "#/$defs/" + name}
+}
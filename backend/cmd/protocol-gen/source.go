@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// source is what reflection cannot tell about the websocket package: its doc comments and the values of its
+// string constants
+type source struct {
+	typeDocs  map[string]string            // Keyed by type name
+	fieldDocs map[string]map[string]string // Keyed by struct name, then Go field name
+	enums     map[string][]string          // The values of each named string type's exported constants, in order
+}
+
+// loadSource reads the package's source in dir, leaving out its tests
+func loadSource(dir string) (*source, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	src := &source{
+		typeDocs:  make(map[string]string),
+		fieldDocs: make(map[string]map[string]string),
+		enums:     make(map[string][]string),
+	}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gen.Tok {
+			case token.TYPE:
+				src.addTypes(gen)
+			case token.CONST:
+				src.addConsts(gen)
+			}
+		}
+	}
+	if len(src.typeDocs) == 0 {
+		return nil, fmt.Errorf("no types found in %s", dir)
+	}
+	return src, nil
+}
+
+// addTypes records the doc comments of the declared types and their fields
+func (s *source) addTypes(gen *ast.GenDecl) {
+	for _, spec := range gen.Specs {
+		typeSpec := spec.(*ast.TypeSpec)
+		doc := typeSpec.Doc
+		if doc == nil && len(gen.Specs) == 1 {
+			doc = gen.Doc
+		}
+		s.typeDocs[typeSpec.Name.Name] = commentText(doc)
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+		docs := make(map[string]string)
+		for _, field := range structType.Fields.List {
+			text := commentText(field.Doc)
+			if text == "" {
+				text = commentText(field.Comment)
+			}
+			for _, name := range field.Names {
+				docs[name.Name] = text
+			}
+		}
+		s.fieldDocs[typeSpec.Name.Name] = docs
+	}
+}
+
+// addConsts records the values of exported string constants declared with a named type
+func (s *source) addConsts(gen *ast.GenDecl) {
+	for _, spec := range gen.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		typeName, ok := valueSpec.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			if !name.IsExported() || i >= len(valueSpec.Values) {
+				continue
+			}
+			lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			value, err := strconv.Unquote(lit.Value)
+			if err == nil && !slices.Contains(s.enums[typeName.Name], value) {
+				s.enums[typeName.Name] = append(s.enums[typeName.Name], value)
+			}
+		}
+	}
+}
+
+// commentText returns a comment's text on one line, "" if there is none
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// identifier matches the property names TypeScript accepts unquoted
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// writeTypeScript returns TypeScript definitions of the protocol
+func writeTypeScript(p *protocol) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by protocol-gen from backend/internal/websocket. DO NOT EDIT.\n")
+	b.WriteString("// Run `go generate ./internal/websocket` in backend to update it.\n")
+
+	b.WriteString("\n")
+	writeDoc(&b, "", p.envelope.doc)
+	b.WriteString("export interface Envelope<T extends string = string, P = unknown> {\n")
+	for _, f := range p.envelope.fields {
+		switch f.name {
+		case "type":
+			writeField(&b, f, "T")
+		case "payload":
+			writeField(&b, f, "P")
+		default:
+			writeField(&b, f, tsType(f.typ))
+		}
+	}
+	b.WriteString("}\n")
+
+	writeMessages(&b, "Client", "clients send", p.client)
+	writeMessages(&b, "Server", "the server sends", p.server)
+
+	for _, def := range p.defs {
+		b.WriteString("\n")
+		writeDoc(&b, "", def.doc)
+		switch {
+		case def.object && len(def.fields) == 0:
+			b.WriteString("export type " + def.name + " = Record<string, never>;\n")
+		case def.object:
+			b.WriteString("export interface " + def.name + " {\n")
+			for _, f := range def.fields {
+				writeField(&b, f, tsType(f.typ))
+			}
+			b.WriteString("}\n")
+		case len(def.enum) > 0:
+			b.WriteString("export type " + def.name + " =")
+			for _, value := range def.enum {
+				b.WriteString("\n  | " + strconv.Quote(value))
+			}
+			b.WriteString(";\n")
+		default:
+			b.WriteString("export type " + def.name + " = " + tsType(def.alias) + ";\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// writeMessages writes the payload of each message one side sends, by type, and the union of their envelopes
+func writeMessages(b *strings.Builder, side, sender string, messages []message) {
+	b.WriteString("\n/** The payload of each message " + sender + ", by message type */\n")
+	b.WriteString("export interface " + side + "Payloads {\n")
+	for _, msg := range messages {
+		b.WriteString("  " + propertyName(msg.msgType) + ": " + tsType(msg.payload) + ";\n")
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nexport type " + side + "MessageType = keyof " + side + "Payloads;\n")
+	b.WriteString("\n/** A message " + sender + " */\n")
+	b.WriteString("export type " + side + "Message = {\n")
+	b.WriteString("  [K in " + side + "MessageType]: Envelope<K, " + side + "Payloads[K]>;\n")
+	b.WriteString("}[" + side + "MessageType];\n")
+}
+
+// writeField writes a property of an interface
+func writeField(b *strings.Builder, f field, typ string) {
+	writeDoc(b, "  ", f.doc)
+	optional := ""
+	if f.optional {
+		optional = "?"
+	}
+	b.WriteString("  " + propertyName(f.name) + optional + ": " + typ + ";\n")
+}
+
+// writeDoc writes a doc comment, if there is one
+func writeDoc(b *strings.Builder, indent, doc string) {
+	if doc != "" {
+		b.WriteString(indent + "/** " + strings.ReplaceAll(doc, "*/", "*\\/") + " */\n")
+	}
+}
+
+// propertyName quotes a property name if TypeScript needs it to be
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsType returns the TypeScript for a type
+func tsType(ref typeRef) string {
+	var typ string
+	switch ref.kind {
+	case kindString:
+		typ = "string"
+	case kindInteger, kindNumber:
+		typ = "number"
+	case kindBoolean:
+		typ = "boolean"
+	case kindAny:
+		typ = "unknown"
+	case kindArray:
+		elem := tsType(*ref.elem)
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		typ = elem + "[]"
+	case kindMap:
+		typ = "Record<string, " + tsType(*ref.elem) + ">"
+	case kindObject:
+		if len(ref.fields) == 0 {
+			typ = "Record<string, never>"
+			break
+		}
+		properties := make([]string, len(ref.fields))
+		for i, f := range ref.fields {
+			optional := ""
+			if f.optional {
+				optional = "?"
+			}
+			properties[i] = propertyName(f.name) + optional + ": " + tsType(f.typ)
+		}
+		typ = "{ " + strings.Join(properties, "; ") + " }"
+	case kindRef:
+		typ = ref.name
+	}
+	if ref.nullable {
+		typ += " | null"
+	}
+	return typ
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"poke-battles/internal/replay"
+)
+
+// DefaultCapacity is the number of rejections a memory trail keeps before dropping the oldest
+const DefaultCapacity = 10000
+
+// Rejection records an action the server refused, with enough context to spot players probing for illegal moves
+type Rejection struct {
+	At        time.Time     `json:"at"`
+	LobbyCode string        `json:"lobby_code"`
+	GameID    string        `json:"game_id"`
+	PlayerID  string        `json:"player_id"`
+	Turn      int           `json:"turn"`
+	Action    replay.Action `json:"action"`
+	Reason    string        `json:"reason"`
+}
+
+// Trail is the audit trail of rejected actions. Implementations must be safe for concurrent use.
+type Trail interface {
+	Record(rejection Rejection)
+	// ByPlayer returns a player's recorded rejections, oldest first
+	ByPlayer(playerID string) []Rejection
+}
+
+// memoryTrail implements Trail by keeping the most recent rejections in memory
+type memoryTrail struct {
+	mu         sync.RWMutex
+	capacity   int
+	rejections []Rejection
+}
+
+// NewMemoryTrail creates a trail that keeps up to capacity rejections for the lifetime of the process
+func NewMemoryTrail(capacity int) Trail {
+	return &memoryTrail{
+		capacity: capacity,
+	}
+}
+
+// Record appends a rejection, dropping the oldest once the trail is full
+func (t *memoryTrail) Record(rejection Rejection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.rejections) >= t.capacity {
+		t.rejections = t.rejections[len(t.rejections)-t.capacity+1:]
+	}
+	t.rejections = append(t.rejections, rejection)
+}
+
+// ByPlayer returns a player's recorded rejections, oldest first
+func (t *memoryTrail) ByPlayer(playerID string) []Rejection {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var rejections []Rejection
+	for _, r := range t.rejections {
+		if r.PlayerID == playerID {
+			rejections = append(rejections, r)
+		}
+	}
+	return rejections
+}
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"testing"
+
+	"poke-battles/internal/replay"
+)
+
+// ========================================
+// Memory Trail Tests
+// ========================================
+
+func TestMemoryTrail_ByPlayer(t *testing.T) {
+	trail := NewMemoryTrail(DefaultCapacity)
+	trail.Record(Rejection{PlayerID: "player-1", Turn: 1, Action: replay.Action{Kind: replay.ActionKindMove, MoveID: "hyper-beam"}, Reason: "unknown move"})
+	trail.Record(Rejection{PlayerID: "player-2", Turn: 1})
+	trail.Record(Rejection{PlayerID: "player-1", Turn: 2})
+
+	got := trail.ByPlayer("player-1")
+	if len(got) != 2 || got[0].Turn != 1 || got[1].Turn != 2 {
+		t.Fatalf("expected player-1's rejections oldest first, got %+v", got)
+	}
+	if got[0].Action.MoveID != "hyper-beam" || got[0].Reason != "unknown move" {
+		t.Errorf("unexpected rejection: %+v", got[0])
+	}
+	if got := trail.ByPlayer("player-3"); len(got) != 0 {
+		t.Errorf("expected no rejections, got %+v", got)
+	}
+}
+
+func TestMemoryTrail_DropsOldestWhenFull(t *testing.T) {
+	trail := NewMemoryTrail(2)
+	for turn := 1; turn <= 3; turn++ {
+		trail.Record(Rejection{PlayerID: "player-1", Turn: turn})
+	}
+
+	got := trail.ByPlayer("player-1")
+	if len(got) != 2 || got[0].Turn != 2 || got[1].Turn != 3 {
+		t.Errorf("expected the two newest rejections, got %+v", got)
+	}
+}
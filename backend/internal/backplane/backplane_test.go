@@ -0,0 +1,169 @@
+package backplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// backplane is what both implementations offer the websocket hub
+type backplane interface {
+	Publish(ctx context.Context, lobbyCode string, data []byte) error
+	Subscribe(ctx context.Context, lobbyCode string) error
+	Unsubscribe(ctx context.Context, lobbyCode string) error
+	Listen(ctx context.Context, receive func(lobbyCode string, data []byte)) error
+	Close() error
+}
+
+// received is a message a test listener was called with
+type received struct {
+	lobbyCode string
+	data      string
+}
+
+// listen runs the backplane's listener until the test ends, returning what it receives
+func listen(t *testing.T, b backplane) <-chan received {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	messages := make(chan received, 16)
+	go b.Listen(ctx, func(lobbyCode string, data []byte) {
+		messages <- received{lobbyCode: lobbyCode, data: string(data)}
+	})
+	return messages
+}
+
+// expectReceived waits for the next message a listener receives
+func expectReceived(t *testing.T, messages <-chan received, lobbyCode, data string) {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		if msg.lobbyCode != lobbyCode || msg.data != data {
+			t.Errorf("expected %q on %s, got %q on %s", data, lobbyCode, msg.data, msg.lobbyCode)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected %q on %s, received nothing", data, lobbyCode)
+	}
+}
+
+// expectNothing checks a listener receives nothing more
+func expectNothing(t *testing.T, messages <-chan received) {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		t.Errorf("expected nothing, got %q on %s", msg.data, msg.lobbyCode)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// testBackplanes checks two backplanes standing in for separate instances carry each other's broadcasts.
+// awaitSubscribers waits for the lobby to have that many subscribers, for backplanes that subscribe asynchronously.
+func testBackplanes(t *testing.T, first, second backplane, awaitSubscribers func(lobbyCode string, n int)) {
+	ctx := context.Background()
+	firstMessages, secondMessages := listen(t, first), listen(t, second)
+
+	if err := first.Subscribe(ctx, "ABC123"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	if err := second.Subscribe(ctx, "ABC123"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	if err := second.Subscribe(ctx, "XYZ789"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	awaitSubscribers("ABC123", 2)
+	awaitSubscribers("XYZ789", 1)
+
+	// Every subscriber to a lobby receives what is published to it, the publisher included
+	if err := first.Publish(ctx, "ABC123", []byte("hello")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	expectReceived(t, firstMessages, "ABC123", "hello")
+	expectReceived(t, secondMessages, "ABC123", "hello")
+
+	// Only subscribers to a lobby receive it
+	if err := first.Publish(ctx, "XYZ789", []byte("other")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	expectReceived(t, secondMessages, "XYZ789", "other")
+	expectNothing(t, firstMessages)
+
+	// Nothing more arrives after unsubscribing
+	if err := second.Unsubscribe(ctx, "ABC123"); err != nil {
+		t.Fatalf("failed to unsubscribe: %v", err)
+	}
+	awaitSubscribers("ABC123", 1)
+	if err := first.Publish(ctx, "ABC123", []byte("again")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	expectReceived(t, firstMessages, "ABC123", "again")
+	expectNothing(t, secondMessages)
+
+	// A closed backplane stops listening
+	first.Close()
+	if err := first.Listen(ctx, func(string, []byte) {}); err != ErrClosed {
+		t.Errorf("expected ErrClosed listening on a closed backplane, got %v", err)
+	}
+}
+
+// ========================================
+// Memory Backplane Tests
+// ========================================
+
+func TestMemory_CarriesBroadcastsBetweenBackplanes(t *testing.T) {
+	broker := NewMemoryBroker()
+	first, second := broker.Connect(), broker.Connect()
+	defer second.Close()
+
+	testBackplanes(t, first, second, func(string, int) {})
+}
+
+// ========================================
+// Redis Backplane Tests
+// ========================================
+
+// awaitRedisSubscribers waits for the Redis channel to have n subscribers, since a subscription
+// is sent to the server without waiting for it to be confirmed
+func awaitRedisSubscribers(t *testing.T, server *miniredis.Miniredis, channel string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for server.PubSubNumSub(channel)[channel] != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers to %s, got %d", n, channel, server.PubSubNumSub(channel)[channel])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRedis_CarriesBroadcastsBetweenBackplanes(t *testing.T) {
+	server := miniredis.RunT(t)
+	firstClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	secondClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer firstClient.Close()
+	defer secondClient.Close()
+	first, second := NewRedis(firstClient, DefaultRedisPrefix), NewRedis(secondClient, DefaultRedisPrefix)
+	defer second.Close()
+
+	testBackplanes(t, first, second, func(lobbyCode string, n int) {
+		awaitRedisSubscribers(t, server, DefaultRedisPrefix+"lobby:"+lobbyCode, n)
+	})
+}
+
+func TestRedis_ChannelsPerLobby(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	b := NewRedis(client, "test:")
+	defer b.Close()
+
+	if err := b.Subscribe(context.Background(), "ABC123"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	awaitRedisSubscribers(t, server, "test:lobby:ABC123", 1)
+	if channels := server.PubSubChannels(""); len(channels) != 1 || channels[0] != "test:lobby:ABC123" {
+		t.Errorf("expected the lobby's own channel, got %v", channels)
+	}
+}
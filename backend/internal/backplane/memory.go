@@ -0,0 +1,139 @@
+package backplane
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker connects the hubs of one process as if they were separate instances, e.g. to test them together
+type MemoryBroker struct {
+	mu         sync.RWMutex
+	backplanes map[*Memory]bool
+}
+
+// NewMemoryBroker creates a broker with no backplanes connected
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{backplanes: make(map[*Memory]bool)}
+}
+
+// Connect creates a backplane for one hub, which receives what any of the broker's backplanes publish
+// to the lobbies it subscribes to
+func (b *MemoryBroker) Connect() *Memory {
+	m := &Memory{
+		broker:  b,
+		lobbies: make(map[string]bool),
+		ready:   make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	b.mu.Lock()
+	b.backplanes[m] = true
+	b.mu.Unlock()
+	return m
+}
+
+// Memory is a backplane connected to a MemoryBroker
+type Memory struct {
+	broker *MemoryBroker
+
+	mu      sync.Mutex
+	lobbies map[string]bool // Subscribed lobby codes
+	queue   []memoryMessage // Received and not yet listened to, oldest first
+	ready   chan struct{}   // Signalled when the queue gains a message
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// memoryMessage is a message waiting to be listened to
+type memoryMessage struct {
+	lobbyCode string
+	data      []byte
+}
+
+// Publish sends data to every backplane on the broker subscribed to the lobby
+func (m *Memory) Publish(ctx context.Context, lobbyCode string, data []byte) error {
+	select {
+	case <-m.closed:
+		return ErrClosed
+	default:
+	}
+
+	m.broker.mu.RLock()
+	defer m.broker.mu.RUnlock()
+	for backplane := range m.broker.backplanes {
+		backplane.deliver(lobbyCode, data)
+	}
+	return nil
+}
+
+// deliver queues a message for the backplane's listener if it is subscribed to the lobby
+func (m *Memory) deliver(lobbyCode string, data []byte) {
+	m.mu.Lock()
+	if !m.lobbies[lobbyCode] {
+		m.mu.Unlock()
+		return
+	}
+	m.queue = append(m.queue, memoryMessage{lobbyCode: lobbyCode, data: append([]byte(nil), data...)})
+	m.mu.Unlock()
+
+	select {
+	case m.ready <- struct{}{}:
+	default: // Already signalled
+	}
+}
+
+// Subscribe starts receiving what is published to the lobby
+func (m *Memory) Subscribe(ctx context.Context, lobbyCode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lobbies[lobbyCode] = true
+	return nil
+}
+
+// Unsubscribe stops receiving what is published to the lobby
+func (m *Memory) Unsubscribe(ctx context.Context, lobbyCode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.lobbies, lobbyCode)
+	return nil
+}
+
+// Subscribed reports whether the backplane is subscribed to the lobby
+func (m *Memory) Subscribed(lobbyCode string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lobbies[lobbyCode]
+}
+
+// Listen calls receive with each message published to a subscribed lobby until ctx is done or the backplane is closed
+func (m *Memory) Listen(ctx context.Context, receive func(lobbyCode string, data []byte)) error {
+	for {
+		m.mu.Lock()
+		queue := m.queue
+		m.queue = nil
+		m.mu.Unlock()
+
+		for _, msg := range queue {
+			receive(msg.lobbyCode, msg.data)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.closed:
+			return ErrClosed
+		case <-m.ready:
+		}
+	}
+}
+
+// Close disconnects the backplane from its broker
+func (m *Memory) Close() error {
+	m.closeOnce.Do(func() {
+		m.broker.mu.Lock()
+		delete(m.broker.backplanes, m)
+		m.broker.mu.Unlock()
+		close(m.closed)
+	})
+	return nil
+}
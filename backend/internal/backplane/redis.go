@@ -0,0 +1,78 @@
+// Package backplane carries lobby broadcasts between API instances, for websocket hubs that share lobbies
+package backplane
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix is what the Redis channels lobbies are broadcast on are named with unless told otherwise
+const DefaultRedisPrefix = "poke-battles:"
+
+// ErrClosed is returned by a backplane that has been closed
+var ErrClosed = errors.New("backplane closed")
+
+// Redis carries lobby broadcasts over Redis pub/sub, each lobby on its own channel named
+// with the prefix, e.g. "poke-battles:lobby:ABC123"
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	pubsub *redis.PubSub // Holds the instance's subscriptions on one connection, resubscribing if it drops
+}
+
+// NewRedis creates a backplane on the client, naming its channels with the prefix
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{
+		client: client,
+		prefix: prefix,
+		pubsub: client.Subscribe(context.Background()),
+	}
+}
+
+// channel returns the name of the lobby's channel
+func (r *Redis) channel(lobbyCode string) string {
+	return r.prefix + "lobby:" + lobbyCode
+}
+
+// Publish sends data to every instance subscribed to the lobby
+func (r *Redis) Publish(ctx context.Context, lobbyCode string, data []byte) error {
+	return r.client.Publish(ctx, r.channel(lobbyCode), data).Err()
+}
+
+// Subscribe starts receiving what is published to the lobby
+func (r *Redis) Subscribe(ctx context.Context, lobbyCode string) error {
+	return r.pubsub.Subscribe(ctx, r.channel(lobbyCode))
+}
+
+// Unsubscribe stops receiving what is published to the lobby
+func (r *Redis) Unsubscribe(ctx context.Context, lobbyCode string) error {
+	return r.pubsub.Unsubscribe(ctx, r.channel(lobbyCode))
+}
+
+// Listen calls receive with each message published to a subscribed lobby until ctx is done or the backplane is closed
+func (r *Redis) Listen(ctx context.Context, receive func(lobbyCode string, data []byte)) error {
+	messages := r.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return ErrClosed
+			}
+			lobbyCode, ok := strings.CutPrefix(msg.Channel, r.prefix+"lobby:")
+			if !ok {
+				continue
+			}
+			receive(lobbyCode, []byte(msg.Payload))
+		}
+	}
+}
+
+// Close drops the backplane's subscriptions. The client is left open.
+func (r *Redis) Close() error {
+	return r.pubsub.Close()
+}
@@ -0,0 +1,235 @@
+package battlelog
+
+import (
+	"fmt"
+	"strconv"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+)
+
+// Entry is a single line of a battle log in locale-neutral form.
+// Key names the message template, Args hold values substituted verbatim (names, numbers),
+// and Terms hold game terms (statuses, stats, hazards, terrains) that are translated before substitution.
+type Entry struct {
+	Turn  int               `json:"turn"`
+	Key   string            `json:"key"`
+	Args  map[string]string `json:"args,omitempty"`
+	Terms map[string]string `json:"terms,omitempty"`
+}
+
+// Build derives the log of a finished battle from its replay.
+// Creature and move names come from the data pack the battle was played with.
+func Build(r *replay.Replay) ([]Entry, error) {
+	pack, err := game.LookupDataPack(r.DataVersion)
+	if err != nil {
+		return nil, fmt.Errorf("replay %q: %w", r.ID, err)
+	}
+
+	b := &builder{pack: pack, names: make(map[string]string), active: make(map[string]string)}
+	for _, player := range r.Players {
+		for _, c := range player.Team {
+			b.names[c.ID] = b.creatureName(c)
+		}
+	}
+
+	b.startBattle(r)
+	for _, turn := range r.Turns {
+		if len(turn.Events) == 0 {
+			continue
+		}
+		b.turn = turn.Number
+		b.add("turn_started", args{"turn": strconv.Itoa(turn.Number)}, nil)
+		for _, e := range turn.Events {
+			b.event(e)
+		}
+	}
+	b.endBattle(r.Outcome)
+	return b.entries, nil
+}
+
+// args and terms are shorthands for the maps of an entry
+type (
+	args  map[string]string
+	terms map[string]string
+)
+
+// builder accumulates log entries while tracking each player's active creature
+type builder struct {
+	pack    *game.DataPack
+	names   map[string]string // creature ID -> display name
+	active  map[string]string // player ID -> active creature ID
+	turn    int
+	entries []Entry
+}
+
+func (b *builder) add(key string, a args, t terms) {
+	b.entries = append(b.entries, Entry{Turn: b.turn, Key: key, Args: a, Terms: t})
+}
+
+// creatureName is a creature's nickname, or its species name if it has none
+func (b *builder) creatureName(c replay.Creature) string {
+	if c.Nickname != "" {
+		return c.Nickname
+	}
+	if species, err := b.pack.LookupSpecies(c.SpeciesID); err == nil {
+		return species.Name
+	}
+	return c.SpeciesID
+}
+
+func (b *builder) moveName(id string) string {
+	if move, err := b.pack.LookupMove(id); err == nil {
+		return move.Name
+	}
+	return id
+}
+
+func itemName(id string) string {
+	if item, err := game.LookupBagItem(id); err == nil {
+		return item.Name
+	}
+	return id
+}
+
+// creature returns the display name of a creature, or of the player's active creature if no creature is given
+func (b *builder) creature(id, playerID string) string {
+	if id == "" {
+		id = b.active[playerID]
+	}
+	if name, ok := b.names[id]; ok {
+		return name
+	}
+	return id
+}
+
+// startBattle logs the players' leads: the last lead chosen during team preview, or the first team member
+func (b *builder) startBattle(r *replay.Replay) {
+	if len(r.Players) != 2 {
+		return
+	}
+	b.add("battle_started", args{"player1": r.Players[0].ID, "player2": r.Players[1].ID}, nil)
+
+	for _, player := range r.Players {
+		lead := 0
+		for _, turn := range r.Turns {
+			for _, action := range turn.Actions {
+				if action.PlayerID == player.ID && action.Kind == replay.ActionKindChooseLead {
+					lead = action.Slot
+				}
+			}
+		}
+		if lead < 0 || lead >= len(player.Team) {
+			continue
+		}
+		b.active[player.ID] = player.Team[lead].ID
+		b.add("sent_out", args{"player": player.ID, "creature": b.creature(player.Team[lead].ID, "")}, nil)
+	}
+}
+
+// event logs a single battle event as one or more entries
+func (b *builder) event(e replay.Event) {
+	switch game.BattleEventType(e.Type) {
+	case game.EventCreatureSwitched:
+		b.active[e.Actor] = e.Target
+		b.add("sent_out", args{"player": e.Actor, "creature": b.creature(e.Target, "")}, nil)
+	case game.EventMoveUsed:
+		b.add("move_used", args{"creature": b.creature(e.Target, e.Actor), "move": b.moveName(e.MoveID)}, nil)
+	case game.EventDamageDealt:
+		b.damageDealt(e)
+	case game.EventMoveFailed:
+		b.add("move_failed."+e.Reason, args{"creature": b.creature("", e.Actor), "target": b.creature(e.Target, e.Actor)}, nil)
+	case game.EventStatusApplied:
+		// A flinch is reported when the flinching creature fails to move
+		if e.Status == string(game.VolatileFlinch) {
+			return
+		}
+		b.add("status_applied."+e.Status, args{"creature": b.creature(e.Target, "")}, terms{"status": e.Status})
+	case game.EventStatusEnded:
+		b.add("status_ended."+e.Status, args{"creature": b.creature(e.Target, "")}, terms{"status": e.Status})
+	case game.EventStatusDamage:
+		b.add("status_damage."+e.Status, args{"creature": b.creature(e.Target, ""), "damage": strconv.Itoa(e.Damage)}, terms{"status": e.Status})
+	case game.EventConfusionSelfHit:
+		b.add("confusion_self_hit", args{"creature": b.creature(e.Target, ""), "damage": strconv.Itoa(e.Damage)}, nil)
+	case game.EventRecoilDamage:
+		b.add("recoil_damage", args{"creature": b.creature(e.Target, ""), "damage": strconv.Itoa(e.Damage)}, nil)
+	case game.EventLeechSeedDrain:
+		b.add("leech_seed_drain", args{"creature": b.creature(e.Target, ""), "damage": strconv.Itoa(e.Damage)}, nil)
+		if e.Recipient != "" && e.Healed > 0 {
+			b.add("hp_restored", args{"creature": b.creature(e.Recipient, ""), "healed": strconv.Itoa(e.Healed)}, nil)
+		}
+	case game.EventStatChanged:
+		b.statChanged(e)
+	case game.EventCreatureFainted:
+		b.add("creature_fainted", args{"creature": b.creature(e.Target, "")}, nil)
+	case game.EventHazardSet:
+		b.add("hazard_set."+e.Hazard, args{"player": e.Side, "layers": strconv.Itoa(e.Layers)}, terms{"hazard": e.Hazard})
+	case game.EventHazardDamage:
+		b.add("hazard_damage."+e.Hazard, args{"creature": b.creature(e.Target, ""), "damage": strconv.Itoa(e.Damage)}, terms{"hazard": e.Hazard})
+	case game.EventHazardCleared:
+		b.add("hazard_cleared", args{"player": e.Side}, terms{"hazard": e.Hazard})
+	case game.EventTerrainSet:
+		b.add("terrain_set."+e.Terrain, nil, terms{"terrain": e.Terrain})
+	case game.EventTerrainEnded:
+		b.add("terrain_ended", nil, terms{"terrain": e.Terrain})
+	case game.EventItemUsed:
+		b.itemUsed(e)
+	}
+}
+
+// damageDealt logs a hit's critical and effectiveness messages before the damage itself
+func (b *builder) damageDealt(e replay.Event) {
+	target := b.creature(e.Target, "")
+	if e.Effectiveness == "no_effect" {
+		b.add("no_effect", args{"target": target}, nil)
+		return
+	}
+	if e.Critical {
+		b.add("critical_hit", nil, nil)
+	}
+	if e.Effectiveness == "super_effective" || e.Effectiveness == "not_very_effective" {
+		b.add(e.Effectiveness, nil, nil)
+	}
+	b.add("damage_dealt", args{"target": target, "damage": strconv.Itoa(e.Damage)}, nil)
+}
+
+// statChanged picks the message for the size and direction of a stat stage change
+func (b *builder) statChanged(e replay.Event) {
+	key := "stat_rose"
+	stages := e.Stages
+	if stages < 0 {
+		key = "stat_fell"
+		stages = -stages
+	}
+	switch {
+	case stages >= 3:
+		key += ".drastic"
+	case stages == 2:
+		key += ".sharp"
+	}
+	b.add(key, args{"creature": b.creature(e.Target, "")}, terms{"stat": e.Stat})
+}
+
+// itemUsed logs a bag item and what it restored or cured
+func (b *builder) itemUsed(e replay.Event) {
+	creature := b.creature(e.Target, "")
+	b.add("item_used", args{"player": e.Actor, "item": itemName(e.ItemID), "creature": creature}, nil)
+	switch {
+	case e.Status != "":
+		b.add("status_cured", args{"creature": creature}, terms{"status": e.Status})
+	case e.Healed > 0:
+		b.add("hp_restored", args{"creature": creature, "healed": strconv.Itoa(e.Healed)}, nil)
+	}
+}
+
+// endBattle logs how the battle ended
+func (b *builder) endBattle(outcome *replay.Outcome) {
+	if outcome == nil {
+		return
+	}
+	key := "battle_ended." + outcome.Reason
+	if outcome.WinnerID == "" {
+		key = "battle_ended.draw"
+	}
+	b.add(key, args{"winner": outcome.WinnerID, "loser": outcome.LoserID}, nil)
+}
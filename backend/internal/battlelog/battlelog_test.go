@@ -0,0 +1,166 @@
+package battlelog
+
+import (
+	"errors"
+	"testing"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+)
+
+// newTestReplay creates the replay of a short battle: player-2 leads with its second creature,
+// player-1's Pikachu knocks it out with a super effective Thunderbolt, and player-2 forfeits.
+func newTestReplay() *replay.Replay {
+	return &replay.Replay{
+		Version:     replay.FormatVersion,
+		ID:          "ABC123-1",
+		BattleID:    "ABC123",
+		DataVersion: game.CurrentDataVersion,
+		Players: []replay.Player{
+			{ID: "player-1", Team: []replay.Creature{
+				{ID: "player-1-0", SpeciesID: "pikachu", Nickname: "Sparky", Level: 50, Moves: []string{"thunderbolt"}},
+			}},
+			{ID: "player-2", Team: []replay.Creature{
+				{ID: "player-2-0", SpeciesID: "venusaur", Level: 50, Moves: []string{"razor-leaf"}},
+				{ID: "player-2-1", SpeciesID: "blastoise", Level: 50, Moves: []string{"swords-dance"}},
+			}},
+		},
+		Turns: []replay.Turn{
+			{
+				Number: 1,
+				Actions: []replay.Action{
+					{PlayerID: "player-2", Kind: replay.ActionKindChooseLead, Slot: 1},
+					{PlayerID: "player-1", Kind: replay.ActionKindMove, MoveID: "thunderbolt"},
+					{PlayerID: "player-2", Kind: replay.ActionKindMove, MoveID: "swords-dance"},
+				},
+				Events: []replay.Event{
+					{Order: 1, Type: string(game.EventMoveUsed), Actor: "player-1", Target: "player-1-0", MoveID: "thunderbolt"},
+					{Order: 2, Type: string(game.EventDamageDealt), Actor: "player-1", Target: "player-2-1", MoveID: "thunderbolt", Damage: 150, Effectiveness: "super_effective", Critical: true},
+					{Order: 3, Type: string(game.EventCreatureFainted), Actor: "player-2", Target: "player-2-1"},
+				},
+			},
+			{
+				Number:  2,
+				Actions: []replay.Action{{PlayerID: "player-2", Kind: replay.ActionKindForfeit}},
+				Events:  []replay.Event{},
+			},
+		},
+		Outcome: &replay.Outcome{WinnerID: "player-1", LoserID: "player-2", Reason: string(game.EndReasonForfeit)},
+	}
+}
+
+// ========================================
+// Build Tests
+// ========================================
+
+func TestBuild_LogsBattleInOrder(t *testing.T) {
+	entries, err := Build(newTestReplay())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		turn int
+		key  string
+		args map[string]string
+	}{
+		{0, "battle_started", map[string]string{"player1": "player-1", "player2": "player-2"}},
+		{0, "sent_out", map[string]string{"player": "player-1", "creature": "Sparky"}},
+		{0, "sent_out", map[string]string{"player": "player-2", "creature": "Blastoise"}},
+		{1, "turn_started", map[string]string{"turn": "1"}},
+		{1, "move_used", map[string]string{"creature": "Sparky", "move": "Thunderbolt"}},
+		{1, "critical_hit", nil},
+		{1, "super_effective", nil},
+		{1, "damage_dealt", map[string]string{"target": "Blastoise", "damage": "150"}},
+		{1, "creature_fainted", map[string]string{"creature": "Blastoise"}},
+		{1, "battle_ended.forfeit", map[string]string{"winner": "player-1", "loser": "player-2"}},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i, want := range expected {
+		got := entries[i]
+		if got.Turn != want.turn || got.Key != want.key {
+			t.Errorf("entry %d: expected turn %d %q, got turn %d %q", i, want.turn, want.key, got.Turn, got.Key)
+		}
+		for name, value := range want.args {
+			if got.Args[name] != value {
+				t.Errorf("entry %d: expected %s=%q, got %q", i, name, value, got.Args[name])
+			}
+		}
+	}
+}
+
+func TestBuild_TracksActiveCreatureAcrossSwitches(t *testing.T) {
+	r := newTestReplay()
+	r.Turns = []replay.Turn{{
+		Number: 1,
+		Events: []replay.Event{
+			{Order: 1, Type: string(game.EventCreatureSwitched), Actor: "player-2", Target: "player-2-0", FromSlot: 0, ToSlot: 0},
+			// Failed moves do not name the creature that tried to move
+			{Order: 2, Type: string(game.EventMoveFailed), Actor: "player-2", MoveID: "razor-leaf", Reason: game.FailReasonMissed},
+		},
+	}}
+
+	entries, err := Build(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failed := entries[len(entries)-2]
+	if failed.Key != "move_failed.missed" || failed.Args["creature"] != "Venusaur" {
+		t.Errorf("expected Venusaur's move to miss, got %+v", failed)
+	}
+}
+
+func TestBuild_StatChangeVariants(t *testing.T) {
+	tests := []struct {
+		stages int
+		key    string
+	}{
+		{1, "stat_rose"},
+		{2, "stat_rose.sharp"},
+		{3, "stat_rose.drastic"},
+		{-1, "stat_fell"},
+		{-2, "stat_fell.sharp"},
+		{-6, "stat_fell.drastic"},
+	}
+
+	for _, tt := range tests {
+		r := newTestReplay()
+		r.Turns = []replay.Turn{{Number: 1, Events: []replay.Event{
+			{Order: 1, Type: string(game.EventStatChanged), Actor: "player-1", Target: "player-1-0", Stat: string(game.StatAttack), Stages: tt.stages},
+		}}}
+
+		entries, err := Build(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		changed := entries[len(entries)-2]
+		if changed.Key != tt.key || changed.Terms["stat"] != string(game.StatAttack) {
+			t.Errorf("%d stages: expected %q, got %+v", tt.stages, tt.key, changed)
+		}
+	}
+}
+
+func TestBuild_DrawHasNoWinner(t *testing.T) {
+	r := newTestReplay()
+	r.Outcome = &replay.Outcome{Reason: string(game.EndReasonTurnLimit)}
+
+	entries, err := Build(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last := entries[len(entries)-1]; last.Key != "battle_ended.draw" {
+		t.Errorf("expected a drawn ending, got %+v", last)
+	}
+}
+
+func TestBuild_UnknownDataVersion(t *testing.T) {
+	r := newTestReplay()
+	r.DataVersion = "999"
+
+	if _, err := Build(r); !errors.Is(err, game.ErrUnknownDataVersion) {
+		t.Errorf("expected ErrUnknownDataVersion, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package battlelog
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// DefaultLocale is the locale logs are rendered in when none is requested
+const DefaultLocale = "en"
+
+var (
+	// ErrUnknownLocale is returned when rendering a log in a locale without a catalogue
+	ErrUnknownLocale = errors.New("unknown locale")
+	// ErrMissingTemplate is returned when a catalogue has no template for an entry
+	ErrMissingTemplate = errors.New("missing template")
+)
+
+// Catalogue holds the text of a battle log in one locale.
+// Messages are templates keyed by entry key, with {name} placeholders for an entry's args and terms.
+// A key with a variant ("move_failed.missed") falls back to its base key ("move_failed") if the variant has no template.
+// Terms translate game terms, keyed by kind and value ("status.burn").
+type Catalogue struct {
+	Messages map[string]string
+	Terms    map[string]string
+}
+
+var catalogues = map[string]*Catalogue{
+	"en": englishCatalogue,
+}
+
+// LookupCatalogue returns the catalogue for a locale
+func LookupCatalogue(locale string) (*Catalogue, error) {
+	catalogue, ok := catalogues[locale]
+	if !ok {
+		return nil, ErrUnknownLocale
+	}
+	return catalogue, nil
+}
+
+// Locales returns every locale logs can be rendered in, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(catalogues))
+	for locale := range catalogues {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Render returns the text of a log entry in the catalogue's locale
+func (c *Catalogue) Render(entry Entry) (string, error) {
+	template, ok := c.Messages[entry.Key]
+	if !ok {
+		base, _, hasVariant := strings.Cut(entry.Key, ".")
+		if template, ok = c.Messages[base]; !hasVariant || !ok {
+			return "", ErrMissingTemplate
+		}
+	}
+
+	replacements := make([]string, 0, 2*(len(entry.Args)+len(entry.Terms)))
+	for name, value := range entry.Args {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	for kind, value := range entry.Terms {
+		term, ok := c.Terms[kind+"."+value]
+		if !ok {
+			term = value
+		}
+		replacements = append(replacements, "{"+kind+"}", term)
+	}
+	return strings.NewReplacer(replacements...).Replace(template), nil
+}
+
+var englishCatalogue = &Catalogue{
+	Messages: map[string]string{
+		"battle_started": "The battle between {player1} and {player2} has begun!",
+		"turn_started":   "Turn {turn}",
+		"sent_out":       "{player} sent out {creature}!",
+
+		"move_used":          "{creature} used {move}!",
+		"critical_hit":       "A critical hit!",
+		"super_effective":    "It's super effective!",
+		"not_very_effective": "It's not very effective...",
+		"no_effect":          "It doesn't affect {target}...",
+		"damage_dealt":       "{target} lost {damage} HP!",
+
+		"move_failed":                  "But it failed!",
+		"move_failed.flinched":         "{creature} flinched and couldn't move!",
+		"move_failed.missed":           "{creature}'s attack missed!",
+		"move_failed.immune":           "It doesn't affect {target}...",
+		"move_failed.no_target":        "But there was no target...",
+		"move_failed.terrain":          "{target} is protected by the terrain!",
+		"move_failed.stat_limit":       "{creature}'s stats won't go any further!",
+		"move_failed.already_affected": "But it failed!",
+
+		"status_applied":            "{creature} is now affected by {status}!",
+		"status_applied.poison":     "{creature} was poisoned!",
+		"status_applied.bad_poison": "{creature} was badly poisoned!",
+		"status_applied.burn":       "{creature} was burned!",
+		"status_applied.confusion":  "{creature} became confused!",
+		"status_applied.leech_seed": "{creature} was seeded!",
+		"status_ended":              "{creature} is no longer affected by {status}.",
+		"status_ended.confusion":    "{creature} snapped out of its confusion!",
+		"status_damage":             "{creature} was hurt by {status}!",
+		"status_damage.burn":        "{creature} was hurt by its burn!",
+		"status_cured":              "{creature} was cured of {status}!",
+		"confusion_self_hit":        "It hurt itself in its confusion!",
+		"recoil_damage":             "{creature} was damaged by the recoil!",
+		"leech_seed_drain":          "{creature}'s health is sapped by Leech Seed!",
+		"hp_restored":               "{creature} restored {healed} HP!",
+
+		"stat_rose":         "{creature}'s {stat} rose!",
+		"stat_rose.sharp":   "{creature}'s {stat} rose sharply!",
+		"stat_rose.drastic": "{creature}'s {stat} rose drastically!",
+		"stat_fell":         "{creature}'s {stat} fell!",
+		"stat_fell.sharp":   "{creature}'s {stat} harshly fell!",
+		"stat_fell.drastic": "{creature}'s {stat} severely fell!",
+
+		"creature_fainted": "{creature} fainted!",
+
+		"hazard_set":                 "{hazard} surrounds {player}'s team!",
+		"hazard_set.stealth_rock":    "Pointed stones float in the air around {player}'s team!",
+		"hazard_set.spikes":          "Spikes were scattered on the ground around {player}'s team!",
+		"hazard_set.toxic_spikes":    "Poison spikes were scattered on the ground around {player}'s team!",
+		"hazard_damage":              "{creature} was hurt by {hazard}!",
+		"hazard_damage.stealth_rock": "Pointed stones dug into {creature}!",
+		"hazard_cleared":             "The {hazard} around {player}'s team disappeared!",
+
+		"terrain_set":          "{terrain} covers the battlefield!",
+		"terrain_set.electric": "An electric current ran across the battlefield!",
+		"terrain_set.grassy":   "Grass grew to cover the battlefield!",
+		"terrain_set.psychic":  "The battlefield got weird!",
+		"terrain_set.misty":    "Mist swirled around the battlefield!",
+		"terrain_ended":        "The {terrain} faded.",
+
+		"item_used": "{player} used a {item} on {creature}!",
+
+		"battle_ended":                "{winner} won the battle!",
+		"battle_ended.forfeit":        "{loser} forfeited! {winner} won the battle!",
+		"battle_ended.draw":           "The battle ended in a draw!",
+		"battle_ended.turn_limit":     "The turn limit was reached! {winner} won with more HP remaining!",
+		"battle_ended.endless_battle": "The battle stalled! {winner} won with more HP remaining!",
+	},
+	Terms: map[string]string{
+		"status.poison":     "poison",
+		"status.bad_poison": "bad poison",
+		"status.burn":       "burn",
+		"status.confusion":  "confusion",
+		"status.leech_seed": "Leech Seed",
+
+		"stat.attack":     "Attack",
+		"stat.defense":    "Defense",
+		"stat.sp_attack":  "Sp. Atk",
+		"stat.sp_defense": "Sp. Def",
+		"stat.speed":      "Speed",
+		"stat.accuracy":   "accuracy",
+		"stat.evasion":    "evasiveness",
+		"stat.critical":   "critical-hit ratio",
+
+		"hazard.stealth_rock": "Stealth Rock",
+		"hazard.spikes":       "Spikes",
+		"hazard.toxic_spikes": "Toxic Spikes",
+
+		"terrain.electric": "Electric Terrain",
+		"terrain.grassy":   "Grassy Terrain",
+		"terrain.psychic":  "Psychic Terrain",
+		"terrain.misty":    "Misty Terrain",
+	},
+}
@@ -0,0 +1,92 @@
+package battlelog
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ========================================
+// Catalogue Tests
+// ========================================
+
+func TestLookupCatalogue(t *testing.T) {
+	if _, err := LookupCatalogue(DefaultLocale); err != nil {
+		t.Errorf("expected the default locale to have a catalogue, got %v", err)
+	}
+	if _, err := LookupCatalogue("xx"); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("expected ErrUnknownLocale, got %v", err)
+	}
+	if !slices.Contains(Locales(), DefaultLocale) {
+		t.Errorf("expected %q among %v", DefaultLocale, Locales())
+	}
+}
+
+func TestRender_SubstitutesArgsAndTerms(t *testing.T) {
+	catalogue, _ := LookupCatalogue("en")
+
+	text, err := catalogue.Render(Entry{Key: "stat_rose.sharp", Args: map[string]string{"creature": "Sparky"}, Terms: map[string]string{"stat": "sp_attack"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Sparky's Sp. Atk rose sharply!" {
+		t.Errorf("unexpected text: %q", text)
+	}
+}
+
+func TestRender_FallsBackToBaseKey(t *testing.T) {
+	catalogue := &Catalogue{
+		Messages: map[string]string{"status_applied": "{creature} has {status}"},
+		Terms:    map[string]string{},
+	}
+
+	// Terms without a translation are shown as their raw value
+	text, err := catalogue.Render(Entry{Key: "status_applied.sleep", Args: map[string]string{"creature": "Snorlax"}, Terms: map[string]string{"status": "sleep"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Snorlax has sleep" {
+		t.Errorf("unexpected text: %q", text)
+	}
+
+	if _, err := catalogue.Render(Entry{Key: "move_used"}); !errors.Is(err, ErrMissingTemplate) {
+		t.Errorf("expected ErrMissingTemplate, got %v", err)
+	}
+}
+
+func TestRender_LogsWholeBattle(t *testing.T) {
+	entries, err := Build(newTestReplay())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	catalogue, _ := LookupCatalogue("en")
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		if lines[i], err = catalogue.Render(entry); err != nil {
+			t.Fatalf("failed to render %+v: %v", entry, err)
+		}
+	}
+	if !slices.Contains(lines, "Sparky used Thunderbolt!") || !slices.Contains(lines, "It's super effective!") {
+		t.Errorf("unexpected log:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// Every locale must translate everything the default locale does, so no locale falls back to a generic message
+func TestCatalogues_CoverDefaultLocale(t *testing.T) {
+	base, _ := LookupCatalogue(DefaultLocale)
+	for _, locale := range Locales() {
+		catalogue, _ := LookupCatalogue(locale)
+		for key := range base.Messages {
+			if _, ok := catalogue.Messages[key]; !ok {
+				t.Errorf("locale %q: missing message %q", locale, key)
+			}
+		}
+		for key := range base.Terms {
+			if _, ok := catalogue.Terms[key]; !ok {
+				t.Errorf("locale %q: missing term %q", locale, key)
+			}
+		}
+	}
+}
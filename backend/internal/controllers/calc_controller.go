@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"poke-battles/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalcCreatureRequest struct {
+	SpeciesID string         `json:"species_id" binding:"required"`
+	Level     int            `json:"level"`      // Defaults to 50
+	CurrentHP int            `json:"current_hp"` // Defaults to full HP
+	Status    string         `json:"status"`
+	Stages    map[string]int `json:"stages"` // Stat stages keyed by stat, e.g. {"attack": 2}
+}
+
+type CalcRequest struct {
+	Attacker CalcCreatureRequest `json:"attacker" binding:"required"`
+	Defender CalcCreatureRequest `json:"defender" binding:"required"`
+	MoveID   string              `json:"move_id" binding:"required"`
+	Critical bool                `json:"critical"`
+	Ruleset  string              `json:"ruleset"` // Applies the ruleset's type chart, if it has one
+}
+
+type CalcResponse struct {
+	MinDamage     int     `json:"min_damage"`
+	MaxDamage     int     `json:"max_damage"`
+	MinPercent    float64 `json:"min_percent"` // Of the defender's max HP
+	MaxPercent    float64 `json:"max_percent"`
+	Rolls         []int   `json:"rolls"` // Damage for each equally likely roll, lowest first
+	Effectiveness float64 `json:"effectiveness"`
+	KOChance      float64 `json:"ko_chance"` // 0..1, from the defender's current HP
+	DefenderHP    int     `json:"defender_hp"`
+	DefenderMaxHP int     `json:"defender_max_hp"`
+}
+
+// calcStages are the stat stages that affect damage, keyed by their request name
+var calcStages = map[string]game.BattleStat{
+	string(game.StatAttack):    game.StatAttack,
+	string(game.StatDefense):   game.StatDefense,
+	string(game.StatSpAttack):  game.StatSpAttack,
+	string(game.StatSpDefense): game.StatSpDefense,
+}
+
+// Calc request validation errors
+var (
+	errInvalidCurrentHP = errors.New("current HP out of range")
+	errUnknownStatStage = errors.New("unknown stat stage")
+	errUnknownStatus    = errors.New("unknown status condition")
+)
+
+// CalcController handles HTTP requests for damage calculations
+type CalcController struct{}
+
+// NewCalcController creates a new damage calculator controller
+func NewCalcController() *CalcController {
+	return &CalcController{}
+}
+
+// Calculate handles POST /api/v1/calc.
+// It returns the damage range and KO chance of a move using the battle engine's damage formula.
+func (c *CalcController) Calculate(ctx *gin.Context) {
+	var req CalcRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data := game.CurrentDataPack()
+	if req.Ruleset != "" {
+		ruleset, err := game.LookupRuleset(req.Ruleset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownRuleset})
+			return
+		}
+		data = ruleset.DataPack(data)
+	}
+
+	move, err := game.LookupMove(req.MoveID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownMove})
+		return
+	}
+	attacker, err := buildCalcCreature("attacker", req.Attacker)
+	if err != nil {
+		respondInvalidCalcCreature(ctx, err)
+		return
+	}
+	defender, err := buildCalcCreature("defender", req.Defender)
+	if err != nil {
+		respondInvalidCalcCreature(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, buildCalcResponse(data.CalculateDamageRange(attacker, defender, move, req.Critical), defender))
+}
+
+// respondInvalidCalcCreature reports why an attacker or defender could not be built
+func respondInvalidCalcCreature(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, game.ErrUnknownSpecies):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownSpecies})
+	case errors.Is(err, game.ErrInvalidLevel):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidLevel})
+	case errors.Is(err, errInvalidCurrentHP):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidCurrentHP})
+	case errors.Is(err, errUnknownStatStage):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownStatStage})
+	case errors.Is(err, errUnknownStatus):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownStatus})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgCalculateDamage})
+	}
+}
+
+// buildCalcCreature creates a creature in the state described by the request
+func buildCalcCreature(id string, req CalcCreatureRequest) (*game.Creature, error) {
+	level := req.Level
+	if level == 0 {
+		level = game.DefaultLevel
+	}
+	if level < game.MinLevel || level > game.MaxLevel {
+		return nil, game.ErrInvalidLevel
+	}
+
+	creature, err := game.NewCreatureFromSpecies(id, req.SpeciesID, level, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CurrentHP < 0 || req.CurrentHP > creature.MaxHP() {
+		return nil, errInvalidCurrentHP
+	}
+	if req.CurrentHP > 0 {
+		creature.CurrentHP = req.CurrentHP
+	}
+
+	switch status := game.StatusCondition(req.Status); status {
+	case game.StatusNone, game.StatusPoison, game.StatusBadPoison, game.StatusBurn:
+		creature.Status = status
+	default:
+		return nil, errUnknownStatus
+	}
+
+	for name, stages := range req.Stages {
+		stat, ok := calcStages[name]
+		if !ok {
+			return nil, errUnknownStatStage
+		}
+		creature.Stages.Apply(stat, stages)
+	}
+	return creature, nil
+}
+
+// buildCalcResponse converts a damage range to the response, with damage as a share of the defender's HP
+func buildCalcResponse(r game.DamageRange, defender *game.Creature) CalcResponse {
+	percent := func(damage int) float64 {
+		return float64(damage) * 100 / float64(defender.MaxHP())
+	}
+	return CalcResponse{
+		MinDamage:     r.Min(),
+		MaxDamage:     r.Max(),
+		MinPercent:    percent(r.Min()),
+		MaxPercent:    percent(r.Max()),
+		Rolls:         r.Rolls,
+		Effectiveness: r.Effectiveness,
+		KOChance:      r.KOChance,
+		DefenderHP:    defender.CurrentHP,
+		DefenderMaxHP: defender.MaxHP(),
+	}
+}
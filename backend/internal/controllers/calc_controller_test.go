@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupCalcRouter() *gin.Engine {
+	ctrl := NewCalcController()
+
+	router := gin.New()
+	router.POST("/api/v1/calc", ctrl.Calculate)
+	return router
+}
+
+// postCalc sends a calc request and returns the recorded response
+func postCalc(router *gin.Engine, calc CalcRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(calc)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/calc", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCalc_Success(t *testing.T) {
+	router := setupCalcRouter()
+
+	w := postCalc(router, CalcRequest{
+		Attacker: CalcCreatureRequest{SpeciesID: "charizard"},
+		Defender: CalcCreatureRequest{SpeciesID: "venusaur"},
+		MoveID:   "flamethrower",
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response CalcResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if response.Effectiveness != 2 {
+		t.Errorf("expected fire to be super effective on grass, got %v", response.Effectiveness)
+	}
+	if response.MinDamage <= 0 || response.MinDamage > response.MaxDamage || len(response.Rolls) != 16 {
+		t.Errorf("unexpected damage range: %+v", response)
+	}
+	if response.DefenderHP != response.DefenderMaxHP {
+		t.Errorf("expected the defender to default to full HP, got %d/%d", response.DefenderHP, response.DefenderMaxHP)
+	}
+}
+
+func TestCalc_StateAffectsDamage(t *testing.T) {
+	router := setupCalcRouter()
+	calc := CalcRequest{
+		Attacker: CalcCreatureRequest{SpeciesID: "charizard"},
+		Defender: CalcCreatureRequest{SpeciesID: "blastoise"},
+		MoveID:   "slash",
+	}
+
+	var baseline, boosted CalcResponse
+	json.Unmarshal(postCalc(router, calc).Body.Bytes(), &baseline)
+	calc.Attacker.Stages = map[string]int{"attack": 2}
+	calc.Defender.CurrentHP = 1
+	json.Unmarshal(postCalc(router, calc).Body.Bytes(), &boosted)
+
+	if boosted.MaxDamage <= baseline.MaxDamage {
+		t.Errorf("expected +2 attack to raise damage: %d vs %d", baseline.MaxDamage, boosted.MaxDamage)
+	}
+	if baseline.KOChance != 0 || boosted.KOChance != 1 {
+		t.Errorf("expected KO chance to follow current HP, got %v and %v", baseline.KOChance, boosted.KOChance)
+	}
+}
+
+func TestCalc_RulesetTypeChart(t *testing.T) {
+	router := setupCalcRouter()
+
+	w := postCalc(router, CalcRequest{
+		Attacker: CalcCreatureRequest{SpeciesID: "charizard"},
+		Defender: CalcCreatureRequest{SpeciesID: "venusaur"},
+		MoveID:   "flamethrower",
+		Ruleset:  "inverse",
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response CalcResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if response.Effectiveness != 0.5 {
+		t.Errorf("expected fire to be resisted by grass in an inverse battle, got %v", response.Effectiveness)
+	}
+}
+
+func TestCalc_InvalidRequests(t *testing.T) {
+	router := setupCalcRouter()
+	valid := CalcCreatureRequest{SpeciesID: "pikachu"}
+
+	tests := []struct {
+		name string
+		calc CalcRequest
+		want string
+	}{
+		{"unknown move", CalcRequest{Attacker: valid, Defender: valid, MoveID: "splash"}, errMsgUnknownMove},
+		{"unknown species", CalcRequest{Attacker: CalcCreatureRequest{SpeciesID: "missingno"}, Defender: valid, MoveID: "thunderbolt"}, errMsgUnknownSpecies},
+		{"level too high", CalcRequest{Attacker: CalcCreatureRequest{SpeciesID: "pikachu", Level: 101}, Defender: valid, MoveID: "thunderbolt"}, errMsgInvalidLevel},
+		{"current HP above max", CalcRequest{Attacker: valid, Defender: CalcCreatureRequest{SpeciesID: "pikachu", CurrentHP: 9999}, MoveID: "thunderbolt"}, errMsgInvalidCurrentHP},
+		{"unknown stage", CalcRequest{Attacker: CalcCreatureRequest{SpeciesID: "pikachu", Stages: map[string]int{"luck": 1}}, Defender: valid, MoveID: "thunderbolt"}, errMsgUnknownStatStage},
+		{"unknown status", CalcRequest{Attacker: CalcCreatureRequest{SpeciesID: "pikachu", Status: "sleep"}, Defender: valid, MoveID: "thunderbolt"}, errMsgUnknownStatus},
+		{"unknown ruleset", CalcRequest{Attacker: valid, Defender: valid, MoveID: "thunderbolt", Ruleset: "anything-goes"}, errMsgUnknownRuleset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postCalc(router, tt.calc)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp["error"] != tt.want {
+				t.Errorf("expected error %q, got %q", tt.want, resp["error"])
+			}
+		})
+	}
+}
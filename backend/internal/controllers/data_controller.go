@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"poke-battles/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataVersionResponse struct {
+	Version      string `json:"version"`
+	Description  string `json:"description"`
+	Current      bool   `json:"current"` // New battles are played with this version
+	SpeciesCount int    `json:"species_count"`
+	MoveCount    int    `json:"move_count"`
+}
+
+// DataController handles HTTP requests for the versioned game data
+type DataController struct{}
+
+// NewDataController creates a new data controller
+func NewDataController() *DataController {
+	return &DataController{}
+}
+
+// ListVersions handles GET /api/v1/data-versions
+func (c *DataController) ListVersions(ctx *gin.Context) {
+	packs := game.ListDataPacks()
+
+	response := make([]DataVersionResponse, len(packs))
+	for i, p := range packs {
+		response[i] = DataVersionResponse{
+			Version:      p.Version,
+			Description:  p.Description,
+			Current:      p.Version == game.CurrentDataVersion,
+			SpeciesCount: len(p.Species),
+			MoveCount:    len(p.Moves),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
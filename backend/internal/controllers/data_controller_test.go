@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poke-battles/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupDataRouter() *gin.Engine {
+	ctrl := NewDataController()
+
+	router := gin.New()
+	router.GET("/api/v1/data-versions", ctrl.ListVersions)
+	return router
+}
+
+func TestListDataVersions_Success(t *testing.T) {
+	router := setupDataRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/data-versions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response []DataVersionResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if len(response) != len(game.ListDataPacks()) {
+		t.Fatalf("expected %d data versions, got %d", len(game.ListDataPacks()), len(response))
+	}
+
+	current := 0
+	for _, v := range response {
+		if !v.Current {
+			continue
+		}
+		current++
+		if v.Version != game.CurrentDataVersion || v.SpeciesCount == 0 || v.MoveCount == 0 {
+			t.Errorf("unexpected current data version: %+v", v)
+		}
+	}
+	if current != 1 {
+		t.Errorf("expected exactly one current data version, got %d", current)
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"poke-battles/internal/battlelog"
+	"poke-battles/internal/replay"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GameLogResponse struct {
+	GameID  string                 `json:"game_id"`
+	Locale  string                 `json:"locale"`
+	Entries []GameLogEntryResponse `json:"entries"`
+}
+
+// GameLogEntryResponse is a log line rendered in the requested locale,
+// alongside the key, args and terms clients can use to render it themselves
+type GameLogEntryResponse struct {
+	battlelog.Entry
+	Text string `json:"text"`
+}
+
+// GameLogController handles HTTP requests for the human-readable logs of finished games
+type GameLogController struct {
+	replays replay.Store
+}
+
+// NewGameLogController creates a new game log controller that reads games from the replay store
+func NewGameLogController(replays replay.Store) *GameLogController {
+	return &GameLogController{
+		replays: replays,
+	}
+}
+
+// Get handles GET /api/v1/games/:id/log?locale=en
+// A game is identified by its replay ID.
+func (c *GameLogController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	locale := ctx.DefaultQuery("locale", battlelog.DefaultLocale)
+	catalogue, err := battlelog.LookupCatalogue(locale)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownLocale})
+		return
+	}
+
+	doc, err := c.replays.Get(id)
+	if err != nil {
+		if errors.Is(err, replay.ErrReplayNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": errMsgGameNotFound})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetGameLog})
+		return
+	}
+
+	entries, err := battlelog.Build(doc)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetGameLog})
+		return
+	}
+
+	response := GameLogResponse{GameID: doc.ID, Locale: locale, Entries: make([]GameLogEntryResponse, len(entries))}
+	for i, entry := range entries {
+		text, err := catalogue.Render(entry)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetGameLog})
+			return
+		}
+		response.Entries[i] = GameLogEntryResponse{Entry: entry, Text: text}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupGameLogRouter(store replay.Store) *gin.Engine {
+	ctrl := NewGameLogController(store)
+
+	router := gin.New()
+	router.GET("/api/v1/games/:id/log", ctrl.Get)
+	return router
+}
+
+// saveTestGame stores the replay of a game player-1 won with a single Thunderbolt
+func saveTestGame(store replay.Store, dataVersion string) {
+	store.Save(&replay.Replay{
+		Version:     replay.FormatVersion,
+		ID:          "ABC123-1",
+		BattleID:    "ABC123",
+		DataVersion: dataVersion,
+		Players: []replay.Player{
+			{ID: "player-1", Team: []replay.Creature{{ID: "player-1-0", SpeciesID: "pikachu", Level: 50, Moves: []string{"thunderbolt"}}}},
+			{ID: "player-2", Team: []replay.Creature{{ID: "player-2-0", SpeciesID: "blastoise", Level: 50, Moves: []string{"swords-dance"}}}},
+		},
+		Turns: []replay.Turn{{
+			Number: 1,
+			Events: []replay.Event{
+				{Order: 1, Type: string(game.EventMoveUsed), Actor: "player-1", Target: "player-1-0", MoveID: "thunderbolt"},
+				{Order: 2, Type: string(game.EventDamageDealt), Actor: "player-1", Target: "player-2-0", MoveID: "thunderbolt", Damage: 160, Effectiveness: "super_effective"},
+				{Order: 3, Type: string(game.EventCreatureFainted), Actor: "player-2", Target: "player-2-0"},
+			},
+		}},
+		Outcome: &replay.Outcome{WinnerID: "player-1", LoserID: "player-2", Reason: string(game.EndReasonVictory)},
+	})
+}
+
+func TestGetGameLog_Success(t *testing.T) {
+	store := replay.NewMemoryStore()
+	saveTestGame(store, game.CurrentDataVersion)
+	router := setupGameLogRouter(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/games/ABC123-1/log", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response GameLogResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.GameID != "ABC123-1" || response.Locale != "en" {
+		t.Errorf("unexpected log header: %+v", response)
+	}
+
+	var texts []string
+	for _, entry := range response.Entries {
+		texts = append(texts, entry.Text)
+	}
+	expected := []string{
+		"The battle between player-1 and player-2 has begun!",
+		"player-1 sent out Pikachu!",
+		"player-2 sent out Blastoise!",
+		"Turn 1",
+		"Pikachu used Thunderbolt!",
+		"It's super effective!",
+		"Blastoise lost 160 HP!",
+		"Blastoise fainted!",
+		"player-1 won the battle!",
+	}
+	if len(texts) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), texts)
+	}
+	for i := range expected {
+		if texts[i] != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], texts[i])
+		}
+	}
+
+	// Entries also carry their locale-neutral form
+	if move := response.Entries[4]; move.Key != "move_used" || move.Args["move"] != "Thunderbolt" {
+		t.Errorf("unexpected move entry: %+v", move)
+	}
+}
+
+func TestGetGameLog_NotFound(t *testing.T) {
+	router := setupGameLogRouter(replay.NewMemoryStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/games/NOPE00-1/log", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestGetGameLog_UnknownLocale(t *testing.T) {
+	store := replay.NewMemoryStore()
+	saveTestGame(store, game.CurrentDataVersion)
+	router := setupGameLogRouter(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/games/ABC123-1/log?locale=xx", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["error"] != errMsgUnknownLocale {
+		t.Errorf("expected error %q, got %q", errMsgUnknownLocale, response["error"])
+	}
+}
+
+func TestGetGameLog_UnknownDataVersion(t *testing.T) {
+	store := replay.NewMemoryStore()
+	saveTestGame(store, "999")
+	router := setupGameLogRouter(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/games/ABC123-1/log", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -3,9 +3,13 @@ package controllers
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"poke-battles/internal/game"
 	"poke-battles/internal/services"
+	"poke-battles/internal/showdown"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,15 +17,40 @@ import (
 // Request types
 
 type CreateLobbyRequest struct {
+	PlayerID   string `json:"player_id" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	MaxPlayers int    `json:"max_players"` // 2 to 8; defaults to 2
+	Visibility string `json:"visibility"`  // public or unlisted; defaults to public
+	Code       string `json:"code"`        // Optional custom room code; a random one is generated when empty
+}
+
+type JoinLobbyRequest struct {
 	PlayerID string `json:"player_id" binding:"required"`
 	Username string `json:"username" binding:"required"`
+	Invite   string `json:"invite"` // Optional invite token from POST /lobbies/:code/invites
 }
 
-type JoinLobbyRequest struct {
+type SpectateLobbyRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+}
+
+type JoinWaitlistRequest struct {
 	PlayerID string `json:"player_id" binding:"required"`
 	Username string `json:"username" binding:"required"`
 }
 
+type CreateInviteRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+}
+
+type QuickJoinRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Ruleset  string `json:"ruleset"`
+	BestOf   int    `json:"best_of"`
+}
+
 type LeaveLobbyRequest struct {
 	PlayerID string `json:"player_id" binding:"required"`
 }
@@ -30,24 +59,138 @@ type StartGameRequest struct {
 	PlayerID string `json:"player_id" binding:"required"`
 }
 
+type TeamMemberRequest struct {
+	SpeciesID string   `json:"species_id" binding:"required"`
+	Level     int      `json:"level"`
+	Item      string   `json:"item"`
+	Moves     []string `json:"moves" binding:"required"`
+	Nickname  string   `json:"nickname,omitempty"`
+	Shiny     bool     `json:"shiny,omitempty"`
+}
+
+type SubmitTeamRequest struct {
+	PlayerID string              `json:"player_id" binding:"required"`
+	Team     []TeamMemberRequest `json:"team" binding:"required"`
+}
+
+type SetReadyRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Ready    *bool  `json:"ready" binding:"required"`
+}
+
+type SetRulesetRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Ruleset  string `json:"ruleset" binding:"required"`
+}
+
+type SetSeriesRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	BestOf   int    `json:"best_of" binding:"required"`
+}
+
+type SetRematchTeamsRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Teams    string `json:"teams" binding:"required"`
+}
+
+type UpdateSettingsRequest struct {
+	PlayerID      string    `json:"player_id" binding:"required"`
+	Ruleset       *string   `json:"ruleset"`
+	BestOf        *int      `json:"best_of"`
+	TurnTimerSec  *int      `json:"turn_timer_sec"`
+	CountdownSec  *int      `json:"countdown_sec"`
+	StartMode     *string   `json:"start_mode"`
+	Visibility    *string   `json:"visibility"`
+	MaxSpectators *int      `json:"max_spectators"`
+	Tags          *[]string `json:"tags"`
+}
+
+type AddBotRequest struct {
+	PlayerID   string `json:"player_id" binding:"required"`
+	Difficulty string `json:"difficulty"`
+}
+
+type SetDraftRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Enabled  *bool  `json:"enabled" binding:"required"`
+}
+
 // Response types
 
 type PlayerResponse struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	TeamSubmitted bool   `json:"team_submitted"`
+	IsReady       bool   `json:"is_ready"`
+	IsBot         bool   `json:"is_bot"`
+	BotDifficulty string `json:"bot_difficulty,omitempty"`
+}
+
+type SpectatorResponse struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
 }
 
+type WaitlistEntryResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Position int    `json:"position"`
+}
+
 type LobbyResponse struct {
-	Code       string           `json:"code"`
-	State      string           `json:"state"`
-	Players    []PlayerResponse `json:"players"`
-	HostID     string           `json:"host_id"`
-	MaxPlayers int              `json:"max_players"`
+	Code           string                  `json:"code"`
+	State          string                  `json:"state"`
+	Players        []PlayerResponse        `json:"players"`
+	Spectators     []SpectatorResponse     `json:"spectators"`
+	Waitlist       []WaitlistEntryResponse `json:"waitlist"`
+	HostID         string                  `json:"host_id"`
+	MaxPlayers     int                     `json:"max_players"`
+	Visibility     string                  `json:"visibility"`
+	Ruleset        string                  `json:"ruleset"`
+	Series         SeriesResponse          `json:"series"`
+	DraftMode      bool                    `json:"draft_mode"`
+	RematchTeams   string                  `json:"rematch_teams"`
+	Settings       SettingsResponse        `json:"settings"`
+	CreatedAt      time.Time               `json:"created_at"`
+	LastActivityAt time.Time               `json:"last_activity_at"`
 }
 
-type LobbyListResponse []LobbyResponse
+type SettingsResponse struct {
+	Ruleset       string   `json:"ruleset"`
+	BestOf        int      `json:"best_of"`
+	TurnTimerSec  int      `json:"turn_timer_sec"`
+	CountdownSec  int      `json:"countdown_sec"`
+	StartMode     string   `json:"start_mode"`
+	Visibility    string   `json:"visibility"`
+	MaxSpectators int      `json:"max_spectators"`
+	Tags          []string `json:"tags"`
+}
+
+type SeriesResponse struct {
+	BestOf      int            `json:"best_of"`
+	GamesPlayed int            `json:"games_played"`
+	Wins        map[string]int `json:"wins"`
+}
 
-// LobbyController handles HTTP requests for lobby operations
+type ViolationResponse struct {
+	Slot    *int   `json:"slot,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type InviteResponse struct {
+	Code      string    `json:"code"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LobbyListResponse struct {
+	Lobbies    []LobbyResponse `json:"lobbies"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// LobbyController handles HTTP requests for lobby operations. The lobby service publishes the changes
+// they make, so the lobby's connected clients hear of them as they would of changes made over the websocket.
 type LobbyController struct {
 	lobbyService services.LobbyService
 }
@@ -65,18 +208,106 @@ func toLobbyResponse(lobby *game.Lobby) LobbyResponse {
 	playerResponses := make([]PlayerResponse, len(players))
 	for i, p := range players {
 		playerResponses[i] = PlayerResponse{
-			ID:       p.ID,
-			Username: p.Username,
+			ID:            p.ID,
+			Username:      p.Username,
+			TeamSubmitted: lobby.HasTeam(p.ID),
+			IsReady:       p.Ready,
+			IsBot:         p.Bot,
+			BotDifficulty: string(p.BotDifficulty),
 		}
 	}
 
+	spectators := lobby.GetSpectators()
+	spectatorResponses := make([]SpectatorResponse, len(spectators))
+	for i, s := range spectators {
+		spectatorResponses[i] = SpectatorResponse{ID: s.ID, Username: s.Username}
+	}
+
+	waitlist := lobby.GetWaitlist()
+	waitlistResponses := make([]WaitlistEntryResponse, len(waitlist))
+	for i, w := range waitlist {
+		waitlistResponses[i] = WaitlistEntryResponse{ID: w.ID, Username: w.Username, Position: i + 1}
+	}
+
 	return LobbyResponse{
-		Code:       lobby.Code,
-		State:      lobby.GetState().String(),
-		Players:    playerResponses,
-		HostID:     lobby.GetHostID(),
-		MaxPlayers: lobby.MaxPlayers,
+		Code:           lobby.Code,
+		State:          lobby.GetState().String(),
+		Players:        playerResponses,
+		Spectators:     spectatorResponses,
+		Waitlist:       waitlistResponses,
+		HostID:         lobby.GetHostID(),
+		MaxPlayers:     lobby.MaxPlayers,
+		Visibility:     string(lobby.GetVisibility()),
+		Ruleset:        lobby.GetRuleset().ID,
+		Series:         toSeriesResponse(lobby.GetSeries()),
+		DraftMode:      lobby.DraftMode(),
+		RematchTeams:   string(lobby.GetRematchTeams()),
+		Settings:       toSettingsResponse(lobby.Settings()),
+		CreatedAt:      lobby.CreatedAt,
+		LastActivityAt: lobby.LastActivity(),
+	}
+}
+
+// toSettingsResponse converts a lobby's settings to a response DTO
+func toSettingsResponse(settings game.LobbySettings) SettingsResponse {
+	return SettingsResponse{
+		Ruleset:       settings.Ruleset.ID,
+		BestOf:        settings.BestOf,
+		TurnTimerSec:  int(settings.TurnTimer / time.Second),
+		CountdownSec:  int(settings.Countdown / time.Second),
+		StartMode:     string(settings.StartMode),
+		Visibility:    string(settings.Visibility),
+		MaxSpectators: settings.MaxSpectators,
+		Tags:          toTagStrings(settings.Tags),
+	}
+}
+
+// toTagStrings converts lobby tags to their string form, never returning nil
+func toTagStrings(tags []game.LobbyTag) []string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = string(tag)
 	}
+	return values
+}
+
+// toSeriesResponse converts a lobby's series score to a response DTO
+func toSeriesResponse(series game.Series) SeriesResponse {
+	return SeriesResponse{
+		BestOf:      series.BestOf,
+		GamesPlayed: series.Games,
+		Wins:        series.Wins,
+	}
+}
+
+// toViolationResponses lists the violations of a team validation error.
+// Slot is omitted for violations that apply to the whole team.
+func toViolationResponses(err error) []ViolationResponse {
+	var teamErr *game.TeamError
+	if !errors.As(err, &teamErr) {
+		return nil
+	}
+
+	violations := make([]ViolationResponse, len(teamErr.Violations))
+	for i, v := range teamErr.Violations {
+		violations[i] = ViolationResponse{Rule: string(v.Rule), Message: v.Err.Error()}
+		if v.Slot >= 0 {
+			slot := v.Slot
+			violations[i].Slot = &slot
+		}
+	}
+	return violations
+}
+
+// respondLobbyLimit answers 409 naming the lobby the player is already in if err is a LobbyLimitError.
+// It returns false, writing nothing, for any other error.
+func respondLobbyLimit(ctx *gin.Context, err error) bool {
+	var limitErr *services.LobbyLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	ctx.JSON(http.StatusConflict, gin.H{"error": errMsgTooManyLobbies, "lobby_code": limitErr.LobbyCode})
+	return true
 }
 
 // Create handles POST /api/v1/lobbies
@@ -87,15 +318,58 @@ func (c *LobbyController) Create(ctx *gin.Context) {
 		return
 	}
 
-	lobby, err := c.lobbyService.CreateLobby(req.PlayerID, req.Username)
+	maxPlayers := req.MaxPlayers
+	if maxPlayers == 0 {
+		maxPlayers = game.DefaultMaxPlayers
+	}
+
+	visibility := game.LobbyVisibility(req.Visibility)
+	if visibility == "" {
+		visibility = game.LobbyVisibilityPublic
+	}
+
+	var lobby *game.Lobby
+	var err error
+	if req.Code != "" {
+		lobby, err = c.lobbyService.CreateLobbyWithCode(req.Code, req.PlayerID, req.Username, maxPlayers, visibility)
+	} else {
+		lobby, err = c.lobbyService.CreateLobby(req.PlayerID, req.Username, maxPlayers, visibility)
+	}
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgCreateLobby})
+		if respondLobbyLimit(ctx, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		message := errMsgCreateLobby
+
+		switch {
+		case errors.Is(err, game.ErrInvalidMaxPlayers):
+			status = http.StatusBadRequest
+			message = errMsgInvalidMaxPlayers
+		case errors.Is(err, game.ErrUnknownVisibility):
+			status = http.StatusBadRequest
+			message = errMsgUnknownVisibility
+		case errors.Is(err, game.ErrInvalidRoomCode):
+			status = http.StatusBadRequest
+			message = errMsgInvalidRoomCode
+		case errors.Is(err, services.ErrRoomCodeTaken):
+			status = http.StatusConflict
+			message = errMsgRoomCodeTaken
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, toLobbyResponse(lobby))
 }
 
+// ListTags handles GET /api/v1/lobbies/tags, the tags hosts may give their lobby
+func (c *LobbyController) ListTags(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"tags": toTagStrings(game.ListLobbyTags())})
+}
+
 // Get handles GET /api/v1/lobbies/:code
 func (c *LobbyController) Get(ctx *gin.Context) {
 	code := ctx.Param("code")
@@ -113,17 +387,88 @@ func (c *LobbyController) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
 }
 
-// List handles GET /api/v1/lobbies
+// List handles GET /api/v1/lobbies?state=&has_open_slot=&best_of=&tag=&sort=&limit=&cursor=.
+// tag may be repeated to only list lobbies carrying every tag.
+// sort is created_at or last_activity_at, prefixed with - for newest first.
 func (c *LobbyController) List(ctx *gin.Context) {
-	lobbies, err := c.lobbyService.ListLobbies()
+	var filter services.LobbyFilter
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		message := errMsgInvalidCursor
+		if errors.Is(err, errInvalidPageLimit) {
+			message = errMsgInvalidPageLimit
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	if value := ctx.Query("state"); value != "" {
+		state, ok := game.ParseLobbyState(value)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidStateFilter})
+			return
+		}
+		filter.State = &state
+	}
+
+	if value := ctx.Query("has_open_slot"); value != "" {
+		hasOpenSlot, err := strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidOpenSlot})
+			return
+		}
+		filter.HasOpenSlot = &hasOpenSlot
+	}
+
+	if value := ctx.Query("best_of"); value != "" {
+		bestOf, err := strconv.Atoi(value)
+		if err != nil || !game.ValidSeriesLength(bestOf) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidSeriesLength})
+			return
+		}
+		filter.BestOf = bestOf
+	}
+
+	for _, value := range ctx.QueryArray("tag") {
+		tag, err := game.ParseLobbyTag(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgUnknownLobbyTag})
+			return
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	order := listOrder{Sort: ctx.DefaultQuery("sort", string(services.LobbySortCreatedAt))}
+	sortBy, newestFirst := strings.CutPrefix(order.Sort, "-")
+	filter.NewestFirst, order.NewestFirst = newestFirst, newestFirst
+	switch filter.SortBy = services.LobbySortField(sortBy); filter.SortBy {
+	case services.LobbySortCreatedAt, services.LobbySortLastActivity:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidLobbySort})
+		return
+	}
+
+	lobbies, err := c.lobbyService.ListLobbies(filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetLobbies})
 		return
 	}
 
-	response := make(LobbyListResponse, len(lobbies))
+	lobbies, nextCursor, err := paginate(lobbies, page, order, func(lobby *game.Lobby) pageKey {
+		return newPageKey(filter.SortKey(lobby), lobby.Code)
+	})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidCursor})
+		return
+	}
+
+	response := LobbyListResponse{
+		Lobbies:    make([]LobbyResponse, len(lobbies)),
+		NextCursor: nextCursor,
+	}
 	for i, lobby := range lobbies {
-		response[i] = toLobbyResponse(lobby)
+		response.Lobbies[i] = toLobbyResponse(lobby)
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -139,8 +484,18 @@ func (c *LobbyController) Join(ctx *gin.Context) {
 		return
 	}
 
-	lobby, err := c.lobbyService.JoinLobby(code, req.PlayerID, req.Username)
+	var lobby *game.Lobby
+	var err error
+	if req.Invite != "" {
+		lobby, err = c.lobbyService.JoinLobbyWithInvite(code, req.Invite, req.PlayerID, req.Username)
+	} else {
+		lobby, err = c.lobbyService.JoinLobby(code, req.PlayerID, req.Username)
+	}
 	if err != nil {
+		if respondLobbyLimit(ctx, err) {
+			return
+		}
+
 		status := http.StatusInternalServerError
 		message := errMsgJoinLobby
 
@@ -148,6 +503,12 @@ func (c *LobbyController) Join(ctx *gin.Context) {
 		case errors.Is(err, services.ErrLobbyNotFound):
 			status = http.StatusNotFound
 			message = errMsgLobbyNotFound
+		case errors.Is(err, game.ErrInvalidInvite):
+			status = http.StatusForbidden
+			message = errMsgInvalidInvite
+		case errors.Is(err, game.ErrInviteExpired):
+			status = http.StatusGone
+			message = errMsgInviteExpired
 		case errors.Is(err, game.ErrLobbyFull):
 			status = http.StatusConflict
 			message = errMsgLobbyFull
@@ -166,6 +527,167 @@ func (c *LobbyController) Join(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
 }
 
+// Spectate handles POST /api/v1/lobbies/:code/spectate
+func (c *LobbyController) Spectate(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SpectateLobbyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SpectateLobby(code, req.PlayerID, req.Username)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSpectateLobby
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, game.ErrSpectatorsFull):
+			status = http.StatusConflict
+			message = errMsgSpectatorsFull
+		case errors.Is(err, game.ErrAlreadySpectating):
+			status = http.StatusConflict
+			message = errMsgAlreadySpectating
+		case errors.Is(err, game.ErrPlayerAlreadyJoined):
+			status = http.StatusConflict
+			message = errMsgPlayerAlreadyInLobby
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// JoinWaitlist handles POST /api/v1/lobbies/:code/waitlist, queueing a player for a full lobby's next free slot
+func (c *LobbyController) JoinWaitlist(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req JoinWaitlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.JoinWaitlist(code, req.PlayerID, req.Username)
+	if err != nil {
+		if respondLobbyLimit(ctx, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		message := errMsgJoinWaitlist
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, game.ErrLobbyNotFull):
+			status = http.StatusConflict
+			message = errMsgLobbyNotFull
+		case errors.Is(err, game.ErrWaitlistFull):
+			status = http.StatusConflict
+			message = errMsgWaitlistFull
+		case errors.Is(err, game.ErrAlreadyWaitlisted):
+			status = http.StatusConflict
+			message = errMsgAlreadyWaitlisted
+		case errors.Is(err, game.ErrWaitlistSpectating):
+			status = http.StatusConflict
+			message = errMsgWaitlistSpectating
+		case errors.Is(err, game.ErrPlayerAlreadyJoined):
+			status = http.StatusConflict
+			message = errMsgPlayerAlreadyInLobby
+		case errors.Is(err, game.ErrInvalidStateForJoin):
+			status = http.StatusConflict
+			message = errMsgLobbyInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// CreateInvite handles POST /api/v1/lobbies/:code/invites
+func (c *LobbyController) CreateInvite(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req CreateInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invite, err := c.lobbyService.CreateInvite(code, req.PlayerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgCreateInvite
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForInvite):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanInvite
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, InviteResponse{
+		Code:      code,
+		Token:     invite.Token,
+		ExpiresAt: invite.ExpiresAt,
+	})
+}
+
+// QuickJoin handles POST /api/v1/lobbies/quick-join.
+// It responds 200 with the lobby the player joined, or 201 if a new lobby had to be created.
+func (c *LobbyController) QuickJoin(ctx *gin.Context) {
+	var req QuickJoinRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := services.QuickJoinFilter{RulesetID: req.Ruleset, BestOf: req.BestOf}
+	lobby, created, err := c.lobbyService.QuickJoin(req.PlayerID, req.Username, filter)
+	if err != nil {
+		if respondLobbyLimit(ctx, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		message := errMsgJoinLobby
+
+		switch {
+		case errors.Is(err, game.ErrUnknownRuleset):
+			status = http.StatusBadRequest
+			message = errMsgUnknownRuleset
+		case errors.Is(err, game.ErrInvalidSeriesLength):
+			status = http.StatusBadRequest
+			message = errMsgInvalidSeriesLength
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, toLobbyResponse(lobby))
+}
+
 // Leave handles POST /api/v1/lobbies/:code/leave
 func (c *LobbyController) Leave(ctx *gin.Context) {
 	code := ctx.Param("code")
@@ -176,7 +698,7 @@ func (c *LobbyController) Leave(ctx *gin.Context) {
 		return
 	}
 
-	err := c.lobbyService.LeaveLobby(code, req.PlayerID)
+	_, err := c.lobbyService.LeaveLobby(code, req.PlayerID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		message := errMsgLeaveLobby
@@ -197,6 +719,37 @@ func (c *LobbyController) Leave(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": msgLeftLobby})
 }
 
+// Delete handles DELETE /api/v1/lobbies/:code?player_id=
+func (c *LobbyController) Delete(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	playerID := ctx.Query("player_id")
+	if playerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgPlayerIDRequired})
+		return
+	}
+
+	_, err := c.lobbyService.DeleteLobby(code, playerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgDeleteLobby
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForDelete):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanDelete
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": msgLobbyDeleted})
+}
+
 // Start handles POST /api/v1/lobbies/:code/start
 func (c *LobbyController) Start(ctx *gin.Context) {
 	code := ctx.Param("code")
@@ -225,6 +778,12 @@ func (c *LobbyController) Start(ctx *gin.Context) {
 		case errors.Is(err, game.ErrNotEnoughPlayers):
 			status = http.StatusConflict
 			message = errMsgNotEnoughPlayers
+		case errors.Is(err, game.ErrTooManyPlayers):
+			status = http.StatusConflict
+			message = errMsgTooManyPlayers
+		case errors.Is(err, game.ErrTeamsNotSubmitted):
+			status = http.StatusConflict
+			message = errMsgTeamsNotSubmitted
 		}
 
 		ctx.JSON(status, gin.H{"error": message})
@@ -240,3 +799,405 @@ func (c *LobbyController) Start(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
 }
+
+// SubmitTeam handles POST /api/v1/lobbies/:code/team
+func (c *LobbyController) SubmitTeam(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SubmitTeamRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SubmitTeam(code, req.PlayerID, toTeamMembers(req.Team))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSubmitTeam
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, game.ErrPlayerNotFound):
+			status = http.StatusNotFound
+			message = errMsgPlayerNotInLobby
+		case errors.Is(err, game.ErrInvalidStateForTeam):
+			status = http.StatusConflict
+			message = errMsgTeamInvalidState
+		case errors.Is(err, game.ErrDraftInProgress):
+			status = http.StatusConflict
+			message = errMsgDraftInProgress
+		case errors.Is(err, game.ErrInvalidTeam):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidTeam, "violations": toViolationResponses(err)})
+			return
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// SetReady handles POST /api/v1/lobbies/:code/ready
+func (c *LobbyController) SetReady(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SetReadyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SetReady(code, req.PlayerID, *req.Ready)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSetReady
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, game.ErrPlayerNotFound):
+			status = http.StatusNotFound
+			message = errMsgPlayerNotInLobby
+		case errors.Is(err, game.ErrInvalidStateForReady):
+			status = http.StatusConflict
+			message = errMsgReadyInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// toTeamMembers converts a team request to domain team members
+func toTeamMembers(team []TeamMemberRequest) []game.TeamMember {
+	members := make([]game.TeamMember, len(team))
+	for i, m := range team {
+		members[i] = game.TeamMember{
+			SpeciesID: m.SpeciesID,
+			Level:     m.Level,
+			Item:      m.Item,
+			Moves:     m.Moves,
+			Nickname:  m.Nickname,
+			Shiny:     m.Shiny,
+		}
+	}
+	return members
+}
+
+// SetRuleset handles POST /api/v1/lobbies/:code/ruleset
+func (c *LobbyController) SetRuleset(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SetRulesetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SetRuleset(code, req.PlayerID, req.Ruleset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSetRuleset
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForRuleset):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanSetRules
+		case errors.Is(err, game.ErrUnknownRuleset):
+			status = http.StatusBadRequest
+			message = errMsgUnknownRuleset
+		case errors.Is(err, game.ErrInvalidStateForRuleset):
+			status = http.StatusConflict
+			message = errMsgRulesetInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// SetSeries handles POST /api/v1/lobbies/:code/series
+func (c *LobbyController) SetSeries(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SetSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SetSeriesLength(code, req.PlayerID, req.BestOf)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSetSeries
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForSeries):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanSetSeries
+		case errors.Is(err, game.ErrInvalidSeriesLength):
+			status = http.StatusBadRequest
+			message = errMsgInvalidSeriesLength
+		case errors.Is(err, game.ErrInvalidStateForSeries):
+			status = http.StatusConflict
+			message = errMsgSeriesInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// SetRematchTeams handles POST /api/v1/lobbies/:code/rematch-teams
+func (c *LobbyController) SetRematchTeams(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SetRematchTeamsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SetRematchTeams(code, req.PlayerID, game.RematchTeams(req.Teams))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSetRematchTeams
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForRematch):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanRematch
+		case errors.Is(err, game.ErrUnknownRematchTeams):
+			status = http.StatusBadRequest
+			message = errMsgUnknownRematchTeams
+		case errors.Is(err, game.ErrInvalidStateForRematchTeams):
+			status = http.StatusConflict
+			message = errMsgRematchInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// UpdateSettings handles PATCH /api/v1/lobbies/:code/settings
+func (c *LobbyController) UpdateSettings(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := services.LobbySettingsUpdate{RulesetID: req.Ruleset}
+	update.BestOf = req.BestOf
+	if req.TurnTimerSec != nil {
+		turnTimer := time.Duration(*req.TurnTimerSec) * time.Second
+		update.TurnTimer = &turnTimer
+	}
+	if req.CountdownSec != nil {
+		countdown := time.Duration(*req.CountdownSec) * time.Second
+		update.Countdown = &countdown
+	}
+	if req.StartMode != nil {
+		startMode := game.StartMode(*req.StartMode)
+		update.StartMode = &startMode
+	}
+	if req.Visibility != nil {
+		visibility := game.LobbyVisibility(*req.Visibility)
+		update.Visibility = &visibility
+	}
+	update.MaxSpectators = req.MaxSpectators
+	if req.Tags != nil {
+		tags := make([]game.LobbyTag, len(*req.Tags))
+		for i, tag := range *req.Tags {
+			tags[i] = game.LobbyTag(tag)
+		}
+		update.Tags = &tags
+	}
+
+	lobby, err := c.lobbyService.UpdateSettings(code, req.PlayerID, update)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgUpdateSettings
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForSettings):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanSettings
+		case errors.Is(err, game.ErrUnknownRuleset):
+			status = http.StatusBadRequest
+			message = errMsgUnknownRuleset
+		case errors.Is(err, game.ErrInvalidSeriesLength):
+			status = http.StatusBadRequest
+			message = errMsgInvalidSeriesLength
+		case errors.Is(err, game.ErrInvalidTurnTimer):
+			status = http.StatusBadRequest
+			message = errMsgInvalidTurnTimer
+		case errors.Is(err, game.ErrInvalidCountdown):
+			status = http.StatusBadRequest
+			message = errMsgInvalidCountdown
+		case errors.Is(err, game.ErrUnknownStartMode):
+			status = http.StatusBadRequest
+			message = errMsgUnknownStartMode
+		case errors.Is(err, game.ErrUnknownVisibility):
+			status = http.StatusBadRequest
+			message = errMsgUnknownVisibility
+		case errors.Is(err, game.ErrInvalidMaxSpectators):
+			status = http.StatusBadRequest
+			message = errMsgInvalidSpectators
+		case errors.Is(err, game.ErrUnknownLobbyTag):
+			status = http.StatusBadRequest
+			message = errMsgUnknownLobbyTag
+		case errors.Is(err, game.ErrTooManyLobbyTags):
+			status = http.StatusBadRequest
+			message = errMsgTooManyLobbyTags
+		case errors.Is(err, game.ErrInvalidStateForSettings):
+			status = http.StatusConflict
+			message = errMsgSettingsInvalidState
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// SetDraft handles POST /api/v1/lobbies/:code/draft
+func (c *LobbyController) SetDraft(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req SetDraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.SetDraftMode(code, req.PlayerID, *req.Enabled)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgSetDraft
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForDraft):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanSetDraft
+		case errors.Is(err, game.ErrInvalidStateForDraft):
+			status = http.StatusConflict
+			message = errMsgDraftInvalidState
+		case errors.Is(err, game.ErrBotInDraftLobby):
+			status = http.StatusConflict
+			message = errMsgBotInDraftLobby
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// AddBot handles POST /api/v1/lobbies/:code/add-bot
+func (c *LobbyController) AddBot(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var req AddBotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lobby, err := c.lobbyService.AddBot(code, req.PlayerID, game.BotDifficulty(req.Difficulty))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := errMsgAddBot
+
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			status = http.StatusNotFound
+			message = errMsgLobbyNotFound
+		case errors.Is(err, services.ErrNotHostForBot):
+			status = http.StatusForbidden
+			message = errMsgOnlyHostCanAddBot
+		case errors.Is(err, game.ErrUnknownBotDifficulty):
+			status = http.StatusBadRequest
+			message = errMsgUnknownBotDifficulty
+		case errors.Is(err, game.ErrLobbyFull):
+			status = http.StatusConflict
+			message = errMsgLobbyFull
+		case errors.Is(err, game.ErrInvalidStateForJoin):
+			status = http.StatusConflict
+			message = errMsgLobbyInvalidState
+		case errors.Is(err, game.ErrBotInDraftLobby):
+			status = http.StatusConflict
+			message = errMsgBotInDraftLobby
+		case errors.Is(err, game.ErrInvalidTeam):
+			status = http.StatusConflict
+			message = errMsgBotTeamNotLegal
+		}
+
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toLobbyResponse(lobby))
+}
+
+// ExportTeam handles GET /api/v1/lobbies/:code/team/export?player_id=...
+// It returns a player's submitted team in Showdown text format.
+func (c *LobbyController) ExportTeam(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	playerID := ctx.Query("player_id")
+	if playerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgPlayerIDRequired})
+		return
+	}
+
+	lobby, err := c.lobbyService.GetLobby(code)
+	if err != nil {
+		if errors.Is(err, services.ErrLobbyNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": errMsgLobbyNotFound})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetLobby})
+		return
+	}
+
+	team, ok := lobby.GetTeam(playerID)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": errMsgTeamNotFound})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ShowdownTeamResponse{Text: showdown.Format(team)})
+}
@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
 	"poke-battles/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +22,56 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
-func setupTestRouter() (*gin.Engine, *LobbyController) {
-	svc := services.NewLobbyService()
+// recordingSubscriber records the codes of lobbies whose settings or ready changes were published or that were deleted,
+// the IDs of players promoted off a waitlist, and the other events as "event:id"
+type recordingSubscriber struct {
+	settingsChanged []string
+	readyChanged    []string
+	closed          []string
+	promoted        []string
+	events          []string
+}
+
+func (r *recordingSubscriber) record(event events.Event) {
+	switch e := event.(type) {
+	case events.PlayerJoined:
+		r.events = append(r.events, "player_joined:"+e.PlayerID)
+	case events.PlayerLeft:
+		r.events = append(r.events, "player_left:"+e.PlayerID)
+	case events.SpectatorJoined:
+		r.events = append(r.events, "spectator_joined:"+e.SpectatorID)
+	case events.SpectatorLeft:
+		r.events = append(r.events, "spectator_left:"+e.SpectatorID)
+	case events.WaitlistJoined:
+		r.events = append(r.events, "waitlist_joined:"+e.PlayerID)
+	case events.WaitlistLeft:
+		r.events = append(r.events, "waitlist_left:"+e.PlayerID)
+	case events.TeamSubmitted:
+		r.events = append(r.events, "team_submitted:"+e.PlayerID)
+	case events.GameStarting:
+		r.events = append(r.events, "game_starting:"+e.Lobby.Code)
+	case events.SettingsChanged:
+		r.settingsChanged = append(r.settingsChanged, e.Lobby.Code)
+	case events.ReadyChanged:
+		r.readyChanged = append(r.readyChanged, e.Lobby.Code)
+	case events.LobbyDeleted:
+		r.closed = append(r.closed, e.Lobby.Code)
+	case events.WaitlistPromoted:
+		for _, p := range e.Promoted {
+			r.promoted = append(r.promoted, p.ID)
+		}
+	}
+}
+
+// setupTestRouter routes the lobby endpoints to a fresh lobby service, returning what the service publishes
+func setupTestRouter() (*gin.Engine, *recordingSubscriber) {
+	bus := events.NewBus()
+	recorded := &recordingSubscriber{}
+	bus.Subscribe(recorded.record)
+	svc := services.NewLobbyServiceWithConfig(services.LobbyServiceConfig{
+		MaxLobbiesPerPlayer: services.DefaultMaxLobbiesPerPlayer,
+		Events:              bus,
+	})
 	ctrl := NewLobbyController(svc)
 
 	router := gin.New()
@@ -26,13 +79,44 @@ func setupTestRouter() (*gin.Engine, *LobbyController) {
 	{
 		api.POST("/lobbies", ctrl.Create)
 		api.GET("/lobbies", ctrl.List)
+		api.POST("/lobbies/quick-join", ctrl.QuickJoin)
+		api.GET("/lobbies/tags", ctrl.ListTags)
 		api.GET("/lobbies/:code", ctrl.Get)
+		api.DELETE("/lobbies/:code", ctrl.Delete)
 		api.POST("/lobbies/:code/join", ctrl.Join)
+		api.POST("/lobbies/:code/spectate", ctrl.Spectate)
+		api.POST("/lobbies/:code/waitlist", ctrl.JoinWaitlist)
+		api.POST("/lobbies/:code/invites", ctrl.CreateInvite)
 		api.POST("/lobbies/:code/leave", ctrl.Leave)
 		api.POST("/lobbies/:code/start", ctrl.Start)
+		api.POST("/lobbies/:code/team", ctrl.SubmitTeam)
+		api.GET("/lobbies/:code/team/export", ctrl.ExportTeam)
+		api.POST("/lobbies/:code/ready", ctrl.SetReady)
+		api.POST("/lobbies/:code/ruleset", ctrl.SetRuleset)
+		api.POST("/lobbies/:code/series", ctrl.SetSeries)
+		api.POST("/lobbies/:code/rematch-teams", ctrl.SetRematchTeams)
+		api.PATCH("/lobbies/:code/settings", ctrl.UpdateSettings)
+		api.POST("/lobbies/:code/draft", ctrl.SetDraft)
+		api.POST("/lobbies/:code/add-bot", ctrl.AddBot)
+	}
+
+	return router, recorded
+}
+
+// submitStarterTeam submits the starter team for a player and returns the response
+func submitStarterTeam(router *gin.Engine, code, playerID string) *httptest.ResponseRecorder {
+	starter := game.StarterTeam()
+	team := make([]TeamMemberRequest, len(starter))
+	for i, m := range starter {
+		team[i] = TeamMemberRequest{SpeciesID: m.SpeciesID, Level: m.Level, Moves: m.Moves}
 	}
+	body, _ := json.Marshal(SubmitTeamRequest{PlayerID: playerID, Team: team})
 
-	return router, ctrl
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+code+"/team", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
 }
 
 // ========================================
@@ -77,6 +161,150 @@ func TestCreate_Success(t *testing.T) {
 	}
 }
 
+func TestCreate_MaxPlayers(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1", "username": "HostPlayer", "max_players": 4}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var resp LobbyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.MaxPlayers != 4 {
+		t.Errorf("expected max_players 4, got %d", resp.MaxPlayers)
+	}
+}
+
+func TestCreate_DefaultMaxPlayers(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1", "username": "HostPlayer"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var resp LobbyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.MaxPlayers != 2 {
+		t.Errorf("expected default max_players 2, got %d", resp.MaxPlayers)
+	}
+	if resp.Visibility != "public" {
+		t.Errorf("expected default visibility 'public', got %q", resp.Visibility)
+	}
+}
+
+func TestCreate_InvalidMaxPlayers(t *testing.T) {
+	for _, maxPlayers := range []int{1, 9} {
+		router, _ := setupTestRouter()
+
+		body, _ := json.Marshal(CreateLobbyRequest{PlayerID: "host-1", Username: "HostPlayer", MaxPlayers: maxPlayers})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("max_players %d: expected status %d, got %d", maxPlayers, http.StatusBadRequest, w.Code)
+		}
+		var resp map[string]string
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if resp["error"] != errMsgInvalidMaxPlayers {
+			t.Errorf("max_players %d: expected error %q, got %q", maxPlayers, errMsgInvalidMaxPlayers, resp["error"])
+		}
+	}
+}
+
+func TestCreate_UnknownVisibility(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1", "username": "HostPlayer", "visibility": "private"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["error"] != errMsgUnknownVisibility {
+		t.Errorf("expected error %q, got %q", errMsgUnknownVisibility, resp["error"])
+	}
+}
+
+func TestCreate_CustomCode(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1", "username": "HostPlayer", "code": "ashketchum"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Code != "ASHKETCHUM" {
+		t.Errorf("expected code ASHKETCHUM, got %q", resp.Code)
+	}
+
+	// A second host can't take the same code
+	body = `{"player_id": "host-2", "username": "OtherHost", "code": "ASHKETCHUM"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var errResp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &errResp)
+	if errResp["error"] != errMsgRoomCodeTaken {
+		t.Errorf("expected error %q, got %q", errMsgRoomCodeTaken, errResp["error"])
+	}
+}
+
+func TestCreate_InvalidCustomCode(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1", "username": "HostPlayer", "code": "POKEMON"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["error"] != errMsgInvalidRoomCode {
+		t.Errorf("expected error %q, got %q", errMsgInvalidRoomCode, resp["error"])
+	}
+}
+
 func TestCreate_MissingPlayerID(t *testing.T) {
 	router, _ := setupTestRouter()
 
@@ -205,14 +433,14 @@ func TestList_Success(t *testing.T) {
 
 	var resp LobbyListResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
-		t.Fatalf("failed to parse response as array: %v", err)
+		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if len(resp) != 1 {
-		t.Errorf("expected 1 lobby, got %d", len(resp))
+	if len(resp.Lobbies) != 1 {
+		t.Errorf("expected 1 lobby, got %d", len(resp.Lobbies))
 	}
 
-	lobby := resp[0]
+	lobby := resp.Lobbies[0]
 	if lobby.Code != createResp.Code {
 		t.Errorf("expected code %q, got %q", createResp.Code, lobby.Code)
 	}
@@ -245,11 +473,14 @@ func TestList_NoLobbies(t *testing.T) {
 
 	var resp LobbyListResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
-		t.Fatalf("failed to parse response as array: %v", err)
+		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if len(resp) != 0 {
-		t.Errorf("expected empty array, got %d lobbies", len(resp))
+	if resp.Lobbies == nil || len(resp.Lobbies) != 0 {
+		t.Errorf("expected empty array, got %d lobbies", len(resp.Lobbies))
+	}
+	if resp.NextCursor != "" {
+		t.Errorf("expected no next cursor, got %q", resp.NextCursor)
 	}
 }
 
@@ -282,16 +513,16 @@ func TestList_MultipleLobbies(t *testing.T) {
 
 	var resp LobbyListResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
-		t.Fatalf("failed to parse response as array: %v", err)
+		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if len(resp) != 3 {
-		t.Errorf("expected 3 lobbies, got %d", len(resp))
+	if len(resp.Lobbies) != 3 {
+		t.Errorf("expected 3 lobbies, got %d", len(resp.Lobbies))
 	}
 
 	// Verify all created lobbies are in the response
 	returnedCodes := make(map[string]bool)
-	for _, lobby := range resp {
+	for _, lobby := range resp.Lobbies {
 		returnedCodes[lobby.Code] = true
 
 		// Verify response structure
@@ -316,121 +547,465 @@ func TestList_MultipleLobbies(t *testing.T) {
 	}
 }
 
-// ========================================
-// Join Lobby Tests
-// ========================================
-
-func TestJoin_Success(t *testing.T) {
+func TestList_ExcludesUnlisted(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create a lobby first
-	createBody := `{"player_id": "host-1", "username": "Host"}`
-	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
-	createReq.Header.Set("Content-Type", "application/json")
-	createW := httptest.NewRecorder()
-	router.ServeHTTP(createW, createReq)
+	codes := make(map[string]string)
+	for _, visibility := range []string{"public", "unlisted"} {
+		createBody := fmt.Sprintf(`{"player_id": "host-%s", "username": "Host", "visibility": %q}`, visibility, visibility)
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
 
-	var createResp LobbyResponse
-	json.Unmarshal(createW.Body.Bytes(), &createResp)
+		var createResp LobbyResponse
+		json.Unmarshal(createW.Body.Bytes(), &createResp)
+		if createResp.Visibility != visibility {
+			t.Errorf("expected visibility %q, got %q", visibility, createResp.Visibility)
+		}
+		codes[visibility] = createResp.Code
+	}
 
-	// Join the lobby
-	joinBody := `{"player_id": "player-2", "username": "Player2"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies", nil)
 	w := httptest.NewRecorder()
-
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	var resp LobbyListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
 	}
-
-	var resp LobbyResponse
-	json.Unmarshal(w.Body.Bytes(), &resp)
-
-	if len(resp.Players) != 2 {
-		t.Errorf("expected 2 players, got %d", len(resp.Players))
+	if len(resp.Lobbies) != 1 || resp.Lobbies[0].Code != codes["public"] {
+		t.Errorf("expected only the public lobby to be listed, got %d lobbies", len(resp.Lobbies))
 	}
-	if resp.State != "ready" {
-		t.Errorf("expected state 'ready', got %q", resp.State)
+
+	// The unlisted lobby is still reachable by code
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+codes["unlisted"], nil)
+	getW := httptest.NewRecorder()
+	router.ServeHTTP(getW, getReq)
+	if getW.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, getW.Code)
 	}
 }
 
-func TestJoin_MissingPlayerID(t *testing.T) {
+func TestList_Filters(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	body := `{"username": "Player"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/join", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	codes := make(map[string]string)
+	for _, hostID := range []string{"host-1", "host-2"} {
+		createBody := fmt.Sprintf(`{"player_id": %q, "username": "Host"}`, hostID)
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
 
-	router.ServeHTTP(w, req)
+		var createResp LobbyResponse
+		json.Unmarshal(createW.Body.Bytes(), &createResp)
+		codes[hostID] = createResp.Code
+	}
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	// Fill host-2's lobby so it moves to ready
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+codes["host-2"]+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"state", "?state=ready", []string{codes["host-2"]}},
+		{"open slot", "?has_open_slot=true", []string{codes["host-1"]}},
+		{"no open slot", "?has_open_slot=false", []string{codes["host-2"]}},
+		{"best of", "?best_of=3", nil},
+		{"combined", "?state=waiting&best_of=1", []string{codes["host-1"]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var resp LobbyListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Lobbies) != len(tt.expected) {
+				t.Fatalf("expected %d lobbies, got %d", len(tt.expected), len(resp.Lobbies))
+			}
+			for i, code := range tt.expected {
+				if resp.Lobbies[i].Code != code {
+					t.Errorf("expected lobby %q at position %d, got %q", code, i, resp.Lobbies[i].Code)
+				}
+			}
+		})
 	}
 }
 
-func TestJoin_LobbyNotFound(t *testing.T) {
+func TestList_Pagination(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	body := `{"player_id": "player-1", "username": "Player"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/join", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
+	created := make(map[string]bool)
+	for _, hostID := range []string{"host-1", "host-2", "host-3"} {
+		createBody := fmt.Sprintf(`{"player_id": %q, "username": "Host"}`, hostID)
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		var createResp LobbyResponse
+		json.Unmarshal(createW.Body.Bytes(), &createResp)
+		created[createResp.Code] = true
 	}
 
-	var resp map[string]string
-	json.Unmarshal(w.Body.Bytes(), &resp)
+	// Walk the list two at a time until the cursor runs out
+	seen := make(map[string]bool)
+	var pageSizes []int
+	cursor := ""
+	for {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies?limit=2&cursor="+cursor, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
 
-	if resp["error"] != errMsgLobbyNotFound {
-		t.Errorf("expected error %q, got %q", errMsgLobbyNotFound, resp["error"])
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+
+		var resp LobbyListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		pageSizes = append(pageSizes, len(resp.Lobbies))
+		for _, lobby := range resp.Lobbies {
+			if seen[lobby.Code] {
+				t.Errorf("lobby %q returned on more than one page", lobby.Code)
+			}
+			seen[lobby.Code] = true
+		}
+
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	if len(pageSizes) != 2 || pageSizes[0] != 2 || pageSizes[1] != 1 {
+		t.Errorf("expected pages of 2 and 1 lobbies, got %v", pageSizes)
+	}
+	for code := range created {
+		if !seen[code] {
+			t.Errorf("expected lobby %q on some page", code)
+		}
 	}
 }
 
-func TestJoin_LobbyFull(t *testing.T) {
+func TestList_SortByLastActivity(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create and fill lobby
-	createBody := `{"player_id": "host-1", "username": "Host"}`
-	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
-	createReq.Header.Set("Content-Type", "application/json")
-	createW := httptest.NewRecorder()
-	router.ServeHTTP(createW, createReq)
+	var codes []string
+	for _, hostID := range []string{"host-1", "host-2"} {
+		createBody := fmt.Sprintf(`{"player_id": %q, "username": "Host"}`, hostID)
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
 
-	var createResp LobbyResponse
-	json.Unmarshal(createW.Body.Bytes(), &createResp)
+		var createResp LobbyResponse
+		json.Unmarshal(createW.Body.Bytes(), &createResp)
+		if createResp.CreatedAt.IsZero() || createResp.LastActivityAt.Before(createResp.CreatedAt) {
+			t.Errorf("expected created_at and a later last_activity_at, got %v and %v", createResp.CreatedAt, createResp.LastActivityAt)
+		}
+		codes = append(codes, createResp.Code)
+		time.Sleep(time.Millisecond)
+	}
 
+	// Activity in the older lobby moves it to the front of the most recently active list
 	joinBody := `{"player_id": "player-2", "username": "Player2"}`
-	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+codes[0]+"/join", bytes.NewBufferString(joinBody))
 	joinReq.Header.Set("Content-Type", "application/json")
-	joinW := httptest.NewRecorder()
-	router.ServeHTTP(joinW, joinReq)
+	router.ServeHTTP(httptest.NewRecorder(), joinReq)
 
-	// Try to join full lobby - state is Ready, so we get "cannot join in current state"
-	body := `{"player_id": "player-3", "username": "Player3"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies?sort=-last_activity_at", nil)
 	w := httptest.NewRecorder()
-
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusConflict {
-		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var resp map[string]string
+	var resp LobbyListResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
-
-	// When lobby has 2 players, state becomes Ready. The state check happens first,
-	// so we get "cannot join in current state" instead of "lobby is full"
-	if resp["error"] != errMsgLobbyInvalidState {
-		t.Errorf("expected error %q, got %q", errMsgLobbyInvalidState, resp["error"])
+	if len(resp.Lobbies) != 2 || resp.Lobbies[0].Code != codes[0] || resp.Lobbies[1].Code != codes[1] {
+		t.Fatalf("expected lobbies in order %v, got %+v", codes, resp.Lobbies)
+	}
+	if !resp.Lobbies[0].LastActivityAt.After(resp.Lobbies[0].CreatedAt) {
+		t.Error("expected joining to move last_activity_at past created_at")
+	}
+}
+
+func TestListTags(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/tags", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp map[string][]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp["tags"]) != len(game.ListLobbyTags()) {
+		t.Errorf("expected %d tags, got %v", len(game.ListLobbyTags()), resp["tags"])
+	}
+}
+
+func TestList_InvalidQuery(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{"unknown state", "?state=paused", errMsgInvalidStateFilter},
+		{"bad open slot", "?has_open_slot=maybe", errMsgInvalidOpenSlot},
+		{"bad best of", "?best_of=2", errMsgInvalidSeriesLength},
+		{"non-numeric best of", "?best_of=three", errMsgInvalidSeriesLength},
+		{"unknown sort", "?sort=players", errMsgInvalidLobbySort},
+		{"double minus sort", "?sort=--created_at", errMsgInvalidLobbySort},
+		{"unknown tag", "?tag=eu&tag=moon", errMsgUnknownLobbyTag},
+		{"zero limit", "?limit=0", errMsgInvalidPageLimit},
+		{"limit too large", "?limit=101", errMsgInvalidPageLimit},
+		{"garbled cursor", "?cursor=not-a-cursor", errMsgInvalidCursor},
+		{"cursor from another sort", "?cursor=" + encodeCursor(pageCursor{Sort: "-created_at", After: pageKey{At: 1, Code: "ABC123"}}), errMsgInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+			}
+		})
+	}
+}
+
+// ========================================
+// Delete Lobby Tests
+// ========================================
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"host", "?player_id=host-1", http.StatusOK, ""},
+		{"not host", "?player_id=player-2", http.StatusForbidden, errMsgOnlyHostCanDelete},
+		{"missing player", "", http.StatusBadRequest, errMsgPlayerIDRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, recorded := setupTestRouter()
+
+			createBody := `{"player_id": "host-1", "username": "Host"}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
+
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			joinBody := `{"player_id": "player-2", "username": "Player2"}`
+			joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+			joinReq.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/lobbies/"+createResp.Code+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			getReq := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+createResp.Code, nil)
+			getW := httptest.NewRecorder()
+			router.ServeHTTP(getW, getReq)
+
+			if tt.expectedError != "" {
+				var resp map[string]string
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+				}
+				if getW.Code != http.StatusOK {
+					t.Errorf("expected the lobby to remain, got status %d", getW.Code)
+				}
+				if len(recorded.closed) != 0 {
+					t.Error("expected clients not to be told the lobby closed")
+				}
+				return
+			}
+
+			if getW.Code != http.StatusNotFound {
+				t.Errorf("expected the deleted lobby to be gone, got status %d", getW.Code)
+			}
+			if len(recorded.closed) != 1 || recorded.closed[0] != createResp.Code {
+				t.Errorf("expected clients of %q to be told the lobby closed, got %v", createResp.Code, recorded.closed)
+			}
+		})
+	}
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lobbies/NOPE00?player_id=host-1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// ========================================
+// Join Lobby Tests
+// ========================================
+
+func TestJoin_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create a lobby first
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	// Join the lobby
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if len(resp.Players) != 2 {
+		t.Errorf("expected 2 players, got %d", len(resp.Players))
+	}
+	if resp.State != "ready" {
+		t.Errorf("expected state 'ready', got %q", resp.State)
+	}
+}
+
+func TestJoin_MissingPlayerID(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"username": "Player"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/join", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestJoin_LobbyNotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "player-1", "username": "Player"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/join", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgLobbyNotFound {
+		t.Errorf("expected error %q, got %q", errMsgLobbyNotFound, resp["error"])
+	}
+}
+
+func TestJoin_LobbyFull(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create and fill lobby
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	joinW := httptest.NewRecorder()
+	router.ServeHTTP(joinW, joinReq)
+
+	// Try to join full lobby - state is Ready, so we get "cannot join in current state"
+	body := `{"player_id": "player-3", "username": "Player3"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	// When lobby has 2 players, state becomes Ready. The state check happens first,
+	// so we get "cannot join in current state" instead of "lobby is full"
+	if resp["error"] != errMsgLobbyInvalidState {
+		t.Errorf("expected error %q, got %q", errMsgLobbyInvalidState, resp["error"])
 	}
 }
 
@@ -447,34 +1022,1155 @@ func TestJoin_AlreadyJoined(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	// Try to join as host again
-	body := `{"player_id": "host-1", "username": "Host"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(body))
+	// Try to join as host again
+	body := `{"player_id": "host-1", "username": "Host"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgPlayerAlreadyInLobby {
+		t.Errorf("expected error %q, got %q", errMsgPlayerAlreadyInLobby, resp["error"])
+	}
+}
+
+func TestJoin_AlreadyInAnotherLobby(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	var codes []string
+	for _, hostID := range []string{"host-1", "host-2"} {
+		createBody := `{"player_id": "` + hostID + `", "username": "Host"}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
+
+		var createResp LobbyResponse
+		json.Unmarshal(createW.Body.Bytes(), &createResp)
+		codes = append(codes, createResp.Code)
+	}
+
+	// host-1 tries to join host-2's lobby while hosting their own
+	body := `{"player_id": "host-1", "username": "Host"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+codes[1]+"/join", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgTooManyLobbies {
+		t.Errorf("expected error %q, got %q", errMsgTooManyLobbies, resp["error"])
+	}
+	if resp["lobby_code"] != codes[0] {
+		t.Errorf("expected lobby_code %q, got %q", codes[0], resp["lobby_code"])
+	}
+
+	// Creating another lobby is rejected the same way
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	if createW.Code != http.StatusConflict {
+		t.Errorf("expected status %d creating a second lobby, got %d", http.StatusConflict, createW.Code)
+	}
+}
+
+func TestJoin_WithInvite(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host", "max_players": 4}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	inviteReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/invites", bytes.NewBufferString(`{"player_id": "host-1"}`))
+	inviteReq.Header.Set("Content-Type", "application/json")
+	inviteW := httptest.NewRecorder()
+	router.ServeHTTP(inviteW, inviteReq)
+
+	if inviteW.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, inviteW.Code)
+	}
+	var invite InviteResponse
+	json.Unmarshal(inviteW.Body.Bytes(), &invite)
+	if invite.Code != createResp.Code || invite.Token == "" || invite.ExpiresAt.IsZero() {
+		t.Fatalf("expected an invite to lobby %q, got %+v", createResp.Code, invite)
+	}
+
+	join := func(playerID, token string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"player_id": %q, "username": "Player", "invite": %q}`, playerID, token)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := join("player-2", invite.Token); w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	// The invite is single-use
+	w := join("player-3", invite.Token)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["error"] != errMsgInvalidInvite {
+		t.Errorf("expected error %q, got %q", errMsgInvalidInvite, resp["error"])
+	}
+}
+
+func TestCreateInvite_NotHost(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	tests := []struct {
+		name           string
+		code           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"not host", createResp.Code, `{"player_id": "player-2"}`, http.StatusForbidden, errMsgOnlyHostCanInvite},
+		{"lobby not found", "NOPE00", `{"player_id": "host-1"}`, http.StatusNotFound, errMsgLobbyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+tt.code+"/invites", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+			}
+		})
+	}
+}
+
+func TestSpectate(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+	if createResp.Spectators == nil || len(createResp.Spectators) != 0 {
+		t.Errorf("expected an empty spectator roster, got %v", createResp.Spectators)
+	}
+	if createResp.Settings.MaxSpectators != game.DefaultMaxSpectators {
+		t.Errorf("expected max_spectators %d, got %d", game.DefaultMaxSpectators, createResp.Settings.MaxSpectators)
+	}
+
+	spectate := func(playerID string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"player_id": %q, "username": "Watcher"}`, playerID)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/spectate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := spectate("watcher-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Spectators) != 1 || resp.Spectators[0].ID != "watcher-1" {
+		t.Errorf("expected watcher-1 on the spectator roster, got %+v", resp.Spectators)
+	}
+	if len(resp.Players) != 1 {
+		t.Errorf("expected 1 player, got %d", len(resp.Players))
+	}
+
+	tests := []struct {
+		name          string
+		playerID      string
+		expectedError string
+	}{
+		{"already spectating", "watcher-1", errMsgAlreadySpectating},
+		{"player", "host-1", errMsgPlayerAlreadyInLobby},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := spectate(tt.playerID)
+			if w.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			var errResp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if errResp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, errResp["error"])
+			}
+		})
+	}
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	router, recorded := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+	if createResp.Waitlist == nil || len(createResp.Waitlist) != 0 {
+		t.Errorf("expected an empty waitlist, got %v", createResp.Waitlist)
+	}
+
+	post := func(path, playerID string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"player_id": %q, "username": "Player"}`, playerID)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("/waitlist", "waiter-1"); w.Code != http.StatusConflict {
+		t.Errorf("expected status %d joining the waitlist of a lobby with a free slot, got %d", http.StatusConflict, w.Code)
+	}
+
+	post("/join", "player-2")
+	post("/waitlist", "waiter-1")
+	w := post("/waitlist", "waiter-2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	expected := []WaitlistEntryResponse{
+		{ID: "waiter-1", Username: "Player", Position: 1},
+		{ID: "waiter-2", Username: "Player", Position: 2},
+	}
+	if !reflect.DeepEqual(resp.Waitlist, expected) {
+		t.Errorf("expected waitlist %+v, got %+v", expected, resp.Waitlist)
+	}
+
+	tests := []struct {
+		name          string
+		playerID      string
+		expectedError string
+	}{
+		{"already waitlisted", "waiter-1", errMsgAlreadyWaitlisted},
+		{"player", "player-2", errMsgPlayerAlreadyInLobby},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post("/waitlist", tt.playerID)
+			if w.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			var errResp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &errResp)
+			if errResp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, errResp["error"])
+			}
+		})
+	}
+
+	// A player leaving promotes the first in line and the clients are told
+	if w := post("/leave", "player-2"); w.Code != http.StatusOK {
+		t.Fatalf("expected status %d leaving, got %d", http.StatusOK, w.Code)
+	}
+	if !reflect.DeepEqual(recorded.promoted, []string{"waiter-1"}) {
+		t.Errorf("expected waiter-1's promotion to be broadcast, got %v", recorded.promoted)
+	}
+}
+
+// ========================================
+// Quick Join Tests
+// ========================================
+
+func TestQuickJoin(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	quickJoin := func(body string) (int, LobbyResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/quick-join", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp LobbyResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	// Nothing to join yet, so the first player gets a new lobby in the requested format
+	status, created := quickJoin(`{"player_id": "player-1", "username": "Player1", "best_of": 3}`)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, status)
+	}
+	if created.HostID != "player-1" {
+		t.Errorf("expected player-1 to host, got %q", created.HostID)
+	}
+	if created.Series.BestOf != 3 {
+		t.Errorf("expected best of 3, got %d", created.Series.BestOf)
+	}
+
+	// A different format doesn't match it
+	status, other := quickJoin(`{"player_id": "player-2", "username": "Player2", "best_of": 5}`)
+	if status != http.StatusCreated || other.Code == created.Code {
+		t.Errorf("expected a separate lobby with status %d, got %q with status %d", http.StatusCreated, other.Code, status)
+	}
+
+	// The same format joins it
+	status, joined := quickJoin(`{"player_id": "player-3", "username": "Player3", "best_of": 3}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, status)
+	}
+	if joined.Code != created.Code {
+		t.Errorf("expected to join lobby %q, got %q", created.Code, joined.Code)
+	}
+	if len(joined.Players) != 2 {
+		t.Errorf("expected 2 players, got %d", len(joined.Players))
+	}
+}
+
+func TestQuickJoin_BadRequest(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	tests := []struct {
+		name          string
+		body          string
+		expectedError string
+	}{
+		{"unknown ruleset", `{"player_id": "player-1", "username": "Player1", "ruleset": "anything-goes"}`, errMsgUnknownRuleset},
+		{"invalid series length", `{"player_id": "player-1", "username": "Player1", "best_of": 2}`, errMsgInvalidSeriesLength},
+		{"missing username", `{"player_id": "player-1"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/quick-join", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if tt.expectedError != "" && resp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+			}
+		})
+	}
+}
+
+// ========================================
+// Leave Lobby Tests
+// ========================================
+
+func TestMutationsNotifyClients(t *testing.T) {
+	router, recorded := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	post := func(path, playerID string) {
+		body := fmt.Sprintf(`{"player_id": %q, "username": "Player"}`, playerID)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("POST %s as %s: expected status %d, got %d", path, playerID, http.StatusOK, w.Code)
+		}
+	}
+
+	post("/join", "player-2")
+	post("/spectate", "watcher-1")
+	post("/waitlist", "waiter-1")
+	submitStarterTeam(router, createResp.Code, "host-1")
+	post("/leave", "watcher-1")
+	post("/leave", "waiter-1")
+	post("/leave", "player-2")
+	post("/join", "player-2")
+	submitStarterTeam(router, createResp.Code, "player-2")
+	post("/start", "host-1")
+
+	expected := []string{
+		"player_joined:player-2",
+		"spectator_joined:watcher-1",
+		"waitlist_joined:waiter-1",
+		"team_submitted:host-1",
+		"spectator_left:watcher-1",
+		"waitlist_left:waiter-1",
+		"player_left:player-2",
+		"player_joined:player-2",
+		"team_submitted:player-2",
+		"game_starting:" + createResp.Code,
+	}
+	if !reflect.DeepEqual(recorded.events, expected) {
+		t.Errorf("expected broadcasts %v, got %v", expected, recorded.events)
+	}
+}
+
+func TestLeave_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create and fill lobby
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	joinW := httptest.NewRecorder()
+	router.ServeHTTP(joinW, joinReq)
+
+	// Player leaves
+	leaveBody := `{"player_id": "player-2"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/leave", bytes.NewBufferString(leaveBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["message"] != msgLeftLobby {
+		t.Errorf("expected message %q, got %q", msgLeftLobby, resp["message"])
+	}
+}
+
+func TestLeave_LobbyNotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "player-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/leave", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestLeave_PlayerNotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create lobby
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	// Try to leave as non-existent player
+	body := `{"player_id": "nonexistent"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/leave", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgPlayerNotInLobby {
+		t.Errorf("expected error %q, got %q", errMsgPlayerNotInLobby, resp["error"])
+	}
+}
+
+func TestLeave_MissingPlayerID(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/leave", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// ========================================
+// Start Game Tests
+// ========================================
+
+func TestStart_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create and fill lobby
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	joinW := httptest.NewRecorder()
+	router.ServeHTTP(joinW, joinReq)
+
+	submitStarterTeam(router, createResp.Code, "host-1")
+	submitStarterTeam(router, createResp.Code, "player-2")
+
+	// Start game
+	startBody := `{"player_id": "host-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(startBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp.State != "active" {
+		t.Errorf("expected state 'active', got %q", resp.State)
+	}
+}
+
+func TestStart_LobbyNotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestStart_NotHost(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create and fill lobby
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	joinW := httptest.NewRecorder()
+	router.ServeHTTP(joinW, joinReq)
+
+	// Non-host tries to start
+	body := `{"player_id": "player-2"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgOnlyHostCanStart {
+		t.Errorf("expected error %q, got %q", errMsgOnlyHostCanStart, resp["error"])
+	}
+}
+
+func TestStart_NotReady(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	// Create lobby without second player
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	// Try to start with only 1 player
+	body := `{"player_id": "host-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgGameInvalidState {
+		t.Errorf("expected error %q, got %q", errMsgGameInvalidState, resp["error"])
+	}
+}
+
+func TestStart_TeamsNotSubmitted(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	joinW := httptest.NewRecorder()
+	router.ServeHTTP(joinW, joinReq)
+
+	// Only the host submits a team
+	submitStarterTeam(router, createResp.Code, "host-1")
+
+	body := `{"player_id": "host-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgTeamsNotSubmitted {
+		t.Errorf("expected error %q, got %q", errMsgTeamsNotSubmitted, resp["error"])
+	}
+}
+
+func TestStart_MissingPlayerID(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// ========================================
+// Submit Team Tests
+// ========================================
+
+func TestSubmitTeam_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	w := submitStarterTeam(router, createResp.Code, "host-1")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if len(resp.Players) != 1 || !resp.Players[0].TeamSubmitted {
+		t.Errorf("expected host to be marked as having submitted a team, got %+v", resp.Players)
+	}
+}
+
+func TestSubmitTeam_LobbyNotFound(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := submitStarterTeam(router, "NOTFND", "host-1")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSubmitTeam_PlayerNotInLobby(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	w := submitStarterTeam(router, createResp.Code, "stranger")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgPlayerNotInLobby {
+		t.Errorf("expected error %q, got %q", errMsgPlayerNotInLobby, resp["error"])
+	}
+}
+
+func TestSubmitTeam_IllegalTeam(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	// Pikachu cannot learn surf
+	body := `{"player_id": "host-1", "team": [{"species_id": "pikachu", "moves": ["surf"]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/team", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp struct {
+		Error      string              `json:"error"`
+		Violations []ViolationResponse `json:"violations"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp.Error != errMsgInvalidTeam {
+		t.Errorf("expected error %q, got %q", errMsgInvalidTeam, resp.Error)
+	}
+	if len(resp.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", resp.Violations)
+	}
+	if v := resp.Violations[0]; v.Slot == nil || *v.Slot != 0 || v.Rule != string(game.RuleMoves) {
+		t.Errorf("expected moves violation in slot 0, got %+v", v)
+	}
+}
+
+func TestSubmitTeam_MissingTeam(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	body := `{"player_id": "host-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/team", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSetReady(t *testing.T) {
+	router, recorded := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	body := `{"player_id": "host-1", "ready": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/ready", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if !resp.Players[0].IsReady {
+		t.Error("expected host to be ready")
+	}
+
+	if len(recorded.readyChanged) != 1 || recorded.readyChanged[0] != createResp.Code {
+		t.Errorf("expected ready change broadcast for %q, got %v", createResp.Code, recorded.readyChanged)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+createResp.Code, nil)
+	getW := httptest.NewRecorder()
+	router.ServeHTTP(getW, getReq)
+
+	var getResp LobbyResponse
+	json.Unmarshal(getW.Body.Bytes(), &getResp)
+
+	if !getResp.Players[0].IsReady {
+		t.Error("expected host to still be ready when the lobby is fetched")
+	}
+}
+
+func TestSetReady_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "lobby not found",
+			code:           "NOPE00",
+			body:           `{"player_id": "host-1", "ready": true}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  errMsgLobbyNotFound,
+		},
+		{
+			name:           "player not in lobby",
+			body:           `{"player_id": "player-2", "ready": true}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  errMsgPlayerNotInLobby,
+		},
+		{
+			name:           "missing ready",
+			body:           `{"player_id": "host-1"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, recorded := setupTestRouter()
+
+			createBody := `{"player_id": "host-1", "username": "Host"}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
+
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			code := tt.code
+			if code == "" {
+				code = createResp.Code
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+code+"/ready", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedError != "" {
+				var resp map[string]string
+				json.Unmarshal(w.Body.Bytes(), &resp)
+
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+				}
+			}
+
+			if len(recorded.readyChanged) != 0 {
+				t.Errorf("expected no ready change broadcast, got %v", recorded.readyChanged)
+			}
+		})
+	}
+}
+
+func TestExportTeam_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	submitStarterTeam(router, createResp.Code, "host-1")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+createResp.Code+"/team/export?player_id=host-1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp ShowdownTeamResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if !strings.HasPrefix(resp.Text, "Venusaur\nLevel: 50\n- Razor Leaf\n") {
+		t.Errorf("unexpected export:\n%s", resp.Text)
+	}
+}
+
+func TestExportTeam_NotSubmitted(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+createResp.Code+"/team/export?player_id=host-1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgTeamNotFound {
+		t.Errorf("expected error %q, got %q", errMsgTeamNotFound, resp["error"])
+	}
+}
+
+func TestExportTeam_MissingPlayerID(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/ABC123/team/export", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// ========================================
+// Set Ruleset Tests
+// ========================================
+
+func TestSetRuleset_Success(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	if createResp.Ruleset != game.DefaultRulesetID {
+		t.Errorf("expected new lobby to use ruleset %q, got %q", game.DefaultRulesetID, createResp.Ruleset)
+	}
+
+	body := `{"player_id": "host-1", "ruleset": "competitive"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/ruleset", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp LobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp.Ruleset != "competitive" {
+		t.Errorf("expected ruleset 'competitive', got %q", resp.Ruleset)
+	}
+}
+
+func TestSetRuleset_NotHost(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+	body := `{"player_id": "player-2", "ruleset": "competitive"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/ruleset", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgOnlyHostCanSetRules {
+		t.Errorf("expected error %q, got %q", errMsgOnlyHostCanSetRules, resp["error"])
+	}
+}
+
+func TestSetRuleset_UnknownRuleset(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	createBody := `{"player_id": "host-1", "username": "Host"}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+
+	var createResp LobbyResponse
+	json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+	body := `{"player_id": "host-1", "ruleset": "anything-goes"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/ruleset", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusConflict {
-		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
 
 	var resp map[string]string
 	json.Unmarshal(w.Body.Bytes(), &resp)
 
-	if resp["error"] != errMsgPlayerAlreadyInLobby {
-		t.Errorf("expected error %q, got %q", errMsgPlayerAlreadyInLobby, resp["error"])
+	if resp["error"] != errMsgUnknownRuleset {
+		t.Errorf("expected error %q, got %q", errMsgUnknownRuleset, resp["error"])
 	}
 }
 
-// ========================================
-// Leave Lobby Tests
-// ========================================
-
-func TestLeave_Success(t *testing.T) {
+func TestSetSeries_Success(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create and fill lobby
 	createBody := `{"player_id": "host-1", "username": "Host"}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
@@ -484,51 +2180,216 @@ func TestLeave_Success(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	joinBody := `{"player_id": "player-2", "username": "Player2"}`
-	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
-	joinReq.Header.Set("Content-Type", "application/json")
-	joinW := httptest.NewRecorder()
-	router.ServeHTTP(joinW, joinReq)
+	if createResp.Series.BestOf != game.DefaultSeriesLength {
+		t.Errorf("expected new lobby to be best of %d, got %d", game.DefaultSeriesLength, createResp.Series.BestOf)
+	}
 
-	// Player leaves
-	leaveBody := `{"player_id": "player-2"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/leave", bytes.NewBufferString(leaveBody))
+	body := `{"player_id": "host-1", "best_of": 5}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/series", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var resp map[string]string
+	var resp LobbyResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
 
-	if resp["message"] != msgLeftLobby {
-		t.Errorf("expected message %q, got %q", msgLeftLobby, resp["message"])
+	if resp.Series.BestOf != 5 || resp.Series.GamesPlayed != 0 {
+		t.Errorf("expected empty best of 5 series, got %+v", resp.Series)
 	}
 }
 
-func TestLeave_LobbyNotFound(t *testing.T) {
-	router, _ := setupTestRouter()
+func TestSetSeries_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"not host", `{"player_id": "player-2", "best_of": 3}`, http.StatusForbidden, errMsgOnlyHostCanSetSeries},
+		{"invalid length", `{"player_id": "host-1", "best_of": 4}`, http.StatusBadRequest, errMsgInvalidSeriesLength},
+	}
 
-	body := `{"player_id": "player-1"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/leave", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupTestRouter()
 
-	router.ServeHTTP(w, req)
+			createBody := `{"player_id": "host-1", "username": "Host"}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			joinBody := `{"player_id": "player-2", "username": "Player2"}`
+			joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+			joinReq.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/series", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			if resp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+			}
+		})
 	}
 }
 
-func TestLeave_PlayerNotFound(t *testing.T) {
+func TestSetRematchTeams(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+		expectedTeams  string
+	}{
+		{"new teams", `{"player_id": "host-1", "teams": "new"}`, http.StatusOK, "", "new"},
+		{"not host", `{"player_id": "player-2", "teams": "new"}`, http.StatusForbidden, errMsgOnlyHostCanRematch, ""},
+		{"unknown setting", `{"player_id": "host-1", "teams": "random"}`, http.StatusBadRequest, errMsgUnknownRematchTeams, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupTestRouter()
+
+			createBody := `{"player_id": "host-1", "username": "Host"}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
+
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			if createResp.RematchTeams != string(game.RematchTeamsSame) {
+				t.Errorf("expected new lobby to keep teams on rematch, got %q", createResp.RematchTeams)
+			}
+
+			joinBody := `{"player_id": "player-2", "username": "Player2"}`
+			joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+			joinReq.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/rematch-teams", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedError != "" {
+				var resp map[string]string
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+				}
+				return
+			}
+
+			var resp LobbyResponse
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp.RematchTeams != tt.expectedTeams {
+				t.Errorf("expected rematch teams %q, got %q", tt.expectedTeams, resp.RematchTeams)
+			}
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"all settings", `{"player_id": "host-1", "ruleset": "competitive", "best_of": 3, "turn_timer_sec": 60, "countdown_sec": 5, "start_mode": "host", "visibility": "unlisted", "max_spectators": 4, "tags": ["EU", "beginner", "eu"]}`, http.StatusOK, ""},
+		{"not host", `{"player_id": "player-2", "best_of": 3}`, http.StatusForbidden, errMsgOnlyHostCanSettings},
+		{"unknown ruleset", `{"player_id": "host-1", "ruleset": "nonexistent"}`, http.StatusBadRequest, errMsgUnknownRuleset},
+		{"invalid series length", `{"player_id": "host-1", "best_of": 2}`, http.StatusBadRequest, errMsgInvalidSeriesLength},
+		{"turn timer too long", `{"player_id": "host-1", "turn_timer_sec": 301}`, http.StatusBadRequest, errMsgInvalidTurnTimer},
+		{"negative countdown", `{"player_id": "host-1", "countdown_sec": -1}`, http.StatusBadRequest, errMsgInvalidCountdown},
+		{"unknown start mode", `{"player_id": "host-1", "start_mode": "manual"}`, http.StatusBadRequest, errMsgUnknownStartMode},
+		{"too many spectators", `{"player_id": "host-1", "max_spectators": 51}`, http.StatusBadRequest, errMsgInvalidSpectators},
+		{"unknown visibility", `{"player_id": "host-1", "visibility": "private"}`, http.StatusBadRequest, errMsgUnknownVisibility},
+		{"unknown tag", `{"player_id": "host-1", "tags": ["moon"]}`, http.StatusBadRequest, errMsgUnknownLobbyTag},
+		{"too many tags", `{"player_id": "host-1", "tags": ["na", "eu", "asia", "oce", "sa", "casual"]}`, http.StatusBadRequest, errMsgTooManyLobbyTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, recorded := setupTestRouter()
+
+			createBody := `{"player_id": "host-1", "username": "Host", "max_players": 3}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
+
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			if createResp.Settings.Ruleset != game.DefaultRulesetID || createResp.Settings.BestOf != 1 {
+				t.Errorf("expected default settings, got %+v", createResp.Settings)
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/lobbies/"+createResp.Code+"/settings", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedError != "" {
+				var resp map[string]string
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp["error"] != tt.expectedError {
+					t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+				}
+				if len(recorded.settingsChanged) != 0 {
+					t.Error("expected no settings_changed broadcast for a rejected update")
+				}
+				return
+			}
+
+			var resp LobbyResponse
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			want := SettingsResponse{Ruleset: "competitive", BestOf: 3, TurnTimerSec: 60, CountdownSec: 5, StartMode: "host", Visibility: "unlisted", MaxSpectators: 4, Tags: []string{"eu", "beginner"}}
+			if !reflect.DeepEqual(resp.Settings, want) {
+				t.Errorf("expected settings %+v, got %+v", want, resp.Settings)
+			}
+			if len(recorded.settingsChanged) != 1 || recorded.settingsChanged[0] != createResp.Code {
+				t.Errorf("expected one settings_changed broadcast for %q, got %v", createResp.Code, recorded.settingsChanged)
+			}
+		})
+	}
+}
+
+func TestUpdateSettings_OnlyWhileWaiting(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create lobby
 	createBody := `{"player_id": "host-1", "username": "Host"}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
@@ -538,49 +2399,32 @@ func TestLeave_PlayerNotFound(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	// Try to leave as non-existent player
-	body := `{"player_id": "nonexistent"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/leave", bytes.NewBufferString(body))
+	joinBody := `{"player_id": "player-2", "username": "Player2"}`
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+	joinReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+	body := `{"player_id": "host-1", "turn_timer_sec": 30}`
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/lobbies/"+createResp.Code+"/settings", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
 	}
 
 	var resp map[string]string
 	json.Unmarshal(w.Body.Bytes(), &resp)
-
-	if resp["error"] != errMsgPlayerNotInLobby {
-		t.Errorf("expected error %q, got %q", errMsgPlayerNotInLobby, resp["error"])
-	}
-}
-
-func TestLeave_MissingPlayerID(t *testing.T) {
-	router, _ := setupTestRouter()
-
-	body := `{}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/leave", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	if resp["error"] != errMsgSettingsInvalidState {
+		t.Errorf("expected error %q, got %q", errMsgSettingsInvalidState, resp["error"])
 	}
 }
 
-// ========================================
-// Start Game Tests
-// ========================================
-
-func TestStart_Success(t *testing.T) {
+func TestSetDraft_BlocksTeamsUntilDrafted(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create and fill lobby
 	createBody := `{"player_id": "host-1", "username": "Host"}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
@@ -590,51 +2434,41 @@ func TestStart_Success(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	joinBody := `{"player_id": "player-2", "username": "Player2"}`
-	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
-	joinReq.Header.Set("Content-Type", "application/json")
-	joinW := httptest.NewRecorder()
-	router.ServeHTTP(joinW, joinReq)
-
-	// Start game
-	startBody := `{"player_id": "host-1"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(startBody))
+	body := `{"player_id": "host-1", "enabled": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/draft", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
 	var resp LobbyResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
 
-	if resp.State != "active" {
-		t.Errorf("expected state 'active', got %q", resp.State)
+	if !resp.DraftMode {
+		t.Error("expected draft mode to be enabled")
 	}
-}
 
-func TestStart_LobbyNotFound(t *testing.T) {
-	router, _ := setupTestRouter()
+	teamW := submitStarterTeam(router, createResp.Code, "host-1")
 
-	body := `{"player_id": "host-1"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/NOTFND/start", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	if teamW.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, teamW.Code)
+	}
 
-	router.ServeHTTP(w, req)
+	var teamResp map[string]string
+	json.Unmarshal(teamW.Body.Bytes(), &teamResp)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	if teamResp["error"] != errMsgDraftInProgress {
+		t.Errorf("expected error %q, got %q", errMsgDraftInProgress, teamResp["error"])
 	}
 }
 
-func TestStart_NotHost(t *testing.T) {
+func TestSetDraft_NotHost(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create and fill lobby
 	createBody := `{"player_id": "host-1", "username": "Host"}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
@@ -644,15 +2478,8 @@ func TestStart_NotHost(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	joinBody := `{"player_id": "player-2", "username": "Player2"}`
-	joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
-	joinReq.Header.Set("Content-Type", "application/json")
-	joinW := httptest.NewRecorder()
-	router.ServeHTTP(joinW, joinReq)
-
-	// Non-host tries to start
-	body := `{"player_id": "player-2"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(body))
+	body := `{"player_id": "player-2", "enabled": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/draft", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
@@ -665,15 +2492,14 @@ func TestStart_NotHost(t *testing.T) {
 	var resp map[string]string
 	json.Unmarshal(w.Body.Bytes(), &resp)
 
-	if resp["error"] != errMsgOnlyHostCanStart {
-		t.Errorf("expected error %q, got %q", errMsgOnlyHostCanStart, resp["error"])
+	if resp["error"] != errMsgOnlyHostCanSetDraft {
+		t.Errorf("expected error %q, got %q", errMsgOnlyHostCanSetDraft, resp["error"])
 	}
 }
 
-func TestStart_NotReady(t *testing.T) {
+func TestAddBot_Success(t *testing.T) {
 	router, _ := setupTestRouter()
 
-	// Create lobby without second player
 	createBody := `{"player_id": "host-1", "username": "Host"}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
@@ -683,38 +2509,79 @@ func TestStart_NotReady(t *testing.T) {
 	var createResp LobbyResponse
 	json.Unmarshal(createW.Body.Bytes(), &createResp)
 
-	// Try to start with only 1 player
-	body := `{"player_id": "host-1"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/start", bytes.NewBufferString(body))
+	body := `{"player_id": "host-1", "difficulty": "lookahead"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/add-bot", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusConflict {
-		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var resp map[string]string
+	var resp LobbyResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
 
-	if resp["error"] != errMsgGameInvalidState {
-		t.Errorf("expected error %q, got %q", errMsgGameInvalidState, resp["error"])
+	if resp.State != "ready" || len(resp.Players) != 2 {
+		t.Fatalf("expected a full, ready lobby, got %s with %d players", resp.State, len(resp.Players))
+	}
+	bot := resp.Players[1]
+	if !bot.IsBot || !bot.TeamSubmitted || resp.Players[0].IsBot {
+		t.Errorf("expected the second player to be a bot with a team, got %+v", resp.Players)
+	}
+	if bot.BotDifficulty != "lookahead" {
+		t.Errorf("expected bot difficulty %q, got %q", "lookahead", bot.BotDifficulty)
 	}
 }
 
-func TestStart_MissingPlayerID(t *testing.T) {
-	router, _ := setupTestRouter()
+func TestAddBot_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"not host", `{"player_id": "player-2"}`, http.StatusForbidden, errMsgOnlyHostCanAddBot},
+		{"lobby full", `{"player_id": "host-1"}`, http.StatusConflict, errMsgLobbyInvalidState},
+		{"unknown difficulty", `{"player_id": "host-1", "difficulty": "impossible"}`, http.StatusBadRequest, errMsgUnknownBotDifficulty},
+	}
 
-	body := `{}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/ABC123/start", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupTestRouter()
 
-	router.ServeHTTP(w, req)
+			createBody := `{"player_id": "host-1", "username": "Host"}`
+			createReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies", bytes.NewBufferString(createBody))
+			createReq.Header.Set("Content-Type", "application/json")
+			createW := httptest.NewRecorder()
+			router.ServeHTTP(createW, createReq)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			var createResp LobbyResponse
+			json.Unmarshal(createW.Body.Bytes(), &createResp)
+
+			joinBody := `{"player_id": "player-2", "username": "Player2"}`
+			joinReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/join", bytes.NewBufferString(joinBody))
+			joinReq.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), joinReq)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+createResp.Code+"/add-bot", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp map[string]string
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			if resp["error"] != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, resp["error"])
+			}
+		})
 	}
 }
 
@@ -890,7 +2757,14 @@ func TestFullFlow_CreateJoinStartLeave(t *testing.T) {
 		t.Errorf("expected state 'ready', got %q", lobby.State)
 	}
 
-	// 3. Host starts game
+	// 3. Both players submit teams
+	for _, playerID := range []string{"host-1", "player-2"} {
+		if w := submitStarterTeam(router, code, playerID); w.Code != http.StatusOK {
+			t.Fatalf("submit team failed with status %d", w.Code)
+		}
+	}
+
+	// 4. Host starts game
 	startReq := httptest.NewRequest(http.MethodPost, "/api/v1/lobbies/"+code+"/start",
 		bytes.NewBufferString(`{"player_id": "host-1"}`))
 	startReq.Header.Set("Content-Type", "application/json")
@@ -906,7 +2780,7 @@ func TestFullFlow_CreateJoinStartLeave(t *testing.T) {
 		t.Errorf("expected state 'active', got %q", lobby.State)
 	}
 
-	// 4. Verify lobby is still accessible
+	// 5. Verify lobby is still accessible
 	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/lobbies/"+code, nil)
 	getW := httptest.NewRecorder()
 	router.ServeHTTP(getW, getReq)
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+
+	cursorPrefix = "after:"
+)
+
+var (
+	errInvalidPageLimit = errors.New("invalid page limit")
+	errInvalidCursor    = errors.New("invalid cursor")
+)
+
+// pageKey is where an item sits in a list ordered by a timestamp, with its code breaking ties
+type pageKey struct {
+	At   int64 // Unix nanoseconds
+	Code string
+}
+
+func newPageKey(at time.Time, code string) pageKey {
+	return pageKey{At: at.UnixNano(), Code: code}
+}
+
+// before returns true if k comes before other, oldest first
+func (k pageKey) before(other pageKey) bool {
+	if k.At != other.At {
+		return k.At < other.At
+	}
+	return k.Code < other.Code
+}
+
+// listOrder is the order a list is returned in
+type listOrder struct {
+	Sort        string // The sort query parameter; a cursor only continues the order it was issued for
+	NewestFirst bool
+}
+
+// comesAfter returns true if key is later in the list than the cursor's item
+func (o listOrder) comesAfter(key, cursor pageKey) bool {
+	if o.NewestFirst {
+		return key.before(cursor)
+	}
+	return cursor.before(key)
+}
+
+// pageCursor is the last item of the previous page
+type pageCursor struct {
+	Sort  string
+	After pageKey
+}
+
+// pageRequest is the limit and position a client asked for on a list endpoint
+type pageRequest struct {
+	Limit  int
+	Cursor *pageCursor // nil for the first page
+}
+
+// parsePageRequest reads the limit and cursor query parameters.
+// A missing limit defaults to DefaultPageLimit and a missing cursor starts at the first item.
+func parsePageRequest(ctx *gin.Context) (pageRequest, error) {
+	page := pageRequest{Limit: DefaultPageLimit}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return pageRequest{}, errInvalidPageLimit
+		}
+		page.Limit = limit
+	}
+
+	if value := ctx.Query("cursor"); value != "" {
+		cursor, err := decodeCursor(value)
+		if err != nil {
+			return pageRequest{}, err
+		}
+		page.Cursor = &cursor
+	}
+
+	return page, nil
+}
+
+// paginate returns the requested page of items, which must already be in order, and the cursor for the page after it.
+// The cursor is empty once the last item has been returned.
+// Cursors hold the key of the last item returned rather than its position,
+// so items added or removed between requests neither shift the next page nor repeat items on it.
+func paginate[T any](items []T, page pageRequest, order listOrder, keyOf func(T) pageKey) ([]T, string, error) {
+	start := 0
+	if page.Cursor != nil {
+		if page.Cursor.Sort != order.Sort {
+			return nil, "", errInvalidCursor
+		}
+		for start < len(items) && !order.comesAfter(keyOf(items[start]), page.Cursor.After) {
+			start++
+		}
+	}
+
+	end := start + page.Limit
+	if end >= len(items) {
+		return items[start:], "", nil
+	}
+	return items[start:end], encodeCursor(pageCursor{Sort: order.Sort, After: keyOf(items[end-1])}), nil
+}
+
+// encodeCursor keeps cursors opaque so the pagination scheme can change without breaking clients
+func encodeCursor(cursor pageCursor) string {
+	raw := cursorPrefix + cursor.Sort + ":" + strconv.FormatInt(cursor.After.At, 10) + ":" + cursor.After.Code
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(value string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return pageCursor{}, errInvalidCursor
+	}
+
+	rest, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return pageCursor{}, errInvalidCursor
+	}
+
+	parts := strings.SplitN(rest, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return pageCursor{}, errInvalidCursor
+	}
+
+	at, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return pageCursor{}, errInvalidCursor
+	}
+	return pageCursor{Sort: parts[0], After: pageKey{At: at, Code: parts[2]}}, nil
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// keyed is a list item whose key is its own value
+type keyed struct {
+	At   int64
+	Code string
+}
+
+func keyOfKeyed(item keyed) pageKey {
+	return pageKey{At: item.At, Code: item.Code}
+}
+
+func codesOf(items []keyed) []string {
+	codes := make([]string, len(items))
+	for i, item := range items {
+		codes[i] = item.Code
+	}
+	return codes
+}
+
+func TestPaginate(t *testing.T) {
+	oldestFirst := listOrder{Sort: "created_at"}
+	items := []keyed{{1, "A"}, {2, "B"}, {2, "C"}, {3, "D"}, {4, "E"}}
+	after := func(item keyed) *pageCursor {
+		return &pageCursor{Sort: oldestFirst.Sort, After: keyOfKeyed(item)}
+	}
+
+	tests := []struct {
+		name         string
+		page         pageRequest
+		expected     []string
+		expectCursor bool
+	}{
+		{"first page", pageRequest{Limit: 2}, []string{"A", "B"}, true},
+		{"tied key", pageRequest{Limit: 2, Cursor: after(items[1])}, []string{"C", "D"}, true},
+		{"last page", pageRequest{Limit: 2, Cursor: after(items[3])}, []string{"E"}, false},
+		{"exact fit", pageRequest{Limit: 5}, []string{"A", "B", "C", "D", "E"}, false},
+		{"past the end", pageRequest{Limit: 2, Cursor: after(items[4])}, []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cursor, err := paginate(items, tt.page, oldestFirst, keyOfKeyed)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if codes := codesOf(got); !slices.Equal(codes, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, codes)
+			}
+			if (cursor != "") != tt.expectCursor {
+				t.Errorf("expected next cursor: %v, got %q", tt.expectCursor, cursor)
+			}
+		})
+	}
+}
+
+func TestPaginate_ListChangesBetweenPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		order listOrder
+		first []keyed
+		then  []keyed
+	}{
+		{
+			"item before the cursor removed",
+			listOrder{Sort: "created_at"},
+			[]keyed{{1, "A"}, {2, "B"}, {3, "C"}, {4, "D"}},
+			[]keyed{{2, "B"}, {3, "C"}, {4, "D"}},
+		},
+		{
+			"item before the cursor added",
+			listOrder{Sort: "created_at"},
+			[]keyed{{1, "A"}, {2, "B"}, {3, "C"}, {4, "D"}},
+			[]keyed{{0, "Z"}, {1, "A"}, {2, "B"}, {3, "C"}, {4, "D"}},
+		},
+		{
+			"newest first, item before the cursor added",
+			listOrder{Sort: "-created_at", NewestFirst: true},
+			[]keyed{{4, "D"}, {3, "C"}, {2, "B"}, {1, "A"}},
+			[]keyed{{5, "E"}, {4, "D"}, {3, "C"}, {2, "B"}, {1, "A"}},
+		},
+		{
+			"cursor's own item removed",
+			listOrder{Sort: "created_at"},
+			[]keyed{{1, "A"}, {2, "B"}, {3, "C"}, {4, "D"}},
+			[]keyed{{1, "A"}, {3, "C"}, {4, "D"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firstPage, value, err := paginate(tt.first, pageRequest{Limit: 2}, tt.order, keyOfKeyed)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			cursor, err := decodeCursor(value)
+			if err != nil {
+				t.Fatalf("expected a valid cursor, got %v", err)
+			}
+
+			secondPage, _, err := paginate(tt.then, pageRequest{Limit: 2, Cursor: &cursor}, tt.order, keyOfKeyed)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			expected := codesOf(tt.first[2:])
+			if codes := codesOf(secondPage); !slices.Equal(codes, expected) {
+				t.Errorf("after %v, expected %v, got %v", codesOf(firstPage), expected, codes)
+			}
+		})
+	}
+}
+
+func TestPaginate_CursorFromAnotherOrder(t *testing.T) {
+	cursor := &pageCursor{Sort: "-created_at", After: pageKey{At: 1, Code: "A"}}
+
+	_, _, err := paginate([]keyed{{1, "A"}}, pageRequest{Limit: 2, Cursor: cursor}, listOrder{Sort: "created_at"}, keyOfKeyed)
+	if !errors.Is(err, errInvalidCursor) {
+		t.Errorf("expected errInvalidCursor, got %v", err)
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	want := pageCursor{Sort: "-last_activity_at", After: pageKey{At: 1700000000123456789, Code: "ABC123"}}
+
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	// The last is the old offset cursor, base64 of "offset:2"
+	for _, cursor := range []string{"!!!", "YWZ0ZXI6", "YWZ0ZXI6Y3JlYXRlZF9hdDp4OkE", "b2Zmc2V0OjI"} {
+		if _, err := decodeCursor(cursor); !errors.Is(err, errInvalidCursor) {
+			t.Errorf("decodeCursor(%q): expected errInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"poke-battles/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerLobbyResponse is the lobby a player belongs to and the ID of its game in progress, if any
+type PlayerLobbyResponse struct {
+	Lobby  LobbyResponse `json:"lobby"`
+	GameID string        `json:"game_id,omitempty"`
+}
+
+// PlayerController handles HTTP requests about individual players
+type PlayerController struct {
+	lobbyService  services.LobbyService
+	battleService services.BattleService
+}
+
+// NewPlayerController creates a new player controller
+func NewPlayerController(ls services.LobbyService, bs services.BattleService) *PlayerController {
+	return &PlayerController{
+		lobbyService:  ls,
+		battleService: bs,
+	}
+}
+
+// GetLobby handles GET /api/v1/players/:id/lobby.
+// It lets a client that lost its local state find the lobby, and game, to reconnect to.
+func (c *PlayerController) GetLobby(ctx *gin.Context) {
+	playerID := ctx.Param("id")
+
+	lobby, err := c.lobbyService.FindPlayerLobby(playerID)
+	if err != nil {
+		if errors.Is(err, services.ErrPlayerHasNoLobby) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": errMsgPlayerHasNoLobby})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetPlayerLobby})
+		return
+	}
+
+	response := PlayerLobbyResponse{Lobby: toLobbyResponse(lobby)}
+	if battle, err := c.battleService.GetLobbyBattle(lobby.Code); err == nil {
+		response.GameID = battle.ID
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
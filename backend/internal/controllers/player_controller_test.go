@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poke-battles/internal/audit"
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+	"poke-battles/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupPlayerRouter(ls services.LobbyService, bs services.BattleService) *gin.Engine {
+	ctrl := NewPlayerController(ls, bs)
+
+	router := gin.New()
+	router.GET("/api/v1/players/:id/lobby", ctrl.GetLobby)
+	return router
+}
+
+func TestGetPlayerLobby_Waiting(t *testing.T) {
+	ls := services.NewLobbyService()
+	bs := services.NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	created, _ := ls.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	router := setupPlayerRouter(ls, bs)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/host-1/lobby", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp PlayerLobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Lobby.Code != created.Code {
+		t.Errorf("expected lobby %q, got %q", created.Code, resp.Lobby.Code)
+	}
+	if resp.GameID != "" {
+		t.Errorf("expected no game ID before the game starts, got %q", resp.GameID)
+	}
+}
+
+func TestGetPlayerLobby_GameInProgress(t *testing.T) {
+	ls := services.NewLobbyService()
+	bs := services.NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	created, _ := ls.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	ls.JoinLobby(created.Code, "player-2", "Player2")
+	ls.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+	ls.SubmitTeam(created.Code, "player-2", game.StarterTeam())
+	battle, err := bs.StartBattle(created)
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	router := setupPlayerRouter(ls, bs)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/player-2/lobby", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp PlayerLobbyResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Lobby.State != "active" {
+		t.Errorf("expected active lobby, got %q", resp.Lobby.State)
+	}
+	if resp.GameID != battle.ID {
+		t.Errorf("expected game ID %q, got %q", battle.ID, resp.GameID)
+	}
+}
+
+func TestGetPlayerLobby_NotInLobby(t *testing.T) {
+	bs := services.NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	router := setupPlayerRouter(services.NewLobbyService(), bs)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/nobody/lobby", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["error"] != errMsgPlayerHasNoLobby {
+		t.Errorf("expected error %q, got %q", errMsgPlayerHasNoLobby, resp["error"])
+	}
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"poke-battles/internal/replay"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplayController handles HTTP requests for finished battle replays
+type ReplayController struct {
+	replays replay.Store
+}
+
+// NewReplayController creates a new replay controller
+func NewReplayController(replays replay.Store) *ReplayController {
+	return &ReplayController{
+		replays: replays,
+	}
+}
+
+// Get handles GET /api/v1/replays/:id
+func (c *ReplayController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	doc, err := c.replays.Get(id)
+	if err != nil {
+		if errors.Is(err, replay.ErrReplayNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": errMsgReplayNotFound})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errMsgGetReplay})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, doc)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poke-battles/internal/replay"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupReplayRouter(store replay.Store) *gin.Engine {
+	ctrl := NewReplayController(store)
+
+	router := gin.New()
+	router.GET("/api/v1/replays/:id", ctrl.Get)
+	return router
+}
+
+func TestGetReplay_Success(t *testing.T) {
+	store := replay.NewMemoryStore()
+	store.Save(&replay.Replay{Version: replay.FormatVersion, ID: "ABC123-1", BattleID: "ABC123", Seed: 42})
+	router := setupReplayRouter(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/replays/ABC123-1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response replay.Replay
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.ID != "ABC123-1" || response.Seed != 42 || response.Version != replay.FormatVersion {
+		t.Errorf("unexpected replay: %+v", response)
+	}
+}
+
+func TestGetReplay_NotFound(t *testing.T) {
+	router := setupReplayRouter(replay.NewMemoryStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/replays/NOPE00-1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
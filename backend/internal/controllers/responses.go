@@ -3,12 +3,36 @@ package controllers
 // Error messages for API responses
 const (
 	errMsgCreateLobby          = "failed to create lobby"
+	errMsgInvalidMaxPlayers    = "max_players must be between 2 and 8"
+	errMsgUnknownVisibility    = "visibility must be public or unlisted"
+	errMsgInvalidRoomCode      = "code must be 4 to 12 letters and digits, excluding 0, O, 1, I and L"
+	errMsgRoomCodeTaken        = "code is already in use"
 	errMsgLobbyNotFound        = "lobby not found"
 	errMsgGetLobby             = "failed to get lobby"
 	errMsgGetLobbies           = "failed to get lobbies"
+	errMsgInvalidStateFilter   = "state must be waiting, ready, active, finished or closed"
+	errMsgInvalidOpenSlot      = "has_open_slot must be true or false"
+	errMsgInvalidLobbySort     = "sort must be created_at or last_activity_at, optionally prefixed with -"
+	errMsgInvalidPageLimit     = "limit must be between 1 and 100"
+	errMsgInvalidCursor        = "invalid cursor"
 	errMsgJoinLobby            = "failed to join lobby"
 	errMsgLobbyFull            = "lobby is full"
+	errMsgTooManyLobbies       = "player is already in another lobby"
+	errMsgSpectateLobby        = "failed to spectate lobby"
+	errMsgSpectatorsFull       = "no spectator slots left"
+	errMsgAlreadySpectating    = "player already spectating"
+	errMsgJoinWaitlist         = "failed to join waitlist"
+	errMsgLobbyNotFull         = "lobby has a free slot, join it directly"
+	errMsgWaitlistFull         = "waitlist is full"
+	errMsgAlreadyWaitlisted    = "player already on the waitlist"
+	errMsgWaitlistSpectating   = "spectators must stop watching before joining the waitlist"
+	errMsgCreateInvite         = "failed to create invite"
+	errMsgOnlyHostCanInvite    = "only host can create invites"
+	errMsgInvalidInvite        = "invite is invalid or has already been used"
+	errMsgInviteExpired        = "invite has expired"
 	errMsgLeaveLobby           = "failed to leave lobby"
+	errMsgDeleteLobby          = "failed to delete lobby"
+	errMsgOnlyHostCanDelete    = "only host can delete the lobby"
 	errMsgPlayerAlreadyInLobby = "player already in lobby"
 	errMsgPlayerNotInLobby     = "player not found in lobby"
 	errMsgLobbyInvalidState    = "cannot join lobby in current state"
@@ -16,10 +40,65 @@ const (
 	errMsgOnlyHostCanStart     = "only host can start the game"
 	errMsgGameInvalidState     = "cannot start game in current state"
 	errMsgNotEnoughPlayers     = "not enough players to start"
+	errMsgTooManyPlayers       = "battles are between exactly two players"
 	errMsgGameStartLobbyState  = "game started but failed to get lobby state"
+	errMsgTeamsNotSubmitted    = "all players must submit a team before starting"
+	errMsgSubmitTeam           = "failed to submit team"
+	errMsgTeamInvalidState     = "cannot change team in current state"
+	errMsgInvalidTeam          = "invalid team"
+	errMsgSetReady             = "failed to set ready state"
+	errMsgReadyInvalidState    = "cannot change ready state after the game has started"
+	errMsgSetRuleset           = "failed to set ruleset"
+	errMsgOnlyHostCanSetRules  = "only host can change the ruleset"
+	errMsgUnknownRuleset       = "unknown ruleset"
+	errMsgRulesetInvalidState  = "cannot change ruleset in current state"
+	errMsgSetSeries            = "failed to set series length"
+	errMsgOnlyHostCanSetSeries = "only host can change the series length"
+	errMsgInvalidSeriesLength  = "series must be best of 1, 3 or 5"
+	errMsgSeriesInvalidState   = "cannot change series length in current state"
+	errMsgSetRematchTeams      = "failed to set rematch teams"
+	errMsgOnlyHostCanRematch   = "only host can change the rematch teams"
+	errMsgUnknownRematchTeams  = "rematch teams must be same or new"
+	errMsgRematchInvalidState  = "cannot change rematch teams during a game"
+	errMsgUpdateSettings       = "failed to update lobby settings"
+	errMsgOnlyHostCanSettings  = "only host can change the lobby settings"
+	errMsgSettingsInvalidState = "lobby settings can only be changed while waiting for players"
+	errMsgInvalidTurnTimer     = "turn_timer_sec must be between 0 and 300"
+	errMsgInvalidCountdown     = "countdown_sec must be between 0 and 30"
+	errMsgUnknownStartMode     = "start_mode must be auto or host"
+	errMsgInvalidSpectators    = "max_spectators must be between 0 and 50"
+	errMsgUnknownLobbyTag      = "unknown lobby tag"
+	errMsgTooManyLobbyTags     = "a lobby can have at most 5 tags"
+	errMsgSetDraft             = "failed to set draft mode"
+	errMsgOnlyHostCanSetDraft  = "only host can change draft mode"
+	errMsgDraftInvalidState    = "cannot change draft mode in current state"
+	errMsgDraftInProgress      = "team cannot be submitted until the draft is complete"
+	errMsgAddBot               = "failed to add bot"
+	errMsgOnlyHostCanAddBot    = "only host can add a bot"
+	errMsgUnknownBotDifficulty = "unknown bot difficulty"
+	errMsgBotInDraftLobby      = "bots cannot play in draft lobbies"
+	errMsgBotTeamNotLegal      = "the bot's team is not legal under the lobby's ruleset"
+	errMsgPlayerIDRequired     = "player_id is required"
+	errMsgTeamNotFound         = "player has not submitted a team"
+	errMsgPlayerHasNoLobby     = "player is not in a lobby"
+	errMsgGetPlayerLobby       = "failed to get player's lobby"
+	errMsgInvalidShowdownTeam  = "invalid showdown team"
+	errMsgReplayNotFound       = "replay not found"
+	errMsgGetReplay            = "failed to get replay"
+	errMsgGameNotFound         = "game not found"
+	errMsgGetGameLog           = "failed to get game log"
+	errMsgUnknownLocale        = "unknown locale"
+	errMsgCalculateDamage      = "failed to calculate damage"
+	errMsgUnknownSpecies       = "unknown species"
+	errMsgUnknownMove          = "unknown move"
+	errMsgInvalidLevel         = "level must be between 1 and 100"
+	errMsgInvalidCurrentHP     = "current_hp must be between 1 and the creature's max HP"
+	errMsgUnknownStatStage     = "stages may only modify attack, defense, sp_attack or sp_defense"
+	errMsgUnknownStatus        = "status must be poison, bad_poison or burn"
 )
 
 // Success messages for API responses
 const (
-	msgLeftLobby = "left lobby successfully"
+	msgLeftLobby    = "left lobby successfully"
+	msgLobbyDeleted = "lobby deleted"
 )
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"net/http"
+
+	"poke-battles/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RulesetResponse struct {
+	ID              string                              `json:"id"`
+	Name            string                              `json:"name"`
+	TeamSize        int                                 `json:"team_size"`
+	LevelCap        int                                 `json:"level_cap"`
+	SpeciesClause   bool                                `json:"species_clause"`
+	ItemClause      bool                                `json:"item_clause"`
+	TeamPreview     bool                                `json:"team_preview"`
+	TurnLimit       int                                 `json:"turn_limit"`
+	BannedSpecies   []string                            `json:"banned_species"`
+	BannedMoves     []string                            `json:"banned_moves"`
+	Bag             map[string]int                      `json:"bag,omitempty"`
+	TypeChart       map[game.Type]map[game.Type]float64 `json:"type_chart,omitempty"`        // Matchups overriding the data's type chart
+	CustomTypeChart bool                                `json:"custom_type_chart,omitempty"` // TypeChart replaces the data's chart entirely
+}
+
+// RulesetController handles HTTP requests for team legality rulesets
+type RulesetController struct{}
+
+// NewRulesetController creates a new ruleset controller
+func NewRulesetController() *RulesetController {
+	return &RulesetController{}
+}
+
+// List handles GET /api/v1/rulesets
+func (c *RulesetController) List(ctx *gin.Context) {
+	rulesets := game.ListRulesets()
+
+	response := make([]RulesetResponse, len(rulesets))
+	for i, r := range rulesets {
+		response[i] = RulesetResponse{
+			ID:              r.ID,
+			Name:            r.Name,
+			TeamSize:        r.TeamSize,
+			LevelCap:        r.LevelCap,
+			SpeciesClause:   r.SpeciesClause,
+			ItemClause:      r.ItemClause,
+			TeamPreview:     r.TeamPreview,
+			TurnLimit:       r.TurnLimit,
+			BannedSpecies:   nonNil(r.BannedSpecies),
+			BannedMoves:     nonNil(r.BannedMoves),
+			Bag:             r.Bag,
+			TypeChart:       r.TypeChart,
+			CustomTypeChart: r.CustomTypeChart,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// nonNil returns an empty slice for nil so lists encode as [] rather than null
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
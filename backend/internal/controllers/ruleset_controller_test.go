@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poke-battles/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupRulesetRouter() *gin.Engine {
+	ctrl := NewRulesetController()
+
+	router := gin.New()
+	router.GET("/api/v1/rulesets", ctrl.List)
+	return router
+}
+
+func TestListRulesets_Success(t *testing.T) {
+	router := setupRulesetRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rulesets", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response []RulesetResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if len(response) != len(game.ListRulesets()) {
+		t.Fatalf("expected %d rulesets, got %d", len(game.ListRulesets()), len(response))
+	}
+
+	var found bool
+	for _, r := range response {
+		if r.ID == "competitive" {
+			found = true
+			if !r.SpeciesClause || !r.ItemClause || r.LevelCap != game.DefaultLevel {
+				t.Errorf("unexpected competitive ruleset: %+v", r)
+			}
+			if r.BannedSpecies == nil || r.BannedMoves == nil {
+				t.Error("expected ban lists to encode as empty arrays")
+			}
+			if !r.TeamPreview {
+				t.Error("expected competitive ruleset to use team preview")
+			}
+		}
+		if r.TurnLimit != game.DefaultTurnLimit {
+			t.Errorf("expected ruleset %q to use the default turn limit, got %d", r.ID, r.TurnLimit)
+		}
+		if r.ID == "inverse" && (!r.CustomTypeChart || r.TypeChart[game.TypeNormal][game.TypeGhost] != 2) {
+			t.Errorf("expected inverse ruleset to list its custom type chart, got %+v", r)
+		}
+		if r.ID != "inverse" && r.TypeChart != nil {
+			t.Errorf("expected ruleset %q to use the data's type chart, got %v", r.ID, r.TypeChart)
+		}
+		if r.ID == "casual" && r.Bag["potion"] == 0 {
+			t.Errorf("expected casual ruleset to list its item bag, got %v", r.Bag)
+		}
+	}
+	if !found {
+		t.Error("expected competitive ruleset to be listed")
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+
+	"poke-battles/internal/showdown"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportTeamRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+type TeamResponse struct {
+	Team []TeamMemberRequest `json:"team"`
+}
+
+type ShowdownTeamResponse struct {
+	Text string `json:"text"`
+}
+
+// TeamController handles HTTP requests for converting teams between formats
+type TeamController struct{}
+
+// NewTeamController creates a new team controller
+func NewTeamController() *TeamController {
+	return &TeamController{}
+}
+
+// Import handles POST /api/v1/teams/import.
+// It converts a team in Showdown text format to the team submission format without checking legality.
+func (c *TeamController) Import(ctx *gin.Context) {
+	var req ImportTeamRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := showdown.Parse(req.Text)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errMsgInvalidShowdownTeam, "detail": err.Error()})
+		return
+	}
+
+	members := make([]TeamMemberRequest, len(team))
+	for i, m := range team {
+		members[i] = TeamMemberRequest{
+			SpeciesID: m.SpeciesID,
+			Level:     m.Level,
+			Item:      m.Item,
+			Moves:     m.Moves,
+			Nickname:  m.Nickname,
+			Shiny:     m.Shiny,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, TeamResponse{Team: members})
+}
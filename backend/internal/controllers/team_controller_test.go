@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupTeamRouter() *gin.Engine {
+	ctrl := NewTeamController()
+
+	router := gin.New()
+	router.POST("/api/v1/teams/import", ctrl.Import)
+	return router
+}
+
+func TestImportTeam_Success(t *testing.T) {
+	router := setupTeamRouter()
+
+	body, _ := json.Marshal(ImportTeamRequest{Text: "Sparky (Pikachu) @ Light Ball\nLevel: 50\n- Thunderbolt\n- Quick Attack\n"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/teams/import", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response TeamResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if len(response.Team) != 1 {
+		t.Fatalf("expected 1 team member, got %d", len(response.Team))
+	}
+	m := response.Team[0]
+	if m.SpeciesID != "pikachu" || m.Level != 50 || m.Item != "light-ball" || len(m.Moves) != 2 || m.Moves[1] != "quick-attack" {
+		t.Errorf("unexpected team member: %+v", m)
+	}
+}
+
+func TestImportTeam_Malformed(t *testing.T) {
+	router := setupTeamRouter()
+
+	body, _ := json.Marshal(ImportTeamRequest{Text: "Pikachu\nLevel: fifty\n"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/teams/import", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp["error"] != errMsgInvalidShowdownTeam {
+		t.Errorf("expected error %q, got %q", errMsgInvalidShowdownTeam, resp["error"])
+	}
+}
+
+func TestImportTeam_MissingText(t *testing.T) {
+	router := setupTeamRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/teams/import", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,63 @@
+package events
+
+import "sync"
+
+// Handler is called with each event published on a bus it subscribes to
+type Handler func(event Event)
+
+// Bus carries events from the services that change lobbies and games to whoever needs to react to them,
+// such as the websocket handler telling the lobby's clients. A nil bus discards what is published to it.
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]Handler
+	order       []int // Subscriber IDs, oldest first
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[int]Handler)}
+}
+
+// Subscribe calls the handler with every event published from now on until the returned function is called
+func (b *Bus) Subscribe(handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = handler
+	b.order = append(b.order, id)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+		for i, subscribed := range b.order {
+			if subscribed == id {
+				b.order = append(b.order[:i:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Publish calls every subscriber with the event, oldest subscriber first, and returns once they all have.
+// Subscribers run on the publisher's goroutine, so they see a publisher's events in the order they happened
+// and may publish events of their own, but must not block.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.order))
+	for _, id := range b.order {
+		handlers = append(handlers, b.subscribers[id])
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
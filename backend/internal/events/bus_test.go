@@ -0,0 +1,63 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ========================================
+// Bus Tests
+// ========================================
+
+func TestBus_PublishesToSubscribersInOrder(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	bus.Subscribe(func(event Event) { got = append(got, "first:"+event.Name()) })
+	bus.Subscribe(func(event Event) { got = append(got, "second:"+event.Name()) })
+
+	bus.Publish(PlayerJoined{PlayerID: "player-1"})
+	bus.Publish(PlayerLeft{PlayerID: "player-1"})
+
+	expected := []string{"first:player_joined", "second:player_joined", "first:player_left", "second:player_left"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+	var first, second int
+	unsubscribe := bus.Subscribe(func(Event) { first++ })
+	bus.Subscribe(func(Event) { second++ })
+
+	bus.Publish(ReadyChanged{})
+	unsubscribe()
+	bus.Publish(ReadyChanged{})
+
+	if first != 1 || second != 2 {
+		t.Errorf("expected the unsubscribed handler to stop receiving events, got %d and %d", first, second)
+	}
+}
+
+func TestBus_SubscriberMayPublish(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	bus.Subscribe(func(event Event) {
+		got = append(got, event.Name())
+		if _, ok := event.(PlayerLeft); ok {
+			bus.Publish(WaitlistPromoted{})
+		}
+	})
+
+	bus.Publish(PlayerLeft{})
+
+	expected := []string{"player_left", "waitlist_promoted"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestBus_NilDiscards(t *testing.T) {
+	var bus *Bus
+	bus.Publish(LobbyDeleted{}) // Must not panic
+}
@@ -0,0 +1,114 @@
+// Package events defines what happens in lobbies and games as typed events, and the bus they are published on.
+package events
+
+import "poke-battles/internal/game"
+
+// Event is something that happened in a lobby or its game
+type Event interface {
+	// Name identifies the kind of event, e.g. "player_joined"
+	Name() string
+}
+
+// PlayerJoined is published when a player or bot takes a slot in a lobby
+type PlayerJoined struct {
+	Lobby    *game.Lobby
+	PlayerID string
+	Username string
+}
+
+// PlayerLeft is published when a player leaves a lobby. The lobby has already been removed if they were its last player.
+type PlayerLeft struct {
+	Lobby    *game.Lobby
+	PlayerID string
+}
+
+// SpectatorJoined is published when someone joins a lobby's spectator roster
+type SpectatorJoined struct {
+	Lobby       *game.Lobby
+	SpectatorID string
+	Username    string
+}
+
+// SpectatorLeft is published when a spectator leaves a lobby
+type SpectatorLeft struct {
+	Lobby       *game.Lobby
+	SpectatorID string
+}
+
+// WaitlistJoined is published when a player queues for a full lobby's next free slot
+type WaitlistJoined struct {
+	Lobby    *game.Lobby
+	PlayerID string
+	Username string
+}
+
+// WaitlistLeft is published when a player leaves a lobby's waitlist without being promoted
+type WaitlistLeft struct {
+	Lobby    *game.Lobby
+	PlayerID string
+}
+
+// WaitlistPromoted is published after PlayerLeft when waitlisted players took the slot the leaving player freed
+type WaitlistPromoted struct {
+	Lobby    *game.Lobby
+	Promoted []game.WaitlistEntry
+}
+
+// TeamSubmitted is published when a player submits their team for the lobby's next game
+type TeamSubmitted struct {
+	Lobby    *game.Lobby
+	PlayerID string
+}
+
+// ReadyChanged is published when a player marks themselves ready or not ready
+type ReadyChanged struct {
+	Lobby    *game.Lobby
+	PlayerID string
+	Ready    bool
+}
+
+// SettingsChanged is published when the host changes a lobby's settings, ruleset, series length,
+// rematch teams or draft mode
+type SettingsChanged struct {
+	Lobby *game.Lobby
+}
+
+// GameStarting is published when the host starts a lobby's game directly
+type GameStarting struct {
+	Lobby *game.Lobby
+}
+
+// GameEnded is published when a lobby's game ends with an outcome, once the command that ended it on the game's
+// goroutine has returned. Subscribers run on that goroutine, so they may read the battle.
+type GameEnded struct {
+	Lobby    *game.Lobby
+	Battle   *game.Battle
+	Outcome  *game.BattleOutcome
+	ReplayID string      // Empty if the replay could not be saved
+	Series   game.Series // The lobby's series score including this game
+}
+
+// LobbyDeleted is published when the host deletes a lobby
+type LobbyDeleted struct {
+	Lobby *game.Lobby
+}
+
+// LobbyExpired is published when the janitor removes a lobby for being idle
+type LobbyExpired struct {
+	Lobby *game.Lobby
+}
+
+func (PlayerJoined) Name() string     { return "player_joined" }
+func (PlayerLeft) Name() string       { return "player_left" }
+func (SpectatorJoined) Name() string  { return "spectator_joined" }
+func (SpectatorLeft) Name() string    { return "spectator_left" }
+func (WaitlistJoined) Name() string   { return "waitlist_joined" }
+func (WaitlistLeft) Name() string     { return "waitlist_left" }
+func (WaitlistPromoted) Name() string { return "waitlist_promoted" }
+func (TeamSubmitted) Name() string    { return "team_submitted" }
+func (ReadyChanged) Name() string     { return "ready_changed" }
+func (SettingsChanged) Name() string  { return "settings_changed" }
+func (GameStarting) Name() string     { return "game_starting" }
+func (GameEnded) Name() string        { return "game_ended" }
+func (LobbyDeleted) Name() string     { return "lobby_deleted" }
+func (LobbyExpired) Name() string     { return "lobby_expired" }
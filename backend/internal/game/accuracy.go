@@ -0,0 +1,42 @@
+package game
+
+// Critical hit configuration
+const (
+	criticalMultiplier = 1.5
+)
+
+// critChanceDenominators maps a critical stage to a 1-in-N chance of a critical hit
+var critChanceDenominators = [maxCritStage + 1]int{24, 8, 2, 1}
+
+// accuracyMultiplier converts a net accuracy stage into a hit chance multiplier: +1 is 4/3, -1 is 3/4, and so on
+func accuracyMultiplier(stage int) float64 {
+	if stage >= 0 {
+		return float64(3+stage) / 3
+	}
+	return 3 / float64(3-stage)
+}
+
+// checkAccuracy rolls whether a move hits, factoring in the user's accuracy and the target's evasion.
+// Moves with no accuracy value never miss.
+func (b *Battle) checkAccuracy(attacker, defender *Creature, move *Move) bool {
+	if move.Accuracy == 0 {
+		return true
+	}
+
+	stage := min(max(attacker.Stages.Accuracy-defender.Stages.Evasion, minStatStage), maxStatStage)
+	chance := int(float64(move.Accuracy) * accuracyMultiplier(stage))
+	if chance >= 100 {
+		return true
+	}
+	return b.rng.Intn(100) < chance
+}
+
+// rollCritical decides whether a damaging move lands a critical hit
+func (b *Battle) rollCritical(attacker *Creature, move *Move) bool {
+	stage := min(attacker.Stages.Critical+move.CritStage, maxCritStage)
+	denominator := critChanceDenominators[stage]
+	if denominator == 1 {
+		return true
+	}
+	return b.rng.Intn(denominator) == 0
+}
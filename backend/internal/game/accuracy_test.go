@@ -0,0 +1,162 @@
+package game
+
+import "testing"
+
+// ========================================
+// Critical Hit Tests
+// ========================================
+
+func TestRollCritical_StageChances(t *testing.T) {
+	tests := []struct {
+		name     string
+		stage    int
+		rollSeq  []int
+		expected bool
+	}{
+		{"stage 0 hit on zero roll", 0, []int{0}, true},
+		{"stage 0 miss on nonzero roll", 0, []int{1}, false},
+		{"stage 2 hit on zero roll", 2, []int{0}, true},
+		{"stage 2 miss on one", 2, []int{1}, false},
+		{"stage 3 always crits", 3, []int{1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "tackle")
+			attacker.Stages.Critical = tt.stage
+			b := newTestBattle(&scriptedRNG{values: tt.rollSeq}, attacker, newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"))
+
+			if got := b.rollCritical(attacker, mustMove(t, "tackle")); got != tt.expected {
+				t.Errorf("expected critical %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRollCritical_HighCritMoveAddsStage(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "slash")
+	attacker.Stages.Critical = 2
+	b := newTestBattle(&scriptedRNG{values: []int{5}}, attacker, newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"))
+
+	if !b.rollCritical(attacker, mustMove(t, "slash")) {
+		t.Error("expected stage 2 plus a high-crit move to always crit")
+	}
+}
+
+func TestCalculateDamage_CriticalMultiplier(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	move := mustMove(t, "tackle")
+
+	normal := CalculateDamage(attacker, defender, move, 100, false)
+	crit := CalculateDamage(attacker, defender, move, 100, true)
+
+	if !crit.Critical || normal.Critical {
+		t.Error("expected Critical flag to reflect the crit roll")
+	}
+	if crit.Damage <= normal.Damage {
+		t.Errorf("expected crit damage to exceed normal: normal %d, crit %d", normal.Damage, crit.Damage)
+	}
+}
+
+func TestCalculateDamage_CriticalIgnoresUnfavorableStages(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	move := mustMove(t, "tackle")
+
+	baseline := CalculateDamage(attacker, defender, move, 100, true).Damage
+
+	attacker.Stages.Attack = -2
+	defender.Stages.Defense = 2
+	if got := CalculateDamage(attacker, defender, move, 100, true).Damage; got != baseline {
+		t.Errorf("expected crit to ignore attacker drops and defender boosts: baseline %d, got %d", baseline, got)
+	}
+
+	attacker.Stages.Attack = 2
+	if got := CalculateDamage(attacker, defender, move, 100, true).Damage; got <= baseline {
+		t.Errorf("expected crit to keep attacker boosts: baseline %d, got %d", baseline, got)
+	}
+}
+
+func TestFocusEnergy_RaisesCritStage(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "focus-energy")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "focus-energy")
+	foe.Stages.Critical = maxCritStage
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, "focus-energy", "focus-energy")
+
+	if user.Stages.Critical != 2 {
+		t.Errorf("expected crit stage 2, got %d", user.Stages.Critical)
+	}
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Actor != "player-2" {
+		t.Errorf("expected focus energy at max crit stage to fail, got %+v", failed)
+	}
+}
+
+// ========================================
+// Accuracy / Evasion Tests
+// ========================================
+
+func TestCheckAccuracy(t *testing.T) {
+	tests := []struct {
+		name     string
+		moveID   string
+		accuracy int
+		evasion  int
+		roll     int
+		expected bool
+	}{
+		{"perfect accuracy move never rolls", "tackle", 0, 0, 99, true},
+		{"never-miss move ignores evasion", "swords-dance", 0, 6, 99, true},
+		{"80 accuracy hits under threshold", "hydro-pump", 0, 0, 79, true},
+		{"80 accuracy misses at threshold", "hydro-pump", 0, 0, 80, false},
+		{"evasion lowers hit chance", "tackle", 0, 1, 75, false},
+		{"evasion lowers hit chance but can still hit", "tackle", 0, 1, 74, true},
+		{"accuracy offsets evasion", "hydro-pump", 1, 1, 79, true},
+		{"accuracy boost guarantees hit", "hydro-pump", 1, 0, 99, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, tt.moveID)
+			defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50, "tackle")
+			attacker.Stages.Accuracy = tt.accuracy
+			defender.Stages.Evasion = tt.evasion
+			b := newTestBattle(&scriptedRNG{values: []int{tt.roll}}, attacker, defender)
+
+			if got := b.checkAccuracy(attacker, defender, mustMove(t, tt.moveID)); got != tt.expected {
+				t.Errorf("expected hit %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMiss_EmitsMoveFailed(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeWater}, 100, "hydro-pump")
+	defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{values: []int{95}, fallback: 15}, attacker, defender)
+
+	events := resolveTurn(t, b, "hydro-pump", "growl")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonMissed || failed[0].Actor != "player-1" {
+		t.Fatalf("expected player-1's move to miss, got %+v", failed)
+	}
+	if defender.CurrentHP != defender.MaxHP() {
+		t.Error("expected no damage on a miss")
+	}
+}
+
+func TestSandAttack_LowersAccuracy(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "sand-attack")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	resolveTurn(t, b, "sand-attack", "growl")
+
+	if foe.Stages.Accuracy != -1 {
+		t.Errorf("expected foe accuracy -1, got %d", foe.Stages.Accuracy)
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import "errors"
+
+var (
+	// ErrUnknownActionKind is returned for an action of a kind the battle does not support
+	ErrUnknownActionKind = errors.New("unknown action kind")
+	// ErrCreatureFainted is returned when a fainted creature is asked to act
+	ErrCreatureFainted = errors.New("active creature has fainted")
+)
+
+// ValidateAction checks that a player may submit an action for the current turn, without submitting it.
+// Every choice a player makes for a turn is checked here, so the server alone decides what is legal:
+// the battle must be waiting on the player, a move must be known by the player's active creature and have PP left,
+// a switch must target a conscious bench creature, and an item must be in the player's bag and affect its target.
+func (b *Battle) ValidateAction(playerID string, action Action) error {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return err
+	}
+
+	if b.outcome != nil {
+		return ErrBattleOver
+	}
+	if b.preview {
+		return ErrInTeamPreview
+	}
+	if b.pause != nil {
+		return ErrBattlePaused
+	}
+	if b.awaitingSwitch() {
+		return ErrAwaitingSwitch
+	}
+	if b.pending[idx] != nil {
+		return ErrActionAlreadySubmitted
+	}
+
+	side := b.Sides[idx]
+	switch action.Kind {
+	case ActionKindSwitch:
+		return side.CanSwitchTo(action.SwitchSlot)
+	case ActionKindItem:
+		return side.canUseItem(action.ItemID, action.ItemSlot)
+	case ActionKindMove:
+		return validateMove(side.Active(), action.MoveID)
+	default:
+		return ErrUnknownActionKind
+	}
+}
+
+// validateMove checks that a creature can use a move. A creature with every move exhausted
+// may choose anything, as it is forced to Struggle.
+func validateMove(active *Creature, moveID string) error {
+	if active.IsFainted() {
+		return ErrCreatureFainted
+	}
+	if !active.HasUsableMove() {
+		return nil
+	}
+	if _, ok := active.FindMove(moveID); !ok {
+		return ErrMoveNotKnown
+	}
+	if active.RemainingPP(moveID) == 0 {
+		return ErrNoPPLeft
+	}
+	return nil
+}
+
+// ValidateForcedSwitch checks that a player may replace their fainted creature with the one in a team slot
+func (b *Battle) ValidateForcedSwitch(playerID string, slot int) error {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return err
+	}
+
+	if b.outcome != nil {
+		return ErrBattleOver
+	}
+	if b.pause != nil {
+		return ErrBattlePaused
+	}
+
+	side := b.Sides[idx]
+	if !side.NeedsReplacement() {
+		return ErrNoSwitchRequired
+	}
+	return side.CanSwitchTo(slot)
+}
+
+// ValidateLead checks that a player may lead with the creature in a team slot during team preview
+func (b *Battle) ValidateLead(playerID string, slot int) error {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return err
+	}
+
+	if b.outcome != nil {
+		return ErrBattleOver
+	}
+	if !b.preview {
+		return ErrNotInTeamPreview
+	}
+	if b.pause != nil {
+		return ErrBattlePaused
+	}
+	if b.leadChosen[idx] {
+		return ErrLeadAlreadyChosen
+	}
+	if slot < 0 || slot >= len(b.Sides[idx].Team) {
+		return ErrInvalidLead
+	}
+	return nil
+}
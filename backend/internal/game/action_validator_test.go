@@ -0,0 +1,104 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Action Validator Tests
+// ========================================
+
+func TestValidateAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(b *Battle)
+		action Action
+		want   error
+	}{
+		{"legal move", nil, Action{Kind: ActionKindMove, MoveID: "razor-leaf"}, nil},
+		{"move not known", nil, Action{Kind: ActionKindMove, MoveID: "hyper-beam"}, ErrMoveNotKnown},
+		{"move out of PP", func(b *Battle) { b.Sides[0].Active().PP["razor-leaf"] = 0 }, Action{Kind: ActionKindMove, MoveID: "razor-leaf"}, ErrNoPPLeft},
+		{"fainted creature", func(b *Battle) { b.Sides[0].Active().CurrentHP = 0 }, Action{Kind: ActionKindMove, MoveID: "razor-leaf"}, ErrAwaitingSwitch},
+		{"legal switch", nil, Action{Kind: ActionKindSwitch, SwitchSlot: 1}, nil},
+		{"switch to active creature", nil, Action{Kind: ActionKindSwitch, SwitchSlot: 0}, ErrInvalidSwitchTarget},
+		{"switch out of range", nil, Action{Kind: ActionKindSwitch, SwitchSlot: 9}, ErrInvalidSwitchTarget},
+		{"switch to fainted creature", func(b *Battle) { b.Sides[0].Team[1].CurrentHP = 0 }, Action{Kind: ActionKindSwitch, SwitchSlot: 1}, ErrInvalidSwitchTarget},
+		{"item not owned", nil, Action{Kind: ActionKindItem, ItemID: "potion", ItemSlot: 0}, ErrItemNotInBag},
+		{"unknown action kind", nil, Action{Kind: ActionKind(99)}, ErrUnknownActionKind},
+		{"paused", func(b *Battle) { b.pause = &Pause{PlayerID: "player-1"} }, Action{Kind: ActionKindMove, MoveID: "razor-leaf"}, ErrBattlePaused},
+		{"in team preview", func(b *Battle) { b.StartTeamPreview() }, Action{Kind: ActionKindMove, MoveID: "razor-leaf"}, ErrInTeamPreview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newSeededStarterBattle(t, 1)
+			if tt.setup != nil {
+				tt.setup(b)
+			}
+			if err := b.ValidateAction("player-1", tt.action); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if _, submitted := b.SubmittedAction("player-1"); submitted {
+				t.Error("expected validation not to submit the action")
+			}
+		})
+	}
+}
+
+func TestValidateAction_UnknownPlayer(t *testing.T) {
+	b := newSeededStarterBattle(t, 1)
+
+	if err := b.ValidateAction("player-3", Action{Kind: ActionKindMove, MoveID: "razor-leaf"}); !errors.Is(err, ErrPlayerNotInBattle) {
+		t.Errorf("expected ErrPlayerNotInBattle, got %v", err)
+	}
+}
+
+func TestValidateMove_FaintedCreature(t *testing.T) {
+	b := newSeededStarterBattle(t, 1)
+	b.Sides[0].Active().CurrentHP = 0
+
+	if err := validateMove(b.Sides[0].Active(), "razor-leaf"); !errors.Is(err, ErrCreatureFainted) {
+		t.Errorf("expected ErrCreatureFainted, got %v", err)
+	}
+}
+
+func TestValidateForcedSwitch(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+	if err := b.ValidateForcedSwitch("player-2", 2); !errors.Is(err, ErrNoSwitchRequired) {
+		t.Errorf("expected ErrNoSwitchRequired before the faint, got %v", err)
+	}
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	if err := b.ValidateForcedSwitch("player-2", 1); !errors.Is(err, ErrInvalidSwitchTarget) {
+		t.Errorf("expected ErrInvalidSwitchTarget for a fainted creature, got %v", err)
+	}
+	if err := b.ValidateForcedSwitch("player-2", 2); err != nil {
+		t.Errorf("expected the healthy bench creature to be a legal replacement, got %v", err)
+	}
+	if !b.Sides[1].NeedsReplacement() {
+		t.Error("expected validation not to switch")
+	}
+}
+
+func TestValidateLead(t *testing.T) {
+	b := newSeededStarterBattle(t, 1)
+	if err := b.ValidateLead("player-1", 0); !errors.Is(err, ErrNotInTeamPreview) {
+		t.Errorf("expected ErrNotInTeamPreview, got %v", err)
+	}
+
+	b.StartTeamPreview()
+	if err := b.ValidateLead("player-1", len(b.Sides[0].Team)); !errors.Is(err, ErrInvalidLead) {
+		t.Errorf("expected ErrInvalidLead, got %v", err)
+	}
+	if err := b.ValidateLead("player-1", 1); err != nil {
+		t.Errorf("expected a legal lead, got %v", err)
+	}
+	if _, err := b.ChooseLead("player-1", 1); err != nil {
+		t.Fatalf("failed to choose lead: %v", err)
+	}
+	if err := b.ValidateLead("player-1", 2); !errors.Is(err, ErrLeadAlreadyChosen) {
+		t.Errorf("expected ErrLeadAlreadyChosen, got %v", err)
+	}
+}
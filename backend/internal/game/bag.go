@@ -0,0 +1,113 @@
+package game
+
+import "errors"
+
+// Item bag errors
+var (
+	ErrUnknownBagItem    = errors.New("unknown bag item")
+	ErrItemNotInBag      = errors.New("item not in bag")
+	ErrInvalidItemTarget = errors.New("invalid item target")
+	ErrItemHasNoEffect   = errors.New("item would have no effect")
+)
+
+// reviveHPDivisor is the fraction of max HP a revived creature comes back with (1/2)
+const reviveHPDivisor = 2
+
+// BagItem is a consumable a player can use on their own team instead of acting for a turn
+type BagItem struct {
+	ID         string
+	Name       string
+	Heal       int  // HP restored to a conscious creature
+	CureStatus bool // removes the target's major status
+	Revive     bool // restores a fainted creature to half its max HP
+}
+
+var bagItemCatalogue = map[string]*BagItem{
+	"potion":       {ID: "potion", Name: "Potion", Heal: 20},
+	"super-potion": {ID: "super-potion", Name: "Super Potion", Heal: 60},
+	"hyper-potion": {ID: "hyper-potion", Name: "Hyper Potion", Heal: 120},
+	"full-heal":    {ID: "full-heal", Name: "Full Heal", CureStatus: true},
+	"revive":       {ID: "revive", Name: "Revive", Revive: true},
+}
+
+// LookupBagItem returns the catalogue entry for a bag item ID
+func LookupBagItem(id string) (*BagItem, error) {
+	item, ok := bagItemCatalogue[id]
+	if !ok {
+		return nil, ErrUnknownBagItem
+	}
+	return item, nil
+}
+
+// affects reports whether using the item on the creature would change anything
+func (i *BagItem) affects(c *Creature) bool {
+	if c.IsFainted() {
+		return i.Revive
+	}
+	if i.Heal > 0 && c.CurrentHP < c.MaxHP() {
+		return true
+	}
+	return i.CureStatus && c.Status != StatusNone
+}
+
+// Bag maps bag item IDs to the quantity a player holds
+type Bag map[string]int
+
+// Clone returns a copy of the bag so each player consumes their own items
+func (b Bag) Clone() Bag {
+	if b == nil {
+		return nil
+	}
+	clone := make(Bag, len(b))
+	for id, count := range b {
+		clone[id] = count
+	}
+	return clone
+}
+
+// canUseItem checks that a side holds the item and that it would affect the creature in the slot
+func (s *BattleSide) canUseItem(itemID string, slot int) error {
+	item, err := LookupBagItem(itemID)
+	if err != nil {
+		return err
+	}
+	if s.Bag[itemID] <= 0 {
+		return ErrItemNotInBag
+	}
+	if slot < 0 || slot >= len(s.Team) {
+		return ErrInvalidItemTarget
+	}
+	if !item.affects(s.Team[slot]) {
+		return ErrItemHasNoEffect
+	}
+	return nil
+}
+
+// executeItem consumes a bag item and applies it to the targeted team member
+func (b *Battle) executeItem(sideIdx int, action *Action) {
+	side := b.Sides[sideIdx]
+	if err := side.canUseItem(action.ItemID, action.ItemSlot); err != nil {
+		return
+	}
+	item, _ := LookupBagItem(action.ItemID)
+	target := side.Team[action.ItemSlot]
+	side.Bag[item.ID]--
+	if side.ItemsUsed == nil {
+		side.ItemsUsed = make(Bag)
+	}
+	side.ItemsUsed[item.ID]++
+
+	event := BattleEvent{Type: EventItemUsed, Actor: side.PlayerID, Target: target.ID, ItemID: item.ID}
+	switch {
+	case item.Revive:
+		target.CurrentHP = max(target.MaxHP()/reviveHPDivisor, 1)
+		event.Healed = target.CurrentHP
+	case item.CureStatus:
+		event.Status = string(target.Status)
+		target.Status = StatusNone
+		target.ToxicCounter = 0
+	default:
+		event.Healed = target.Heal(item.Heal)
+	}
+	b.emit(event)
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newBagBattle creates a battle where player-1 has a bench creature and a bag of every item
+func newBagBattle(t *testing.T) *Battle {
+	t.Helper()
+	side1 := NewBattleSide("player-1", []*Creature{
+		newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle"),
+		newTestCreature(t, "bench", []Type{TypeNormal}, 50, "tackle"),
+	})
+	side1.Bag = Bag{"potion": 1, "full-heal": 1, "revive": 1}
+	side2 := NewBattleSide("player-2", []*Creature{newTestCreature(t, "foe", []Type{TypeNormal}, 40, "growl")})
+	return NewBattle("battle-1", side1, side2, &scriptedRNG{fallback: 15})
+}
+
+// useItem submits an item for player-1 and a Growl for player-2, then resolves the turn
+func useItem(t *testing.T, b *Battle, itemID string, slot int) []BattleEvent {
+	t.Helper()
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindItem, ItemID: itemID, ItemSlot: slot}); err != nil {
+		t.Fatalf("item %s: %v", itemID, err)
+	}
+	if err := b.SubmitAction("player-2", Action{Kind: ActionKindMove, MoveID: "growl"}); err != nil {
+		t.Fatalf("player-2 action: %v", err)
+	}
+	events, err := b.ResolveTurn()
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	return events
+}
+
+// ========================================
+// Item Bag Tests
+// ========================================
+
+func TestBag_PotionHealsAndConsumesTurn(t *testing.T) {
+	b := newBagBattle(t)
+	lead := b.Sides[0].Active()
+	lead.CurrentHP = lead.MaxHP() - 50
+
+	events := useItem(t, b, "potion", 0)
+
+	if events[0].Type != EventItemUsed || events[0].ItemID != "potion" || events[0].Healed != 20 {
+		t.Fatalf("expected item_used healing 20 first, got %+v", events[0])
+	}
+	if len(findEvents(events, EventMoveUsed)) != 1 {
+		t.Error("expected only the opponent to use a move")
+	}
+	if lead.CurrentHP != lead.MaxHP()-30 {
+		t.Errorf("expected lead at %d HP, got %d", lead.MaxHP()-30, lead.CurrentHP)
+	}
+	if b.Sides[0].Bag["potion"] != 0 {
+		t.Errorf("expected potion to be consumed, %d left", b.Sides[0].Bag["potion"])
+	}
+	if b.Sides[0].ItemsUsed["potion"] != 1 {
+		t.Errorf("expected the potion to be recorded as used, got %v", b.Sides[0].ItemsUsed)
+	}
+}
+
+func TestBag_ReviveRestoresHalfHP(t *testing.T) {
+	b := newBagBattle(t)
+	bench := b.Sides[0].Team[1]
+	bench.CurrentHP = 0
+
+	events := useItem(t, b, "revive", 1)
+
+	if bench.CurrentHP != bench.MaxHP()/2 {
+		t.Errorf("expected revived creature at %d HP, got %d", bench.MaxHP()/2, bench.CurrentHP)
+	}
+	if events[0].Target != "bench" || events[0].Healed != bench.CurrentHP {
+		t.Errorf("unexpected item_used event: %+v", events[0])
+	}
+}
+
+func TestBag_FullHealCuresStatus(t *testing.T) {
+	b := newBagBattle(t)
+	lead := b.Sides[0].Active()
+	lead.Status = StatusBadPoison
+	lead.ToxicCounter = 2
+
+	events := useItem(t, b, "full-heal", 0)
+
+	if lead.Status != StatusNone || lead.ToxicCounter != 0 {
+		t.Errorf("expected status cured, got %q (counter %d)", lead.Status, lead.ToxicCounter)
+	}
+	if events[0].Status != string(StatusBadPoison) {
+		t.Errorf("expected event to report the cured status, got %q", events[0].Status)
+	}
+}
+
+func TestBag_InvalidUses(t *testing.T) {
+	tests := []struct {
+		name   string
+		itemID string
+		slot   int
+		want   error
+	}{
+		{"unknown item", "master-ball", 0, ErrUnknownBagItem},
+		{"not in bag", "hyper-potion", 0, ErrItemNotInBag},
+		{"slot out of range", "potion", 2, ErrInvalidItemTarget},
+		{"potion at full HP", "potion", 0, ErrItemHasNoEffect},
+		{"revive on conscious creature", "revive", 1, ErrItemHasNoEffect},
+		{"full heal without status", "full-heal", 0, ErrItemHasNoEffect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBagBattle(t)
+			err := b.SubmitAction("player-1", Action{Kind: ActionKindItem, ItemID: tt.itemID, ItemSlot: tt.slot})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBag_PotionOnFaintedCreatureRejected(t *testing.T) {
+	b := newBagBattle(t)
+	b.Sides[0].Team[1].CurrentHP = 0
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindItem, ItemID: "potion", ItemSlot: 1})
+	if !errors.Is(err, ErrItemHasNoEffect) {
+		t.Errorf("expected ErrItemHasNoEffect, got %v", err)
+	}
+}
+
+func TestBag_ItemsGoBeforeMoves(t *testing.T) {
+	b := newBagBattle(t)
+	b.Sides[1].Active().Stats.Speed = 500
+	b.Sides[0].Active().CurrentHP--
+
+	events := useItem(t, b, "potion", 0)
+
+	if events[0].Type != EventItemUsed {
+		t.Errorf("expected item to resolve before the faster opponent's move, got %s", events[0].Type)
+	}
+}
+
+func TestBag_CloneIsIndependent(t *testing.T) {
+	bag := Bag{"potion": 2}
+	clone := bag.Clone()
+	clone["potion"]--
+
+	if bag["potion"] != 2 {
+		t.Errorf("expected original bag untouched, got %d", bag["potion"])
+	}
+	if Bag(nil).Clone() != nil {
+		t.Error("expected nil bag to clone to nil")
+	}
+}
@@ -0,0 +1,390 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// Battle domain errors
+var (
+	ErrPlayerNotInBattle      = errors.New("player not in battle")
+	ErrActionAlreadySubmitted = errors.New("action already submitted this turn")
+	ErrMoveNotKnown           = errors.New("active creature does not know that move")
+	ErrActionsPending         = errors.New("not all players have submitted an action")
+	ErrInvalidSwitchTarget    = errors.New("invalid switch target")
+	ErrNoPPLeft               = errors.New("move has no PP left")
+)
+
+// RNG is the source of randomness used for every random battle decision
+type RNG interface {
+	Intn(n int) int
+}
+
+// ActionKind identifies the kind of action a player chose for a turn
+type ActionKind int
+
+const (
+	ActionKindMove ActionKind = iota
+	ActionKindSwitch
+	ActionKindItem
+)
+
+// Action is a player's choice for a turn
+type Action struct {
+	Kind       ActionKind
+	MoveID     string // For ActionKindMove
+	SwitchSlot int    // For ActionKindSwitch
+	ItemID     string // For ActionKindItem
+	ItemSlot   int    // For ActionKindItem, the team slot the item is used on
+}
+
+// BattleSide is one player's half of the battle
+type BattleSide struct {
+	PlayerID   string
+	Team       []*Creature
+	ActiveSlot int
+	Hazards    Hazards
+	Bag        Bag // Consumable items, only under rulesets that provide a bag
+	ItemsUsed  Bag // Bag items used so far, which the opponent has seen
+}
+
+// NewBattleSide creates a side with the first team member active
+func NewBattleSide(playerID string, team []*Creature) *BattleSide {
+	return &BattleSide{
+		PlayerID: playerID,
+		Team:     team,
+	}
+}
+
+// Active returns the creature currently on the field
+func (s *BattleSide) Active() *Creature {
+	return s.Team[s.ActiveSlot]
+}
+
+// CanSwitchTo checks if the creature in the given slot can be brought onto the field
+func (s *BattleSide) CanSwitchTo(slot int) error {
+	if slot < 0 || slot >= len(s.Team) || slot == s.ActiveSlot || s.Team[slot].IsFainted() {
+		return ErrInvalidSwitchTarget
+	}
+	return nil
+}
+
+// Switch brings the creature in the given slot onto the field.
+// The outgoing creature loses all volatile conditions and stat stages.
+func (s *BattleSide) Switch(slot int) error {
+	if err := s.CanSwitchTo(slot); err != nil {
+		return err
+	}
+	s.Active().Appeared = true
+	s.Active().leaveField()
+	s.ActiveSlot = slot
+	return nil
+}
+
+// RevealedSlots returns the team slots the opponent has seen on the field, in team order
+func (s *BattleSide) RevealedSlots() []int {
+	var slots []int
+	for i, c := range s.Team {
+		if c.Appeared || i == s.ActiveSlot {
+			slots = append(slots, i)
+		}
+	}
+	return slots
+}
+
+// Battle holds the authoritative state of a two-player battle.
+// A Battle is not safe for concurrent use; the battle service runs each game on its own goroutine.
+type Battle struct {
+	ID    string
+	Sides [2]*BattleSide
+	Turn  int
+	Field Field
+	// Seed is the RNG seed for battles created with NewSeededBattle; zero when the RNG was supplied directly
+	Seed int64
+	// Data is the game data the battle resolves against, the current data pack unless replaced before the first turn
+	Data *DataPack
+
+	rng      RNG
+	seedSalt string // Salt of the seed commitment, see SeedCommitment
+	pending  [2]*Action
+	outcome  *BattleOutcome
+
+	// Team preview state, see StartTeamPreview
+	preview    bool
+	leadChosen [2]bool
+
+	// Turn limit and endless battle clause state, see checkStalemate
+	turnLimit    int
+	stalledTurns int
+	lastHP       int
+	lastFainted  int
+
+	// Player with an unanswered draw offer, see OfferDraw
+	drawOffer string
+
+	// Pause state, see RequestPause
+	pauseRequest string
+	pause        *Pause
+	pauseUsed    [2]time.Duration
+
+	// Post-game summary stats, see Stats
+	stats [2]sideStats
+
+	// Per-turn resolution state
+	events []BattleEvent
+	acted  [2]bool
+}
+
+// NewBattle creates a battle between two sides, starting at turn 1
+func NewBattle(id string, side1, side2 *BattleSide, rng RNG) *Battle {
+	b := &Battle{
+		ID:    id,
+		Sides: [2]*BattleSide{side1, side2},
+		Turn:  1,
+		Data:  CurrentDataPack(),
+		rng:   rng,
+	}
+	b.lastHP, b.lastFainted = b.progress()
+	return b
+}
+
+// sideIndex returns the index of the player's side
+func (b *Battle) sideIndex(playerID string) (int, error) {
+	for i, side := range b.Sides {
+		if side.PlayerID == playerID {
+			return i, nil
+		}
+	}
+	return -1, ErrPlayerNotInBattle
+}
+
+// HasPlayer returns true if the player has a side in the battle
+func (b *Battle) HasPlayer(playerID string) bool {
+	_, err := b.sideIndex(playerID)
+	return err == nil
+}
+
+// SubmitAction records a player's action for the current turn once ValidateAction accepts it
+func (b *Battle) SubmitAction(playerID string, action Action) error {
+	if err := b.ValidateAction(playerID, action); err != nil {
+		return err
+	}
+
+	idx, _ := b.sideIndex(playerID)
+	if action.Kind == ActionKindMove && !b.Sides[idx].Active().HasUsableMove() {
+		// Every move is exhausted, so the creature is forced to Struggle
+		action.MoveID = StruggleMoveID
+	}
+
+	b.pending[idx] = &action
+	return nil
+}
+
+// SubmittedAction returns the action a player has chosen for the current turn, if any
+func (b *Battle) SubmittedAction(playerID string) (Action, bool) {
+	idx, err := b.sideIndex(playerID)
+	if err != nil || b.pending[idx] == nil {
+		return Action{}, false
+	}
+	return *b.pending[idx], true
+}
+
+// CurrentTurn returns the turn awaiting actions
+func (b *Battle) CurrentTurn() int {
+	return b.Turn
+}
+
+// AllActionsSubmitted returns true once both players have chosen an action
+func (b *Battle) AllActionsSubmitted() bool {
+	return b.pending[0] != nil && b.pending[1] != nil
+}
+
+// ResolveTurn executes the submitted actions and returns the turn events.
+// Event order numbers follow the resolution order of the actions.
+func (b *Battle) ResolveTurn() ([]BattleEvent, error) {
+	if b.pending[0] == nil || b.pending[1] == nil {
+		return nil, ErrActionsPending
+	}
+
+	b.events = nil
+	b.acted = [2]bool{}
+
+	for _, idx := range b.actionOrder() {
+		switch b.pending[idx].Kind {
+		case ActionKindSwitch:
+			b.executeSwitch(idx, b.pending[idx])
+		case ActionKindItem:
+			b.executeItem(idx, b.pending[idx])
+		default:
+			b.executeMove(idx, b.pending[idx])
+		}
+		b.acted[idx] = true
+	}
+
+	b.endOfTurn()
+	b.recordTurnSurvived()
+	b.checkVictory()
+	b.checkStalemate()
+
+	events := b.events
+	b.events = nil
+	b.pending = [2]*Action{}
+	b.drawOffer = ""
+	b.Turn++
+
+	return events, nil
+}
+
+// executeSwitch resolves a voluntary switch for a side
+func (b *Battle) executeSwitch(sideIdx int, action *Action) {
+	side := b.Sides[sideIdx]
+	from := side.ActiveSlot
+	if err := side.Switch(action.SwitchSlot); err != nil {
+		return
+	}
+	b.emit(BattleEvent{Type: EventCreatureSwitched, Actor: side.PlayerID, Target: side.Active().ID, FromSlot: from, ToSlot: side.ActiveSlot})
+	b.applyEntryHazards(sideIdx)
+}
+
+// executeMove resolves a single move action for a side
+func (b *Battle) executeMove(sideIdx int, action *Action) {
+	side := b.Sides[sideIdx]
+	attacker := side.Active()
+	if attacker.IsFainted() {
+		return
+	}
+
+	move := struggleMove
+	if action.MoveID != StruggleMoveID {
+		move, _ = attacker.FindMove(action.MoveID)
+	}
+	actor := side.PlayerID
+
+	if b.checkFlinch(actor, attacker, move) {
+		return
+	}
+	if b.checkConfusion(actor, attacker) {
+		return
+	}
+
+	attacker.usePP(move.ID)
+	b.emit(BattleEvent{Type: EventMoveUsed, Actor: actor, Target: attacker.ID, MoveID: move.ID})
+
+	if move.TargetsUser() {
+		if !b.applyStatChanges(actor, attacker, move.UserStatChanges) {
+			b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonStatLimit})
+		}
+		return
+	}
+
+	targetIdx := 1 - sideIdx
+
+	// Field moves affect a side of the field rather than the opposing creature
+	if move.Hazard != HazardNone {
+		b.setHazard(actor, targetIdx, move)
+		return
+	}
+	if move.Category == MoveCategoryStatus && move.HazardRemoval != HazardRemovalNone {
+		b.removeHazards(actor, sideIdx, move.HazardRemoval)
+		return
+	}
+	if move.Terrain != TerrainNone {
+		b.setTerrain(actor, move)
+		return
+	}
+
+	defender := b.Sides[targetIdx].Active()
+	if defender.IsFainted() {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonNoTarget})
+		return
+	}
+
+	if !b.checkAccuracy(attacker, defender, move) {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, Target: defender.ID, MoveID: move.ID, Reason: FailReasonMissed})
+		return
+	}
+
+	if move.Category == MoveCategoryStatus {
+		b.executeStatusMove(actor, defender, move)
+		return
+	}
+
+	critical := b.rollCritical(attacker, move)
+	result := b.Data.CalculateDamage(attacker, defender, b.Field.withTerrainPower(attacker, defender, move), rollDamage(b.rng), critical)
+	dealt := defender.TakeDamage(result.Damage)
+	b.emit(BattleEvent{
+		Type:          EventDamageDealt,
+		Actor:         actor,
+		Target:        defender.ID,
+		MoveID:        move.ID,
+		Damage:        dealt,
+		Effectiveness: EffectivenessLabel(result.Effectiveness),
+		Critical:      result.Critical,
+	})
+
+	if result.Effectiveness == 0 {
+		return
+	}
+
+	b.applyStatChanges(actor, attacker, move.UserStatChanges)
+	b.removeHazards(actor, sideIdx, move.HazardRemoval)
+	defenderFainted := b.checkFainted(b.Sides[targetIdx].PlayerID, defender)
+	if move.RecoilDivisor > 0 {
+		b.applyRecoil(actor, attacker, move)
+	}
+	if defenderFainted {
+		return
+	}
+	b.applyStatChanges(actor, defender, move.TargetStatChanges)
+	b.applySecondaryEffects(actor, targetIdx, move)
+}
+
+// applyRecoil deals a fraction of the user's max HP back to the user after a hit
+func (b *Battle) applyRecoil(actor string, attacker *Creature, move *Move) {
+	recoil := attacker.MaxHP() / move.RecoilDivisor
+	if recoil < 1 {
+		recoil = 1
+	}
+	dealt := attacker.TakeDamage(recoil)
+	b.emit(BattleEvent{Type: EventRecoilDamage, Actor: actor, Target: attacker.ID, MoveID: move.ID, Damage: dealt})
+	b.checkFainted(actor, attacker)
+}
+
+// executeStatusMove applies a non-damaging move to the opposing creature
+func (b *Battle) executeStatusMove(actor string, target *Creature, move *Move) {
+	if move.Volatile != VolatileNone {
+		b.applyVolatile(actor, target, move)
+		return
+	}
+	if !b.applyStatChanges(actor, target, move.TargetStatChanges) {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonStatLimit})
+	}
+}
+
+// endOfTurn applies residual effects and clears single-turn conditions
+func (b *Battle) endOfTurn() {
+	for _, idx := range b.speedOrder() {
+		b.drainLeechSeed(idx)
+		b.applyStatusDamage(idx)
+	}
+	b.tickTerrain()
+	for _, side := range b.Sides {
+		side.Active().Volatiles.Flinched = false
+	}
+}
+
+// checkFainted emits a faint event if the creature has no HP left
+func (b *Battle) checkFainted(owner string, creature *Creature) bool {
+	if !creature.IsFainted() {
+		return false
+	}
+	b.emit(BattleEvent{Type: EventCreatureFainted, Actor: owner, Target: creature.ID})
+	return true
+}
+
+// emit appends an event to the current turn, assigning its order
+func (b *Battle) emit(event BattleEvent) {
+	event.Order = len(b.events) + 1
+	b.events = append(b.events, event)
+	b.recordStats(event)
+}
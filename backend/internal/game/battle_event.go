@@ -0,0 +1,75 @@
+package game
+
+// BattleEventType identifies what happened during turn resolution
+type BattleEventType string
+
+const (
+	EventMoveUsed         BattleEventType = "move_used"
+	EventDamageDealt      BattleEventType = "damage_dealt"
+	EventStatusApplied    BattleEventType = "status_applied"
+	EventStatusEnded      BattleEventType = "status_ended"
+	EventCreatureFainted  BattleEventType = "creature_fainted"
+	EventMoveFailed       BattleEventType = "move_failed"
+	EventConfusionSelfHit BattleEventType = "confusion_self_hit"
+	EventLeechSeedDrain   BattleEventType = "leech_seed_drain"
+	EventStatChanged      BattleEventType = "stat_changed"
+	EventCreatureSwitched BattleEventType = "creature_switched"
+	EventRecoilDamage     BattleEventType = "recoil_damage"
+	EventStatusDamage     BattleEventType = "status_damage"
+	EventHazardSet        BattleEventType = "hazard_set"
+	EventHazardDamage     BattleEventType = "hazard_damage"
+	EventHazardCleared    BattleEventType = "hazard_cleared"
+	EventTerrainSet       BattleEventType = "terrain_set"
+	EventTerrainEnded     BattleEventType = "terrain_ended"
+	EventItemUsed         BattleEventType = "item_used"
+)
+
+// Move failure reasons reported in move_failed events
+const (
+	FailReasonFlinched        = "flinched"
+	FailReasonConfused        = "confused"
+	FailReasonAlreadyAffected = "already_affected"
+	FailReasonImmune          = "immune"
+	FailReasonNoTarget        = "no_target"
+	FailReasonStatLimit       = "stat_limit"
+	FailReasonMissed          = "missed"
+	FailReasonTerrain         = "terrain"
+)
+
+// BattleEvent is a single ordered event produced while resolving a turn.
+// Only the fields relevant to the event type are populated.
+type BattleEvent struct {
+	Order  int
+	Type   BattleEventType
+	Actor  string // Player ID whose creature caused the event
+	Target string // ID of the creature affected
+
+	MoveID        string
+	Damage        int
+	Effectiveness string
+	Critical      bool
+	Status        string
+	Reason        string
+	Stat          string
+	Stages        int
+	Secondary     bool // Set on effects triggered by a move's secondary effect chance
+
+	// FromSlot and ToSlot describe a switch
+	FromSlot int
+	ToSlot   int
+
+	// Recipient and Healed describe HP restored to another creature (e.g. leech seed)
+	Recipient string
+	Healed    int
+
+	// Side, Hazard and Layers describe entry hazards; Side is the player ID whose field is affected
+	Side   string
+	Hazard string
+	Layers int
+
+	// Terrain is the terrain set or ended
+	Terrain string
+
+	// ItemID is the bag item used; Healed and Status report what it restored or cured
+	ItemID string
+}
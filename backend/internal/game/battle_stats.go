@@ -0,0 +1,67 @@
+package game
+
+// BattleStats summarises one player's battle for the post-game summary
+type BattleStats struct {
+	DamageDealt   int    // HP the player's moves took from opposing creatures
+	DamageTaken   int    // HP the player's creatures lost, from any source
+	KOs           int    // Opposing creatures that fainted
+	TurnsSurvived int    // Turns the player ended with a creature still standing
+	MostUsedMove  string // Empty if the player never used a move
+}
+
+// sideStats accumulates a player's stats as the battle's events are emitted
+type sideStats struct {
+	BattleStats
+	moveUses map[string]int
+}
+
+// Stats returns the player's stats for the battle so far
+func (b *Battle) Stats(playerID string) (BattleStats, error) {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return BattleStats{}, err
+	}
+
+	stats := b.stats[idx].BattleStats
+	for moveID, uses := range b.stats[idx].moveUses {
+		// Ties go to the alphabetically first move so the summary is deterministic
+		best := b.stats[idx].moveUses[stats.MostUsedMove]
+		if uses > best || (uses == best && moveID < stats.MostUsedMove) {
+			stats.MostUsedMove = moveID
+		}
+	}
+	return stats, nil
+}
+
+// recordStats adds an emitted event to the stats of the players it concerns
+func (b *Battle) recordStats(event BattleEvent) {
+	idx, err := b.sideIndex(event.Actor)
+	if err != nil {
+		return
+	}
+
+	switch event.Type {
+	case EventMoveUsed:
+		if b.stats[idx].moveUses == nil {
+			b.stats[idx].moveUses = make(map[string]int)
+		}
+		b.stats[idx].moveUses[event.MoveID]++
+	case EventDamageDealt:
+		b.stats[idx].DamageDealt += event.Damage
+		b.stats[1-idx].DamageTaken += event.Damage
+	case EventRecoilDamage, EventStatusDamage, EventHazardDamage, EventConfusionSelfHit, EventLeechSeedDrain:
+		// The actor of these events is the owner of the creature that lost HP
+		b.stats[idx].DamageTaken += event.Damage
+	case EventCreatureFainted:
+		b.stats[1-idx].KOs++
+	}
+}
+
+// recordTurnSurvived counts the resolved turn for each player with a creature still standing
+func (b *Battle) recordTurnSurvived() {
+	for i, side := range b.Sides {
+		if !side.AllFainted() {
+			b.stats[i].TurnsSurvived++
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package game
+
+import "testing"
+
+func TestStats_DamageAndMoves(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{fallback: 15}, newTestCreature(t, "a", []Type{TypeNormal}, 60, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	dealt := map[string]int{}
+	for _, e := range events {
+		if e.Type == EventDamageDealt {
+			dealt[e.Actor] += e.Damage
+		}
+	}
+	stats1, _ := b.Stats("player-1")
+	stats2, _ := b.Stats("player-2")
+	if stats1.DamageDealt != dealt["player-1"] || stats2.DamageTaken != dealt["player-1"] {
+		t.Errorf("expected player-1 to deal %d, got dealt %d / taken %d", dealt["player-1"], stats1.DamageDealt, stats2.DamageTaken)
+	}
+	if stats2.DamageDealt != dealt["player-2"] || stats1.DamageTaken != dealt["player-2"] {
+		t.Errorf("expected player-2 to deal %d, got dealt %d / taken %d", dealt["player-2"], stats2.DamageDealt, stats1.DamageTaken)
+	}
+	if stats1.MostUsedMove != "tackle" || stats1.TurnsSurvived != 1 {
+		t.Errorf("expected tackle used and 1 turn survived, got %+v", stats1)
+	}
+}
+
+func TestStats_KOs(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{fallback: 15}, newTestCreature(t, "a", []Type{TypeNormal}, 60, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.Sides[1].Active().CurrentHP = 1
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	winner, _ := b.Stats("player-1")
+	loser, _ := b.Stats("player-2")
+	if winner.KOs != 1 || loser.KOs != 0 {
+		t.Errorf("expected the KO to be credited to player-1, got %d and %d", winner.KOs, loser.KOs)
+	}
+	if winner.TurnsSurvived != 1 || loser.TurnsSurvived != 0 {
+		t.Errorf("expected only player-1 to survive the turn, got %d and %d", winner.TurnsSurvived, loser.TurnsSurvived)
+	}
+	if loser.MostUsedMove != "" {
+		t.Errorf("expected no move for player-2, who fainted before acting, got %q", loser.MostUsedMove)
+	}
+}
+
+func TestStats_MostUsedMoveTieBreak(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 60, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.stats[0].moveUses = map[string]int{"tackle": 2, "ember": 2, "growl": 1}
+
+	stats, _ := b.Stats("player-1")
+	if stats.MostUsedMove != "ember" {
+		t.Errorf("expected ties to go to the alphabetically first move, got %q", stats.MostUsedMove)
+	}
+	if _, err := b.Stats("nobody"); err == nil {
+		t.Error("expected an error for a player not in the battle")
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Test Helpers
+// ========================================
+
+// scriptedRNG returns queued values in order, then falls back to a default
+type scriptedRNG struct {
+	values   []int
+	fallback int
+}
+
+func (r *scriptedRNG) Intn(n int) int {
+	v := r.fallback
+	if len(r.values) > 0 {
+		v = r.values[0]
+		r.values = r.values[1:]
+	}
+	if v >= n {
+		v = n - 1
+	}
+	return v
+}
+
+// mustMove looks up a catalogue move or fails the test
+func mustMove(t *testing.T, id string) *Move {
+	t.Helper()
+	move, err := LookupMove(id)
+	if err != nil {
+		t.Fatalf("unknown move %q", id)
+	}
+	return move
+}
+
+// newTestCreature creates a level 50 creature with uniform stats
+func newTestCreature(t *testing.T, id string, types []Type, speed int, moveIDs ...string) *Creature {
+	t.Helper()
+	moves := make([]*Move, len(moveIDs))
+	for i, moveID := range moveIDs {
+		moves[i] = mustMove(t, moveID)
+	}
+	stats := Stats{HP: 160, Attack: 100, Defense: 100, SpAttack: 100, SpDefense: 100, Speed: speed}
+	return NewCreature(id, id, 50, types, stats, moves)
+}
+
+// newTestBattle creates a battle with one creature per side
+func newTestBattle(rng RNG, c1, c2 *Creature) *Battle {
+	return NewBattle("battle-1", NewBattleSide("player-1", []*Creature{c1}), NewBattleSide("player-2", []*Creature{c2}), rng)
+}
+
+// resolveTurn submits both move actions and resolves the turn
+func resolveTurn(t *testing.T, b *Battle, move1, move2 string) []BattleEvent {
+	t.Helper()
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: move1}); err != nil {
+		t.Fatalf("player-1 submit failed: %v", err)
+	}
+	if err := b.SubmitAction("player-2", Action{Kind: ActionKindMove, MoveID: move2}); err != nil {
+		t.Fatalf("player-2 submit failed: %v", err)
+	}
+	events, err := b.ResolveTurn()
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	return events
+}
+
+// findEvents returns all events of the given type
+func findEvents(events []BattleEvent, eventType BattleEventType) []BattleEvent {
+	var found []BattleEvent
+	for _, e := range events {
+		if e.Type == eventType {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// ========================================
+// Happy Path Tests
+// ========================================
+
+func TestNewBattle_StartsAtTurnOne(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	if b.Turn != 1 {
+		t.Errorf("expected turn 1, got %d", b.Turn)
+	}
+	if b.AllActionsSubmitted() {
+		t.Error("expected no actions submitted")
+	}
+}
+
+func TestResolveTurn_FasterCreatureMovesFirst(t *testing.T) {
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, slow, fast)
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	used := findEvents(events, EventMoveUsed)
+	if len(used) != 2 {
+		t.Fatalf("expected 2 move_used events, got %d", len(used))
+	}
+	if used[0].Actor != "player-2" {
+		t.Errorf("expected faster player-2 to move first, got %s", used[0].Actor)
+	}
+	if b.Turn != 2 {
+		t.Errorf("expected turn 2 after resolution, got %d", b.Turn)
+	}
+}
+
+func TestResolveTurn_EventOrderIsSequential(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{fallback: 15}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 60, "tackle"))
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	for i, e := range events {
+		if e.Order != i+1 {
+			t.Errorf("expected event %d to have order %d, got %d", i, i+1, e.Order)
+		}
+	}
+}
+
+func TestSwitch_ClearsVolatiles(t *testing.T) {
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle")
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 50, "tackle")
+	side := NewBattleSide("player-1", []*Creature{lead, bench})
+
+	lead.Volatiles.ConfusionTurns = 3
+	lead.Volatiles.LeechSeeded = true
+
+	if err := side.Switch(1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if side.Active() != bench {
+		t.Error("expected bench creature to be active")
+	}
+	if lead.Volatiles != (Volatiles{}) {
+		t.Errorf("expected volatiles cleared on switch out, got %+v", lead.Volatiles)
+	}
+}
+
+func TestSwitch_RevealsOutgoingCreature(t *testing.T) {
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle")
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 50, "tackle")
+	reserve := newTestCreature(t, "reserve", []Type{TypeNormal}, 50, "tackle")
+	side := NewBattleSide("player-1", []*Creature{lead, bench, reserve})
+
+	if slots := side.RevealedSlots(); len(slots) != 1 || slots[0] != 0 {
+		t.Fatalf("expected only the lead to be revealed, got %v", slots)
+	}
+
+	side.Switch(1)
+
+	if slots := side.RevealedSlots(); len(slots) != 2 || slots[0] != 0 || slots[1] != 1 {
+		t.Errorf("expected the lead and the incoming creature to be revealed, got %v", slots)
+	}
+}
+
+// ========================================
+// Error Case Tests
+// ========================================
+
+func TestSubmitAction_PlayerNotInBattle(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	err := b.SubmitAction("stranger", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	if !errors.Is(err, ErrPlayerNotInBattle) {
+		t.Errorf("expected ErrPlayerNotInBattle, got %v", err)
+	}
+}
+
+func TestSubmitAction_UnknownMove(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "surf"})
+	if !errors.Is(err, ErrMoveNotKnown) {
+		t.Errorf("expected ErrMoveNotKnown, got %v", err)
+	}
+}
+
+func TestSubmitAction_AlreadySubmitted(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	if !errors.Is(err, ErrActionAlreadySubmitted) {
+		t.Errorf("expected ErrActionAlreadySubmitted, got %v", err)
+	}
+}
+
+func TestResolveTurn_ActionsPending(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+
+	_, err := b.ResolveTurn()
+	if !errors.Is(err, ErrActionsPending) {
+		t.Errorf("expected ErrActionsPending, got %v", err)
+	}
+}
+
+func TestSwitch_InvalidTargets(t *testing.T) {
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle")
+	fainted := newTestCreature(t, "fainted", []Type{TypeNormal}, 50, "tackle")
+	fainted.CurrentHP = 0
+	side := NewBattleSide("player-1", []*Creature{lead, fainted})
+
+	for _, slot := range []int{-1, 0, 1, 2} {
+		if err := side.Switch(slot); !errors.Is(err, ErrInvalidSwitchTarget) {
+			t.Errorf("slot %d: expected ErrInvalidSwitchTarget, got %v", slot, err)
+		}
+	}
+}
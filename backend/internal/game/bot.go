@@ -0,0 +1,100 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Bot errors
+var (
+	ErrBotInDraftLobby      = errors.New("bots cannot play in draft lobbies")
+	ErrUnknownBotDifficulty = errors.New("unknown bot difficulty")
+)
+
+// BotUsername is the display name of bot players
+const BotUsername = "Bot"
+
+// BotPlayerID returns the ID of the bot that fills a lobby's second slot
+func BotPlayerID(lobbyCode string) string {
+	return "bot-" + lobbyCode
+}
+
+// BotDifficulty selects the policy a bot plays with
+type BotDifficulty string
+
+const (
+	BotDifficultyRandom    BotDifficulty = "random"    // Any legal move or switch
+	BotDifficultyGreedy    BotDifficulty = "greedy"    // Best damage this turn, switching out of type disadvantage
+	BotDifficultyLookahead BotDifficulty = "lookahead" // Weighs each option against the opponent's strongest reply
+)
+
+// DefaultBotDifficulty is used when a bot is added without choosing a difficulty
+const DefaultBotDifficulty = BotDifficultyGreedy
+
+// BotView is what a policy sees when making a choice: the bot's side and the opposing side.
+// Policies must not modify the view or keep it after returning.
+type BotView struct {
+	Side *BattleSide
+	Foe  *BattleSide
+}
+
+// BotPolicy is a strategy for a bot's battle choices
+type BotPolicy interface {
+	// ChooseAction picks the bot's action for the turn
+	ChooseAction(view BotView) Action
+	// ChooseReplacement picks the team slot sent in after the active creature faints.
+	// The view always has at least one switch target.
+	ChooseReplacement(view BotView) int
+}
+
+// botPolicies builds the policy for each difficulty; adding an entry offers a new difficulty
+var botPolicies = map[BotDifficulty]func() BotPolicy{
+	BotDifficultyRandom: func() BotPolicy {
+		// The bot draws from its own RNG so seeded battles still replay from their actions alone
+		return randomPolicy{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	},
+	BotDifficultyGreedy:    func() BotPolicy { return greedyPolicy{} },
+	BotDifficultyLookahead: func() BotPolicy { return lookaheadPolicy{} },
+}
+
+// NewBotPolicy returns the policy a bot of the given difficulty plays with
+func NewBotPolicy(difficulty BotDifficulty) (BotPolicy, error) {
+	newPolicy, ok := botPolicies[difficulty]
+	if !ok {
+		return nil, ErrUnknownBotDifficulty
+	}
+	return newPolicy(), nil
+}
+
+// ListBotDifficulties returns every bot difficulty, sorted by name
+func ListBotDifficulties() []BotDifficulty {
+	difficulties := make([]BotDifficulty, 0, len(botPolicies))
+	for d := range botPolicies {
+		difficulties = append(difficulties, d)
+	}
+	sort.Slice(difficulties, func(i, j int) bool { return difficulties[i] < difficulties[j] })
+	return difficulties
+}
+
+// BotAction asks a policy for a bot's action for the current turn
+func (b *Battle) BotAction(playerID string, policy BotPolicy) (Action, error) {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return Action{}, err
+	}
+	return policy.ChooseAction(BotView{Side: b.Sides[idx], Foe: b.Sides[1-idx]}), nil
+}
+
+// BotSwitchSlot asks a policy for the creature a bot sends in to replace a fainted one
+func (b *Battle) BotSwitchSlot(playerID string, policy BotPolicy) (int, error) {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return -1, err
+	}
+	if len(b.Sides[idx].SwitchTargets()) == 0 {
+		return -1, ErrNoSwitchAvailable
+	}
+	return policy.ChooseReplacement(BotView{Side: b.Sides[idx], Foe: b.Sides[1-idx]}), nil
+}
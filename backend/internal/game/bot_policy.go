@@ -0,0 +1,204 @@
+package game
+
+// averageDamageRoll is the damage roll policies use to estimate a move's damage
+const averageDamageRoll = (damageRollMin + damageRollMax) / 2
+
+// randomPolicy picks uniformly among every legal move and switch
+type randomPolicy struct {
+	rng RNG
+}
+
+func (p randomPolicy) ChooseAction(v BotView) Action {
+	var options []Action
+	for _, m := range usableMoves(v.Side.Active()) {
+		options = append(options, Action{Kind: ActionKindMove, MoveID: m.ID})
+	}
+	for _, slot := range v.Side.SwitchTargets() {
+		options = append(options, Action{Kind: ActionKindSwitch, SwitchSlot: slot})
+	}
+	return options[p.rng.Intn(len(options))]
+}
+
+func (p randomPolicy) ChooseReplacement(v BotView) int {
+	slots := v.Side.SwitchTargets()
+	return slots[p.rng.Intn(len(slots))]
+}
+
+// greedyPolicy switches out of a type disadvantage when a safer creature is on the bench,
+// and otherwise uses the move expected to deal the most damage
+type greedyPolicy struct{}
+
+func (greedyPolicy) ChooseAction(v BotView) Action {
+	active, foe := v.Side.Active(), v.Foe.Active()
+
+	move, damage := bestMove(active, foe)
+	if threat(foe, active) > 1 && damage.Effectiveness <= 1 {
+		if slot, ok := saferSwitch(v.Side, foe); ok {
+			return Action{Kind: ActionKindSwitch, SwitchSlot: slot}
+		}
+	}
+	return Action{Kind: ActionKindMove, MoveID: move.ID}
+}
+
+// ChooseReplacement sends in the creature least threatened by the opposing creature,
+// preferring the one that hits it hardest
+func (greedyPolicy) ChooseReplacement(v BotView) int {
+	foe := v.Foe.Active()
+
+	best, bestThreat, bestDamage := -1, 0.0, 0
+	for _, slot := range v.Side.SwitchTargets() {
+		c := v.Side.Team[slot]
+		t := threat(foe, c)
+		_, damage := bestMove(c, foe)
+		if best == -1 || t < bestThreat || (t == bestThreat && damage.Damage > bestDamage) {
+			best, bestThreat, bestDamage = slot, t, damage.Damage
+		}
+	}
+	return best
+}
+
+// lookaheadPolicy looks one turn ahead: every move and switch is scored by the damage it trades
+// against the opponent's strongest reply, accounting for knockouts before the slower side acts
+type lookaheadPolicy struct{}
+
+func (lookaheadPolicy) ChooseAction(v BotView) Action {
+	active, foe := v.Side.Active(), v.Foe.Active()
+	reply, _ := bestMove(foe, active)
+
+	var best Action
+	bestScore := 0.0
+	for i, m := range usableMoves(active) {
+		dealt, taken := expectedDamage(active, foe, m), expectedDamage(foe, active, reply)
+		if movesFirst(active, m, foe, reply) {
+			if dealt >= foe.CurrentHP {
+				taken = 0
+			}
+		} else if taken >= active.CurrentHP {
+			dealt = 0
+		}
+		score := hpValue(foe, dealt) - hpValue(active, taken)
+		if i == 0 || score > bestScore {
+			best, bestScore = Action{Kind: ActionKindMove, MoveID: m.ID}, score
+		}
+	}
+
+	// Switches go first, so the incoming creature takes the reply without dealing damage
+	for _, slot := range v.Side.SwitchTargets() {
+		c := v.Side.Team[slot]
+		if score := -hpValue(c, expectedDamage(foe, c, bestReply(foe, c))); score > bestScore {
+			best, bestScore = Action{Kind: ActionKindSwitch, SwitchSlot: slot}, score
+		}
+	}
+	return best
+}
+
+// ChooseReplacement sends in the creature with the best damage trade against the opposing creature
+func (lookaheadPolicy) ChooseReplacement(v BotView) int {
+	foe := v.Foe.Active()
+
+	best, bestScore := -1, 0.0
+	for _, slot := range v.Side.SwitchTargets() {
+		c := v.Side.Team[slot]
+		move, _ := bestMove(c, foe)
+		score := hpValue(foe, expectedDamage(c, foe, move)) - hpValue(c, expectedDamage(foe, c, bestReply(foe, c)))
+		if best == -1 || score > bestScore {
+			best, bestScore = slot, score
+		}
+	}
+	return best
+}
+
+// usableMoves returns the moves with PP left, or Struggle once every move is exhausted
+func usableMoves(c *Creature) []*Move {
+	var moves []*Move
+	for _, m := range c.Moves {
+		if c.RemainingPP(m.ID) > 0 {
+			moves = append(moves, m)
+		}
+	}
+	if len(moves) == 0 {
+		return []*Move{struggleMove}
+	}
+	return moves
+}
+
+// bestMove returns the usable move with the highest accuracy-weighted damage
+func bestMove(attacker, defender *Creature) (*Move, DamageResult) {
+	var best *Move
+	var bestResult DamageResult
+	bestScore := -1.0
+	for _, m := range usableMoves(attacker) {
+		result := CalculateDamage(attacker, defender, m, damageRollMax, false)
+		score := float64(result.Damage) * hitChance(m)
+		if score > bestScore {
+			best, bestResult, bestScore = m, result, score
+		}
+	}
+	return best, bestResult
+}
+
+// bestReply returns the move the attacker would use against the defender
+func bestReply(attacker, defender *Creature) *Move {
+	move, _ := bestMove(attacker, defender)
+	return move
+}
+
+// expectedDamage estimates the damage a move deals with an average roll, weighted by accuracy
+func expectedDamage(attacker, defender *Creature, move *Move) int {
+	if move == nil {
+		return 0
+	}
+	result := CalculateDamage(attacker, defender, move, averageDamageRoll, false)
+	return int(float64(result.Damage) * hitChance(move))
+}
+
+// hitChance returns a move's chance to hit as a fraction; moves with no accuracy never miss
+func hitChance(m *Move) float64 {
+	if m.Accuracy == 0 {
+		return 1
+	}
+	return float64(m.Accuracy) / 100
+}
+
+// movesFirst reports whether the attacker's move resolves before the defender's reply.
+// Speed ties count against the attacker.
+func movesFirst(attacker *Creature, move *Move, defender *Creature, reply *Move) bool {
+	replyPriority := 0
+	if reply != nil {
+		replyPriority = reply.Priority
+	}
+	if move.Priority != replyPriority {
+		return move.Priority > replyPriority
+	}
+	return attacker.EffectiveStat(StatSpeed) > defender.EffectiveStat(StatSpeed)
+}
+
+// hpValue scores damage as the fraction of max HP lost, with a knockout worth an extra full bar
+func hpValue(c *Creature, damage int) float64 {
+	if damage >= c.CurrentHP {
+		return float64(c.CurrentHP)/float64(c.MaxHP()) + 1
+	}
+	return float64(damage) / float64(c.MaxHP())
+}
+
+// threat returns the highest effectiveness of the attacker's types against the defender,
+// a stand-in for the attacker's same-type moves
+func threat(attacker, defender *Creature) float64 {
+	highest := 0.0
+	for _, t := range attacker.Types {
+		highest = max(highest, Effectiveness(t, defender.Types))
+	}
+	return highest
+}
+
+// saferSwitch returns the bench creature the foe threatens least, if it is not at a disadvantage itself
+func saferSwitch(side *BattleSide, foe *Creature) (int, bool) {
+	best, bestThreat := -1, 0.0
+	for _, slot := range side.SwitchTargets() {
+		t := threat(foe, side.Team[slot])
+		if best == -1 || t < bestThreat {
+			best, bestThreat = slot, t
+		}
+	}
+	return best, best != -1 && bestThreat <= 1
+}
@@ -0,0 +1,276 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newBotBattle creates a battle where the bot (player-2) has the given team against a single foe
+func newBotBattle(foe *Creature, team ...*Creature) *Battle {
+	return NewBattle("battle-1", NewBattleSide("player-1", []*Creature{foe}), NewBattleSide("player-2", team), &scriptedRNG{fallback: 15})
+}
+
+// ========================================
+// Bot Policy Tests
+// ========================================
+
+func TestBotAction_PicksBestDamage(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "bot", []Type{TypeNormal}, 50, "growl", "tackle", "ice-beam"),
+	)
+
+	action, err := b.BotAction("player-2", greedyPolicy{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if action.Kind != ActionKindMove || action.MoveID != "ice-beam" {
+		t.Errorf("expected super effective ice-beam over STAB tackle, got %+v", action)
+	}
+}
+
+func TestBotAction_SwitchesOutOfDisadvantage(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 50, "flamethrower"),
+		newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "grass-2", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "water", []Type{TypeWater}, 50, "surf"),
+	)
+
+	action, _ := b.BotAction("player-2", greedyPolicy{})
+
+	if action.Kind != ActionKindSwitch || action.SwitchSlot != 2 {
+		t.Errorf("expected a switch to the water type in slot 2, got %+v", action)
+	}
+}
+
+func TestBotAction_StaysInWhenItHitsSuperEffectively(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 50, "flamethrower"),
+		newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle", "surf"),
+		newTestCreature(t, "water", []Type{TypeWater}, 50, "surf"),
+	)
+
+	action, _ := b.BotAction("player-2", greedyPolicy{})
+
+	if action.Kind != ActionKindMove || action.MoveID != "surf" {
+		t.Errorf("expected the bot to stay in and use surf, got %+v", action)
+	}
+}
+
+func TestBotAction_NoSaferSwitch(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 50, "flamethrower"),
+		newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "grass-2", []Type{TypeGrass}, 50, "tackle"),
+	)
+
+	action, _ := b.BotAction("player-2", greedyPolicy{})
+
+	if action.Kind != ActionKindMove {
+		t.Errorf("expected the bot to attack when every switch is also at a disadvantage, got %+v", action)
+	}
+}
+
+func TestBotAction_StrugglesWithoutPP(t *testing.T) {
+	bot := newTestCreature(t, "bot", []Type{TypeNormal}, 50, "tackle")
+	bot.PP["tackle"] = 0
+	b := newBotBattle(newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"), bot)
+
+	action, _ := b.BotAction("player-2", greedyPolicy{})
+
+	if action.MoveID != StruggleMoveID {
+		t.Errorf("expected struggle, got %+v", action)
+	}
+	if err := b.SubmitAction("player-2", action); err != nil {
+		t.Errorf("expected the bot's action to be accepted, got %v", err)
+	}
+}
+
+func TestBotSwitchSlot_LeastThreatened(t *testing.T) {
+	fainted := newTestCreature(t, "fainted", []Type{TypeNormal}, 50, "tackle")
+	fainted.CurrentHP = 0
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 50, "flamethrower"),
+		fainted,
+		newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "water", []Type{TypeWater}, 50, "surf"),
+	)
+
+	slot, err := b.BotSwitchSlot("player-2", greedyPolicy{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if slot != 2 {
+		t.Errorf("expected the water type in slot 2, got %d", slot)
+	}
+}
+
+func TestBotSwitchSlot_NoneAvailable(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"),
+		newTestCreature(t, "bot", []Type{TypeNormal}, 50, "tackle"),
+	)
+
+	if _, err := b.BotSwitchSlot("player-2", greedyPolicy{}); !errors.Is(err, ErrNoSwitchAvailable) {
+		t.Errorf("expected ErrNoSwitchAvailable, got %v", err)
+	}
+}
+
+// ========================================
+// Bot Difficulty Tests
+// ========================================
+
+func TestNewBotPolicy(t *testing.T) {
+	for _, d := range ListBotDifficulties() {
+		if policy, err := NewBotPolicy(d); err != nil || policy == nil {
+			t.Errorf("difficulty %q: expected a policy, got %v", d, err)
+		}
+	}
+	if _, err := NewBotPolicy("impossible"); !errors.Is(err, ErrUnknownBotDifficulty) {
+		t.Errorf("expected ErrUnknownBotDifficulty, got %v", err)
+	}
+}
+
+func TestRandomPolicy_ChoosesAmongMovesAndSwitches(t *testing.T) {
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"),
+		newTestCreature(t, "bot", []Type{TypeNormal}, 50, "tackle", "growl"),
+		newTestCreature(t, "bench", []Type{TypeWater}, 50, "surf"),
+	)
+
+	action, _ := b.BotAction("player-2", randomPolicy{rng: &scriptedRNG{values: []int{1}}})
+	if action.Kind != ActionKindMove || action.MoveID != "growl" {
+		t.Errorf("expected the second move, got %+v", action)
+	}
+
+	action, _ = b.BotAction("player-2", randomPolicy{rng: &scriptedRNG{values: []int{2}}})
+	if action.Kind != ActionKindSwitch || action.SwitchSlot != 1 {
+		t.Errorf("expected a switch to slot 1, got %+v", action)
+	}
+	if err := b.SubmitAction("player-2", action); err != nil {
+		t.Errorf("expected the bot's action to be accepted, got %v", err)
+	}
+}
+
+func TestLookaheadPolicy_TakesPriorityKnockout(t *testing.T) {
+	foe := newTestCreature(t, "foe", []Type{TypeWater}, 200, "surf")
+	foe.CurrentHP = 10
+	bot := newTestCreature(t, "bot", []Type{TypeElectric}, 50, "thunderbolt", "quick-attack")
+	bot.CurrentHP = 5
+	b := newBotBattle(foe, bot)
+
+	if action, _ := b.BotAction("player-2", greedyPolicy{}); action.MoveID != "thunderbolt" {
+		t.Fatalf("expected greedy to pick the strongest move, got %+v", action)
+	}
+
+	action, _ := b.BotAction("player-2", lookaheadPolicy{})
+	if action.Kind != ActionKindMove || action.MoveID != "quick-attack" {
+		t.Errorf("expected quick-attack to knock the foe out before it moves, got %+v", action)
+	}
+}
+
+func TestLookaheadPolicy_SwitchesOutOfKnockout(t *testing.T) {
+	bot := newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle")
+	bot.CurrentHP = 5
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 100, "flamethrower"),
+		bot,
+		newTestCreature(t, "water", []Type{TypeWater}, 50, "surf"),
+	)
+
+	action, _ := b.BotAction("player-2", lookaheadPolicy{})
+
+	if action.Kind != ActionKindSwitch || action.SwitchSlot != 1 {
+		t.Errorf("expected a switch to the water type, got %+v", action)
+	}
+}
+
+func TestLookaheadPolicy_Replacement(t *testing.T) {
+	fainted := newTestCreature(t, "fainted", []Type{TypeNormal}, 50, "tackle")
+	fainted.CurrentHP = 0
+	b := newBotBattle(
+		newTestCreature(t, "foe", []Type{TypeFire}, 50, "flamethrower"),
+		fainted,
+		newTestCreature(t, "grass", []Type{TypeGrass}, 50, "tackle"),
+		newTestCreature(t, "water", []Type{TypeWater}, 50, "surf"),
+	)
+
+	slot, err := b.BotSwitchSlot("player-2", lookaheadPolicy{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if slot != 2 {
+		t.Errorf("expected the water type in slot 2, got %d", slot)
+	}
+}
+
+// ========================================
+// Lobby Bot Tests
+// ========================================
+
+func TestAddBot_FillsLobby(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	bot, err := lobby.AddBot("")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if bot.ID != BotPlayerID("ABC123") || !bot.Bot {
+		t.Errorf("unexpected bot player: %+v", bot)
+	}
+	if !lobby.IsBot(bot.ID) || lobby.IsBot("host-1") {
+		t.Error("expected only the bot to be reported as a bot")
+	}
+	if lobby.GetState() != LobbyStateReady {
+		t.Errorf("expected lobby to be ready, got %s", lobby.GetState())
+	}
+	if !lobby.HasTeam(bot.ID) {
+		t.Error("expected the bot to bring a team")
+	}
+	if bot.BotDifficulty != DefaultBotDifficulty {
+		t.Errorf("expected the default difficulty, got %q", bot.BotDifficulty)
+	}
+
+	if _, err := lobby.AddBot(""); !errors.Is(err, ErrInvalidStateForJoin) {
+		t.Errorf("expected ErrInvalidStateForJoin for a second bot, got %v", err)
+	}
+}
+
+func TestAddBot_UnknownDifficulty(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if _, err := lobby.AddBot("impossible"); !errors.Is(err, ErrUnknownBotDifficulty) {
+		t.Errorf("expected ErrUnknownBotDifficulty, got %v", err)
+	}
+	if lobby.PlayerCount() != 1 {
+		t.Errorf("expected no bot to join, got %d players", lobby.PlayerCount())
+	}
+}
+
+func TestAddBot_DraftLobby(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.SetDraftMode(true)
+
+	if _, err := lobby.AddBot(""); !errors.Is(err, ErrBotInDraftLobby) {
+		t.Errorf("expected ErrBotInDraftLobby, got %v", err)
+	}
+
+	lobby.SetDraftMode(false)
+	lobby.AddBot("")
+	if err := lobby.SetDraftMode(true); !errors.Is(err, ErrBotInDraftLobby) {
+		t.Errorf("expected ErrBotInDraftLobby when enabling draft mode, got %v", err)
+	}
+}
+
+func TestAddBot_LeavesWithLastHuman(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddBot("")
+
+	if _, err := lobby.RemovePlayer("host-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.PlayerCount() != 0 {
+		t.Errorf("expected the bot to leave with the host, %d players remain", lobby.PlayerCount())
+	}
+}
@@ -0,0 +1,150 @@
+package game
+
+// Stats holds a creature's battle stats. HP is the maximum hit points.
+type Stats struct {
+	HP        int
+	Attack    int
+	Defense   int
+	SpAttack  int
+	SpDefense int
+	Speed     int
+}
+
+// Creature is a single team member participating in a battle
+type Creature struct {
+	ID        string
+	SpeciesID string
+	Name      string
+	Nickname  string // Chosen by the player; empty when the creature goes by its species name
+	Shiny     bool
+	Level     int
+	Types     []Type
+	Stats     Stats
+	CurrentHP int
+	Moves     []*Move
+	// PP holds the remaining power points for each known move, keyed by move ID
+	PP map[string]int
+	// Revealed records the known moves the creature has used, which the opponent has seen
+	Revealed map[string]bool
+	// Appeared is set once the creature has left the field, so the opponent knows it is on the team
+	Appeared bool
+
+	// Status persists through switching; ToxicCounter tracks bad poison turns while active
+	Status       StatusCondition
+	ToxicCounter int
+
+	// Volatile conditions and stat stages are cleared whenever the creature leaves the field
+	Volatiles Volatiles
+	Stages    StatStages
+}
+
+// NewCreature creates a creature at full HP
+func NewCreature(id, name string, level int, types []Type, stats Stats, moves []*Move) *Creature {
+	pp := make(map[string]int, len(moves))
+	for _, m := range moves {
+		pp[m.ID] = m.PP
+	}
+	return &Creature{
+		ID:        id,
+		Name:      name,
+		Level:     level,
+		Types:     types,
+		Stats:     stats,
+		CurrentHP: stats.HP,
+		Moves:     moves,
+		PP:        pp,
+		Revealed:  make(map[string]bool, len(moves)),
+	}
+}
+
+// MaxHP returns the creature's maximum hit points
+func (c *Creature) MaxHP() int {
+	return c.Stats.HP
+}
+
+// IsFainted returns true if the creature has no HP left
+func (c *Creature) IsFainted() bool {
+	return c.CurrentHP <= 0
+}
+
+// HasType checks if the creature has the given type
+func (c *Creature) HasType(t Type) bool {
+	for _, ct := range c.Types {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+// FindMove returns the creature's move with the given ID
+func (c *Creature) FindMove(moveID string) (*Move, bool) {
+	for _, m := range c.Moves {
+		if m.ID == moveID {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// RemainingPP returns the PP left for a known move
+func (c *Creature) RemainingPP(moveID string) int {
+	return c.PP[moveID]
+}
+
+// HasUsableMove returns true if at least one known move has PP left
+func (c *Creature) HasUsableMove() bool {
+	for _, m := range c.Moves {
+		if c.PP[m.ID] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// usePP deducts one PP from a known move and reveals it to the opponent
+func (c *Creature) usePP(moveID string) {
+	if c.PP[moveID] > 0 {
+		c.PP[moveID]--
+		c.Revealed[moveID] = true
+	}
+}
+
+// RevealedMoves returns the known moves the creature has used, in moveset order
+func (c *Creature) RevealedMoves() []*Move {
+	var moves []*Move
+	for _, m := range c.Moves {
+		if c.Revealed[m.ID] {
+			moves = append(moves, m)
+		}
+	}
+	return moves
+}
+
+// leaveField resets everything that only lasts while the creature is active
+func (c *Creature) leaveField() {
+	c.Volatiles.Clear()
+	c.Stages = StatStages{}
+	c.ToxicCounter = 0
+}
+
+// TakeDamage reduces HP by amount (never below zero) and returns the damage actually dealt
+func (c *Creature) TakeDamage(amount int) int {
+	if amount > c.CurrentHP {
+		amount = c.CurrentHP
+	}
+	c.CurrentHP -= amount
+	return amount
+}
+
+// Heal restores HP by amount (never above max) and returns the HP actually restored
+func (c *Creature) Heal(amount int) int {
+	if c.IsFainted() {
+		return 0
+	}
+	if missing := c.MaxHP() - c.CurrentHP; amount > missing {
+		amount = missing
+	}
+	c.CurrentHP += amount
+	return amount
+}
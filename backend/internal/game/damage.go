@@ -0,0 +1,118 @@
+package game
+
+// Damage formula configuration
+const (
+	damageRollMin = 85  // Lowest random damage roll, in percent
+	damageRollMax = 100 // Highest random damage roll, in percent
+	stabBonus     = 1.5 // Same-type attack bonus
+)
+
+// DamageResult describes the outcome of a damage calculation
+type DamageResult struct {
+	Damage        int
+	Effectiveness float64
+	Critical      bool
+}
+
+// CalculateDamage computes the damage a move deals to a defender.
+// roll is the random damage factor in percent (damageRollMin..damageRollMax).
+// A critical hit ignores the attacker's negative stages and the defender's positive stages.
+// It uses the current data's type chart.
+func CalculateDamage(attacker, defender *Creature, move *Move, roll int, critical bool) DamageResult {
+	return CurrentDataPack().CalculateDamage(attacker, defender, move, roll, critical)
+}
+
+// CalculateDamage computes the damage a move deals to a defender using the pack's type chart
+func (p *DataPack) CalculateDamage(attacker, defender *Creature, move *Move, roll int, critical bool) DamageResult {
+	if move.Category == MoveCategoryStatus || move.Power == 0 {
+		return DamageResult{Effectiveness: 1}
+	}
+
+	attackStat, defenseStat := StatAttack, StatDefense
+	if move.Category == MoveCategorySpecial {
+		attackStat, defenseStat = StatSpAttack, StatSpDefense
+	}
+
+	attackStage, defenseStage := attacker.Stages.Get(attackStat), defender.Stages.Get(defenseStat)
+	if critical {
+		attackStage, defenseStage = max(attackStage, 0), min(defenseStage, 0)
+	}
+	attack := attacker.statAtStage(attackStat, attackStage)
+	defense := defender.statAtStage(defenseStat, defenseStage)
+
+	effectiveness := p.Effectiveness(move.Type, defender.Types)
+	if effectiveness == 0 {
+		return DamageResult{Effectiveness: 0}
+	}
+
+	damage := float64(baseDamage(attacker.Level, move.Power, attack, defense))
+	if critical {
+		damage *= criticalMultiplier
+	}
+	damage = damage * float64(roll) / 100
+	if attacker.HasType(move.Type) {
+		damage *= stabBonus
+	}
+	if attacker.Status == StatusBurn && move.Category == MoveCategoryPhysical {
+		damage *= burnAttackModifier
+	}
+	damage *= effectiveness
+
+	return DamageResult{
+		Damage:        max(int(damage), 1),
+		Effectiveness: effectiveness,
+		Critical:      critical,
+	}
+}
+
+// baseDamage applies the level/power/stat portion of the damage formula
+func baseDamage(level, power, attack, defense int) int {
+	if defense < 1 {
+		defense = 1
+	}
+	return (((2*level/5)+2)*power*attack/defense)/50 + 2
+}
+
+// rollDamage draws a random damage factor from the battle RNG
+func rollDamage(rng RNG) int {
+	return damageRollMin + rng.Intn(damageRollMax-damageRollMin+1)
+}
+
+// DamageRange describes the damage a move can deal across every damage roll
+type DamageRange struct {
+	Rolls         []int // Damage for each roll, from the lowest roll to the highest
+	Effectiveness float64
+	KOChance      float64 // Share of rolls that knock the defender out from its current HP, 0..1
+}
+
+// Min returns the damage of the lowest roll
+func (r DamageRange) Min() int {
+	return r.Rolls[0]
+}
+
+// Max returns the damage of the highest roll
+func (r DamageRange) Max() int {
+	return r.Rolls[len(r.Rolls)-1]
+}
+
+// CalculateDamageRange runs CalculateDamage for every damage roll, each of which is equally likely.
+// It uses the current data's type chart.
+func CalculateDamageRange(attacker, defender *Creature, move *Move, critical bool) DamageRange {
+	return CurrentDataPack().CalculateDamageRange(attacker, defender, move, critical)
+}
+
+// CalculateDamageRange runs CalculateDamage for every damage roll using the pack's type chart
+func (p *DataPack) CalculateDamageRange(attacker, defender *Creature, move *Move, critical bool) DamageRange {
+	r := DamageRange{Rolls: make([]int, 0, damageRollMax-damageRollMin+1)}
+	knockouts := 0
+	for roll := damageRollMin; roll <= damageRollMax; roll++ {
+		result := p.CalculateDamage(attacker, defender, move, roll, critical)
+		r.Rolls = append(r.Rolls, result.Damage)
+		r.Effectiveness = result.Effectiveness
+		if result.Damage > 0 && result.Damage >= defender.CurrentHP {
+			knockouts++
+		}
+	}
+	r.KOChance = float64(knockouts) / float64(len(r.Rolls))
+	return r
+}
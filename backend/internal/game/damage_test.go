@@ -0,0 +1,52 @@
+package game
+
+import "testing"
+
+func TestCalculateDamageRange_MatchesRolls(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	move := mustMove(t, "tackle")
+
+	r := CalculateDamageRange(attacker, defender, move, false)
+
+	if len(r.Rolls) != damageRollMax-damageRollMin+1 {
+		t.Fatalf("expected one result per roll, got %d", len(r.Rolls))
+	}
+	if r.Min() != CalculateDamage(attacker, defender, move, damageRollMin, false).Damage {
+		t.Errorf("expected min to match the lowest roll, got %d", r.Min())
+	}
+	if r.Max() != CalculateDamage(attacker, defender, move, damageRollMax, false).Damage {
+		t.Errorf("expected max to match the highest roll, got %d", r.Max())
+	}
+	if r.KOChance != 0 {
+		t.Errorf("expected no KO chance against a healthy defender, got %v", r.KOChance)
+	}
+}
+
+func TestCalculateDamageRange_KOChance(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	move := mustMove(t, "tackle")
+	full := CalculateDamageRange(attacker, defender, move, false)
+
+	defender.CurrentHP = full.Min()
+	if r := CalculateDamageRange(attacker, defender, move, false); r.KOChance != 1 {
+		t.Errorf("expected a guaranteed KO at %d HP, got %v", defender.CurrentHP, r.KOChance)
+	}
+
+	defender.CurrentHP = full.Max()
+	if r := CalculateDamageRange(attacker, defender, move, false); r.KOChance <= 0 || r.KOChance >= 1 {
+		t.Errorf("expected only the top rolls to KO at %d HP, got %v", defender.CurrentHP, r.KOChance)
+	}
+}
+
+func TestCalculateDamageRange_StatusMove(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "growl")
+	defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50, "tackle")
+	defender.CurrentHP = 0
+
+	r := CalculateDamageRange(attacker, defender, mustMove(t, "growl"), false)
+	if r.Max() != 0 || r.KOChance != 0 {
+		t.Errorf("expected a status move to deal no damage, got max %d and KO chance %v", r.Max(), r.KOChance)
+	}
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownDataVersion is returned when a data version is not in the registry
+var ErrUnknownDataVersion = errors.New("unknown data version")
+
+// CurrentDataVersion is the data version new battles are played with.
+// Balance updates register a new pack and bump this, leaving older packs in place
+// so battles recorded against them still resolve the same way.
+const CurrentDataVersion = "1"
+
+// DataPack is a versioned release of the species, move and type chart datasets
+type DataPack struct {
+	Version     string
+	Description string
+	Species     map[string]*Species
+	Moves       map[string]*Move
+	TypeChart   map[Type]map[Type]float64
+	Ruleset     string // ID of the ruleset whose type chart the pack carries, see Ruleset.DataPack; empty for a registered pack
+}
+
+// dataPacks holds every data version a battle may have been played with, keyed by version
+var dataPacks = map[string]*DataPack{
+	"1": {
+		Version:     "1",
+		Description: "Initial species, move and type chart data",
+		Species:     speciesCatalogue,
+		Moves:       moveCatalogue,
+		TypeChart:   typeChart,
+	},
+}
+
+// LookupDataPack returns the data pack for a version
+func LookupDataPack(version string) (*DataPack, error) {
+	pack, ok := dataPacks[version]
+	if !ok {
+		return nil, ErrUnknownDataVersion
+	}
+	return pack, nil
+}
+
+// CurrentDataPack returns the data pack new battles are played with
+func CurrentDataPack() *DataPack {
+	return dataPacks[CurrentDataVersion]
+}
+
+// ListDataPacks returns every registered data pack, ordered by version
+func ListDataPacks() []*DataPack {
+	packs := make([]*DataPack, 0, len(dataPacks))
+	for _, p := range dataPacks {
+		packs = append(packs, p)
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Version < packs[j].Version })
+	return packs
+}
+
+// LookupSpecies returns the pack's entry for a species ID
+func (p *DataPack) LookupSpecies(id string) (*Species, error) {
+	species, ok := p.Species[id]
+	if !ok {
+		return nil, ErrUnknownSpecies
+	}
+	return species, nil
+}
+
+// LookupMove returns the pack's entry for a move ID
+func (p *DataPack) LookupMove(id string) (*Move, error) {
+	move, ok := p.Moves[id]
+	if !ok {
+		return nil, ErrUnknownMove
+	}
+	return move, nil
+}
+
+// Effectiveness returns the damage multiplier of an attacking type against a set of defending types.
+// A typeless attack (TypeNone) is always neutral.
+func (p *DataPack) Effectiveness(attack Type, defending []Type) float64 {
+	multiplier := 1.0
+	if attack == TypeNone {
+		return multiplier
+	}
+	for _, def := range defending {
+		if m, ok := p.TypeChart[attack][def]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// NewCreatureFromSpecies builds a battle-ready creature of the given species with the given moves
+func (p *DataPack) NewCreatureFromSpecies(id, speciesID string, level int, moveIDs []string) (*Creature, error) {
+	species, err := p.LookupSpecies(speciesID)
+	if err != nil {
+		return nil, fmt.Errorf("species %q: %w", speciesID, err)
+	}
+
+	moves := make([]*Move, 0, len(moveIDs))
+	for _, moveID := range moveIDs {
+		move, err := p.LookupMove(moveID)
+		if err != nil {
+			return nil, fmt.Errorf("move %q: %w", moveID, err)
+		}
+		moves = append(moves, move)
+	}
+
+	creature := NewCreature(id, species.Name, level, species.Types, species.StatsAtLevel(level), moves)
+	creature.SpeciesID = species.ID
+	return creature, nil
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupDataPack(t *testing.T) {
+	pack, err := LookupDataPack(CurrentDataVersion)
+	if err != nil {
+		t.Fatalf("expected the current data version to be registered, got %v", err)
+	}
+	if pack != CurrentDataPack() || pack.Version != CurrentDataVersion {
+		t.Errorf("unexpected current data pack: %+v", pack)
+	}
+
+	if _, err := LookupDataPack("0"); !errors.Is(err, ErrUnknownDataVersion) {
+		t.Errorf("expected ErrUnknownDataVersion, got %v", err)
+	}
+}
+
+func TestListDataPacks_OrderedByVersion(t *testing.T) {
+	packs := ListDataPacks()
+	if len(packs) != len(dataPacks) {
+		t.Fatalf("expected %d data packs, got %d", len(dataPacks), len(packs))
+	}
+	for i := 1; i < len(packs); i++ {
+		if packs[i-1].Version >= packs[i].Version {
+			t.Errorf("expected data packs ordered by version, got %q before %q", packs[i-1].Version, packs[i].Version)
+		}
+	}
+}
+
+func TestNewBattle_StampedWithCurrentData(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 60, "tackle"))
+
+	if b.Data == nil || b.Data.Version != CurrentDataVersion {
+		t.Errorf("expected battle to use data version %q, got %+v", CurrentDataVersion, b.Data)
+	}
+}
+
+func TestBattle_ResolvesAgainstItsDataPack(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "surf")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, attacker, defender)
+	b.Data = &DataPack{
+		Version:   "test",
+		Species:   speciesCatalogue,
+		Moves:     moveCatalogue,
+		TypeChart: map[Type]map[Type]float64{TypeWater: {TypeFire: 0.5}},
+	}
+
+	events := resolveTurn(t, b, "surf", "tackle")
+
+	for _, e := range events {
+		if e.Type == EventDamageDealt && e.Actor == "player-1" {
+			if e.Effectiveness != EffectivenessLabel(0.5) {
+				t.Errorf("expected the battle's type chart to make surf resisted, got %q", e.Effectiveness)
+			}
+			return
+		}
+	}
+	t.Fatal("expected surf to deal damage")
+}
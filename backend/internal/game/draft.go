@@ -0,0 +1,187 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Draft errors
+var (
+	ErrDraftNotStarted      = errors.New("draft has not started")
+	ErrDraftComplete        = errors.New("draft is complete")
+	ErrDraftInProgress      = errors.New("team cannot be submitted until the draft is complete")
+	ErrNotYourDraftTurn     = errors.New("not your turn to draft")
+	ErrSpeciesNotAvailable  = errors.New("species is not available in the draft pool")
+	ErrSpeciesNotDrafted    = errors.New("species was not drafted by the player")
+	ErrInvalidStateForDraft = errors.New("cannot change draft mode in current state")
+)
+
+// RuleDrafted is the violation rule for team members the player did not draft
+const RuleDrafted Rule = "drafted"
+
+// Draft limits
+const (
+	DraftBansPerPlayer  = 1
+	DraftPicksPerPlayer = 3
+)
+
+// DraftKind is whether a draft turn bans a species from the pool or picks it for the player's team
+type DraftKind string
+
+const (
+	DraftKindBan  DraftKind = "ban"
+	DraftKindPick DraftKind = "pick"
+)
+
+// DraftTurn is one step of the draft order
+type DraftTurn struct {
+	PlayerID string
+	Kind     DraftKind
+}
+
+// Draft is a ban/pick phase in which two players take species from a shared pool before team building.
+// Bans alternate, then picks snake (A, B, B, A, A, B) to offset the first pick.
+// A Draft is not safe for concurrent use; the lobby guards it.
+type Draft struct {
+	Pool  []string // every species that can be banned or picked, ordered by ID
+	Order []DraftTurn
+	Step  int                 // index into Order of the turn awaiting a choice; len(Order) once complete
+	Bans  map[string][]string // species banned by each player
+	Picks map[string][]string // species picked by each player
+}
+
+// newDraft creates a draft over every species in the current data with the first player banning first
+func newDraft(first, second string) *Draft {
+	species := CurrentDataPack().Species
+	pool := make([]string, 0, len(species))
+	for id := range species {
+		pool = append(pool, id)
+	}
+	sort.Strings(pool)
+
+	order := make([]DraftTurn, 0, 2*(DraftBansPerPlayer+DraftPicksPerPlayer))
+	for i := 0; i < DraftBansPerPlayer; i++ {
+		order = append(order, DraftTurn{first, DraftKindBan}, DraftTurn{second, DraftKindBan})
+	}
+	for i := 0; i < DraftPicksPerPlayer; i++ {
+		if i%2 == 0 {
+			order = append(order, DraftTurn{first, DraftKindPick}, DraftTurn{second, DraftKindPick})
+		} else {
+			order = append(order, DraftTurn{second, DraftKindPick}, DraftTurn{first, DraftKindPick})
+		}
+	}
+
+	return &Draft{
+		Pool:  pool,
+		Order: order,
+		Bans:  map[string][]string{first: {}, second: {}},
+		Picks: map[string][]string{first: {}, second: {}},
+	}
+}
+
+// Complete returns true once every turn in the draft order has been taken
+func (d *Draft) Complete() bool {
+	return d.Step >= len(d.Order)
+}
+
+// Current returns the turn awaiting a choice, or false once the draft is complete
+func (d *Draft) Current() (DraftTurn, bool) {
+	if d.Complete() {
+		return DraftTurn{}, false
+	}
+	return d.Order[d.Step], true
+}
+
+// Available reports whether a species is in the pool and has not been banned or picked
+func (d *Draft) Available(speciesID string) bool {
+	if !slices.Contains(d.Pool, speciesID) {
+		return false
+	}
+	for _, taken := range []map[string][]string{d.Bans, d.Picks} {
+		for _, ids := range taken {
+			if slices.Contains(ids, speciesID) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Drafted reports whether the player picked the species
+func (d *Draft) Drafted(playerID, speciesID string) bool {
+	return slices.Contains(d.Picks[playerID], speciesID)
+}
+
+// submit bans or picks a species for the player whose turn it is
+func (d *Draft) submit(playerID, speciesID string) error {
+	turn, ok := d.Current()
+	if !ok {
+		return ErrDraftComplete
+	}
+	if turn.PlayerID != playerID {
+		return ErrNotYourDraftTurn
+	}
+	if !d.Available(speciesID) {
+		return ErrSpeciesNotAvailable
+	}
+
+	if turn.Kind == DraftKindBan {
+		d.Bans[playerID] = append(d.Bans[playerID], speciesID)
+	} else {
+		d.Picks[playerID] = append(d.Picks[playerID], speciesID)
+	}
+	d.Step++
+	return nil
+}
+
+// expire resolves a turn the player ran out of time on: a ban is skipped and a pick takes
+// the first available species in the pool. It does nothing unless the draft is still on step.
+func (d *Draft) expire(step int) bool {
+	turn, ok := d.Current()
+	if !ok || d.Step != step {
+		return false
+	}
+
+	if turn.Kind == DraftKindPick {
+		for _, id := range d.Pool {
+			if d.Available(id) {
+				d.Picks[turn.PlayerID] = append(d.Picks[turn.PlayerID], id)
+				break
+			}
+		}
+	}
+	d.Step++
+	return true
+}
+
+// clone returns a deep copy of the draft
+func (d *Draft) clone() *Draft {
+	copyTaken := func(taken map[string][]string) map[string][]string {
+		copied := make(map[string][]string, len(taken))
+		for playerID, ids := range taken {
+			copied[playerID] = append([]string{}, ids...)
+		}
+		return copied
+	}
+	return &Draft{
+		Pool:  append([]string(nil), d.Pool...),
+		Order: append([]DraftTurn(nil), d.Order...),
+		Step:  d.Step,
+		Bans:  copyTaken(d.Bans),
+		Picks: copyTaken(d.Picks),
+	}
+}
+
+// teamViolations lists team members whose species the player did not draft
+func (d *Draft) teamViolations(playerID string, team []TeamMember) []Violation {
+	var violations []Violation
+	for i, member := range team {
+		if !d.Drafted(playerID, member.SpeciesID) {
+			err := fmt.Errorf("species %q: %w", member.SpeciesID, ErrSpeciesNotDrafted)
+			violations = append(violations, Violation{Slot: i, Rule: RuleDrafted, Err: err})
+		}
+	}
+	return violations
+}
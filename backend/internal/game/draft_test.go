@@ -0,0 +1,250 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newDraftLobby creates a full draft-mode lobby with its draft started
+func newDraftLobby(t *testing.T) *Lobby {
+	t.Helper()
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	if err := lobby.SetDraftMode(true); err != nil {
+		t.Fatalf("failed to enable draft mode: %v", err)
+	}
+	lobby.AddPlayer("player-2", "Player2")
+	if !lobby.StartDraft() {
+		t.Fatal("expected draft to start")
+	}
+	return lobby
+}
+
+// draftStarterTeam runs a draft in which host-1 picks the starter team's species
+func draftStarterTeam(t *testing.T, lobby *Lobby) {
+	t.Helper()
+	picks := []struct{ playerID, speciesID string }{
+		{"host-1", "gengar"}, {"player-2", "alakazam"}, // bans
+		{"host-1", "venusaur"}, {"player-2", "machamp"}, {"player-2", "pikachu"},
+		{"host-1", "charizard"}, {"host-1", "blastoise"}, {"player-2", "snorlax"},
+	}
+	for _, p := range picks {
+		if err := lobby.SubmitDraftPick(p.playerID, p.speciesID); err != nil {
+			t.Fatalf("%s drafting %s: %v", p.playerID, p.speciesID, err)
+		}
+	}
+}
+
+// ========================================
+// Draft Tests
+// ========================================
+
+func TestDraft_Order(t *testing.T) {
+	d := newDraft("a", "b")
+
+	want := []DraftTurn{
+		{"a", DraftKindBan}, {"b", DraftKindBan},
+		{"a", DraftKindPick}, {"b", DraftKindPick}, {"b", DraftKindPick},
+		{"a", DraftKindPick}, {"a", DraftKindPick}, {"b", DraftKindPick},
+	}
+	if len(d.Order) != len(want) {
+		t.Fatalf("expected %d turns, got %d", len(want), len(d.Order))
+	}
+	for i, turn := range want {
+		if d.Order[i] != turn {
+			t.Errorf("turn %d: expected %+v, got %+v", i, turn, d.Order[i])
+		}
+	}
+	if len(d.Pool) != len(speciesCatalogue) {
+		t.Errorf("expected the whole catalogue in the pool, got %d species", len(d.Pool))
+	}
+}
+
+func TestDraft_SubmitRemovesSpeciesFromPool(t *testing.T) {
+	d := newDraft("a", "b")
+
+	if err := d.submit("a", "snorlax"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if d.Available("snorlax") {
+		t.Error("expected banned species to leave the pool")
+	}
+	if err := d.submit("b", "snorlax"); !errors.Is(err, ErrSpeciesNotAvailable) {
+		t.Errorf("expected ErrSpeciesNotAvailable, got %v", err)
+	}
+	if err := d.submit("b", "missingno"); !errors.Is(err, ErrSpeciesNotAvailable) {
+		t.Errorf("expected ErrSpeciesNotAvailable for unknown species, got %v", err)
+	}
+}
+
+func TestDraft_NotYourTurn(t *testing.T) {
+	d := newDraft("a", "b")
+
+	if err := d.submit("b", "snorlax"); !errors.Is(err, ErrNotYourDraftTurn) {
+		t.Errorf("expected ErrNotYourDraftTurn, got %v", err)
+	}
+	if d.Step != 0 {
+		t.Errorf("expected draft to stay on step 0, got %d", d.Step)
+	}
+}
+
+func TestDraft_ExpireSkipsBanAndAutoPicks(t *testing.T) {
+	d := newDraft("a", "b")
+
+	if !d.expire(0) {
+		t.Fatal("expected turn 0 to expire")
+	}
+	if len(d.Bans["a"]) != 0 {
+		t.Errorf("expected a timed-out ban to be skipped, got %v", d.Bans["a"])
+	}
+
+	d.submit("b", "alakazam")
+	if !d.expire(2) {
+		t.Fatal("expected turn 2 to expire")
+	}
+	if !d.Drafted("a", "blastoise") {
+		t.Errorf("expected the first available species to be picked, got %v", d.Picks["a"])
+	}
+}
+
+func TestDraft_ExpireStaleStep(t *testing.T) {
+	d := newDraft("a", "b")
+	d.submit("a", "snorlax")
+
+	if d.expire(0) {
+		t.Error("expected a turn that was already taken not to expire")
+	}
+	if d.Step != 1 {
+		t.Errorf("expected draft to stay on step 1, got %d", d.Step)
+	}
+}
+
+func TestDraft_Complete(t *testing.T) {
+	d := newDraft("a", "b")
+	for step := 0; step < len(d.Order); step++ {
+		d.expire(step)
+	}
+
+	if !d.Complete() {
+		t.Fatal("expected draft to be complete")
+	}
+	if _, ok := d.Current(); ok {
+		t.Error("expected no current turn once complete")
+	}
+	if err := d.submit("a", "snorlax"); !errors.Is(err, ErrDraftComplete) {
+		t.Errorf("expected ErrDraftComplete, got %v", err)
+	}
+	if len(d.Picks["a"]) != DraftPicksPerPlayer || len(d.Picks["b"]) != DraftPicksPerPlayer {
+		t.Errorf("expected %d picks each, got %v", DraftPicksPerPlayer, d.Picks)
+	}
+}
+
+// ========================================
+// Lobby Draft Tests
+// ========================================
+
+func TestLobbyDraft_StartsWhenFull(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.SetDraftMode(true)
+
+	if lobby.StartDraft() {
+		t.Fatal("expected draft not to start with one player")
+	}
+
+	lobby.AddPlayer("player-2", "Player2")
+	if !lobby.StartDraft() {
+		t.Fatal("expected draft to start once the lobby is full")
+	}
+	if lobby.StartDraft() {
+		t.Error("expected a running draft not to restart")
+	}
+
+	draft, _ := lobby.GetDraft()
+	if turn, _ := draft.Current(); turn.PlayerID != "host-1" {
+		t.Errorf("expected host to ban first, got %q", turn.PlayerID)
+	}
+}
+
+func TestLobbyDraft_NotInDraftMode(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+
+	if lobby.StartDraft() {
+		t.Error("expected no draft without draft mode")
+	}
+	if err := lobby.SubmitDraftPick("host-1", "snorlax"); !errors.Is(err, ErrDraftNotStarted) {
+		t.Errorf("expected ErrDraftNotStarted, got %v", err)
+	}
+}
+
+func TestLobbyDraft_TeamBlockedUntilComplete(t *testing.T) {
+	lobby := newDraftLobby(t)
+
+	if err := lobby.SubmitTeam("host-1", StarterTeam()); !errors.Is(err, ErrDraftInProgress) {
+		t.Fatalf("expected ErrDraftInProgress, got %v", err)
+	}
+
+	draftStarterTeam(t, lobby)
+
+	if err := lobby.SubmitTeam("host-1", StarterTeam()); err != nil {
+		t.Errorf("expected drafted team to be accepted, got %v", err)
+	}
+}
+
+func TestLobbyDraft_UndraftedSpeciesRejected(t *testing.T) {
+	lobby := newDraftLobby(t)
+	draftStarterTeam(t, lobby)
+
+	err := lobby.SubmitTeam("player-2", StarterTeam())
+
+	var teamErr *TeamError
+	if !errors.As(err, &teamErr) {
+		t.Fatalf("expected a TeamError, got %v", err)
+	}
+	if len(teamErr.Violations) != 3 {
+		t.Fatalf("expected 3 violations, got %d", len(teamErr.Violations))
+	}
+	for _, v := range teamErr.Violations {
+		if v.Rule != RuleDrafted || !errors.Is(v.Err, ErrSpeciesNotDrafted) {
+			t.Errorf("unexpected violation: %+v", v)
+		}
+	}
+}
+
+func TestLobbyDraft_EnablingDiscardsTeams(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+
+	if err := lobby.SetDraftMode(true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.HasTeam("host-1") || lobby.HasTeam("player-2") {
+		t.Error("expected enabling draft mode to discard submitted teams")
+	}
+}
+
+func TestLobbyDraft_PlayerLeavingResetsDraft(t *testing.T) {
+	lobby := newDraftLobby(t)
+	draftStarterTeam(t, lobby)
+	lobby.SubmitTeam("host-1", StarterTeam())
+
+	lobby.RemovePlayer("player-2")
+
+	if _, ok := lobby.GetDraft(); ok {
+		t.Error("expected the draft to be discarded")
+	}
+	if lobby.HasTeam("host-1") {
+		t.Error("expected drafted teams to be discarded")
+	}
+}
+
+func TestLobbyDraft_SetDraftModeAfterStart(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+
+	if err := lobby.SetDraftMode(true); !errors.Is(err, ErrInvalidStateForDraft) {
+		t.Errorf("expected ErrInvalidStateForDraft, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package game
+
+import "errors"
+
+var (
+	// ErrDrawAlreadyOffered is returned when a draw is offered while an offer is still unanswered
+	ErrDrawAlreadyOffered = errors.New("draw already offered")
+	// ErrNoDrawOffer is returned when responding without an opposing draw offer to answer
+	ErrNoDrawOffer = errors.New("no draw offer to respond to")
+)
+
+// OfferDraw proposes a draw to the opponent. The offer stands until the opponent responds
+// or the current turn resolves.
+func (b *Battle) OfferDraw(playerID string) error {
+	if b.outcome != nil {
+		return ErrBattleOver
+	}
+	if _, err := b.sideIndex(playerID); err != nil {
+		return err
+	}
+	if b.drawOffer != "" {
+		return ErrDrawAlreadyOffered
+	}
+
+	b.drawOffer = playerID
+	return nil
+}
+
+// RespondDraw answers the opponent's draw offer. Accepting ends the battle as a draw and
+// returns the outcome; declining withdraws the offer and returns nil.
+func (b *Battle) RespondDraw(playerID string, accept bool) (*BattleOutcome, error) {
+	if b.outcome != nil {
+		return nil, ErrBattleOver
+	}
+	if _, err := b.sideIndex(playerID); err != nil {
+		return nil, err
+	}
+	if b.drawOffer == "" || b.drawOffer == playerID {
+		return nil, ErrNoDrawOffer
+	}
+
+	b.drawOffer = ""
+	if !accept {
+		return nil, nil
+	}
+	b.end("", "", EndReasonDraw)
+	return b.outcome, nil
+}
+
+// DrawOffer returns the player with an unanswered draw offer, or "" if there is none
+func (b *Battle) DrawOffer() string {
+	return b.drawOffer
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Draw Offer Tests
+// ========================================
+
+func TestDraw_AcceptEndsBattleAsDraw(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	if err := b.OfferDraw("player-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	outcome, err := b.RespondDraw("player-2", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if outcome == nil || !outcome.IsDraw() || outcome.LoserID != "" || outcome.Reason != EndReasonDraw {
+		t.Errorf("expected a draw, got %+v", outcome)
+	}
+	if b.Outcome() != outcome {
+		t.Error("expected outcome to be recorded on the battle")
+	}
+}
+
+func TestDraw_DeclineWithdrawsOffer(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.OfferDraw("player-1")
+
+	outcome, err := b.RespondDraw("player-2", false)
+	if err != nil || outcome != nil {
+		t.Fatalf("expected the offer to be declined, got %+v, %v", outcome, err)
+	}
+
+	if b.DrawOffer() != "" || b.Outcome() != nil {
+		t.Errorf("expected the battle to go on without an offer, got offer %q", b.DrawOffer())
+	}
+	if _, err := b.RespondDraw("player-2", true); !errors.Is(err, ErrNoDrawOffer) {
+		t.Errorf("expected ErrNoDrawOffer, got %v", err)
+	}
+}
+
+func TestDraw_CannotAnswerOwnOffer(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.OfferDraw("player-1")
+
+	if _, err := b.RespondDraw("player-1", true); !errors.Is(err, ErrNoDrawOffer) {
+		t.Errorf("expected ErrNoDrawOffer, got %v", err)
+	}
+	if err := b.OfferDraw("player-2"); !errors.Is(err, ErrDrawAlreadyOffered) {
+		t.Errorf("expected ErrDrawAlreadyOffered, got %v", err)
+	}
+}
+
+func TestDraw_OfferExpiresWhenTurnResolves(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{fallback: 15}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.OfferDraw("player-1")
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	if _, err := b.RespondDraw("player-2", true); !errors.Is(err, ErrNoDrawOffer) {
+		t.Errorf("expected the offer to expire with the turn, got %v", err)
+	}
+}
+
+func TestDraw_Errors(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	if err := b.OfferDraw("stranger"); !errors.Is(err, ErrPlayerNotInBattle) {
+		t.Errorf("expected ErrPlayerNotInBattle, got %v", err)
+	}
+
+	b.Forfeit("player-1")
+	if err := b.OfferDraw("player-2"); !errors.Is(err, ErrBattleOver) {
+		t.Errorf("expected ErrBattleOver, got %v", err)
+	}
+	if _, err := b.RespondDraw("player-2", true); !errors.Is(err, ErrBattleOver) {
+		t.Errorf("expected ErrBattleOver, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package game
+
+import "errors"
+
+// Forced switch errors
+var (
+	ErrAwaitingSwitch    = errors.New("waiting for a fainted creature to be replaced")
+	ErrNoSwitchRequired  = errors.New("no forced switch required")
+	ErrNoSwitchAvailable = errors.New("no creature available to switch in")
+)
+
+// SwitchTargets returns the team slots that can be switched in
+func (s *BattleSide) SwitchTargets() []int {
+	var slots []int
+	for i := range s.Team {
+		if s.CanSwitchTo(i) == nil {
+			slots = append(slots, i)
+		}
+	}
+	return slots
+}
+
+// NeedsReplacement returns true if the active creature has fainted and a healthy creature can replace it
+func (s *BattleSide) NeedsReplacement() bool {
+	return s.Active().IsFainted() && len(s.SwitchTargets()) > 0
+}
+
+// PendingSwitches returns the IDs of players who must replace a fainted creature
+// before the next turn can begin
+func (b *Battle) PendingSwitches() []string {
+	var players []string
+	for _, side := range b.Sides {
+		if side.NeedsReplacement() {
+			players = append(players, side.PlayerID)
+		}
+	}
+	return players
+}
+
+// SwitchTargets returns the slots a player can switch in
+func (b *Battle) SwitchTargets(playerID string) ([]int, error) {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return nil, err
+	}
+	return b.Sides[idx].SwitchTargets(), nil
+}
+
+// awaitingSwitch returns true while any side still has to replace a fainted creature
+func (b *Battle) awaitingSwitch() bool {
+	for _, side := range b.Sides {
+		if side.NeedsReplacement() {
+			return true
+		}
+	}
+	return false
+}
+
+// SubmitForcedSwitch replaces a player's fainted active creature between turns.
+// The switch and any entry hazard effects happen immediately and their events are returned.
+func (b *Battle) SubmitForcedSwitch(playerID string, slot int) ([]BattleEvent, error) {
+	if err := b.ValidateForcedSwitch(playerID, slot); err != nil {
+		return nil, err
+	}
+
+	idx, _ := b.sideIndex(playerID)
+	side := b.Sides[idx]
+	from := side.ActiveSlot
+	if err := side.Switch(slot); err != nil {
+		return nil, err
+	}
+
+	b.events = nil
+	b.emit(BattleEvent{Type: EventCreatureSwitched, Actor: playerID, Target: side.Active().ID, FromSlot: from, ToSlot: side.ActiveSlot})
+	b.applyEntryHazards(idx)
+	b.checkVictory()
+
+	events := b.events
+	b.events = nil
+	return events, nil
+}
+
+// AutoSwitchSlot picks the replacement used when a player fails to choose one in time
+func (b *Battle) AutoSwitchSlot(playerID string) (int, error) {
+	slots, err := b.SwitchTargets(playerID)
+	if err != nil {
+		return -1, err
+	}
+	if len(slots) == 0 {
+		return -1, ErrNoSwitchAvailable
+	}
+	return slots[0], nil
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newForcedSwitchBattle creates a battle where player-2's lead faints to player-1's first attack
+func newForcedSwitchBattle(t *testing.T) *Battle {
+	t.Helper()
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "tackle")
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle")
+	lead.CurrentHP = 1
+	fainted := newTestCreature(t, "fainted", []Type{TypeNormal}, 50, "tackle")
+	fainted.CurrentHP = 0
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 50, "tackle")
+	return NewBattle("battle-1",
+		NewBattleSide("player-1", []*Creature{attacker}),
+		NewBattleSide("player-2", []*Creature{lead, fainted, bench}),
+		&scriptedRNG{fallback: 15})
+}
+
+// ========================================
+// Forced Switch Tests
+// ========================================
+
+func TestForcedSwitch_RequiredAfterFaint(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	pending := b.PendingSwitches()
+	if len(pending) != 1 || pending[0] != "player-2" {
+		t.Fatalf("expected player-2 to need a forced switch, got %v", pending)
+	}
+	slots, _ := b.SwitchTargets("player-2")
+	if len(slots) != 1 || slots[0] != 2 {
+		t.Errorf("expected only the healthy bench slot to be available, got %v", slots)
+	}
+}
+
+func TestForcedSwitch_PausesTurnProgression(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+	resolveTurn(t, b, "tackle", "tackle")
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	if !errors.Is(err, ErrAwaitingSwitch) {
+		t.Fatalf("expected ErrAwaitingSwitch, got %v", err)
+	}
+
+	events, err := b.SubmitForcedSwitch("player-2", 2)
+	if err != nil {
+		t.Fatalf("forced switch failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventCreatureSwitched || events[0].Target != "bench" {
+		t.Errorf("expected creature_switched event for bench, got %+v", events)
+	}
+	if len(b.PendingSwitches()) != 0 {
+		t.Error("expected no pending switches after replacement")
+	}
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"}); err != nil {
+		t.Errorf("expected actions to be accepted after replacement, got %v", err)
+	}
+}
+
+func TestForcedSwitch_InvalidSlot(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+	resolveTurn(t, b, "tackle", "tackle")
+
+	if _, err := b.SubmitForcedSwitch("player-2", 1); !errors.Is(err, ErrInvalidSwitchTarget) {
+		t.Errorf("expected ErrInvalidSwitchTarget for a fainted slot, got %v", err)
+	}
+}
+
+func TestForcedSwitch_NotRequired(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+
+	if _, err := b.SubmitForcedSwitch("player-2", 2); !errors.Is(err, ErrNoSwitchRequired) {
+		t.Errorf("expected ErrNoSwitchRequired, got %v", err)
+	}
+}
+
+func TestAutoSwitchSlot_PicksFirstHealthyCreature(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+	resolveTurn(t, b, "tackle", "tackle")
+
+	slot, err := b.AutoSwitchSlot("player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if slot != 2 {
+		t.Errorf("expected slot 2, got %d", slot)
+	}
+}
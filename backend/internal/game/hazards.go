@@ -0,0 +1,174 @@
+package game
+
+// Hazard is an entry hazard laid on one side of the field
+type Hazard string
+
+const (
+	HazardNone        Hazard = ""
+	HazardStealthRock Hazard = "stealth_rock"
+	HazardSpikes      Hazard = "spikes"
+	HazardToxicSpikes Hazard = "toxic_spikes"
+)
+
+// HazardRemoval describes which sides of the field a move clears of hazards
+type HazardRemoval int
+
+const (
+	HazardRemovalNone      HazardRemoval = iota
+	HazardRemovalUserSide                // e.g. Rapid Spin
+	HazardRemovalBothSides               // e.g. Defog
+)
+
+// Entry hazard configuration
+const (
+	maxSpikesLayers          = 3
+	maxToxicSpikesLayers     = 2
+	stealthRockDamageDivisor = 8 // Neutral Stealth Rock damage is 1/8 of max HP
+)
+
+// spikesDamageDivisors maps Spikes layers to the fraction of max HP dealt (1/8, 1/6, 1/4)
+var spikesDamageDivisors = [maxSpikesLayers + 1]int{0, 8, 6, 4}
+
+// Hazards tracks the entry hazards on one side of the field
+type Hazards struct {
+	StealthRock bool
+	Spikes      int // Layers, 0..3
+	ToxicSpikes int // Layers, 0..2
+}
+
+// Layers returns the number of layers of a hazard (1 for Stealth Rock when present)
+func (h *Hazards) Layers(hazard Hazard) int {
+	switch hazard {
+	case HazardStealthRock:
+		if h.StealthRock {
+			return 1
+		}
+		return 0
+	case HazardSpikes:
+		return h.Spikes
+	case HazardToxicSpikes:
+		return h.ToxicSpikes
+	default:
+		return 0
+	}
+}
+
+// Any returns true if at least one hazard is present
+func (h *Hazards) Any() bool {
+	return h.StealthRock || h.Spikes > 0 || h.ToxicSpikes > 0
+}
+
+// add lays one layer of a hazard, returning false if it is already at its maximum
+func (h *Hazards) add(hazard Hazard) bool {
+	switch hazard {
+	case HazardStealthRock:
+		if h.StealthRock {
+			return false
+		}
+		h.StealthRock = true
+	case HazardSpikes:
+		if h.Spikes >= maxSpikesLayers {
+			return false
+		}
+		h.Spikes++
+	case HazardToxicSpikes:
+		if h.ToxicSpikes >= maxToxicSpikesLayers {
+			return false
+		}
+		h.ToxicSpikes++
+	default:
+		return false
+	}
+	return true
+}
+
+// remove clears a hazard entirely
+func (h *Hazards) remove(hazard Hazard) {
+	switch hazard {
+	case HazardStealthRock:
+		h.StealthRock = false
+	case HazardSpikes:
+		h.Spikes = 0
+	case HazardToxicSpikes:
+		h.ToxicSpikes = 0
+	}
+}
+
+// isGrounded reports whether the creature is affected by ground-based hazards and terrain
+func (c *Creature) isGrounded() bool {
+	return !c.HasType(TypeFlying)
+}
+
+// setHazard lays the move's hazard on the opposing side
+func (b *Battle) setHazard(actor string, targetSide int, move *Move) {
+	side := b.Sides[targetSide]
+	if !side.Hazards.add(move.Hazard) {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonAlreadyAffected})
+		return
+	}
+	b.emit(BattleEvent{Type: EventHazardSet, Actor: actor, Side: side.PlayerID, Hazard: string(move.Hazard), Layers: side.Hazards.Layers(move.Hazard)})
+}
+
+// clearHazards removes every hazard from a side, emitting one event per hazard removed
+func (b *Battle) clearHazards(actor string, sideIdx int) {
+	side := b.Sides[sideIdx]
+	for _, hazard := range []Hazard{HazardStealthRock, HazardSpikes, HazardToxicSpikes} {
+		if side.Hazards.Layers(hazard) == 0 {
+			continue
+		}
+		side.Hazards.remove(hazard)
+		b.emit(BattleEvent{Type: EventHazardCleared, Actor: actor, Side: side.PlayerID, Hazard: string(hazard)})
+	}
+}
+
+// removeHazards applies a move's hazard removal
+func (b *Battle) removeHazards(actor string, userSide int, removal HazardRemoval) {
+	switch removal {
+	case HazardRemovalUserSide:
+		b.clearHazards(actor, userSide)
+	case HazardRemovalBothSides:
+		b.clearHazards(actor, userSide)
+		b.clearHazards(actor, 1-userSide)
+	}
+}
+
+// applyEntryHazards hurts or poisons a creature that just switched in
+func (b *Battle) applyEntryHazards(sideIdx int) {
+	side := b.Sides[sideIdx]
+	creature := side.Active()
+	owner := side.PlayerID
+
+	if side.Hazards.StealthRock {
+		damage := int(float64(creature.MaxHP()) * b.Data.Effectiveness(TypeRock, creature.Types) / stealthRockDamageDivisor)
+		b.hazardDamage(owner, creature, HazardStealthRock, max(damage, 1))
+	}
+
+	if !creature.isGrounded() {
+		return
+	}
+
+	if layers := side.Hazards.Spikes; layers > 0 && !creature.IsFainted() {
+		b.hazardDamage(owner, creature, HazardSpikes, max(creature.MaxHP()/spikesDamageDivisors[layers], 1))
+	}
+
+	if layers := side.Hazards.ToxicSpikes; layers > 0 && !creature.IsFainted() {
+		if creature.HasType(TypePoison) {
+			// Grounded poison types absorb Toxic Spikes on entry
+			side.Hazards.remove(HazardToxicSpikes)
+			b.emit(BattleEvent{Type: EventHazardCleared, Actor: owner, Side: owner, Hazard: string(HazardToxicSpikes)})
+			return
+		}
+		status := StatusPoison
+		if layers >= maxToxicSpikesLayers {
+			status = StatusBadPoison
+		}
+		b.applyStatus(owner, creature, status)
+	}
+}
+
+// hazardDamage deals entry hazard damage and checks for a faint
+func (b *Battle) hazardDamage(owner string, creature *Creature, hazard Hazard, amount int) {
+	dealt := creature.TakeDamage(amount)
+	b.emit(BattleEvent{Type: EventHazardDamage, Actor: owner, Target: creature.ID, Hazard: string(hazard), Damage: dealt})
+	b.checkFainted(owner, creature)
+}
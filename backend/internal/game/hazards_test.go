@@ -0,0 +1,262 @@
+package game
+
+import "testing"
+
+// newHazardBattle creates a battle where player-2 has a bench creature to switch in
+func newHazardBattle(t *testing.T, setter *Creature, benchTypes []Type) (*Battle, *Creature) {
+	t.Helper()
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "growl")
+	bench := newTestCreature(t, "bench", benchTypes, 50, "growl")
+	b := NewBattle("battle-1",
+		NewBattleSide("player-1", []*Creature{setter}),
+		NewBattleSide("player-2", []*Creature{lead, bench}),
+		&scriptedRNG{fallback: 15})
+	return b, bench
+}
+
+// switchPlayer2 has player-2 switch to slot 1 while player-1 uses the given move
+func switchPlayer2(t *testing.T, b *Battle, move1 string) []BattleEvent {
+	t.Helper()
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: move1}); err != nil {
+		t.Fatalf("player-1 submit failed: %v", err)
+	}
+	if err := b.SubmitAction("player-2", Action{Kind: ActionKindSwitch, SwitchSlot: 1}); err != nil {
+		t.Fatalf("player-2 submit failed: %v", err)
+	}
+	events, err := b.ResolveTurn()
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	return events
+}
+
+// ========================================
+// Hazard Setting Tests
+// ========================================
+
+func TestStealthRock_SetOnOpposingSide(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeRock}, 100, "stealth-rock")
+	b, _ := newHazardBattle(t, setter, []Type{TypeNormal})
+
+	events := resolveTurn(t, b, "stealth-rock", "growl")
+
+	set := findEvents(events, EventHazardSet)
+	if len(set) != 1 || set[0].Side != "player-2" || set[0].Hazard != string(HazardStealthRock) {
+		t.Fatalf("expected stealth rock on player-2's side, got %+v", set)
+	}
+	if !b.Sides[1].Hazards.StealthRock || b.Sides[0].Hazards.Any() {
+		t.Error("expected only player-2's side to have stealth rock")
+	}
+}
+
+func TestSpikes_StackUpToThreeLayers(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeGround}, 100, "spikes")
+	b, _ := newHazardBattle(t, setter, []Type{TypeNormal})
+
+	for i := 0; i < maxSpikesLayers; i++ {
+		resolveTurn(t, b, "spikes", "growl")
+	}
+	events := resolveTurn(t, b, "spikes", "growl")
+
+	if b.Sides[1].Hazards.Spikes != maxSpikesLayers {
+		t.Errorf("expected %d layers, got %d", maxSpikesLayers, b.Sides[1].Hazards.Spikes)
+	}
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonAlreadyAffected {
+		t.Errorf("expected a fourth layer to fail, got %+v", failed)
+	}
+}
+
+// ========================================
+// Switch-In Tests
+// ========================================
+
+func TestStealthRock_DamageScalesWithRockEffectiveness(t *testing.T) {
+	tests := []struct {
+		name    string
+		types   []Type
+		divisor int
+	}{
+		{"neutral", []Type{TypeNormal}, 8},
+		{"weak", []Type{TypeFire}, 4},
+		{"double weak", []Type{TypeFire, TypeFlying}, 2},
+		{"resistant", []Type{TypeGround}, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setter := newTestCreature(t, "setter", []Type{TypeRock}, 100, "growl")
+			b, bench := newHazardBattle(t, setter, tt.types)
+			b.Sides[1].Hazards.StealthRock = true
+
+			events := switchPlayer2(t, b, "growl")
+
+			damage := findEvents(events, EventHazardDamage)
+			if len(damage) != 1 || damage[0].Target != "bench" {
+				t.Fatalf("expected hazard damage on switch-in, got %+v", damage)
+			}
+			if expected := bench.MaxHP() / tt.divisor; damage[0].Damage != expected {
+				t.Errorf("expected %d damage, got %d", expected, damage[0].Damage)
+			}
+		})
+	}
+}
+
+func TestSpikes_DamageByLayers(t *testing.T) {
+	for layers, divisor := range map[int]int{1: 8, 2: 6, 3: 4} {
+		setter := newTestCreature(t, "setter", []Type{TypeGround}, 100, "growl")
+		b, bench := newHazardBattle(t, setter, []Type{TypeNormal})
+		b.Sides[1].Hazards.Spikes = layers
+
+		events := switchPlayer2(t, b, "growl")
+
+		damage := findEvents(events, EventHazardDamage)
+		if len(damage) != 1 || damage[0].Damage != bench.MaxHP()/divisor {
+			t.Errorf("%d layers: expected %d damage, got %+v", layers, bench.MaxHP()/divisor, damage)
+		}
+	}
+}
+
+func TestSpikes_FlyingTypesUnaffected(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeGround}, 100, "growl")
+	b, bench := newHazardBattle(t, setter, []Type{TypeFlying})
+	b.Sides[1].Hazards.Spikes = 3
+	b.Sides[1].Hazards.ToxicSpikes = 2
+
+	events := switchPlayer2(t, b, "growl")
+
+	if len(findEvents(events, EventHazardDamage)) != 0 || bench.Status != StatusNone {
+		t.Error("expected airborne creature to ignore ground hazards")
+	}
+}
+
+func TestToxicSpikes_PoisonsOnSwitchIn(t *testing.T) {
+	for layers, expected := range map[int]StatusCondition{1: StatusPoison, 2: StatusBadPoison} {
+		setter := newTestCreature(t, "setter", []Type{TypePoison}, 100, "growl")
+		b, bench := newHazardBattle(t, setter, []Type{TypeNormal})
+		b.Sides[1].Hazards.ToxicSpikes = layers
+
+		events := switchPlayer2(t, b, "growl")
+
+		if bench.Status != expected {
+			t.Errorf("%d layers: expected %s, got %q", layers, expected, bench.Status)
+		}
+		applied := findEvents(events, EventStatusApplied)
+		if len(applied) != 1 || applied[0].Status != string(expected) {
+			t.Errorf("%d layers: expected status_applied event, got %+v", layers, applied)
+		}
+	}
+}
+
+func TestToxicSpikes_AbsorbedByPoisonType(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypePoison}, 100, "growl")
+	b, bench := newHazardBattle(t, setter, []Type{TypePoison})
+	b.Sides[1].Hazards.ToxicSpikes = 2
+
+	events := switchPlayer2(t, b, "growl")
+
+	if bench.Status != StatusNone {
+		t.Errorf("expected poison type not to be poisoned, got %q", bench.Status)
+	}
+	if b.Sides[1].Hazards.ToxicSpikes != 0 {
+		t.Error("expected toxic spikes to be absorbed")
+	}
+	if len(findEvents(events, EventHazardCleared)) != 1 {
+		t.Error("expected hazard_cleared event")
+	}
+}
+
+func TestForcedSwitch_AppliesHazards(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+	b.Sides[1].Hazards.StealthRock = true
+	resolveTurn(t, b, "tackle", "tackle")
+
+	events, err := b.SubmitForcedSwitch("player-2", 2)
+	if err != nil {
+		t.Fatalf("forced switch failed: %v", err)
+	}
+
+	if len(events) != 2 || events[1].Type != EventHazardDamage || events[1].Order != 2 {
+		t.Errorf("expected switch followed by hazard damage, got %+v", events)
+	}
+}
+
+// ========================================
+// Hazard Removal Tests
+// ========================================
+
+func TestRapidSpin_ClearsUserSide(t *testing.T) {
+	spinner := newTestCreature(t, "spinner", []Type{TypeWater}, 100, "rapid-spin")
+	b, _ := newHazardBattle(t, spinner, []Type{TypeNormal})
+	b.Sides[0].Hazards = Hazards{StealthRock: true, Spikes: 2}
+	b.Sides[1].Hazards = Hazards{Spikes: 1}
+
+	events := resolveTurn(t, b, "rapid-spin", "growl")
+
+	if b.Sides[0].Hazards.Any() {
+		t.Error("expected user's side to be cleared")
+	}
+	if b.Sides[1].Hazards.Spikes != 1 {
+		t.Error("expected opposing side to keep its hazards")
+	}
+	if cleared := findEvents(events, EventHazardCleared); len(cleared) != 2 {
+		t.Errorf("expected 2 hazard_cleared events, got %+v", cleared)
+	}
+}
+
+func TestRapidSpin_FailsAgainstGhost(t *testing.T) {
+	spinner := newTestCreature(t, "spinner", []Type{TypeWater}, 100, "rapid-spin")
+	ghost := newTestCreature(t, "ghost", []Type{TypeGhost}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, spinner, ghost)
+	b.Sides[0].Hazards.StealthRock = true
+
+	resolveTurn(t, b, "rapid-spin", "growl")
+
+	if !b.Sides[0].Hazards.StealthRock {
+		t.Error("expected hazards to remain when rapid spin has no effect")
+	}
+}
+
+func TestDefog_ClearsBothSides(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeFlying}, 100, "defog")
+	b, _ := newHazardBattle(t, user, []Type{TypeNormal})
+	b.Sides[0].Hazards = Hazards{ToxicSpikes: 1}
+	b.Sides[1].Hazards = Hazards{StealthRock: true}
+
+	resolveTurn(t, b, "defog", "growl")
+
+	if b.Sides[0].Hazards.Any() || b.Sides[1].Hazards.Any() {
+		t.Error("expected defog to clear both sides")
+	}
+}
+
+// ========================================
+// Poison Damage Tests
+// ========================================
+
+func TestPoison_EndOfTurnDamage(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "growl")
+	user.Status = StatusPoison
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl"))
+
+	events := resolveTurn(t, b, "growl", "growl")
+
+	damage := findEvents(events, EventStatusDamage)
+	if len(damage) != 1 || damage[0].Damage != user.MaxHP()/8 {
+		t.Errorf("expected 1/8 poison damage, got %+v", damage)
+	}
+}
+
+func TestBadPoison_DamageEscalates(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "growl")
+	user.Status = StatusBadPoison
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl"))
+
+	for turn := 1; turn <= 3; turn++ {
+		events := resolveTurn(t, b, "growl", "growl")
+		damage := findEvents(events, EventStatusDamage)
+		if expected := user.MaxHP() * turn / 16; len(damage) != 1 || damage[0].Damage != expected {
+			t.Errorf("turn %d: expected %d damage, got %+v", turn, expected, damage)
+		}
+	}
+}
@@ -8,21 +8,52 @@ import (
 
 // Domain errors
 var (
-	ErrLobbyFull            = errors.New("lobby is full")
-	ErrPlayerAlreadyJoined  = errors.New("player already in lobby")
-	ErrPlayerNotFound       = errors.New("player not found in lobby")
-	ErrInvalidStateForJoin  = errors.New("cannot join lobby in current state")
-	ErrInvalidStateForStart = errors.New("cannot start lobby in current state")
-	ErrNotEnoughPlayers     = errors.New("not enough players to start")
+	ErrLobbyFull              = errors.New("lobby is full")
+	ErrPlayerAlreadyJoined    = errors.New("player already in lobby")
+	ErrPlayerNotFound         = errors.New("player not found in lobby")
+	ErrInvalidStateForJoin    = errors.New("cannot join lobby in current state")
+	ErrInvalidStateForStart   = errors.New("cannot start lobby in current state")
+	ErrNotEnoughPlayers       = errors.New("not enough players to start")
+	ErrInvalidStateForEnd     = errors.New("cannot end lobby in current state")
+	ErrInvalidStateForClose   = errors.New("cannot close lobby in current state")
+	ErrInvalidStateForTeam    = errors.New("cannot change team in current state")
+	ErrTeamsNotSubmitted      = errors.New("every player must submit a team before starting")
+	ErrInvalidStateForRuleset = errors.New("cannot change ruleset in current state")
+	ErrInvalidMaxPlayers      = errors.New("max players must be between 2 and 8")
+	ErrTooManyPlayers         = errors.New("too many players to start a battle")
+	ErrUnknownVisibility      = errors.New("visibility must be public or unlisted")
 )
 
+// Lobby size limits
+const (
+	MinPlayers        = 2 // Players needed for a lobby to be ready
+	MaxLobbyPlayers   = 8 // Largest lobby that can be created
+	DefaultMaxPlayers = 2
+	BattlePlayers     = 2 // Battles are fought between exactly two players
+)
+
+// LobbyVisibility decides whether a lobby is advertised in the lobby list
+type LobbyVisibility string
+
+const (
+	LobbyVisibilityPublic   LobbyVisibility = "public"   // Listed and joinable by code
+	LobbyVisibilityUnlisted LobbyVisibility = "unlisted" // Joinable by code only
+)
+
+// ValidLobbyVisibility reports whether a lobby can be given the visibility
+func ValidLobbyVisibility(visibility LobbyVisibility) bool {
+	return visibility == LobbyVisibilityPublic || visibility == LobbyVisibilityUnlisted
+}
+
 // LobbyState represents the current state of a lobby
 type LobbyState int
 
 const (
-	LobbyStateWaiting LobbyState = iota // Waiting for players
-	LobbyStateReady                     // Both players joined, ready to start
-	LobbyStateActive                    // Game in progress
+	LobbyStateWaiting  LobbyState = iota // Waiting for players
+	LobbyStateReady                      // Enough players joined to start
+	LobbyStateActive                     // Game in progress
+	LobbyStateFinished                   // Game over, result available
+	LobbyStateClosed                     // No further games will be played
 )
 
 // String returns a human-readable representation of the lobby state
@@ -34,15 +65,32 @@ func (s LobbyState) String() string {
 		return "ready"
 	case LobbyStateActive:
 		return "active"
+	case LobbyStateFinished:
+		return "finished"
+	case LobbyStateClosed:
+		return "closed"
 	default:
 		return "unknown"
 	}
 }
 
+// ParseLobbyState returns the lobby state with the given String form
+func ParseLobbyState(s string) (LobbyState, bool) {
+	for _, state := range []LobbyState{LobbyStateWaiting, LobbyStateReady, LobbyStateActive, LobbyStateFinished, LobbyStateClosed} {
+		if state.String() == s {
+			return state, true
+		}
+	}
+	return 0, false
+}
+
 // Player represents a player in a lobby
 type Player struct {
-	ID       string
-	Username string
+	ID            string
+	Username      string
+	Bot           bool          // Controlled by the server rather than a client
+	BotDifficulty BotDifficulty // Policy a bot plays with; empty for humans
+	Ready         bool          // Ready for the next game to start; bots are always ready
 }
 
 // Lobby represents a game lobby
@@ -54,22 +102,75 @@ type Lobby struct {
 	HostID     string
 	MaxPlayers int
 	CreatedAt  time.Time
+
+	// lastActivity is when the lobby was last changed, for idle expiry and sorting
+	lastActivity time.Time
+
+	// visibility decides whether the lobby appears in the lobby list
+	visibility LobbyVisibility
+	// tags describe the lobby, e.g. its region or skill level, for filtering the lobby list
+	tags []LobbyTag
+	// ruleset is the team legality rules submitted teams are validated against
+	ruleset *Ruleset
+	// turnTimer is how long players have to choose each turn's action; 0 for no limit
+	turnTimer time.Duration
+	// countdown is how long players are warned before the game starts; 0 to start immediately
+	countdown time.Duration
+	// startMode decides whether the game starts once every player is ready or waits for the host
+	startMode StartMode
+	// teams holds each player's validated team, keyed by player ID
+	teams map[string][]TeamMember
+	// series is the best-of-N match the lobby's games count towards
+	series Series
+	// rematchTeams decides whether a rematch keeps the submitted teams or asks for new ones
+	rematchTeams RematchTeams
+	// draftMode requires teams to be built from species drafted in draft, which starts once the lobby is full
+	draftMode bool
+	draft     *Draft
+	// invites maps each outstanding invite token to when it expires
+	invites map[string]time.Time
+	// spectators watch the lobby without playing, up to maxSpectators of them
+	spectators    []*Spectator
+	maxSpectators int
+	// waitlist queues players for the next free slot while the lobby is full, first in line first
+	waitlist []*WaitlistEntry
 }
 
-// NewLobby creates a new lobby with the given host as the first player
+// NewLobby creates a new lobby for DefaultMaxPlayers with the given host as the first player
 func NewLobby(code, hostID, hostUsername string) *Lobby {
+	lobby, _ := NewLobbyWithMaxPlayers(code, hostID, hostUsername, DefaultMaxPlayers)
+	return lobby
+}
+
+// NewLobbyWithMaxPlayers creates a new lobby that holds up to maxPlayers, between MinPlayers and MaxLobbyPlayers,
+// with the given host as the first player
+func NewLobbyWithMaxPlayers(code, hostID, hostUsername string, maxPlayers int) (*Lobby, error) {
+	if maxPlayers < MinPlayers || maxPlayers > MaxLobbyPlayers {
+		return nil, ErrInvalidMaxPlayers
+	}
+
 	host := &Player{
 		ID:       hostID,
 		Username: hostUsername,
 	}
+	now := time.Now()
 	return &Lobby{
-		Code:       code,
-		State:      LobbyStateWaiting,
-		Players:    []*Player{host},
-		HostID:     hostID,
-		MaxPlayers: 2,
-		CreatedAt:  time.Now(),
-	}
+		Code:          code,
+		State:         LobbyStateWaiting,
+		Players:       []*Player{host},
+		HostID:        hostID,
+		MaxPlayers:    maxPlayers,
+		CreatedAt:     now,
+		lastActivity:  now,
+		visibility:    LobbyVisibilityPublic,
+		ruleset:       DefaultRuleset(),
+		startMode:     StartModeAuto,
+		teams:         make(map[string][]TeamMember),
+		invites:       make(map[string]time.Time),
+		maxSpectators: DefaultMaxSpectators,
+		series:        newSeries(DefaultSeriesLength),
+		rematchTeams:  RematchTeamsSame,
+	}, nil
 }
 
 // AddPlayer adds a player to the lobby with validation
@@ -77,8 +178,22 @@ func (l *Lobby) AddPlayer(id, username string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Check state - can only join while waiting
-	if l.State != LobbyStateWaiting {
+	if err := l.checkJoin(id); err != nil {
+		return err
+	}
+
+	l.addPlayer(&Player{
+		ID:       id,
+		Username: username,
+	})
+	return nil
+}
+
+// checkJoin returns why the player can't join the lobby, or nil if they can.
+// Requires the caller to hold the lock.
+func (l *Lobby) checkJoin(id string) error {
+	// Check state - can only join before the game starts
+	if !l.open() {
 		return ErrInvalidStateForJoin
 	}
 
@@ -94,22 +209,85 @@ func (l *Lobby) AddPlayer(id, username string) error {
 		return ErrLobbyFull
 	}
 
-	// Add player
-	l.Players = append(l.Players, &Player{
-		ID:       id,
-		Username: username,
-	})
+	return nil
+}
+
+// addPlayer appends a player and marks the lobby ready once it has enough players to start.
+// Requires the caller to hold the lock.
+func (l *Lobby) addPlayer(p *Player) {
+	// A spectator or waitlisted player who joins as a player leaves the spectator roster or waitlist
+	l.removeSpectator(p.ID)
+	l.removeFromWaitlist(p.ID)
+	l.Players = append(l.Players, p)
+	l.touch()
 
-	// Transition to Ready if we now have max players
-	if len(l.Players) == l.MaxPlayers {
+	if len(l.Players) >= MinPlayers {
 		l.State = LobbyStateReady
 	}
+}
 
-	return nil
+// open returns true while players may still join: while waiting, or while ready with slots left.
+// Requires the caller to hold the lock.
+func (l *Lobby) open() bool {
+	return l.State == LobbyStateWaiting || (l.State == LobbyStateReady && len(l.Players) < l.MaxPlayers)
+}
+
+// HasOpenSlot returns true if another player could join the lobby
+func (l *Lobby) HasOpenSlot() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.open()
+}
+
+// AddBot fills the lobby's open slot with a bot that plays the starter team.
+// An empty difficulty selects DefaultBotDifficulty.
+func (l *Lobby) AddBot(difficulty BotDifficulty) (*Player, error) {
+	if difficulty == "" {
+		difficulty = DefaultBotDifficulty
+	}
+	if _, ok := botPolicies[difficulty]; !ok {
+		return nil, ErrUnknownBotDifficulty
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.open() {
+		return nil, ErrInvalidStateForJoin
+	}
+	if len(l.Players) >= l.MaxPlayers {
+		return nil, ErrLobbyFull
+	}
+	if l.draftMode {
+		return nil, ErrBotInDraftLobby
+	}
+
+	team := StarterTeam()
+	if err := l.ruleset.Validate(team); err != nil {
+		return nil, err
+	}
+
+	bot := &Player{ID: BotPlayerID(l.Code), Username: BotUsername, Bot: true, BotDifficulty: difficulty}
+	l.addPlayer(bot)
+	l.teams[bot.ID] = team
+	copied := *bot
+	return &copied, nil
+}
+
+// IsBot returns true if the player is a bot
+func (l *Lobby) IsBot(playerID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, p := range l.Players {
+		if p.ID == playerID {
+			return p.Bot
+		}
+	}
+	return false
 }
 
 // RemovePlayer removes a player from the lobby
-func (l *Lobby) RemovePlayer(id string) error {
+func (l *Lobby) RemovePlayer(id string) ([]WaitlistEntry, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -124,20 +302,79 @@ func (l *Lobby) RemovePlayer(id string) error {
 	}
 
 	if !found {
-		return ErrPlayerNotFound
+		return nil, ErrPlayerNotFound
+	}
+	delete(l.teams, id)
+	l.touch()
+
+	// Bots never play on their own, so they leave with the last human player
+	if !l.hasHuman() {
+		for _, p := range l.Players {
+			delete(l.teams, p.ID)
+		}
+		l.Players = nil
+	}
+
+	// A draft only covers the players who took part, so it restarts with the next opponent
+	if l.draftMode {
+		l.draft = nil
+		l.teams = make(map[string][]TeamMember)
 	}
 
-	// If we were Ready and now have fewer players, go back to Waiting
-	if l.State == LobbyStateReady && len(l.Players) < l.MaxPlayers {
+	// If we were Ready and now have too few players, go back to Waiting
+	if l.State == LobbyStateReady && len(l.Players) < MinPlayers {
 		l.State = LobbyStateWaiting
 	}
 
+	// A finished game can't be rematched without its players, so the lobby closes
+	if l.State == LobbyStateFinished {
+		l.State = LobbyStateClosed
+	}
+
+	// The freed slot goes to the first player on the waitlist
+	promoted := l.promoteWaitlist()
+
 	// If host left and there are remaining players, assign new host
 	if id == l.HostID && len(l.Players) > 0 {
 		l.HostID = l.Players[0].ID
 	}
 
-	return nil
+	return promoted, nil
+}
+
+// LastActivity returns when the lobby was last changed
+func (l *Lobby) LastActivity() time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.lastActivity
+}
+
+// touch records activity in the lobby.
+// Requires the caller to hold the lock.
+func (l *Lobby) touch() {
+	l.lastActivity = time.Now()
+}
+
+// hasHuman returns true if any player is not a bot.
+// Requires the caller to hold the lock.
+func (l *Lobby) hasHuman() bool {
+	for _, p := range l.Players {
+		if !p.Bot {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBot returns true if any player is a bot.
+// Requires the caller to hold the lock.
+func (l *Lobby) hasBot() bool {
+	for _, p := range l.Players {
+		if p.Bot {
+			return true
+		}
+	}
+	return false
 }
 
 // GetState returns the current lobby state (thread-safe)
@@ -147,11 +384,12 @@ func (l *Lobby) GetState() LobbyState {
 	return l.State
 }
 
-// CanStart returns true if the lobby can start a game
+// CanStart returns true if the lobby can start a game: it is ready, holds exactly BattlePlayers
+// and every player has submitted a team
 func (l *Lobby) CanStart() bool {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	return l.State == LobbyStateReady && len(l.Players) == l.MaxPlayers
+	return l.State == LobbyStateReady && len(l.Players) == BattlePlayers && l.allTeamsSubmitted()
 }
 
 // Start transitions the lobby from Ready to Active
@@ -163,14 +401,392 @@ func (l *Lobby) Start() error {
 		return ErrInvalidStateForStart
 	}
 
-	if len(l.Players) < l.MaxPlayers {
+	if len(l.Players) < BattlePlayers {
 		return ErrNotEnoughPlayers
 	}
+	if len(l.Players) > BattlePlayers {
+		return ErrTooManyPlayers
+	}
+
+	if !l.allTeamsSubmitted() {
+		return ErrTeamsNotSubmitted
+	}
 
 	l.State = LobbyStateActive
+	l.clearReady()
+	l.touch()
+	return nil
+}
+
+// GetRuleset returns the ruleset teams are validated against
+func (l *Lobby) GetRuleset() *Ruleset {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.ruleset
+}
+
+// SetRuleset changes the lobby's ruleset before the game starts.
+// Submitted teams that are not legal under the new ruleset are discarded and must be resubmitted.
+func (l *Lobby) SetRuleset(ruleset *Ruleset) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForRuleset
+	}
+
+	l.applyRuleset(ruleset)
+	l.touch()
+	return nil
+}
+
+// applyRuleset switches to the ruleset and discards submitted teams that are not legal under it.
+// The caller must hold l.mu.
+func (l *Lobby) applyRuleset(ruleset *Ruleset) {
+	l.ruleset = ruleset
+	for playerID, team := range l.teams {
+		if ruleset.Validate(team) != nil {
+			delete(l.teams, playerID)
+		}
+	}
+}
+
+// SubmitTeam validates a player's team against the lobby's ruleset and stores it,
+// replacing any earlier submission. Teams can only be changed before the game starts.
+func (l *Lobby) SubmitTeam(playerID string, team []TeamMember) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	team = SanitizeTeam(team)
+	if err := l.validateTeam(playerID, team); err != nil {
+		return err
+	}
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForTeam
+	}
+	if !l.hasPlayer(playerID) {
+		return ErrPlayerNotFound
+	}
+
+	l.teams[playerID] = team
+	l.touch()
+	return nil
+}
+
+// validateTeam checks a team against the ruleset and, in draft mode, the player's picks.
+// Requires the caller to hold the lock.
+func (l *Lobby) validateTeam(playerID string, team []TeamMember) error {
+	err := l.ruleset.Validate(team)
+	if !l.draftMode {
+		return err
+	}
+	if l.draft == nil || !l.draft.Complete() {
+		return ErrDraftInProgress
+	}
+
+	var violations []Violation
+	var teamErr *TeamError
+	if errors.As(err, &teamErr) {
+		violations = teamErr.Violations
+	}
+	violations = append(violations, l.draft.teamViolations(playerID, team)...)
+	if len(violations) > 0 {
+		return &TeamError{Violations: violations}
+	}
+	return nil
+}
+
+// GetTeam returns a copy of a player's submitted team
+func (l *Lobby) GetTeam(playerID string) ([]TeamMember, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	team, ok := l.teams[playerID]
+	if !ok {
+		return nil, false
+	}
+	return copyTeam(team), true
+}
+
+// HasTeam checks if a player has submitted a team
+func (l *Lobby) HasTeam(playerID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.teams[playerID]
+	return ok
+}
+
+// AllTeamsSubmitted returns true once every player in the lobby has submitted a team
+func (l *Lobby) AllTeamsSubmitted() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.allTeamsSubmitted()
+}
+
+// allTeamsSubmitted requires the caller to hold the lock
+func (l *Lobby) allTeamsSubmitted() bool {
+	for _, p := range l.Players {
+		if _, ok := l.teams[p.ID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPlayer requires the caller to hold the lock
+func (l *Lobby) hasPlayer(id string) bool {
+	for _, p := range l.Players {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// copyTeam returns a deep copy of a team so callers cannot mutate stored submissions
+func copyTeam(team []TeamMember) []TeamMember {
+	copied := make([]TeamMember, len(team))
+	for i, member := range team {
+		copied[i] = member
+		copied[i].Moves = append([]string(nil), member.Moves...)
+	}
+	return copied
+}
+
+// End transitions the lobby from Active to Finished once its game is over
+func (l *Lobby) End() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateActive {
+		return ErrInvalidStateForEnd
+	}
+
+	l.State = LobbyStateFinished
+	l.touch()
+	return nil
+}
+
+// Close transitions an Active or Finished lobby to Closed, after which no further games are played.
+// Any game in progress must be discarded by the caller.
+func (l *Lobby) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateActive && l.State != LobbyStateFinished {
+		return ErrInvalidStateForClose
+	}
+
+	l.State = LobbyStateClosed
+	l.touch()
+	return nil
+}
+
+// GetSeries returns a snapshot of the lobby's series score
+func (l *Lobby) GetSeries() Series {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.series.clone()
+}
+
+// SetSeriesLength configures the lobby as a best-of-N series before the first game starts
+func (l *Lobby) SetSeriesLength(bestOf int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !ValidSeriesLength(bestOf) {
+		return ErrInvalidSeriesLength
+	}
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForSeries
+	}
+
+	l.series = newSeries(bestOf)
+	l.touch()
+	return nil
+}
+
+// RecordWin counts a finished game towards the series and returns the updated score.
+// An empty winnerID records a draw, which counts as a game played but a win for neither player.
+func (l *Lobby) RecordWin(winnerID string) Series {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.series.Games++
+	if winnerID != "" {
+		l.series.Wins[winnerID]++
+	}
+	l.touch()
+	return l.series.clone()
+}
+
+// GetVisibility returns whether the lobby is public or unlisted
+func (l *Lobby) GetVisibility() LobbyVisibility {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.visibility
+}
+
+// SetVisibility configures whether the lobby appears in the lobby list.
+// Unlisted lobbies can still be joined by anyone who knows the code.
+func (l *Lobby) SetVisibility(visibility LobbyVisibility) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !ValidLobbyVisibility(visibility) {
+		return ErrUnknownVisibility
+	}
+
+	l.visibility = visibility
+	l.touch()
 	return nil
 }
 
+// GetRematchTeams returns whether the lobby's rematches keep the submitted teams or ask for new ones
+func (l *Lobby) GetRematchTeams() RematchTeams {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.rematchTeams
+}
+
+// SetRematchTeams configures the teams the lobby's rematches are played with.
+// It can be changed at any time outside a game.
+func (l *Lobby) SetRematchTeams(teams RematchTeams) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !ValidRematchTeams(teams) {
+		return ErrUnknownRematchTeams
+	}
+	if l.State == LobbyStateActive {
+		return ErrInvalidStateForRematchTeams
+	}
+
+	l.rematchTeams = teams
+	l.touch()
+	return nil
+}
+
+// Rematch transitions a finished lobby back to Ready for the next game. With RematchTeamsSame the
+// game can start straight away with the same teams; with RematchTeamsNew the human players' teams
+// are discarded and must be submitted again. Bots keep their team.
+// Once the series is over, a rematch starts a new series of the same length.
+func (l *Lobby) Rematch() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateFinished {
+		return ErrInvalidStateForRematch
+	}
+	if len(l.Players) < BattlePlayers {
+		return ErrNotEnoughPlayers
+	}
+
+	if l.rematchTeams == RematchTeamsNew {
+		for _, p := range l.Players {
+			if !p.Bot {
+				delete(l.teams, p.ID)
+			}
+		}
+	} else if !l.allTeamsSubmitted() {
+		return ErrTeamsNotSubmitted
+	}
+
+	if l.series.IsOver() {
+		l.series = newSeries(l.series.BestOf)
+	}
+	l.State = LobbyStateReady
+	l.touch()
+	return nil
+}
+
+// DraftMode returns true if teams must be built from drafted species
+func (l *Lobby) DraftMode() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.draftMode
+}
+
+// SetDraftMode turns draft mode on or off before the game starts.
+// Any draft in progress is discarded, and enabling draft mode discards submitted teams.
+func (l *Lobby) SetDraftMode(enabled bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForDraft
+	}
+	if enabled && l.hasBot() {
+		return ErrBotInDraftLobby
+	}
+
+	l.draftMode = enabled
+	l.draft = nil
+	if enabled {
+		l.teams = make(map[string][]TeamMember)
+	}
+	l.touch()
+	return nil
+}
+
+// StartDraft begins the draft once a draft-mode lobby holds two players, with the host banning first.
+// It returns false if the lobby is not in draft mode, does not hold exactly two players, or is already drafting.
+func (l *Lobby) StartDraft() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.draftMode || l.draft != nil || l.State != LobbyStateReady || len(l.Players) != BattlePlayers {
+		return false
+	}
+
+	first, second := l.Players[0].ID, l.Players[1].ID
+	if second == l.HostID {
+		first, second = second, first
+	}
+	l.draft = newDraft(first, second)
+	l.touch()
+	return true
+}
+
+// GetDraft returns a copy of the lobby's draft, or false if no draft has started
+func (l *Lobby) GetDraft() (*Draft, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.draft == nil {
+		return nil, false
+	}
+	return l.draft.clone(), true
+}
+
+// SubmitDraftPick bans or picks a species for the player whose draft turn it is
+func (l *Lobby) SubmitDraftPick(playerID, speciesID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.draft == nil {
+		return ErrDraftNotStarted
+	}
+	if err := l.draft.submit(playerID, speciesID); err != nil {
+		return err
+	}
+	l.touch()
+	return nil
+}
+
+// ExpireDraftTurn resolves a draft turn that ran out of time, skipping a ban or picking the
+// first available species. It returns false if the draft has already moved past step.
+func (l *Lobby) ExpireDraftTurn(step int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.draft == nil || !l.draft.expire(step) {
+		return false
+	}
+	l.touch()
+	return true
+}
+
 // PlayerCount returns the number of players in the lobby (thread-safe)
 func (l *Lobby) PlayerCount() int {
 	l.mu.RLock()
@@ -204,8 +820,11 @@ func (l *Lobby) GetPlayers() []*Player {
 	players := make([]*Player, len(l.Players))
 	for i, p := range l.Players {
 		players[i] = &Player{
-			ID:       p.ID,
-			Username: p.Username,
+			ID:            p.ID,
+			Username:      p.Username,
+			Bot:           p.Bot,
+			BotDifficulty: p.BotDifficulty,
+			Ready:         p.Ready || p.Bot,
 		}
 	}
 	return players
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Lobby invite errors
+var (
+	ErrInvalidInvite = errors.New("invite is invalid or has already been used")
+	ErrInviteExpired = errors.New("invite has expired")
+)
+
+// InviteTTL is how long an invite can be redeemed for after it is created
+const InviteTTL = 24 * time.Hour
+
+// inviteTokenBytes is the amount of randomness in an invite token, which is hex encoded
+const inviteTokenBytes = 16
+
+// Invite is a single-use token that lets one player join the lobby it was created for
+type Invite struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// CreateInvite issues a new invite that expires InviteTTL after now.
+// Expired invites are dropped as new ones are created so they don't accumulate.
+func (l *Lobby) CreateInvite(now time.Time) (Invite, error) {
+	raw := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return Invite{}, err
+	}
+	invite := Invite{Token: hex.EncodeToString(raw), ExpiresAt: now.Add(InviteTTL)}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for token, expiresAt := range l.invites {
+		if !now.Before(expiresAt) {
+			delete(l.invites, token)
+		}
+	}
+	l.invites[invite.Token] = invite.ExpiresAt
+	l.touch()
+	return invite, nil
+}
+
+// AddPlayerWithInvite adds a player who holds an invite to the lobby.
+// The invite is used up only if the player joins, so a player turned away by a full lobby can retry with it.
+func (l *Lobby) AddPlayerWithInvite(id, username, token string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt, ok := l.invites[token]
+	if !ok {
+		return ErrInvalidInvite
+	}
+	if !now.Before(expiresAt) {
+		delete(l.invites, token)
+		return ErrInviteExpired
+	}
+
+	if err := l.checkJoin(id); err != nil {
+		return err
+	}
+
+	delete(l.invites, token)
+	l.addPlayer(&Player{
+		ID:       id,
+		Username: username,
+	})
+	return nil
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateInvite(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	now := time.Now()
+
+	first, err := lobby.CreateInvite(now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, _ := lobby.CreateInvite(now)
+
+	if first.Token == "" || first.Token == second.Token {
+		t.Errorf("expected distinct tokens, got %q and %q", first.Token, second.Token)
+	}
+	if !first.ExpiresAt.Equal(now.Add(InviteTTL)) {
+		t.Errorf("expected invite to expire at %v, got %v", now.Add(InviteTTL), first.ExpiresAt)
+	}
+}
+
+func TestAddPlayerWithInvite_SingleUse(t *testing.T) {
+	lobby, _ := NewLobbyWithMaxPlayers("ABC123", "host-1", "Host", 4)
+	now := time.Now()
+	invite, _ := lobby.CreateInvite(now)
+
+	if err := lobby.AddPlayerWithInvite("player-2", "Player2", invite.Token, now); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.HasPlayer("player-2") {
+		t.Error("expected player-2 to be in the lobby")
+	}
+
+	if err := lobby.AddPlayerWithInvite("player-3", "Player3", invite.Token, now); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("expected ErrInvalidInvite for a used invite, got %v", err)
+	}
+}
+
+func TestAddPlayerWithInvite_Rejected(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		token    func(lobby *Lobby) string
+		at       time.Time
+		expected error
+	}{
+		{
+			name:     "unknown token",
+			token:    func(*Lobby) string { return "not-a-token" },
+			at:       now,
+			expected: ErrInvalidInvite,
+		},
+		{
+			name: "expired",
+			token: func(lobby *Lobby) string {
+				invite, _ := lobby.CreateInvite(now)
+				return invite.Token
+			},
+			at:       now.Add(InviteTTL),
+			expected: ErrInviteExpired,
+		},
+		{
+			name: "other lobby's token",
+			token: func(*Lobby) string {
+				invite, _ := NewLobby("DEF456", "host-2", "Host2").CreateInvite(now)
+				return invite.Token
+			},
+			at:       now,
+			expected: ErrInvalidInvite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lobby := NewLobby("ABC123", "host-1", "Host")
+			if err := lobby.AddPlayerWithInvite("player-2", "Player2", tt.token(lobby), tt.at); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+			if lobby.HasPlayer("player-2") {
+				t.Error("expected player-2 not to join")
+			}
+		})
+	}
+}
+
+func TestAddPlayerWithInvite_KeptWhenJoinFails(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	now := time.Now()
+	invite, _ := lobby.CreateInvite(now)
+	lobby.AddPlayer("player-2", "Player2")
+
+	if err := lobby.AddPlayerWithInvite("player-3", "Player3", invite.Token, now); !errors.Is(err, ErrInvalidStateForJoin) {
+		t.Fatalf("expected ErrInvalidStateForJoin, got %v", err)
+	}
+
+	// Once a slot opens up the same invite still works
+	lobby.RemovePlayer("player-2")
+	if err := lobby.AddPlayerWithInvite("player-3", "Player3", invite.Token, now); err != nil {
+		t.Errorf("expected invite to still be usable, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+import "errors"
+
+// ErrInvalidStateForReady is returned when a player changes their ready state after the game has started
+var ErrInvalidStateForReady = errors.New("cannot change ready state in current state")
+
+// SetReady marks a player ready or not ready for the game to start.
+// Ready state can only change before the game starts.
+func (l *Lobby) SetReady(playerID string, ready bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForReady
+	}
+	for _, p := range l.Players {
+		if p.ID == playerID {
+			p.Ready = ready
+			l.touch()
+			return nil
+		}
+	}
+	return ErrPlayerNotFound
+}
+
+// IsReady returns true if the player is ready for the game to start; bots are always ready
+func (l *Lobby) IsReady(playerID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, p := range l.Players {
+		if p.ID == playerID {
+			return p.Ready || p.Bot
+		}
+	}
+	return false
+}
+
+// AllReady returns true if every player is ready for the game to start
+func (l *Lobby) AllReady() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, p := range l.Players {
+		if !p.Ready && !p.Bot {
+			return false
+		}
+	}
+	return true
+}
+
+// clearReady marks every player not ready, so each game needs the players to ready up again.
+// Requires the caller to hold the lock.
+func (l *Lobby) clearReady() {
+	for _, p := range l.Players {
+		p.Ready = false
+	}
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetReady(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+
+	if err := lobby.SetReady("host-1", true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.IsReady("host-1") {
+		t.Error("expected host to be ready")
+	}
+	if lobby.AllReady() {
+		t.Error("expected lobby not to be all ready while player-2 is not")
+	}
+
+	lobby.SetReady("player-2", true)
+	if !lobby.AllReady() {
+		t.Error("expected lobby to be all ready")
+	}
+
+	lobby.SetReady("host-1", false)
+	if lobby.IsReady("host-1") {
+		t.Error("expected host to be not ready")
+	}
+	if players := lobby.GetPlayers(); players[0].Ready || !players[1].Ready {
+		t.Errorf("expected GetPlayers to report ready state, got %v and %v", players[0].Ready, players[1].Ready)
+	}
+}
+
+func TestSetReady_PlayerNotFound(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.SetReady("player-2", true); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestSetReady_ClearedWhenGameStarts(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	lobby.SubmitTeam("host-1", StarterTeam())
+	lobby.SubmitTeam("player-2", StarterTeam())
+	lobby.SetReady("host-1", true)
+	lobby.SetReady("player-2", true)
+
+	if err := lobby.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	if lobby.IsReady("host-1") || lobby.IsReady("player-2") {
+		t.Error("expected ready state to be cleared when the game starts")
+	}
+	if err := lobby.SetReady("host-1", true); !errors.Is(err, ErrInvalidStateForReady) {
+		t.Errorf("expected ErrInvalidStateForReady, got %v", err)
+	}
+}
+
+func TestIsReady_Bot(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	bot, _ := lobby.AddBot("")
+	lobby.SetReady("host-1", true)
+
+	if !lobby.IsReady(bot.ID) {
+		t.Error("expected bot to always be ready")
+	}
+	if !lobby.AllReady() {
+		t.Error("expected lobby to be all ready with a ready host and a bot")
+	}
+}
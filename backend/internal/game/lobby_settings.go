@@ -0,0 +1,144 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// Lobby settings errors
+var (
+	ErrInvalidStateForSettings = errors.New("lobby settings can only be changed while waiting for players")
+	ErrInvalidTurnTimer        = errors.New("turn timer must be between 0 and 300 seconds")
+	ErrInvalidCountdown        = errors.New("countdown must be between 0 and 30 seconds")
+	ErrUnknownStartMode        = errors.New("start mode must be auto or host")
+)
+
+// Lobby settings limits
+const (
+	MaxTurnTimer = 5 * time.Minute
+	MaxCountdown = 30 * time.Second
+)
+
+// StartMode decides how a lobby's game starts once every player is ready
+type StartMode string
+
+const (
+	StartModeAuto StartMode = "auto" // The game starts as soon as every player is ready
+	StartModeHost StartMode = "host" // Every player readies up, then the host starts the game
+)
+
+// ValidStartMode reports whether a lobby can be configured with the start mode
+func ValidStartMode(mode StartMode) bool {
+	return mode == StartModeAuto || mode == StartModeHost
+}
+
+// LobbySettings are the options the host configures before the game starts
+type LobbySettings struct {
+	Ruleset       *Ruleset
+	BestOf        int           // Games in the lobby's series, its match format
+	TurnTimer     time.Duration // How long players have to choose each turn's action; 0 for no limit
+	Countdown     time.Duration // How long players are warned before the game starts; 0 to start immediately
+	StartMode     StartMode
+	Visibility    LobbyVisibility
+	MaxSpectators int        // How many spectators may watch at once; 0 turns spectating off
+	Tags          []LobbyTag // Whitelisted tags players can filter the lobby list by
+}
+
+// LobbySettingsUpdate lists the settings to change; nil fields are left as they are
+type LobbySettingsUpdate struct {
+	Ruleset       *Ruleset
+	BestOf        *int
+	TurnTimer     *time.Duration
+	Countdown     *time.Duration
+	StartMode     *StartMode
+	Visibility    *LobbyVisibility
+	MaxSpectators *int
+	Tags          *[]LobbyTag // Replaces every tag; duplicates are dropped
+}
+
+// Settings returns the lobby's current settings
+func (l *Lobby) Settings() LobbySettings {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.settings()
+}
+
+// settings describes the lobby's current settings. The caller must hold l.mu.
+func (l *Lobby) settings() LobbySettings {
+	return LobbySettings{
+		Ruleset:       l.ruleset,
+		BestOf:        l.series.BestOf,
+		TurnTimer:     l.turnTimer,
+		Countdown:     l.countdown,
+		StartMode:     l.startMode,
+		Visibility:    l.visibility,
+		MaxSpectators: l.maxSpectators,
+		Tags:          append([]LobbyTag(nil), l.tags...),
+	}
+}
+
+// UpdateSettings changes the settings listed in update while the lobby is waiting for players
+// and returns the resulting settings. Either every listed setting is applied or, on error, none are.
+// A new ruleset discards submitted teams that are not legal under it, and a new series length
+// restarts the series score.
+func (l *Lobby) UpdateSettings(update LobbySettingsUpdate) (LobbySettings, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State != LobbyStateWaiting {
+		return LobbySettings{}, ErrInvalidStateForSettings
+	}
+	if update.BestOf != nil && !ValidSeriesLength(*update.BestOf) {
+		return LobbySettings{}, ErrInvalidSeriesLength
+	}
+	if update.TurnTimer != nil && (*update.TurnTimer < 0 || *update.TurnTimer > MaxTurnTimer) {
+		return LobbySettings{}, ErrInvalidTurnTimer
+	}
+	if update.Countdown != nil && (*update.Countdown < 0 || *update.Countdown > MaxCountdown) {
+		return LobbySettings{}, ErrInvalidCountdown
+	}
+	if update.StartMode != nil && !ValidStartMode(*update.StartMode) {
+		return LobbySettings{}, ErrUnknownStartMode
+	}
+	if update.Visibility != nil && !ValidLobbyVisibility(*update.Visibility) {
+		return LobbySettings{}, ErrUnknownVisibility
+	}
+	if update.MaxSpectators != nil && (*update.MaxSpectators < 0 || *update.MaxSpectators > MaxSpectators) {
+		return LobbySettings{}, ErrInvalidMaxSpectators
+	}
+	var tags []LobbyTag
+	if update.Tags != nil {
+		var err error
+		if tags, err = normalizeLobbyTags(*update.Tags); err != nil {
+			return LobbySettings{}, err
+		}
+	}
+
+	if update.Ruleset != nil {
+		l.applyRuleset(update.Ruleset)
+	}
+	if update.BestOf != nil {
+		l.series = newSeries(*update.BestOf)
+	}
+	if update.TurnTimer != nil {
+		l.turnTimer = *update.TurnTimer
+	}
+	if update.Countdown != nil {
+		l.countdown = *update.Countdown
+	}
+	if update.StartMode != nil {
+		l.startMode = *update.StartMode
+	}
+	if update.Visibility != nil {
+		l.visibility = *update.Visibility
+	}
+	// Spectators already watching stay when the limit is lowered; only new ones are turned away
+	if update.MaxSpectators != nil {
+		l.maxSpectators = *update.MaxSpectators
+	}
+	if update.Tags != nil {
+		l.tags = tags
+	}
+	l.touch()
+	return l.settings(), nil
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLobbySettings_Defaults(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	settings := lobby.Settings()
+	if settings.Ruleset.ID != DefaultRulesetID {
+		t.Errorf("expected ruleset %q, got %q", DefaultRulesetID, settings.Ruleset.ID)
+	}
+	if settings.BestOf != DefaultSeriesLength {
+		t.Errorf("expected best of %d, got %d", DefaultSeriesLength, settings.BestOf)
+	}
+	if settings.TurnTimer != 0 || settings.Countdown != 0 {
+		t.Errorf("expected no turn timer or countdown, got %v and %v", settings.TurnTimer, settings.Countdown)
+	}
+	if settings.Visibility != LobbyVisibilityPublic {
+		t.Errorf("expected public, got %q", settings.Visibility)
+	}
+	if settings.StartMode != StartModeAuto {
+		t.Errorf("expected start mode auto, got %q", settings.StartMode)
+	}
+}
+
+func TestUpdateSettings_AppliesListedSettings(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	competitive, _ := LookupRuleset("competitive")
+	bestOf := 3
+	turnTimer := time.Minute
+
+	settings, err := lobby.UpdateSettings(LobbySettingsUpdate{Ruleset: competitive, BestOf: &bestOf, TurnTimer: &turnTimer})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if settings.Ruleset != competitive || settings.BestOf != 3 || settings.TurnTimer != time.Minute {
+		t.Errorf("expected the listed settings to change, got %+v", settings)
+	}
+	if settings.Countdown != 0 || settings.Visibility != LobbyVisibilityPublic {
+		t.Errorf("expected unlisted settings to be left as they are, got %+v", settings)
+	}
+	if lobby.GetRuleset() != competitive || lobby.GetSeries().BestOf != 3 {
+		t.Error("expected the ruleset and series to follow the settings")
+	}
+}
+
+func TestUpdateSettings_RejectsInvalidWithoutApplying(t *testing.T) {
+	valid, even := 3, 2
+	tooLong := MaxTurnTimer + time.Second
+	negative := -time.Second
+	unknown := LobbyVisibility("private")
+	manual := StartMode("manual")
+	tooManySpectators := MaxSpectators + 1
+
+	tests := []struct {
+		name   string
+		update LobbySettingsUpdate
+		want   error
+	}{
+		{"series length", LobbySettingsUpdate{BestOf: &even}, ErrInvalidSeriesLength},
+		{"turn timer", LobbySettingsUpdate{BestOf: &valid, TurnTimer: &tooLong}, ErrInvalidTurnTimer},
+		{"countdown", LobbySettingsUpdate{BestOf: &valid, Countdown: &negative}, ErrInvalidCountdown},
+		{"visibility", LobbySettingsUpdate{BestOf: &valid, Visibility: &unknown}, ErrUnknownVisibility},
+		{"start mode", LobbySettingsUpdate{BestOf: &valid, StartMode: &manual}, ErrUnknownStartMode},
+		{"max spectators", LobbySettingsUpdate{BestOf: &valid, MaxSpectators: &tooManySpectators}, ErrInvalidMaxSpectators},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lobby := NewLobby("ABC123", "host-1", "Host")
+
+			if _, err := lobby.UpdateSettings(tt.update); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if lobby.GetSeries().BestOf != DefaultSeriesLength {
+				t.Error("expected no setting to change")
+			}
+		})
+	}
+}
+
+func TestUpdateSettings_OnlyWhileWaiting(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+
+	countdown := 5 * time.Second
+	if _, err := lobby.UpdateSettings(LobbySettingsUpdate{Countdown: &countdown}); !errors.Is(err, ErrInvalidStateForSettings) {
+		t.Errorf("expected ErrInvalidStateForSettings, got %v", err)
+	}
+}
+
+func TestUpdateSettings_RulesetDiscardsIllegalTeams(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	team := StarterTeam()
+	team[0].Level = MaxLevel
+	if err := lobby.SubmitTeam("host-1", team); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+
+	competitive, _ := LookupRuleset("competitive")
+	if _, err := lobby.UpdateSettings(LobbySettingsUpdate{Ruleset: competitive}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.HasTeam("host-1") {
+		t.Error("expected a team over the competitive level cap to be discarded")
+	}
+}
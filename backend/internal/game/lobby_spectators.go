@@ -0,0 +1,102 @@
+package game
+
+import "errors"
+
+// Spectator errors
+var (
+	ErrSpectatorsFull       = errors.New("no spectator slots left")
+	ErrAlreadySpectating    = errors.New("player already spectating")
+	ErrSpectatorNotFound    = errors.New("spectator not found in lobby")
+	ErrInvalidMaxSpectators = errors.New("max spectators must be between 0 and 50")
+)
+
+// Spectator limits
+const (
+	DefaultMaxSpectators = 10
+	MaxSpectators        = 50
+)
+
+// Spectator is someone watching the lobby without playing in it
+type Spectator struct {
+	ID       string
+	Username string
+}
+
+// AddSpectator adds someone to the lobby's spectator roster.
+// Spectators may join in any state, since watching a game in progress is the point.
+func (l *Lobby) AddSpectator(id, username string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range l.Players {
+		if p.ID == id {
+			return ErrPlayerAlreadyJoined
+		}
+	}
+	if l.hasSpectator(id) {
+		return ErrAlreadySpectating
+	}
+	if l.waitlistPosition(id) > 0 {
+		return ErrAlreadyWaitlisted
+	}
+	if len(l.spectators) >= l.maxSpectators {
+		return ErrSpectatorsFull
+	}
+
+	l.spectators = append(l.spectators, &Spectator{ID: id, Username: username})
+	l.touch()
+	return nil
+}
+
+// RemoveSpectator takes someone off the lobby's spectator roster
+func (l *Lobby) RemoveSpectator(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.removeSpectator(id) {
+		return ErrSpectatorNotFound
+	}
+	l.touch()
+	return nil
+}
+
+// removeSpectator deletes the spectator and reports whether they were present.
+// Requires the caller to hold the lock.
+func (l *Lobby) removeSpectator(id string) bool {
+	for i, s := range l.spectators {
+		if s.ID == id {
+			l.spectators = append(l.spectators[:i], l.spectators[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HasSpectator returns true if the ID is on the lobby's spectator roster
+func (l *Lobby) HasSpectator(id string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.hasSpectator(id)
+}
+
+// hasSpectator requires the caller to hold the lock
+func (l *Lobby) hasSpectator(id string) bool {
+	for _, s := range l.spectators {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSpectators returns a copy of the spectator roster in the order they joined
+func (l *Lobby) GetSpectators() []Spectator {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	spectators := make([]Spectator, len(l.spectators))
+	for i, s := range l.spectators {
+		spectators[i] = *s
+	}
+	return spectators
+}
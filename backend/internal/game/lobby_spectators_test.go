@@ -0,0 +1,84 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddSpectator(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.AddSpectator("watcher-1", "Watcher"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.HasSpectator("watcher-1") {
+		t.Error("expected watcher-1 to be spectating")
+	}
+	if lobby.PlayerCount() != 1 {
+		t.Errorf("expected spectators not to count as players, got %d players", lobby.PlayerCount())
+	}
+
+	spectators := lobby.GetSpectators()
+	if len(spectators) != 1 || spectators[0].ID != "watcher-1" || spectators[0].Username != "Watcher" {
+		t.Errorf("unexpected spectator roster %+v", spectators)
+	}
+}
+
+func TestAddSpectator_Rejected(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddSpectator("watcher-1", "Watcher")
+
+	if err := lobby.AddSpectator("watcher-1", "Watcher"); !errors.Is(err, ErrAlreadySpectating) {
+		t.Errorf("expected ErrAlreadySpectating, got %v", err)
+	}
+	if err := lobby.AddSpectator("host-1", "Host"); !errors.Is(err, ErrPlayerAlreadyJoined) {
+		t.Errorf("expected ErrPlayerAlreadyJoined, got %v", err)
+	}
+
+	limit := 1
+	lobby.UpdateSettings(LobbySettingsUpdate{MaxSpectators: &limit})
+	if err := lobby.AddSpectator("watcher-2", "Watcher2"); !errors.Is(err, ErrSpectatorsFull) {
+		t.Errorf("expected ErrSpectatorsFull, got %v", err)
+	}
+}
+
+func TestAddSpectator_DuringGame(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	lobby.SubmitTeam("host-1", StarterTeam())
+	lobby.SubmitTeam("player-2", StarterTeam())
+	if err := lobby.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	if err := lobby.AddSpectator("watcher-1", "Watcher"); err != nil {
+		t.Errorf("expected to spectate a game in progress, got %v", err)
+	}
+}
+
+func TestRemoveSpectator(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddSpectator("watcher-1", "Watcher")
+
+	if err := lobby.RemoveSpectator("watcher-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.HasSpectator("watcher-1") {
+		t.Error("expected watcher-1 to be removed")
+	}
+	if err := lobby.RemoveSpectator("watcher-1"); !errors.Is(err, ErrSpectatorNotFound) {
+		t.Errorf("expected ErrSpectatorNotFound, got %v", err)
+	}
+}
+
+func TestAddPlayer_PromotesSpectator(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddSpectator("watcher-1", "Watcher")
+
+	if err := lobby.AddPlayer("watcher-1", "Watcher"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.HasSpectator("watcher-1") || !lobby.HasPlayer("watcher-1") {
+		t.Error("expected watcher-1 to move from the spectator roster to the players")
+	}
+}
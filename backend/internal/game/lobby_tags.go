@@ -0,0 +1,108 @@
+package game
+
+import (
+	"errors"
+	"strings"
+)
+
+// Lobby tag errors
+var (
+	ErrUnknownLobbyTag  = errors.New("unknown lobby tag")
+	ErrTooManyLobbyTags = errors.New("a lobby can have at most 5 tags")
+)
+
+// MaxLobbyTags is how many tags a lobby may carry
+const MaxLobbyTags = 5
+
+// LobbyTag describes a lobby so players can find the ones that suit them, e.g. by region or skill level
+type LobbyTag string
+
+// Regions
+const (
+	LobbyTagNorthAmerica LobbyTag = "na"
+	LobbyTagSouthAmerica LobbyTag = "sa"
+	LobbyTagEurope       LobbyTag = "eu"
+	LobbyTagAsia         LobbyTag = "asia"
+	LobbyTagOceania      LobbyTag = "oce"
+)
+
+// Skill levels and play styles
+const (
+	LobbyTagBeginner       LobbyTag = "beginner"
+	LobbyTagIntermediate   LobbyTag = "intermediate"
+	LobbyTagExpert         LobbyTag = "expert"
+	LobbyTagNewbiesWelcome LobbyTag = "newbies-welcome"
+	LobbyTagCasual         LobbyTag = "casual"
+	LobbyTagCompetitive    LobbyTag = "competitive"
+)
+
+// lobbyTags is the whitelist of tags a lobby may carry, in display order
+var lobbyTags = []LobbyTag{
+	LobbyTagNorthAmerica, LobbyTagSouthAmerica, LobbyTagEurope, LobbyTagAsia, LobbyTagOceania,
+	LobbyTagBeginner, LobbyTagIntermediate, LobbyTagExpert,
+	LobbyTagNewbiesWelcome, LobbyTagCasual, LobbyTagCompetitive,
+}
+
+// ListLobbyTags returns every tag a lobby may carry
+func ListLobbyTags() []LobbyTag {
+	return append([]LobbyTag(nil), lobbyTags...)
+}
+
+// ParseLobbyTag returns the whitelisted tag matching s, ignoring case and surrounding whitespace
+func ParseLobbyTag(s string) (LobbyTag, error) {
+	tag := LobbyTag(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range lobbyTags {
+		if tag == known {
+			return tag, nil
+		}
+	}
+	return "", ErrUnknownLobbyTag
+}
+
+// normalizeLobbyTags parses a lobby's tags, dropping duplicates.
+// It fails if any tag is not whitelisted or there are more than MaxLobbyTags.
+func normalizeLobbyTags(tags []LobbyTag) ([]LobbyTag, error) {
+	normalized := make([]LobbyTag, 0, len(tags))
+	for _, t := range tags {
+		tag, err := ParseLobbyTag(string(t))
+		if err != nil {
+			return nil, err
+		}
+		if !hasLobbyTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxLobbyTags {
+		return nil, ErrTooManyLobbyTags
+	}
+	return normalized, nil
+}
+
+// hasLobbyTag returns true if tag is among tags
+func hasLobbyTag(tags []LobbyTag, tag LobbyTag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTags returns a copy of the lobby's tags
+func (l *Lobby) GetTags() []LobbyTag {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]LobbyTag(nil), l.tags...)
+}
+
+// HasTags returns true if the lobby carries every one of the tags
+func (l *Lobby) HasTags(tags []LobbyTag) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, tag := range tags {
+		if !hasLobbyTag(l.tags, tag) {
+			return false
+		}
+	}
+	return true
+}
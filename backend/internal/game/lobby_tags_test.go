@@ -0,0 +1,60 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseLobbyTag(t *testing.T) {
+	tag, err := ParseLobbyTag("  Newbies-Welcome ")
+	if err != nil || tag != LobbyTagNewbiesWelcome {
+		t.Errorf("expected %q, got %q, %v", LobbyTagNewbiesWelcome, tag, err)
+	}
+
+	if _, err := ParseLobbyTag("moon"); !errors.Is(err, ErrUnknownLobbyTag) {
+		t.Errorf("expected ErrUnknownLobbyTag, got %v", err)
+	}
+}
+
+func TestUpdateSettings_Tags(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	tags := []LobbyTag{"EU", LobbyTagBeginner, LobbyTagEurope}
+	settings, err := lobby.UpdateSettings(LobbySettingsUpdate{Tags: &tags})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []LobbyTag{LobbyTagEurope, LobbyTagBeginner}
+	if !reflect.DeepEqual(settings.Tags, want) || !reflect.DeepEqual(lobby.GetTags(), want) {
+		t.Errorf("expected tags %v without duplicates, got %v", want, lobby.GetTags())
+	}
+	if !lobby.HasTags([]LobbyTag{LobbyTagBeginner}) || lobby.HasTags([]LobbyTag{LobbyTagBeginner, LobbyTagAsia}) {
+		t.Error("expected HasTags to require every tag")
+	}
+
+	cleared := []LobbyTag{}
+	lobby.UpdateSettings(LobbySettingsUpdate{Tags: &cleared})
+	if len(lobby.GetTags()) != 0 {
+		t.Errorf("expected tags to be cleared, got %v", lobby.GetTags())
+	}
+}
+
+func TestUpdateSettings_InvalidTags(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	unknown := []LobbyTag{LobbyTagEurope, "moon"}
+	if _, err := lobby.UpdateSettings(LobbySettingsUpdate{Tags: &unknown}); !errors.Is(err, ErrUnknownLobbyTag) {
+		t.Errorf("expected ErrUnknownLobbyTag, got %v", err)
+	}
+
+	tooMany := ListLobbyTags()[:MaxLobbyTags+1]
+	if _, err := lobby.UpdateSettings(LobbySettingsUpdate{Tags: &tooMany}); !errors.Is(err, ErrTooManyLobbyTags) {
+		t.Errorf("expected ErrTooManyLobbyTags, got %v", err)
+	}
+
+	if len(lobby.GetTags()) != 0 {
+		t.Errorf("expected a rejected update to leave the tags unchanged, got %v", lobby.GetTags())
+	}
+}
@@ -1,11 +1,23 @@
 package game
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
+// submitStarterTeams submits the starter team for every player in the lobby
+func submitStarterTeams(t *testing.T, lobby *Lobby) {
+	t.Helper()
+	for _, p := range lobby.GetPlayers() {
+		if err := lobby.SubmitTeam(p.ID, StarterTeam()); err != nil {
+			t.Fatalf("failed to submit team for %s: %v", p.ID, err)
+		}
+	}
+}
+
 // ========================================
 // Happy Path Tests
 // ========================================
@@ -60,7 +72,7 @@ func TestRemovePlayer_Success(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
 
-	err := lobby.RemovePlayer("player-2")
+	_, err := lobby.RemovePlayer("player-2")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -80,7 +92,7 @@ func TestRemovePlayer_ReassignsHost(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
 
-	err := lobby.RemovePlayer("host-1")
+	_, err := lobby.RemovePlayer("host-1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -99,6 +111,7 @@ func TestRemovePlayer_ReassignsHost(t *testing.T) {
 func TestStart_Success(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
 
 	err := lobby.Start()
 	if err != nil {
@@ -119,6 +132,12 @@ func TestCanStart_ReturnsTrueWhenReady(t *testing.T) {
 
 	lobby.AddPlayer("player-2", "Player2")
 
+	if lobby.CanStart() {
+		t.Error("expected CanStart to be false until teams are submitted")
+	}
+
+	submitStarterTeams(t, lobby)
+
 	if !lobby.CanStart() {
 		t.Error("expected CanStart to be true with 2 players in Ready state")
 	}
@@ -180,6 +199,8 @@ func TestLobbyState_String(t *testing.T) {
 		{LobbyStateWaiting, "waiting"},
 		{LobbyStateReady, "ready"},
 		{LobbyStateActive, "active"},
+		{LobbyStateFinished, "finished"},
+		{LobbyStateClosed, "closed"},
 		{LobbyState(99), "unknown"},
 	}
 
@@ -190,6 +211,37 @@ func TestLobbyState_String(t *testing.T) {
 	}
 }
 
+func TestParseLobbyState(t *testing.T) {
+	for _, state := range []LobbyState{LobbyStateWaiting, LobbyStateReady, LobbyStateActive, LobbyStateFinished, LobbyStateClosed} {
+		got, ok := ParseLobbyState(state.String())
+		if !ok || got != state {
+			t.Errorf("ParseLobbyState(%q) = %v, %v; want %v, true", state.String(), got, ok, state)
+		}
+	}
+
+	if _, ok := ParseLobbyState("unknown"); ok {
+		t.Error("expected unknown state to be rejected")
+	}
+}
+
+func TestHasOpenSlot(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	if !lobby.HasOpenSlot() {
+		t.Error("expected waiting lobby to have an open slot")
+	}
+
+	lobby.AddPlayer("player-2", "Player2")
+	if lobby.HasOpenSlot() {
+		t.Error("expected full lobby to have no open slot")
+	}
+
+	larger, _ := NewLobbyWithMaxPlayers("DEF456", "host-1", "Host", 4)
+	larger.AddPlayer("player-2", "Player2")
+	if !larger.HasOpenSlot() {
+		t.Error("expected ready lobby below capacity to have an open slot")
+	}
+}
+
 // ========================================
 // Validation Error Tests
 // ========================================
@@ -220,6 +272,103 @@ func TestAddPlayer_LobbyFullInWaitingState(t *testing.T) {
 	}
 }
 
+func TestNewLobbyWithMaxPlayers_Range(t *testing.T) {
+	for _, maxPlayers := range []int{MinPlayers, MaxLobbyPlayers} {
+		lobby, err := NewLobbyWithMaxPlayers("ABC123", "host-1", "Host", maxPlayers)
+		if err != nil || lobby.MaxPlayers != maxPlayers {
+			t.Errorf("expected a lobby for %d players, got %v", maxPlayers, err)
+		}
+	}
+	for _, maxPlayers := range []int{0, 1, MaxLobbyPlayers + 1} {
+		if _, err := NewLobbyWithMaxPlayers("ABC123", "host-1", "Host", maxPlayers); !errors.Is(err, ErrInvalidMaxPlayers) {
+			t.Errorf("max %d: expected ErrInvalidMaxPlayers, got %v", maxPlayers, err)
+		}
+	}
+	if lobby := NewLobby("ABC123", "host-1", "Host"); lobby.MaxPlayers != DefaultMaxPlayers {
+		t.Errorf("expected NewLobby to hold %d players, got %d", DefaultMaxPlayers, lobby.MaxPlayers)
+	}
+}
+
+func TestSetVisibility(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	if lobby.GetVisibility() != LobbyVisibilityPublic {
+		t.Errorf("expected new lobbies to be public, got %q", lobby.GetVisibility())
+	}
+
+	if err := lobby.SetVisibility(LobbyVisibilityUnlisted); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetVisibility() != LobbyVisibilityUnlisted {
+		t.Errorf("expected unlisted, got %q", lobby.GetVisibility())
+	}
+	if err := lobby.SetVisibility("private"); !errors.Is(err, ErrUnknownVisibility) {
+		t.Errorf("expected ErrUnknownVisibility, got %v", err)
+	}
+}
+
+func TestLastActivity_UpdatedOnChanges(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	if !lobby.LastActivity().Equal(lobby.CreatedAt) {
+		t.Errorf("expected last activity to start at creation, got %v", lobby.LastActivity())
+	}
+
+	created := lobby.LastActivity()
+	time.Sleep(time.Millisecond)
+	lobby.AddPlayer("player-2", "Player2")
+	if !lobby.LastActivity().After(created) {
+		t.Error("expected a player joining to count as activity")
+	}
+
+	joined := lobby.LastActivity()
+	time.Sleep(time.Millisecond)
+	lobby.SubmitTeam("host-1", StarterTeam())
+	if !lobby.LastActivity().After(joined) {
+		t.Error("expected a team submission to count as activity")
+	}
+
+	submitted := lobby.LastActivity()
+	time.Sleep(time.Millisecond)
+	lobby.SetVisibility(LobbyVisibilityUnlisted)
+	if !lobby.LastActivity().After(submitted) {
+		t.Error("expected a visibility change to count as activity")
+	}
+}
+
+func TestLargerLobby_ReadyWithTwoAndJoinableUntilFull(t *testing.T) {
+	lobby, _ := NewLobbyWithMaxPlayers("ABC123", "host-1", "Host", 3)
+
+	lobby.AddPlayer("player-2", "Player2")
+	if lobby.GetState() != LobbyStateReady {
+		t.Fatalf("expected Ready with two players, got %s", lobby.GetState())
+	}
+	if err := lobby.AddPlayer("player-3", "Player3"); err != nil {
+		t.Fatalf("expected a ready lobby with a free slot to accept players, got %v", err)
+	}
+	if err := lobby.AddPlayer("player-4", "Player4"); !errors.Is(err, ErrInvalidStateForJoin) {
+		t.Errorf("expected a full lobby to reject players, got %v", err)
+	}
+
+	// Battles are fought between two players
+	for _, id := range []string{"host-1", "player-2", "player-3"} {
+		lobby.SubmitTeam(id, StarterTeam())
+	}
+	if lobby.CanStart() {
+		t.Error("expected three players not to be able to start a battle")
+	}
+	if err := lobby.Start(); !errors.Is(err, ErrTooManyPlayers) {
+		t.Errorf("expected ErrTooManyPlayers, got %v", err)
+	}
+
+	lobby.RemovePlayer("player-3")
+	if !lobby.CanStart() {
+		t.Error("expected two players with teams to be able to start")
+	}
+	lobby.RemovePlayer("player-2")
+	if lobby.GetState() != LobbyStateWaiting {
+		t.Errorf("expected Waiting with one player, got %s", lobby.GetState())
+	}
+}
+
 func TestAddPlayer_DuplicatePlayer(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 
@@ -243,7 +392,7 @@ func TestAddPlayer_InvalidState(t *testing.T) {
 func TestRemovePlayer_NotFound(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 
-	err := lobby.RemovePlayer("nonexistent")
+	_, err := lobby.RemovePlayer("nonexistent")
 	if err != ErrPlayerNotFound {
 		t.Errorf("expected ErrPlayerNotFound, got %v", err)
 	}
@@ -307,6 +456,7 @@ func TestStateTransition_ReadyToWaiting(t *testing.T) {
 func TestStateTransition_ReadyToActive(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
 
 	if lobby.GetState() != LobbyStateReady {
 		t.Fatalf("expected state Ready, got %v", lobby.GetState())
@@ -319,6 +469,71 @@ func TestStateTransition_ReadyToActive(t *testing.T) {
 	}
 }
 
+func TestStateTransition_ActiveToFinished(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+
+	if err := lobby.End(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if lobby.GetState() != LobbyStateFinished {
+		t.Errorf("expected state Finished after game end, got %v", lobby.GetState())
+	}
+	if err := lobby.AddPlayer("player-3", "Player3"); err != ErrInvalidStateForJoin {
+		t.Errorf("expected finished lobby to reject joins, got %v", err)
+	}
+}
+
+func TestStateTransition_ToClosed(t *testing.T) {
+	for _, end := range []bool{false, true} {
+		lobby := NewLobby("ABC123", "host-1", "Host")
+		lobby.AddPlayer("player-2", "Player2")
+		submitStarterTeams(t, lobby)
+		lobby.Start()
+		if end {
+			lobby.End()
+		}
+
+		if err := lobby.Close(); err != nil {
+			t.Fatalf("expected no error closing from %v, got %v", end, err)
+		}
+		if lobby.GetState() != LobbyStateClosed {
+			t.Errorf("expected state Closed, got %v", lobby.GetState())
+		}
+		if err := lobby.End(); err != ErrInvalidStateForEnd {
+			t.Errorf("expected closed lobby to reject End, got %v", err)
+		}
+	}
+}
+
+func TestClose_InvalidState(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.Close(); err != ErrInvalidStateForClose {
+		t.Errorf("expected ErrInvalidStateForClose for a waiting lobby, got %v", err)
+	}
+
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+	lobby.Close()
+	if err := lobby.Close(); err != ErrInvalidStateForClose {
+		t.Errorf("expected ErrInvalidStateForClose for a closed lobby, got %v", err)
+	}
+}
+
+func TestEnd_InvalidState(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	err := lobby.End()
+	if err != ErrInvalidStateForEnd {
+		t.Errorf("expected ErrInvalidStateForEnd, got %v", err)
+	}
+}
+
 func TestStateTransition_NoTransitionOnFirstAdd(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 
@@ -371,7 +586,7 @@ func TestRemovePlayer_HostOfTwo(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
 
-	err := lobby.RemovePlayer("host-1")
+	_, err := lobby.RemovePlayer("host-1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -392,7 +607,7 @@ func TestRemovePlayer_HostOfTwo(t *testing.T) {
 func TestRemovePlayer_OnlyPlayer(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 
-	err := lobby.RemovePlayer("host-1")
+	_, err := lobby.RemovePlayer("host-1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -406,6 +621,7 @@ func TestRemovePlayer_OnlyPlayer(t *testing.T) {
 func TestStart_CalledTwice(t *testing.T) {
 	lobby := NewLobby("ABC123", "host-1", "Host")
 	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
 
 	err := lobby.Start()
 	if err != nil {
@@ -418,6 +634,151 @@ func TestStart_CalledTwice(t *testing.T) {
 	}
 }
 
+// ========================================
+// Team Submission Tests
+// ========================================
+
+func TestSubmitTeam_Success(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.SubmitTeam("host-1", StarterTeam()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	team, ok := lobby.GetTeam("host-1")
+	if !ok || len(team) != len(StarterTeam()) {
+		t.Errorf("expected stored starter team, got %+v", team)
+	}
+	if !lobby.HasTeam("host-1") {
+		t.Error("expected HasTeam to be true after submission")
+	}
+}
+
+func TestSubmitTeam_ReturnsCopy(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.SubmitTeam("host-1", StarterTeam())
+
+	team, _ := lobby.GetTeam("host-1")
+	team[0].Moves[0] = "splash"
+
+	stored, _ := lobby.GetTeam("host-1")
+	if stored[0].Moves[0] == "splash" {
+		t.Error("expected stored team to be unaffected by changes to a returned copy")
+	}
+}
+
+func TestSubmitTeam_InvalidTeam(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	err := lobby.SubmitTeam("host-1", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"surf"}}})
+	if !errors.Is(err, ErrMoveNotLearnable) {
+		t.Errorf("expected ErrMoveNotLearnable, got %v", err)
+	}
+	if lobby.HasTeam("host-1") {
+		t.Error("expected invalid team not to be stored")
+	}
+}
+
+func TestSubmitTeam_PlayerNotInLobby(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.SubmitTeam("stranger", StarterTeam()); err != ErrPlayerNotFound {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestSubmitTeam_AfterStart(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+
+	if err := lobby.SubmitTeam("host-1", StarterTeam()); err != ErrInvalidStateForTeam {
+		t.Errorf("expected ErrInvalidStateForTeam, got %v", err)
+	}
+}
+
+func TestStart_TeamsNotSubmitted(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	lobby.SubmitTeam("host-1", StarterTeam())
+
+	if err := lobby.Start(); err != ErrTeamsNotSubmitted {
+		t.Errorf("expected ErrTeamsNotSubmitted, got %v", err)
+	}
+	if lobby.GetState() != LobbyStateReady {
+		t.Errorf("expected state to remain Ready, got %v", lobby.GetState())
+	}
+}
+
+func TestRemovePlayer_DiscardsTeam(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	lobby.SubmitTeam("player-2", StarterTeam())
+
+	lobby.RemovePlayer("player-2")
+
+	if lobby.HasTeam("player-2") {
+		t.Error("expected team to be discarded when the player leaves")
+	}
+}
+
+// ========================================
+// Ruleset Tests
+// ========================================
+
+func TestNewLobby_DefaultRuleset(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if lobby.GetRuleset().ID != DefaultRulesetID {
+		t.Errorf("expected ruleset %q, got %q", DefaultRulesetID, lobby.GetRuleset().ID)
+	}
+}
+
+func TestSubmitTeam_ValidatedAgainstLobbyRuleset(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	competitive, _ := LookupRuleset("competitive")
+	lobby.SetRuleset(competitive)
+
+	pikachu := TeamMember{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}}
+	err := lobby.SubmitTeam("host-1", []TeamMember{pikachu, pikachu})
+	if !errors.Is(err, ErrSpeciesClause) {
+		t.Errorf("expected ErrSpeciesClause, got %v", err)
+	}
+}
+
+func TestSetRuleset_DiscardsTeamsThatBecomeIllegal(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+
+	pikachu := TeamMember{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}}
+	lobby.SubmitTeam("host-1", StarterTeam())
+	lobby.SubmitTeam("player-2", []TeamMember{pikachu, pikachu})
+
+	competitive, _ := LookupRuleset("competitive")
+	if err := lobby.SetRuleset(competitive); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !lobby.HasTeam("host-1") {
+		t.Error("expected legal team to be kept")
+	}
+	if lobby.HasTeam("player-2") {
+		t.Error("expected team breaking the species clause to be discarded")
+	}
+}
+
+func TestSetRuleset_AfterStart(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+
+	if err := lobby.SetRuleset(DefaultRuleset()); err != ErrInvalidStateForRuleset {
+		t.Errorf("expected ErrInvalidStateForRuleset, got %v", err)
+	}
+}
+
 // ========================================
 // Concurrency Tests
 // ========================================
@@ -0,0 +1,120 @@
+package game
+
+import "errors"
+
+// Waitlist errors
+var (
+	ErrLobbyNotFull       = errors.New("lobby has a free slot, join it directly")
+	ErrAlreadyWaitlisted  = errors.New("player already on the waitlist")
+	ErrNotWaitlisted      = errors.New("player not on the waitlist")
+	ErrWaitlistFull       = errors.New("waitlist is full")
+	ErrWaitlistSpectating = errors.New("spectators must stop watching before joining the waitlist")
+)
+
+// MaxWaitlist is how many players may queue for a lobby's next free slot
+const MaxWaitlist = 10
+
+// WaitlistEntry is a player queued for a full lobby's next free slot
+type WaitlistEntry struct {
+	ID       string
+	Username string
+}
+
+// JoinWaitlist queues a player for the next slot to open in a full lobby.
+// Players are promoted into the lobby in the order they joined the waitlist.
+func (l *Lobby) JoinWaitlist(id, username string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A full lobby is no longer open, so check the state it would be joinable in
+	if l.State != LobbyStateWaiting && l.State != LobbyStateReady {
+		return ErrInvalidStateForJoin
+	}
+	for _, p := range l.Players {
+		if p.ID == id {
+			return ErrPlayerAlreadyJoined
+		}
+	}
+	if l.hasSpectator(id) {
+		return ErrWaitlistSpectating
+	}
+	if l.waitlistPosition(id) > 0 {
+		return ErrAlreadyWaitlisted
+	}
+	if len(l.Players) < l.MaxPlayers {
+		return ErrLobbyNotFull
+	}
+	if len(l.waitlist) >= MaxWaitlist {
+		return ErrWaitlistFull
+	}
+
+	l.waitlist = append(l.waitlist, &WaitlistEntry{ID: id, Username: username})
+	l.touch()
+	return nil
+}
+
+// LeaveWaitlist takes a player off the lobby's waitlist
+func (l *Lobby) LeaveWaitlist(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.removeFromWaitlist(id) {
+		return ErrNotWaitlisted
+	}
+	l.touch()
+	return nil
+}
+
+// removeFromWaitlist takes a player off the waitlist, returning false if they weren't on it.
+// Requires the caller to hold the lock.
+func (l *Lobby) removeFromWaitlist(id string) bool {
+	position := l.waitlistPosition(id)
+	if position == 0 {
+		return false
+	}
+	l.waitlist = append(l.waitlist[:position-1], l.waitlist[position:]...)
+	return true
+}
+
+// WaitlistPosition returns the player's 1-based place on the waitlist, or 0 if they are not on it
+func (l *Lobby) WaitlistPosition(id string) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.waitlistPosition(id)
+}
+
+// waitlistPosition requires the caller to hold the lock
+func (l *Lobby) waitlistPosition(id string) int {
+	for i, w := range l.waitlist {
+		if w.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// GetWaitlist returns a copy of the waitlist, first in line first
+func (l *Lobby) GetWaitlist() []WaitlistEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	waitlist := make([]WaitlistEntry, len(l.waitlist))
+	for i, w := range l.waitlist {
+		waitlist[i] = *w
+	}
+	return waitlist
+}
+
+// promoteWaitlist moves players from the front of the waitlist into the lobby's free slots
+// while it is still open, and returns them in the order they were promoted.
+// Requires the caller to hold the lock.
+func (l *Lobby) promoteWaitlist() []WaitlistEntry {
+	var promoted []WaitlistEntry
+	for len(l.waitlist) > 0 && l.open() && len(l.Players) < l.MaxPlayers {
+		next := l.waitlist[0]
+		l.waitlist = l.waitlist[1:]
+		l.addPlayer(&Player{ID: next.ID, Username: next.Username})
+		promoted = append(promoted, *next)
+	}
+	return promoted
+}
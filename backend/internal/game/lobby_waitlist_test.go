@@ -0,0 +1,147 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// fullLobby returns a two-player lobby with no free slot
+func fullLobby() *Lobby {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	return lobby
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	lobby := fullLobby()
+
+	if err := lobby.JoinWaitlist("waiter-1", "Waiter1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := lobby.JoinWaitlist("waiter-2", "Waiter2"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if pos := lobby.WaitlistPosition("waiter-1"); pos != 1 {
+		t.Errorf("expected waiter-1 first in line, got position %d", pos)
+	}
+	if pos := lobby.WaitlistPosition("waiter-2"); pos != 2 {
+		t.Errorf("expected waiter-2 second in line, got position %d", pos)
+	}
+	if pos := lobby.WaitlistPosition("host-1"); pos != 0 {
+		t.Errorf("expected players not to be on the waitlist, got position %d", pos)
+	}
+	if lobby.PlayerCount() != 2 {
+		t.Errorf("expected waitlisted players not to count as players, got %d players", lobby.PlayerCount())
+	}
+}
+
+func TestJoinWaitlist_Rejected(t *testing.T) {
+	notFull := NewLobby("ABC123", "host-1", "Host")
+	if err := notFull.JoinWaitlist("waiter-1", "Waiter1"); !errors.Is(err, ErrLobbyNotFull) {
+		t.Errorf("expected ErrLobbyNotFull, got %v", err)
+	}
+
+	lobby := fullLobby()
+	lobby.AddSpectator("watcher-1", "Watcher")
+	lobby.JoinWaitlist("waiter-1", "Waiter1")
+
+	if err := lobby.JoinWaitlist("waiter-1", "Waiter1"); !errors.Is(err, ErrAlreadyWaitlisted) {
+		t.Errorf("expected ErrAlreadyWaitlisted, got %v", err)
+	}
+	if err := lobby.JoinWaitlist("player-2", "Player2"); !errors.Is(err, ErrPlayerAlreadyJoined) {
+		t.Errorf("expected ErrPlayerAlreadyJoined, got %v", err)
+	}
+	if err := lobby.JoinWaitlist("watcher-1", "Watcher"); !errors.Is(err, ErrWaitlistSpectating) {
+		t.Errorf("expected ErrWaitlistSpectating, got %v", err)
+	}
+	if err := lobby.AddSpectator("waiter-1", "Waiter1"); !errors.Is(err, ErrAlreadyWaitlisted) {
+		t.Errorf("expected waitlisted players not to spectate, got %v", err)
+	}
+
+	for i := lobby.WaitlistPosition("waiter-1"); i < MaxWaitlist; i++ {
+		lobby.JoinWaitlist(string(rune('a'+i)), "Filler")
+	}
+	if err := lobby.JoinWaitlist("waiter-late", "Late"); !errors.Is(err, ErrWaitlistFull) {
+		t.Errorf("expected ErrWaitlistFull, got %v", err)
+	}
+}
+
+func TestJoinWaitlist_DuringGame(t *testing.T) {
+	lobby := fullLobby()
+	lobby.SubmitTeam("host-1", StarterTeam())
+	lobby.SubmitTeam("player-2", StarterTeam())
+	if err := lobby.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	if err := lobby.JoinWaitlist("waiter-1", "Waiter1"); !errors.Is(err, ErrInvalidStateForJoin) {
+		t.Errorf("expected ErrInvalidStateForJoin, got %v", err)
+	}
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	lobby := fullLobby()
+	lobby.JoinWaitlist("waiter-1", "Waiter1")
+	lobby.JoinWaitlist("waiter-2", "Waiter2")
+
+	if err := lobby.LeaveWaitlist("waiter-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pos := lobby.WaitlistPosition("waiter-2"); pos != 1 {
+		t.Errorf("expected waiter-2 to move up to first, got position %d", pos)
+	}
+	if err := lobby.LeaveWaitlist("waiter-1"); !errors.Is(err, ErrNotWaitlisted) {
+		t.Errorf("expected ErrNotWaitlisted, got %v", err)
+	}
+}
+
+func TestRemovePlayer_PromotesWaitlist(t *testing.T) {
+	lobby := fullLobby()
+	lobby.JoinWaitlist("waiter-1", "Waiter1")
+	lobby.JoinWaitlist("waiter-2", "Waiter2")
+
+	promoted, err := lobby.RemovePlayer("host-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(promoted) != 1 || promoted[0].ID != "waiter-1" || promoted[0].Username != "Waiter1" {
+		t.Fatalf("expected waiter-1 to be promoted, got %+v", promoted)
+	}
+	if !lobby.HasPlayer("waiter-1") {
+		t.Error("expected waiter-1 to be a player")
+	}
+	if lobby.GetState() != LobbyStateReady {
+		t.Errorf("expected lobby to be ready with two players again, got %s", lobby.GetState())
+	}
+	if lobby.GetHostID() != "player-2" {
+		t.Errorf("expected host to pass to the longest-standing player, got %s", lobby.GetHostID())
+	}
+
+	waitlist := lobby.GetWaitlist()
+	if len(waitlist) != 1 || waitlist[0].ID != "waiter-2" {
+		t.Errorf("expected waiter-2 to be left waiting, got %+v", waitlist)
+	}
+}
+
+func TestRemovePlayer_NoPromotionOnceStarted(t *testing.T) {
+	lobby := fullLobby()
+	lobby.JoinWaitlist("waiter-1", "Waiter1")
+	lobby.SubmitTeam("host-1", StarterTeam())
+	lobby.SubmitTeam("player-2", StarterTeam())
+	if err := lobby.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	promoted, err := lobby.RemovePlayer("player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(promoted) != 0 {
+		t.Errorf("expected no promotion into a game in progress, got %+v", promoted)
+	}
+	if lobby.HasPlayer("waiter-1") {
+		t.Error("expected waiter-1 to stay on the waitlist")
+	}
+}
@@ -0,0 +1,115 @@
+package game
+
+import "errors"
+
+// ErrUnknownMove is returned when a move ID is not in the catalogue
+var ErrUnknownMove = errors.New("unknown move")
+
+// MoveCategory determines which stats a move uses
+type MoveCategory int
+
+const (
+	MoveCategoryPhysical MoveCategory = iota // Uses Attack vs Defense
+	MoveCategorySpecial                      // Uses SpAttack vs SpDefense
+	MoveCategoryStatus                       // Deals no direct damage
+)
+
+// String returns a human-readable representation of the move category
+func (c MoveCategory) String() string {
+	switch c {
+	case MoveCategoryPhysical:
+		return "physical"
+	case MoveCategorySpecial:
+		return "special"
+	case MoveCategoryStatus:
+		return "status"
+	default:
+		return "unknown"
+	}
+}
+
+// Move is the static definition of a move
+type Move struct {
+	ID       string
+	Name     string
+	Type     Type
+	Category MoveCategory
+	Power    int
+	Accuracy int // Percent chance to hit; 0 means the move never misses
+	PP       int
+	Priority int
+	// CritStage is added to the user's critical stage for this move only
+	CritStage int
+
+	// Volatile is applied to the target when the move connects
+	Volatile VolatileStatus
+	// Secondary effects may trigger on the target after the move deals damage
+	Secondary []SecondaryEffect
+	// UserStatChanges and TargetStatChanges are stage changes applied when the move connects
+	UserStatChanges   []StatChange
+	TargetStatChanges []StatChange
+	// Hazard is laid on the target's side of the field
+	Hazard Hazard
+	// HazardRemoval clears hazards from the field when the move connects
+	HazardRemoval HazardRemoval
+	// Terrain is set on the field when the move is used
+	Terrain Terrain
+	// RecoilDivisor deals 1/RecoilDivisor of the user's max HP back to the user after a hit; 0 means no recoil
+	RecoilDivisor int
+}
+
+// TargetsUser returns true for status moves that only affect the user
+func (m *Move) TargetsUser() bool {
+	return m.Category == MoveCategoryStatus && m.Volatile == VolatileNone && len(m.TargetStatChanges) == 0 &&
+		m.Hazard == HazardNone && m.HazardRemoval == HazardRemovalNone && m.Terrain == TerrainNone
+}
+
+// StruggleMoveID identifies the move a creature is forced to use once every move is out of PP
+const StruggleMoveID = "struggle"
+
+// struggleMove is not learnable and never consumes PP
+var struggleMove = &Move{ID: StruggleMoveID, Name: "Struggle", Type: TypeNone, Category: MoveCategoryPhysical, Power: 50, RecoilDivisor: 4}
+
+// moveCatalogue holds every move in data version 1, keyed by ID
+var moveCatalogue = map[string]*Move{
+	"tackle":           {ID: "tackle", Name: "Tackle", Type: TypeNormal, Category: MoveCategoryPhysical, Power: 40, Accuracy: 100, PP: 35},
+	"quick-attack":     {ID: "quick-attack", Name: "Quick Attack", Type: TypeNormal, Category: MoveCategoryPhysical, Power: 40, Accuracy: 100, PP: 30, Priority: 1},
+	"thunderbolt":      {ID: "thunderbolt", Name: "Thunderbolt", Type: TypeElectric, Category: MoveCategorySpecial, Power: 90, Accuracy: 100, PP: 15},
+	"flamethrower":     {ID: "flamethrower", Name: "Flamethrower", Type: TypeFire, Category: MoveCategorySpecial, Power: 90, Accuracy: 100, PP: 15, Secondary: []SecondaryEffect{{Chance: 10, Status: StatusBurn}}},
+	"surf":             {ID: "surf", Name: "Surf", Type: TypeWater, Category: MoveCategorySpecial, Power: 90, Accuracy: 100, PP: 15},
+	"razor-leaf":       {ID: "razor-leaf", Name: "Razor Leaf", Type: TypeGrass, Category: MoveCategoryPhysical, Power: 55, Accuracy: 95, PP: 25, CritStage: 1},
+	"slash":            {ID: "slash", Name: "Slash", Type: TypeNormal, Category: MoveCategoryPhysical, Power: 70, Accuracy: 100, PP: 20, CritStage: 1},
+	"hydro-pump":       {ID: "hydro-pump", Name: "Hydro Pump", Type: TypeWater, Category: MoveCategorySpecial, Power: 110, Accuracy: 80, PP: 5},
+	"earthquake":       {ID: "earthquake", Name: "Earthquake", Type: TypeGround, Category: MoveCategoryPhysical, Power: 100, Accuracy: 100, PP: 10},
+	"ice-beam":         {ID: "ice-beam", Name: "Ice Beam", Type: TypeIce, Category: MoveCategorySpecial, Power: 90, Accuracy: 100, PP: 10},
+	"psychic":          {ID: "psychic", Name: "Psychic", Type: TypePsychic, Category: MoveCategorySpecial, Power: 90, Accuracy: 100, PP: 10, Secondary: []SecondaryEffect{{Chance: 10, TargetStatChanges: []StatChange{{Stat: StatSpDefense, Stages: -1}}}}},
+	"iron-head":        {ID: "iron-head", Name: "Iron Head", Type: TypeSteel, Category: MoveCategoryPhysical, Power: 80, Accuracy: 100, PP: 15, Secondary: []SecondaryEffect{{Chance: 30, Flinch: true}}},
+	"bite":             {ID: "bite", Name: "Bite", Type: TypeDark, Category: MoveCategoryPhysical, Power: 60, Accuracy: 100, PP: 25, Secondary: []SecondaryEffect{{Chance: 30, Flinch: true}}},
+	"fake-out":         {ID: "fake-out", Name: "Fake Out", Type: TypeNormal, Category: MoveCategoryPhysical, Power: 40, Accuracy: 100, PP: 10, Priority: 3, Secondary: []SecondaryEffect{{Chance: 100, Flinch: true}}},
+	"confuse-ray":      {ID: "confuse-ray", Name: "Confuse Ray", Type: TypeGhost, Category: MoveCategoryStatus, Accuracy: 100, PP: 10, Volatile: VolatileConfusion},
+	"leech-seed":       {ID: "leech-seed", Name: "Leech Seed", Type: TypeGrass, Category: MoveCategoryStatus, Accuracy: 90, PP: 10, Volatile: VolatileLeechSeed},
+	"swords-dance":     {ID: "swords-dance", Name: "Swords Dance", Type: TypeNormal, Category: MoveCategoryStatus, PP: 20, UserStatChanges: []StatChange{{Stat: StatAttack, Stages: 2}}},
+	"agility":          {ID: "agility", Name: "Agility", Type: TypePsychic, Category: MoveCategoryStatus, PP: 30, UserStatChanges: []StatChange{{Stat: StatSpeed, Stages: 2}}},
+	"calm-mind":        {ID: "calm-mind", Name: "Calm Mind", Type: TypePsychic, Category: MoveCategoryStatus, PP: 20, UserStatChanges: []StatChange{{Stat: StatSpAttack, Stages: 1}, {Stat: StatSpDefense, Stages: 1}}},
+	"growl":            {ID: "growl", Name: "Growl", Type: TypeNormal, Category: MoveCategoryStatus, Accuracy: 100, PP: 40, TargetStatChanges: []StatChange{{Stat: StatAttack, Stages: -1}}},
+	"tail-whip":        {ID: "tail-whip", Name: "Tail Whip", Type: TypeNormal, Category: MoveCategoryStatus, Accuracy: 100, PP: 30, TargetStatChanges: []StatChange{{Stat: StatDefense, Stages: -1}}},
+	"focus-energy":     {ID: "focus-energy", Name: "Focus Energy", Type: TypeNormal, Category: MoveCategoryStatus, PP: 30, UserStatChanges: []StatChange{{Stat: StatCritical, Stages: 2}}},
+	"double-team":      {ID: "double-team", Name: "Double Team", Type: TypeNormal, Category: MoveCategoryStatus, PP: 15, UserStatChanges: []StatChange{{Stat: StatEvasion, Stages: 1}}},
+	"sand-attack":      {ID: "sand-attack", Name: "Sand Attack", Type: TypeGround, Category: MoveCategoryStatus, Accuracy: 100, PP: 15, TargetStatChanges: []StatChange{{Stat: StatAccuracy, Stages: -1}}},
+	"stealth-rock":     {ID: "stealth-rock", Name: "Stealth Rock", Type: TypeRock, Category: MoveCategoryStatus, PP: 20, Hazard: HazardStealthRock},
+	"spikes":           {ID: "spikes", Name: "Spikes", Type: TypeGround, Category: MoveCategoryStatus, PP: 20, Hazard: HazardSpikes},
+	"toxic-spikes":     {ID: "toxic-spikes", Name: "Toxic Spikes", Type: TypePoison, Category: MoveCategoryStatus, PP: 20, Hazard: HazardToxicSpikes},
+	"rapid-spin":       {ID: "rapid-spin", Name: "Rapid Spin", Type: TypeNormal, Category: MoveCategoryPhysical, Power: 50, Accuracy: 100, PP: 40, HazardRemoval: HazardRemovalUserSide},
+	"defog":            {ID: "defog", Name: "Defog", Type: TypeFlying, Category: MoveCategoryStatus, PP: 15, HazardRemoval: HazardRemovalBothSides},
+	"electric-terrain": {ID: "electric-terrain", Name: "Electric Terrain", Type: TypeElectric, Category: MoveCategoryStatus, PP: 10, Terrain: TerrainElectric},
+	"grassy-terrain":   {ID: "grassy-terrain", Name: "Grassy Terrain", Type: TypeGrass, Category: MoveCategoryStatus, PP: 10, Terrain: TerrainGrassy},
+	"psychic-terrain":  {ID: "psychic-terrain", Name: "Psychic Terrain", Type: TypePsychic, Category: MoveCategoryStatus, PP: 10, Terrain: TerrainPsychic},
+	"misty-terrain":    {ID: "misty-terrain", Name: "Misty Terrain", Type: TypeFairy, Category: MoveCategoryStatus, PP: 10, Terrain: TerrainMisty},
+	"outrage":          {ID: "outrage", Name: "Outrage", Type: TypeDragon, Category: MoveCategoryPhysical, Power: 120, Accuracy: 100, PP: 10},
+	"close-combat":     {ID: "close-combat", Name: "Close Combat", Type: TypeFighting, Category: MoveCategoryPhysical, Power: 120, Accuracy: 100, PP: 5, UserStatChanges: []StatChange{{Stat: StatDefense, Stages: -1}, {Stat: StatSpDefense, Stages: -1}}},
+}
+
+// LookupMove returns the current data's entry for a move ID
+func LookupMove(id string) (*Move, error) {
+	return CurrentDataPack().LookupMove(id)
+}
@@ -0,0 +1,107 @@
+package game
+
+import "errors"
+
+// ErrBattleOver is returned for any action submitted after the battle has ended
+var ErrBattleOver = errors.New("battle is over")
+
+// EndReason describes why a battle ended
+type EndReason string
+
+const (
+	EndReasonVictory            EndReason = "victory"
+	EndReasonForfeit            EndReason = "forfeit"
+	EndReasonDraw               EndReason = "draw"                // Both players agreed to a draw
+	EndReasonTurnLimit          EndReason = "turn_limit"          // Decided by remaining HP once the turn limit was reached
+	EndReasonEndlessBattle      EndReason = "endless_battle"      // Decided by remaining HP after too many turns without progress
+	EndReasonOpponentDisconnect EndReason = "opponent_disconnect" // The loser stayed disconnected past the grace period
+)
+
+// BattleOutcome records the result of a finished battle
+type BattleOutcome struct {
+	WinnerID string // Empty for a draw, as is LoserID
+	LoserID  string
+	Reason   EndReason
+	Seed     int64  // RNG seed of the battle, revealed once it is over so it can be re-simulated
+	SeedSalt string // Salt of the battle's seed commitment, revealed with the seed so the commitment can be checked
+}
+
+// IsDraw returns true if the battle ended without a winner
+func (o *BattleOutcome) IsDraw() bool {
+	return o.WinnerID == ""
+}
+
+// Outcome returns the battle result, or nil while the battle is in progress
+func (b *Battle) Outcome() *BattleOutcome {
+	return b.outcome
+}
+
+// Forfeit ends the battle with the forfeiting player as the loser
+func (b *Battle) Forfeit(playerID string) (*BattleOutcome, error) {
+	return b.forfeit(playerID, EndReasonForfeit)
+}
+
+// ForfeitForDisconnect ends the battle in the opponent's favour because the player did not return in time
+func (b *Battle) ForfeitForDisconnect(playerID string) (*BattleOutcome, error) {
+	return b.forfeit(playerID, EndReasonOpponentDisconnect)
+}
+
+// forfeit ends the battle with the player as the loser for the given reason
+func (b *Battle) forfeit(playerID string, reason EndReason) (*BattleOutcome, error) {
+	if b.outcome != nil {
+		return nil, ErrBattleOver
+	}
+
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	b.end(b.Sides[1-idx].PlayerID, playerID, reason)
+	return b.outcome, nil
+}
+
+// AllFainted returns true once every creature on the side has fainted
+func (s *BattleSide) AllFainted() bool {
+	for _, c := range s.Team {
+		if !c.IsFainted() {
+			return false
+		}
+	}
+	return true
+}
+
+// checkVictory ends the battle once a side has no creatures left standing.
+// If both sides fall in the same turn, the player whose creature fainted last wins.
+func (b *Battle) checkVictory() {
+	out0, out1 := b.Sides[0].AllFainted(), b.Sides[1].AllFainted()
+	switch {
+	case out0 && out1:
+		winner := b.lastFaintedOwner()
+		loser := b.Sides[0].PlayerID
+		if loser == winner {
+			loser = b.Sides[1].PlayerID
+		}
+		b.end(winner, loser, EndReasonVictory)
+	case out0:
+		b.end(b.Sides[1].PlayerID, b.Sides[0].PlayerID, EndReasonVictory)
+	case out1:
+		b.end(b.Sides[0].PlayerID, b.Sides[1].PlayerID, EndReasonVictory)
+	}
+}
+
+// lastFaintedOwner returns the owner of the last creature to faint this turn
+func (b *Battle) lastFaintedOwner() string {
+	for i := len(b.events) - 1; i >= 0; i-- {
+		if b.events[i].Type == EventCreatureFainted {
+			return b.events[i].Actor
+		}
+	}
+	return b.Sides[0].PlayerID
+}
+
+// end records the outcome and discards any pending actions
+func (b *Battle) end(winnerID, loserID string, reason EndReason) {
+	b.outcome = &BattleOutcome{WinnerID: winnerID, LoserID: loserID, Reason: reason, Seed: b.Seed, SeedSalt: b.seedSalt}
+	b.pending = [2]*Action{}
+}
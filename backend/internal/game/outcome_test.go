@@ -0,0 +1,120 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Forfeit Tests
+// ========================================
+
+func TestForfeit_ForfeitingPlayerLoses(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	outcome, err := b.Forfeit("player-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if outcome.LoserID != "player-1" || outcome.WinnerID != "player-2" || outcome.Reason != EndReasonForfeit {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+	if b.Outcome() != outcome {
+		t.Error("expected outcome to be recorded on the battle")
+	}
+}
+
+func TestForfeitForDisconnect_DisconnectedPlayerLoses(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	outcome, err := b.ForfeitForDisconnect("player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if outcome.LoserID != "player-2" || outcome.WinnerID != "player-1" || outcome.Reason != EndReasonOpponentDisconnect {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+}
+
+func TestForfeit_DiscardsPendingActions(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.SubmitAction("player-2", Action{Kind: ActionKindMove, MoveID: "tackle"})
+
+	b.Forfeit("player-1")
+
+	if b.AllActionsSubmitted() {
+		t.Error("expected no pending actions after forfeit")
+	}
+	if _, err := b.ResolveTurn(); !errors.Is(err, ErrActionsPending) {
+		t.Errorf("expected turn resolution to be impossible, got %v", err)
+	}
+}
+
+func TestForfeit_BattleAlreadyOver(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+	b.Forfeit("player-1")
+
+	if _, err := b.Forfeit("player-2"); !errors.Is(err, ErrBattleOver) {
+		t.Errorf("expected ErrBattleOver, got %v", err)
+	}
+	if err := b.SubmitAction("player-2", Action{Kind: ActionKindMove, MoveID: "tackle"}); !errors.Is(err, ErrBattleOver) {
+		t.Errorf("expected actions to be rejected after the battle ended, got %v", err)
+	}
+}
+
+func TestForfeit_PlayerNotInBattle(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	if _, err := b.Forfeit("stranger"); !errors.Is(err, ErrPlayerNotInBattle) {
+		t.Errorf("expected ErrPlayerNotInBattle, got %v", err)
+	}
+}
+
+// ========================================
+// Victory Tests
+// ========================================
+
+func TestVictory_LastCreatureFainted(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50, "tackle")
+	defender.CurrentHP = 1
+	b := newTestBattle(&scriptedRNG{fallback: 15}, attacker, defender)
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	outcome := b.Outcome()
+	if outcome == nil {
+		t.Fatal("expected battle to end")
+	}
+	if outcome.WinnerID != "player-1" || outcome.LoserID != "player-2" || outcome.Reason != EndReasonVictory {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+}
+
+func TestVictory_NotWhileBenchRemains(t *testing.T) {
+	b := newForcedSwitchBattle(t)
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	if b.Outcome() != nil {
+		t.Errorf("expected battle to continue while a healthy creature remains, got %+v", b.Outcome())
+	}
+}
+
+func TestVictory_RecoilUserWinsWhenBothFall(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	user.PP["tackle"] = 0
+	user.CurrentHP = 10
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	foe.CurrentHP = 1
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	resolveTurn(t, b, StruggleMoveID, "growl")
+
+	outcome := b.Outcome()
+	if outcome == nil || outcome.WinnerID != "player-1" {
+		t.Errorf("expected the struggling player to win when both fall, got %+v", outcome)
+	}
+}
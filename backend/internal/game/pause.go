@@ -0,0 +1,148 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxPauseBudget is the total time each player may hold a battle paused over the whole game
+const MaxPauseBudget = 3 * time.Minute
+
+var (
+	// ErrPauseAlreadyRequested is returned when a pause is requested while a request is still unanswered
+	ErrPauseAlreadyRequested = errors.New("pause already requested")
+	// ErrNoPauseRequest is returned when responding without an opposing pause request to answer
+	ErrNoPauseRequest = errors.New("no pause request to respond to")
+	// ErrBattlePaused is returned for choices made while the battle is paused
+	ErrBattlePaused = errors.New("battle is paused")
+	// ErrBattleNotPaused is returned when resuming a battle that is not paused
+	ErrBattleNotPaused = errors.New("battle is not paused")
+	// ErrPauseBudgetExhausted is returned when a player has no pause time left
+	ErrPauseBudgetExhausted = errors.New("pause budget exhausted")
+)
+
+// PauseReason describes why a battle was paused
+type PauseReason string
+
+const (
+	PauseReasonRequested  PauseReason = "requested"  // both players agreed to pause
+	PauseReasonDisconnect PauseReason = "disconnect" // a player lost their connection
+)
+
+// Pause describes a battle pause in progress
+type Pause struct {
+	PlayerID string // player whose pause budget the pause is charged to
+	Reason   PauseReason
+	Since    time.Time
+}
+
+// RequestPause asks the opponent to pause the battle. The request stands until the opponent responds
+// or the battle is paused for another reason.
+func (b *Battle) RequestPause(playerID string) error {
+	if b.outcome != nil {
+		return ErrBattleOver
+	}
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return err
+	}
+	if b.pause != nil {
+		return ErrBattlePaused
+	}
+	if b.pauseRequest != "" {
+		return ErrPauseAlreadyRequested
+	}
+	if b.pauseUsed[idx] >= MaxPauseBudget {
+		return ErrPauseBudgetExhausted
+	}
+
+	b.pauseRequest = playerID
+	return nil
+}
+
+// RespondPause answers the opponent's pause request. Accepting pauses the battle from now,
+// charged to the requesting player, and returns the pause; declining withdraws the request and returns nil.
+func (b *Battle) RespondPause(playerID string, accept bool, now time.Time) (*Pause, error) {
+	if b.outcome != nil {
+		return nil, ErrBattleOver
+	}
+	if _, err := b.sideIndex(playerID); err != nil {
+		return nil, err
+	}
+	if b.pauseRequest == "" || b.pauseRequest == playerID {
+		return nil, ErrNoPauseRequest
+	}
+
+	requester := b.pauseRequest
+	b.pauseRequest = ""
+	if !accept {
+		return nil, nil
+	}
+	b.pause = &Pause{PlayerID: requester, Reason: PauseReasonRequested, Since: now}
+	return b.Paused(), nil
+}
+
+// PauseForDisconnect pauses the battle for a player who lost their connection, charged to that player.
+// A player with no pause time left is not given a pause.
+func (b *Battle) PauseForDisconnect(playerID string, now time.Time) (*Pause, error) {
+	if b.outcome != nil {
+		return nil, ErrBattleOver
+	}
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if b.pause != nil {
+		return nil, ErrBattlePaused
+	}
+	if b.pauseUsed[idx] >= MaxPauseBudget {
+		return nil, ErrPauseBudgetExhausted
+	}
+
+	b.pauseRequest = ""
+	b.pause = &Pause{PlayerID: playerID, Reason: PauseReasonDisconnect, Since: now}
+	return b.Paused(), nil
+}
+
+// Resume ends the pause in progress, charging its length to the paused player's budget
+func (b *Battle) Resume(now time.Time) error {
+	if b.pause == nil {
+		return ErrBattleNotPaused
+	}
+
+	idx, err := b.sideIndex(b.pause.PlayerID)
+	if err != nil {
+		return err
+	}
+	b.pauseUsed[idx] = min(b.pauseUsed[idx]+now.Sub(b.pause.Since), MaxPauseBudget)
+	b.pause = nil
+	return nil
+}
+
+// Paused returns the pause in progress, or nil if the battle is not paused
+func (b *Battle) Paused() *Pause {
+	if b.pause == nil {
+		return nil
+	}
+	pause := *b.pause
+	return &pause
+}
+
+// PauseRequest returns the player with an unanswered pause request, or "" if there is none
+func (b *Battle) PauseRequest() string {
+	return b.pauseRequest
+}
+
+// PauseBudget returns how much pause time a player has left, counting the pause in progress if it is theirs
+func (b *Battle) PauseBudget(playerID string, now time.Time) time.Duration {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return 0
+	}
+
+	used := b.pauseUsed[idx]
+	if b.pause != nil && b.pause.PlayerID == playerID {
+		used += now.Sub(b.pause.Since)
+	}
+	return max(MaxPauseBudget-used, 0)
+}
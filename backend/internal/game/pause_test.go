@@ -0,0 +1,152 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// ========================================
+// Pause Tests
+// ========================================
+
+// newPauseTestBattle creates a one-on-one battle for pause tests
+func newPauseTestBattle(t *testing.T) *Battle {
+	t.Helper()
+	return newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+}
+
+func TestPause_AcceptPausesBattle(t *testing.T) {
+	b := newPauseTestBattle(t)
+	start := time.Now()
+
+	if err := b.RequestPause("player-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pause, err := b.RespondPause("player-2", true, start)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if pause == nil || pause.PlayerID != "player-1" || pause.Reason != PauseReasonRequested || !pause.Since.Equal(start) {
+		t.Errorf("expected a pause charged to player-1, got %+v", pause)
+	}
+	if b.PauseRequest() != "" {
+		t.Errorf("expected the request to be answered, got %q", b.PauseRequest())
+	}
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"}); !errors.Is(err, ErrBattlePaused) {
+		t.Errorf("expected ErrBattlePaused, got %v", err)
+	}
+}
+
+func TestPause_DeclineWithdrawsRequest(t *testing.T) {
+	b := newPauseTestBattle(t)
+	b.RequestPause("player-1")
+
+	pause, err := b.RespondPause("player-2", false, time.Now())
+	if err != nil || pause != nil {
+		t.Fatalf("expected the request to be declined, got %+v, %v", pause, err)
+	}
+
+	if b.PauseRequest() != "" || b.Paused() != nil {
+		t.Error("expected the battle to go on without a request")
+	}
+	if _, err := b.RespondPause("player-2", true, time.Now()); !errors.Is(err, ErrNoPauseRequest) {
+		t.Errorf("expected ErrNoPauseRequest, got %v", err)
+	}
+}
+
+func TestPause_CannotAnswerOwnRequest(t *testing.T) {
+	b := newPauseTestBattle(t)
+	b.RequestPause("player-1")
+
+	if _, err := b.RespondPause("player-1", true, time.Now()); !errors.Is(err, ErrNoPauseRequest) {
+		t.Errorf("expected ErrNoPauseRequest, got %v", err)
+	}
+	if err := b.RequestPause("player-2"); !errors.Is(err, ErrPauseAlreadyRequested) {
+		t.Errorf("expected ErrPauseAlreadyRequested, got %v", err)
+	}
+}
+
+func TestPause_ResumeChargesBudget(t *testing.T) {
+	b := newPauseTestBattle(t)
+	start := time.Now()
+	b.RequestPause("player-1")
+	b.RespondPause("player-2", true, start)
+
+	if got := b.PauseBudget("player-1", start.Add(time.Minute)); got != MaxPauseBudget-time.Minute {
+		t.Errorf("expected the running pause to count against the budget, got %v", got)
+	}
+	if err := b.Resume(start.Add(time.Minute)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if b.Paused() != nil {
+		t.Error("expected the battle to be running again")
+	}
+	if got := b.PauseBudget("player-1", start.Add(time.Hour)); got != MaxPauseBudget-time.Minute {
+		t.Errorf("expected a minute to be charged to player-1, got %v left", got)
+	}
+	if got := b.PauseBudget("player-2", start); got != MaxPauseBudget {
+		t.Errorf("expected player-2's budget to be untouched, got %v", got)
+	}
+	if err := b.Resume(start); !errors.Is(err, ErrBattleNotPaused) {
+		t.Errorf("expected ErrBattleNotPaused, got %v", err)
+	}
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"}); err != nil {
+		t.Errorf("expected actions to be accepted after resuming, got %v", err)
+	}
+}
+
+func TestPause_BudgetExhausted(t *testing.T) {
+	b := newPauseTestBattle(t)
+	start := time.Now()
+	b.RequestPause("player-1")
+	b.RespondPause("player-2", true, start)
+	b.Resume(start.Add(2 * MaxPauseBudget))
+
+	if got := b.PauseBudget("player-1", start); got != 0 {
+		t.Errorf("expected no pause time left, got %v", got)
+	}
+	if err := b.RequestPause("player-1"); !errors.Is(err, ErrPauseBudgetExhausted) {
+		t.Errorf("expected ErrPauseBudgetExhausted, got %v", err)
+	}
+	if _, err := b.PauseForDisconnect("player-1", start); !errors.Is(err, ErrPauseBudgetExhausted) {
+		t.Errorf("expected ErrPauseBudgetExhausted, got %v", err)
+	}
+}
+
+func TestPause_ForDisconnect(t *testing.T) {
+	b := newPauseTestBattle(t)
+	b.RequestPause("player-1")
+
+	pause, err := b.PauseForDisconnect("player-2", time.Now())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if pause.PlayerID != "player-2" || pause.Reason != PauseReasonDisconnect {
+		t.Errorf("expected a disconnect pause charged to player-2, got %+v", pause)
+	}
+	if b.PauseRequest() != "" {
+		t.Error("expected the pending request to be dropped")
+	}
+	if err := b.RequestPause("player-1"); !errors.Is(err, ErrBattlePaused) {
+		t.Errorf("expected ErrBattlePaused, got %v", err)
+	}
+	if _, err := b.SubmitForcedSwitch("player-1", 0); !errors.Is(err, ErrBattlePaused) {
+		t.Errorf("expected ErrBattlePaused, got %v", err)
+	}
+}
+
+func TestPause_ForfeitWhilePaused(t *testing.T) {
+	b := newPauseTestBattle(t)
+	b.PauseForDisconnect("player-2", time.Now())
+
+	if _, err := b.Forfeit("player-1"); err != nil {
+		t.Errorf("expected a paused battle to be forfeitable, got %v", err)
+	}
+	if err := b.RequestPause("player-1"); !errors.Is(err, ErrBattleOver) {
+		t.Errorf("expected ErrBattleOver, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// PP Tracking Tests
+// ========================================
+
+func TestNewCreature_StartsWithFullPP(t *testing.T) {
+	c := newTestCreature(t, "c", []Type{TypeNormal}, 50, "tackle", "hydro-pump")
+
+	if got := c.RemainingPP("tackle"); got != 35 {
+		t.Errorf("expected tackle PP 35, got %d", got)
+	}
+	if got := c.RemainingPP("hydro-pump"); got != 5 {
+		t.Errorf("expected hydro-pump PP 5, got %d", got)
+	}
+}
+
+func TestResolveTurn_UsingMoveConsumesPP(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	resolveTurn(t, b, "tackle", "growl")
+
+	if got := user.RemainingPP("tackle"); got != 34 {
+		t.Errorf("expected tackle PP 34, got %d", got)
+	}
+	if got := foe.RemainingPP("growl"); got != 39 {
+		t.Errorf("expected growl PP 39, got %d", got)
+	}
+}
+
+func TestResolveTurn_UsingMoveRevealsIt(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle", "growl")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	if len(user.RevealedMoves()) != 0 {
+		t.Fatal("expected no moves to be revealed before the battle")
+	}
+
+	resolveTurn(t, b, "tackle", "growl")
+
+	revealed := user.RevealedMoves()
+	if len(revealed) != 1 || revealed[0].ID != "tackle" {
+		t.Errorf("expected only tackle to be revealed, got %v", revealed)
+	}
+}
+
+func TestResolveTurn_FlinchDoesNotConsumePP(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "fake-out")
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 0}, fast, slow)
+
+	resolveTurn(t, b, "fake-out", "tackle")
+
+	if got := slow.RemainingPP("tackle"); got != 35 {
+		t.Errorf("expected flinched creature to keep its PP, got %d", got)
+	}
+}
+
+func TestSubmitAction_MoveWithoutPP(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle", "growl")
+	user.PP["tackle"] = 0
+	b := newTestBattle(&scriptedRNG{}, user, newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle"))
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	if !errors.Is(err, ErrNoPPLeft) {
+		t.Errorf("expected ErrNoPPLeft, got %v", err)
+	}
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "growl"}); err != nil {
+		t.Errorf("expected move with PP to be accepted, got %v", err)
+	}
+}
+
+// ========================================
+// Struggle Tests
+// ========================================
+
+func TestStruggle_ForcedWhenAllPPExhausted(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle", "growl")
+	user.PP["tackle"] = 0
+	user.PP["growl"] = 0
+	foe := newTestCreature(t, "foe", []Type{TypeGhost}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, "tackle", "growl")
+
+	used := findEvents(events, EventMoveUsed)
+	if used[0].Actor != "player-1" || used[0].MoveID != StruggleMoveID {
+		t.Fatalf("expected player-1 to struggle, got %+v", used[0])
+	}
+	damage := findEvents(events, EventDamageDealt)
+	if len(damage) != 1 || damage[0].Damage <= 0 || damage[0].Effectiveness != "normal" {
+		t.Errorf("expected typeless struggle to hit a ghost type, got %+v", damage)
+	}
+}
+
+func TestStruggle_AppliesRecoil(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	user.PP["tackle"] = 0
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, StruggleMoveID, "growl")
+
+	recoil := findEvents(events, EventRecoilDamage)
+	if len(recoil) != 1 {
+		t.Fatalf("expected 1 recoil_damage event, got %d", len(recoil))
+	}
+	expected := user.MaxHP() / 4
+	if recoil[0].Target != "user" || recoil[0].Damage != expected {
+		t.Errorf("expected %d recoil on user, got %+v", expected, recoil[0])
+	}
+	if user.CurrentHP != user.MaxHP()-expected {
+		t.Errorf("expected user HP %d, got %d", user.MaxHP()-expected, user.CurrentHP)
+	}
+}
+
+func TestStruggle_RecoilCanFaintUser(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	user.PP["tackle"] = 0
+	user.CurrentHP = 10
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	foe.CurrentHP = 1
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, StruggleMoveID, "growl")
+
+	fainted := findEvents(events, EventCreatureFainted)
+	if len(fainted) != 2 {
+		t.Fatalf("expected both creatures to faint, got %+v", fainted)
+	}
+	if fainted[0].Target != "foe" || fainted[1].Target != "user" {
+		t.Errorf("expected target to faint before the recoiling user, got %+v", fainted)
+	}
+}
@@ -0,0 +1,23 @@
+package game
+
+import "math/rand"
+
+// NewSeededRNG creates a deterministic RNG.
+// Two battles built from the same seed, teams and actions resolve identically.
+func NewSeededRNG(seed int64) RNG {
+	return rand.New(rand.NewSource(seed))
+}
+
+// NewSeededBattle creates a battle whose random decisions are all drawn from an RNG seeded with seed.
+// The seed is kept in the battle state so the battle can be re-simulated,
+// and a salt is drawn for committing to it, see Battle.SeedCommitment.
+func NewSeededBattle(id string, side1, side2 *BattleSide, seed int64) (*Battle, error) {
+	salt, err := newSeedSalt()
+	if err != nil {
+		return nil, err
+	}
+	b := NewBattle(id, side1, side2, NewSeededRNG(seed))
+	b.Seed = seed
+	b.seedSalt = salt
+	return b, nil
+}
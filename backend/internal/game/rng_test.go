@@ -0,0 +1,80 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newSeededStarterBattle creates a battle between two starter teams using the given seed
+func newSeededStarterBattle(t *testing.T, seed int64) *Battle {
+	t.Helper()
+	team1, err := NewStarterTeam("player-1")
+	if err != nil {
+		t.Fatalf("failed to build team: %v", err)
+	}
+	team2, err := NewStarterTeam("player-2")
+	if err != nil {
+		t.Fatalf("failed to build team: %v", err)
+	}
+	b, err := NewSeededBattle("battle-1", NewBattleSide("player-1", team1), NewBattleSide("player-2", team2), seed)
+	if err != nil {
+		t.Fatalf("failed to create battle: %v", err)
+	}
+	return b
+}
+
+// ========================================
+// Seeded RNG Tests
+// ========================================
+
+func TestNewSeededBattle_StoresSeed(t *testing.T) {
+	b := newSeededStarterBattle(t, 42)
+
+	if b.Seed != 42 {
+		t.Errorf("expected seed 42, got %d", b.Seed)
+	}
+}
+
+func TestNewSeededBattle_ReplaysDeterministically(t *testing.T) {
+	turns := [][2]string{
+		{"razor-leaf", "leech-seed"},
+		{"earthquake", "razor-leaf"},
+		{"razor-leaf", "earthquake"},
+	}
+
+	simulate := func() []BattleEvent {
+		b := newSeededStarterBattle(t, 1234)
+		var all []BattleEvent
+		for _, moves := range turns {
+			all = append(all, resolveTurn(t, b, moves[0], moves[1])...)
+		}
+		return all
+	}
+
+	first, second := simulate(), simulate()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected identical events for the same seed and actions\nfirst:  %+v\nsecond: %+v", first, second)
+	}
+}
+
+func TestSeededRNG_SameSeedSameSequence(t *testing.T) {
+	a, b := NewSeededRNG(7), NewSeededRNG(7)
+
+	for i := 0; i < 20; i++ {
+		if x, y := a.Intn(100), b.Intn(100); x != y {
+			t.Fatalf("draw %d differs: %d vs %d", i, x, y)
+		}
+	}
+}
+
+func TestOutcome_IncludesSeed(t *testing.T) {
+	b := newSeededStarterBattle(t, 99)
+
+	outcome, err := b.Forfeit("player-1")
+	if err != nil {
+		t.Fatalf("forfeit failed: %v", err)
+	}
+	if outcome.Seed != 99 {
+		t.Errorf("expected outcome to record seed 99, got %d", outcome.Seed)
+	}
+}
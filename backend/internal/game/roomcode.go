@@ -2,22 +2,37 @@ package game
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
+	"strings"
 )
 
 // Room code configuration
 const (
 	// Characters excludes ambiguous characters (0/O, 1/I/L)
 	roomCodeCharset = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
-	roomCodeLength  = 6
+
+	// DefaultRoomCodeLength is the length of generated room codes unless configured otherwise
+	DefaultRoomCodeLength = 6
+	// MinRoomCodeLength and MaxRoomCodeLength bound generated and custom room codes
+	MinRoomCodeLength = 4
+	MaxRoomCodeLength = 12
 )
 
+// ErrInvalidRoomCode is returned for a custom room code of the wrong length or with characters outside the charset
+var ErrInvalidRoomCode = errors.New("room code must be 4 to 12 letters and digits, excluding 0, O, 1, I and L")
+
 // GenerateRoomCode creates a unique 6-character alphanumeric code
 func GenerateRoomCode() string {
-	code := make([]byte, roomCodeLength)
+	return GenerateRoomCodeOfLength(DefaultRoomCodeLength)
+}
+
+// GenerateRoomCodeOfLength creates a random alphanumeric code of the given length
+func GenerateRoomCodeOfLength(length int) string {
+	code := make([]byte, length)
 	charsetLen := big.NewInt(int64(len(roomCodeCharset)))
 
-	for i := 0; i < roomCodeLength; i++ {
+	for i := 0; i < length; i++ {
 		idx, err := rand.Int(rand.Reader, charsetLen)
 		if err != nil {
 			// Fall back to a simple approach if crypto/rand fails
@@ -29,3 +44,23 @@ func GenerateRoomCode() string {
 
 	return string(code)
 }
+
+// ValidRoomCodeLength returns true if room codes may be length characters long
+func ValidRoomCodeLength(length int) bool {
+	return length >= MinRoomCodeLength && length <= MaxRoomCodeLength
+}
+
+// ParseRoomCode checks a custom room code against the room code rules, ignoring case and surrounding whitespace,
+// and returns it in upper case
+func ParseRoomCode(s string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if !ValidRoomCodeLength(len(code)) {
+		return "", ErrInvalidRoomCode
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(roomCodeCharset, code[i]) < 0 {
+			return "", ErrInvalidRoomCode
+		}
+	}
+	return code, nil
+}
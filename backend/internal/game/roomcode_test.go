@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -98,3 +99,39 @@ func BenchmarkGenerateRoomCode(b *testing.B) {
 		GenerateRoomCode()
 	}
 }
+
+func TestGenerateRoomCodeOfLength(t *testing.T) {
+	for _, length := range []int{MinRoomCodeLength, 8, MaxRoomCodeLength} {
+		code := GenerateRoomCodeOfLength(length)
+		if len(code) != length {
+			t.Errorf("expected code length %d, got %d", length, len(code))
+		}
+		if _, err := ParseRoomCode(code); err != nil {
+			t.Errorf("generated code %q failed to parse: %v", code, err)
+		}
+	}
+}
+
+func TestParseRoomCode(t *testing.T) {
+	code, err := ParseRoomCode("  ashketchum ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if code != "ASHKETCHUM" {
+		t.Errorf("expected ASHKETCHUM, got %q", code)
+	}
+
+	invalid := []string{
+		"",
+		"ABC",           // too short
+		"ABCDEFGHJKMNP", // too long
+		"POKEMON",       // contains O
+		"TEAM-1",        // punctuation and 1
+		"CAFÉ22",        // non-ASCII
+	}
+	for _, s := range invalid {
+		if _, err := ParseRoomCode(s); !errors.Is(err, ErrInvalidRoomCode) {
+			t.Errorf("ParseRoomCode(%q): expected ErrInvalidRoomCode, got %v", s, err)
+		}
+	}
+}
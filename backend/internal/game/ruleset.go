@@ -0,0 +1,163 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// ErrUnknownRuleset is returned when a ruleset ID is not in the catalogue
+var ErrUnknownRuleset = errors.New("unknown ruleset")
+
+// DefaultRulesetID is the ruleset new lobbies start with
+const DefaultRulesetID = "standard"
+
+// Ruleset is a set of team legality rules a lobby can play under.
+// Rulesets only tighten the catalogue limits; TeamSize and LevelCap never exceed MaxTeamSize and MaxLevel.
+type Ruleset struct {
+	ID            string
+	Name          string
+	TeamSize      int  // maximum creatures per team
+	LevelCap      int  // highest level a creature may be brought at
+	SpeciesClause bool // at most one creature of each species
+	ItemClause    bool // at most one creature holding each item
+	TeamPreview   bool // players see the opposing species and choose their lead before turn 1
+	Bag           Bag  // consumable items each player starts the battle with; nil for no bag
+	TurnLimit     int  // turn after which the battle is decided by remaining HP; 0 for no limit
+	BannedSpecies []string
+	BannedMoves   []string
+	// TypeChart lists matchups that override the data pack's type chart; nil to play with it unchanged
+	TypeChart map[Type]map[Type]float64
+	// CustomTypeChart replaces the data pack's type chart with TypeChart entirely; unlisted matchups are neutral
+	CustomTypeChart bool
+}
+
+var rulesetCatalogue = map[string]*Ruleset{
+	"standard": {
+		ID: "standard", Name: "Standard",
+		TeamSize: MaxTeamSize, LevelCap: MaxLevel,
+		TurnLimit: DefaultTurnLimit,
+	},
+	"competitive": {
+		ID: "competitive", Name: "Competitive",
+		TeamSize: MaxTeamSize, LevelCap: DefaultLevel,
+		SpeciesClause: true, ItemClause: true, TeamPreview: true,
+		TurnLimit: DefaultTurnLimit,
+	},
+	"casual": {
+		ID: "casual", Name: "Casual",
+		TeamSize: MaxTeamSize, LevelCap: MaxLevel,
+		Bag:       Bag{"potion": 3, "super-potion": 2, "full-heal": 2, "revive": 1},
+		TurnLimit: DefaultTurnLimit,
+	},
+	"inverse": {
+		ID: "inverse", Name: "Inverse",
+		TeamSize: MaxTeamSize, LevelCap: MaxLevel,
+		TurnLimit: DefaultTurnLimit,
+		TypeChart: inverseTypeChart(typeChart), CustomTypeChart: true,
+	},
+}
+
+// LookupRuleset returns the catalogue entry for a ruleset ID
+func LookupRuleset(id string) (*Ruleset, error) {
+	ruleset, ok := rulesetCatalogue[id]
+	if !ok {
+		return nil, ErrUnknownRuleset
+	}
+	return ruleset, nil
+}
+
+// DefaultRuleset returns the ruleset new lobbies start with
+func DefaultRuleset() *Ruleset {
+	return rulesetCatalogue[DefaultRulesetID]
+}
+
+// ListRulesets returns every ruleset in the catalogue, ordered by ID
+func ListRulesets() []*Ruleset {
+	rulesets := make([]*Ruleset, 0, len(rulesetCatalogue))
+	for _, r := range rulesetCatalogue {
+		rulesets = append(rulesets, r)
+	}
+	sort.Slice(rulesets, func(i, j int) bool { return rulesets[i].ID < rulesets[j].ID })
+	return rulesets
+}
+
+// DataPack returns the data battles under the ruleset resolve against:
+// base itself, or a copy of it carrying the ruleset's type chart
+func (r *Ruleset) DataPack(base *DataPack) *DataPack {
+	if r.TypeChart == nil && !r.CustomTypeChart {
+		return base
+	}
+
+	chart := make(map[Type]map[Type]float64, len(base.TypeChart))
+	if !r.CustomTypeChart {
+		for attack, matchups := range base.TypeChart {
+			chart[attack] = maps.Clone(matchups)
+		}
+	}
+	for attack, matchups := range r.TypeChart {
+		if chart[attack] == nil {
+			chart[attack] = make(map[Type]float64, len(matchups))
+		}
+		maps.Copy(chart[attack], matchups)
+	}
+
+	pack := *base
+	pack.TypeChart = chart
+	pack.Ruleset = r.ID
+	return &pack
+}
+
+// Validate checks a team against the ruleset and returns a *TeamError listing every violation, or nil
+func (r *Ruleset) Validate(team []TeamMember) error {
+	var violations []Violation
+
+	if len(team) == 0 {
+		violations = append(violations, Violation{Slot: -1, Rule: RuleTeamSize, Err: ErrEmptyTeam})
+	}
+	if len(team) > r.TeamSize {
+		err := fmt.Errorf("%d creatures, limit %d: %w", len(team), r.TeamSize, ErrTeamTooLarge)
+		violations = append(violations, Violation{Slot: -1, Rule: RuleTeamSize, Err: err})
+	}
+
+	speciesSlots := make(map[string]int, len(team))
+	itemSlots := make(map[string]int, len(team))
+	for i, member := range team {
+		violations = append(violations, member.violations(i, r)...)
+
+		if r.SpeciesClause {
+			if first, ok := speciesSlots[member.SpeciesID]; ok {
+				err := fmt.Errorf("species %q also in slot %d: %w", member.SpeciesID, first, ErrSpeciesClause)
+				violations = append(violations, Violation{Slot: i, Rule: RuleSpeciesClause, Err: err})
+			} else {
+				speciesSlots[member.SpeciesID] = i
+			}
+		}
+
+		if r.ItemClause && member.Item != "" {
+			if first, ok := itemSlots[member.Item]; ok {
+				err := fmt.Errorf("item %q also in slot %d: %w", member.Item, first, ErrItemClause)
+				violations = append(violations, Violation{Slot: i, Rule: RuleItemClause, Err: err})
+			} else {
+				itemSlots[member.Item] = i
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &TeamError{Violations: violations}
+	}
+	return nil
+}
+
+// bansSpecies reports whether the ruleset bans a species
+func (r *Ruleset) bansSpecies(id string) bool {
+	return slices.Contains(r.BannedSpecies, id)
+}
+
+// bansMove reports whether the ruleset bans a move
+func (r *Ruleset) bansMove(id string) bool {
+	return slices.Contains(r.BannedMoves, id)
+}
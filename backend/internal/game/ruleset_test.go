@@ -0,0 +1,219 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Ruleset Catalogue Tests
+// ========================================
+
+func TestLookupRuleset_Default(t *testing.T) {
+	ruleset, err := LookupRuleset(DefaultRulesetID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ruleset != DefaultRuleset() {
+		t.Error("expected default ruleset to be the catalogue entry for DefaultRulesetID")
+	}
+}
+
+func TestLookupRuleset_Unknown(t *testing.T) {
+	if _, err := LookupRuleset("anything-goes"); !errors.Is(err, ErrUnknownRuleset) {
+		t.Errorf("expected ErrUnknownRuleset, got %v", err)
+	}
+}
+
+func TestListRulesets_OrderedByID(t *testing.T) {
+	rulesets := ListRulesets()
+	if len(rulesets) != len(rulesetCatalogue) {
+		t.Fatalf("expected %d rulesets, got %d", len(rulesetCatalogue), len(rulesets))
+	}
+	for i := 1; i < len(rulesets); i++ {
+		if rulesets[i-1].ID >= rulesets[i].ID {
+			t.Errorf("expected rulesets ordered by ID, got %q before %q", rulesets[i-1].ID, rulesets[i].ID)
+		}
+	}
+}
+
+func TestRulesetCatalogue_StarterTeamIsLegalEverywhere(t *testing.T) {
+	for _, ruleset := range ListRulesets() {
+		if err := ruleset.Validate(StarterTeam()); err != nil {
+			t.Errorf("expected starter team to be legal under %q, got %v", ruleset.ID, err)
+		}
+	}
+}
+
+// ========================================
+// Clause Tests
+// ========================================
+
+func TestRuleset_Violations(t *testing.T) {
+	strict := &Ruleset{
+		ID: "strict", TeamSize: 2, LevelCap: 50,
+		SpeciesClause: true, ItemClause: true,
+		BannedSpecies: []string{"alakazam"}, BannedMoves: []string{"agility"},
+	}
+	pikachu := TeamMember{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}}
+
+	tests := []struct {
+		name     string
+		team     []TeamMember
+		rule     Rule
+		expected error
+	}{
+		{"team size", []TeamMember{pikachu, {SpeciesID: "gengar", Moves: []string{"confuse-ray"}}, {SpeciesID: "snorlax", Moves: []string{"tackle"}}}, RuleTeamSize, ErrTeamTooLarge},
+		{"level cap", []TeamMember{{SpeciesID: "pikachu", Level: 51, Moves: []string{"thunderbolt"}}}, RuleLevelCap, ErrLevelAboveCap},
+		{"banned species", []TeamMember{{SpeciesID: "alakazam", Moves: []string{"psychic"}}}, RuleBannedSpecies, ErrSpeciesBanned},
+		{"banned move", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"agility"}}}, RuleBannedMove, ErrMoveBanned},
+		{"species clause", []TeamMember{pikachu, pikachu}, RuleSpeciesClause, ErrSpeciesClause},
+		{"item clause", []TeamMember{{SpeciesID: "pikachu", Item: "leftovers", Moves: []string{"thunderbolt"}}, {SpeciesID: "gengar", Item: "leftovers", Moves: []string{"confuse-ray"}}}, RuleItemClause, ErrItemClause},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := strict.Validate(tt.team)
+			if !errors.Is(err, tt.expected) || !errors.Is(err, ErrInvalidTeam) {
+				t.Fatalf("expected %v wrapped in ErrInvalidTeam, got %v", tt.expected, err)
+			}
+
+			var teamErr *TeamError
+			if !errors.As(err, &teamErr) {
+				t.Fatalf("expected *TeamError, got %T", err)
+			}
+			if len(teamErr.Violations) != 1 || teamErr.Violations[0].Rule != tt.rule {
+				t.Errorf("expected a single %s violation, got %+v", tt.rule, teamErr.Violations)
+			}
+		})
+	}
+}
+
+func TestRuleset_ClausesOffByDefault(t *testing.T) {
+	pikachu := TeamMember{SpeciesID: "pikachu", Item: "leftovers", Moves: []string{"thunderbolt"}}
+
+	if err := DefaultRuleset().Validate([]TeamMember{pikachu, pikachu}); err != nil {
+		t.Errorf("expected duplicates to be legal without clauses, got %v", err)
+	}
+}
+
+func TestRuleset_ItemClauseIgnoresEmptyItems(t *testing.T) {
+	competitive, _ := LookupRuleset("competitive")
+	team := []TeamMember{
+		{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}},
+		{SpeciesID: "gengar", Moves: []string{"confuse-ray"}},
+	}
+
+	if err := competitive.Validate(team); err != nil {
+		t.Errorf("expected creatures without items to be legal, got %v", err)
+	}
+}
+
+func TestRuleset_ReportsEveryViolation(t *testing.T) {
+	competitive, _ := LookupRuleset("competitive")
+	team := []TeamMember{
+		{SpeciesID: "pikachu", Level: 60, Moves: []string{"surf"}},
+		{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}},
+	}
+
+	var teamErr *TeamError
+	if !errors.As(competitive.Validate(team), &teamErr) {
+		t.Fatal("expected *TeamError")
+	}
+
+	expected := []Violation{
+		{Slot: 0, Rule: RuleLevelCap},
+		{Slot: 0, Rule: RuleMoves},
+		{Slot: 1, Rule: RuleSpeciesClause},
+	}
+	if len(teamErr.Violations) != len(expected) {
+		t.Fatalf("expected %d violations, got %+v", len(expected), teamErr.Violations)
+	}
+	for i, v := range teamErr.Violations {
+		if v.Slot != expected[i].Slot || v.Rule != expected[i].Rule {
+			t.Errorf("violation %d: expected slot %d rule %s, got slot %d rule %s", i, expected[i].Slot, expected[i].Rule, v.Slot, v.Rule)
+		}
+	}
+}
+
+func TestRuleset_TeamWideViolationHasNoSlot(t *testing.T) {
+	var teamErr *TeamError
+	if !errors.As(DefaultRuleset().Validate(nil), &teamErr) {
+		t.Fatal("expected *TeamError")
+	}
+	if teamErr.Violations[0].Slot != -1 {
+		t.Errorf("expected team-wide violation to have slot -1, got %d", teamErr.Violations[0].Slot)
+	}
+}
+
+// ========================================
+// Type Chart Tests
+// ========================================
+
+func TestRuleset_DataPackUnchangedWithoutTypeChart(t *testing.T) {
+	base := CurrentDataPack()
+	if pack := DefaultRuleset().DataPack(base); pack != base {
+		t.Error("expected a ruleset without a type chart to play with the data pack unchanged")
+	}
+}
+
+func TestRuleset_TypeChartOverridesAmendDataPack(t *testing.T) {
+	base := CurrentDataPack()
+	r := &Ruleset{ID: "fan", TypeChart: map[Type]map[Type]float64{TypeFire: {TypeWater: 2}, TypeNormal: {TypeGhost: 1}}}
+
+	pack := r.DataPack(base)
+
+	if got := pack.Effectiveness(TypeFire, []Type{TypeWater}); got != 2 {
+		t.Errorf("expected overridden fire vs water to be 2, got %v", got)
+	}
+	if got := pack.Effectiveness(TypeNormal, []Type{TypeGhost}); got != 1 {
+		t.Errorf("expected overridden normal vs ghost to be neutral, got %v", got)
+	}
+	if got := pack.Effectiveness(TypeFire, []Type{TypeGrass}); got != 2 {
+		t.Errorf("expected matchups not overridden to keep the data pack's value, got %v", got)
+	}
+	if pack.Ruleset != "fan" || pack.Version != base.Version {
+		t.Errorf("expected the pack to keep its version and name the ruleset, got %q and %q", pack.Version, pack.Ruleset)
+	}
+	if got := base.Effectiveness(TypeFire, []Type{TypeWater}); got != 0.5 {
+		t.Errorf("expected the data pack itself to be left alone, got %v", got)
+	}
+}
+
+func TestRuleset_CustomTypeChartReplacesDataPack(t *testing.T) {
+	r := &Ruleset{ID: "fan", TypeChart: map[Type]map[Type]float64{TypeFire: {TypeWater: 2}}, CustomTypeChart: true}
+
+	pack := r.DataPack(CurrentDataPack())
+
+	if got := pack.Effectiveness(TypeFire, []Type{TypeWater}); got != 2 {
+		t.Errorf("expected custom fire vs water to be 2, got %v", got)
+	}
+	if got := pack.Effectiveness(TypeFire, []Type{TypeGrass}); got != 1 {
+		t.Errorf("expected matchups missing from a custom chart to be neutral, got %v", got)
+	}
+}
+
+func TestRuleset_InverseTypeChart(t *testing.T) {
+	inverse, err := LookupRuleset("inverse")
+	if err != nil {
+		t.Fatalf("expected inverse ruleset, got %v", err)
+	}
+	pack := inverse.DataPack(CurrentDataPack())
+
+	tests := []struct {
+		attack    Type
+		defending []Type
+		want      float64
+	}{
+		{TypeFire, []Type{TypeGrass}, 0.5},
+		{TypeFire, []Type{TypeWater}, 2},
+		{TypeNormal, []Type{TypeGhost}, 2},
+		{TypeNormal, []Type{TypeNormal}, 1},
+		{TypeElectric, []Type{TypeWater, TypeFlying}, 0.25},
+	}
+	for _, tt := range tests {
+		if got := pack.Effectiveness(tt.attack, tt.defending); got != tt.want {
+			t.Errorf("%s vs %v: expected %v, got %v", tt.attack, tt.defending, tt.want, got)
+		}
+	}
+}
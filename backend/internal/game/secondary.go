@@ -0,0 +1,38 @@
+package game
+
+// SecondaryEffect is an extra effect a damaging move has a chance to trigger on the target when it hits
+type SecondaryEffect struct {
+	Chance            int             // Percent chance to trigger
+	Status            StatusCondition // Major status inflicted on the target
+	Flinch            bool            // Flinches the target if it has not acted yet this turn
+	TargetStatChanges []StatChange
+}
+
+// applySecondaryEffects rolls each of the move's secondary effects against the target.
+// Every event produced by a triggered effect is marked as secondary.
+func (b *Battle) applySecondaryEffects(actor string, targetSide int, move *Move) {
+	target := b.Sides[targetSide].Active()
+	for _, effect := range move.Secondary {
+		// A flinch only matters before the target moves, so no chance is rolled after that
+		if effect.Flinch && b.acted[targetSide] {
+			continue
+		}
+		if b.rng.Intn(100) >= effect.Chance {
+			continue
+		}
+
+		start := len(b.events)
+		if effect.Status != StatusNone {
+			b.applyStatus(actor, target, effect.Status)
+		}
+		if effect.Flinch {
+			target.Volatiles.Flinched = true
+			b.emit(BattleEvent{Type: EventStatusApplied, Actor: actor, Target: target.ID, Status: string(VolatileFlinch)})
+		}
+		b.applyStatChanges(actor, target, effect.TargetStatChanges)
+
+		for i := start; i < len(b.events); i++ {
+			b.events[i].Secondary = true
+		}
+	}
+}
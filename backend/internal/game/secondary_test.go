@@ -0,0 +1,123 @@
+package game
+
+import "testing"
+
+// ========================================
+// Secondary Effect Tests
+// ========================================
+
+func TestSecondary_BurnTriggers(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "flamethrower")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "growl")
+	// crit and damage rolls, then secondary roll 0 < 10
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0}, fallback: 15}, attacker, target)
+
+	events := resolveTurn(t, b, "flamethrower", "growl")
+
+	if target.Status != StatusBurn {
+		t.Fatalf("expected target to be burned, got %q", target.Status)
+	}
+	applied := findEvents(events, EventStatusApplied)
+	if len(applied) != 1 || !applied[0].Secondary || applied[0].Status != string(StatusBurn) {
+		t.Fatalf("expected secondary status_applied burn event, got %+v", applied)
+	}
+	damage := findEvents(events, EventDamageDealt)
+	if applied[0].Order < damage[0].Order {
+		t.Error("expected secondary effect to be reported after the damage event")
+	}
+}
+
+func TestSecondary_ChanceRollMisses(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "flamethrower")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 10}, fallback: 15}, attacker, target)
+
+	events := resolveTurn(t, b, "flamethrower", "growl")
+
+	if target.Status != StatusNone || len(findEvents(events, EventStatusApplied)) != 0 {
+		t.Error("expected no burn when the chance roll fails")
+	}
+}
+
+func TestSecondary_FireTypesCannotBeBurned(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "flamethrower")
+	target := newTestCreature(t, "target", []Type{TypeFire}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0}, fallback: 15}, attacker, target)
+
+	resolveTurn(t, b, "flamethrower", "growl")
+
+	if target.Status != StatusNone {
+		t.Errorf("expected fire type to resist burn, got %q", target.Status)
+	}
+}
+
+func TestSecondary_StatDrop(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypePsychic}, 100, "psychic")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "swords-dance")
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0}, fallback: 15}, attacker, target)
+
+	events := resolveTurn(t, b, "psychic", "swords-dance")
+
+	changed := findEvents(events, EventStatChanged)
+	if len(changed) != 2 || !changed[0].Secondary || changed[0].Stat != string(StatSpDefense) || changed[0].Stages != -1 {
+		t.Errorf("expected secondary sp_defense drop, got %+v", changed)
+	}
+}
+
+func TestSecondary_FlinchReported(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeSteel}, 100, "iron-head")
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0}, fallback: 15}, fast, slow)
+
+	events := resolveTurn(t, b, "iron-head", "tackle")
+
+	applied := findEvents(events, EventStatusApplied)
+	if len(applied) != 1 || !applied[0].Secondary || applied[0].Status != string(VolatileFlinch) {
+		t.Errorf("expected secondary flinch event, got %+v", applied)
+	}
+}
+
+// ========================================
+// Burn Tests
+// ========================================
+
+func TestBurn_HalvesPhysicalDamage(t *testing.T) {
+	healthy := newTestCreature(t, "healthy", []Type{TypeNormal}, 100, "tackle")
+	burned := newTestCreature(t, "burned", []Type{TypeNormal}, 100, "tackle")
+	burned.Status = StatusBurn
+	defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50)
+	move := mustMove(t, "tackle")
+
+	normal := CalculateDamage(healthy, defender, move, 100, false).Damage
+	halved := CalculateDamage(burned, defender, move, 100, false).Damage
+
+	if halved != normal/2 {
+		t.Errorf("expected burn to halve physical damage %d, got %d", normal, halved)
+	}
+}
+
+func TestBurn_DoesNotAffectSpecialDamage(t *testing.T) {
+	healthy := newTestCreature(t, "healthy", []Type{TypeNormal}, 100, "surf")
+	burned := newTestCreature(t, "burned", []Type{TypeNormal}, 100, "surf")
+	burned.Status = StatusBurn
+	defender := newTestCreature(t, "defender", []Type{TypeNormal}, 50)
+	move := mustMove(t, "surf")
+
+	if CalculateDamage(healthy, defender, move, 100, false).Damage != CalculateDamage(burned, defender, move, 100, false).Damage {
+		t.Error("expected burn not to change special damage")
+	}
+}
+
+func TestBurn_EndOfTurnDamage(t *testing.T) {
+	burned := newTestCreature(t, "burned", []Type{TypeNormal}, 100, "growl")
+	burned.Status = StatusBurn
+	opponent := newTestCreature(t, "opponent", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, burned, opponent)
+
+	events := resolveTurn(t, b, "growl", "growl")
+
+	damage := findEvents(events, EventStatusDamage)
+	if len(damage) != 1 || damage[0].Damage != burned.MaxHP()/burnDamageDivisor {
+		t.Errorf("expected 1/16 burn damage, got %+v", damage)
+	}
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"poke-battles/internal/random"
+)
+
+// seedSaltBytes is the length of the random salt mixed into a seed commitment
+const seedSaltBytes = 16
+
+// SeedCommitment hashes a battle RNG seed with a salt: the hex SHA-256 of "<salt>:<seed>", seed in decimal.
+// Sent before the first turn, it binds the battle to its seed without revealing it;
+// the salt stops the seed being recovered by hashing likely values.
+func SeedCommitment(seed int64, salt string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + strconv.FormatInt(seed, 10)))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySeedCommitment reports whether a revealed seed and salt match an earlier commitment
+func VerifySeedCommitment(commitment string, seed int64, salt string) bool {
+	return commitment != "" && SeedCommitment(seed, salt) == commitment
+}
+
+// NewBattleSeed draws a battle RNG seed from crypto/rand, so it cannot be guessed from when the battle started.
+// Seeds are never negative.
+func NewBattleSeed() (int64, error) {
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return 0, fmt.Errorf("drawing battle seed: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(seed[:]) >> 1), nil
+}
+
+// newSeedSalt creates a random hex salt for a seed commitment
+func newSeedSalt() (string, error) {
+	salt, err := random.Hex(seedSaltBytes)
+	if err != nil {
+		return "", fmt.Errorf("drawing seed salt: %w", err)
+	}
+	return salt, nil
+}
+
+// SeedCommitment returns the commitment to the battle's RNG seed, or "" for a battle created without a seed.
+// The seed and salt behind it are revealed in the outcome once the battle is over.
+func (b *Battle) SeedCommitment() string {
+	if b.seedSalt == "" {
+		return ""
+	}
+	return SeedCommitment(b.Seed, b.seedSalt)
+}
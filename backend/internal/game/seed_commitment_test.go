@@ -0,0 +1,77 @@
+package game
+
+import "testing"
+
+func TestSeedCommitment_Verify(t *testing.T) {
+	commitment := SeedCommitment(42, "abc")
+
+	if commitment != SeedCommitment(42, "abc") || len(commitment) != 64 {
+		t.Fatalf("expected a stable hex SHA-256 commitment, got %q", commitment)
+	}
+	if !VerifySeedCommitment(commitment, 42, "abc") {
+		t.Error("expected the revealed seed and salt to match the commitment")
+	}
+	if VerifySeedCommitment(commitment, 43, "abc") || VerifySeedCommitment(commitment, 42, "abd") {
+		t.Error("expected a different seed or salt not to match the commitment")
+	}
+	if VerifySeedCommitment("", 42, "abc") {
+		t.Error("expected an empty commitment never to match")
+	}
+}
+
+func TestBattle_SeedCommitmentRevealedInOutcome(t *testing.T) {
+	b, err := NewSeededBattle("battle-1", NewBattleSide("player-1", []*Creature{newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle")}),
+		NewBattleSide("player-2", []*Creature{newTestCreature(t, "b", []Type{TypeNormal}, 60, "tackle")}), 7)
+	if err != nil {
+		t.Fatalf("failed to create battle: %v", err)
+	}
+	commitment := b.SeedCommitment()
+
+	outcome, err := b.Forfeit("player-1")
+	if err != nil {
+		t.Fatalf("forfeit failed: %v", err)
+	}
+
+	if outcome.Seed != 7 || outcome.SeedSalt == "" || !VerifySeedCommitment(commitment, outcome.Seed, outcome.SeedSalt) {
+		t.Errorf("expected the outcome to reveal the committed seed, got %+v for %q", outcome, commitment)
+	}
+}
+
+func TestBattle_SeedCommitmentSaltedPerBattle(t *testing.T) {
+	side := func(id string) *BattleSide {
+		return NewBattleSide(id, []*Creature{newTestCreature(t, id, []Type{TypeNormal}, 50, "tackle")})
+	}
+	first, err := NewSeededBattle("battle-1", side("player-1"), side("player-2"), 7)
+	if err != nil {
+		t.Fatalf("failed to create battle: %v", err)
+	}
+	second, err := NewSeededBattle("battle-2", side("player-1"), side("player-2"), 7)
+	if err != nil {
+		t.Fatalf("failed to create battle: %v", err)
+	}
+
+	if first.SeedCommitment() == second.SeedCommitment() {
+		t.Error("expected battles with the same seed to commit to it with different salts")
+	}
+	if unseeded := newTestBattle(&scriptedRNG{}, first.Sides[0].Active(), first.Sides[1].Active()); unseeded.SeedCommitment() != "" {
+		t.Error("expected no commitment for a battle created without a seed")
+	}
+}
+
+func TestNewBattleSeed(t *testing.T) {
+	first, err := NewBattleSeed()
+	if err != nil {
+		t.Fatalf("failed to draw seed: %v", err)
+	}
+	second, err := NewBattleSeed()
+	if err != nil {
+		t.Fatalf("failed to draw seed: %v", err)
+	}
+
+	if first < 0 || second < 0 {
+		t.Errorf("expected seeds never to be negative, got %d and %d", first, second)
+	}
+	if first == second {
+		t.Errorf("expected two draws to give different seeds, got %d twice", first)
+	}
+}
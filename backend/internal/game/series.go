@@ -0,0 +1,83 @@
+package game
+
+import "errors"
+
+// Series errors
+var (
+	ErrInvalidSeriesLength         = errors.New("series must be best of 1, 3 or 5")
+	ErrInvalidStateForSeries       = errors.New("cannot change series length in current state")
+	ErrInvalidStateForRematch      = errors.New("cannot rematch in current state")
+	ErrUnknownRematchTeams         = errors.New("rematch teams must be same or new")
+	ErrInvalidStateForRematchTeams = errors.New("cannot change rematch teams during a game")
+)
+
+// DefaultSeriesLength is the series length new lobbies start with: a single game
+const DefaultSeriesLength = 1
+
+// RematchTeams selects the teams a lobby's rematches are played with
+type RematchTeams string
+
+const (
+	RematchTeamsSame RematchTeams = "same" // The rematch starts straight away with the teams of the last game
+	RematchTeamsNew  RematchTeams = "new"  // Players submit new teams and ready up before the rematch starts
+)
+
+// ValidRematchTeams reports whether a lobby can be configured with the rematch teams setting
+func ValidRematchTeams(teams RematchTeams) bool {
+	return teams == RematchTeamsSame || teams == RematchTeamsNew
+}
+
+// seriesLengths are the supported best-of-N series lengths
+var seriesLengths = []int{1, 3, 5}
+
+// ValidSeriesLength reports whether a lobby can be configured as best of n
+func ValidSeriesLength(n int) bool {
+	for _, length := range seriesLengths {
+		if n == length {
+			return true
+		}
+	}
+	return false
+}
+
+// Series tracks game wins across a best-of-N match between the lobby's players
+type Series struct {
+	BestOf int
+	Games  int            // games played so far
+	Wins   map[string]int // games won, keyed by player ID
+}
+
+// newSeries starts an empty best-of-N series
+func newSeries(bestOf int) Series {
+	return Series{BestOf: bestOf, Wins: make(map[string]int)}
+}
+
+// WinsNeeded returns the number of game wins that takes the series
+func (s Series) WinsNeeded() int {
+	return s.BestOf/2 + 1
+}
+
+// Winner returns the player who has taken the series, or "" while it is still undecided
+func (s Series) Winner() string {
+	for playerID, wins := range s.Wins {
+		if wins >= s.WinsNeeded() {
+			return playerID
+		}
+	}
+	return ""
+}
+
+// IsOver returns true once a player has won enough games to take the series
+func (s Series) IsOver() bool {
+	return s.Winner() != ""
+}
+
+// clone returns a copy of the series that does not share its wins map
+func (s Series) clone() Series {
+	wins := make(map[string]int, len(s.Wins))
+	for playerID, n := range s.Wins {
+		wins[playerID] = n
+	}
+	s.Wins = wins
+	return s
+}
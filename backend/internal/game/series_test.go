@@ -0,0 +1,220 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newFinishedLobby creates a lobby with two players and teams whose first game has been played
+func newFinishedLobby(t *testing.T, bestOf int) *Lobby {
+	t.Helper()
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	lobby.AddPlayer("player-2", "Player2")
+	submitStarterTeams(t, lobby)
+	if err := lobby.SetSeriesLength(bestOf); err != nil {
+		t.Fatalf("failed to set series length: %v", err)
+	}
+	if err := lobby.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	lobby.RecordWin("host-1")
+	lobby.End()
+	return lobby
+}
+
+// ========================================
+// Series Tests
+// ========================================
+
+func TestSeries_WinsNeeded(t *testing.T) {
+	for bestOf, want := range map[int]int{1: 1, 3: 2, 5: 3} {
+		if got := newSeries(bestOf).WinsNeeded(); got != want {
+			t.Errorf("best of %d: expected %d wins needed, got %d", bestOf, want, got)
+		}
+	}
+}
+
+func TestSeries_Winner(t *testing.T) {
+	series := newSeries(3)
+	series.Wins["host-1"] = 1
+	series.Wins["player-2"] = 1
+	if series.IsOver() {
+		t.Fatal("expected 1-1 best of 3 to be undecided")
+	}
+
+	series.Wins["player-2"]++
+	if series.Winner() != "player-2" {
+		t.Errorf("expected player-2 to take the series, got %q", series.Winner())
+	}
+}
+
+func TestValidSeriesLength(t *testing.T) {
+	for _, n := range []int{1, 3, 5} {
+		if !ValidSeriesLength(n) {
+			t.Errorf("expected best of %d to be valid", n)
+		}
+	}
+	for _, n := range []int{0, 2, 4, 7} {
+		if ValidSeriesLength(n) {
+			t.Errorf("expected best of %d to be invalid", n)
+		}
+	}
+}
+
+func TestLobby_DefaultsToSingleGame(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if lobby.GetSeries().BestOf != DefaultSeriesLength {
+		t.Errorf("expected best of %d, got %d", DefaultSeriesLength, lobby.GetSeries().BestOf)
+	}
+}
+
+func TestLobby_SetSeriesLength(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.SetSeriesLength(4); !errors.Is(err, ErrInvalidSeriesLength) {
+		t.Errorf("expected ErrInvalidSeriesLength, got %v", err)
+	}
+	if err := lobby.SetSeriesLength(5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetSeries().BestOf != 5 {
+		t.Errorf("expected best of 5, got %d", lobby.GetSeries().BestOf)
+	}
+}
+
+func TestLobby_SetSeriesLength_InvalidState(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+
+	if err := lobby.SetSeriesLength(5); !errors.Is(err, ErrInvalidStateForSeries) {
+		t.Errorf("expected ErrInvalidStateForSeries, got %v", err)
+	}
+}
+
+func TestLobby_RecordWin(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+
+	series := lobby.GetSeries()
+	if series.Games != 1 || series.Wins["host-1"] != 1 {
+		t.Errorf("expected 1 game won by host-1, got %+v", series)
+	}
+
+	series.Wins["host-1"] = 5
+	if lobby.GetSeries().Wins["host-1"] != 1 {
+		t.Error("expected GetSeries to return a copy")
+	}
+}
+
+func TestLobby_Rematch(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+
+	if err := lobby.Rematch(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetState() != LobbyStateReady {
+		t.Errorf("expected state Ready, got %v", lobby.GetState())
+	}
+	if lobby.GetSeries().Games != 1 {
+		t.Error("expected series score to carry into the rematch")
+	}
+	if !lobby.HasTeam("host-1") || !lobby.HasTeam("player-2") {
+		t.Error("expected teams to carry into the rematch")
+	}
+}
+
+func TestLobby_Rematch_StartsNewSeriesWhenOver(t *testing.T) {
+	lobby := newFinishedLobby(t, 1)
+
+	lobby.Rematch()
+
+	series := lobby.GetSeries()
+	if series.BestOf != 1 || series.Games != 0 || len(series.Wins) != 0 {
+		t.Errorf("expected a fresh best of 1 series, got %+v", series)
+	}
+}
+
+func TestLobby_Rematch_InvalidState(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	if err := lobby.Rematch(); !errors.Is(err, ErrInvalidStateForRematch) {
+		t.Errorf("expected ErrInvalidStateForRematch, got %v", err)
+	}
+}
+
+func TestLobby_Rematch_PlayerLeft(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+	lobby.RemovePlayer("player-2")
+
+	if lobby.GetState() != LobbyStateClosed {
+		t.Errorf("expected lobby to close once a player left, got %v", lobby.GetState())
+	}
+	if err := lobby.Rematch(); !errors.Is(err, ErrInvalidStateForRematch) {
+		t.Errorf("expected ErrInvalidStateForRematch, got %v", err)
+	}
+}
+
+func TestRecordWin_DrawCountsGameWithoutWin(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+
+	series := lobby.RecordWin("")
+
+	if series.Games != 1 {
+		t.Errorf("expected the draw to count as a game, got %d", series.Games)
+	}
+	if len(series.Wins) != 0 {
+		t.Errorf("expected no wins for a draw, got %v", series.Wins)
+	}
+}
+
+// ========================================
+// Rematch Teams Tests
+// ========================================
+
+func TestLobby_SetRematchTeams(t *testing.T) {
+	lobby := NewLobby("ABC123", "host-1", "Host")
+	if lobby.GetRematchTeams() != RematchTeamsSame {
+		t.Errorf("expected new lobby to keep teams on rematch, got %q", lobby.GetRematchTeams())
+	}
+
+	if err := lobby.SetRematchTeams("shuffled"); !errors.Is(err, ErrUnknownRematchTeams) {
+		t.Errorf("expected ErrUnknownRematchTeams, got %v", err)
+	}
+	if err := lobby.SetRematchTeams(RematchTeamsNew); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetRematchTeams() != RematchTeamsNew {
+		t.Errorf("expected new teams on rematch, got %q", lobby.GetRematchTeams())
+	}
+}
+
+func TestLobby_SetRematchTeams_BetweenGamesOnly(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+	if err := lobby.SetRematchTeams(RematchTeamsNew); err != nil {
+		t.Fatalf("expected rematch teams to be changeable after a game, got %v", err)
+	}
+
+	lobby.Rematch()
+	submitStarterTeams(t, lobby)
+	lobby.Start()
+	if err := lobby.SetRematchTeams(RematchTeamsSame); !errors.Is(err, ErrInvalidStateForRematchTeams) {
+		t.Errorf("expected ErrInvalidStateForRematchTeams, got %v", err)
+	}
+}
+
+func TestLobby_Rematch_NewTeams(t *testing.T) {
+	lobby := newFinishedLobby(t, 3)
+	lobby.SetRematchTeams(RematchTeamsNew)
+
+	if err := lobby.Rematch(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetState() != LobbyStateReady {
+		t.Errorf("expected state Ready, got %v", lobby.GetState())
+	}
+	if lobby.HasTeam("host-1") || lobby.HasTeam("player-2") {
+		t.Error("expected teams to be discarded for the rematch")
+	}
+	if err := lobby.Start(); !errors.Is(err, ErrTeamsNotSubmitted) {
+		t.Errorf("expected the rematch to wait for new teams, got %v", err)
+	}
+}
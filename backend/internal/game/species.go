@@ -0,0 +1,119 @@
+package game
+
+import "errors"
+
+// ErrUnknownSpecies is returned when a species ID is not in the catalogue
+var ErrUnknownSpecies = errors.New("unknown species")
+
+// Creature stat configuration
+const (
+	DefaultLevel = 50
+	defaultIV    = 31
+)
+
+// Species is the static definition of a creature species
+type Species struct {
+	ID        string
+	Name      string
+	Types     []Type
+	BaseStats Stats
+	Learnset  []string // Move IDs this species can learn
+}
+
+// CanLearn checks if the species can learn the given move
+func (s *Species) CanLearn(moveID string) bool {
+	for _, id := range s.Learnset {
+		if id == moveID {
+			return true
+		}
+	}
+	return false
+}
+
+// StatsAtLevel computes a creature's stats from the species base stats
+func (s *Species) StatsAtLevel(level int) Stats {
+	stat := func(base int) int {
+		return (2*base+defaultIV)*level/100 + 5
+	}
+	return Stats{
+		HP:        (2*s.BaseStats.HP+defaultIV)*level/100 + level + 10,
+		Attack:    stat(s.BaseStats.Attack),
+		Defense:   stat(s.BaseStats.Defense),
+		SpAttack:  stat(s.BaseStats.SpAttack),
+		SpDefense: stat(s.BaseStats.SpDefense),
+		Speed:     stat(s.BaseStats.Speed),
+	}
+}
+
+// speciesCatalogue holds every species in data version 1, keyed by ID
+var speciesCatalogue = map[string]*Species{
+	"venusaur": {
+		ID: "venusaur", Name: "Venusaur", Types: []Type{TypeGrass, TypePoison},
+		BaseStats: Stats{HP: 80, Attack: 82, Defense: 83, SpAttack: 100, SpDefense: 100, Speed: 80},
+		Learnset:  []string{"tackle", "razor-leaf", "leech-seed", "swords-dance", "earthquake", "growl"},
+	},
+	"charizard": {
+		ID: "charizard", Name: "Charizard", Types: []Type{TypeFire, TypeFlying},
+		BaseStats: Stats{HP: 78, Attack: 84, Defense: 78, SpAttack: 109, SpDefense: 85, Speed: 100},
+		Learnset:  []string{"flamethrower", "slash", "earthquake", "swords-dance", "growl", "focus-energy", "bite"},
+	},
+	"blastoise": {
+		ID: "blastoise", Name: "Blastoise", Types: []Type{TypeWater},
+		BaseStats: Stats{HP: 79, Attack: 83, Defense: 100, SpAttack: 85, SpDefense: 105, Speed: 78},
+		Learnset:  []string{"surf", "hydro-pump", "ice-beam", "bite", "tail-whip", "earthquake", "fake-out"},
+	},
+	"pikachu": {
+		ID: "pikachu", Name: "Pikachu", Types: []Type{TypeElectric},
+		BaseStats: Stats{HP: 35, Attack: 55, Defense: 40, SpAttack: 50, SpDefense: 50, Speed: 90},
+		Learnset:  []string{"thunderbolt", "quick-attack", "agility", "double-team", "tail-whip", "growl", "fake-out"},
+	},
+	"gengar": {
+		ID: "gengar", Name: "Gengar", Types: []Type{TypeGhost, TypePoison},
+		BaseStats: Stats{HP: 60, Attack: 65, Defense: 60, SpAttack: 130, SpDefense: 75, Speed: 110},
+		Learnset:  []string{"confuse-ray", "psychic", "thunderbolt", "ice-beam", "double-team"},
+	},
+	"alakazam": {
+		ID: "alakazam", Name: "Alakazam", Types: []Type{TypePsychic},
+		BaseStats: Stats{HP: 55, Attack: 50, Defense: 45, SpAttack: 135, SpDefense: 95, Speed: 120},
+		Learnset:  []string{"psychic", "calm-mind", "confuse-ray", "thunderbolt", "agility"},
+	},
+	"machamp": {
+		ID: "machamp", Name: "Machamp", Types: []Type{TypeFighting},
+		BaseStats: Stats{HP: 90, Attack: 130, Defense: 80, SpAttack: 65, SpDefense: 85, Speed: 55},
+		Learnset:  []string{"close-combat", "earthquake", "focus-energy", "slash", "tackle"},
+	},
+	"steelix": {
+		ID: "steelix", Name: "Steelix", Types: []Type{TypeSteel, TypeGround},
+		BaseStats: Stats{HP: 75, Attack: 85, Defense: 200, SpAttack: 55, SpDefense: 65, Speed: 30},
+		Learnset:  []string{"iron-head", "earthquake", "bite", "sand-attack", "tackle"},
+	},
+	"snorlax": {
+		ID: "snorlax", Name: "Snorlax", Types: []Type{TypeNormal},
+		BaseStats: Stats{HP: 160, Attack: 110, Defense: 65, SpAttack: 65, SpDefense: 110, Speed: 30},
+		Learnset:  []string{"tackle", "earthquake", "ice-beam", "surf", "bite", "iron-head"},
+	},
+}
+
+// LookupSpecies returns the current data's entry for a species ID
+func LookupSpecies(id string) (*Species, error) {
+	return CurrentDataPack().LookupSpecies(id)
+}
+
+// NewCreatureFromSpecies builds a battle-ready creature of the given species with the given moves from the current data
+func NewCreatureFromSpecies(id, speciesID string, level int, moveIDs []string) (*Creature, error) {
+	return CurrentDataPack().NewCreatureFromSpecies(id, speciesID, level, moveIDs)
+}
+
+// StarterTeam returns a ready-made legal team for players who do not build their own
+func StarterTeam() []TeamMember {
+	return []TeamMember{
+		{SpeciesID: "venusaur", Moves: []string{"razor-leaf", "leech-seed", "swords-dance", "earthquake"}},
+		{SpeciesID: "charizard", Moves: []string{"flamethrower", "slash", "earthquake", "focus-energy"}},
+		{SpeciesID: "blastoise", Moves: []string{"surf", "ice-beam", "bite", "tail-whip"}},
+	}
+}
+
+// NewStarterTeam builds the starter team for a player
+func NewStarterTeam(ownerID string) ([]*Creature, error) {
+	return BuildTeam(ownerID, StarterTeam())
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpecies_StatsAtLevel(t *testing.T) {
+	species, err := LookupSpecies("pikachu")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+
+	stats := species.StatsAtLevel(50)
+
+	if stats.HP != 110 {
+		t.Errorf("expected HP 110, got %d", stats.HP)
+	}
+	if stats.Speed != 110 {
+		t.Errorf("expected Speed 110, got %d", stats.Speed)
+	}
+}
+
+func TestLookupSpecies_Unknown(t *testing.T) {
+	if _, err := LookupSpecies("missingno"); !errors.Is(err, ErrUnknownSpecies) {
+		t.Errorf("expected ErrUnknownSpecies, got %v", err)
+	}
+}
+
+func TestNewCreatureFromSpecies_UnknownMove(t *testing.T) {
+	_, err := NewCreatureFromSpecies("c", "pikachu", DefaultLevel, []string{"splash"})
+	if !errors.Is(err, ErrUnknownMove) {
+		t.Errorf("expected ErrUnknownMove, got %v", err)
+	}
+}
+
+func TestNewStarterTeam(t *testing.T) {
+	team, err := NewStarterTeam("player-1")
+	if err != nil {
+		t.Fatalf("failed to build starter team: %v", err)
+	}
+
+	if len(team) != len(StarterTeam()) {
+		t.Fatalf("expected %d creatures, got %d", len(StarterTeam()), len(team))
+	}
+	for i, c := range team {
+		species, _ := LookupSpecies(c.SpeciesID)
+		for _, m := range c.Moves {
+			if !species.CanLearn(m.ID) {
+				t.Errorf("slot %d: %s cannot learn %s", i, c.SpeciesID, m.ID)
+			}
+		}
+		if c.CurrentHP != c.MaxHP() {
+			t.Errorf("slot %d: expected full HP", i)
+		}
+	}
+	if team[0].ID != "player-1-0" {
+		t.Errorf("expected owner-prefixed creature ID, got %s", team[0].ID)
+	}
+}
@@ -0,0 +1,80 @@
+package game
+
+// DefaultTurnLimit is the turn limit of the catalogue rulesets
+const DefaultTurnLimit = 100
+
+// endlessBattleTurns is how many consecutive turns may pass without progress, meaning no creature
+// fainted and no HP was lost overall, before the endless battle clause ends the battle
+const endlessBattleTurns = 20
+
+// SetTurnLimit sets the turn after which the battle is decided by remaining HP; 0 removes the limit
+func (b *Battle) SetTurnLimit(turns int) {
+	b.turnLimit = turns
+}
+
+// TurnLimit returns the turn after which the battle is decided by remaining HP, or 0 for no limit
+func (b *Battle) TurnLimit() int {
+	return b.turnLimit
+}
+
+// checkStalemate ends a battle still in progress once the turn limit is reached or the endless
+// battle clause applies. Either way the player with the higher share of their team's HP remaining
+// wins, and equal shares are a draw.
+func (b *Battle) checkStalemate() {
+	if b.outcome != nil {
+		return
+	}
+
+	hp, fainted := b.progress()
+	if hp < b.lastHP || fainted > b.lastFainted {
+		b.stalledTurns = 0
+	} else {
+		b.stalledTurns++
+	}
+	b.lastHP, b.lastFainted = hp, fainted
+
+	switch {
+	case b.turnLimit > 0 && b.Turn >= b.turnLimit:
+		b.decideByRemainingHP(EndReasonTurnLimit)
+	case b.stalledTurns >= endlessBattleTurns:
+		b.decideByRemainingHP(EndReasonEndlessBattle)
+	}
+}
+
+// progress returns the HP remaining across both teams and the number of fainted creatures
+func (b *Battle) progress() (hp, fainted int) {
+	for _, side := range b.Sides {
+		for _, c := range side.Team {
+			hp += c.CurrentHP
+			if c.IsFainted() {
+				fainted++
+			}
+		}
+	}
+	return hp, fainted
+}
+
+// decideByRemainingHP ends the battle in favour of the side with the higher share of its team's HP remaining
+func (b *Battle) decideByRemainingHP(reason EndReason) {
+	hp0, max0 := b.Sides[0].remainingHP()
+	hp1, max1 := b.Sides[1].remainingHP()
+
+	// Compare hp0/max0 with hp1/max1 without rounding
+	switch share0, share1 := hp0*max1, hp1*max0; {
+	case share0 > share1:
+		b.end(b.Sides[0].PlayerID, b.Sides[1].PlayerID, reason)
+	case share1 > share0:
+		b.end(b.Sides[1].PlayerID, b.Sides[0].PlayerID, reason)
+	default:
+		b.end("", "", reason)
+	}
+}
+
+// remainingHP returns the side's current and maximum HP summed over its team
+func (s *BattleSide) remainingHP() (current, max int) {
+	for _, c := range s.Team {
+		current += c.CurrentHP
+		max += c.MaxHP()
+	}
+	return current, max
+}
@@ -0,0 +1,119 @@
+package game
+
+import "testing"
+
+// ========================================
+// Turn Limit Tests
+// ========================================
+
+func TestTurnLimit_HigherRemainingHPWins(t *testing.T) {
+	c1 := newTestCreature(t, "a", []Type{TypeNormal}, 50, "growl")
+	c2 := newTestCreature(t, "b", []Type{TypeNormal}, 50, "growl")
+	c1.CurrentHP = c1.MaxHP() / 4
+	b := newTestBattle(&scriptedRNG{}, c1, c2)
+	b.SetTurnLimit(2)
+
+	resolveTurn(t, b, "growl", "growl")
+	if b.Outcome() != nil {
+		t.Fatalf("expected battle to continue before the turn limit, got %+v", b.Outcome())
+	}
+
+	resolveTurn(t, b, "growl", "growl")
+
+	outcome := b.Outcome()
+	if outcome == nil {
+		t.Fatal("expected battle to end at the turn limit")
+	}
+	if outcome.WinnerID != "player-2" || outcome.LoserID != "player-1" || outcome.Reason != EndReasonTurnLimit {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+}
+
+func TestTurnLimit_ComparesShareOfTeamHP(t *testing.T) {
+	// Player 1 has more HP left in absolute terms, but a smaller share of a larger team
+	c1 := newTestCreature(t, "a", []Type{TypeNormal}, 50, "growl")
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 50, "growl")
+	bench.CurrentHP = 0
+	c2 := newTestCreature(t, "b", []Type{TypeNormal}, 50, "growl")
+	c2.CurrentHP = c2.MaxHP() * 3 / 4
+	b := NewBattle("battle-1", NewBattleSide("player-1", []*Creature{c1, bench}), NewBattleSide("player-2", []*Creature{c2}), &scriptedRNG{})
+	b.SetTurnLimit(1)
+
+	resolveTurn(t, b, "growl", "growl")
+
+	if outcome := b.Outcome(); outcome == nil || outcome.WinnerID != "player-2" {
+		t.Errorf("expected player-2 to win on HP share, got %+v", outcome)
+	}
+}
+
+func TestTurnLimit_EqualHPIsDraw(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "growl"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "growl"))
+	b.SetTurnLimit(1)
+
+	resolveTurn(t, b, "growl", "growl")
+
+	outcome := b.Outcome()
+	if outcome == nil {
+		t.Fatal("expected battle to end at the turn limit")
+	}
+	if !outcome.IsDraw() || outcome.LoserID != "" || outcome.Reason != EndReasonTurnLimit {
+		t.Errorf("expected a draw, got %+v", outcome)
+	}
+}
+
+func TestTurnLimit_ZeroMeansNoLimit(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{fallback: 15}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	resolveTurn(t, b, "tackle", "tackle")
+
+	if b.TurnLimit() != 0 || b.Outcome() != nil {
+		t.Errorf("expected no turn limit, got limit %d and outcome %+v", b.TurnLimit(), b.Outcome())
+	}
+}
+
+// ========================================
+// Endless Battle Tests
+// ========================================
+
+func TestEndlessBattle_EndsAfterTurnsWithoutProgress(t *testing.T) {
+	c1 := newTestCreature(t, "a", []Type{TypeNormal}, 50, "growl")
+	c2 := newTestCreature(t, "b", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{}, c1, c2)
+	c1.CurrentHP = c1.MaxHP() / 2
+
+	// The first turn counts as progress, since HP was lost since the battle was created
+	for i := 0; i < endlessBattleTurns; i++ {
+		resolveTurn(t, b, "growl", "growl")
+		if b.Outcome() != nil {
+			t.Fatalf("expected battle to continue after %d turns, got %+v", i+1, b.Outcome())
+		}
+	}
+
+	resolveTurn(t, b, "growl", "growl")
+
+	outcome := b.Outcome()
+	if outcome == nil {
+		t.Fatal("expected the endless battle clause to end the battle")
+	}
+	if outcome.WinnerID != "player-2" || outcome.Reason != EndReasonEndlessBattle {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+}
+
+func TestEndlessBattle_DamageResetsCount(t *testing.T) {
+	c1 := newTestCreature(t, "a", []Type{TypeNormal}, 50, "growl", "tackle")
+	c2 := newTestCreature(t, "b", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, c1, c2)
+
+	for i := 0; i < endlessBattleTurns-1; i++ {
+		resolveTurn(t, b, "growl", "growl")
+	}
+	resolveTurn(t, b, "tackle", "growl")
+	for i := 0; i < endlessBattleTurns-1; i++ {
+		resolveTurn(t, b, "growl", "growl")
+	}
+
+	if b.Outcome() != nil {
+		t.Errorf("expected damage to reset the endless battle count, got %+v", b.Outcome())
+	}
+}
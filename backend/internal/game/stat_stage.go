@@ -0,0 +1,135 @@
+package game
+
+// BattleStat identifies a stat that can be modified by stages during battle
+type BattleStat string
+
+const (
+	StatAttack    BattleStat = "attack"
+	StatDefense   BattleStat = "defense"
+	StatSpAttack  BattleStat = "sp_attack"
+	StatSpDefense BattleStat = "sp_defense"
+	StatSpeed     BattleStat = "speed"
+	StatAccuracy  BattleStat = "accuracy"
+	StatEvasion   BattleStat = "evasion"
+	StatCritical  BattleStat = "critical"
+)
+
+// Stat stage limits
+const (
+	minStatStage = -6
+	maxStatStage = 6
+	maxCritStage = 3
+)
+
+// StatChange is a stage modification applied by a move
+type StatChange struct {
+	Stat   BattleStat
+	Stages int
+}
+
+// StatStages tracks stage modifiers for each battle stat.
+// Stages are reset when the creature leaves the field.
+type StatStages struct {
+	Attack    int
+	Defense   int
+	SpAttack  int
+	SpDefense int
+	Speed     int
+	Accuracy  int
+	Evasion   int
+	Critical  int // Critical hit stage, 0..3
+}
+
+// stage returns a pointer to the stage counter for a stat
+func (s *StatStages) stage(stat BattleStat) *int {
+	switch stat {
+	case StatAttack:
+		return &s.Attack
+	case StatDefense:
+		return &s.Defense
+	case StatSpAttack:
+		return &s.SpAttack
+	case StatSpDefense:
+		return &s.SpDefense
+	case StatSpeed:
+		return &s.Speed
+	case StatAccuracy:
+		return &s.Accuracy
+	case StatEvasion:
+		return &s.Evasion
+	case StatCritical:
+		return &s.Critical
+	default:
+		return nil
+	}
+}
+
+// Get returns the current stage for a stat
+func (s *StatStages) Get(stat BattleStat) int {
+	if p := s.stage(stat); p != nil {
+		return *p
+	}
+	return 0
+}
+
+// Apply changes a stat's stage, clamped to -6..+6 (0..3 for critical), and returns the change actually applied
+func (s *StatStages) Apply(stat BattleStat, delta int) int {
+	p := s.stage(stat)
+	if p == nil {
+		return 0
+	}
+	lower, upper := minStatStage, maxStatStage
+	if stat == StatCritical {
+		lower, upper = 0, maxCritStage
+	}
+	next := min(max(*p+delta, lower), upper)
+	applied := next - *p
+	*p = next
+	return applied
+}
+
+// stageMultiplier converts a stage into a stat multiplier: +1 is 3/2, -1 is 2/3, and so on
+func stageMultiplier(stage int) float64 {
+	if stage >= 0 {
+		return float64(2+stage) / 2
+	}
+	return 2 / float64(2-stage)
+}
+
+// EffectiveStat returns a stat after applying its current stage modifier
+func (c *Creature) EffectiveStat(stat BattleStat) int {
+	return c.statAtStage(stat, c.Stages.Get(stat))
+}
+
+// statAtStage returns a stat as it would be at the given stage
+func (c *Creature) statAtStage(stat BattleStat, stage int) int {
+	var base int
+	switch stat {
+	case StatAttack:
+		base = c.Stats.Attack
+	case StatDefense:
+		base = c.Stats.Defense
+	case StatSpAttack:
+		base = c.Stats.SpAttack
+	case StatSpDefense:
+		base = c.Stats.SpDefense
+	case StatSpeed:
+		base = c.Stats.Speed
+	}
+	return int(float64(base) * stageMultiplier(stage))
+}
+
+// applyStatChanges modifies a creature's stages and emits a stat_changed event for each change.
+// It returns false if no stage could be changed because every stat was already at its limit.
+func (b *Battle) applyStatChanges(actor string, target *Creature, changes []StatChange) bool {
+	changed := false
+	for _, change := range changes {
+		applied := target.Stages.Apply(change.Stat, change.Stages)
+		if applied == 0 {
+			continue
+		}
+		changed = true
+		b.emit(BattleEvent{Type: EventStatChanged, Actor: actor, Target: target.ID, Stat: string(change.Stat), Stages: applied})
+	}
+	return changed
+}
@@ -0,0 +1,165 @@
+package game
+
+import "testing"
+
+// ========================================
+// StatStages Tests
+// ========================================
+
+func TestStatStages_ApplyClampsToLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    int
+		delta    int
+		expected int
+		applied  int
+	}{
+		{"raise within range", 0, 2, 2, 2},
+		{"lower within range", 0, -1, -1, -1},
+		{"raise past max", 5, 2, 6, 1},
+		{"lower past min", -5, -3, -6, -1},
+		{"already at max", 6, 1, 6, 0},
+		{"already at min", -6, -2, -6, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages := StatStages{Attack: tt.start}
+			applied := stages.Apply(StatAttack, tt.delta)
+			if applied != tt.applied {
+				t.Errorf("expected applied %d, got %d", tt.applied, applied)
+			}
+			if stages.Get(StatAttack) != tt.expected {
+				t.Errorf("expected stage %d, got %d", tt.expected, stages.Get(StatAttack))
+			}
+		})
+	}
+}
+
+func TestStatStages_UnknownStatIgnored(t *testing.T) {
+	stages := StatStages{}
+	if applied := stages.Apply(BattleStat("luck"), 2); applied != 0 {
+		t.Errorf("expected unknown stat to be ignored, got %d", applied)
+	}
+}
+
+func TestEffectiveStat_AppliesMultiplier(t *testing.T) {
+	tests := []struct {
+		stage    int
+		expected int
+	}{
+		{0, 100},
+		{1, 150},
+		{2, 200},
+		{6, 400},
+		{-1, 66},
+		{-2, 50},
+		{-6, 25},
+	}
+
+	for _, tt := range tests {
+		c := newTestCreature(t, "c", []Type{TypeNormal}, 100, "tackle")
+		c.Stages.Attack = tt.stage
+		if got := c.EffectiveStat(StatAttack); got != tt.expected {
+			t.Errorf("stage %d: expected %d, got %d", tt.stage, tt.expected, got)
+		}
+	}
+}
+
+// ========================================
+// Battle Integration Tests
+// ========================================
+
+func TestSwordsDance_RaisesAttackAndEmitsEvent(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "swords-dance")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, "swords-dance", "growl")
+
+	changes := findEvents(events, EventStatChanged)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 stat_changed events, got %d", len(changes))
+	}
+	if changes[0].Target != "user" || changes[0].Stat != string(StatAttack) || changes[0].Stages != 2 {
+		t.Errorf("expected +2 attack on user, got %+v", changes[0])
+	}
+	if changes[1].Target != "user" || changes[1].Stages != -1 {
+		t.Errorf("expected growl to lower user attack by 1, got %+v", changes[1])
+	}
+	if user.Stages.Attack != 1 {
+		t.Errorf("expected net attack stage +1, got %d", user.Stages.Attack)
+	}
+}
+
+func TestStatChange_AtLimitFails(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "swords-dance")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "swords-dance")
+	user.Stages.Attack = maxStatStage
+	b := newTestBattle(&scriptedRNG{fallback: 15}, user, foe)
+
+	events := resolveTurn(t, b, "swords-dance", "swords-dance")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Actor != "player-1" || failed[0].Reason != FailReasonStatLimit {
+		t.Errorf("expected player-1's swords dance to fail at the limit, got %+v", failed)
+	}
+}
+
+func TestStatStages_AffectDamage(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeFire}, 100, "tackle")
+	defender := newTestCreature(t, "defender", []Type{TypeFire}, 50, "tackle")
+	move := mustMove(t, "tackle")
+
+	neutral := CalculateDamage(attacker, defender, move, 100, false).Damage
+	attacker.Stages.Attack = 2
+	boosted := CalculateDamage(attacker, defender, move, 100, false).Damage
+	defender.Stages.Defense = 2
+	walled := CalculateDamage(attacker, defender, move, 100, false).Damage
+
+	if boosted <= neutral {
+		t.Errorf("expected +2 attack to increase damage: neutral %d, boosted %d", neutral, boosted)
+	}
+	if walled != neutral {
+		t.Errorf("expected equal attack and defense boosts to cancel: neutral %d, got %d", neutral, walled)
+	}
+}
+
+func TestStatStages_AffectTurnOrder(t *testing.T) {
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 60, "tackle")
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "tackle")
+	slow.Stages.Speed = 2
+	b := newTestBattle(&scriptedRNG{fallback: 15}, slow, fast)
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	used := findEvents(events, EventMoveUsed)
+	if used[0].Actor != "player-1" {
+		t.Errorf("expected +2 speed creature to move first, got %s", used[0].Actor)
+	}
+}
+
+func TestCloseCombat_LowersUserDefenses(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeFighting}, 100, "close-combat")
+	foe := newTestCreature(t, "foe", []Type{TypeNormal}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 0}, user, foe)
+
+	resolveTurn(t, b, "close-combat", "tackle")
+
+	if user.Stages.Defense != -1 || user.Stages.SpDefense != -1 {
+		t.Errorf("expected -1 defense and sp_defense, got %+v", user.Stages)
+	}
+}
+
+func TestSwitch_ResetsStatStages(t *testing.T) {
+	lead := newTestCreature(t, "lead", []Type{TypeNormal}, 50, "tackle")
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 50, "tackle")
+	side := NewBattleSide("player-1", []*Creature{lead, bench})
+	lead.Stages.Attack = 4
+
+	side.Switch(1)
+
+	if lead.Stages != (StatStages{}) {
+		t.Errorf("expected stat stages reset on switch out, got %+v", lead.Stages)
+	}
+}
@@ -0,0 +1,81 @@
+package game
+
+// StatusCondition is a major status that persists when the creature leaves the field
+type StatusCondition string
+
+const (
+	StatusNone      StatusCondition = ""
+	StatusPoison    StatusCondition = "poison"
+	StatusBadPoison StatusCondition = "bad_poison"
+	StatusBurn      StatusCondition = "burn"
+)
+
+// Status condition configuration
+const (
+	poisonDamageDivisor    = 8   // Poison deals 1/8 of max HP each turn
+	badPoisonDamageDivisor = 16  // Bad poison deals n/16 of max HP on its nth turn
+	burnDamageDivisor      = 16  // Burn deals 1/16 of max HP each turn
+	burnAttackModifier     = 0.5 // Burn halves the damage of the burned creature's physical moves
+)
+
+// canBePoisoned checks if the creature can receive a poison status
+func (c *Creature) canBePoisoned() bool {
+	return c.Status == StatusNone && !c.HasType(TypePoison) && !c.HasType(TypeSteel)
+}
+
+// canBeBurned checks if the creature can receive a burn
+func (c *Creature) canBeBurned() bool {
+	return c.Status == StatusNone && !c.HasType(TypeFire)
+}
+
+// applyStatus inflicts a major status on the target and emits status_applied.
+// It returns false if the target is already statused or immune.
+func (b *Battle) applyStatus(actor string, target *Creature, status StatusCondition) bool {
+	if b.Field.blocksStatus(target) {
+		return false
+	}
+
+	switch status {
+	case StatusPoison, StatusBadPoison:
+		if !target.canBePoisoned() {
+			return false
+		}
+	case StatusBurn:
+		if !target.canBeBurned() {
+			return false
+		}
+	default:
+		return false
+	}
+
+	target.Status = status
+	target.ToxicCounter = 0
+	b.emit(BattleEvent{Type: EventStatusApplied, Actor: actor, Target: target.ID, Status: string(status)})
+	return true
+}
+
+// applyStatusDamage deals end-of-turn damage from the active creature's major status
+func (b *Battle) applyStatusDamage(sideIdx int) {
+	side := b.Sides[sideIdx]
+	creature := side.Active()
+	if creature.IsFainted() {
+		return
+	}
+
+	var damage int
+	switch creature.Status {
+	case StatusPoison:
+		damage = creature.MaxHP() / poisonDamageDivisor
+	case StatusBadPoison:
+		creature.ToxicCounter++
+		damage = creature.MaxHP() * creature.ToxicCounter / badPoisonDamageDivisor
+	case StatusBurn:
+		damage = creature.MaxHP() / burnDamageDivisor
+	default:
+		return
+	}
+
+	dealt := creature.TakeDamage(max(damage, 1))
+	b.emit(BattleEvent{Type: EventStatusDamage, Actor: side.PlayerID, Target: creature.ID, Status: string(creature.Status), Damage: dealt})
+	b.checkFainted(side.PlayerID, creature)
+}
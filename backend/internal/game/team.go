@@ -0,0 +1,215 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Team validation errors. Every error returned by team validation also matches ErrInvalidTeam.
+var (
+	ErrInvalidTeam      = errors.New("invalid team")
+	ErrEmptyTeam        = errors.New("team has no creatures")
+	ErrTeamTooLarge     = errors.New("team has too many creatures")
+	ErrInvalidLevel     = errors.New("level out of range")
+	ErrLevelAboveCap    = errors.New("level above the ruleset's level cap")
+	ErrNoMoves          = errors.New("creature has no moves")
+	ErrTooManyMoves     = errors.New("creature has too many moves")
+	ErrDuplicateMove    = errors.New("creature knows the same move twice")
+	ErrMoveNotLearnable = errors.New("species cannot learn move")
+	ErrSpeciesBanned    = errors.New("species is banned")
+	ErrMoveBanned       = errors.New("move is banned")
+	ErrSpeciesClause    = errors.New("team has more than one creature of the same species")
+	ErrItemClause       = errors.New("team has more than one creature holding the same item")
+	ErrNicknameTooLong  = errors.New("nickname is too long")
+)
+
+// Team limits
+const (
+	MaxTeamSize         = 6
+	MaxMovesPerCreature = 4
+	MinLevel            = 1
+	MaxLevel            = 100
+	MaxNicknameLength   = 18 // In characters, after sanitizing
+)
+
+// Rule identifies which team rule a violation breaks
+type Rule string
+
+const (
+	RuleTeamSize      Rule = "team_size"
+	RuleSpecies       Rule = "species"
+	RuleLevel         Rule = "level"
+	RuleLevelCap      Rule = "level_cap"
+	RuleMoves         Rule = "moves"
+	RuleBannedSpecies Rule = "banned_species"
+	RuleBannedMove    Rule = "banned_move"
+	RuleSpeciesClause Rule = "species_clause"
+	RuleItemClause    Rule = "item_clause"
+	RuleNickname      Rule = "nickname"
+)
+
+// TeamMember is a player's choice of species, level, item and moves for one team slot
+type TeamMember struct {
+	SpeciesID string
+	Level     int    // 0 means DefaultLevel
+	Item      string // held item ID; only checked by the item clause, items have no battle effect yet
+	Moves     []string
+	Nickname  string // Shown in place of the species name; empty for none
+	Shiny     bool   // Cosmetic only
+}
+
+// SanitizeNickname makes a nickname safe to show to other players: control and invisible
+// formatting characters are dropped, and runs of whitespace become a single space
+func SanitizeNickname(nickname string) string {
+	var b strings.Builder
+	for _, r := range nickname {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// SanitizeTeam returns a copy of the team with every nickname sanitized
+func SanitizeTeam(team []TeamMember) []TeamMember {
+	sanitized := copyTeam(team)
+	for i := range sanitized {
+		sanitized[i].Nickname = SanitizeNickname(sanitized[i].Nickname)
+	}
+	return sanitized
+}
+
+// level returns the member's level, falling back to DefaultLevel when unset
+func (m TeamMember) level() int {
+	if m.Level == 0 {
+		return DefaultLevel
+	}
+	return m.Level
+}
+
+// Violation is a single way in which a team breaks a ruleset
+type Violation struct {
+	Slot int // team slot, or -1 when the violation applies to the whole team
+	Rule Rule
+	Err  error
+}
+
+// Error returns the violation message, prefixed with the slot when there is one
+func (v Violation) Error() string {
+	if v.Slot < 0 {
+		return v.Err.Error()
+	}
+	return fmt.Sprintf("team slot %d: %v", v.Slot, v.Err)
+}
+
+// TeamError lists every violation found while validating a team.
+// It matches ErrInvalidTeam and each violation's error with errors.Is.
+type TeamError struct {
+	Violations []Violation
+}
+
+// Error joins the violations into a single message
+func (e *TeamError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return ErrInvalidTeam.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes ErrInvalidTeam and each violation's error to errors.Is
+func (e *TeamError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations)+1)
+	errs = append(errs, ErrInvalidTeam)
+	for _, v := range e.Violations {
+		errs = append(errs, v.Err)
+	}
+	return errs
+}
+
+// violations checks the member in a team slot against the catalogues and a ruleset
+func (m TeamMember) violations(slot int, r *Ruleset) []Violation {
+	var violations []Violation
+	add := func(rule Rule, err error) {
+		violations = append(violations, Violation{Slot: slot, Rule: rule, Err: err})
+	}
+
+	species, err := LookupSpecies(m.SpeciesID)
+	if err != nil {
+		add(RuleSpecies, fmt.Errorf("species %q: %w", m.SpeciesID, err))
+	} else if r.bansSpecies(m.SpeciesID) {
+		add(RuleBannedSpecies, fmt.Errorf("species %q: %w", m.SpeciesID, ErrSpeciesBanned))
+	}
+
+	if level := m.level(); level < MinLevel || level > MaxLevel {
+		add(RuleLevel, fmt.Errorf("level %d: %w", level, ErrInvalidLevel))
+	} else if level > r.LevelCap {
+		add(RuleLevelCap, fmt.Errorf("level %d exceeds cap %d: %w", level, r.LevelCap, ErrLevelAboveCap))
+	}
+
+	if n := len([]rune(SanitizeNickname(m.Nickname))); n > MaxNicknameLength {
+		add(RuleNickname, fmt.Errorf("%d characters, limit %d: %w", n, MaxNicknameLength, ErrNicknameTooLong))
+	}
+
+	if len(m.Moves) == 0 {
+		add(RuleMoves, ErrNoMoves)
+	}
+	if len(m.Moves) > MaxMovesPerCreature {
+		add(RuleMoves, ErrTooManyMoves)
+	}
+
+	seen := make(map[string]bool, len(m.Moves))
+	for _, moveID := range m.Moves {
+		if seen[moveID] {
+			add(RuleMoves, fmt.Errorf("move %q: %w", moveID, ErrDuplicateMove))
+			continue
+		}
+		seen[moveID] = true
+
+		if _, err := LookupMove(moveID); err != nil {
+			add(RuleMoves, fmt.Errorf("move %q: %w", moveID, err))
+			continue
+		}
+		if r.bansMove(moveID) {
+			add(RuleBannedMove, fmt.Errorf("move %q: %w", moveID, ErrMoveBanned))
+		}
+		if species != nil && !species.CanLearn(moveID) {
+			add(RuleMoves, fmt.Errorf("move %q: %w", moveID, ErrMoveNotLearnable))
+		}
+	}
+
+	return violations
+}
+
+// ValidateTeam checks that a team is legal under the default ruleset:
+// 1 to MaxTeamSize creatures, each of a known species with 1 to MaxMovesPerCreature distinct moves it can learn.
+func ValidateTeam(team []TeamMember) error {
+	return DefaultRuleset().Validate(team)
+}
+
+// BuildTeam validates a team and builds its battle-ready creatures.
+// Creature IDs are prefixed with the owner ID so they are unique within a battle.
+func BuildTeam(ownerID string, team []TeamMember) ([]*Creature, error) {
+	if err := ValidateTeam(team); err != nil {
+		return nil, err
+	}
+
+	creatures := make([]*Creature, 0, len(team))
+	for i, member := range team {
+		creature, err := NewCreatureFromSpecies(fmt.Sprintf("%s-%d", ownerID, i), member.SpeciesID, member.level(), member.Moves)
+		if err != nil {
+			return nil, fmt.Errorf("team slot %d: %w", i, err)
+		}
+		creature.Nickname = SanitizeNickname(member.Nickname)
+		creature.Shiny = member.Shiny
+		creatures = append(creatures, creature)
+	}
+	return creatures, nil
+}
@@ -0,0 +1,50 @@
+package game
+
+import "errors"
+
+// Team preview errors
+var (
+	ErrInTeamPreview     = errors.New("waiting for both players to choose a lead")
+	ErrNotInTeamPreview  = errors.New("battle is not in team preview")
+	ErrLeadAlreadyChosen = errors.New("lead already chosen")
+	ErrInvalidLead       = errors.New("invalid lead slot")
+)
+
+// StartTeamPreview holds the battle before turn 1 until both players have chosen their lead.
+// Without team preview the first team member of each side leads.
+func (b *Battle) StartTeamPreview() {
+	b.preview = true
+	b.leadChosen = [2]bool{}
+}
+
+// InTeamPreview returns true while the battle is waiting for lead choices
+func (b *Battle) InTeamPreview() bool {
+	return b.preview
+}
+
+// LeadChosen returns true once the player has chosen their lead during team preview
+func (b *Battle) LeadChosen(playerID string) bool {
+	idx, err := b.sideIndex(playerID)
+	if err != nil {
+		return false
+	}
+	return b.leadChosen[idx]
+}
+
+// ChooseLead picks the creature a player sends out first.
+// It returns true once both players have chosen and turn 1 can begin.
+func (b *Battle) ChooseLead(playerID string, slot int) (bool, error) {
+	if err := b.ValidateLead(playerID, slot); err != nil {
+		return false, err
+	}
+
+	idx, _ := b.sideIndex(playerID)
+	side := b.Sides[idx]
+	side.ActiveSlot = slot
+	b.leadChosen[idx] = true
+
+	if b.leadChosen[0] && b.leadChosen[1] {
+		b.preview = false
+	}
+	return !b.preview, nil
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// newPreviewBattle creates a battle in team preview with two creatures per side
+func newPreviewBattle(t *testing.T) *Battle {
+	t.Helper()
+	b := NewBattle("battle-1",
+		NewBattleSide("player-1", []*Creature{
+			newTestCreature(t, "a1", []Type{TypeNormal}, 50, "tackle"),
+			newTestCreature(t, "a2", []Type{TypeNormal}, 50, "tackle"),
+		}),
+		NewBattleSide("player-2", []*Creature{
+			newTestCreature(t, "b1", []Type{TypeNormal}, 50, "tackle"),
+			newTestCreature(t, "b2", []Type{TypeNormal}, 50, "tackle"),
+		}),
+		&scriptedRNG{fallback: 15})
+	b.StartTeamPreview()
+	return b
+}
+
+// ========================================
+// Team Preview Tests
+// ========================================
+
+func TestTeamPreview_ChooseLeadSetsActiveSlot(t *testing.T) {
+	b := newPreviewBattle(t)
+
+	ready, err := b.ChooseLead("player-1", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ready {
+		t.Error("expected preview to continue until both players choose")
+	}
+	if !b.LeadChosen("player-1") || b.LeadChosen("player-2") {
+		t.Error("expected only player-1 to have chosen a lead")
+	}
+
+	ready, err = b.ChooseLead("player-2", 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !ready {
+		t.Error("expected preview to end once both players choose")
+	}
+	if b.InTeamPreview() {
+		t.Error("expected battle to leave team preview")
+	}
+	if b.Sides[0].Active().ID != "a2" || b.Sides[1].Active().ID != "b1" {
+		t.Errorf("unexpected leads: %s vs %s", b.Sides[0].Active().ID, b.Sides[1].Active().ID)
+	}
+}
+
+func TestTeamPreview_BlocksActions(t *testing.T) {
+	b := newPreviewBattle(t)
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"})
+	if !errors.Is(err, ErrInTeamPreview) {
+		t.Fatalf("expected ErrInTeamPreview, got %v", err)
+	}
+
+	b.ChooseLead("player-1", 0)
+	b.ChooseLead("player-2", 0)
+
+	if err := b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "tackle"}); err != nil {
+		t.Errorf("expected actions to be accepted after preview, got %v", err)
+	}
+	if b.CurrentTurn() != 1 {
+		t.Errorf("expected preview to leave the battle on turn 1, got %d", b.CurrentTurn())
+	}
+}
+
+func TestTeamPreview_LeadAlreadyChosen(t *testing.T) {
+	b := newPreviewBattle(t)
+	b.ChooseLead("player-1", 0)
+
+	_, err := b.ChooseLead("player-1", 1)
+	if !errors.Is(err, ErrLeadAlreadyChosen) {
+		t.Errorf("expected ErrLeadAlreadyChosen, got %v", err)
+	}
+	if b.Sides[0].ActiveSlot != 0 {
+		t.Errorf("expected lead to stay in slot 0, got %d", b.Sides[0].ActiveSlot)
+	}
+}
+
+func TestTeamPreview_InvalidLead(t *testing.T) {
+	b := newPreviewBattle(t)
+
+	for _, slot := range []int{-1, 2} {
+		if _, err := b.ChooseLead("player-1", slot); !errors.Is(err, ErrInvalidLead) {
+			t.Errorf("slot %d: expected ErrInvalidLead, got %v", slot, err)
+		}
+	}
+	if b.LeadChosen("player-1") {
+		t.Error("expected invalid choices not to count as a lead")
+	}
+}
+
+func TestTeamPreview_PlayerNotInBattle(t *testing.T) {
+	b := newPreviewBattle(t)
+
+	if _, err := b.ChooseLead("stranger", 0); !errors.Is(err, ErrPlayerNotInBattle) {
+		t.Errorf("expected ErrPlayerNotInBattle, got %v", err)
+	}
+}
+
+func TestTeamPreview_NotStarted(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	if b.InTeamPreview() {
+		t.Error("expected battles to skip team preview by default")
+	}
+	if _, err := b.ChooseLead("player-1", 0); !errors.Is(err, ErrNotInTeamPreview) {
+		t.Errorf("expected ErrNotInTeamPreview, got %v", err)
+	}
+}
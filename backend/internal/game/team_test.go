@@ -0,0 +1,102 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Team Validation Tests
+// ========================================
+
+func TestValidateTeam_StarterTeamIsLegal(t *testing.T) {
+	if err := ValidateTeam(StarterTeam()); err != nil {
+		t.Errorf("expected starter team to be legal, got %v", err)
+	}
+}
+
+func TestValidateTeam_Violations(t *testing.T) {
+	tests := []struct {
+		name     string
+		team     []TeamMember
+		expected error
+	}{
+		{"empty team", nil, ErrEmptyTeam},
+		{"too many creatures", make([]TeamMember, MaxTeamSize+1), ErrTeamTooLarge},
+		{"unknown species", []TeamMember{{SpeciesID: "missingno", Moves: []string{"tackle"}}}, ErrUnknownSpecies},
+		{"level too high", []TeamMember{{SpeciesID: "pikachu", Level: MaxLevel + 1, Moves: []string{"thunderbolt"}}}, ErrInvalidLevel},
+		{"negative level", []TeamMember{{SpeciesID: "pikachu", Level: -1, Moves: []string{"thunderbolt"}}}, ErrInvalidLevel},
+		{"no moves", []TeamMember{{SpeciesID: "pikachu"}}, ErrNoMoves},
+		{"too many moves", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt", "quick-attack", "agility", "growl", "tail-whip"}}}, ErrTooManyMoves},
+		{"unknown move", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"splash"}}}, ErrUnknownMove},
+		{"unlearnable move", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"surf"}}}, ErrMoveNotLearnable},
+		{"duplicate move", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt", "thunderbolt"}}}, ErrDuplicateMove},
+		{"nickname too long", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}, Nickname: "Sparky the Magnificent"}}, ErrNicknameTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTeam(tt.team)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+			if !errors.Is(err, ErrInvalidTeam) {
+				t.Errorf("expected error to wrap ErrInvalidTeam, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildTeam_UsesSubmittedLevelAndMoves(t *testing.T) {
+	team, err := BuildTeam("player-1", []TeamMember{{SpeciesID: "pikachu", Level: 30, Moves: []string{"thunderbolt", "quick-attack"}}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	c := team[0]
+	if c.ID != "player-1-0" || c.SpeciesID != "pikachu" || c.Level != 30 || len(c.Moves) != 2 {
+		t.Errorf("unexpected creature: %+v", c)
+	}
+}
+
+func TestBuildTeam_DefaultLevel(t *testing.T) {
+	team, _ := BuildTeam("player-1", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}}})
+
+	if team[0].Level != DefaultLevel {
+		t.Errorf("expected default level %d, got %d", DefaultLevel, team[0].Level)
+	}
+}
+
+func TestBuildTeam_NicknameAndShiny(t *testing.T) {
+	team, err := BuildTeam("player-1", []TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}, Nickname: " Spar\u200bky\n", Shiny: true}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if team[0].Nickname != "Sparky" || !team[0].Shiny || team[0].Name != "Pikachu" {
+		t.Errorf("unexpected creature: %+v", team[0])
+	}
+}
+
+func TestSanitizeNickname(t *testing.T) {
+	tests := map[string]string{
+		"Sparky":                    "Sparky",
+		"  Big \t\n Guy ":           "Big Guy",
+		"Bell\a\x00":                "Bell",
+		"\u202eevil\u200d":          "evil",
+		"Pika\u00e7hu \u2764\ufe0f": "Pika\u00e7hu \u2764\ufe0f",
+		"":                          "",
+	}
+	for input, want := range tests {
+		if got := SanitizeNickname(input); got != want {
+			t.Errorf("SanitizeNickname(%q): expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestValidateTeam_NicknameLengthCountsSanitizedCharacters(t *testing.T) {
+	nickname := "\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9\u00e9   "
+	if err := ValidateTeam([]TeamMember{{SpeciesID: "pikachu", Moves: []string{"thunderbolt"}, Nickname: nickname}}); err != nil {
+		t.Errorf("expected an 18 character nickname with trailing spaces to be legal, got %v", err)
+	}
+}
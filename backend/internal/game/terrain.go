@@ -0,0 +1,98 @@
+package game
+
+// Terrain is a field-wide condition that affects grounded creatures
+type Terrain string
+
+const (
+	TerrainNone     Terrain = ""
+	TerrainElectric Terrain = "electric"
+	TerrainGrassy   Terrain = "grassy"
+	TerrainPsychic  Terrain = "psychic"
+	TerrainMisty    Terrain = "misty"
+)
+
+// Terrain configuration
+const (
+	terrainDuration      = 5   // Turns a terrain lasts, including the turn it was set
+	terrainPowerBoost    = 1.3 // Boost to moves of the terrain's type used by grounded creatures
+	terrainPowerWeakened = 0.5 // Reduction to moves the terrain weakens against grounded targets
+)
+
+// terrainBoostedType maps each terrain to the move type it powers up
+var terrainBoostedType = map[Terrain]Type{
+	TerrainElectric: TypeElectric,
+	TerrainGrassy:   TypeGrass,
+	TerrainPsychic:  TypePsychic,
+}
+
+// grassyTerrainWeakenedMoves are ground-shaking moves dampened by Grassy Terrain
+var grassyTerrainWeakenedMoves = map[string]bool{
+	"earthquake": true,
+}
+
+// Field holds conditions that affect both sides of the battle
+type Field struct {
+	Terrain      Terrain
+	TerrainTurns int // Turns remaining, including the current one
+}
+
+// movePower returns the move's base power after terrain modifiers
+func (f *Field) movePower(attacker, defender *Creature, move *Move) int {
+	modifier := 1.0
+	if boosted, ok := terrainBoostedType[f.Terrain]; ok && move.Type == boosted && attacker.isGrounded() {
+		modifier *= terrainPowerBoost
+	}
+	if defender.isGrounded() {
+		switch {
+		case f.Terrain == TerrainGrassy && grassyTerrainWeakenedMoves[move.ID]:
+			modifier *= terrainPowerWeakened
+		case f.Terrain == TerrainMisty && move.Type == TypeDragon:
+			modifier *= terrainPowerWeakened
+		}
+	}
+	return int(float64(move.Power) * modifier)
+}
+
+// withTerrainPower returns the move as modified by the terrain, copying it only if its power changes
+func (f *Field) withTerrainPower(attacker, defender *Creature, move *Move) *Move {
+	power := f.movePower(attacker, defender, move)
+	if power == move.Power {
+		return move
+	}
+	modified := *move
+	modified.Power = power
+	return &modified
+}
+
+// blocksStatus reports whether the terrain protects the creature from status conditions
+func (f *Field) blocksStatus(c *Creature) bool {
+	return f.Terrain == TerrainMisty && c.isGrounded()
+}
+
+// setTerrain replaces the active terrain with the move's terrain
+func (b *Battle) setTerrain(actor string, move *Move) {
+	if b.Field.Terrain == move.Terrain {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonAlreadyAffected})
+		return
+	}
+
+	b.Field.Terrain = move.Terrain
+	b.Field.TerrainTurns = terrainDuration
+	b.emit(BattleEvent{Type: EventTerrainSet, Actor: actor, Terrain: string(move.Terrain)})
+}
+
+// tickTerrain counts down the active terrain at the end of the turn
+func (b *Battle) tickTerrain() {
+	if b.Field.Terrain == TerrainNone {
+		return
+	}
+
+	b.Field.TerrainTurns--
+	if b.Field.TerrainTurns > 0 {
+		return
+	}
+
+	ended := b.Field.Terrain
+	b.Field.Terrain = TerrainNone
+	b.emit(BattleEvent{Type: EventTerrainEnded, Terrain: string(ended)})
+}
@@ -0,0 +1,167 @@
+package game
+
+import "testing"
+
+// ========================================
+// Terrain Setting Tests
+// ========================================
+
+func TestTerrain_SetAndExpires(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeElectric}, 100, "electric-terrain", "growl")
+	opponent := newTestCreature(t, "opponent", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, setter, opponent)
+
+	events := resolveTurn(t, b, "electric-terrain", "growl")
+
+	set := findEvents(events, EventTerrainSet)
+	if len(set) != 1 || set[0].Terrain != string(TerrainElectric) {
+		t.Fatalf("expected electric terrain to be set, got %+v", set)
+	}
+	if b.Field.Terrain != TerrainElectric || b.Field.TerrainTurns != terrainDuration-1 {
+		t.Fatalf("expected electric terrain with %d turns left, got %+v", terrainDuration-1, b.Field)
+	}
+
+	for i := 1; i < terrainDuration-1; i++ {
+		resolveTurn(t, b, "growl", "growl")
+	}
+	if b.Field.Terrain != TerrainElectric {
+		t.Fatal("expected terrain to last until its final turn")
+	}
+
+	events = resolveTurn(t, b, "growl", "growl")
+	ended := findEvents(events, EventTerrainEnded)
+	if len(ended) != 1 || ended[0].Terrain != string(TerrainElectric) {
+		t.Errorf("expected terrain_ended event, got %+v", ended)
+	}
+	if b.Field.Terrain != TerrainNone {
+		t.Errorf("expected no terrain, got %q", b.Field.Terrain)
+	}
+}
+
+func TestTerrain_SameTerrainFails(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeGrass}, 100, "grassy-terrain")
+	opponent := newTestCreature(t, "opponent", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, setter, opponent)
+
+	resolveTurn(t, b, "grassy-terrain", "growl")
+	events := resolveTurn(t, b, "grassy-terrain", "growl")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonAlreadyAffected {
+		t.Errorf("expected already_affected failure, got %+v", failed)
+	}
+}
+
+func TestTerrain_ReplacesActiveTerrain(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypePsychic}, 100, "psychic-terrain")
+	opponent := newTestCreature(t, "opponent", []Type{TypeFairy}, 50, "misty-terrain")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, setter, opponent)
+
+	resolveTurn(t, b, "psychic-terrain", "misty-terrain")
+
+	if b.Field.Terrain != TerrainMisty || b.Field.TerrainTurns != terrainDuration-1 {
+		t.Errorf("expected the later terrain to replace the first, got %+v", b.Field)
+	}
+}
+
+// ========================================
+// Terrain Power Modifier Tests
+// ========================================
+
+func TestTerrain_MovePower(t *testing.T) {
+	grounded := newTestCreature(t, "grounded", []Type{TypeNormal}, 50)
+	flying := newTestCreature(t, "flying", []Type{TypeFlying}, 50)
+
+	tests := []struct {
+		name     string
+		terrain  Terrain
+		attacker *Creature
+		defender *Creature
+		moveID   string
+		expected int
+	}{
+		{"electric boosts electric moves", TerrainElectric, grounded, grounded, "thunderbolt", 117},
+		{"grassy boosts grass moves", TerrainGrassy, grounded, grounded, "razor-leaf", 71},
+		{"psychic boosts psychic moves", TerrainPsychic, grounded, grounded, "psychic", 117},
+		{"boost requires grounded attacker", TerrainElectric, flying, grounded, "thunderbolt", 90},
+		{"boost ignores other types", TerrainElectric, grounded, grounded, "surf", 90},
+		{"grassy weakens earthquake", TerrainGrassy, grounded, grounded, "earthquake", 50},
+		{"misty weakens dragon moves", TerrainMisty, grounded, grounded, "outrage", 60},
+		{"weakening requires grounded target", TerrainMisty, grounded, flying, "outrage", 120},
+		{"no terrain", TerrainNone, grounded, grounded, "thunderbolt", 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := Field{Terrain: tt.terrain, TerrainTurns: terrainDuration}
+			move := mustMove(t, tt.moveID)
+			if got := field.movePower(tt.attacker, tt.defender, move); got != tt.expected {
+				t.Errorf("expected power %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTerrain_BoostIncreasesDamage(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "thunderbolt")
+	plain := newTestCreature(t, "plain", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, attacker, plain)
+	baseline := findEvents(resolveTurn(t, b, "thunderbolt", "growl"), EventDamageDealt)[0].Damage
+
+	attacker = newTestCreature(t, "attacker", []Type{TypeNormal}, 100, "thunderbolt")
+	plain = newTestCreature(t, "plain", []Type{TypeNormal}, 50, "growl")
+	b = newTestBattle(&scriptedRNG{fallback: 15}, attacker, plain)
+	b.Field = Field{Terrain: TerrainElectric, TerrainTurns: terrainDuration}
+	boosted := findEvents(resolveTurn(t, b, "thunderbolt", "growl"), EventDamageDealt)[0].Damage
+
+	if boosted <= baseline {
+		t.Errorf("expected electric terrain to increase damage, got %d vs %d", boosted, baseline)
+	}
+}
+
+// ========================================
+// Terrain Status Immunity Tests
+// ========================================
+
+func TestMistyTerrain_BlocksConfusion(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeGhost}, 100, "confuse-ray")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, attacker, target)
+	b.Field = Field{Terrain: TerrainMisty, TerrainTurns: terrainDuration}
+
+	events := resolveTurn(t, b, "confuse-ray", "growl")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonTerrain {
+		t.Errorf("expected terrain failure, got %+v", failed)
+	}
+	if target.Volatiles.Has(VolatileConfusion) {
+		t.Error("expected grounded target not to be confused")
+	}
+}
+
+func TestMistyTerrain_BlocksToxicSpikesPoison(t *testing.T) {
+	setter := newTestCreature(t, "setter", []Type{TypeNormal}, 100, "growl")
+	b, bench := newHazardBattle(t, setter, []Type{TypeNormal})
+	b.Sides[1].Hazards.ToxicSpikes = 1
+	b.Field = Field{Terrain: TerrainMisty, TerrainTurns: terrainDuration}
+
+	switchPlayer2(t, b, "growl")
+
+	if bench.Status != StatusNone {
+		t.Errorf("expected misty terrain to prevent poison, got %q", bench.Status)
+	}
+}
+
+func TestMistyTerrain_DoesNotProtectFlyingTypes(t *testing.T) {
+	attacker := newTestCreature(t, "attacker", []Type{TypeGhost}, 100, "confuse-ray")
+	target := newTestCreature(t, "target", []Type{TypeFlying}, 50, "growl")
+	b := newTestBattle(&scriptedRNG{values: []int{0}, fallback: 15}, attacker, target)
+	b.Field = Field{Terrain: TerrainMisty, TerrainTurns: terrainDuration}
+
+	resolveTurn(t, b, "confuse-ray", "growl")
+
+	if !target.Volatiles.Has(VolatileConfusion) {
+		t.Error("expected airborne target to be confused")
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+// switchPriority places switches and bag items above every move priority bracket (moves range from -7 to +5)
+const switchPriority = 6
+
+// actionOrder returns side indices in the order their pending actions resolve.
+// Switches and bag items go first, then moves by priority bracket, then by effective speed.
+func (b *Battle) actionOrder() []int {
+	p0, p1 := b.actionPriority(0), b.actionPriority(1)
+	switch {
+	case p0 > p1:
+		return []int{0, 1}
+	case p1 > p0:
+		return []int{1, 0}
+	default:
+		return b.speedOrder()
+	}
+}
+
+// actionPriority returns the priority bracket of a side's pending action
+func (b *Battle) actionPriority(sideIdx int) int {
+	action := b.pending[sideIdx]
+	if action.Kind == ActionKindSwitch || action.Kind == ActionKindItem {
+		return switchPriority
+	}
+	move, ok := b.Sides[sideIdx].Active().FindMove(action.MoveID)
+	if !ok {
+		return 0
+	}
+	return move.Priority
+}
+
+// speedOrder returns side indices fastest first, breaking speed ties with the battle RNG
+func (b *Battle) speedOrder() []int {
+	s0 := b.Sides[0].Active().EffectiveStat(StatSpeed)
+	s1 := b.Sides[1].Active().EffectiveStat(StatSpeed)
+	switch {
+	case s0 > s1:
+		return []int{0, 1}
+	case s1 > s0:
+		return []int{1, 0}
+	case b.rng.Intn(2) == 0:
+		return []int{0, 1}
+	default:
+		return []int{1, 0}
+	}
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Priority Tests
+// ========================================
+
+func TestActionOrder_HigherPriorityMovesFirst(t *testing.T) {
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "quick-attack")
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, slow, fast)
+
+	events := resolveTurn(t, b, "quick-attack", "tackle")
+
+	used := findEvents(events, EventMoveUsed)
+	if used[0].Actor != "player-1" || used[0].Order != 1 {
+		t.Errorf("expected priority move to resolve first with order 1, got %+v", used[0])
+	}
+}
+
+func TestActionOrder_SamePriorityFallsBackToSpeed(t *testing.T) {
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "quick-attack")
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "quick-attack")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, slow, fast)
+
+	events := resolveTurn(t, b, "quick-attack", "quick-attack")
+
+	used := findEvents(events, EventMoveUsed)
+	if used[0].Actor != "player-2" {
+		t.Errorf("expected faster creature to win the priority tie, got %s", used[0].Actor)
+	}
+}
+
+func TestActionOrder_SwitchBeforeMoves(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "fake-out")
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	bench := newTestCreature(t, "bench", []Type{TypeNormal}, 10, "tackle")
+	b := NewBattle("battle-1",
+		NewBattleSide("player-1", []*Creature{fast}),
+		NewBattleSide("player-2", []*Creature{slow, bench}),
+		&scriptedRNG{fallback: 15})
+
+	b.SubmitAction("player-1", Action{Kind: ActionKindMove, MoveID: "fake-out"})
+	b.SubmitAction("player-2", Action{Kind: ActionKindSwitch, SwitchSlot: 1})
+	events, err := b.ResolveTurn()
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+
+	if events[0].Type != EventCreatureSwitched || events[0].Actor != "player-2" {
+		t.Fatalf("expected switch to resolve first, got %+v", events[0])
+	}
+	if events[0].FromSlot != 0 || events[0].ToSlot != 1 || events[0].Target != "bench" {
+		t.Errorf("unexpected switch event data: %+v", events[0])
+	}
+	damage := findEvents(events, EventDamageDealt)
+	if len(damage) != 1 || damage[0].Target != "bench" {
+		t.Errorf("expected the incoming creature to take the hit, got %+v", damage)
+	}
+}
+
+// ========================================
+// Speed Tie Tests
+// ========================================
+
+func TestSpeedOrder_TieUsesRNG(t *testing.T) {
+	tests := []struct {
+		roll     int
+		expected string
+	}{
+		{0, "player-1"},
+		{1, "player-2"},
+	}
+
+	for _, tt := range tests {
+		a := newTestCreature(t, "a", []Type{TypeNormal}, 80, "growl")
+		c := newTestCreature(t, "c", []Type{TypeNormal}, 80, "growl")
+		b := newTestBattle(&scriptedRNG{values: []int{tt.roll}, fallback: 15}, a, c)
+
+		events := resolveTurn(t, b, "growl", "growl")
+
+		used := findEvents(events, EventMoveUsed)
+		if used[0].Actor != tt.expected {
+			t.Errorf("roll %d: expected %s first, got %s", tt.roll, tt.expected, used[0].Actor)
+		}
+	}
+}
+
+// ========================================
+// Switch Submission Tests
+// ========================================
+
+func TestSubmitAction_InvalidSwitchSlot(t *testing.T) {
+	b := newTestBattle(&scriptedRNG{}, newTestCreature(t, "a", []Type{TypeNormal}, 50, "tackle"), newTestCreature(t, "b", []Type{TypeNormal}, 50, "tackle"))
+
+	err := b.SubmitAction("player-1", Action{Kind: ActionKindSwitch, SwitchSlot: 3})
+	if !errors.Is(err, ErrInvalidSwitchTarget) {
+		t.Errorf("expected ErrInvalidSwitchTarget, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+// Type represents an elemental creature or move type
+type Type string
+
+const (
+	TypeNone     Type = ""
+	TypeNormal   Type = "normal"
+	TypeFire     Type = "fire"
+	TypeWater    Type = "water"
+	TypeElectric Type = "electric"
+	TypeGrass    Type = "grass"
+	TypeIce      Type = "ice"
+	TypeFighting Type = "fighting"
+	TypePoison   Type = "poison"
+	TypeGround   Type = "ground"
+	TypeFlying   Type = "flying"
+	TypePsychic  Type = "psychic"
+	TypeBug      Type = "bug"
+	TypeRock     Type = "rock"
+	TypeGhost    Type = "ghost"
+	TypeDragon   Type = "dragon"
+	TypeDark     Type = "dark"
+	TypeSteel    Type = "steel"
+	TypeFairy    Type = "fairy"
+)
+
+// typeChart lists every non-neutral attacking matchup in data version 1: attacker -> defender -> multiplier.
+// Matchups not listed are neutral (1x).
+var typeChart = map[Type]map[Type]float64{
+	TypeNormal:   {TypeRock: 0.5, TypeGhost: 0, TypeSteel: 0.5},
+	TypeFire:     {TypeFire: 0.5, TypeWater: 0.5, TypeGrass: 2, TypeIce: 2, TypeBug: 2, TypeRock: 0.5, TypeDragon: 0.5, TypeSteel: 2},
+	TypeWater:    {TypeFire: 2, TypeWater: 0.5, TypeGrass: 0.5, TypeGround: 2, TypeRock: 2, TypeDragon: 0.5},
+	TypeElectric: {TypeWater: 2, TypeElectric: 0.5, TypeGrass: 0.5, TypeGround: 0, TypeFlying: 2, TypeDragon: 0.5},
+	TypeGrass:    {TypeFire: 0.5, TypeWater: 2, TypeGrass: 0.5, TypePoison: 0.5, TypeGround: 2, TypeFlying: 0.5, TypeBug: 0.5, TypeRock: 2, TypeDragon: 0.5, TypeSteel: 0.5},
+	TypeIce:      {TypeFire: 0.5, TypeWater: 0.5, TypeGrass: 2, TypeIce: 0.5, TypeGround: 2, TypeFlying: 2, TypeDragon: 2, TypeSteel: 0.5},
+	TypeFighting: {TypeNormal: 2, TypeIce: 2, TypePoison: 0.5, TypeFlying: 0.5, TypePsychic: 0.5, TypeBug: 0.5, TypeRock: 2, TypeGhost: 0, TypeDark: 2, TypeSteel: 2, TypeFairy: 0.5},
+	TypePoison:   {TypeGrass: 2, TypePoison: 0.5, TypeGround: 0.5, TypeRock: 0.5, TypeGhost: 0.5, TypeSteel: 0, TypeFairy: 2},
+	TypeGround:   {TypeFire: 2, TypeElectric: 2, TypeGrass: 0.5, TypePoison: 2, TypeFlying: 0, TypeBug: 0.5, TypeRock: 2, TypeSteel: 2},
+	TypeFlying:   {TypeElectric: 0.5, TypeGrass: 2, TypeFighting: 2, TypeBug: 2, TypeRock: 0.5, TypeSteel: 0.5},
+	TypePsychic:  {TypeFighting: 2, TypePoison: 2, TypePsychic: 0.5, TypeDark: 0, TypeSteel: 0.5},
+	TypeBug:      {TypeFire: 0.5, TypeGrass: 2, TypeFighting: 0.5, TypePoison: 0.5, TypeFlying: 0.5, TypePsychic: 2, TypeGhost: 0.5, TypeDark: 2, TypeSteel: 0.5, TypeFairy: 0.5},
+	TypeRock:     {TypeFire: 2, TypeIce: 2, TypeFighting: 0.5, TypeGround: 0.5, TypeFlying: 2, TypeBug: 2, TypeSteel: 0.5},
+	TypeGhost:    {TypeNormal: 0, TypePsychic: 2, TypeGhost: 2, TypeDark: 0.5},
+	TypeDragon:   {TypeDragon: 2, TypeSteel: 0.5, TypeFairy: 0},
+	TypeDark:     {TypeFighting: 0.5, TypePsychic: 2, TypeGhost: 2, TypeDark: 0.5, TypeFairy: 0.5},
+	TypeSteel:    {TypeFire: 0.5, TypeWater: 0.5, TypeElectric: 0.5, TypeIce: 2, TypeRock: 2, TypeSteel: 0.5, TypeFairy: 2},
+	TypeFairy:    {TypeFire: 0.5, TypeFighting: 2, TypePoison: 0.5, TypeDragon: 2, TypeDark: 2, TypeSteel: 0.5},
+}
+
+// inverseTypeChart reverses every listed matchup for inverse battles:
+// super effective matchups become resisted, and resisted or immune ones become super effective
+func inverseTypeChart(chart map[Type]map[Type]float64) map[Type]map[Type]float64 {
+	inverse := make(map[Type]map[Type]float64, len(chart))
+	for attack, matchups := range chart {
+		inverse[attack] = make(map[Type]float64, len(matchups))
+		for def, m := range matchups {
+			if m > 1 {
+				inverse[attack][def] = 0.5
+			} else {
+				inverse[attack][def] = 2
+			}
+		}
+	}
+	return inverse
+}
+
+// Effectiveness returns the damage multiplier of an attacking type against a set of defending types.
+// It uses the current data's type chart.
+func Effectiveness(attack Type, defending []Type) float64 {
+	return CurrentDataPack().Effectiveness(attack, defending)
+}
+
+// EffectivenessLabel classifies a multiplier the way turn events report it
+func EffectivenessLabel(multiplier float64) string {
+	switch {
+	case multiplier == 0:
+		return "no_effect"
+	case multiplier > 1:
+		return "super_effective"
+	case multiplier < 1:
+		return "not_very_effective"
+	default:
+		return "normal"
+	}
+}
@@ -0,0 +1,126 @@
+package game
+
+// VolatileStatus is a temporary condition that ends when the creature leaves the field
+type VolatileStatus string
+
+const (
+	VolatileNone      VolatileStatus = ""
+	VolatileConfusion VolatileStatus = "confusion"
+	VolatileFlinch    VolatileStatus = "flinch"
+	VolatileLeechSeed VolatileStatus = "leech_seed"
+)
+
+// Volatile status configuration
+const (
+	confusionMinTurns      = 2
+	confusionMaxTurns      = 5
+	confusionSelfHitChance = 33 // Percent chance a confused creature hits itself
+	confusionSelfHitPower  = 40 // Typeless physical attack against itself
+	leechSeedDrainDivisor  = 8  // Drains 1/8 of max HP each turn
+)
+
+// Volatiles tracks a creature's volatile conditions
+type Volatiles struct {
+	ConfusionTurns int  // Turns of confusion remaining; 0 means not confused
+	Flinched       bool // Set for the remainder of the current turn only
+	LeechSeeded    bool
+}
+
+// Has checks if the given volatile status is active
+func (v *Volatiles) Has(status VolatileStatus) bool {
+	switch status {
+	case VolatileConfusion:
+		return v.ConfusionTurns > 0
+	case VolatileFlinch:
+		return v.Flinched
+	case VolatileLeechSeed:
+		return v.LeechSeeded
+	default:
+		return false
+	}
+}
+
+// Clear removes all volatile conditions
+func (v *Volatiles) Clear() {
+	*v = Volatiles{}
+}
+
+// applyVolatile inflicts a volatile status on the target, emitting the outcome
+func (b *Battle) applyVolatile(actor string, target *Creature, move *Move) {
+	if target.Volatiles.Has(move.Volatile) {
+		b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonAlreadyAffected})
+		return
+	}
+
+	switch move.Volatile {
+	case VolatileConfusion:
+		if b.Field.blocksStatus(target) {
+			b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonTerrain})
+			return
+		}
+		target.Volatiles.ConfusionTurns = confusionMinTurns + b.rng.Intn(confusionMaxTurns-confusionMinTurns+1)
+	case VolatileLeechSeed:
+		if target.HasType(TypeGrass) {
+			b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, MoveID: move.ID, Reason: FailReasonImmune})
+			return
+		}
+		target.Volatiles.LeechSeeded = true
+	default:
+		return
+	}
+
+	b.emit(BattleEvent{Type: EventStatusApplied, Actor: actor, Target: target.ID, Status: string(move.Volatile)})
+}
+
+// checkFlinch reports whether a flinched creature loses its action this turn
+func (b *Battle) checkFlinch(actor string, creature *Creature, move *Move) bool {
+	if !creature.Volatiles.Flinched {
+		return false
+	}
+	b.emit(BattleEvent{Type: EventMoveFailed, Actor: actor, Target: creature.ID, MoveID: move.ID, Reason: FailReasonFlinched})
+	return true
+}
+
+// checkConfusion counts down confusion and rolls for a self-hit.
+// It returns true if the creature hurt itself and loses its action.
+func (b *Battle) checkConfusion(actor string, creature *Creature) bool {
+	if creature.Volatiles.ConfusionTurns == 0 {
+		return false
+	}
+
+	creature.Volatiles.ConfusionTurns--
+	if creature.Volatiles.ConfusionTurns == 0 {
+		b.emit(BattleEvent{Type: EventStatusEnded, Actor: actor, Target: creature.ID, Status: string(VolatileConfusion)})
+		return false
+	}
+
+	if b.rng.Intn(100) >= confusionSelfHitChance {
+		return false
+	}
+
+	base := baseDamage(creature.Level, confusionSelfHitPower, creature.EffectiveStat(StatAttack), creature.EffectiveStat(StatDefense))
+	damage := creature.TakeDamage(max(base*rollDamage(b.rng)/100, 1))
+	b.emit(BattleEvent{Type: EventConfusionSelfHit, Actor: actor, Target: creature.ID, Damage: damage, Reason: FailReasonConfused})
+	b.checkFainted(actor, creature)
+	return true
+}
+
+// drainLeechSeed saps HP from a seeded creature and heals the opposing active creature
+func (b *Battle) drainLeechSeed(sideIdx int) {
+	side := b.Sides[sideIdx]
+	seeded := side.Active()
+	if seeded.IsFainted() || !seeded.Volatiles.LeechSeeded {
+		return
+	}
+
+	damage := seeded.TakeDamage(max(seeded.MaxHP()/leechSeedDrainDivisor, 1))
+	event := BattleEvent{Type: EventLeechSeedDrain, Actor: side.PlayerID, Target: seeded.ID, Damage: damage}
+
+	if recipient := b.Sides[1-sideIdx].Active(); !recipient.IsFainted() {
+		event.Recipient = recipient.ID
+		event.Healed = recipient.Heal(damage)
+	}
+
+	b.emit(event)
+	b.checkFainted(side.PlayerID, seeded)
+}
@@ -0,0 +1,234 @@
+package game
+
+import "testing"
+
+// ========================================
+// Confusion Tests
+// ========================================
+
+func TestConfuseRay_AppliesConfusion(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeGhost}, 100, "confuse-ray")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "tackle")
+	// Confusion duration roll 1 -> 3 turns; target's own confusion check avoids a self-hit
+	b := newTestBattle(&scriptedRNG{values: []int{1, 99}, fallback: 15}, user, target)
+
+	events := resolveTurn(t, b, "confuse-ray", "tackle")
+
+	applied := findEvents(events, EventStatusApplied)
+	if len(applied) != 1 || applied[0].Status != string(VolatileConfusion) {
+		t.Fatalf("expected confusion status_applied event, got %+v", applied)
+	}
+	if applied[0].Target != "target" {
+		t.Errorf("expected confusion on target, got %s", applied[0].Target)
+	}
+	if target.Volatiles.ConfusionTurns != 2 {
+		t.Errorf("expected 2 confusion turns remaining after first check, got %d", target.Volatiles.ConfusionTurns)
+	}
+}
+
+func TestConfusion_SelfHitSkipsMove(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	confused := newTestCreature(t, "confused", []Type{TypeNormal}, 50, "tackle")
+	confused.Volatiles.ConfusionTurns = 3
+	// user's crit and damage rolls, then confused creature's self-hit roll (0 < 33) and damage roll
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0, 15}, fallback: 15}, user, confused)
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	selfHits := findEvents(events, EventConfusionSelfHit)
+	if len(selfHits) != 1 {
+		t.Fatalf("expected 1 confusion_self_hit event, got %d", len(selfHits))
+	}
+	if selfHits[0].Target != "confused" || selfHits[0].Damage <= 0 {
+		t.Errorf("expected self-hit damage on confused creature, got %+v", selfHits[0])
+	}
+	for _, e := range findEvents(events, EventMoveUsed) {
+		if e.Actor == "player-2" {
+			t.Error("expected confused creature to lose its move after self-hit")
+		}
+	}
+}
+
+func TestConfusion_NoSelfHitStillMoves(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	confused := newTestCreature(t, "confused", []Type{TypeNormal}, 50, "tackle")
+	confused.Volatiles.ConfusionTurns = 3
+	// user's crit and damage rolls, then confused creature's self-hit roll (50 >= 33)
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 50}, fallback: 15}, user, confused)
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	if len(findEvents(events, EventConfusionSelfHit)) != 0 {
+		t.Error("expected no self-hit")
+	}
+	if len(findEvents(events, EventMoveUsed)) != 2 {
+		t.Error("expected both creatures to move")
+	}
+}
+
+func TestConfusion_WearsOff(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeNormal}, 100, "tackle")
+	confused := newTestCreature(t, "confused", []Type{TypeNormal}, 50, "tackle")
+	confused.Volatiles.ConfusionTurns = 1
+	b := newTestBattle(&scriptedRNG{fallback: 0}, user, confused)
+
+	events := resolveTurn(t, b, "tackle", "tackle")
+
+	ended := findEvents(events, EventStatusEnded)
+	if len(ended) != 1 || ended[0].Status != string(VolatileConfusion) {
+		t.Fatalf("expected confusion status_ended event, got %+v", ended)
+	}
+	if len(findEvents(events, EventConfusionSelfHit)) != 0 {
+		t.Error("expected no self-hit on the turn confusion ends")
+	}
+	if confused.Volatiles.Has(VolatileConfusion) {
+		t.Error("expected confusion to be cleared")
+	}
+}
+
+func TestConfusion_AlreadyConfusedFails(t *testing.T) {
+	user := newTestCreature(t, "user", []Type{TypeGhost}, 100, "confuse-ray")
+	target := newTestCreature(t, "target", []Type{TypeNormal}, 50, "tackle")
+	target.Volatiles.ConfusionTurns = 4
+	b := newTestBattle(&scriptedRNG{fallback: 99}, user, target)
+
+	events := resolveTurn(t, b, "confuse-ray", "tackle")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonAlreadyAffected {
+		t.Errorf("expected already_affected failure, got %+v", failed)
+	}
+}
+
+// ========================================
+// Flinch Tests
+// ========================================
+
+func TestFlinch_FasterAttackerPreventsMove(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeSteel}, 100, "iron-head")
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	// crit and damage rolls, then flinch roll 0 < 30
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 0}, fallback: 15}, fast, slow)
+
+	events := resolveTurn(t, b, "iron-head", "tackle")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonFlinched || failed[0].Actor != "player-2" {
+		t.Fatalf("expected player-2 to flinch, got %+v", failed)
+	}
+	if len(findEvents(events, EventMoveUsed)) != 1 {
+		t.Error("expected only the faster creature to use a move")
+	}
+	if slow.Volatiles.Flinched {
+		t.Error("expected flinch to be cleared at end of turn")
+	}
+}
+
+func TestFlinch_SlowerAttackerCannotFlinch(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeNormal}, 100, "tackle")
+	slow := newTestCreature(t, "slow", []Type{TypeSteel}, 50, "iron-head")
+	b := newTestBattle(&scriptedRNG{fallback: 0}, fast, slow)
+
+	events := resolveTurn(t, b, "tackle", "iron-head")
+
+	if len(findEvents(events, EventMoveFailed)) != 0 {
+		t.Error("expected no flinch when the target already moved")
+	}
+	if fast.Volatiles.Flinched {
+		t.Error("expected flinch not to be set on a creature that already acted")
+	}
+}
+
+func TestFlinch_ChanceRollMisses(t *testing.T) {
+	fast := newTestCreature(t, "fast", []Type{TypeSteel}, 100, "iron-head")
+	slow := newTestCreature(t, "slow", []Type{TypeNormal}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{values: []int{15, 15, 30}, fallback: 15}, fast, slow)
+
+	events := resolveTurn(t, b, "iron-head", "tackle")
+
+	if len(findEvents(events, EventMoveUsed)) != 2 {
+		t.Error("expected both creatures to move when flinch roll fails")
+	}
+}
+
+// ========================================
+// Leech Seed Tests
+// ========================================
+
+func TestLeechSeed_DrainsAtEndOfTurn(t *testing.T) {
+	seeder := newTestCreature(t, "seeder", []Type{TypeGrass}, 100, "leech-seed")
+	seeded := newTestCreature(t, "seeded", []Type{TypeNormal}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, seeder, seeded)
+
+	events := resolveTurn(t, b, "leech-seed", "tackle")
+
+	drains := findEvents(events, EventLeechSeedDrain)
+	if len(drains) != 1 {
+		t.Fatalf("expected 1 leech_seed_drain event, got %d", len(drains))
+	}
+	drain := drains[0]
+	if drain.Target != "seeded" || drain.Damage != seeded.MaxHP()/leechSeedDrainDivisor {
+		t.Errorf("expected 1/8 max HP drained from seeded, got %+v", drain)
+	}
+	if drain.Recipient != "seeder" || drain.Healed != drain.Damage {
+		t.Errorf("expected seeder healed by drained amount, got %+v", drain)
+	}
+	if drain.Order != len(events) {
+		t.Error("expected leech seed drain to be the last event of the turn")
+	}
+}
+
+func TestLeechSeed_GrassTypesImmune(t *testing.T) {
+	seeder := newTestCreature(t, "seeder", []Type{TypeGrass}, 100, "leech-seed")
+	target := newTestCreature(t, "target", []Type{TypeGrass}, 50, "tackle")
+	b := newTestBattle(&scriptedRNG{fallback: 15}, seeder, target)
+
+	events := resolveTurn(t, b, "leech-seed", "tackle")
+
+	failed := findEvents(events, EventMoveFailed)
+	if len(failed) != 1 || failed[0].Reason != FailReasonImmune {
+		t.Errorf("expected immune failure, got %+v", failed)
+	}
+	if target.Volatiles.LeechSeeded {
+		t.Error("expected grass type not to be seeded")
+	}
+}
+
+func TestLeechSeed_CanFaintSeededCreature(t *testing.T) {
+	seeder := newTestCreature(t, "seeder", []Type{TypeGrass}, 100, "leech-seed")
+	seeded := newTestCreature(t, "seeded", []Type{TypeNormal}, 50, "confuse-ray")
+	seeded.Volatiles.LeechSeeded = true
+	seeded.CurrentHP = 1
+	b := newTestBattle(&scriptedRNG{fallback: 99}, seeder, seeded)
+
+	events := resolveTurn(t, b, "leech-seed", "confuse-ray")
+
+	fainted := findEvents(events, EventCreatureFainted)
+	if len(fainted) != 1 || fainted[0].Target != "seeded" {
+		t.Errorf("expected seeded creature to faint from drain, got %+v", fainted)
+	}
+}
+
+// ========================================
+// Volatiles Tests
+// ========================================
+
+func TestVolatiles_HasAndClear(t *testing.T) {
+	v := Volatiles{ConfusionTurns: 2, Flinched: true, LeechSeeded: true}
+
+	for _, status := range []VolatileStatus{VolatileConfusion, VolatileFlinch, VolatileLeechSeed} {
+		if !v.Has(status) {
+			t.Errorf("expected %s to be active", status)
+		}
+	}
+	if v.Has(VolatileNone) {
+		t.Error("expected VolatileNone never to be active")
+	}
+
+	v.Clear()
+	for _, status := range []VolatileStatus{VolatileConfusion, VolatileFlinch, VolatileLeechSeed} {
+		if v.Has(status) {
+			t.Errorf("expected %s to be cleared", status)
+		}
+	}
+}
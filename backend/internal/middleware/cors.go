@@ -7,11 +7,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// DefaultAllowedOrigins are the browser origins allowed when none are configured: the frontend's dev server
+var DefaultAllowedOrigins = []string{"http://localhost:5173"}
+
+// CORS allows cross-origin requests from the allowed origins, which may use a "*" wildcard
+// for subdomains such as "https://*.example.com"
+func CORS(allowedOrigins []string) gin.HandlerFunc {
 	return cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
-		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
 		AllowHeaders:     []string{"Origin", "Content-Type"},
 		AllowCredentials: true,
+		AllowWildcard:    true,
 	})
 }
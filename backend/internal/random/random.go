@@ -0,0 +1,18 @@
+// Package random draws the random strings that IDs and salts are made from
+package random
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// Hex returns n bytes from crypto/rand, hex encoded. There is deliberately no fallback if crypto/rand fails:
+// a value drawn from the clock instead could be guessed.
+func Hex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("reading %d random bytes: %w", n, err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,31 @@
+package random
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// ========================================
+// Hex Tests
+// ========================================
+
+func TestHex(t *testing.T) {
+	first, err := Hex(8)
+	if err != nil {
+		t.Fatalf("failed to draw: %v", err)
+	}
+	second, err := Hex(8)
+	if err != nil {
+		t.Fatalf("failed to draw: %v", err)
+	}
+
+	if len(first) != 16 {
+		t.Errorf("expected 16 hex digits for 8 bytes, got %q", first)
+	}
+	if _, err := hex.DecodeString(first); err != nil {
+		t.Errorf("expected hex, got %q: %v", first, err)
+	}
+	if first == second {
+		t.Errorf("expected two draws to differ, got %q twice", first)
+	}
+}
@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/random"
+)
+
+// replayIDBytes is the number of random bytes in a replay ID
+const replayIDBytes = 8
+
+// Recorder accumulates the replay of a single battle while it is in progress.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	replay *Replay
+}
+
+// NewRecorder starts recording a battle, capturing its seed and both teams before turn 1
+func NewRecorder(battle *game.Battle) (*Recorder, error) {
+	id, err := newReplayID(battle.ID)
+	if err != nil {
+		return nil, err
+	}
+	players := make([]Player, len(battle.Sides))
+	for i, side := range battle.Sides {
+		players[i] = newPlayer(side)
+	}
+
+	return &Recorder{
+		replay: &Replay{
+			Version:     FormatVersion,
+			ID:          id,
+			BattleID:    battle.ID,
+			Seed:        battle.Seed,
+			DataVersion: battle.Data.Version,
+			TypeChart:   newTypeChart(battle.Data),
+			Players:     players,
+			Turns:       []Turn{},
+			StartedAt:   time.Now(),
+		},
+	}, nil
+}
+
+// ID returns the ID the replay will be stored under
+func (r *Recorder) ID() string {
+	return r.replay.ID
+}
+
+// RecordAction records an accepted action for a turn
+func (r *Recorder) RecordAction(turn int, action Action) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := r.turn(turn)
+	t.Actions = append(t.Actions, action)
+}
+
+// RecordEvents records the events produced while resolving a turn
+func (r *Recorder) RecordEvents(turn int, events []game.BattleEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := r.turn(turn)
+	for _, e := range events {
+		t.Events = append(t.Events, newEvent(e))
+	}
+}
+
+// Finish records the outcome and returns the completed replay
+func (r *Recorder) Finish(outcome *game.BattleOutcome) *Replay {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.replay.Outcome = &Outcome{
+		WinnerID: outcome.WinnerID,
+		LoserID:  outcome.LoserID,
+		Reason:   string(outcome.Reason),
+	}
+	r.replay.EndedAt = time.Now()
+	return r.replay
+}
+
+// turn returns the entry for a turn number, appending it if this is the first record for that turn
+func (r *Recorder) turn(number int) *Turn {
+	turns := r.replay.Turns
+	for i := len(turns) - 1; i >= 0; i-- {
+		if turns[i].Number == number {
+			return &turns[i]
+		}
+	}
+	r.replay.Turns = append(turns, Turn{Number: number, Actions: []Action{}, Events: []Event{}})
+	return &r.replay.Turns[len(r.replay.Turns)-1]
+}
+
+// newReplayID creates a unique ID for a battle's replay.
+// Battle IDs are reused across rematches, so a random suffix keeps replays apart.
+func newReplayID(battleID string) (string, error) {
+	suffix, err := random.Hex(replayIDBytes)
+	if err != nil {
+		return "", fmt.Errorf("drawing replay ID: %w", err)
+	}
+	return battleID + "-" + suffix, nil
+}
@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"testing"
+
+	"poke-battles/internal/game"
+)
+
+// newTestBattle creates a seeded battle between two starter teams
+func newTestBattle(t *testing.T) *game.Battle {
+	t.Helper()
+	team1, err := game.NewStarterTeam("player-1")
+	if err != nil {
+		t.Fatalf("failed to build team: %v", err)
+	}
+	team2, err := game.NewStarterTeam("player-2")
+	if err != nil {
+		t.Fatalf("failed to build team: %v", err)
+	}
+	b, err := game.NewSeededBattle("ABC123", game.NewBattleSide("player-1", team1), game.NewBattleSide("player-2", team2), 42)
+	if err != nil {
+		t.Fatalf("failed to create battle: %v", err)
+	}
+	return b
+}
+
+// newTestRecorder starts recording a battle
+func newTestRecorder(t *testing.T, battle *game.Battle) *Recorder {
+	t.Helper()
+	rec, err := NewRecorder(battle)
+	if err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+	return rec
+}
+
+// ========================================
+// Recorder Tests
+// ========================================
+
+func TestNewRecorder_CapturesBattleSetup(t *testing.T) {
+	battle := newTestBattle(t)
+
+	doc := newTestRecorder(t, battle).Finish(&game.BattleOutcome{WinnerID: "player-1", LoserID: "player-2", Reason: game.EndReasonForfeit})
+
+	if doc.Version != FormatVersion || doc.BattleID != "ABC123" || doc.Seed != 42 || doc.DataVersion != game.CurrentDataVersion {
+		t.Errorf("unexpected replay header: %+v", doc)
+	}
+	if len(doc.Players) != 2 || doc.Players[0].ID != "player-1" || len(doc.Players[0].Team) != len(battle.Sides[0].Team) {
+		t.Fatalf("expected both teams to be recorded, got %+v", doc.Players)
+	}
+	lead := doc.Players[0].Team[0]
+	if lead.SpeciesID != "venusaur" || len(lead.Moves) != 4 || lead.Moves[0] != "razor-leaf" {
+		t.Errorf("unexpected lead creature: %+v", lead)
+	}
+	if doc.Outcome == nil || doc.Outcome.Reason != string(game.EndReasonForfeit) {
+		t.Errorf("expected forfeit outcome, got %+v", doc.Outcome)
+	}
+}
+
+func TestNewRecorder_RecordsRulesetTypeChart(t *testing.T) {
+	battle := newTestBattle(t)
+	if doc := newTestRecorder(t, battle).Finish(&game.BattleOutcome{WinnerID: "player-1", LoserID: "player-2", Reason: game.EndReasonForfeit}); doc.TypeChart != nil {
+		t.Errorf("expected no type chart when the data pack's is used, got %v", doc.TypeChart)
+	}
+
+	inverse, _ := game.LookupRuleset("inverse")
+	battle.Data = inverse.DataPack(battle.Data)
+	doc := newTestRecorder(t, battle).Finish(&game.BattleOutcome{WinnerID: "player-1", LoserID: "player-2", Reason: game.EndReasonForfeit})
+
+	if doc.TypeChart["normal"]["ghost"] != 2 {
+		t.Errorf("expected the inverse type chart to be recorded, got %v", doc.TypeChart)
+	}
+}
+
+func TestRecorder_GroupsActionsAndEventsByTurn(t *testing.T) {
+	rec := newTestRecorder(t, newTestBattle(t))
+
+	rec.RecordAction(1, Action{PlayerID: "player-1", Kind: ActionKindMove, MoveID: "razor-leaf"})
+	rec.RecordAction(1, Action{PlayerID: "player-2", Kind: ActionKindSwitch, Slot: 1})
+	rec.RecordEvents(1, []game.BattleEvent{
+		{Order: 1, Type: game.EventCreatureSwitched, Actor: "player-2", FromSlot: 0, ToSlot: 1},
+		{Order: 2, Type: game.EventMoveUsed, Actor: "player-1", MoveID: "razor-leaf"},
+	})
+	rec.RecordAction(1, Action{PlayerID: "player-2", Kind: ActionKindForcedSwitch, Slot: 2})
+	rec.RecordAction(2, Action{PlayerID: "player-1", Kind: ActionKindForfeit})
+
+	doc := rec.Finish(&game.BattleOutcome{WinnerID: "player-2", LoserID: "player-1", Reason: game.EndReasonForfeit})
+
+	if len(doc.Turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(doc.Turns))
+	}
+	if len(doc.Turns[0].Actions) != 3 || len(doc.Turns[0].Events) != 2 {
+		t.Errorf("expected turn 1 to hold 3 actions and 2 events, got %+v", doc.Turns[0])
+	}
+	if doc.Turns[0].Events[0].Type != string(game.EventCreatureSwitched) || doc.Turns[0].Events[0].ToSlot != 1 {
+		t.Errorf("unexpected first event: %+v", doc.Turns[0].Events[0])
+	}
+	if doc.Turns[1].Number != 2 || doc.Turns[1].Actions[0].Kind != ActionKindForfeit {
+		t.Errorf("unexpected turn 2: %+v", doc.Turns[1])
+	}
+}
+
+func TestNewRecorder_UniqueIDs(t *testing.T) {
+	battle := newTestBattle(t)
+
+	if newTestRecorder(t, battle).ID() == newTestRecorder(t, battle).ID() {
+		t.Error("expected each recording of the same battle to get its own replay ID")
+	}
+}
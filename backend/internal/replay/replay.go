@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"time"
+
+	"poke-battles/internal/game"
+)
+
+// FormatVersion is the version of the replay document format.
+// Bump it whenever a change would stop older readers from re-simulating a replay.
+const FormatVersion = 1
+
+// ActionKind identifies a recorded player action
+type ActionKind string
+
+const (
+	ActionKindMove         ActionKind = "move"
+	ActionKindSwitch       ActionKind = "switch"
+	ActionKindForcedSwitch ActionKind = "forced_switch"
+	ActionKindForfeit      ActionKind = "forfeit"
+	ActionKindChooseLead   ActionKind = "choose_lead"
+	ActionKindItem         ActionKind = "item"
+	ActionKindOfferDraw    ActionKind = "offer_draw"
+	ActionKindAcceptDraw   ActionKind = "accept_draw"
+	ActionKindDeclineDraw  ActionKind = "decline_draw"
+	ActionKindDisconnect   ActionKind = "disconnect" // The player stayed disconnected past the grace period and lost
+)
+
+// Replay is the versioned record of a complete battle.
+// Together with the seed, data version and teams, the recorded actions are enough to re-simulate the battle.
+type Replay struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id"`
+	BattleID    string    `json:"battle_id"`
+	Seed        int64     `json:"seed"`
+	DataVersion string    `json:"data_version"` // Game data pack the battle was played with, see game.LookupDataPack
+	TypeChart   TypeChart `json:"type_chart,omitempty"`
+	Players     []Player  `json:"players"`
+	Turns       []Turn    `json:"turns"`
+	Outcome     *Outcome  `json:"outcome,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+}
+
+// TypeChart is the type chart of a ruleset that replaced or amended the data pack's chart.
+// It holds every matchup the battle resolved against: attacker -> defender -> multiplier, unlisted matchups neutral.
+type TypeChart map[string]map[string]float64
+
+// newTypeChart records the battle's type chart if a ruleset changed it
+func newTypeChart(pack *game.DataPack) TypeChart {
+	if pack.Ruleset == "" {
+		return nil
+	}
+	chart := make(TypeChart, len(pack.TypeChart))
+	for attack, matchups := range pack.TypeChart {
+		chart[string(attack)] = make(map[string]float64, len(matchups))
+		for def, m := range matchups {
+			chart[string(attack)][string(def)] = m
+		}
+	}
+	return chart
+}
+
+// Player is a battle participant and the team they brought
+type Player struct {
+	ID   string         `json:"id"`
+	Team []Creature     `json:"team"`
+	Bag  map[string]int `json:"bag,omitempty"` // Bag items held at the start of the battle
+}
+
+// Creature is a team member as it entered the battle
+type Creature struct {
+	ID        string   `json:"id"`
+	SpeciesID string   `json:"species_id"`
+	Nickname  string   `json:"nickname,omitempty"`
+	Shiny     bool     `json:"shiny,omitempty"`
+	Level     int      `json:"level"`
+	Moves     []string `json:"moves"`
+}
+
+// Turn groups the actions accepted for a turn with the events they produced.
+// Forced switches belong to the turn in which the creature fainted,
+// and lead choices made during team preview belong to turn 1.
+type Turn struct {
+	Number  int      `json:"number"`
+	Actions []Action `json:"actions"`
+	Events  []Event  `json:"events"`
+}
+
+// Action is a single accepted player action
+type Action struct {
+	PlayerID string     `json:"player_id"`
+	Kind     ActionKind `json:"kind"`
+	MoveID   string     `json:"move_id,omitempty"`
+	ItemID   string     `json:"item_id,omitempty"`
+	Slot     int        `json:"slot,omitempty"`
+}
+
+// Event is a recorded battle event. Only the fields relevant to the event type are set.
+type Event struct {
+	Order         int    `json:"order"`
+	Type          string `json:"type"`
+	Actor         string `json:"actor,omitempty"`
+	Target        string `json:"target,omitempty"`
+	MoveID        string `json:"move_id,omitempty"`
+	Damage        int    `json:"damage,omitempty"`
+	Effectiveness string `json:"effectiveness,omitempty"`
+	Critical      bool   `json:"critical,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Stat          string `json:"stat,omitempty"`
+	Stages        int    `json:"stages,omitempty"`
+	Secondary     bool   `json:"secondary,omitempty"`
+	FromSlot      int    `json:"from_slot,omitempty"`
+	ToSlot        int    `json:"to_slot,omitempty"`
+	Recipient     string `json:"recipient,omitempty"`
+	Healed        int    `json:"healed,omitempty"`
+	Side          string `json:"side,omitempty"`
+	Hazard        string `json:"hazard,omitempty"`
+	Layers        int    `json:"layers,omitempty"`
+	Terrain       string `json:"terrain,omitempty"`
+	ItemID        string `json:"item_id,omitempty"`
+}
+
+// Outcome records how the battle ended
+type Outcome struct {
+	WinnerID string `json:"winner_id"`
+	LoserID  string `json:"loser_id"`
+	Reason   string `json:"reason"`
+}
+
+// newPlayer records a side's team as it stands at the start of the battle
+func newPlayer(side *game.BattleSide) Player {
+	team := make([]Creature, len(side.Team))
+	for i, c := range side.Team {
+		moves := make([]string, len(c.Moves))
+		for j, m := range c.Moves {
+			moves[j] = m.ID
+		}
+		team[i] = Creature{ID: c.ID, SpeciesID: c.SpeciesID, Nickname: c.Nickname, Shiny: c.Shiny, Level: c.Level, Moves: moves}
+	}
+	return Player{ID: side.PlayerID, Team: team, Bag: side.Bag.Clone()}
+}
+
+// newEvent converts a domain battle event to its recorded form
+func newEvent(e game.BattleEvent) Event {
+	return Event{
+		Order:         e.Order,
+		Type:          string(e.Type),
+		Actor:         e.Actor,
+		Target:        e.Target,
+		MoveID:        e.MoveID,
+		Damage:        e.Damage,
+		Effectiveness: e.Effectiveness,
+		Critical:      e.Critical,
+		Status:        e.Status,
+		Reason:        e.Reason,
+		Stat:          e.Stat,
+		Stages:        e.Stages,
+		Secondary:     e.Secondary,
+		FromSlot:      e.FromSlot,
+		ToSlot:        e.ToSlot,
+		Recipient:     e.Recipient,
+		Healed:        e.Healed,
+		Side:          e.Side,
+		Hazard:        e.Hazard,
+		Layers:        e.Layers,
+		Terrain:       e.Terrain,
+		ItemID:        e.ItemID,
+	}
+}
@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrReplayNotFound is returned when no replay is stored under an ID
+var ErrReplayNotFound = errors.New("replay not found")
+
+// Store persists finished replays. Implementations must be safe for concurrent use.
+type Store interface {
+	Save(replay *Replay) error
+	Get(id string) (*Replay, error)
+}
+
+// memoryStore implements Store by keeping encoded replay documents in memory
+type memoryStore struct {
+	mu      sync.RWMutex
+	replays map[string][]byte
+}
+
+// NewMemoryStore creates a store that keeps replays for the lifetime of the process
+func NewMemoryStore() Store {
+	return &memoryStore{
+		replays: make(map[string][]byte),
+	}
+}
+
+// Save encodes and stores a replay, replacing any replay with the same ID
+func (s *memoryStore) Save(replay *Replay) error {
+	doc, err := json.Marshal(replay)
+	if err != nil {
+		return fmt.Errorf("replay %q: %w", replay.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replays[replay.ID] = doc
+	return nil
+}
+
+// Get decodes the replay stored under an ID
+func (s *memoryStore) Get(id string) (*Replay, error) {
+	s.mu.RLock()
+	doc, exists := s.replays[id]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("replay %q: %w", id, ErrReplayNotFound)
+	}
+
+	var replay Replay
+	if err := json.Unmarshal(doc, &replay); err != nil {
+		return nil, fmt.Errorf("replay %q: %w", id, err)
+	}
+	return &replay, nil
+}
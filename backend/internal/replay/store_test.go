@@ -0,0 +1,55 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========================================
+// Memory Store Tests
+// ========================================
+
+func TestMemoryStore_SaveAndGet(t *testing.T) {
+	store := NewMemoryStore()
+	saved := &Replay{
+		Version:  FormatVersion,
+		ID:       "ABC123-1",
+		BattleID: "ABC123",
+		Seed:     7,
+		Turns:    []Turn{{Number: 1, Actions: []Action{{PlayerID: "player-1", Kind: ActionKindMove, MoveID: "tackle"}}}},
+	}
+
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	got, err := store.Get("ABC123-1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if got.Seed != 7 || len(got.Turns) != 1 || got.Turns[0].Actions[0].MoveID != "tackle" {
+		t.Errorf("unexpected replay: %+v", got)
+	}
+}
+
+func TestMemoryStore_ReturnsIndependentCopies(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save(&Replay{ID: "ABC123-1", Seed: 7})
+
+	got, _ := store.Get("ABC123-1")
+	got.Seed = 8
+
+	again, _ := store.Get("ABC123-1")
+	if again.Seed != 7 {
+		t.Error("expected stored replay to be unaffected by changes to a retrieved copy")
+	}
+}
+
+func TestMemoryStore_NotFound(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.Get("NOPE00-1")
+	if !errors.Is(err, ErrReplayNotFound) {
+		t.Errorf("expected ErrReplayNotFound, got %v", err)
+	}
+}
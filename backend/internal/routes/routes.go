@@ -2,6 +2,8 @@ package routes
 
 import (
 	"poke-battles/internal/controllers"
+	"poke-battles/internal/middleware"
+	"poke-battles/internal/replay"
 	"poke-battles/internal/services"
 	"poke-battles/internal/websocket"
 
@@ -10,8 +12,9 @@ import (
 
 const v1BasePath = "/api/v1"
 
-// RegisterRoutes registers API routes with injected dependencies
-func RegisterRoutes(server *gin.Engine, lobbyService services.LobbyService, wsHandler *websocket.Handler) {
+// RegisterRoutes registers API routes with injected dependencies. Routes for operators only
+// require adminToken as a bearer token, and are closed if it is "".
+func RegisterRoutes(server *gin.Engine, lobbyService services.LobbyService, battleService services.BattleService, replayStore replay.Store, wsHandler *websocket.Handler, adminToken string) {
 	v1 := server.Group(v1BasePath)
 
 	// Health check
@@ -24,12 +27,66 @@ func RegisterRoutes(server *gin.Engine, lobbyService services.LobbyService, wsHa
 	lobby := controllers.NewLobbyController(lobbyService)
 	lobbiesRoute.POST("", lobby.Create)
 	lobbiesRoute.GET("", lobby.List)
+	lobbiesRoute.POST("/quick-join", lobby.QuickJoin)
+	lobbiesRoute.GET("/tags", lobby.ListTags)
 	lobbiesRoute.GET("/:code", lobby.Get)
+	lobbiesRoute.DELETE("/:code", lobby.Delete)
 	lobbiesRoute.POST("/:code/join", lobby.Join)
+	lobbiesRoute.POST("/:code/spectate", lobby.Spectate)
+	lobbiesRoute.POST("/:code/waitlist", lobby.JoinWaitlist)
+	lobbiesRoute.POST("/:code/invites", lobby.CreateInvite)
 	lobbiesRoute.POST("/:code/leave", lobby.Leave)
 	lobbiesRoute.POST("/:code/start", lobby.Start)
+	lobbiesRoute.POST("/:code/team", lobby.SubmitTeam)
+	lobbiesRoute.GET("/:code/team/export", lobby.ExportTeam)
+	lobbiesRoute.POST("/:code/ready", lobby.SetReady)
+	lobbiesRoute.POST("/:code/ruleset", lobby.SetRuleset)
+	lobbiesRoute.POST("/:code/series", lobby.SetSeries)
+	lobbiesRoute.POST("/:code/rematch-teams", lobby.SetRematchTeams)
+	lobbiesRoute.PATCH("/:code/settings", lobby.UpdateSettings)
+	lobbiesRoute.POST("/:code/draft", lobby.SetDraft)
+	lobbiesRoute.POST("/:code/add-bot", lobby.AddBot)
+
+	// Players
+	playersRoute := v1.Group("/players")
+	players := controllers.NewPlayerController(lobbyService, battleService)
+	playersRoute.GET("/:id/lobby", players.GetLobby)
+
+	// Rulesets
+	rulesetsRoute := v1.Group("/rulesets")
+	rulesets := controllers.NewRulesetController()
+	rulesetsRoute.GET("", rulesets.List)
+
+	// Game data versions
+	data := controllers.NewDataController()
+	v1.GET("/data-versions", data.ListVersions)
+
+	// Teams
+	teamsRoute := v1.Group("/teams")
+	teams := controllers.NewTeamController()
+	teamsRoute.POST("/import", teams.Import)
+
+	// Damage calculator
+	calc := controllers.NewCalcController()
+	v1.POST("/calc", calc.Calculate)
+
+	// Replays
+	replaysRoute := v1.Group("/replays")
+	replays := controllers.NewReplayController(replayStore)
+	replaysRoute.GET("/:id", replays.Get)
+
+	// Game logs
+	gamesRoute := v1.Group("/games")
+	gameLogs := controllers.NewGameLogController(replayStore)
+	gamesRoute.GET("/:id/log", gameLogs.Get)
 
 	// WebSocket
 	wsRoute := v1.Group("/ws")
 	wsRoute.GET("/game/:code", wsHandler.HandleConnection)
+	wsRoute.GET("/metrics", wsHandler.HandleMetrics)
+
+	// Connections identify players, so only operators may list them
+	wsAdminRoute := wsRoute.Group("", middleware.AdminToken(adminToken))
+	wsAdminRoute.GET("/connections", wsHandler.HandleConnections)
+	wsAdminRoute.GET("/connections/:id", wsHandler.HandleConnectionInfo)
 }
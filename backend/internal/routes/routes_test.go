@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"poke-battles/internal/audit"
+	"poke-battles/internal/middleware"
+	"poke-battles/internal/replay"
+	"poke-battles/internal/services"
+	"poke-battles/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupTestServer registers the routes behind the CORS middleware, as cmd/api does
+func setupTestServer() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(middleware.CORS(middleware.DefaultAllowedOrigins))
+
+	lobbyService := services.NewLobbyService()
+	replayStore := replay.NewMemoryStore()
+	battleService := services.NewBattleService(replayStore, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	wsHandler := websocket.NewHandler(websocket.NewHub(), lobbyService, battleService)
+	RegisterRoutes(server, lobbyService, battleService, replayStore, wsHandler, "")
+	return server
+}
+
+// ========================================
+// CORS Tests
+// ========================================
+
+func TestRoutes_PreflightAllowsEveryMethodUsed(t *testing.T) {
+	server := setupTestServer()
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/lobbies"},
+		{http.MethodDelete, "/api/v1/lobbies/ABC123"},
+		{http.MethodPatch, "/api/v1/lobbies/ABC123/settings"},
+	} {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, route.path, nil)
+			req.Header.Set("Origin", middleware.DefaultAllowedOrigins[0])
+			req.Header.Set("Access-Control-Request-Method", route.method)
+			w := httptest.NewRecorder()
+
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+			}
+			if allowed := w.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(allowed, route.method) {
+				t.Errorf("expected %s to be allowed, got %q", route.method, allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+)
+
+// commandQueueSize is how many commands can wait for a game's goroutine before Dispatch blocks
+const commandQueueSize = 64
+
+// GameCommand is run on a game's goroutine, with exclusive access to its battle
+type GameCommand func(battle *game.Battle)
+
+// newActiveBattle wraps a newly started battle, whose end is published on bus; its goroutine is started with run
+func newActiveBattle(battle *game.Battle, lobby *game.Lobby, bus *events.Bus) (*activeBattle, error) {
+	recorder, err := replay.NewRecorder(battle)
+	if err != nil {
+		return nil, err
+	}
+	return &activeBattle{
+		battle:   battle,
+		lobby:    lobby,
+		recorder: recorder,
+		commands: make(chan GameCommand, commandQueueSize),
+		stop:     make(chan struct{}),
+		events:   bus,
+	}, nil
+}
+
+// run executes the game's commands in order until the game ends. GameEnded is published once the command
+// that ended the game returns, so whatever the command sent about the final turn goes out first.
+// Commands already queued when it ends still run, and find the game over.
+func (a *activeBattle) run() {
+	for {
+		select {
+		case cmd := <-a.commands:
+			cmd(a.battle)
+			a.publishEnded()
+		case <-a.stop:
+			a.publishEnded() // The game was ended from outside a command
+			for {
+				select {
+				case cmd := <-a.commands:
+					cmd(a.battle)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Dispatch queues a command to run on the game's goroutine after those queued before it.
+// Commands are how callers act on a battle without blocking on turn resolution, and without
+// ever touching it from two goroutines at once.
+func (s *battleService) Dispatch(gameID string, cmd GameCommand) error {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case active.commands <- cmd:
+		return nil
+	case <-active.stop:
+		return fmt.Errorf("battle %q: %w", gameID, ErrBattleNotFound)
+	}
+}
+
+// publishEnded publishes the game's GameEnded event if it has ended since the last call
+func (a *activeBattle) publishEnded() {
+	if a.ended == nil {
+		return
+	}
+	ended := *a.ended
+	a.ended = nil
+	a.events.Publish(ended)
+}
@@ -0,0 +1,531 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"poke-battles/internal/audit"
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+	"poke-battles/internal/random"
+	"poke-battles/internal/replay"
+)
+
+// Battle service errors
+var (
+	ErrBattleNotFound      = errors.New("battle not found")
+	ErrBattleAlreadyExists = errors.New("battle already exists")
+	ErrNotEnoughPlayers    = errors.New("battle requires two players")
+)
+
+// TurnResult is the outcome of a resolved turn
+type TurnResult struct {
+	Turn    int // The turn that was resolved
+	Events  []game.BattleEvent
+	Outcome *game.BattleOutcome // Set when the turn ended the battle
+	// ReplayID identifies the stored replay once the battle has ended.
+	// It is empty if the replay could not be saved.
+	ReplayID string
+	// Series is the lobby's series score including this battle, set with Outcome
+	Series game.Series
+}
+
+// BattleService manages the games played in lobbies.
+// Every started battle gets its own game ID, which is also the battle's ID; a lobby has at most
+// one game in progress at a time.
+//
+// Each game runs on its own goroutine, which runs the commands passed to Dispatch one at a time.
+// Battles are not safe for concurrent use, so once a game has started, its battle may only be read
+// or changed, including through the methods below, from a command dispatched to it.
+type BattleService interface {
+	// StartBattle starts a new game between the lobby's players under a freshly generated game ID
+	StartBattle(lobby *game.Lobby) (*game.Battle, error)
+	// GetBattle retrieves a game in progress by its ID
+	GetBattle(gameID string) (*game.Battle, error)
+	// GetLobbyBattle retrieves the game in progress in a lobby
+	GetLobbyBattle(code string) (*game.Battle, error)
+	// ActiveGames returns the codes of the lobbies with a game in progress, keyed by game ID
+	ActiveGames() map[string]string
+	// Dispatch queues a command to run on the game's goroutine and returns without waiting for it
+	Dispatch(gameID string, cmd GameCommand) error
+	// SubmitAction records a player's action and resolves the turn once both players
+	// have acted. The returned TurnResult is nil while the opponent's action is pending.
+	SubmitAction(gameID, playerID string, action game.Action) (*TurnResult, error)
+	// SubmitForcedSwitch replaces a player's fainted creature between turns.
+	// The result belongs to the turn that caused the faint.
+	SubmitForcedSwitch(gameID, playerID string, slot int) (*TurnResult, error)
+	// ChooseLead picks a player's lead during team preview.
+	// It returns true once both leads are chosen and turn 1 can begin.
+	ChooseLead(gameID, playerID string, slot int) (bool, error)
+	// Forfeit ends the battle with the player as the loser and releases the lobby.
+	// The result carries the outcome and replay but no events.
+	Forfeit(gameID, playerID string) (*TurnResult, error)
+	// ForfeitForDisconnect ends the battle with the player as the loser for staying disconnected past
+	// the grace period, and releases the lobby. The result carries the outcome and replay but no events.
+	ForfeitForDisconnect(gameID, playerID string) (*TurnResult, error)
+	// OfferDraw proposes a draw to the player's opponent.
+	OfferDraw(gameID, playerID string) error
+	// RespondDraw answers the opponent's draw offer. Accepting ends the battle as a draw and releases
+	// the lobby; the result carries the outcome and replay but no events. Declining returns a nil result.
+	RespondDraw(gameID, playerID string, accept bool) (*TurnResult, error)
+	// RequestPause asks the player's opponent to pause the battle.
+	RequestPause(gameID, playerID string) error
+	// RespondPause answers the opponent's pause request. Accepting pauses the battle and returns the pause;
+	// declining returns nil.
+	RespondPause(gameID, playerID string, accept bool) (*game.Pause, error)
+	// PauseForDisconnect pauses the battle for a player who lost their connection.
+	PauseForDisconnect(gameID, playerID string) (*game.Pause, error)
+	// Resume ends the pause in progress, charging it to the paused player's pause budget.
+	Resume(gameID string) error
+	// AbandonLobbyBattle discards the game in progress in a lobby without an outcome, replay or series result,
+	// stopping its goroutine once queued commands have run. It returns the ID of the discarded game.
+	AbandonLobbyBattle(code string) (string, error)
+}
+
+// activeBattle pairs a battle with the lobby it was started from, its replay recording
+// and the queue of commands for its goroutine
+type activeBattle struct {
+	battle   *game.Battle
+	lobby    *game.Lobby
+	recorder *replay.Recorder
+	commands chan GameCommand
+	stop     chan struct{} // Closed once the game has ended
+	events   *events.Bus
+	ended    *events.GameEnded // Set when the game ends, until its goroutine publishes it
+}
+
+// battleService implements BattleService with in-memory storage.
+// Finished battles are saved to the replay store, rejected actions to the audit trail,
+// and the end of each game is published on the event bus.
+type battleService struct {
+	mu         sync.RWMutex
+	battles    map[string]*activeBattle // Games in progress, keyed by game ID
+	lobbyGames map[string]string        // Game ID in progress, keyed by lobby code
+	replays    replay.Store
+	rejections audit.Trail
+	events     *events.Bus
+}
+
+// NewBattleService creates a new battle service instance that saves replays to the given store,
+// records every rejected action in the given audit trail and publishes GameEnded on the given bus,
+// which may be nil to publish nothing
+func NewBattleService(replays replay.Store, rejections audit.Trail, bus *events.Bus) BattleService {
+	return &battleService{
+		battles:    make(map[string]*activeBattle),
+		lobbyGames: make(map[string]string),
+		replays:    replays,
+		rejections: rejections,
+		events:     bus,
+	}
+}
+
+// gameIDBytes is the number of random bytes that follow the lobby code in a game ID
+const gameIDBytes = 4
+
+// newGameID creates a unique ID for a game played in a lobby.
+// Lobbies host several games through rematches, so a random suffix keeps them apart.
+func newGameID(lobbyCode string) (string, error) {
+	suffix, err := random.Hex(gameIDBytes)
+	if err != nil {
+		return "", fmt.Errorf("drawing game ID: %w", err)
+	}
+	return lobbyCode + "-" + suffix, nil
+}
+
+// StartBattle creates a battle between the lobby's players using their submitted teams.
+// A ready lobby is transitioned to active. Each player gets their own copy of the ruleset's item bag,
+// and the battle resolves against the ruleset's type chart if it has one.
+// If the ruleset uses team preview, the battle waits for both players to choose a lead before turn 1.
+func (s *battleService) StartBattle(lobby *game.Lobby) (*game.Battle, error) {
+	players := lobby.GetPlayers()
+	if len(players) != 2 {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, ErrNotEnoughPlayers)
+	}
+	ruleset := lobby.GetRuleset()
+
+	var sides [2]*game.BattleSide
+	for i, p := range players {
+		members, ok := lobby.GetTeam(p.ID)
+		if !ok {
+			return nil, fmt.Errorf("lobby %q, player %q: %w", lobby.Code, p.ID, game.ErrTeamsNotSubmitted)
+		}
+		team, err := game.BuildTeam(p.ID, members)
+		if err != nil {
+			return nil, fmt.Errorf("lobby %q, player %q: %w", lobby.Code, p.ID, err)
+		}
+		sides[i] = game.NewBattleSide(p.ID, team)
+		sides[i].Bag = ruleset.Bag.Clone()
+	}
+
+	// Draw the IDs and seed and build the battle before the lobby starts, so a failure leaves it as it was
+	gameID, err := newGameID(lobby.Code)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+	}
+	seed, err := game.NewBattleSeed()
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+	}
+	battle, err := game.NewSeededBattle(gameID, sides[0], sides[1], seed)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+	}
+	battle.Data = ruleset.DataPack(battle.Data)
+	battle.SetTurnLimit(ruleset.TurnLimit)
+	if ruleset.TeamPreview {
+		battle.StartTeamPreview()
+	}
+	active, err := newActiveBattle(battle, lobby, s.events)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.lobbyGames[lobby.Code]; exists {
+		return nil, fmt.Errorf("lobby %q: %w", lobby.Code, ErrBattleAlreadyExists)
+	}
+
+	if lobby.GetState() != game.LobbyStateActive {
+		if err := lobby.Start(); err != nil {
+			return nil, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+		}
+	}
+
+	s.battles[battle.ID] = active
+	s.lobbyGames[lobby.Code] = battle.ID
+	go active.run()
+
+	return battle, nil
+}
+
+// GetBattle retrieves a game in progress by its ID
+func (s *battleService) GetBattle(gameID string) (*game.Battle, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+	return active.battle, nil
+}
+
+// GetLobbyBattle retrieves the game in progress in a lobby
+func (s *battleService) GetLobbyBattle(code string) (*game.Battle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	gameID, exists := s.lobbyGames[code]
+	if !exists {
+		return nil, fmt.Errorf("lobby %q: %w", code, ErrBattleNotFound)
+	}
+	return s.battles[gameID].battle, nil
+}
+
+// ActiveGames returns the codes of the lobbies with a game in progress, keyed by game ID
+func (s *battleService) ActiveGames() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	games := make(map[string]string, len(s.battles))
+	for gameID, active := range s.battles {
+		games[gameID] = active.lobby.Code
+	}
+	return games
+}
+
+// getActive retrieves the battle and lobby entry for a game ID
+func (s *battleService) getActive(gameID string) (*activeBattle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	active, exists := s.battles[gameID]
+	if !exists {
+		return nil, fmt.Errorf("battle %q: %w", gameID, ErrBattleNotFound)
+	}
+
+	return active, nil
+}
+
+// SubmitAction records a player's action and resolves the turn when both actions are in
+func (s *battleService) SubmitAction(gameID, playerID string, action game.Action) (*TurnResult, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+	battle := active.battle
+
+	// Resubmitting the action already chosen for this turn changes nothing
+	if submitted, ok := battle.SubmittedAction(playerID); ok && submitted == action {
+		return nil, nil
+	}
+
+	// The turn cannot advance until this action is accepted, so it is read beforehand
+	turn := battle.CurrentTurn()
+	if err := battle.SubmitAction(playerID, action); err != nil {
+		s.recordRejection(active, playerID, turn, replayAction(playerID, action), err)
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	active.recorder.RecordAction(turn, replayAction(playerID, action))
+
+	if !battle.AllActionsSubmitted() {
+		return nil, nil
+	}
+
+	events, err := battle.ResolveTurn()
+	if errors.Is(err, game.ErrActionsPending) {
+		// The opponent's submission already resolved this turn
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("battle %q: %w", gameID, err)
+	}
+
+	return s.turnResult(gameID, active, battle.CurrentTurn()-1, events), nil
+}
+
+// recordRejection adds an action the battle refused to the audit trail, with its game and lobby
+func (s *battleService) recordRejection(active *activeBattle, playerID string, turn int, action replay.Action, err error) {
+	s.rejections.Record(audit.Rejection{
+		At:        time.Now(),
+		LobbyCode: active.lobby.Code,
+		GameID:    active.battle.ID,
+		PlayerID:  playerID,
+		Turn:      turn,
+		Action:    action,
+		Reason:    err.Error(),
+	})
+}
+
+// replayAction converts a battle action to its replay form
+func replayAction(playerID string, action game.Action) replay.Action {
+	switch action.Kind {
+	case game.ActionKindSwitch:
+		return replay.Action{PlayerID: playerID, Kind: replay.ActionKindSwitch, Slot: action.SwitchSlot}
+	case game.ActionKindItem:
+		return replay.Action{PlayerID: playerID, Kind: replay.ActionKindItem, ItemID: action.ItemID, Slot: action.ItemSlot}
+	}
+	return replay.Action{PlayerID: playerID, Kind: replay.ActionKindMove, MoveID: action.MoveID}
+}
+
+// turnResult records and packages resolved events, ending the battle if they decided it
+func (s *battleService) turnResult(gameID string, active *activeBattle, turn int, events []game.BattleEvent) *TurnResult {
+	active.recorder.RecordEvents(turn, events)
+
+	result := &TurnResult{Turn: turn, Events: events}
+	if outcome := active.battle.Outcome(); outcome != nil {
+		result.Outcome = outcome
+		result.ReplayID, result.Series = s.endBattle(gameID, active, outcome)
+	}
+	return result
+}
+
+// SubmitForcedSwitch replaces a player's fainted active creature
+func (s *battleService) SubmitForcedSwitch(gameID, playerID string, slot int) (*TurnResult, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := active.battle.CurrentTurn() - 1
+	events, err := active.battle.SubmitForcedSwitch(playerID, slot)
+	if err != nil {
+		s.recordRejection(active, playerID, turn, replay.Action{PlayerID: playerID, Kind: replay.ActionKindForcedSwitch, Slot: slot}, err)
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+
+	active.recorder.RecordAction(turn, replay.Action{PlayerID: playerID, Kind: replay.ActionKindForcedSwitch, Slot: slot})
+	return s.turnResult(gameID, active, turn, events), nil
+}
+
+// ChooseLead picks a player's lead during team preview
+func (s *battleService) ChooseLead(gameID, playerID string, slot int) (bool, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return false, err
+	}
+
+	ready, err := active.battle.ChooseLead(playerID, slot)
+	if err != nil {
+		s.recordRejection(active, playerID, active.battle.CurrentTurn(), replay.Action{PlayerID: playerID, Kind: replay.ActionKindChooseLead, Slot: slot}, err)
+		return false, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+
+	active.recorder.RecordAction(active.battle.CurrentTurn(), replay.Action{PlayerID: playerID, Kind: replay.ActionKindChooseLead, Slot: slot})
+	return ready, nil
+}
+
+// Forfeit ends the battle in the opponent's favour, then releases the lobby and battle state
+func (s *battleService) Forfeit(gameID, playerID string) (*TurnResult, error) {
+	return s.forfeit(gameID, playerID, replay.ActionKindForfeit, (*game.Battle).Forfeit)
+}
+
+// ForfeitForDisconnect ends the battle in the opponent's favour for a player who did not return in time,
+// then releases the lobby and battle state
+func (s *battleService) ForfeitForDisconnect(gameID, playerID string) (*TurnResult, error) {
+	return s.forfeit(gameID, playerID, replay.ActionKindDisconnect, (*game.Battle).ForfeitForDisconnect)
+}
+
+// forfeit ends the battle with end, recording it in the replay as kind
+func (s *battleService) forfeit(gameID, playerID string, kind replay.ActionKind, end func(*game.Battle, string) (*game.BattleOutcome, error)) (*TurnResult, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := active.battle.CurrentTurn()
+	outcome, err := end(active.battle, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	active.recorder.RecordAction(turn, replay.Action{PlayerID: playerID, Kind: kind})
+
+	replayID, series := s.endBattle(gameID, active, outcome)
+	return &TurnResult{
+		Turn:     turn,
+		Outcome:  outcome,
+		ReplayID: replayID,
+		Series:   series,
+	}, nil
+}
+
+// OfferDraw proposes a draw to the player's opponent
+func (s *battleService) OfferDraw(gameID, playerID string) error {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return err
+	}
+
+	if err := active.battle.OfferDraw(playerID); err != nil {
+		return fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	active.recorder.RecordAction(active.battle.CurrentTurn(), replay.Action{PlayerID: playerID, Kind: replay.ActionKindOfferDraw})
+	return nil
+}
+
+// RespondDraw answers the opponent's draw offer, ending the battle as a draw if the player accepts
+func (s *battleService) RespondDraw(gameID, playerID string, accept bool) (*TurnResult, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := active.battle.CurrentTurn()
+	outcome, err := active.battle.RespondDraw(playerID, accept)
+	if err != nil {
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	if outcome == nil {
+		active.recorder.RecordAction(turn, replay.Action{PlayerID: playerID, Kind: replay.ActionKindDeclineDraw})
+		return nil, nil
+	}
+	active.recorder.RecordAction(turn, replay.Action{PlayerID: playerID, Kind: replay.ActionKindAcceptDraw})
+
+	replayID, series := s.endBattle(gameID, active, outcome)
+	return &TurnResult{
+		Turn:     turn,
+		Outcome:  outcome,
+		ReplayID: replayID,
+		Series:   series,
+	}, nil
+}
+
+// RequestPause asks the player's opponent to pause the battle
+func (s *battleService) RequestPause(gameID, playerID string) error {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return err
+	}
+
+	if err := active.battle.RequestPause(playerID); err != nil {
+		return fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	return nil
+}
+
+// RespondPause answers the opponent's pause request, pausing the battle from now if the player accepts
+func (s *battleService) RespondPause(gameID, playerID string, accept bool) (*game.Pause, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	pause, err := active.battle.RespondPause(playerID, accept, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	return pause, nil
+}
+
+// PauseForDisconnect pauses the battle from now for a player who lost their connection
+func (s *battleService) PauseForDisconnect(gameID, playerID string) (*game.Pause, error) {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	pause, err := active.battle.PauseForDisconnect(playerID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("battle %q, player %q: %w", gameID, playerID, err)
+	}
+	return pause, nil
+}
+
+// Resume ends the pause in progress
+func (s *battleService) Resume(gameID string) error {
+	active, err := s.getActive(gameID)
+	if err != nil {
+		return err
+	}
+
+	if err := active.battle.Resume(time.Now()); err != nil {
+		return fmt.Errorf("battle %q: %w", gameID, err)
+	}
+	return nil
+}
+
+// AbandonLobbyBattle discards a lobby's game in progress, for lobbies that are closed mid-game
+func (s *battleService) AbandonLobbyBattle(code string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gameID, exists := s.lobbyGames[code]
+	if !exists {
+		return "", fmt.Errorf("lobby %q: %w", code, ErrBattleNotFound)
+	}
+
+	active := s.battles[gameID]
+	delete(s.battles, gameID)
+	delete(s.lobbyGames, code)
+	close(active.stop)
+	return gameID, nil
+}
+
+// endBattle saves the replay, counts the win towards the lobby's series, transitions the lobby
+// to finished and removes the completed battle, stopping its goroutine once it has published GameEnded.
+// It returns the ID of the saved replay, or an empty string if saving failed, and the updated series score.
+func (s *battleService) endBattle(gameID string, active *activeBattle, outcome *game.BattleOutcome) (string, game.Series) {
+	var replayID string
+	if err := s.replays.Save(active.recorder.Finish(outcome)); err == nil {
+		replayID = active.recorder.ID()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := active.lobby.RecordWin(outcome.WinnerID)
+	// The lobby can only fail to end if it already left active, which is the desired state
+	_ = active.lobby.End()
+	delete(s.battles, gameID)
+	delete(s.lobbyGames, active.lobby.Code)
+	active.ended = &events.GameEnded{
+		Lobby:    active.lobby,
+		Battle:   active.battle,
+		Outcome:  outcome,
+		ReplayID: replayID,
+		Series:   series,
+	}
+	close(active.stop)
+	return replayID, series
+}
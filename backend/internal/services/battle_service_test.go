@@ -0,0 +1,675 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"poke-battles/internal/audit"
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+	"poke-battles/internal/replay"
+)
+
+// newFullLobby creates a lobby with two players
+func newFullLobby(t *testing.T) *game.Lobby {
+	t.Helper()
+	lobby := game.NewLobby("ABC123", "player-1", "Player1")
+	if err := lobby.AddPlayer("player-2", "Player2"); err != nil {
+		t.Fatalf("failed to add player: %v", err)
+	}
+	for _, playerID := range []string{"player-1", "player-2"} {
+		if err := lobby.SubmitTeam(playerID, game.StarterTeam()); err != nil {
+			t.Fatalf("failed to submit team: %v", err)
+		}
+	}
+	return lobby
+}
+
+// ========================================
+// Happy Path Tests
+// ========================================
+
+func TestStartBattle_Success(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+
+	battle, err := svc.StartBattle(newFullLobby(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if battle.Sides[0].PlayerID != "player-1" || battle.Sides[1].PlayerID != "player-2" {
+		t.Errorf("unexpected sides: %s vs %s", battle.Sides[0].PlayerID, battle.Sides[1].PlayerID)
+	}
+	if battle.Seed == 0 {
+		t.Error("expected battle to be created with an RNG seed")
+	}
+
+	got, err := svc.GetBattle(battle.ID)
+	if err != nil || got != battle {
+		t.Errorf("expected battle to be retrievable by game ID, got %v", err)
+	}
+	got, err = svc.GetLobbyBattle("ABC123")
+	if err != nil || got != battle {
+		t.Errorf("expected battle to be retrievable by lobby code, got %v", err)
+	}
+}
+
+func TestStartBattle_DistinctGameIDPerGame(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+
+	first, _ := svc.StartBattle(lobby)
+	if first.ID == lobby.Code {
+		t.Errorf("expected a game ID distinct from the lobby code, got %q", first.ID)
+	}
+	if games := svc.ActiveGames(); len(games) != 1 || games[first.ID] != "ABC123" {
+		t.Errorf("expected the game to be tracked as active in its lobby, got %v", games)
+	}
+
+	svc.Forfeit(first.ID, "player-1")
+	if len(svc.ActiveGames()) != 0 {
+		t.Errorf("expected no active games after the forfeit, got %v", svc.ActiveGames())
+	}
+	lobby.Rematch()
+	second, err := svc.StartBattle(lobby)
+	if err != nil {
+		t.Fatalf("expected rematch to start, got %v", err)
+	}
+
+	if second.ID == first.ID {
+		t.Errorf("expected the rematch to get a new game ID, got %q twice", first.ID)
+	}
+	if _, err := svc.GetBattle(first.ID); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected the finished game to be gone, got %v", err)
+	}
+	if got, _ := svc.GetLobbyBattle("ABC123"); got != second {
+		t.Error("expected the lobby to map to the rematch")
+	}
+}
+
+func TestStartBattle_ActivatesLobby(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+
+	svc.StartBattle(lobby)
+
+	if lobby.GetState() != game.LobbyStateActive {
+		t.Errorf("expected state Active, got %v", lobby.GetState())
+	}
+}
+
+func TestSubmitAction_ResolvesWhenBothSubmitted(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	result, err := svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "razor-leaf"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != nil {
+		t.Fatal("expected no result while opponent action is pending")
+	}
+
+	result, err = svc.SubmitAction(battle.ID, "player-2", game.Action{Kind: game.ActionKindMove, MoveID: "swords-dance"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result == nil || result.Turn != 1 || len(result.Events) == 0 {
+		t.Errorf("expected resolved turn 1 with events, got %+v", result)
+	}
+}
+
+func TestSubmitAction_ResubmittingSameActionIsIdempotent(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+	attack := game.Action{Kind: game.ActionKindMove, MoveID: "razor-leaf"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.SubmitAction(battle.ID, "player-1", attack); err != nil {
+			t.Fatalf("submission %d: expected no error, got %v", i+1, err)
+		}
+	}
+	other := game.Action{Kind: game.ActionKindMove, MoveID: "swords-dance"}
+	if _, err := svc.SubmitAction(battle.ID, "player-1", other); !errors.Is(err, game.ErrActionAlreadySubmitted) {
+		t.Errorf("expected ErrActionAlreadySubmitted for a different action, got %v", err)
+	}
+
+	result, _ := svc.Forfeit(battle.ID, "player-2")
+	doc, _ := store.Get(result.ReplayID)
+	moves := 0
+	for _, action := range doc.Turns[0].Actions {
+		if action.Kind == replay.ActionKindMove {
+			moves++
+		}
+	}
+	if moves != 1 {
+		t.Errorf("expected the action to be recorded once, got %+v", doc.Turns[0].Actions)
+	}
+}
+
+func TestForfeit_EndsBattleAndReleasesLobby(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	battle, _ := svc.StartBattle(lobby)
+
+	result, err := svc.Forfeit(battle.ID, "player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	outcome := result.Outcome
+
+	if outcome.LoserID != "player-2" || outcome.WinnerID != "player-1" || outcome.Reason != game.EndReasonForfeit {
+		t.Errorf("unexpected outcome: %+v", outcome)
+	}
+	if lobby.GetState() == game.LobbyStateActive {
+		t.Error("expected lobby to leave the active state")
+	}
+	if _, err := svc.GetBattle(battle.ID); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected battle state to be cleaned up, got %v", err)
+	}
+}
+
+func TestForfeitForDisconnect_EndsBattle(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	battle, _ := svc.StartBattle(lobby)
+
+	result, err := svc.ForfeitForDisconnect(battle.ID, "player-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Outcome.WinnerID != "player-2" || result.Outcome.Reason != game.EndReasonOpponentDisconnect {
+		t.Errorf("unexpected outcome: %+v", result.Outcome)
+	}
+	if lobby.GetState() == game.LobbyStateActive {
+		t.Error("expected lobby to leave the active state")
+	}
+	if _, err := svc.ForfeitForDisconnect(battle.ID, "player-1"); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound once the battle is over, got %v", err)
+	}
+}
+
+func TestAbandonLobbyBattle_DiscardsGame(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	battle, _ := svc.StartBattle(lobby)
+
+	gameID, err := svc.AbandonLobbyBattle(lobby.Code)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gameID != battle.ID {
+		t.Errorf("expected game %q to be abandoned, got %q", battle.ID, gameID)
+	}
+	if _, err := svc.GetLobbyBattle(lobby.Code); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected battle state to be cleaned up, got %v", err)
+	}
+	if _, err := svc.AbandonLobbyBattle(lobby.Code); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound once abandoned, got %v", err)
+	}
+}
+
+func TestSubmitAction_VictoryEndsBattle(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	battle, _ := svc.StartBattle(lobby)
+	for _, c := range battle.Sides[1].Team {
+		c.CurrentHP = 0
+	}
+	battle.Sides[1].Active().CurrentHP = 1
+
+	svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "earthquake"})
+	result, err := svc.SubmitAction(battle.ID, "player-2", game.Action{Kind: game.ActionKindMove, MoveID: "swords-dance"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Outcome == nil || result.Outcome.WinnerID != "player-1" || result.Outcome.Reason != game.EndReasonVictory {
+		t.Fatalf("expected player-1 victory, got %+v", result.Outcome)
+	}
+	if lobby.GetState() != game.LobbyStateFinished {
+		t.Errorf("expected state Finished, got %v", lobby.GetState())
+	}
+	if _, err := svc.GetBattle(battle.ID); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected battle state to be cleaned up, got %v", err)
+	}
+}
+
+func TestSubmitAction_VictorySavesReplay(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+	for _, c := range battle.Sides[1].Team {
+		c.CurrentHP = 0
+	}
+	battle.Sides[1].Active().CurrentHP = 1
+
+	svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "earthquake"})
+	result, _ := svc.SubmitAction(battle.ID, "player-2", game.Action{Kind: game.ActionKindMove, MoveID: "swords-dance"})
+
+	doc, err := store.Get(result.ReplayID)
+	if err != nil {
+		t.Fatalf("expected replay to be saved, got %v", err)
+	}
+	if doc.Version != replay.FormatVersion || doc.BattleID != battle.ID || doc.Seed != battle.Seed {
+		t.Errorf("unexpected replay header: %+v", doc)
+	}
+	if len(doc.Turns) != 1 || len(doc.Turns[0].Actions) != 2 || len(doc.Turns[0].Events) != len(result.Events) {
+		t.Fatalf("expected turn 1 with both actions and all events, got %+v", doc.Turns)
+	}
+	if doc.Outcome == nil || doc.Outcome.WinnerID != "player-1" {
+		t.Errorf("expected player-1 victory in replay, got %+v", doc.Outcome)
+	}
+}
+
+func TestForfeit_SavesReplay(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	result, _ := svc.Forfeit(battle.ID, "player-2")
+
+	doc, err := store.Get(result.ReplayID)
+	if err != nil {
+		t.Fatalf("expected replay to be saved, got %v", err)
+	}
+	actions := doc.Turns[0].Actions
+	if len(actions) != 1 || actions[0].Kind != replay.ActionKindForfeit || actions[0].PlayerID != "player-2" {
+		t.Errorf("expected forfeit action to be recorded, got %+v", actions)
+	}
+}
+
+// ========================================
+// Error Cases
+// ========================================
+
+func TestStartBattle_NotEnoughPlayers(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+
+	_, err := svc.StartBattle(game.NewLobby("ABC123", "player-1", "Player1"))
+	if !errors.Is(err, ErrNotEnoughPlayers) {
+		t.Errorf("expected ErrNotEnoughPlayers, got %v", err)
+	}
+}
+
+func TestStartBattle_AlreadyExists(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	svc.StartBattle(lobby)
+
+	_, err := svc.StartBattle(lobby)
+	if !errors.Is(err, ErrBattleAlreadyExists) {
+		t.Errorf("expected ErrBattleAlreadyExists, got %v", err)
+	}
+}
+
+func TestGetBattle_NotFound(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+
+	_, err := svc.GetBattle("NOPE00")
+	if !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound, got %v", err)
+	}
+}
+
+func TestSubmitAction_WrapsDomainErrors(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+	battle.Sides[0].Active().PP["razor-leaf"] = 0
+
+	_, err := svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "razor-leaf"})
+	if !errors.Is(err, game.ErrNoPPLeft) {
+		t.Errorf("expected ErrNoPPLeft, got %v", err)
+	}
+}
+
+func TestSubmitAction_RejectionRecordedInAuditTrail(t *testing.T) {
+	trail := audit.NewMemoryTrail(audit.DefaultCapacity)
+	svc := NewBattleService(replay.NewMemoryStore(), trail, nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	if _, err := svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "hyper-beam"}); err == nil {
+		t.Fatal("expected an unknown move to be rejected")
+	}
+	if _, err := svc.SubmitForcedSwitch(battle.ID, "player-2", 1); err == nil {
+		t.Fatal("expected a forced switch without a faint to be rejected")
+	}
+	if _, err := svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindMove, MoveID: "razor-leaf"}); err != nil {
+		t.Fatalf("expected a legal move to be accepted, got %v", err)
+	}
+
+	rejected := trail.ByPlayer("player-1")
+	if len(rejected) != 1 {
+		t.Fatalf("expected only the illegal move in player-1's trail, got %+v", rejected)
+	}
+	got := rejected[0]
+	if got.LobbyCode != "ABC123" || got.GameID != battle.ID || got.Turn != 1 || got.At.IsZero() {
+		t.Errorf("unexpected rejection context: %+v", got)
+	}
+	if got.Action.Kind != replay.ActionKindMove || got.Action.MoveID != "hyper-beam" || got.Reason != game.ErrMoveNotKnown.Error() {
+		t.Errorf("unexpected rejected action: %+v", got)
+	}
+
+	if rejected := trail.ByPlayer("player-2"); len(rejected) != 1 || rejected[0].Action.Kind != replay.ActionKindForcedSwitch {
+		t.Errorf("expected the forced switch in player-2's trail, got %+v", rejected)
+	}
+}
+
+func TestForfeit_BattleNotFound(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+
+	_, err := svc.Forfeit("NOPE00", "player-1")
+	if !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound, got %v", err)
+	}
+}
+
+func TestForfeit_CountsTowardsSeries(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	lobby.SetSeriesLength(3)
+	battle, _ := svc.StartBattle(lobby)
+
+	result, _ := svc.Forfeit(battle.ID, "player-1")
+
+	if result.Series.Games != 1 || result.Series.Wins["player-2"] != 1 {
+		t.Errorf("expected player-2 to lead the series 1-0, got %+v", result.Series)
+	}
+	if result.Series.IsOver() {
+		t.Error("expected best of 3 to continue after one game")
+	}
+}
+
+// ========================================
+// Draw Offer Tests
+// ========================================
+
+func TestRespondDraw_AcceptEndsBattleAndSavesReplay(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	battle, _ := svc.StartBattle(lobby)
+
+	if err := svc.OfferDraw(battle.ID, "player-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	result, err := svc.RespondDraw(battle.ID, "player-2", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Outcome == nil || !result.Outcome.IsDraw() || result.Outcome.Reason != game.EndReasonDraw {
+		t.Errorf("expected a draw, got %+v", result.Outcome)
+	}
+	if result.Series.Games != 1 || len(result.Series.Wins) != 0 {
+		t.Errorf("expected the draw to count as a game without a win, got %+v", result.Series)
+	}
+	if lobby.GetState() != game.LobbyStateFinished {
+		t.Errorf("expected lobby to be finished, got %s", lobby.GetState())
+	}
+	if _, err := svc.GetBattle(battle.ID); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected battle to be removed, got %v", err)
+	}
+
+	doc, err := store.Get(result.ReplayID)
+	if err != nil {
+		t.Fatalf("expected replay to be saved, got %v", err)
+	}
+	actions := doc.Turns[0].Actions
+	if len(actions) != 2 || actions[0].Kind != replay.ActionKindOfferDraw || actions[1].Kind != replay.ActionKindAcceptDraw {
+		t.Errorf("expected the offer and acceptance to be recorded, got %+v", actions)
+	}
+}
+
+func TestRespondDraw_DeclineKeepsBattle(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+	svc.OfferDraw(battle.ID, "player-1")
+
+	result, err := svc.RespondDraw(battle.ID, "player-2", false)
+	if err != nil || result != nil {
+		t.Fatalf("expected the offer to be declined, got %+v, %v", result, err)
+	}
+	if _, err := svc.GetBattle(battle.ID); err != nil {
+		t.Errorf("expected battle to continue, got %v", err)
+	}
+}
+
+func TestRespondDraw_NoOffer(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	if _, err := svc.RespondDraw(battle.ID, "player-2", true); !errors.Is(err, game.ErrNoDrawOffer) {
+		t.Errorf("expected ErrNoDrawOffer, got %v", err)
+	}
+}
+
+// ========================================
+// Item Bag Tests
+// ========================================
+
+func TestStartBattle_BagFromRuleset(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	casual, _ := game.LookupRuleset("casual")
+	lobby.SetRuleset(casual)
+
+	battle, _ := svc.StartBattle(lobby)
+
+	for _, side := range battle.Sides {
+		if side.Bag["potion"] != casual.Bag["potion"] {
+			t.Errorf("expected %s to start with %d potions, got %d", side.PlayerID, casual.Bag["potion"], side.Bag["potion"])
+		}
+	}
+	battle.Sides[0].Bag["potion"]--
+	if battle.Sides[1].Bag["potion"] != casual.Bag["potion"] || casual.Bag["potion"] != 3 {
+		t.Error("expected each player and the ruleset to hold separate bags")
+	}
+}
+
+func TestSubmitAction_ItemRecordedInReplay(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	casual, _ := game.LookupRuleset("casual")
+	lobby.SetRuleset(casual)
+	battle, _ := svc.StartBattle(lobby)
+	battle.Sides[0].Active().CurrentHP--
+
+	if _, err := svc.SubmitAction(battle.ID, "player-1", game.Action{Kind: game.ActionKindItem, ItemID: "potion"}); err != nil {
+		t.Fatalf("item action failed: %v", err)
+	}
+	result, _ := svc.Forfeit(battle.ID, "player-2")
+
+	doc, err := store.Get(result.ReplayID)
+	if err != nil {
+		t.Fatalf("expected replay to be saved, got %v", err)
+	}
+	if doc.Players[0].Bag["potion"] != 3 {
+		t.Errorf("expected starting bag in replay, got %v", doc.Players[0].Bag)
+	}
+	actions := doc.Turns[0].Actions
+	if len(actions) == 0 || actions[0].Kind != replay.ActionKindItem || actions[0].ItemID != "potion" {
+		t.Errorf("expected item action to be recorded, got %+v", actions)
+	}
+}
+
+// ========================================
+// Team Preview Tests
+// ========================================
+
+func TestStartBattle_TeamPreviewFromRuleset(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	competitive, _ := game.LookupRuleset("competitive")
+	if err := lobby.SetRuleset(competitive); err != nil {
+		t.Fatalf("failed to set ruleset: %v", err)
+	}
+
+	battle, _ := svc.StartBattle(lobby)
+
+	if !battle.InTeamPreview() {
+		t.Error("expected competitive battle to start in team preview")
+	}
+}
+
+func TestStartBattle_TypeChartFromRuleset(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	inverse, _ := game.LookupRuleset("inverse")
+	if err := lobby.SetRuleset(inverse); err != nil {
+		t.Fatalf("failed to set ruleset: %v", err)
+	}
+
+	battle, _ := svc.StartBattle(lobby)
+
+	if battle.Data.Ruleset != "inverse" || battle.Data.Effectiveness(game.TypeFire, []game.Type{game.TypeGrass}) != 0.5 {
+		t.Errorf("expected the battle to resolve against the inverse type chart, got %+v", battle.Data)
+	}
+}
+
+func TestStartBattle_NoTeamPreviewByDefault(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	if battle.InTeamPreview() {
+		t.Error("expected standard battle to skip team preview")
+	}
+}
+
+func TestChooseLead_EndsPreviewAndRecordsReplay(t *testing.T) {
+	store := replay.NewMemoryStore()
+	svc := NewBattleService(store, audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	lobby := newFullLobby(t)
+	competitive, _ := game.LookupRuleset("competitive")
+	lobby.SetRuleset(competitive)
+	battle, _ := svc.StartBattle(lobby)
+
+	if ready, err := svc.ChooseLead(battle.ID, "player-1", 2); err != nil || ready {
+		t.Fatalf("expected first lead to be accepted without ending preview, got ready=%v err=%v", ready, err)
+	}
+	if ready, err := svc.ChooseLead(battle.ID, "player-2", 1); err != nil || !ready {
+		t.Fatalf("expected second lead to end preview, got ready=%v err=%v", ready, err)
+	}
+	if battle.Sides[0].ActiveSlot != 2 || battle.Sides[1].ActiveSlot != 1 {
+		t.Errorf("unexpected leads: %d vs %d", battle.Sides[0].ActiveSlot, battle.Sides[1].ActiveSlot)
+	}
+
+	result, _ := svc.Forfeit(battle.ID, "player-1")
+
+	doc, err := store.Get(result.ReplayID)
+	if err != nil {
+		t.Fatalf("expected replay to be saved, got %v", err)
+	}
+	actions := doc.Turns[0].Actions
+	if len(actions) != 3 || actions[0].Kind != replay.ActionKindChooseLead || actions[0].Slot != 2 {
+		t.Errorf("expected lead choices to be recorded on turn 1, got %+v", actions)
+	}
+}
+
+func TestChooseLead_WrapsDomainErrors(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	_, err := svc.ChooseLead(battle.ID, "player-1", 0)
+	if !errors.Is(err, game.ErrNotInTeamPreview) {
+		t.Errorf("expected ErrNotInTeamPreview, got %v", err)
+	}
+}
+
+// ========================================
+// Command Dispatch Tests
+// ========================================
+
+func TestDispatch_RunsCommandsInOrder(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	done := make(chan []int)
+	var order []int
+	for i := 1; i <= 3; i++ {
+		i := i
+		if err := svc.Dispatch(battle.ID, func(b *game.Battle) {
+			if b != battle {
+				t.Error("expected the command to get the game's battle")
+			}
+			order = append(order, i)
+		}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	svc.Dispatch(battle.ID, func(*game.Battle) { done <- order })
+
+	got := <-done
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("expected commands to run in order, got %v", got)
+	}
+}
+
+func TestDispatch_CommandsQueuedBehindGameEndStillRun(t *testing.T) {
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), nil)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	queued := make(chan struct{})
+	errs := make(chan error, 1)
+	svc.Dispatch(battle.ID, func(b *game.Battle) {
+		<-queued
+		svc.Forfeit(b.ID, "player-1")
+	})
+	svc.Dispatch(battle.ID, func(b *game.Battle) {
+		_, err := svc.Forfeit(b.ID, "player-2")
+		errs <- err
+	})
+	close(queued)
+
+	if err := <-errs; !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound, got %v", err)
+	}
+	if err := svc.Dispatch(battle.ID, func(*game.Battle) {}); !errors.Is(err, ErrBattleNotFound) {
+		t.Errorf("expected ErrBattleNotFound after the game ended, got %v", err)
+	}
+}
+
+func TestDispatch_GameEndedPublishedAfterCommand(t *testing.T) {
+	bus := events.NewBus()
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), bus)
+	battle, _ := svc.StartBattle(newFullLobby(t))
+
+	order := make(chan string, 2)
+	bus.Subscribe(func(event events.Event) {
+		if e, ok := event.(events.GameEnded); ok {
+			if e.Outcome.WinnerID != "player-2" || e.ReplayID == "" || e.Battle != battle {
+				t.Errorf("unexpected game ended event: %+v", e)
+			}
+			order <- "game_ended"
+		}
+	})
+	svc.Dispatch(battle.ID, func(b *game.Battle) {
+		svc.Forfeit(b.ID, "player-1")
+		order <- "command"
+	})
+
+	if first, second := <-order, <-order; first != "command" || second != "game_ended" {
+		t.Errorf("expected GameEnded after the command that ended the game, got %s then %s", first, second)
+	}
+}
+
+func TestAbandonLobbyBattle_PublishesNothing(t *testing.T) {
+	bus := events.NewBus()
+	svc := NewBattleService(replay.NewMemoryStore(), audit.NewMemoryTrail(audit.DefaultCapacity), bus)
+	svc.StartBattle(newFullLobby(t))
+
+	published := make(chan events.Event, 1)
+	bus.Subscribe(func(event events.Event) { published <- event })
+	svc.AbandonLobbyBattle("ABC123")
+
+	select {
+	case event := <-published:
+		t.Errorf("expected nothing published for an abandoned game, got %s", event.Name())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"time"
+
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+)
+
+// Lobby janitor defaults
+const (
+	DefaultLobbyIdleTTL    = 30 * time.Minute
+	DefaultJanitorInterval = time.Minute
+)
+
+// LobbyJanitorConfig configures the background janitor that removes abandoned lobbies
+type LobbyJanitorConfig struct {
+	IdleTTL  time.Duration // How long a lobby may go without activity before it is removed
+	Interval time.Duration // How often lobbies are checked
+	// IsPlayerConnected reports whether a player has a live connection; lobbies with one are never removed
+	IsPlayerConnected func(playerID string) bool
+}
+
+// ExpireIdleLobbies removes lobbies that are waiting, ready or closed, have seen no activity for ttl
+// as of now, and have none of their human players connected. It publishes LobbyExpired for each one
+// and returns the removed lobbies.
+func (s *lobbyService) ExpireIdleLobbies(now time.Time, ttl time.Duration, isPlayerConnected func(playerID string) bool) []*game.Lobby {
+	s.mu.Lock()
+	var expired []*game.Lobby
+	for code, lobby := range s.lobbies {
+		if !lobbyIdle(lobby, now, ttl, isPlayerConnected) {
+			continue
+		}
+		delete(s.lobbies, code)
+		expired = append(expired, lobby)
+	}
+	s.mu.Unlock()
+
+	for _, lobby := range expired {
+		s.events.Publish(events.LobbyExpired{Lobby: lobby})
+	}
+	return expired
+}
+
+// lobbyIdle returns true if a lobby has been abandoned before its game started or after it closed
+func lobbyIdle(lobby *game.Lobby, now time.Time, ttl time.Duration, isPlayerConnected func(playerID string) bool) bool {
+	state := lobby.GetState()
+	if state != game.LobbyStateWaiting && state != game.LobbyStateReady && state != game.LobbyStateClosed {
+		return false
+	}
+	if now.Sub(lobby.LastActivity()) < ttl {
+		return false
+	}
+	for _, p := range lobby.GetPlayers() {
+		if !p.Bot && isPlayerConnected(p.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// StartJanitor expires idle lobbies every cfg.Interval in the background until the returned stop function is called
+func (s *lobbyService) StartJanitor(cfg LobbyJanitorConfig) (stop func()) {
+	ticker := time.NewTicker(cfg.Interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				s.ExpireIdleLobbies(now, cfg.IdleTTL, cfg.IsPlayerConnected)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+)
+
+func noneConnected(string) bool { return false }
+
+func TestExpireIdleLobbies_RemovesAbandonedLobbies(t *testing.T) {
+	svc := NewLobbyService()
+
+	waiting, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	ready, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(ready.Code, "player-2", "Player2")
+
+	expired := svc.ExpireIdleLobbies(time.Now().Add(time.Hour), time.Minute, noneConnected)
+	if len(expired) != 2 {
+		t.Fatalf("expected 2 expired lobbies, got %d", len(expired))
+	}
+	for _, code := range []string{waiting.Code, ready.Code} {
+		if _, err := svc.GetLobby(code); !errors.Is(err, ErrLobbyNotFound) {
+			t.Errorf("expected lobby %q to be removed, got %v", code, err)
+		}
+	}
+}
+
+func TestExpireIdleLobbies_KeepsRecentlyActiveLobbies(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	if expired := svc.ExpireIdleLobbies(time.Now(), time.Minute, noneConnected); len(expired) != 0 {
+		t.Errorf("expected no lobby to expire within the TTL, got %d", len(expired))
+	}
+	if _, err := svc.GetLobby(created.Code); err != nil {
+		t.Errorf("expected lobby to remain, got %v", err)
+	}
+}
+
+func TestExpireIdleLobbies_KeepsLobbiesWithConnectedPlayers(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	connected := func(playerID string) bool { return playerID == "player-2" }
+	if expired := svc.ExpireIdleLobbies(time.Now().Add(time.Hour), time.Minute, connected); len(expired) != 0 {
+		t.Errorf("expected a lobby with a connected player to remain, got %d expired", len(expired))
+	}
+}
+
+func TestExpireIdleLobbies_KeepsActiveGames(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+	svc.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+	svc.SubmitTeam(created.Code, "player-2", game.StarterTeam())
+	if err := svc.StartGame(created.Code, "host-1"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	if expired := svc.ExpireIdleLobbies(time.Now().Add(time.Hour), time.Minute, noneConnected); len(expired) != 0 {
+		t.Errorf("expected a lobby with a game in progress to remain, got %d expired", len(expired))
+	}
+}
+
+func TestExpireIdleLobbies_RemovesClosedLobbies(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+	svc.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+	svc.SubmitTeam(created.Code, "player-2", game.StarterTeam())
+	svc.StartGame(created.Code, "host-1")
+	created.End()
+	svc.LeaveLobby(created.Code, "player-2")
+
+	if expired := svc.ExpireIdleLobbies(time.Now().Add(time.Hour), time.Minute, noneConnected); len(expired) != 1 {
+		t.Errorf("expected the closed lobby to expire, got %d expired", len(expired))
+	}
+}
+
+func TestStartJanitor_PublishesExpiredLobbies(t *testing.T) {
+	bus := events.NewBus()
+	svc := NewLobbyServiceWithConfig(LobbyServiceConfig{Events: bus})
+
+	created, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	expired := make(chan *game.Lobby, 1)
+	bus.Subscribe(func(event events.Event) {
+		if e, ok := event.(events.LobbyExpired); ok {
+			expired <- e.Lobby
+		}
+	})
+	stop := svc.StartJanitor(LobbyJanitorConfig{
+		IdleTTL:           time.Nanosecond,
+		Interval:          10 * time.Millisecond,
+		IsPlayerConnected: noneConnected,
+	})
+	defer stop()
+
+	select {
+	case lobby := <-expired:
+		if lobby.Code != created.Code {
+			t.Errorf("expected lobby %q to expire, got %q", created.Code, lobby.Code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the janitor to expire the idle lobby")
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"poke-battles/internal/game"
+)
+
+// DefaultMaxLobbiesPerPlayer is how many lobbies a player may play in at once unless configured otherwise
+const DefaultMaxLobbiesPerPlayer = 1
+
+// ErrTooManyLobbies is matched by LobbyLimitError with errors.Is
+var ErrTooManyLobbies = errors.New("player is already in as many lobbies as allowed")
+
+// LobbyLimitError rejects creating or joining a lobby while the player already plays in as many as allowed.
+// It names the player's most recently active lobby so a client can return to it or leave it.
+type LobbyLimitError struct {
+	PlayerID  string
+	LobbyCode string
+}
+
+// Error names the player and the lobby they are already in
+func (e *LobbyLimitError) Error() string {
+	return fmt.Sprintf("player %q, lobby %q: %s", e.PlayerID, e.LobbyCode, ErrTooManyLobbies)
+}
+
+// Unwrap exposes ErrTooManyLobbies to errors.Is
+func (e *LobbyLimitError) Unwrap() error {
+	return ErrTooManyLobbies
+}
+
+// playerLobbiesLocked returns the lobbies the player is playing in, most recently active first.
+// The caller must hold s.mu.
+func (s *lobbyService) playerLobbiesLocked(playerID string) []*game.Lobby {
+	var lobbies []*game.Lobby
+	for _, lobby := range s.lobbies {
+		if lobby.HasPlayer(playerID) {
+			lobbies = append(lobbies, lobby)
+		}
+	}
+
+	sort.Slice(lobbies, func(i, j int) bool {
+		a, b := lobbies[i].LastActivity(), lobbies[j].LastActivity()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return lobbies[i].Code < lobbies[j].Code
+	})
+	return lobbies
+}
+
+// checkLobbyLimitLocked returns a LobbyLimitError if the player can't take part in another lobby.
+// Lobbies the player is waitlisted for count as well as those they play in. Closed lobbies don't count,
+// and neither does the lobby being joined, so joining it again fails as a duplicate instead.
+// The caller must hold s.mu.
+func (s *lobbyService) checkLobbyLimitLocked(playerID, joiningCode string) error {
+	if s.maxLobbiesPerPlayer <= 0 {
+		return nil
+	}
+
+	var current []*game.Lobby
+	for _, lobby := range s.playerLobbiesLocked(playerID) {
+		if lobby.Code != joiningCode && lobby.GetState() != game.LobbyStateClosed {
+			current = append(current, lobby)
+		}
+	}
+	if len(current) < s.maxLobbiesPerPlayer {
+		for _, lobby := range s.lobbies {
+			if lobby.Code != joiningCode && lobby.WaitlistPosition(playerID) > 0 {
+				current = append(current, lobby)
+			}
+		}
+	}
+
+	if len(current) >= s.maxLobbiesPerPlayer {
+		return &LobbyLimitError{PlayerID: playerID, LobbyCode: current[0].Code}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+)
+
+// QuickJoinFilter narrows the lobbies quick-join may place a player in; zero values match any format
+type QuickJoinFilter struct {
+	RulesetID string // Only lobbies using this ruleset
+	BestOf    int    // Only lobbies playing a best-of-N series of this length
+}
+
+// matches returns true if the lobby is a public, joinable lobby in the requested format
+func (f QuickJoinFilter) matches(lobby *game.Lobby) bool {
+	if lobby.GetVisibility() != game.LobbyVisibilityPublic || !lobby.HasOpenSlot() {
+		return false
+	}
+	if f.RulesetID != "" && lobby.GetRuleset().ID != f.RulesetID {
+		return false
+	}
+	if f.BestOf != 0 && lobby.GetSeries().BestOf != f.BestOf {
+		return false
+	}
+	return true
+}
+
+// QuickJoin puts the player in the oldest public lobby that has room and matches the filter,
+// or creates a new public lobby in that format with the player as host if none does.
+// The returned bool is true when a lobby was created.
+// A player already waiting in a matching lobby gets that lobby back.
+func (s *lobbyService) QuickJoin(playerID, playerUsername string, filter QuickJoinFilter) (*game.Lobby, bool, error) {
+	// Check the format before searching so a bad filter can't silently match nothing and create a lobby
+	var ruleset *game.Ruleset
+	if filter.RulesetID != "" {
+		var err error
+		if ruleset, err = game.LookupRuleset(filter.RulesetID); err != nil {
+			return nil, false, fmt.Errorf("ruleset %q: %w", filter.RulesetID, err)
+		}
+	}
+	if filter.BestOf != 0 && !game.ValidSeriesLength(filter.BestOf) {
+		return nil, false, fmt.Errorf("best of %d: %w", filter.BestOf, game.ErrInvalidSeriesLength)
+	}
+
+	// Hold the write lock throughout so two quick-joins can't both miss a lobby and create one each
+	s.mu.Lock()
+	lobby, created, joined, err := s.quickJoinLocked(playerID, playerUsername, filter, ruleset)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if joined {
+		s.events.Publish(events.PlayerJoined{Lobby: lobby, PlayerID: playerID, Username: playerUsername})
+	}
+	return lobby, created, nil
+}
+
+// quickJoinLocked places the player for QuickJoin, returning whether it created the lobby or added them to one
+// that already existed. The caller must hold s.mu.
+func (s *lobbyService) quickJoinLocked(playerID, playerUsername string, filter QuickJoinFilter, ruleset *game.Ruleset) (lobby *game.Lobby, created, joined bool, err error) {
+	candidates := make([]*game.Lobby, 0)
+	for _, lobby := range s.lobbies {
+		if filter.matches(lobby) {
+			candidates = append(candidates, lobby)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.Code < b.Code
+	})
+
+	for _, lobby := range candidates {
+		if lobby.HasPlayer(playerID) {
+			return lobby, false, false, nil
+		}
+	}
+	if err := s.checkLobbyLimitLocked(playerID, ""); err != nil {
+		return nil, false, false, err
+	}
+	for _, lobby := range candidates {
+		// A direct join may have taken the slot since the lobby was matched; try the next one
+		if err := lobby.AddPlayer(playerID, playerUsername); err == nil {
+			return lobby, false, true, nil
+		}
+	}
+
+	lobby, err = s.createLobbyLocked("", playerID, playerUsername, game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if ruleset != nil {
+		if err := lobby.SetRuleset(ruleset); err != nil {
+			return nil, false, false, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+		}
+	}
+	if filter.BestOf != 0 {
+		if err := lobby.SetSeriesLength(filter.BestOf); err != nil {
+			return nil, false, false, fmt.Errorf("lobby %q: %w", lobby.Code, err)
+		}
+	}
+
+	return lobby, true, false, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"poke-battles/internal/game"
+)
+
+func TestQuickJoin_JoinsOldestMatchingLobby(t *testing.T) {
+	svc := NewLobbyService()
+
+	oldest, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	newer, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	newer.CreatedAt = oldest.CreatedAt.Add(1)
+
+	lobby, created, err := svc.QuickJoin("player-3", "Player3", QuickJoinFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created {
+		t.Error("expected an existing lobby to be joined")
+	}
+	if lobby.Code != oldest.Code {
+		t.Errorf("expected to join oldest lobby %q, got %q", oldest.Code, lobby.Code)
+	}
+	if !lobby.HasPlayer("player-3") {
+		t.Error("expected player to be in the lobby")
+	}
+}
+
+func TestQuickJoin_SkipsUnjoinableLobbies(t *testing.T) {
+	svc := NewLobbyService()
+
+	svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityUnlisted)
+	full, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(full.Code, "player-2", "Player2")
+
+	lobby, created, err := svc.QuickJoin("player-3", "Player3", QuickJoinFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !created {
+		t.Errorf("expected a new lobby, got existing lobby %q", lobby.Code)
+	}
+	if !lobby.IsHost("player-3") {
+		t.Error("expected the player to host the new lobby")
+	}
+	if lobby.GetVisibility() != game.LobbyVisibilityPublic {
+		t.Errorf("expected new lobby to be public, got %q", lobby.GetVisibility())
+	}
+}
+
+func TestQuickJoin_MatchesFormat(t *testing.T) {
+	svc := NewLobbyService()
+
+	standard, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	competitive, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.SetRuleset(competitive.Code, "host-2", "competitive")
+	svc.SetSeriesLength(competitive.Code, "host-2", 3)
+
+	lobby, created, _ := svc.QuickJoin("player-3", "Player3", QuickJoinFilter{RulesetID: "competitive", BestOf: 3})
+	if created || lobby.Code != competitive.Code {
+		t.Errorf("expected to join competitive lobby %q, got %q (created %v)", competitive.Code, lobby.Code, created)
+	}
+
+	// No waiting lobby plays a best-of-5, so one is created in that format
+	lobby, created, _ = svc.QuickJoin("player-4", "Player4", QuickJoinFilter{BestOf: 5})
+	if !created || lobby.Code == standard.Code {
+		t.Fatalf("expected a new lobby, got %q (created %v)", lobby.Code, created)
+	}
+	if lobby.GetSeries().BestOf != 5 {
+		t.Errorf("expected new lobby to be best of 5, got %d", lobby.GetSeries().BestOf)
+	}
+	if lobby.GetRuleset().ID != game.DefaultRulesetID {
+		t.Errorf("expected default ruleset, got %q", lobby.GetRuleset().ID)
+	}
+}
+
+func TestQuickJoin_ReturnsLobbyPlayerIsWaitingIn(t *testing.T) {
+	svc := NewLobbyService()
+
+	first, _, _ := svc.QuickJoin("player-1", "Player1", QuickJoinFilter{})
+	again, created, err := svc.QuickJoin("player-1", "Player1", QuickJoinFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created || again.Code != first.Code {
+		t.Errorf("expected lobby %q back, got %q (created %v)", first.Code, again.Code, created)
+	}
+	if again.PlayerCount() != 1 {
+		t.Errorf("expected 1 player, got %d", again.PlayerCount())
+	}
+}
+
+func TestQuickJoin_InvalidFilter(t *testing.T) {
+	svc := NewLobbyService()
+
+	tests := []struct {
+		name     string
+		filter   QuickJoinFilter
+		expected error
+	}{
+		{"unknown ruleset", QuickJoinFilter{RulesetID: "anything-goes"}, game.ErrUnknownRuleset},
+		{"invalid series length", QuickJoinFilter{BestOf: 2}, game.ErrInvalidSeriesLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.QuickJoin("player-1", "Player1", tt.filter); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if lobbies, _ := svc.ListLobbies(LobbyFilter{}); len(lobbies) != 0 {
+		t.Errorf("expected no lobby to be created, got %d", len(lobbies))
+	}
+}
+
+func TestQuickJoin_Concurrent(t *testing.T) {
+	svc := NewLobbyService()
+
+	const players = 10
+	var wg sync.WaitGroup
+	for i := 0; i < players; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("player-%d", i)
+			if _, _, err := svc.QuickJoin(id, id, QuickJoinFilter{}); err != nil {
+				t.Errorf("QuickJoin(%q): %v", id, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Every lobby should be filled in pairs rather than each player getting their own
+	lobbies, _ := svc.ListLobbies(LobbyFilter{})
+	if len(lobbies) != players/game.DefaultMaxPlayers {
+		t.Errorf("expected %d lobbies, got %d", players/game.DefaultMaxPlayers, len(lobbies))
+	}
+	for _, lobby := range lobbies {
+		if lobby.PlayerCount() != game.DefaultMaxPlayers {
+			t.Errorf("expected lobby %q to be full, has %d players", lobby.Code, lobby.PlayerCount())
+		}
+	}
+}
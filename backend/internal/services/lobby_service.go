@@ -3,55 +3,158 @@ package services
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
+	"poke-battles/internal/events"
 	"poke-battles/internal/game"
 )
 
 // Sentinel errors for error type checking with errors.Is()
 var (
-	ErrLobbyNotFound = errors.New("lobby not found")
-	ErrNotHost       = errors.New("only host can start the game")
+	ErrLobbyNotFound      = errors.New("lobby not found")
+	ErrNotHost            = errors.New("only host can start the game")
+	ErrNotHostForRuleset  = errors.New("only host can change the ruleset")
+	ErrNotHostForSeries   = errors.New("only host can change the series length")
+	ErrNotHostForRematch  = errors.New("only host can change the rematch teams")
+	ErrNotHostForDraft    = errors.New("only host can change draft mode")
+	ErrNotHostForBot      = errors.New("only host can add a bot")
+	ErrNotHostForSettings = errors.New("only host can change the lobby settings")
+	ErrNotHostForDelete   = errors.New("only host can delete the lobby")
+	ErrNotHostForInvite   = errors.New("only host can create invites")
+	ErrPlayerHasNoLobby   = errors.New("player is not in a lobby")
+	ErrRoomCodeTaken      = errors.New("room code is already in use")
 )
 
+// LobbySettingsUpdate lists the lobby settings to change; nil fields are left as they are.
+// The ruleset is given by ID and looked up in the ruleset catalogue.
+type LobbySettingsUpdate struct {
+	game.LobbySettingsUpdate
+	RulesetID *string
+}
+
 // LobbyService defines the interface for lobby operations
 type LobbyService interface {
-	CreateLobby(hostID, hostUsername string) (*game.Lobby, error)
+	CreateLobby(hostID, hostUsername string, maxPlayers int, visibility game.LobbyVisibility) (*game.Lobby, error)
+	CreateLobbyWithCode(code, hostID, hostUsername string, maxPlayers int, visibility game.LobbyVisibility) (*game.Lobby, error)
 	JoinLobby(code, playerID, playerUsername string) (*game.Lobby, error)
-	LeaveLobby(code, playerID string) error
+	JoinLobbyWithInvite(code, token, playerID, playerUsername string) (*game.Lobby, error)
+	SpectateLobby(code, playerID, playerUsername string) (*game.Lobby, error)
+	QuickJoin(playerID, playerUsername string, filter QuickJoinFilter) (*game.Lobby, bool, error)
+	CreateInvite(code, playerID string) (game.Invite, error)
+	JoinWaitlist(code, playerID, playerUsername string) (*game.Lobby, error)
+	LeaveLobby(code, playerID string) ([]game.WaitlistEntry, error)
+	DeleteLobby(code, playerID string) (*game.Lobby, error)
 	GetLobby(code string) (*game.Lobby, error)
+	FindPlayerLobby(playerID string) (*game.Lobby, error)
 	StartGame(code, playerID string) error
-	ListLobbies() ([]*game.Lobby, error)
+	ListLobbies(filter LobbyFilter) ([]*game.Lobby, error)
+	SubmitTeam(code, playerID string, team []game.TeamMember) (*game.Lobby, error)
+	SetReady(code, playerID string, ready bool) (*game.Lobby, error)
+	SetRuleset(code, playerID, rulesetID string) (*game.Lobby, error)
+	SetSeriesLength(code, playerID string, bestOf int) (*game.Lobby, error)
+	SetRematchTeams(code, playerID string, teams game.RematchTeams) (*game.Lobby, error)
+	UpdateSettings(code, playerID string, update LobbySettingsUpdate) (*game.Lobby, error)
+	Rematch(code string) (*game.Lobby, error)
+	SetDraftMode(code, playerID string, enabled bool) (*game.Lobby, error)
+	StartDraft(code string) (*game.Lobby, bool, error)
+	SubmitDraftPick(code, playerID, speciesID string) (*game.Lobby, error)
+	ExpireDraftTurn(code string, step int) (*game.Lobby, bool, error)
+	AddBot(code, playerID string, difficulty game.BotDifficulty) (*game.Lobby, error)
+	ExpireIdleLobbies(now time.Time, ttl time.Duration, isPlayerConnected func(playerID string) bool) []*game.Lobby
+	StartJanitor(cfg LobbyJanitorConfig) (stop func())
+}
+
+// LobbyServiceConfig configures a lobby service
+type LobbyServiceConfig struct {
+	// MaxLobbiesPerPlayer is how many lobbies a player may play in at once; 0 for no limit
+	MaxLobbiesPerPlayer int
+	// RoomCodeLength is the length of generated room codes, between game.MinRoomCodeLength and game.MaxRoomCodeLength;
+	// 0 for game.DefaultRoomCodeLength
+	RoomCodeLength int
+	// Events is where changes to lobbies are published once they are made; nil publishes nothing
+	Events *events.Bus
 }
 
 // lobbyService implements LobbyService with in-memory storage
 type lobbyService struct {
 	mu      sync.RWMutex
 	lobbies map[string]*game.Lobby
+	// maxLobbiesPerPlayer is how many lobbies a player may play in at once; 0 for no limit
+	maxLobbiesPerPlayer int
+	roomCodeLength      int
+	// events is published to after s.mu is released, so subscribers may call back into the service
+	events *events.Bus
 }
 
-// NewLobbyService creates a new lobby service instance
+// NewLobbyService creates a new lobby service instance that lets each player play in DefaultMaxLobbiesPerPlayer lobbies at once
 func NewLobbyService() LobbyService {
+	return NewLobbyServiceWithConfig(LobbyServiceConfig{MaxLobbiesPerPlayer: DefaultMaxLobbiesPerPlayer})
+}
+
+// NewLobbyServiceWithConfig creates a new lobby service instance with the given limits, room code length and event bus
+func NewLobbyServiceWithConfig(cfg LobbyServiceConfig) LobbyService {
+	roomCodeLength := cfg.RoomCodeLength
+	if roomCodeLength == 0 {
+		roomCodeLength = game.DefaultRoomCodeLength
+	}
 	return &lobbyService{
-		lobbies: make(map[string]*game.Lobby),
+		lobbies:             make(map[string]*game.Lobby),
+		maxLobbiesPerPlayer: cfg.MaxLobbiesPerPlayer,
+		roomCodeLength:      roomCodeLength,
+		events:              cfg.Events,
 	}
 }
 
-// CreateLobby creates a new lobby with the given host
-func (s *lobbyService) CreateLobby(hostID, hostUsername string) (*game.Lobby, error) {
+// CreateLobby creates a new lobby for up to maxPlayers with the given host and visibility
+func (s *lobbyService) CreateLobby(hostID, hostUsername string, maxPlayers int, visibility game.LobbyVisibility) (*game.Lobby, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.createLobbyLocked("", hostID, hostUsername, maxPlayers, visibility)
+}
+
+// CreateLobbyWithCode creates a new lobby like CreateLobby, under a custom room code chosen by the host.
+// The code is checked against the room code rules and must not belong to another lobby.
+func (s *lobbyService) CreateLobbyWithCode(code, hostID, hostUsername string, maxPlayers int, visibility game.LobbyVisibility) (*game.Lobby, error) {
+	parsed, err := game.ParseRoomCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.lobbies[parsed]; exists {
+		return nil, fmt.Errorf("lobby %q: %w", parsed, ErrRoomCodeTaken)
+	}
+
+	return s.createLobbyLocked(parsed, hostID, hostUsername, maxPlayers, visibility)
+}
+
+// createLobbyLocked stores a new lobby under the given room code, or a fresh random one if it is empty.
+// The caller must hold s.mu.
+func (s *lobbyService) createLobbyLocked(code, hostID, hostUsername string, maxPlayers int, visibility game.LobbyVisibility) (*game.Lobby, error) {
+	if err := s.checkLobbyLimitLocked(hostID, ""); err != nil {
+		return nil, err
+	}
+
 	// Generate a unique room code
-	var code string
-	for {
-		code = game.GenerateRoomCode()
-		if _, exists := s.lobbies[code]; !exists {
-			break
+	for code == "" {
+		code = game.GenerateRoomCodeOfLength(s.roomCodeLength)
+		if _, exists := s.lobbies[code]; exists {
+			code = ""
 		}
 	}
 
-	lobby := game.NewLobby(code, hostID, hostUsername)
+	lobby, err := game.NewLobbyWithMaxPlayers(code, hostID, hostUsername, maxPlayers)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+	if err := lobby.SetVisibility(visibility); err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
 	s.lobbies[code] = lobby
 
 	return lobby, nil
@@ -59,33 +162,158 @@ func (s *lobbyService) CreateLobby(hostID, hostUsername string) (*game.Lobby, er
 
 // JoinLobby adds a player to an existing lobby
 func (s *lobbyService) JoinLobby(code, playerID, playerUsername string) (*game.Lobby, error) {
-	s.mu.RLock()
+	return s.joinLobby(code, playerID, playerUsername, func(lobby *game.Lobby) error {
+		return lobby.AddPlayer(playerID, playerUsername)
+	})
+}
+
+// JoinLobbyWithInvite adds a player to a lobby using one of its single-use invites
+func (s *lobbyService) JoinLobbyWithInvite(code, token, playerID, playerUsername string) (*game.Lobby, error) {
+	return s.joinLobby(code, playerID, playerUsername, func(lobby *game.Lobby) error {
+		return lobby.AddPlayerWithInvite(playerID, playerUsername, token, time.Now())
+	})
+}
+
+// joinLobby adds a player to an existing lobby with add, within their lobby limit, and publishes PlayerJoined
+func (s *lobbyService) joinLobby(code, playerID, playerUsername string, add func(lobby *game.Lobby) error) (*game.Lobby, error) {
+	// Hold the write lock so a player can't join two lobbies at once past the limit
+	s.mu.Lock()
+	lobby, err := s.joinLobbyLocked(code, playerID, add)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.PlayerJoined{Lobby: lobby, PlayerID: playerID, Username: playerUsername})
+	return lobby, nil
+}
+
+// joinLobbyLocked adds a player to an existing lobby with add. The caller must hold s.mu.
+func (s *lobbyService) joinLobbyLocked(code, playerID string, add func(lobby *game.Lobby) error) (*game.Lobby, error) {
 	lobby, exists := s.lobbies[code]
-	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("lobby %q: %w", code, ErrLobbyNotFound)
+	}
+
+	if err := s.checkLobbyLimitLocked(playerID, code); err != nil {
+		return nil, err
+	}
+
+	if err := add(lobby); err != nil {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
+	}
+
+	return lobby, nil
+}
+
+// CreateInvite issues a single-use invite to the lobby (host only)
+func (s *lobbyService) CreateInvite(code, playerID string) (game.Invite, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return game.Invite{}, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return game.Invite{}, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForInvite)
+	}
+
+	invite, err := lobby.CreateInvite(time.Now())
+	if err != nil {
+		return game.Invite{}, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	return invite, nil
+}
+
+// SpectateLobby adds someone to a lobby's spectator roster
+func (s *lobbyService) SpectateLobby(code, playerID, playerUsername string) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lobby.AddSpectator(playerID, playerUsername); err != nil {
+		return nil, fmt.Errorf("lobby %q, spectator %q: %w", code, playerID, err)
+	}
+
+	s.events.Publish(events.SpectatorJoined{Lobby: lobby, SpectatorID: playerID, Username: playerUsername})
+	return lobby, nil
+}
 
+// JoinWaitlist queues a player for the next free slot in a full lobby
+func (s *lobbyService) JoinWaitlist(code, playerID, playerUsername string) (*game.Lobby, error) {
+	s.mu.Lock()
+	lobby, err := s.joinWaitlistLocked(code, playerID, playerUsername)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.WaitlistJoined{Lobby: lobby, PlayerID: playerID, Username: playerUsername})
+	return lobby, nil
+}
+
+// joinWaitlistLocked queues a player for the next free slot in a full lobby. The caller must hold s.mu.
+func (s *lobbyService) joinWaitlistLocked(code, playerID, playerUsername string) (*game.Lobby, error) {
+	lobby, exists := s.lobbies[code]
 	if !exists {
 		return nil, fmt.Errorf("lobby %q: %w", code, ErrLobbyNotFound)
 	}
 
-	if err := lobby.AddPlayer(playerID, playerUsername); err != nil {
+	// A waitlisted player is promoted without asking, so the waitlist counts towards their lobbies
+	if err := s.checkLobbyLimitLocked(playerID, code); err != nil {
+		return nil, err
+	}
+
+	if err := lobby.JoinWaitlist(playerID, playerUsername); err != nil {
 		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
 	}
 
 	return lobby, nil
 }
 
-// LeaveLobby removes a player from a lobby and cleans up empty lobbies
-func (s *lobbyService) LeaveLobby(code, playerID string) error {
+// LeaveLobby removes a player, spectator or waitlisted player from a lobby and cleans up empty lobbies.
+// It returns the waitlisted players promoted into the slot a leaving player freed.
+func (s *lobbyService) LeaveLobby(code, playerID string) ([]game.WaitlistEntry, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	lobby, left, promoted, err := s.leaveLobbyLocked(code, playerID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
 
+	s.events.Publish(left)
+	if len(promoted) > 0 {
+		s.events.Publish(events.WaitlistPromoted{Lobby: lobby, Promoted: promoted})
+	}
+	return promoted, nil
+}
+
+// leaveLobbyLocked removes someone from a lobby, returning the lobby, the event saying how they left and the
+// waitlisted players promoted in their place. The caller must hold s.mu.
+func (s *lobbyService) leaveLobbyLocked(code, playerID string) (*game.Lobby, events.Event, []game.WaitlistEntry, error) {
 	lobby, exists := s.lobbies[code]
 	if !exists {
-		return fmt.Errorf("lobby %q: %w", code, ErrLobbyNotFound)
+		return nil, nil, nil, fmt.Errorf("lobby %q: %w", code, ErrLobbyNotFound)
+	}
+
+	if lobby.HasSpectator(playerID) {
+		if err := lobby.RemoveSpectator(playerID); err != nil {
+			return nil, nil, nil, fmt.Errorf("lobby %q, spectator %q: %w", code, playerID, err)
+		}
+		return lobby, events.SpectatorLeft{Lobby: lobby, SpectatorID: playerID}, nil, nil
 	}
 
-	if err := lobby.RemovePlayer(playerID); err != nil {
-		return fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
+	if lobby.WaitlistPosition(playerID) > 0 {
+		if err := lobby.LeaveWaitlist(playerID); err != nil {
+			return nil, nil, nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
+		}
+		return lobby, events.WaitlistLeft{Lobby: lobby, PlayerID: playerID}, nil, nil
+	}
+
+	promoted, err := lobby.RemovePlayer(playerID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
 	}
 
 	// Clean up empty lobbies
@@ -93,7 +321,38 @@ func (s *lobbyService) LeaveLobby(code, playerID string) error {
 		delete(s.lobbies, code)
 	}
 
-	return nil
+	return lobby, events.PlayerLeft{Lobby: lobby, PlayerID: playerID}, promoted, nil
+}
+
+// DeleteLobby removes a lobby at its host's request, whatever its state, and returns the removed lobby
+func (s *lobbyService) DeleteLobby(code, playerID string) (*game.Lobby, error) {
+	s.mu.Lock()
+	lobby, err := s.deleteLobbyLocked(code, playerID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.LobbyDeleted{Lobby: lobby})
+	return lobby, nil
+}
+
+// deleteLobbyLocked removes a lobby at its host's request. The caller must hold s.mu.
+func (s *lobbyService) deleteLobbyLocked(code, playerID string) (*game.Lobby, error) {
+	lobby, exists := s.lobbies[code]
+	if !exists {
+		return nil, fmt.Errorf("lobby %q: %w", code, ErrLobbyNotFound)
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForDelete)
+	}
+
+	// Connections still holding the lobby see that its game is over; a lobby whose game
+	// hasn't started has nothing to close
+	_ = lobby.Close()
+	delete(s.lobbies, code)
+	return lobby, nil
 }
 
 // GetLobby retrieves a lobby by its code
@@ -109,18 +368,298 @@ func (s *lobbyService) GetLobby(code string) (*game.Lobby, error) {
 	return lobby, nil
 }
 
-// ListLobbies retrieves a list of all lobbies
-func (s *lobbyService) ListLobbies() ([]*game.Lobby, error) {
+// FindPlayerLobby retrieves the lobby the player is playing in. If they are in several,
+// the one with the most recent activity is returned.
+func (s *lobbyService) FindPlayerLobby(playerID string) (*game.Lobby, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lobbies := s.playerLobbiesLocked(playerID)
+	if len(lobbies) == 0 {
+		return nil, fmt.Errorf("player %q: %w", playerID, ErrPlayerHasNoLobby)
+	}
+	return lobbies[0], nil
+}
+
+// LobbySortField is the timestamp the lobby list is ordered by
+type LobbySortField string
+
+const (
+	LobbySortCreatedAt    LobbySortField = "created_at"       // When the lobby was created (the default)
+	LobbySortLastActivity LobbySortField = "last_activity_at" // When the lobby last changed
+)
+
+// LobbyFilter narrows and orders the lobby list; zero values match every lobby
+type LobbyFilter struct {
+	State       *game.LobbyState // Only lobbies in this state
+	HasOpenSlot *bool            // Only lobbies that can (or cannot) take another player
+	BestOf      int              // Only lobbies playing a best-of-N series of this length; 0 for any
+	Tags        []game.LobbyTag  // Only lobbies carrying every one of these tags
+	SortBy      LobbySortField   // Timestamp to sort by; empty for LobbySortCreatedAt
+	NewestFirst bool             // Sort newest first instead of oldest first
+}
+
+// SortKey returns the timestamp the lobby is ordered by
+func (f LobbyFilter) SortKey(lobby *game.Lobby) time.Time {
+	if f.SortBy == LobbySortLastActivity {
+		return lobby.LastActivity()
+	}
+	return lobby.CreatedAt
+}
+
+// matches returns true if the lobby passes every filter
+func (f LobbyFilter) matches(lobby *game.Lobby) bool {
+	if f.State != nil && lobby.GetState() != *f.State {
+		return false
+	}
+	if f.HasOpenSlot != nil && lobby.HasOpenSlot() != *f.HasOpenSlot {
+		return false
+	}
+	if f.BestOf != 0 && lobby.GetSeries().BestOf != f.BestOf {
+		return false
+	}
+	if len(f.Tags) > 0 && !lobby.HasTags(f.Tags) {
+		return false
+	}
+	return true
+}
+
+// ListLobbies retrieves the public lobbies matching the filter, sorted by creation or last activity time.
+// Unlisted lobbies are only reachable by code, and closed lobbies are left out unless the filter asks for them.
+func (s *lobbyService) ListLobbies(filter LobbyFilter) ([]*game.Lobby, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	lobbies := make([]*game.Lobby, 0, len(s.lobbies))
 	for _, lobby := range s.lobbies {
+		if lobby.GetVisibility() == game.LobbyVisibilityUnlisted || !filter.matches(lobby) {
+			continue
+		}
+		if filter.State == nil && lobby.GetState() == game.LobbyStateClosed {
+			continue
+		}
 		lobbies = append(lobbies, lobby)
 	}
+
+	sort.Slice(lobbies, func(i, j int) bool {
+		a, b := lobbies[i], lobbies[j]
+		if filter.NewestFirst {
+			a, b = b, a
+		}
+		if ta, tb := filter.SortKey(a), filter.SortKey(b); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.Code < b.Code
+	})
 	return lobbies, nil
 }
 
+// SubmitTeam validates and stores a player's team for the lobby's next game
+func (s *lobbyService) SubmitTeam(code, playerID string, team []game.TeamMember) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lobby.SubmitTeam(playerID, team); err != nil {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
+	}
+
+	s.events.Publish(events.TeamSubmitted{Lobby: lobby, PlayerID: playerID})
+	return lobby, nil
+}
+
+// SetReady marks a player in a lobby ready or not ready for the game to start
+func (s *lobbyService) SetReady(code, playerID string, ready bool) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lobby.SetReady(playerID, ready); err != nil {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, err)
+	}
+
+	s.events.Publish(events.ReadyChanged{Lobby: lobby, PlayerID: playerID, Ready: ready})
+	return lobby, nil
+}
+
+// SetRuleset selects the team legality rules for a lobby (host only)
+func (s *lobbyService) SetRuleset(code, playerID, rulesetID string) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForRuleset)
+	}
+
+	ruleset, err := game.LookupRuleset(rulesetID)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q, ruleset %q: %w", code, rulesetID, err)
+	}
+
+	if err := lobby.SetRuleset(ruleset); err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	s.events.Publish(events.SettingsChanged{Lobby: lobby})
+	return lobby, nil
+}
+
+// SetSeriesLength configures a lobby as a best-of-N series (host only)
+func (s *lobbyService) SetSeriesLength(code, playerID string, bestOf int) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForSeries)
+	}
+
+	if err := lobby.SetSeriesLength(bestOf); err != nil {
+		return nil, fmt.Errorf("lobby %q, best of %d: %w", code, bestOf, err)
+	}
+
+	s.events.Publish(events.SettingsChanged{Lobby: lobby})
+	return lobby, nil
+}
+
+// SetRematchTeams configures whether a lobby's rematches keep the submitted teams (host only)
+func (s *lobbyService) SetRematchTeams(code, playerID string, teams game.RematchTeams) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForRematch)
+	}
+
+	if err := lobby.SetRematchTeams(teams); err != nil {
+		return nil, fmt.Errorf("lobby %q, rematch teams %q: %w", code, teams, err)
+	}
+
+	s.events.Publish(events.SettingsChanged{Lobby: lobby})
+	return lobby, nil
+}
+
+// UpdateSettings changes a waiting lobby's settings (host only)
+func (s *lobbyService) UpdateSettings(code, playerID string, update LobbySettingsUpdate) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForSettings)
+	}
+
+	if update.RulesetID != nil {
+		ruleset, err := game.LookupRuleset(*update.RulesetID)
+		if err != nil {
+			return nil, fmt.Errorf("lobby %q, ruleset %q: %w", code, *update.RulesetID, err)
+		}
+		update.Ruleset = ruleset
+	}
+
+	if _, err := lobby.UpdateSettings(update.LobbySettingsUpdate); err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	s.events.Publish(events.SettingsChanged{Lobby: lobby})
+	return lobby, nil
+}
+
+// Rematch returns a finished lobby to ready so its players can play the next game
+func (s *lobbyService) Rematch(code string) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lobby.Rematch(); err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	return lobby, nil
+}
+
+// SetDraftMode turns the ban/pick draft on or off for a lobby (host only)
+func (s *lobbyService) SetDraftMode(code, playerID string, enabled bool) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForDraft)
+	}
+
+	if err := lobby.SetDraftMode(enabled); err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	s.events.Publish(events.SettingsChanged{Lobby: lobby})
+	return lobby, nil
+}
+
+// StartDraft begins a draft-mode lobby's draft, returning false if it could not start
+func (s *lobbyService) StartDraft(code string) (*game.Lobby, bool, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return lobby, lobby.StartDraft(), nil
+}
+
+// SubmitDraftPick bans or picks a species on the player's draft turn
+func (s *lobbyService) SubmitDraftPick(code, playerID, speciesID string) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lobby.SubmitDraftPick(playerID, speciesID); err != nil {
+		return nil, fmt.Errorf("lobby %q, player %q, species %q: %w", code, playerID, speciesID, err)
+	}
+
+	return lobby, nil
+}
+
+// ExpireDraftTurn resolves a timed-out draft turn, returning false if the draft had moved on
+func (s *lobbyService) ExpireDraftTurn(code string, step int) (*game.Lobby, bool, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return lobby, lobby.ExpireDraftTurn(step), nil
+}
+
+// AddBot fills a lobby's open slot with a bot opponent of the given difficulty (host only)
+func (s *lobbyService) AddBot(code, playerID string, difficulty game.BotDifficulty) (*game.Lobby, error) {
+	lobby, err := s.GetLobby(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lobby.IsHost(playerID) {
+		return nil, fmt.Errorf("lobby %q, player %q: %w", code, playerID, ErrNotHostForBot)
+	}
+
+	bot, err := lobby.AddBot(difficulty)
+	if err != nil {
+		return nil, fmt.Errorf("lobby %q: %w", code, err)
+	}
+
+	s.events.Publish(events.PlayerJoined{Lobby: lobby, PlayerID: bot.ID, Username: bot.Username})
+	return lobby, nil
+}
+
 // StartGame starts the game for a lobby (host only)
 func (s *lobbyService) StartGame(code, playerID string) error {
 	s.mu.RLock()
@@ -139,5 +678,6 @@ func (s *lobbyService) StartGame(code, playerID string) error {
 		return fmt.Errorf("lobby %q: %w", code, err)
 	}
 
+	s.events.Publish(events.GameStarting{Lobby: lobby})
 	return nil
 }
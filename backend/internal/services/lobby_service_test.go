@@ -5,7 +5,9 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"poke-battles/internal/events"
 	"poke-battles/internal/game"
 )
 
@@ -16,7 +18,7 @@ import (
 func TestCreateLobby_Success(t *testing.T) {
 	svc := NewLobbyService()
 
-	lobby, err := svc.CreateLobby("host-1", "HostPlayer")
+	lobby, err := svc.CreateLobby("host-1", "HostPlayer", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -35,12 +37,57 @@ func TestCreateLobby_Success(t *testing.T) {
 	}
 }
 
+func TestCreateLobby_InvalidMaxPlayers(t *testing.T) {
+	svc := NewLobbyService()
+
+	if _, err := svc.CreateLobby("host-1", "HostPlayer", game.MaxLobbyPlayers+1, game.LobbyVisibilityPublic); !errors.Is(err, game.ErrInvalidMaxPlayers) {
+		t.Errorf("expected ErrInvalidMaxPlayers, got %v", err)
+	}
+	if lobbies, _ := svc.ListLobbies(LobbyFilter{}); len(lobbies) != 0 {
+		t.Error("expected no lobby to be stored")
+	}
+}
+
+func TestCreateLobbyWithCode(t *testing.T) {
+	svc := NewLobbyService()
+
+	lobby, err := svc.CreateLobbyWithCode("streamer22", "host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.Code != "STREAMER22" {
+		t.Errorf("expected code STREAMER22, got %q", lobby.Code)
+	}
+	if _, err := svc.GetLobby("STREAMER22"); err != nil {
+		t.Errorf("expected lobby to be stored under its custom code, got %v", err)
+	}
+
+	if _, err := svc.CreateLobbyWithCode("STREAMER22", "host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic); !errors.Is(err, ErrRoomCodeTaken) {
+		t.Errorf("expected ErrRoomCodeTaken, got %v", err)
+	}
+	if _, err := svc.CreateLobbyWithCode("NO!", "host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic); !errors.Is(err, game.ErrInvalidRoomCode) {
+		t.Errorf("expected ErrInvalidRoomCode, got %v", err)
+	}
+}
+
+func TestCreateLobby_ConfiguredCodeLength(t *testing.T) {
+	svc := NewLobbyServiceWithConfig(LobbyServiceConfig{RoomCodeLength: 8})
+
+	lobby, err := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(lobby.Code) != 8 {
+		t.Errorf("expected an 8 character code, got %q", lobby.Code)
+	}
+}
+
 func TestCreateLobby_UniqueRoomCodes(t *testing.T) {
 	svc := NewLobbyService()
 	codes := make(map[string]bool)
 
 	for i := 0; i < 100; i++ {
-		lobby, err := svc.CreateLobby("host-"+string(rune('0'+i)), "Host")
+		lobby, err := svc.CreateLobby("host-"+string(rune('0'+i)), "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 		if err != nil {
 			t.Fatalf("create failed: %v", err)
 		}
@@ -54,7 +101,7 @@ func TestCreateLobby_UniqueRoomCodes(t *testing.T) {
 func TestJoinLobby_Success(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 
 	lobby, err := svc.JoinLobby(created.Code, "player-2", "Player2")
 	if err != nil {
@@ -75,10 +122,10 @@ func TestJoinLobby_Success(t *testing.T) {
 func TestLeaveLobby_Success(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	svc.JoinLobby(created.Code, "player-2", "Player2")
 
-	err := svc.LeaveLobby(created.Code, "player-2")
+	_, err := svc.LeaveLobby(created.Code, "player-2")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -95,10 +142,10 @@ func TestLeaveLobby_Success(t *testing.T) {
 func TestLeaveLobby_DeletesEmptyLobby(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	code := created.Code
 
-	err := svc.LeaveLobby(code, "host-1")
+	_, err := svc.LeaveLobby(code, "host-1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -112,7 +159,7 @@ func TestLeaveLobby_DeletesEmptyLobby(t *testing.T) {
 func TestGetLobby_Success(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 
 	lobby, err := svc.GetLobby(created.Code)
 	if err != nil {
@@ -128,11 +175,11 @@ func TestListLobbies_Success(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Create multiple lobbies
-	lobby1, _ := svc.CreateLobby("host-1", "Host1")
-	lobby2, _ := svc.CreateLobby("host-2", "Host2")
-	lobby3, _ := svc.CreateLobby("host-3", "Host3")
+	lobby1, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	lobby2, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	lobby3, _ := svc.CreateLobby("host-3", "Host3", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 
-	lobbies, err := svc.ListLobbies()
+	lobbies, err := svc.ListLobbies(LobbyFilter{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -167,7 +214,7 @@ func TestListLobbies_Empty(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Expected behavior: should return empty slice, not error
-	lobbies, err := svc.ListLobbies()
+	lobbies, err := svc.ListLobbies(LobbyFilter{})
 
 	if err != nil {
 		t.Errorf("expected no error for empty lobby list, got %v", err)
@@ -178,11 +225,143 @@ func TestListLobbies_Empty(t *testing.T) {
 	}
 }
 
+func TestListLobbies_ExcludesUnlisted(t *testing.T) {
+	svc := NewLobbyService()
+
+	public, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	unlisted, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityUnlisted)
+
+	lobbies, _ := svc.ListLobbies(LobbyFilter{})
+	if len(lobbies) != 1 || lobbies[0].Code != public.Code {
+		t.Errorf("expected only the public lobby to be listed, got %d lobbies", len(lobbies))
+	}
+
+	// Unlisted lobbies stay joinable by code
+	if _, err := svc.JoinLobby(unlisted.Code, "player-2", "Player2"); err != nil {
+		t.Errorf("expected to join the unlisted lobby by code, got %v", err)
+	}
+}
+
+func TestListLobbies_ExcludesClosed(t *testing.T) {
+	svc := NewLobbyService()
+
+	open, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	closed, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(closed.Code, "player-2", "Player2")
+	svc.SubmitTeam(closed.Code, "host-2", game.StarterTeam())
+	svc.SubmitTeam(closed.Code, "player-2", game.StarterTeam())
+	svc.StartGame(closed.Code, "host-2")
+	closed.Close()
+
+	lobbies, _ := svc.ListLobbies(LobbyFilter{})
+	if len(lobbies) != 1 || lobbies[0].Code != open.Code {
+		t.Errorf("expected only the open lobby to be listed, got %d lobbies", len(lobbies))
+	}
+
+	closedState := game.LobbyStateClosed
+	lobbies, _ = svc.ListLobbies(LobbyFilter{State: &closedState})
+	if len(lobbies) != 1 || lobbies[0].Code != closed.Code {
+		t.Errorf("expected the closed lobby when filtering by state, got %d lobbies", len(lobbies))
+	}
+}
+
+func TestListLobbies_Filters(t *testing.T) {
+	svc := NewLobbyService()
+
+	waiting, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	ready, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(ready.Code, "player-2", "Player2")
+	svc.SetSeriesLength(waiting.Code, "host-1", 3)
+
+	readyState := game.LobbyStateReady
+	openSlot := true
+	tests := []struct {
+		name     string
+		filter   LobbyFilter
+		expected string
+	}{
+		{"state", LobbyFilter{State: &readyState}, ready.Code},
+		{"open slot", LobbyFilter{HasOpenSlot: &openSlot}, waiting.Code},
+		{"best of", LobbyFilter{BestOf: 3}, waiting.Code},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lobbies, err := svc.ListLobbies(tt.filter)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(lobbies) != 1 || lobbies[0].Code != tt.expected {
+				t.Errorf("expected only lobby %q, got %d lobbies", tt.expected, len(lobbies))
+			}
+		})
+	}
+}
+
+func TestListLobbies_FilterByTags(t *testing.T) {
+	svc := NewLobbyService()
+
+	tagged, _ := svc.CreateLobby("host-1", "Host1", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	tags := []game.LobbyTag{game.LobbyTagEurope, game.LobbyTagBeginner}
+	svc.UpdateSettings(tagged.Code, "host-1", LobbySettingsUpdate{LobbySettingsUpdate: game.LobbySettingsUpdate{Tags: &tags}})
+
+	lobbies, _ := svc.ListLobbies(LobbyFilter{Tags: []game.LobbyTag{game.LobbyTagEurope}})
+	if len(lobbies) != 1 || lobbies[0].Code != tagged.Code {
+		t.Errorf("expected only the tagged lobby, got %d lobbies", len(lobbies))
+	}
+
+	lobbies, _ = svc.ListLobbies(LobbyFilter{Tags: []game.LobbyTag{game.LobbyTagEurope, game.LobbyTagExpert}})
+	if len(lobbies) != 0 {
+		t.Errorf("expected lobbies to need every tag, got %d lobbies", len(lobbies))
+	}
+}
+
+func TestListLobbies_SortedByCreatedAt(t *testing.T) {
+	svc := NewLobbyService()
+
+	base := time.Now()
+	var codes []string
+	for i, hostID := range []string{"host-1", "host-2", "host-3"} {
+		lobby, _ := svc.CreateLobby(hostID, "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+		// Created out of order so the result can't rely on insertion order
+		lobby.CreatedAt = base.Add(time.Duration(2-i) * time.Minute)
+		codes = append([]string{lobby.Code}, codes...)
+	}
+
+	lobbies, _ := svc.ListLobbies(LobbyFilter{})
+	for i, lobby := range lobbies {
+		if lobby.Code != codes[i] {
+			t.Errorf("oldest first: position %d = %q, want %q", i, lobby.Code, codes[i])
+		}
+	}
+
+	lobbies, _ = svc.ListLobbies(LobbyFilter{NewestFirst: true})
+	for i, lobby := range lobbies {
+		if want := codes[len(codes)-1-i]; lobby.Code != want {
+			t.Errorf("newest first: position %d = %q, want %q", i, lobby.Code, want)
+		}
+	}
+}
+
+func TestCreateLobby_UnknownVisibility(t *testing.T) {
+	svc := NewLobbyService()
+
+	if _, err := svc.CreateLobby("host-1", "HostPlayer", game.DefaultMaxPlayers, "private"); !errors.Is(err, game.ErrUnknownVisibility) {
+		t.Errorf("expected ErrUnknownVisibility, got %v", err)
+	}
+	if lobbies, _ := svc.ListLobbies(LobbyFilter{}); len(lobbies) != 0 {
+		t.Error("expected no lobby to be stored")
+	}
+}
+
 func TestStartGame_Success(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	svc.JoinLobby(created.Code, "player-2", "Player2")
+	svc.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+	svc.SubmitTeam(created.Code, "player-2", game.StarterTeam())
 
 	err := svc.StartGame(created.Code, "host-1")
 	if err != nil {
@@ -195,6 +374,34 @@ func TestStartGame_Success(t *testing.T) {
 	}
 }
 
+func TestSubmitTeam_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	lobby, err := svc.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.HasTeam("host-1") {
+		t.Error("expected host team to be stored")
+	}
+}
+
+func TestSetReady_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	lobby, err := svc.SetReady(created.Code, "host-1", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.IsReady("host-1") {
+		t.Error("expected host to be ready")
+	}
+}
+
 // ========================================
 // Validation Error Tests
 // ========================================
@@ -211,7 +418,7 @@ func TestJoinLobby_NotFound(t *testing.T) {
 func TestJoinLobby_LobbyFull(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	svc.JoinLobby(created.Code, "player-2", "Player2")
 
 	// When lobby has 2 players, state becomes Ready.
@@ -225,7 +432,7 @@ func TestJoinLobby_LobbyFull(t *testing.T) {
 func TestJoinLobby_PlayerAlreadyJoined(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 
 	_, err := svc.JoinLobby(created.Code, "host-1", "HostAgain")
 	if !errors.Is(err, game.ErrPlayerAlreadyJoined) {
@@ -242,10 +449,153 @@ func TestGetLobby_NotFound(t *testing.T) {
 	}
 }
 
+func TestFindPlayerLobby(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	lobby, err := svc.FindPlayerLobby("player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.Code != created.Code {
+		t.Errorf("expected lobby %q, got %q", created.Code, lobby.Code)
+	}
+
+	_, err = svc.FindPlayerLobby("player-3")
+	if !errors.Is(err, ErrPlayerHasNoLobby) {
+		t.Errorf("expected ErrPlayerHasNoLobby, got %v", err)
+	}
+
+	svc.LeaveLobby(created.Code, "player-2")
+	_, err = svc.FindPlayerLobby("player-2")
+	if !errors.Is(err, ErrPlayerHasNoLobby) {
+		t.Errorf("expected ErrPlayerHasNoLobby after leaving, got %v", err)
+	}
+}
+
+func TestLobbyLimit_RejectsSecondLobby(t *testing.T) {
+	svc := NewLobbyService()
+
+	first, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	other, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	_, err := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	var limitErr *LobbyLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrTooManyLobbies) {
+		t.Fatalf("expected LobbyLimitError creating a second lobby, got %v", err)
+	}
+	if limitErr.LobbyCode != first.Code {
+		t.Errorf("expected error to name lobby %q, got %q", first.Code, limitErr.LobbyCode)
+	}
+
+	if _, err := svc.JoinLobby(other.Code, "host-1", "Host"); !errors.Is(err, ErrTooManyLobbies) {
+		t.Errorf("expected ErrTooManyLobbies joining a second lobby, got %v", err)
+	}
+	if _, _, err := svc.QuickJoin("host-1", "Host", QuickJoinFilter{BestOf: 3}); !errors.Is(err, ErrTooManyLobbies) {
+		t.Errorf("expected ErrTooManyLobbies quick-joining, got %v", err)
+	}
+
+	// Joining the same lobby again is still reported as a duplicate join
+	if _, err := svc.JoinLobby(first.Code, "host-1", "Host"); !errors.Is(err, game.ErrPlayerAlreadyJoined) {
+		t.Errorf("expected ErrPlayerAlreadyJoined, got %v", err)
+	}
+
+	// Spectating doesn't count towards the limit
+	if _, err := svc.SpectateLobby(other.Code, "host-1", "Host"); err != nil {
+		t.Errorf("expected spectating to be allowed, got %v", err)
+	}
+
+	svc.LeaveLobby(first.Code, "host-1")
+	if _, err := svc.JoinLobby(other.Code, "host-1", "Host"); err != nil {
+		t.Errorf("expected join to succeed after leaving, got %v", err)
+	}
+}
+
+func TestJoinWaitlist_PromotedOnLeave(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if _, err := svc.JoinWaitlist(created.Code, "waiter-1", "Waiter"); !errors.Is(err, game.ErrLobbyNotFull) {
+		t.Errorf("expected ErrLobbyNotFull, got %v", err)
+	}
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	lobby, err := svc.JoinWaitlist(created.Code, "waiter-1", "Waiter")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.WaitlistPosition("waiter-1") != 1 {
+		t.Errorf("expected waiter-1 first in line, got position %d", lobby.WaitlistPosition("waiter-1"))
+	}
+	if _, err := svc.JoinWaitlist("NOPE00", "waiter-1", "Waiter"); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+
+	// The waitlist counts towards the lobby limit, so a promotion never takes a player over it
+	other, _ := svc.CreateLobby("host-2", "Host2", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if _, err := svc.JoinLobby(other.Code, "waiter-1", "Waiter"); !errors.Is(err, ErrTooManyLobbies) {
+		t.Errorf("expected ErrTooManyLobbies joining while waitlisted, got %v", err)
+	}
+
+	promoted, err := svc.LeaveLobby(created.Code, "player-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(promoted) != 1 || promoted[0].ID != "waiter-1" {
+		t.Fatalf("expected waiter-1 to be promoted, got %+v", promoted)
+	}
+	if !lobby.HasPlayer("waiter-1") {
+		t.Error("expected waiter-1 to be a player")
+	}
+}
+
+func TestLeaveLobby_Waitlisted(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+	svc.JoinWaitlist(created.Code, "waiter-1", "Waiter")
+
+	promoted, err := svc.LeaveLobby(created.Code, "waiter-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(promoted) != 0 {
+		t.Errorf("expected no promotion when a waitlisted player leaves, got %+v", promoted)
+	}
+	if created.WaitlistPosition("waiter-1") != 0 {
+		t.Error("expected waiter-1 to be off the waitlist")
+	}
+	if created.PlayerCount() != 2 {
+		t.Errorf("expected players to be unaffected, got %d", created.PlayerCount())
+	}
+}
+
+func TestLobbyLimit_Configurable(t *testing.T) {
+	svc := NewLobbyServiceWithConfig(LobbyServiceConfig{MaxLobbiesPerPlayer: 2})
+
+	svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if _, err := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic); err != nil {
+		t.Fatalf("expected second lobby to be allowed, got %v", err)
+	}
+	if _, err := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic); !errors.Is(err, ErrTooManyLobbies) {
+		t.Errorf("expected ErrTooManyLobbies for a third lobby, got %v", err)
+	}
+
+	unlimited := NewLobbyServiceWithConfig(LobbyServiceConfig{})
+	for i := 0; i < 5; i++ {
+		if _, err := unlimited.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic); err != nil {
+			t.Fatalf("expected no limit, got %v", err)
+		}
+	}
+}
+
 func TestLeaveLobby_NotFound(t *testing.T) {
 	svc := NewLobbyService()
 
-	err := svc.LeaveLobby("NOTFOUND", "player-1")
+	_, err := svc.LeaveLobby("NOTFOUND", "player-1")
 	if !errors.Is(err, ErrLobbyNotFound) {
 		t.Errorf("expected ErrLobbyNotFound, got %v", err)
 	}
@@ -254,14 +604,119 @@ func TestLeaveLobby_NotFound(t *testing.T) {
 func TestLeaveLobby_PlayerNotFound(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 
-	err := svc.LeaveLobby(created.Code, "nonexistent")
+	_, err := svc.LeaveLobby(created.Code, "nonexistent")
 	if !errors.Is(err, game.ErrPlayerNotFound) {
 		t.Errorf("expected ErrPlayerNotFound, got %v", err)
 	}
 }
 
+func TestCreateInvite_JoinWithInvite(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityUnlisted)
+	invite, err := svc.CreateInvite(created.Code, "host-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lobby, err := svc.JoinLobbyWithInvite(created.Code, invite.Token, "player-2", "Player2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.HasPlayer("player-2") {
+		t.Error("expected player-2 to be in the lobby")
+	}
+
+	svc.LeaveLobby(created.Code, "player-2")
+	if _, err := svc.JoinLobbyWithInvite(created.Code, invite.Token, "player-3", "Player3"); !errors.Is(err, game.ErrInvalidInvite) {
+		t.Errorf("expected ErrInvalidInvite for a used invite, got %v", err)
+	}
+}
+
+func TestCreateInvite_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	if _, err := svc.CreateInvite(created.Code, "player-2"); !errors.Is(err, ErrNotHostForInvite) {
+		t.Errorf("expected ErrNotHostForInvite, got %v", err)
+	}
+	if _, err := svc.CreateInvite("NOPE00", "host-1"); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+}
+
+func TestSpectateLobby(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	lobby, err := svc.SpectateLobby(created.Code, "watcher-1", "Watcher")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !lobby.HasSpectator("watcher-1") {
+		t.Error("expected watcher-1 to be spectating")
+	}
+
+	// Spectators leave the same way players do, without closing the lobby
+	if _, err := svc.LeaveLobby(created.Code, "watcher-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.HasSpectator("watcher-1") {
+		t.Error("expected watcher-1 to be removed")
+	}
+	if _, err := svc.GetLobby(created.Code); err != nil {
+		t.Errorf("expected lobby to remain, got %v", err)
+	}
+
+	if _, err := svc.SpectateLobby("NOPE00", "watcher-1", "Watcher"); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+}
+
+func TestDeleteLobby_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	deleted, err := svc.DeleteLobby(created.Code, "host-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deleted != created {
+		t.Error("expected the deleted lobby to be returned")
+	}
+	if _, err := svc.GetLobby(created.Code); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound after deletion, got %v", err)
+	}
+}
+
+func TestDeleteLobby_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	if _, err := svc.DeleteLobby(created.Code, "player-2"); !errors.Is(err, ErrNotHostForDelete) {
+		t.Errorf("expected ErrNotHostForDelete, got %v", err)
+	}
+	if _, err := svc.GetLobby(created.Code); err != nil {
+		t.Errorf("expected lobby to remain, got %v", err)
+	}
+}
+
+func TestDeleteLobby_NotFound(t *testing.T) {
+	svc := NewLobbyService()
+
+	if _, err := svc.DeleteLobby("NOTFOUND", "host-1"); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+}
+
 func TestStartGame_NotFound(t *testing.T) {
 	svc := NewLobbyService()
 
@@ -274,7 +729,7 @@ func TestStartGame_NotFound(t *testing.T) {
 func TestStartGame_NotHost(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	svc.JoinLobby(created.Code, "player-2", "Player2")
 
 	err := svc.StartGame(created.Code, "player-2")
@@ -283,10 +738,262 @@ func TestStartGame_NotHost(t *testing.T) {
 	}
 }
 
+func TestStartGame_TeamsNotSubmitted(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+	svc.SubmitTeam(created.Code, "host-1", game.StarterTeam())
+
+	err := svc.StartGame(created.Code, "host-1")
+	if !errors.Is(err, game.ErrTeamsNotSubmitted) {
+		t.Errorf("expected ErrTeamsNotSubmitted, got %v", err)
+	}
+}
+
+func TestSubmitTeam_NotFound(t *testing.T) {
+	svc := NewLobbyService()
+
+	_, err := svc.SubmitTeam("NOTFOUND", "player-1", game.StarterTeam())
+	if !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+}
+
+func TestSetReady_NotFound(t *testing.T) {
+	svc := NewLobbyService()
+
+	_, err := svc.SetReady("NOTFOUND", "player-1", true)
+	if !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected ErrLobbyNotFound, got %v", err)
+	}
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	_, err = svc.SetReady(created.Code, "player-2", true)
+	if !errors.Is(err, game.ErrPlayerNotFound) {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestSubmitTeam_InvalidTeam(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	team := []game.TeamMember{{SpeciesID: "pikachu", Moves: []string{"surf"}}}
+	_, err := svc.SubmitTeam(created.Code, "host-1", team)
+	if !errors.Is(err, game.ErrMoveNotLearnable) {
+		t.Errorf("expected ErrMoveNotLearnable, got %v", err)
+	}
+}
+
+func TestSetRuleset_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	lobby, err := svc.SetRuleset(created.Code, "host-1", "competitive")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetRuleset().ID != "competitive" {
+		t.Errorf("expected ruleset competitive, got %q", lobby.GetRuleset().ID)
+	}
+}
+
+func TestSetRuleset_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	_, err := svc.SetRuleset(created.Code, "player-2", "competitive")
+	if !errors.Is(err, ErrNotHostForRuleset) {
+		t.Errorf("expected ErrNotHostForRuleset, got %v", err)
+	}
+}
+
+func TestSetRuleset_UnknownRuleset(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	_, err := svc.SetRuleset(created.Code, "host-1", "anything-goes")
+	if !errors.Is(err, game.ErrUnknownRuleset) {
+		t.Errorf("expected ErrUnknownRuleset, got %v", err)
+	}
+}
+
+func TestSetSeriesLength_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	lobby, err := svc.SetSeriesLength(created.Code, "host-1", 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lobby.GetSeries().BestOf != 3 {
+		t.Errorf("expected best of 3, got %d", lobby.GetSeries().BestOf)
+	}
+}
+
+func TestSetSeriesLength_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	_, err := svc.SetSeriesLength(created.Code, "player-2", 3)
+	if !errors.Is(err, ErrNotHostForSeries) {
+		t.Errorf("expected ErrNotHostForSeries, got %v", err)
+	}
+}
+
+func TestSetSeriesLength_Invalid(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	_, err := svc.SetSeriesLength(created.Code, "host-1", 2)
+	if !errors.Is(err, game.ErrInvalidSeriesLength) {
+		t.Errorf("expected ErrInvalidSeriesLength, got %v", err)
+	}
+}
+
+func TestUpdateSettings_Success(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	rulesetID := "competitive"
+	visibility := game.LobbyVisibilityUnlisted
+	lobby, err := svc.UpdateSettings(created.Code, "host-1", LobbySettingsUpdate{
+		RulesetID:           &rulesetID,
+		LobbySettingsUpdate: game.LobbySettingsUpdate{Visibility: &visibility},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	settings := lobby.Settings()
+	if settings.Ruleset.ID != "competitive" || settings.Visibility != game.LobbyVisibilityUnlisted {
+		t.Errorf("expected competitive and unlisted, got %q and %q", settings.Ruleset.ID, settings.Visibility)
+	}
+}
+
+func TestUpdateSettings_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", 3, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	_, err := svc.UpdateSettings(created.Code, "player-2", LobbySettingsUpdate{})
+	if !errors.Is(err, ErrNotHostForSettings) {
+		t.Errorf("expected ErrNotHostForSettings, got %v", err)
+	}
+}
+
+func TestUpdateSettings_UnknownRuleset(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	rulesetID := "anything-goes"
+	_, err := svc.UpdateSettings(created.Code, "host-1", LobbySettingsUpdate{RulesetID: &rulesetID})
+	if !errors.Is(err, game.ErrUnknownRuleset) {
+		t.Errorf("expected ErrUnknownRuleset, got %v", err)
+	}
+}
+
+func TestRematch_NotFinished(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	_, err := svc.Rematch(created.Code)
+	if !errors.Is(err, game.ErrInvalidStateForRematch) {
+		t.Errorf("expected ErrInvalidStateForRematch, got %v", err)
+	}
+}
+
+func TestSetDraftMode_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	_, err := svc.SetDraftMode(created.Code, "player-2", true)
+	if !errors.Is(err, ErrNotHostForDraft) {
+		t.Errorf("expected ErrNotHostForDraft, got %v", err)
+	}
+}
+
+func TestDraft_StartAndPick(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if _, err := svc.SetDraftMode(created.Code, "host-1", true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	svc.JoinLobby(created.Code, "player-2", "Player2")
+
+	if _, started, err := svc.StartDraft(created.Code); err != nil || !started {
+		t.Fatalf("expected draft to start, got started=%v err=%v", started, err)
+	}
+
+	_, err := svc.SubmitDraftPick(created.Code, "player-2", "snorlax")
+	if !errors.Is(err, game.ErrNotYourDraftTurn) {
+		t.Errorf("expected ErrNotYourDraftTurn, got %v", err)
+	}
+
+	lobby, err := svc.SubmitDraftPick(created.Code, "host-1", "snorlax")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	draft, _ := lobby.GetDraft()
+	if draft.Step != 1 {
+		t.Errorf("expected draft on step 1, got %d", draft.Step)
+	}
+
+	if _, expired, _ := svc.ExpireDraftTurn(created.Code, 0); expired {
+		t.Error("expected a taken turn not to expire")
+	}
+	if _, expired, _ := svc.ExpireDraftTurn(created.Code, 1); !expired {
+		t.Error("expected the current turn to expire")
+	}
+}
+
+func TestAddBot_NotHost(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+
+	_, err := svc.AddBot(created.Code, "player-2", "")
+	if !errors.Is(err, ErrNotHostForBot) {
+		t.Errorf("expected ErrNotHostForBot, got %v", err)
+	}
+}
+
+func TestAddBot_LobbyRemovedWhenHostLeaves(t *testing.T) {
+	svc := NewLobbyService()
+
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	if _, err := svc.AddBot(created.Code, "host-1", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	svc.LeaveLobby(created.Code, "host-1")
+
+	if _, err := svc.GetLobby(created.Code); !errors.Is(err, ErrLobbyNotFound) {
+		t.Errorf("expected a lobby left with only a bot to be removed, got %v", err)
+	}
+}
+
 func TestStartGame_InvalidState(t *testing.T) {
 	svc := NewLobbyService()
 
-	created, _ := svc.CreateLobby("host-1", "Host")
+	created, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	// Only 1 player, state is Waiting
 
 	err := svc.StartGame(created.Code, "host-1")
@@ -303,7 +1010,7 @@ func TestFullFlow_CreateJoinReady(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Host creates lobby
-	lobby, err := svc.CreateLobby("host-1", "Host")
+	lobby, err := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	if err != nil {
 		t.Fatalf("create failed: %v", err)
 	}
@@ -328,14 +1035,14 @@ func TestFullFlow_CreateJoinLeaveRejoin(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Host creates lobby
-	lobby, _ := svc.CreateLobby("host-1", "Host")
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	code := lobby.Code
 
 	// Player joins
 	svc.JoinLobby(code, "player-2", "Player2")
 
 	// Player leaves
-	err := svc.LeaveLobby(code, "player-2")
+	_, err := svc.LeaveLobby(code, "player-2")
 	if err != nil {
 		t.Fatalf("leave failed: %v", err)
 	}
@@ -359,14 +1066,14 @@ func TestFullFlow_HostLeaveReassign(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Host creates lobby
-	lobby, _ := svc.CreateLobby("host-1", "Host")
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	code := lobby.Code
 
 	// Player joins
 	svc.JoinLobby(code, "player-2", "Player2")
 
 	// Host leaves
-	err := svc.LeaveLobby(code, "host-1")
+	_, err := svc.LeaveLobby(code, "host-1")
 	if err != nil {
 		t.Fatalf("host leave failed: %v", err)
 	}
@@ -385,9 +1092,13 @@ func TestFullFlow_StartAndVerifyActive(t *testing.T) {
 	svc := NewLobbyService()
 
 	// Create and fill lobby
-	lobby, _ := svc.CreateLobby("host-1", "Host")
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	svc.JoinLobby(lobby.Code, "player-2", "Player2")
 
+	// Submit teams
+	svc.SubmitTeam(lobby.Code, "host-1", game.StarterTeam())
+	svc.SubmitTeam(lobby.Code, "player-2", game.StarterTeam())
+
 	// Start game
 	err := svc.StartGame(lobby.Code, "host-1")
 	if err != nil {
@@ -416,7 +1127,7 @@ func TestConcurrent_CreateLobbies(t *testing.T) {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			lobby, err := svc.CreateLobby("host-"+string(rune(id)), "Host")
+			lobby, err := svc.CreateLobby("host-"+string(rune(id)), "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 			if err != nil {
 				atomic.AddInt64(&errorCount, 1)
 				return
@@ -444,7 +1155,7 @@ func TestConcurrent_CreateLobbies(t *testing.T) {
 func TestConcurrent_JoinSameLobby(t *testing.T) {
 	svc := NewLobbyService()
 
-	lobby, _ := svc.CreateLobby("host-1", "Host")
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	code := lobby.Code
 
 	var wg sync.WaitGroup
@@ -479,7 +1190,7 @@ func TestConcurrent_JoinSameLobby(t *testing.T) {
 func TestConcurrent_GetAndModify(t *testing.T) {
 	svc := NewLobbyService()
 
-	lobby, _ := svc.CreateLobby("host-1", "Host")
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	code := lobby.Code
 	svc.JoinLobby(code, "player-2", "Player2")
 
@@ -532,3 +1243,83 @@ func TestConcurrent_GetAndModify(t *testing.T) {
 		t.Errorf("expected state Ready with 2 players, got %v", state)
 	}
 }
+
+// ========================================
+// Event Tests
+// ========================================
+
+// newPublishingLobbyService creates a lobby service that publishes to a bus, returning the names of the events
+// published as "event:id". Each event's lobby is looked up as it arrives, as subscribers do.
+func newPublishingLobbyService(t *testing.T) (LobbyService, *[]string) {
+	t.Helper()
+	bus := events.NewBus()
+	svc := NewLobbyServiceWithConfig(LobbyServiceConfig{MaxLobbiesPerPlayer: DefaultMaxLobbiesPerPlayer, Events: bus})
+	published := &[]string{}
+	bus.Subscribe(func(event events.Event) {
+		svc.GetLobby("ANY") // Must not deadlock on the service's lock
+		id := ""
+		switch e := event.(type) {
+		case events.PlayerJoined:
+			id = e.PlayerID
+		case events.PlayerLeft:
+			id = e.PlayerID
+		case events.SpectatorJoined:
+			id = e.SpectatorID
+		case events.SpectatorLeft:
+			id = e.SpectatorID
+		case events.WaitlistJoined:
+			id = e.PlayerID
+		case events.WaitlistPromoted:
+			id = e.Promoted[0].ID
+		case events.LobbyDeleted:
+			id = e.Lobby.Code
+		}
+		*published = append(*published, event.Name()+":"+id)
+	})
+	return svc, published
+}
+
+func TestLobbyService_PublishesChanges(t *testing.T) {
+	svc, published := newPublishingLobbyService(t)
+
+	lobby, _ := svc.CreateLobby("host-1", "Host", game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
+	code := lobby.Code
+	svc.JoinLobby(code, "player-2", "Player2")
+	svc.JoinLobby(code, "player-3", "Player3") // Full, so nothing is published
+	svc.SpectateLobby(code, "watcher-1", "Watcher")
+	svc.JoinWaitlist(code, "waiter-1", "Waiter")
+	svc.LeaveLobby(code, "watcher-1")
+	svc.LeaveLobby(code, "player-2")
+	svc.DeleteLobby(code, "host-1")
+
+	expected := []string{
+		"player_joined:player-2",
+		"spectator_joined:watcher-1",
+		"waitlist_joined:waiter-1",
+		"spectator_left:watcher-1",
+		"player_left:player-2",
+		"waitlist_promoted:waiter-1",
+		"lobby_deleted:" + code,
+	}
+	if len(*published) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, *published)
+	}
+	for i := range expected {
+		if (*published)[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, *published)
+			break
+		}
+	}
+}
+
+func TestLobbyService_QuickJoinPublishesOnlyJoins(t *testing.T) {
+	svc, published := newPublishingLobbyService(t)
+
+	svc.QuickJoin("player-1", "Player1", QuickJoinFilter{}) // Creates a lobby
+	svc.QuickJoin("player-1", "Player1", QuickJoinFilter{}) // Already in it
+	svc.QuickJoin("player-2", "Player2", QuickJoinFilter{}) // Joins it
+
+	if len(*published) != 1 || (*published)[0] != "player_joined:player-2" {
+		t.Errorf("expected only player-2 joining to be published, got %v", *published)
+	}
+}
@@ -0,0 +1,152 @@
+package sessions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore implements Store in memory, for instances in one process such as in tests
+type memoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*memorySession // Keyed by player ID
+	tokens   map[string]memoryToken    // Keyed by token hash
+}
+
+// memorySession is a stored session and the instance that owns it
+type memorySession struct {
+	Session
+	owner string
+}
+
+// memoryToken is a stored reconnect token
+type memoryToken struct {
+	playerID string
+	expiry   time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() Store {
+	return &memoryStore{
+		sessions: make(map[string]*memorySession),
+		tokens:   make(map[string]memoryToken),
+	}
+}
+
+// Claim makes owner the owner of the player's session in the lobby, starting a new one unless resuming
+func (s *memoryStore) Claim(ctx context.Context, playerID, lobbyCode, owner string, resume bool) (*Session, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[playerID]
+	previousOwner := ""
+	if ok {
+		previousOwner = stored.owner
+	}
+	if ok && resume && stored.LobbyCode == lobbyCode {
+		stored.owner = owner
+		session := stored.Session
+		session.Messages = append([]Message(nil), stored.Messages...)
+		return &session, previousOwner, nil
+	}
+
+	s.sessions[playerID] = &memorySession{Session: Session{LobbyCode: lobbyCode}, owner: owner}
+	return nil, previousOwner, nil
+}
+
+// owned returns the player's session if owner owns it
+func (s *memoryStore) owned(playerID, owner string) (*memorySession, error) {
+	stored, ok := s.sessions[playerID]
+	if !ok || stored.owner != owner {
+		return nil, ErrNotOwner
+	}
+	return stored, nil
+}
+
+// Append records a message sent to the player
+func (s *memoryStore) Append(ctx context.Context, playerID, owner string, msg Message, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.owned(playerID, owner)
+	if err != nil {
+		return err
+	}
+	stored.Messages = append(stored.Messages, msg)
+	if len(stored.Messages) > limit {
+		stored.Messages = stored.Messages[len(stored.Messages)-limit:]
+	}
+	if msg.Seq > stored.LastSeq {
+		stored.LastSeq = msg.Seq
+	}
+	return nil
+}
+
+// Trim forgets the messages sent to the player up to and including seq
+func (s *memoryStore) Trim(ctx context.Context, playerID, owner string, seq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.owned(playerID, owner)
+	if err != nil {
+		return err
+	}
+	kept := stored.Messages[:0]
+	for _, msg := range stored.Messages {
+		if msg.Seq > seq {
+			kept = append(kept, msg)
+		}
+	}
+	stored.Messages = kept
+	return nil
+}
+
+// SkipTo moves the player's last seq on to seq if it is behind
+func (s *memoryStore) SkipTo(ctx context.Context, playerID, owner string, seq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.owned(playerID, owner)
+	if err != nil {
+		return err
+	}
+	if seq > stored.LastSeq {
+		stored.LastSeq = seq
+	}
+	return nil
+}
+
+// Drop forgets the player's session in the lobby
+func (s *memoryStore) Drop(ctx context.Context, playerID, lobbyCode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.sessions[playerID]; ok && stored.LobbyCode == lobbyCode {
+		delete(s.sessions, playerID)
+	}
+	return nil
+}
+
+// SaveReconnectToken records the hash of a reconnect token issued to the player
+func (s *memoryStore) SaveReconnectToken(ctx context.Context, tokenHash, playerID string, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenHash] = memoryToken{playerID: playerID, expiry: expiry}
+	return nil
+}
+
+// ConsumeReconnectToken returns the player a reconnect token was issued to and forgets it
+func (s *memoryStore) ConsumeReconnectToken(ctx context.Context, tokenHash string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.tokens[tokenHash]
+	if !ok {
+		return "", nil
+	}
+	delete(s.tokens, tokenHash)
+	if !time.Now().Before(stored.expiry) {
+		return "", nil
+	}
+	return stored.playerID, nil
+}
@@ -0,0 +1,191 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix is what the Redis keys sessions are stored under are named with unless told otherwise
+const DefaultRedisPrefix = "poke-battles:"
+
+// Each session is a hash of its lobby, owner and last seq, named with the prefix, e.g. "poke-battles:session:player-1",
+// beside a sorted set of its messages scored by seq. The scripts check ownership and write in one step.
+var (
+	claimScript = redis.NewScript(`
+local previous = redis.call('HGET', KEYS[1], 'owner') or ''
+if ARGV[3] == '1' and redis.call('HGET', KEYS[1], 'lobby') == ARGV[1] then
+	redis.call('HSET', KEYS[1], 'owner', ARGV[2])
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+	return {previous, redis.call('HGET', KEYS[1], 'last_seq'), redis.call('ZRANGE', KEYS[2], 0, -1)}
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+redis.call('HSET', KEYS[1], 'lobby', ARGV[1], 'owner', ARGV[2], 'last_seq', '0')
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {previous}
+`)
+
+	appendScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then return 0 end
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[3])
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -tonumber(ARGV[4]) - 1)
+if tonumber(ARGV[2]) > tonumber(redis.call('HGET', KEYS[1], 'last_seq')) then
+	redis.call('HSET', KEYS[1], 'last_seq', ARGV[2])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+return 1
+`)
+
+	trimScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then return 0 end
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[2])
+return 1
+`)
+
+	skipToScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then return 0 end
+if tonumber(ARGV[2]) > tonumber(redis.call('HGET', KEYS[1], 'last_seq')) then
+	redis.call('HSET', KEYS[1], 'last_seq', ARGV[2])
+end
+return 1
+`)
+
+	dropScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'lobby') == ARGV[1] then
+	redis.call('DEL', KEYS[1], KEYS[2])
+end
+return 1
+`)
+)
+
+// redisStore implements Store in Redis, where every instance can reach it
+type redisStore struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisStore creates a store on the client, naming its keys with the prefix. A session is forgotten once
+// it has gone ttl without being claimed or sent a message.
+func NewRedisStore(client redis.UniversalClient, prefix string, ttl time.Duration) Store {
+	return &redisStore{client: client, prefix: prefix, ttl: ttl}
+}
+
+// keys returns the keys of the player's session hash and message set
+func (s *redisStore) keys(playerID string) []string {
+	key := s.prefix + "session:" + playerID
+	return []string{key, key + ":messages"}
+}
+
+// Claim makes owner the owner of the player's session in the lobby, starting a new one unless resuming
+func (s *redisStore) Claim(ctx context.Context, playerID, lobbyCode, owner string, resume bool) (*Session, string, error) {
+	resumeArg := "0"
+	if resume {
+		resumeArg = "1"
+	}
+	reply, err := claimScript.Run(ctx, s.client, s.keys(playerID), lobbyCode, owner, resumeArg, s.ttl.Milliseconds()).Slice()
+	if err != nil {
+		return nil, "", fmt.Errorf("session %q: %w", playerID, err)
+	}
+	previousOwner, _ := reply[0].(string)
+	if len(reply) == 1 {
+		return nil, previousOwner, nil
+	}
+
+	lastSeq, err := strconv.ParseInt(fmt.Sprint(reply[1]), 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("session %q: last seq: %w", playerID, err)
+	}
+	session := &Session{LobbyCode: lobbyCode, LastSeq: lastSeq}
+	members, _ := reply[2].([]interface{})
+	for _, member := range members {
+		msg, err := decodeMessage(fmt.Sprint(member))
+		if err != nil {
+			return nil, "", fmt.Errorf("session %q: %w", playerID, err)
+		}
+		session.Messages = append(session.Messages, msg)
+	}
+	return session, previousOwner, nil
+}
+
+// encodeMessage encodes a message as a member of a session's message set, unique by its seq
+func encodeMessage(msg Message) string {
+	return strconv.FormatInt(msg.Seq, 10) + ":" + strconv.FormatInt(msg.LobbySeq, 10) + ":" + string(msg.Data)
+}
+
+// decodeMessage decodes a member of a session's message set
+func decodeMessage(member string) (Message, error) {
+	parts := strings.SplitN(member, ":", 3)
+	if len(parts) != 3 {
+		return Message{}, errors.New("malformed message")
+	}
+	seq, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Message{}, fmt.Errorf("message seq: %w", err)
+	}
+	lobbySeq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Message{}, fmt.Errorf("message lobby seq: %w", err)
+	}
+	return Message{Seq: seq, LobbySeq: lobbySeq, Data: []byte(parts[2])}, nil
+}
+
+// runOwned runs a script that writes to the player's session only if owner owns it
+func (s *redisStore) runOwned(ctx context.Context, script *redis.Script, playerID string, args ...interface{}) error {
+	written, err := script.Run(ctx, s.client, s.keys(playerID), args...).Int()
+	if err != nil {
+		return fmt.Errorf("session %q: %w", playerID, err)
+	}
+	if written == 0 {
+		return fmt.Errorf("session %q: %w", playerID, ErrNotOwner)
+	}
+	return nil
+}
+
+// Append records a message sent to the player
+func (s *redisStore) Append(ctx context.Context, playerID, owner string, msg Message, limit int) error {
+	return s.runOwned(ctx, appendScript, playerID, owner, msg.Seq, encodeMessage(msg), limit, s.ttl.Milliseconds())
+}
+
+// Trim forgets the messages sent to the player up to and including seq
+func (s *redisStore) Trim(ctx context.Context, playerID, owner string, seq int64) error {
+	return s.runOwned(ctx, trimScript, playerID, owner, seq)
+}
+
+// SkipTo moves the player's last seq on to seq if it is behind
+func (s *redisStore) SkipTo(ctx context.Context, playerID, owner string, seq int64) error {
+	return s.runOwned(ctx, skipToScript, playerID, owner, seq)
+}
+
+// Drop forgets the player's session in the lobby
+func (s *redisStore) Drop(ctx context.Context, playerID, lobbyCode string) error {
+	if err := dropScript.Run(ctx, s.client, s.keys(playerID), lobbyCode).Err(); err != nil {
+		return fmt.Errorf("session %q: %w", playerID, err)
+	}
+	return nil
+}
+
+// SaveReconnectToken records the hash of a reconnect token issued to the player, expiring with it
+func (s *redisStore) SaveReconnectToken(ctx context.Context, tokenHash, playerID string, expiry time.Time) error {
+	ttl := time.Until(expiry)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, s.prefix+"reconnect:"+tokenHash, playerID, ttl).Err()
+}
+
+// ConsumeReconnectToken returns the player a reconnect token was issued to and deletes it in the same step
+func (s *redisStore) ConsumeReconnectToken(ctx context.Context, tokenHash string) (string, error) {
+	playerID, err := s.client.GetDel(ctx, s.prefix+"reconnect:"+tokenHash).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return playerID, err
+}
@@ -0,0 +1,56 @@
+// Package sessions keeps players' websocket sessions where every API instance can reach them,
+// so a client can reconnect to a different instance than the one it was on
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotOwner is returned when a session is written to by an instance it has since been claimed from
+var ErrNotOwner = errors.New("session owned by another instance")
+
+// Message is a message sent to a player, kept for replay after they reconnect
+type Message struct {
+	Seq      int64
+	LobbySeq int64 // 0 unless it was a broadcast to the lobby
+	Data     []byte
+}
+
+// Session is what a player needs to pick up where they left off: the last seq they were sent in the lobby and
+// the latest messages, oldest first
+type Session struct {
+	LobbyCode string
+	LastSeq   int64
+	Messages  []Message
+}
+
+// Store keeps players' sessions, each owned by the one instance that numbers and sends the player's messages.
+// Writes from any other instance fail with ErrNotOwner. Implementations must be safe for concurrent use.
+type Store interface {
+	// Claim makes owner the owner of the player's session in the lobby and returns it along with the instance
+	// that owned it before, empty if none did. With resume false, or when the player has no session in the
+	// lobby, a new empty session is started and nil is returned for it.
+	Claim(ctx context.Context, playerID, lobbyCode, owner string, resume bool) (session *Session, previousOwner string, err error)
+
+	// Append records a message sent to the player, keeping only the latest limit messages
+	Append(ctx context.Context, playerID, owner string, msg Message, limit int) error
+
+	// Trim forgets the messages sent to the player up to and including seq
+	Trim(ctx context.Context, playerID, owner string, seq int64) error
+
+	// SkipTo moves the player's last seq on to seq if it is behind
+	SkipTo(ctx context.Context, playerID, owner string, seq int64) error
+
+	// Drop forgets the player's session in the lobby
+	Drop(ctx context.Context, playerID, lobbyCode string) error
+
+	// SaveReconnectToken records the hash of a reconnect token issued to the player, accepted until expiry.
+	// Only hashes are stored, so a token cannot be read back from the store.
+	SaveReconnectToken(ctx context.Context, tokenHash, playerID string, expiry time.Time) error
+
+	// ConsumeReconnectToken returns the player the reconnect token with the hash was issued to, empty if it is
+	// unknown or has expired, and forgets it, so each token is accepted only once
+	ConsumeReconnectToken(ctx context.Context, tokenHash string) (string, error)
+}
@@ -0,0 +1,143 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testStore checks a store hands sessions between two instances, "instance-a" and "instance-b"
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	// A first claim starts a new session
+	session, previous, err := store.Claim(ctx, "player-1", "ABC123", "instance-a", true)
+	if err != nil || session != nil || previous != "" {
+		t.Fatalf("expected a new session, got %+v, previous owner %q, %v", session, previous, err)
+	}
+	for seq := int64(1); seq <= 4; seq++ {
+		msg := Message{Seq: seq, LobbySeq: seq * 10, Data: []byte(`{"type":"lobby_updated"}`)}
+		if err := store.Append(ctx, "player-1", "instance-a", msg, 3); err != nil {
+			t.Fatalf("failed to append: %v", err)
+		}
+	}
+	if err := store.Trim(ctx, "player-1", "instance-a", 2); err != nil {
+		t.Fatalf("failed to trim: %v", err)
+	}
+	if err := store.SkipTo(ctx, "player-1", "instance-a", 6); err != nil {
+		t.Fatalf("failed to skip: %v", err)
+	}
+
+	// Another instance resuming it gets the latest messages kept and the last seq
+	session, previous, err = store.Claim(ctx, "player-1", "ABC123", "instance-b", true)
+	if err != nil || session == nil {
+		t.Fatalf("expected the session to be resumed, got %v", err)
+	}
+	if previous != "instance-a" {
+		t.Errorf("expected the previous owner to be instance-a, got %q", previous)
+	}
+	expected := &Session{LobbyCode: "ABC123", LastSeq: 6, Messages: []Message{
+		{Seq: 3, LobbySeq: 30, Data: []byte(`{"type":"lobby_updated"}`)},
+		{Seq: 4, LobbySeq: 40, Data: []byte(`{"type":"lobby_updated"}`)},
+	}}
+	if !reflect.DeepEqual(session, expected) {
+		t.Errorf("expected %+v, got %+v", expected, session)
+	}
+
+	// The instance it was claimed from can no longer write to it
+	err = store.Append(ctx, "player-1", "instance-a", Message{Seq: 7, Data: []byte("{}")}, 3)
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("expected ErrNotOwner, got %v", err)
+	}
+	if err := store.Append(ctx, "player-1", "instance-b", Message{Seq: 7, Data: []byte("{}")}, 3); err != nil {
+		t.Errorf("expected the owner to append, got %v", err)
+	}
+
+	// Claiming without resuming, or in another lobby, starts over
+	if session, _, _ := store.Claim(ctx, "player-1", "ABC123", "instance-a", false); session != nil {
+		t.Error("expected a new session when not resuming")
+	}
+	if session, _, _ := store.Claim(ctx, "player-1", "XYZ789", "instance-a", true); session != nil {
+		t.Error("expected a new session in another lobby")
+	}
+
+	// Dropping only forgets the session in its own lobby
+	store.Drop(ctx, "player-1", "ABC123")
+	if err := store.SkipTo(ctx, "player-1", "instance-a", 1); err != nil {
+		t.Errorf("expected the session in another lobby to be kept, got %v", err)
+	}
+	store.Drop(ctx, "player-1", "XYZ789")
+	if err := store.SkipTo(ctx, "player-1", "instance-a", 1); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("expected the dropped session to be gone, got %v", err)
+	}
+
+	// Reconnect tokens name their player once, until they expire
+	store.SaveReconnectToken(ctx, "token-1", "player-1", time.Now().Add(time.Minute))
+	if playerID, err := store.ConsumeReconnectToken(ctx, "token-1"); err != nil || playerID != "player-1" {
+		t.Errorf("expected token-1 to be player-1's, got %q, %v", playerID, err)
+	}
+	if playerID, err := store.ConsumeReconnectToken(ctx, "token-1"); err != nil || playerID != "" {
+		t.Errorf("expected a used token to name no one, got %q, %v", playerID, err)
+	}
+	if playerID, err := store.ConsumeReconnectToken(ctx, "token-2"); err != nil || playerID != "" {
+		t.Errorf("expected an unknown token to name no one, got %q, %v", playerID, err)
+	}
+}
+
+// ========================================
+// Memory Store Tests
+// ========================================
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestMemoryStore_ReconnectTokenExpires(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveReconnectToken(context.Background(), "token-1", "player-1", time.Now().Add(-time.Second))
+	if playerID, _ := store.ConsumeReconnectToken(context.Background(), "token-1"); playerID != "" {
+		t.Errorf("expected an expired token to name no one, got %q", playerID)
+	}
+}
+
+// ========================================
+// Redis Store Tests
+// ========================================
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testStore(t, NewRedisStore(client, DefaultRedisPrefix, time.Hour))
+}
+
+func TestRedisStore_Expires(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	store := NewRedisStore(client, "test:", time.Minute)
+	ctx := context.Background()
+
+	store.Claim(ctx, "player-1", "ABC123", "instance-a", true)
+	store.Append(ctx, "player-1", "instance-a", Message{Seq: 1, Data: []byte("{}")}, 10)
+	store.SaveReconnectToken(ctx, "token-1", "player-1", time.Now().Add(30*time.Second))
+	if !server.Exists("test:session:player-1") || !server.Exists("test:session:player-1:messages") {
+		t.Fatal("expected the session to be stored under the prefix")
+	}
+
+	// Sessions go after the TTL without a message, tokens when they expire
+	server.FastForward(45 * time.Second)
+	if playerID, _ := store.ConsumeReconnectToken(ctx, "token-1"); playerID != "" {
+		t.Errorf("expected the token to have expired, got %q", playerID)
+	}
+	server.FastForward(30 * time.Second)
+	if server.Exists("test:session:player-1") || server.Exists("test:session:player-1:messages") {
+		t.Error("expected the session to have expired")
+	}
+}
@@ -0,0 +1,195 @@
+// Package showdown converts teams to and from the Pokemon Showdown text format,
+// so players can reuse teams built in the Showdown teambuilder.
+package showdown
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"poke-battles/internal/game"
+)
+
+// Parse errors
+var (
+	ErrEmptyTeam = errors.New("no creatures in team text")
+	ErrSyntax    = errors.New("malformed showdown team")
+)
+
+// showdownDefaultLevel is the level Showdown assumes when a set has no Level line
+const showdownDefaultLevel = 100
+
+// Parse reads a team in Showdown text format. Sets are separated by blank lines.
+// Nicknames, genders, items, levels, shininess and moves are read; abilities, EVs, IVs, natures
+// and other lines have no equivalent in this game and are ignored.
+// Species, item and move names are converted to catalogue IDs but not validated.
+func Parse(text string) ([]game.TeamMember, error) {
+	var team []game.TeamMember
+	var current *game.TeamMember
+
+	flush := func() {
+		if current != nil {
+			team = append(team, *current)
+			current = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "==="):
+			// Team headers from Showdown backups, e.g. "=== [gen9ou] My Team ==="
+			flush()
+		case current == nil:
+			member, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current = &member
+		case strings.HasPrefix(line, "-"):
+			move := ToID(strings.TrimPrefix(line, "-"))
+			if move == "" {
+				return nil, fmt.Errorf("line %d: empty move: %w", i+1, ErrSyntax)
+			}
+			current.Moves = append(current.Moves, move)
+		case strings.HasPrefix(line, "Level:"):
+			level, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Level:")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: level %q: %w", i+1, line, ErrSyntax)
+			}
+			current.Level = level
+		case strings.HasPrefix(line, "Shiny:"):
+			current.Shiny = strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(line, "Shiny:")), "yes")
+		}
+	}
+	flush()
+
+	if len(team) == 0 {
+		return nil, ErrEmptyTeam
+	}
+	return team, nil
+}
+
+// parseHeader reads the first line of a set: "Nickname (Species) (M) @ Item".
+// Everything but the species is optional.
+func parseHeader(line string) (game.TeamMember, error) {
+	name, item, _ := strings.Cut(line, "@")
+	name = strings.TrimSpace(name)
+
+	for _, gender := range []string{" (M)", " (F)"} {
+		name = strings.TrimSuffix(name, gender)
+	}
+
+	// A nickname puts the species in parentheses
+	var nickname string
+	if open := strings.LastIndex(name, " ("); open >= 0 && strings.HasSuffix(name, ")") {
+		nickname = strings.TrimSpace(name[:open])
+		name = name[open+2 : len(name)-1]
+	}
+
+	species := ToID(name)
+	if species == "" {
+		return game.TeamMember{}, fmt.Errorf("species %q: %w", line, ErrSyntax)
+	}
+
+	return game.TeamMember{
+		SpeciesID: species,
+		Level:     showdownDefaultLevel,
+		Item:      ToID(item),
+		Nickname:  nickname,
+	}, nil
+}
+
+// Format writes a team in Showdown text format, using catalogue display names where they exist.
+// A Level line is written whenever the level differs from Showdown's default of 100,
+// and a Shiny line only for shiny creatures.
+func Format(team []game.TeamMember) string {
+	var b strings.Builder
+
+	for i, member := range team {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		if member.Nickname != "" {
+			b.WriteString(member.Nickname + " (" + speciesName(member.SpeciesID) + ")")
+		} else {
+			b.WriteString(speciesName(member.SpeciesID))
+		}
+		if member.Item != "" {
+			b.WriteString(" @ " + displayName(member.Item))
+		}
+		b.WriteString("\n")
+
+		level := member.Level
+		if level == 0 {
+			level = game.DefaultLevel
+		}
+		if level != showdownDefaultLevel {
+			fmt.Fprintf(&b, "Level: %d\n", level)
+		}
+		if member.Shiny {
+			b.WriteString("Shiny: Yes\n")
+		}
+
+		for _, moveID := range member.Moves {
+			b.WriteString("- " + moveName(moveID) + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// ToID converts a Showdown display name to a catalogue ID, e.g. "Quick Attack" to "quick-attack".
+// Letters and digits are kept, spaces and hyphens become single hyphens, and everything else is dropped.
+func ToID(name string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_':
+			pendingHyphen = true
+		}
+	}
+
+	return b.String()
+}
+
+// speciesName returns the display name of a species, falling back to its ID
+func speciesName(id string) string {
+	if species, err := game.LookupSpecies(id); err == nil {
+		return species.Name
+	}
+	return displayName(id)
+}
+
+// moveName returns the display name of a move, falling back to its ID
+func moveName(id string) string {
+	if move, err := game.LookupMove(id); err == nil {
+		return move.Name
+	}
+	return displayName(id)
+}
+
+// displayName turns an ID with no catalogue entry into a title-cased name, e.g. "light-ball" to "Light Ball"
+func displayName(id string) string {
+	words := strings.Split(id, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
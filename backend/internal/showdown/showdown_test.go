@@ -0,0 +1,158 @@
+package showdown
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"poke-battles/internal/game"
+)
+
+// ========================================
+// Parse Tests
+// ========================================
+
+func TestParse_FullSet(t *testing.T) {
+	text := `Sparky (Pikachu) (M) @ Light Ball
+Ability: Static
+Level: 50
+Shiny: Yes
+EVs: 252 Atk / 4 SpD / 252 Spe
+Jolly Nature
+- Thunderbolt
+- Quick Attack
+- Fake Out
+`
+
+	team, err := Parse(text)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []game.TeamMember{{
+		SpeciesID: "pikachu",
+		Level:     50,
+		Item:      "light-ball",
+		Moves:     []string{"thunderbolt", "quick-attack", "fake-out"},
+		Nickname:  "Sparky",
+		Shiny:     true,
+	}}
+	if !reflect.DeepEqual(team, expected) {
+		t.Errorf("expected %+v, got %+v", expected, team)
+	}
+}
+
+func TestParse_MultipleSetsAndHeaders(t *testing.T) {
+	text := "=== [gen9ou] Starters ===\r\n\r\nVenusaur\r\n- Razor Leaf\r\n\r\n\r\nCharizard (F)\r\n- Flamethrower\r\n"
+
+	team, err := Parse(text)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(team) != 2 || team[0].SpeciesID != "venusaur" || team[1].SpeciesID != "charizard" {
+		t.Errorf("unexpected team: %+v", team)
+	}
+}
+
+func TestParse_MissingLevelUsesShowdownDefault(t *testing.T) {
+	team, _ := Parse("Snorlax\n- Tackle\n")
+
+	if team[0].Level != 100 {
+		t.Errorf("expected level 100, got %d", team[0].Level)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected error
+	}{
+		{"empty text", "  \n\n", ErrEmptyTeam},
+		{"bad level", "Pikachu\nLevel: fifty\n- Thunderbolt", ErrSyntax},
+		{"empty move", "Pikachu\n- \n", ErrSyntax},
+		{"no species", "@ Leftovers\n- Tackle", ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.text); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// ========================================
+// Format Tests
+// ========================================
+
+func TestFormat_UsesDisplayNames(t *testing.T) {
+	team := []game.TeamMember{
+		{SpeciesID: "pikachu", Item: "light-ball", Moves: []string{"thunderbolt", "quick-attack"}},
+		{SpeciesID: "snorlax", Level: 100, Moves: []string{"tackle"}},
+	}
+
+	expected := `Pikachu @ Light Ball
+Level: 50
+- Thunderbolt
+- Quick Attack
+
+Snorlax
+- Tackle
+`
+	if got := Format(team); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestFormat_NicknameAndShiny(t *testing.T) {
+	team := []game.TeamMember{{SpeciesID: "pikachu", Level: 100, Moves: []string{"thunderbolt"}, Nickname: "Sparky", Shiny: true}}
+
+	expected := `Sparky (Pikachu)
+Shiny: Yes
+- Thunderbolt
+`
+	if got := Format(team); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	team := game.StarterTeam()
+	for i := range team {
+		team[i].Level = game.DefaultLevel
+	}
+
+	parsed, err := Parse(Format(team))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(parsed, team) {
+		t.Errorf("expected %+v, got %+v", team, parsed)
+	}
+}
+
+// ========================================
+// ToID Tests
+// ========================================
+
+func TestToID(t *testing.T) {
+	tests := map[string]string{
+		"Quick Attack":   "quick-attack",
+		"  Mr. Mime ":    "mr-mime",
+		"Farfetch’d":     "farfetchd",
+		"Will-O-Wisp":    "will-o-wisp",
+		"U-turn":         "u-turn",
+		"Porygon2":       "porygon2",
+		"":               "",
+		"King's  Shield": "kings-shield",
+	}
+
+	for name, expected := range tests {
+		if got := ToID(name); got != expected {
+			t.Errorf("ToID(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
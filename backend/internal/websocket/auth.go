@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinSessionSecretLength is the fewest bytes a secret signing session tokens may have
+const MinSessionSecretLength = 32
+
+// ErrInvalidSessionToken is returned by a SessionValidator for a token it does not accept
+var ErrInvalidSessionToken = errors.New("invalid session token")
+
+// ErrSessionAuthUnavailable is returned when session tokens are required but there is no validator to check them
+var ErrSessionAuthUnavailable = errors.New("session validation is not configured")
+
+// SessionValidator checks a session token, returning the ID of the player it was issued to
+type SessionValidator func(token string) (playerID string, err error)
+
+// SessionAuth decides who may connect. Without a validator every player_id is trusted, as long as the
+// player is in the lobby, unless tokens are required.
+type SessionAuth struct {
+	Validate SessionValidator
+
+	// Required refuses every client while there is no validator, rather than trusting them all, so a server
+	// that must authenticate players cannot run without checking their tokens
+	Required bool
+
+	// RequireOnUpgrade refuses upgrades that carry no session token, so unauthorized clients are turned away
+	// before a connection is allocated for them. Otherwise they may present it in authenticate's session_token.
+	RequireOnUpgrade bool
+}
+
+// NewSignedSessionValidator returns a validator accepting session tokens signed with the secret, as
+// SignSessionToken makes them, until they expire. Whatever logs players in issues them with the same secret.
+func NewSignedSessionValidator(secret []byte) (SessionValidator, error) {
+	if len(secret) < MinSessionSecretLength {
+		return nil, fmt.Errorf("session token secret must be at least %d bytes, got %d", MinSessionSecretLength, len(secret))
+	}
+	key := append([]byte(nil), secret...)
+	return func(token string) (string, error) {
+		return validateSignedSessionToken(key, token, time.Now())
+	}, nil
+}
+
+// SignSessionToken issues a session token for the player that is valid until expiresAt. It is the player ID
+// and expiry, in Unix seconds, followed by an HMAC-SHA256 of the two, separated by dots:
+// base64url(player_id) "." expiry "." base64url(hmac), with no base64 padding.
+func SignSessionToken(secret []byte, playerID string, expiresAt time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(playerID)) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return claims + "." + base64.RawURLEncoding.EncodeToString(signSessionClaims(secret, claims))
+}
+
+// signSessionClaims returns the HMAC of a session token's player ID and expiry
+func signSessionClaims(secret []byte, claims string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(claims))
+	return mac.Sum(nil)
+}
+
+// validateSignedSessionToken returns the player a token signed with the secret was issued to,
+// or ErrInvalidSessionToken if its signature does not match or it expired before now
+func validateSignedSessionToken(secret []byte, token string, now time.Time) (string, error) {
+	encodedPlayer, rest, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidSessionToken
+	}
+	expiry, encodedSignature, ok := strings.Cut(rest, ".")
+	if !ok {
+		return "", ErrInvalidSessionToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signSessionClaims(secret, encodedPlayer+"."+expiry)) {
+		return "", ErrInvalidSessionToken
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return "", ErrInvalidSessionToken
+	}
+	playerID, err := base64.RawURLEncoding.DecodeString(encodedPlayer)
+	if err != nil || len(playerID) == 0 {
+		return "", ErrInvalidSessionToken
+	}
+	return string(playerID), nil
+}
+
+// SetSessionAuth sets how the session tokens players connect with are validated
+func (h *Handler) SetSessionAuth(auth SessionAuth) {
+	h.sessionAuth = auth
+}
+
+// upgradeSessionToken returns the session token an upgrade request carries in its token query parameter
+// or as a bearer token in its Authorization header, or "" if it carries none
+func upgradeSessionToken(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// authorizeUpgrade validates the upgrade request's session token, returning the player it belongs to,
+// or "" if there is no validator or the request carries no token and need not
+func (h *Handler) authorizeUpgrade(r *http.Request) (playerID string, err error) {
+	if h.sessionAuth.Validate == nil {
+		if h.sessionAuth.Required {
+			return "", ErrSessionAuthUnavailable
+		}
+		return "", nil
+	}
+	token := upgradeSessionToken(r)
+	if token == "" {
+		if h.sessionAuth.RequireOnUpgrade {
+			return "", ErrInvalidSessionToken
+		}
+		return "", nil
+	}
+	return h.sessionAuth.Validate(token)
+}
+
+// authorizeSession reports whether the connection may authenticate as the player: a token validated on
+// upgrade must have been issued to them, and otherwise the authenticate message's session_token must be
+func (h *Handler) authorizeSession(conn *Connection, payload AuthenticatePayload) bool {
+	if h.sessionAuth.Validate == nil {
+		return !h.sessionAuth.Required
+	}
+	if conn.sessionPlayerID != "" {
+		return conn.sessionPlayerID == payload.PlayerID
+	}
+	playerID, err := h.sessionAuth.Validate(payload.SessionToken)
+	return err == nil && playerID == payload.PlayerID
+}
@@ -0,0 +1,49 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ========================================
+// Session Token Tests
+// ========================================
+
+var testSessionSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestValidateSignedSessionToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	valid := SignSessionToken(testSessionSecret, "player.1", now.Add(time.Hour))
+
+	playerID, err := validateSignedSessionToken(testSessionSecret, valid, now)
+	if err != nil || playerID != "player.1" {
+		t.Fatalf("expected player.1, got %q, %v", playerID, err)
+	}
+
+	parts := strings.Split(valid, ".") // Player ID, expiry and signature
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", SignSessionToken(testSessionSecret, "player.1", now)},
+		{"other secret", SignSessionToken([]byte("fedcba9876543210fedcba9876543210"), "player.1", now.Add(time.Hour))},
+		{"player swapped", "cGxheWVyLTI." + parts[1] + "." + parts[2]}, // base64url("player-2")
+		{"expiry extended", parts[0] + ".1800000000." + parts[2]},
+		{"no player", SignSessionToken(testSessionSecret, "", now.Add(time.Hour))},
+		{"garbled", "not-a-token"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		if _, err := validateSignedSessionToken(testSessionSecret, tt.token, now); !errors.Is(err, ErrInvalidSessionToken) {
+			t.Errorf("%s: expected ErrInvalidSessionToken, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewSignedSessionValidator_ShortSecret(t *testing.T) {
+	if _, err := NewSignedSessionValidator(testSessionSecret[:MinSessionSecretLength-1]); err == nil {
+		t.Error("expected a secret shorter than the minimum to be rejected")
+	}
+}
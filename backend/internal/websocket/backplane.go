@@ -0,0 +1,236 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Backplane carries lobby broadcasts between API instances, so several instances behind a load balancer can
+// each host connections for the same lobby. Implementations must be safe for concurrent use; see the backplane
+// package for one backed by Redis pub/sub.
+type Backplane interface {
+	// Publish sends data to every instance subscribed to the lobby, this one included
+	Publish(ctx context.Context, lobbyCode string, data []byte) error
+	// Subscribe starts receiving what is published to the lobby
+	Subscribe(ctx context.Context, lobbyCode string) error
+	// Unsubscribe stops receiving what is published to the lobby
+	Unsubscribe(ctx context.Context, lobbyCode string) error
+	// Listen calls receive with each message published to a subscribed lobby, one at a time,
+	// until ctx is done or the backplane fails
+	Listen(ctx context.Context, receive func(lobbyCode string, data []byte)) error
+}
+
+const (
+	// backplaneTimeout bounds each call the hub makes to its backplane
+	backplaneTimeout = 5 * time.Second
+
+	// backplaneRetryDelay is how long the hub waits before listening again, or retrying a lobby's
+	// subscription, after the backplane fails
+	backplaneRetryDelay = time.Second
+
+	// backplaneQueueSize is how many broadcasts can wait to be published before more are dropped
+	backplaneQueueSize = 1024
+)
+
+// remoteBroadcast is a lobby broadcast as it travels over the backplane
+type remoteBroadcast struct {
+	Instance       string    `json:"instance"` // The hub that made it, which has already sent it to its own connections
+	ExceptPlayerID string    `json:"except_player_id,omitempty"`
+	Envelope       *Envelope `json:"envelope"`
+}
+
+// queuedPublish is a broadcast waiting to be published
+type queuedPublish struct {
+	lobbyCode string
+	data      []byte
+}
+
+// hubBackplane links a hub to the backplane it shares its broadcasts over
+type hubBackplane struct {
+	backplane Backplane
+
+	// Broadcasts are published in the order they were made, by one goroutine, so a slow
+	// backplane holds up no one broadcasting
+	outbound chan queuedPublish
+
+	// Lobbies whose subscription may no longer match whether the hub has connections in them
+	mu      sync.Mutex
+	pending map[string]bool
+	wake    chan struct{}
+
+	// Lobbies the hub is subscribed to, only touched by the goroutine syncing subscriptions
+	subscribed map[string]bool
+}
+
+// SetBackplane has the hub publish its lobby broadcasts on the backplane and send its connections the
+// broadcasts other instances publish there. Deliveries to the hub's own connections stay in-process; the hub
+// subscribes to a lobby only while it has connections in it. It must be called before the hub's first connection.
+func (h *Hub) SetBackplane(backplane Backplane) {
+	link := &hubBackplane{
+		backplane:  backplane,
+		outbound:   make(chan queuedPublish, backplaneQueueSize),
+		pending:    make(map[string]bool),
+		wake:       make(chan struct{}, 1),
+		subscribed: make(map[string]bool),
+	}
+
+	h.mu.Lock()
+	h.backplane = link
+	h.mu.Unlock()
+
+	ctx := h.stopContext()
+	go h.publishBroadcasts(ctx, link)
+	go h.syncSubscriptions(ctx, link)
+	go h.listenToBackplane(ctx, link)
+}
+
+// getBackplane returns the hub's link to its backplane, nil if it has none
+func (h *Hub) getBackplane() *hubBackplane {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.backplane
+}
+
+// publishRemote queues a broadcast the hub made for the other instances, dropping it if too many are waiting
+func (h *Hub) publishRemote(link *hubBackplane, lobbyCode, exceptPlayerID string, env *Envelope) {
+	data, err := json.Marshal(remoteBroadcast{Instance: h.instanceID, ExceptPlayerID: exceptPlayerID, Envelope: env})
+	if err != nil {
+		h.Logger().Error("backplane broadcast not encoded", "lobby_code", lobbyCode, "type", env.Type, "error", err)
+		return
+	}
+	select {
+	case link.outbound <- queuedPublish{lobbyCode: lobbyCode, data: data}:
+	default:
+		h.Logger().Warn("backplane broadcast dropped, publish queue full", "lobby_code", lobbyCode, "type", env.Type)
+	}
+}
+
+// publishBroadcasts publishes queued broadcasts until ctx is done
+func (h *Hub) publishBroadcasts(ctx context.Context, link *hubBackplane) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-link.outbound:
+			callCtx, cancel := context.WithTimeout(ctx, backplaneTimeout)
+			err := link.backplane.Publish(callCtx, queued.lobbyCode, queued.data)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				h.Logger().Warn("backplane publish failed", "lobby_code", queued.lobbyCode, "error", err)
+			}
+		}
+	}
+}
+
+// receiveRemote sends a broadcast from the backplane to the hub's connections in the lobby,
+// unless the hub made it and so has already sent it
+func (h *Hub) receiveRemote(lobbyCode string, data []byte) {
+	var broadcast remoteBroadcast
+	if err := json.Unmarshal(data, &broadcast); err != nil || broadcast.Envelope == nil {
+		h.Logger().Warn("backplane broadcast not decoded", "lobby_code", lobbyCode, "error", err)
+		return
+	}
+	if broadcast.Instance == h.instanceID {
+		return
+	}
+
+	msg, err := prepareEnvelope(broadcast.Envelope)
+	if err != nil {
+		h.Logger().Warn("backplane broadcast not prepared", "lobby_code", lobbyCode, "error", err)
+		return
+	}
+	// Decoded once here, so the lobby changes can be listed for resyncing clients without decoding it again
+	var events []LobbyEventRecord
+	if broadcast.Envelope.Type == TypeLobbyUpdated {
+		var update LobbyUpdatedPayload
+		if err := broadcast.Envelope.ParsePayload(&update); err == nil {
+			events = lobbyUpdateEvents(update)
+		}
+	}
+	h.deliverToLobby(lobbyCode, broadcast.ExceptPlayerID, msg, events, time.Now())
+}
+
+// listenToBackplane receives broadcasts from the backplane until ctx is done, listening again if it fails
+func (h *Hub) listenToBackplane(ctx context.Context, link *hubBackplane) {
+	for {
+		err := link.backplane.Listen(ctx, h.receiveRemote)
+		if ctx.Err() != nil {
+			return
+		}
+		h.Logger().Error("backplane stopped listening", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backplaneRetryDelay):
+		}
+	}
+}
+
+// resubscribe has the lobby's subscription brought in line with whether the hub has connections in it
+func (link *hubBackplane) resubscribe(lobbyCode string) {
+	link.mu.Lock()
+	link.pending[lobbyCode] = true
+	link.mu.Unlock()
+
+	select {
+	case link.wake <- struct{}{}:
+	default: // Already woken
+	}
+}
+
+// takePending returns the lobbies waiting to be resubscribed and clears them
+func (link *hubBackplane) takePending() []string {
+	link.mu.Lock()
+	defer link.mu.Unlock()
+	codes := make([]string, 0, len(link.pending))
+	for code := range link.pending {
+		codes = append(codes, code)
+	}
+	clear(link.pending)
+	return codes
+}
+
+// syncSubscriptions subscribes to lobbies the hub has connections in, and unsubscribes from those it no longer has,
+// until ctx is done. Subscriptions change on one goroutine, which checks the lobby's connections as it goes, so a
+// lobby emptied and joined again in quick succession is left subscribed however the changes interleave.
+func (h *Hub) syncSubscriptions(ctx context.Context, link *hubBackplane) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-link.wake:
+		}
+
+		for _, lobbyCode := range link.takePending() {
+			want := h.LobbyConnectionCount(lobbyCode) > 0
+			if want == link.subscribed[lobbyCode] {
+				continue
+			}
+
+			callCtx, cancel := context.WithTimeout(ctx, backplaneTimeout)
+			var err error
+			if want {
+				err = link.backplane.Subscribe(callCtx, lobbyCode)
+			} else {
+				err = link.backplane.Unsubscribe(callCtx, lobbyCode)
+			}
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				h.Logger().Warn("backplane subscription not changed", "lobby_code", lobbyCode, "subscribe", want, "error", err)
+				time.AfterFunc(backplaneRetryDelay, func() { link.resubscribe(lobbyCode) })
+				continue
+			}
+
+			if want {
+				link.subscribed[lobbyCode] = true
+			} else {
+				delete(link.subscribed, lobbyCode)
+			}
+		}
+	}
+}
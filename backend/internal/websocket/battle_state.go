@@ -0,0 +1,271 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"poke-battles/internal/game"
+)
+
+// buildGameState creates the game state snapshot as seen by the given player.
+// The player's own team is fully detailed; the opponent only exposes its active creature.
+func buildGameState(battle *game.Battle, playerID string) GameStatePayload {
+	state := GameStatePayload{
+		TurnNumber: battle.CurrentTurn(),
+		Phase:      battlePhase(battle),
+		Field: FieldInfo{
+			Terrain:      string(battle.Field.Terrain),
+			TerrainTurns: battle.Field.TerrainTurns,
+		},
+	}
+
+	for _, side := range battle.Sides {
+		if side.PlayerID == playerID {
+			state.PlayerState = buildOwnSideState(side)
+		} else {
+			state.OpponentState = buildOpponentSideState(side)
+		}
+	}
+
+	return state
+}
+
+// buildSpectatorState creates the full view of the battle given to spectators, with nothing about either side hidden
+func buildSpectatorState(battle *game.Battle) SpectatorStatePayload {
+	state := SpectatorStatePayload{
+		TurnNumber: battle.CurrentTurn(),
+		Phase:      battlePhase(battle),
+		Sides:      make([]PlayerBattleState, len(battle.Sides)),
+		Field: FieldInfo{
+			Terrain:      string(battle.Field.Terrain),
+			TerrainTurns: battle.Field.TerrainTurns,
+		},
+	}
+	for i, side := range battle.Sides {
+		state.Sides[i] = buildOwnSideState(side)
+	}
+	return state
+}
+
+// battlePhase returns the phase the battle is currently in
+func battlePhase(battle *game.Battle) GamePhase {
+	if battle.Outcome() != nil {
+		return GamePhaseEnded
+	}
+	if battle.InTeamPreview() {
+		return GamePhaseTeamPreview
+	}
+	if len(battle.PendingSwitches()) > 0 {
+		return GamePhaseSwitchSelection
+	}
+	return GamePhaseActionSelection
+}
+
+// buildTeamPreview creates the team preview as seen by the given player:
+// their own team in full and the species of the opposing team
+func buildTeamPreview(battle *game.Battle, playerID string) TeamPreviewPayload {
+	var preview TeamPreviewPayload
+	for _, side := range battle.Sides {
+		if side.PlayerID == playerID {
+			preview.PlayerState = buildOwnSideState(side)
+			continue
+		}
+
+		team := make([]PreviewCreatureInfo, len(side.Team))
+		for i, c := range side.Team {
+			team[i] = PreviewCreatureInfo{SpeciesID: c.SpeciesID, Name: c.Name, Nickname: c.Nickname, Shiny: c.Shiny, Level: c.Level}
+		}
+		preview.Opponent = PreviewSideInfo{PlayerID: side.PlayerID, Team: team}
+	}
+	return preview
+}
+
+// buildOwnSideState describes a player's own side with full team details
+func buildOwnSideState(side *game.BattleSide) PlayerBattleState {
+	team := make([]DetailedCreatureInfo, len(side.Team))
+	for i, c := range side.Team {
+		moves := make([]MoveInfo, len(c.Moves))
+		for j, m := range c.Moves {
+			moves[j] = MoveInfo{
+				ID:       m.ID,
+				Name:     m.Name,
+				Type:     string(m.Type),
+				PP:       c.RemainingPP(m.ID),
+				MaxPP:    m.PP,
+				Power:    m.Power,
+				Accuracy: m.Accuracy,
+			}
+		}
+		team[i] = DetailedCreatureInfo{
+			CreatureInfo: CreatureInfo{
+				ID:        c.ID,
+				Name:      c.Name,
+				Nickname:  c.Nickname,
+				Shiny:     c.Shiny,
+				CurrentHP: c.CurrentHP,
+				MaxHP:     c.MaxHP(),
+				Status:    string(c.Status),
+				IsActive:  i == side.ActiveSlot,
+			},
+			Moves: moves,
+		}
+	}
+
+	return PlayerBattleState{
+		PlayerID:   side.PlayerID,
+		Team:       team,
+		ActiveSlot: side.ActiveSlot,
+		Hazards:    buildHazardsInfo(side.Hazards),
+		Bag:        side.Bag.Clone(),
+	}
+}
+
+// buildOpponentSideState describes the opposing side with public information only: the
+// creatures seen on the field with the moves they used, and the bag items used. The rest of
+// the team, PP and the remaining bag stay hidden.
+func buildOpponentSideState(side *game.BattleSide) PlayerBattleState {
+	active := side.Active()
+
+	var revealed []RevealedCreatureInfo
+	for _, slot := range side.RevealedSlots() {
+		c := side.Team[slot]
+		var moves []RevealedMoveInfo
+		for _, m := range c.RevealedMoves() {
+			moves = append(moves, RevealedMoveInfo{
+				ID:       m.ID,
+				Name:     m.Name,
+				Type:     string(m.Type),
+				Power:    m.Power,
+				Accuracy: m.Accuracy,
+			})
+		}
+		revealed = append(revealed, RevealedCreatureInfo{
+			CreatureInfo: CreatureInfo{
+				ID:        c.ID,
+				Name:      c.Name,
+				Nickname:  c.Nickname,
+				Shiny:     c.Shiny,
+				CurrentHP: c.CurrentHP,
+				MaxHP:     c.MaxHP(),
+				Status:    string(c.Status),
+				IsActive:  slot == side.ActiveSlot,
+			},
+			Slot:  slot,
+			Moves: moves,
+		})
+	}
+
+	return PlayerBattleState{
+		PlayerID:     side.PlayerID,
+		ActiveSlot:   side.ActiveSlot,
+		BenchCount:   len(side.Team) - 1,
+		ActiveHP:     active.CurrentHP,
+		ActiveMaxHP:  active.MaxHP(),
+		ActiveStatus: string(active.Status),
+		Revealed:     revealed,
+		ItemsUsed:    side.ItemsUsed.Clone(),
+		Hazards:      buildHazardsInfo(side.Hazards),
+	}
+}
+
+// buildHazardsInfo describes a side's entry hazards, or nil if the side is clear
+func buildHazardsInfo(hazards game.Hazards) *HazardsInfo {
+	if !hazards.Any() {
+		return nil
+	}
+	return &HazardsInfo{
+		StealthRock: hazards.StealthRock,
+		Spikes:      hazards.Spikes,
+		ToxicSpikes: hazards.ToxicSpikes,
+	}
+}
+
+// buildTurnEvents converts domain battle events to protocol turn events
+func buildTurnEvents(events []game.BattleEvent) []TurnEvent {
+	turnEvents := make([]TurnEvent, 0, len(events))
+	for _, e := range events {
+		eventType, data := turnEventData(e)
+		raw, _ := json.Marshal(data)
+		turnEvents = append(turnEvents, TurnEvent{
+			Order: e.Order,
+			Type:  eventType,
+			Actor: e.Actor,
+			Data:  raw,
+		})
+	}
+	return turnEvents
+}
+
+// turnEventData maps a domain event to its protocol type and event data
+func turnEventData(e game.BattleEvent) (TurnEventType, interface{}) {
+	switch e.Type {
+	case game.EventMoveUsed:
+		return TurnEventMoveUsed, MoveUsedEventData{MoveID: e.MoveID}
+	case game.EventDamageDealt:
+		return TurnEventDamageDealt, DamageDealtEventData{Target: e.Target, Damage: e.Damage, Effectiveness: e.Effectiveness, Critical: e.Critical}
+	case game.EventStatusApplied:
+		return TurnEventStatusApplied, StatusAppliedEventData{Target: e.Target, Status: e.Status, Secondary: e.Secondary}
+	case game.EventStatusEnded:
+		return TurnEventStatusEnded, StatusEndedEventData{Target: e.Target, Status: e.Status}
+	case game.EventCreatureFainted:
+		return TurnEventCreatureFainted, CreatureFaintedEventData{CreatureID: e.Target, Owner: e.Actor}
+	case game.EventMoveFailed:
+		return TurnEventMoveFailed, MoveFailedEventData{MoveID: e.MoveID, Reason: e.Reason}
+	case game.EventConfusionSelfHit:
+		return TurnEventConfusionSelfHit, ConfusionSelfHitEventData{Target: e.Target, Damage: e.Damage}
+	case game.EventLeechSeedDrain:
+		return TurnEventLeechSeedDrain, LeechSeedDrainEventData{Target: e.Target, Damage: e.Damage, Recipient: e.Recipient, Healed: e.Healed}
+	case game.EventStatChanged:
+		return TurnEventStatChanged, StatChangedEventData{Target: e.Target, Stat: e.Stat, Stages: e.Stages, Secondary: e.Secondary}
+	case game.EventCreatureSwitched:
+		return TurnEventCreatureSwitched, CreatureSwitchedEventData{FromSlot: e.FromSlot, ToSlot: e.ToSlot}
+	case game.EventRecoilDamage:
+		return TurnEventRecoilDamage, RecoilDamageEventData{Target: e.Target, Damage: e.Damage}
+	case game.EventStatusDamage:
+		return TurnEventStatusDamage, StatusDamageEventData{Target: e.Target, Status: e.Status, Damage: e.Damage}
+	case game.EventHazardSet:
+		return TurnEventHazardSet, HazardSetEventData{Side: e.Side, Hazard: e.Hazard, Layers: e.Layers}
+	case game.EventHazardDamage:
+		return TurnEventHazardDamage, HazardDamageEventData{Target: e.Target, Hazard: e.Hazard, Damage: e.Damage}
+	case game.EventHazardCleared:
+		return TurnEventHazardCleared, HazardClearedEventData{Side: e.Side, Hazard: e.Hazard}
+	case game.EventTerrainSet:
+		return TurnEventTerrainSet, TerrainEventData{Terrain: e.Terrain}
+	case game.EventTerrainEnded:
+		return TurnEventTerrainEnded, TerrainEventData{Terrain: e.Terrain}
+	case game.EventItemUsed:
+		return TurnEventItemUsed, ItemUsedEventData{ItemID: e.ItemID, Target: e.Target, Healed: e.Healed, CuredStatus: e.Status}
+	default:
+		return TurnEventType(e.Type), nil
+	}
+}
+
+// buildBattleStats collects both players' post-game stats
+func buildBattleStats(battle *game.Battle) map[string]BattleStatsInfo {
+	stats := make(map[string]BattleStatsInfo, len(battle.Sides))
+	for _, side := range battle.Sides {
+		s, err := battle.Stats(side.PlayerID)
+		if err != nil {
+			continue
+		}
+		stats[side.PlayerID] = BattleStatsInfo{
+			DamageDealt:   s.DamageDealt,
+			DamageTaken:   s.DamageTaken,
+			KOs:           s.KOs,
+			TurnsSurvived: s.TurnsSurvived,
+			MostUsedMove:  s.MostUsedMove,
+		}
+	}
+	return stats
+}
+
+// buildTypeChart converts a data pack's type chart to its wire form
+func buildTypeChart(pack *game.DataPack) map[string]map[string]float64 {
+	chart := make(map[string]map[string]float64, len(pack.TypeChart))
+	for attack, matchups := range pack.TypeChart {
+		chart[string(attack)] = make(map[string]float64, len(matchups))
+		for def, m := range matchups {
+			chart[string(attack)][string(def)] = m
+		}
+	}
+	return chart
+}
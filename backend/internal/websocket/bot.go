@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"poke-battles/internal/game"
+)
+
+// playBots lets every bot in the lobby act on whatever the battle is waiting for from it:
+// a lead during team preview, a replacement for a fainted creature, or the turn's action.
+// Bot choices go through the battle service like a client's, so a turn they complete is published as usual.
+func (h *Handler) playBots(lobbyCode string, battle *game.Battle) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	for _, p := range lobby.GetPlayers() {
+		if p.Bot {
+			policy, err := game.NewBotPolicy(p.BotDifficulty)
+			if err != nil {
+				continue
+			}
+			h.playBot(lobbyCode, battle, p.ID, policy)
+		}
+	}
+}
+
+// playBot makes a single bot's pending choice. Choices the battle is not ready for, such as
+// an action while the opponent still has to replace a fainted creature, are left for later.
+func (h *Handler) playBot(lobbyCode string, battle *game.Battle, botID string, policy game.BotPolicy) {
+	if battle.InTeamPreview() {
+		if battle.LeadChosen(botID) {
+			return
+		}
+		ready, err := h.battleService.ChooseLead(battle.ID, botID, 0)
+		if err != nil || !ready {
+			return
+		}
+		h.broadcastGameState(lobbyCode, battle)
+	}
+
+	if containsPlayer(battle.PendingSwitches(), botID) {
+		slot, err := battle.BotSwitchSlot(botID, policy)
+		if err != nil {
+			return
+		}
+		result, err := h.battleService.SubmitForcedSwitch(battle.ID, botID, slot)
+		if err != nil {
+			return
+		}
+		h.stopSwitchTimer(botID)
+		h.publishTurnResult(lobbyCode, battle, result)
+		return
+	}
+
+	if _, submitted := battle.SubmittedAction(botID); submitted {
+		return
+	}
+	action, err := battle.BotAction(botID, policy)
+	if err != nil {
+		return
+	}
+	turn := battle.CurrentTurn()
+	result, err := h.battleService.SubmitAction(battle.ID, botID, action)
+	if err != nil {
+		return
+	}
+	if result == nil {
+		h.notifyOpponentCommitted(battle, botID, turn)
+		return
+	}
+	h.publishTurnResult(lobbyCode, battle, result)
+}
+
+// answerBotDrawOffers has every bot in the lobby decline a pending draw offer.
+// Bots always play on to a result.
+func (h *Handler) answerBotDrawOffers(lobbyCode string, battle *game.Battle) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	for _, p := range lobby.GetPlayers() {
+		if !p.Bot || battle.DrawOffer() == "" || battle.DrawOffer() == p.ID {
+			continue
+		}
+		if _, err := h.battleService.RespondDraw(battle.ID, p.ID, false); err == nil {
+			h.hub.BroadcastToLobby(lobbyCode, TypeDrawDeclined, DrawDeclinedPayload{PlayerID: p.ID})
+		}
+	}
+}
+
+// answerBotPauseRequests has every bot in the lobby accept a pending pause request.
+// Bots have no use for the time, so they never hold up a player who needs it.
+func (h *Handler) answerBotPauseRequests(lobbyCode string, battle *game.Battle) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	for _, p := range lobby.GetPlayers() {
+		if !p.Bot || battle.PauseRequest() == "" || battle.PauseRequest() == p.ID {
+			continue
+		}
+		if pause, err := h.battleService.RespondPause(battle.ID, p.ID, true); err == nil {
+			h.startPause(lobbyCode, battle, pause)
+		}
+	}
+}
+
+// humanPlayerIDs returns the IDs of the players who play over a connection
+func humanPlayerIDs(players []*game.Player) []string {
+	var ids []string
+	for _, p := range players {
+		if !p.Bot {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
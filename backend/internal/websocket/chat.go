@@ -0,0 +1,124 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"poke-battles/internal/game"
+)
+
+// Chat limits
+const (
+	// MaxChatMessageLength is the longest chat message, in characters, after sanitizing
+	MaxChatMessageLength = 280
+
+	// defaultChatRateLimit messages may be sent by each sender per defaultChatRateWindow
+	defaultChatRateLimit  = 5
+	defaultChatRateWindow = 10 * time.Second
+)
+
+// chatLimiter caps how many chat messages each sender may send in a sliding window
+type chatLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	sent   map[string][]time.Time // Send times within the window, oldest first, keyed by sender ID
+}
+
+func newChatLimiter(limit int, window time.Duration) *chatLimiter {
+	return &chatLimiter{
+		limit:  limit,
+		window: window,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+// allow records a message from the sender at now and returns true,
+// or returns false without recording it if the sender has used up the window's allowance.
+// History is kept across reconnects so reconnecting doesn't reset the allowance.
+func (l *chatLimiter) allow(senderID string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	for id, sent := range l.sent {
+		// Drop every send time that has left the window, and senders with none left
+		for len(sent) > 0 && !sent[0].After(cutoff) {
+			sent = sent[1:]
+		}
+		if len(sent) == 0 {
+			delete(l.sent, id)
+		} else {
+			l.sent[id] = sent
+		}
+	}
+
+	if len(l.sent[senderID]) >= l.limit {
+		return false
+	}
+	l.sent[senderID] = append(l.sent[senderID], now)
+	return true
+}
+
+// handleChatMessage relays a chat message to everyone connected to the sender's lobby, players and spectators alike
+func (h *Handler) handleChatMessage(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload ChatMessagePayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
+	text := game.SanitizeNickname(payload.Text)
+	if text == "" || utf8.RuneCountInString(text) > MaxChatMessageLength {
+		conn.SendError(ErrCodeInvalidAction, fmt.Sprintf("Chat messages must be 1 to %d characters", MaxChatMessageLength), env.CorrelationID)
+		return
+	}
+
+	lobby, err := h.lobbyService.GetLobby(conn.LobbyCode())
+	if err != nil {
+		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		return
+	}
+
+	senderID := conn.PlayerID()
+	username, ok := memberUsername(lobby, senderID)
+	if !ok {
+		conn.SendError(ErrCodePlayerNotInLobby, "Player not in lobby", env.CorrelationID)
+		return
+	}
+
+	now := time.Now()
+	if !h.chatLimiter.allow(senderID, now) {
+		conn.SendError(ErrCodeRateLimited, "Sending chat messages too quickly", env.CorrelationID)
+		return
+	}
+
+	h.hub.BroadcastToLobby(lobby.Code, TypeChatMessage, ChatMessageBroadcastPayload{
+		SenderID:  senderID,
+		Username:  username,
+		Spectator: conn.IsSpectator(),
+		Text:      text,
+		SentAt:    now.UnixMilli(),
+	})
+}
+
+// memberUsername finds the username of a lobby player or spectator
+func memberUsername(lobby *game.Lobby, id string) (string, bool) {
+	for _, p := range lobby.GetPlayers() {
+		if p.ID == id {
+			return p.Username, true
+		}
+	}
+	for _, s := range lobby.GetSpectators() {
+		if s.ID == id {
+			return s.Username, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+)
+
+// Application close codes sent in the close frame when the server disconnects a client, so clients can tell
+// whether and how soon to reconnect. Codes from 4000 mirror the error code that ended the connection; codes
+// from 4100 mark disconnects that are not errors.
+const (
+	CloseCodeInternalError      = 4000
+	CloseCodeAuthFailed         = 4001
+	CloseCodeAuthRequired       = 4002
+	CloseCodeSessionExpired     = 4003
+	CloseCodeLobbyNotFound      = 4004
+	CloseCodePlayerNotInLobby   = 4005
+	CloseCodeVersionMismatch    = 4006
+	CloseCodeMalformedMessage   = 4007
+	CloseCodeRateLimited        = 4008
+	CloseCodeTooManyConnections = 4009
+	CloseCodeLobbyFull          = 4010
+	CloseCodeInvalidState       = 4011
+
+	CloseCodeReplaced     = 4100 // The player connected again elsewhere; do not reconnect
+	CloseCodeLobbyClosed  = 4101 // The lobby is gone; do not reconnect
+	CloseCodeLeftLobby    = 4102 // The player left the lobby; do not reconnect
+	CloseCodeSlowConsumer = 4103 // The client fell behind; reconnect with last_seq straight away
+)
+
+// errorCloseCodes maps the error codes that can end a connection to their close codes
+var errorCloseCodes = map[ErrorCode]int{
+	ErrCodeInternalError:      CloseCodeInternalError,
+	ErrCodeAuthFailed:         CloseCodeAuthFailed,
+	ErrCodeAuthRequired:       CloseCodeAuthRequired,
+	ErrCodeSessionExpired:     CloseCodeSessionExpired,
+	ErrCodeLobbyNotFound:      CloseCodeLobbyNotFound,
+	ErrCodePlayerNotInLobby:   CloseCodePlayerNotInLobby,
+	ErrCodeVersionMismatch:    CloseCodeVersionMismatch,
+	ErrCodeMalformedMessage:   CloseCodeMalformedMessage,
+	ErrCodeRateLimited:        CloseCodeRateLimited,
+	ErrCodeTooManyConnections: CloseCodeTooManyConnections,
+	ErrCodeLobbyFull:          CloseCodeLobbyFull,
+	ErrCodeInvalidState:       CloseCodeInvalidState,
+}
+
+// CloseCodeFor returns the close code for a connection ended by the error, CloseCodeInternalError if it has none
+func CloseCodeFor(code ErrorCode) int {
+	if closeCode, ok := errorCloseCodes[code]; ok {
+		return closeCode
+	}
+	return CloseCodeInternalError
+}
+
+// maxCloseReasonLength is the most bytes of reason a close frame has room for
+const maxCloseReasonLength = 123
+
+// closeMessage formats a close frame's payload, cutting the reason short if it does not fit
+func closeMessage(code int, reason string) []byte {
+	if len(reason) > maxCloseReasonLength {
+		reason = reason[:maxCloseReasonLength]
+		for !utf8.ValidString(reason) {
+			reason = reason[:len(reason)-1]
+		}
+	}
+	return websocket.FormatCloseMessage(code, reason)
+}
+
+// setCloseReason sets the close code and reason the connection is closed with; the first one set is kept
+func (c *Connection) setCloseReason(code int, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setCloseReasonLocked(code, reason)
+}
+
+// setCloseReasonLocked is setCloseReason for a caller that already holds the lock
+func (c *Connection) setCloseReasonLocked(code int, reason string) {
+	if c.closeCode == 0 {
+		c.closeCode = code
+		c.closeReason = reason
+	}
+}
+
+// closeStatus returns the close code and reason the server chose for the connection, 0 and "" if it has not chosen one
+func (c *Connection) closeStatus() (int, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closeCode, c.closeReason
+}
+
+// closeFrame returns the payload of the close frame the connection is closed with
+func (c *Connection) closeFrame() []byte {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closeCode == 0 {
+		return []byte{}
+	}
+	return closeMessage(c.closeCode, c.closeReason)
+}
+
+// CloseWithCode closes the connection once the messages already queued have been written, with the close code and reason
+func (c *Connection) CloseWithCode(code int, reason string) {
+	c.setCloseReason(code, reason)
+	c.CloseAfterWrites()
+}
+
+// CloseWithError closes the connection once the messages already queued have been written,
+// with the error's close code and the message as its reason
+func (c *Connection) CloseWithError(code ErrorCode, message string) {
+	c.CloseWithCode(CloseCodeFor(code), message)
+}
+
+// writeCloseFrame sends the close frame straight away, for a connection about to be torn down
+// without waiting for its write pump
+func (c *Connection) writeCloseFrame() {
+	if c.conn == nil {
+		return
+	}
+	c.conn.WriteControl(websocket.CloseMessage, c.closeFrame(), time.Now().Add(c.config.WriteWait))
+}
@@ -0,0 +1,28 @@
+package websocket
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// ========================================
+// Close Code Tests
+// ========================================
+
+func TestCloseCodeFor(t *testing.T) {
+	if CloseCodeFor(ErrCodeAuthFailed) != 4001 || CloseCodeFor(ErrCodeRateLimited) != 4008 {
+		t.Error("expected auth failures to close with 4001 and rate limiting with 4008")
+	}
+	if CloseCodeFor(ErrCodeNotYourTurn) != CloseCodeInternalError {
+		t.Error("expected an error that never ends a connection to fall back to the internal error code")
+	}
+}
+
+func TestCloseMessage_TruncatesReason(t *testing.T) {
+	data := closeMessage(CloseCodeLobbyClosed, strings.Repeat("é", 100))
+	reason := string(data[2:])
+	if len(reason) > maxCloseReasonLength || !utf8.ValidString(reason) {
+		t.Errorf("expected the reason cut to at most %d bytes of valid UTF-8, got %d bytes", maxCloseReasonLength, len(reason))
+	}
+}
@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+
+	"poke-battles/internal/game"
+)
+
+// defaultLobbyUpdateWindow is how long lobby changes following a lobby_updated broadcast are held back
+// to be sent together as one
+const defaultLobbyUpdateWindow = 50 * time.Millisecond
+
+// lobbyUpdateWindow collects the lobby changes made since a lobby's last lobby_updated broadcast.
+// While one is open for a lobby, further changes wait for it to close and go out in a single
+// lobby_updated carrying the lobby as it is then. Any other message sent to the lobby or one of its
+// players first sends the changes held so far, so updates are never overtaken.
+type lobbyUpdateWindow struct {
+	timer  *time.Timer
+	events []LobbyEventRecord // Oldest first
+}
+
+// SetLobbyUpdateWindow sets how long lobby changes are held back to be coalesced; 0 broadcasts every change at once
+func (h *Handler) SetLobbyUpdateWindow(window time.Duration) {
+	h.lobbyUpdatesMu.Lock()
+	defer h.lobbyUpdatesMu.Unlock()
+	h.lobbyUpdateWindow = window
+}
+
+// broadcastLobbyUpdate broadcasts a lobby update to all players in the lobby. The first change after a quiet
+// spell goes out at once and opens a window; changes made during it are coalesced into one broadcast when it closes.
+func (h *Handler) broadcastLobbyUpdate(lobby *game.Lobby, event LobbyEvent, eventData interface{}) {
+	h.lobbyUpdatesMu.Lock()
+	defer h.lobbyUpdatesMu.Unlock()
+
+	if h.lobbyUpdateWindow <= 0 {
+		h.sendLobbyUpdate(lobby, []LobbyEventRecord{newLobbyEventRecord(event, eventData)})
+		return
+	}
+
+	if window, ok := h.lobbyUpdates[lobby.Code]; ok {
+		window.events = append(window.events, newLobbyEventRecord(event, eventData))
+		return
+	}
+	h.sendLobbyUpdate(lobby, []LobbyEventRecord{newLobbyEventRecord(event, eventData)})
+	h.openLobbyUpdateWindow(lobby.Code)
+}
+
+// openLobbyUpdateWindow starts holding back the lobby's changes. The caller must hold lobbyUpdatesMu.
+func (h *Handler) openLobbyUpdateWindow(lobbyCode string) {
+	window := &lobbyUpdateWindow{}
+	window.timer = time.AfterFunc(h.lobbyUpdateWindow, func() {
+		h.closeLobbyUpdateWindow(lobbyCode, window)
+	})
+	h.lobbyUpdates[lobbyCode] = window
+}
+
+// closeLobbyUpdateWindow broadcasts the changes held back during the window, if there were any,
+// and keeps holding back changes for another window after doing so
+func (h *Handler) closeLobbyUpdateWindow(lobbyCode string, window *lobbyUpdateWindow) {
+	h.lobbyUpdatesMu.Lock()
+	defer h.lobbyUpdatesMu.Unlock()
+
+	if h.lobbyUpdates[lobbyCode] != window {
+		return // Cancelled
+	}
+	delete(h.lobbyUpdates, lobbyCode)
+	if len(window.events) == 0 {
+		return
+	}
+
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return // The lobby is gone, and its players were told so
+	}
+	h.sendLobbyUpdate(lobby, window.events)
+	h.openLobbyUpdateWindow(lobbyCode)
+}
+
+// flushLobbyUpdates sends the lobby's held back changes now, keeping its window open
+func (h *Handler) flushLobbyUpdates(lobbyCode string) {
+	h.lobbyUpdatesMu.Lock()
+	defer h.lobbyUpdatesMu.Unlock()
+
+	window, ok := h.lobbyUpdates[lobbyCode]
+	if !ok || len(window.events) == 0 {
+		return
+	}
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+	h.sendLobbyUpdate(lobby, window.events)
+	window.events = nil
+}
+
+// stopLobbyUpdates drops the lobby's held back changes
+func (h *Handler) stopLobbyUpdates(lobbyCode string) {
+	h.lobbyUpdatesMu.Lock()
+	defer h.lobbyUpdatesMu.Unlock()
+
+	if window, ok := h.lobbyUpdates[lobbyCode]; ok {
+		window.timer.Stop()
+		delete(h.lobbyUpdates, lobbyCode)
+	}
+}
+
+// newLobbyEventRecord records a lobby change and the data describing it
+func newLobbyEventRecord(event LobbyEvent, eventData interface{}) LobbyEventRecord {
+	record := LobbyEventRecord{Event: event}
+	if eventData != nil {
+		data, _ := json.Marshal(eventData)
+		record.EventData = data
+	}
+	return record
+}
+
+// sendLobbyUpdate broadcasts the lobby as it is now, with the changes that led to it. The latest is the
+// update's event, and any before it are listed in earlier_events.
+func (h *Handler) sendLobbyUpdate(lobby *game.Lobby, events []LobbyEventRecord) {
+	latest := events[len(events)-1]
+	payload := LobbyUpdatedPayload{
+		Lobby:     h.buildLobbyInfo(lobby),
+		Event:     latest.Event,
+		EventData: latest.EventData,
+	}
+	if len(events) > 1 {
+		payload.EarlierEvents = events[:len(events)-1]
+	}
+	h.hub.broadcastToLobby(lobby.Code, "", TypeLobbyUpdated, payload)
+}
@@ -0,0 +1,101 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+)
+
+// Subprotocols a client may request when connecting to choose how envelopes are encoded.
+// A client that requests neither gets JSON.
+const (
+	SubprotocolJSON    = "json"
+	SubprotocolMsgpack = "msgpack"
+)
+
+// wireCodec encodes envelopes on the wire. Messages are built as JSON throughout the server,
+// so a codec only translates them at the connection's edge.
+type wireCodec interface {
+	// frameType is the websocket message type the codec's messages are sent as
+	frameType() int
+	// encode translates an outbound JSON message into the codec's format
+	encode(data []byte) ([]byte, error)
+	// decode translates an inbound message in the codec's format into JSON
+	decode(data []byte) ([]byte, error)
+}
+
+// codecFor returns the codec for a negotiated subprotocol, JSON if none was
+func codecFor(subprotocol string) wireCodec {
+	if subprotocol == SubprotocolMsgpack {
+		return msgpackCodec{}
+	}
+	return jsonCodec{}
+}
+
+// jsonCodec sends messages as JSON text frames, as they are built
+type jsonCodec struct{}
+
+func (jsonCodec) frameType() int                     { return websocket.TextMessage }
+func (jsonCodec) encode(data []byte) ([]byte, error) { return data, nil }
+func (jsonCodec) decode(data []byte) ([]byte, error) { return data, nil }
+
+// msgpackHandle decodes maps with string keys and strings as strings, so decoded messages translate back to JSON
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	return h
+}()
+
+// msgpackCodec sends messages as MessagePack binary frames
+type msgpackCodec struct{}
+
+func (msgpackCodec) frameType() int { return websocket.BinaryMessage }
+
+func (msgpackCodec) encode(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, msgpackHandle).Encode(fromJSONNumbers(v)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (msgpackCodec) decode(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := codec.NewDecoderBytes(data, msgpackHandle).Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// fromJSONNumbers replaces the JSON numbers in a decoded value with integers where they are whole,
+// and floats otherwise, so they keep their type in MessagePack
+func fromJSONNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = fromJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fromJSONNumbers(item)
+		}
+	}
+	return v
+}
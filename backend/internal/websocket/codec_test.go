@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+)
+
+// ========================================
+// Codec Tests
+// ========================================
+
+func TestCodecFor(t *testing.T) {
+	if _, ok := codecFor(SubprotocolMsgpack).(msgpackCodec); !ok {
+		t.Error("expected the msgpack subprotocol to select MessagePack")
+	}
+	for _, subprotocol := range []string{SubprotocolJSON, ""} {
+		if _, ok := codecFor(subprotocol).(jsonCodec); !ok {
+			t.Errorf("expected subprotocol %q to select JSON", subprotocol)
+		}
+	}
+}
+
+func TestMsgpackCodec_RoundTrip(t *testing.T) {
+	env, err := NewEnvelopeWithSeq(TypeBattlePaused, 7, BattlePausedPayload{
+		PlayerID:          "player-1",
+		Reason:            "disconnect",
+		BudgetRemainingMs: 180000,
+		ExpiresAt:         1700000000123,
+	})
+	if err != nil {
+		t.Fatalf("failed to build envelope: %v", err)
+	}
+	data, _ := json.Marshal(env)
+
+	c := msgpackCodec{}
+	if c.frameType() != websocket.BinaryMessage {
+		t.Error("expected MessagePack to be sent as binary frames")
+	}
+	encoded, err := c.encode(data)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	// Whole numbers stay integers on the wire
+	var wire map[string]interface{}
+	if err := codec.NewDecoderBytes(encoded, msgpackHandle).Decode(&wire); err != nil {
+		t.Fatalf("failed to decode MessagePack: %v", err)
+	}
+	if seq, ok := wire["seq"].(int64); !ok || seq != 7 {
+		t.Errorf("expected seq to be the integer 7, got %#v", wire["seq"])
+	}
+
+	decoded, err := c.decode(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	var got, want map[string]interface{}
+	json.Unmarshal(decoded, &got)
+	json.Unmarshal(data, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the message:\n got  %v\n want %v", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"fmt"
+	"time"
+)
+
+// Connection defaults
+const (
+	// DefaultWriteWait is the time allowed to write a message to the peer
+	DefaultWriteWait = 10 * time.Second
+
+	// DefaultPongWait is the time allowed to read the next pong message from the peer
+	DefaultPongWait = 60 * time.Second
+
+	// DefaultPingPeriod is how often the peer is pinged, short enough of DefaultPongWait for the pong to arrive in time
+	DefaultPingPeriod = (DefaultPongWait * 9) / 10
+
+	// DefaultMaxMessageSize is the largest message accepted from the peer, in bytes
+	DefaultMaxMessageSize = 8192
+
+	// DefaultSendBufferSize is how many messages may wait to be written to the peer,
+	// with room for a full replay and the messages that follow it on reconnecting
+	DefaultSendBufferSize = replayBufferSize + 64
+
+	// DefaultSessionDuration is how long a connection stays authenticated
+	DefaultSessionDuration = 24 * time.Hour
+
+	// DefaultReconnectTokenDuration is how long a reconnect token is accepted after it is issued
+	DefaultReconnectTokenDuration = 5 * time.Minute
+
+	// DefaultAuthTimeout is how long a new connection has to authenticate before it is closed
+	DefaultAuthTimeout = 10 * time.Second
+)
+
+// ConnectionConfig tunes keepalive, limits and session lifetimes for a hub's connections,
+// e.g. allowing longer waits for clients on mobile networks
+type ConnectionConfig struct {
+	WriteWait              time.Duration // Time allowed to write a message to the peer
+	PongWait               time.Duration // Time allowed to read the next pong message from the peer
+	PingPeriod             time.Duration // How often the peer is pinged; must be less than PongWait
+	MaxMessageSize         int64         // Largest message accepted from the peer, in bytes
+	SendBufferSize         int           // Messages that may wait to be written before the peer is a slow consumer; must exceed the 256 a replay may send
+	SessionDuration        time.Duration // How long a connection stays authenticated
+	ReconnectTokenDuration time.Duration // How long a reconnect token is accepted after it is issued, never past the session
+	AuthTimeout            time.Duration // How long a new connection has to authenticate before it is closed with AUTH_REQUIRED
+}
+
+// DefaultConnectionConfig returns the connection defaults
+func DefaultConnectionConfig() ConnectionConfig {
+	return ConnectionConfig{
+		WriteWait:              DefaultWriteWait,
+		PongWait:               DefaultPongWait,
+		PingPeriod:             DefaultPingPeriod,
+		MaxMessageSize:         DefaultMaxMessageSize,
+		SendBufferSize:         DefaultSendBufferSize,
+		SessionDuration:        DefaultSessionDuration,
+		ReconnectTokenDuration: DefaultReconnectTokenDuration,
+		AuthTimeout:            DefaultAuthTimeout,
+	}
+}
+
+// Validate returns an error if a duration or size is not positive, the peer is not pinged more often
+// than its pongs are waited for, or the send buffer cannot hold a replay
+func (c ConnectionConfig) Validate() error {
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"write wait", c.WriteWait},
+		{"pong wait", c.PongWait},
+		{"ping period", c.PingPeriod},
+		{"session duration", c.SessionDuration},
+		{"reconnect token duration", c.ReconnectTokenDuration},
+		{"auth timeout", c.AuthTimeout},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			return fmt.Errorf("%s %v is not positive", d.name, d.value)
+		}
+	}
+	if c.PingPeriod >= c.PongWait {
+		return fmt.Errorf("ping period %v is not less than pong wait %v", c.PingPeriod, c.PongWait)
+	}
+	if c.MaxMessageSize <= 0 {
+		return fmt.Errorf("max message size %d is not positive", c.MaxMessageSize)
+	}
+	if c.SendBufferSize <= replayBufferSize {
+		return fmt.Errorf("send buffer size %d does not exceed the replay buffer size %d", c.SendBufferSize, replayBufferSize)
+	}
+	return nil
+}
+
+// SetConnectionConfig sets the config of connections the hub serves from now on; open connections keep theirs
+func (h *Hub) SetConnectionConfig(config ConnectionConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connConfig = config
+	return nil
+}
+
+// ConnectionConfig returns the config new connections to the hub get
+func (h *Hub) ConnectionConfig() ConnectionConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.connConfig
+}
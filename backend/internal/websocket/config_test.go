@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionConfig_Validate(t *testing.T) {
+	if err := DefaultConnectionConfig().Validate(); err != nil {
+		t.Fatalf("expected the defaults to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *ConnectionConfig)
+	}{
+		{"zero write wait", func(c *ConnectionConfig) { c.WriteWait = 0 }},
+		{"negative session duration", func(c *ConnectionConfig) { c.SessionDuration = -time.Hour }},
+		{"zero reconnect token duration", func(c *ConnectionConfig) { c.ReconnectTokenDuration = 0 }},
+		{"zero auth timeout", func(c *ConnectionConfig) { c.AuthTimeout = 0 }},
+		{"ping period not less than pong wait", func(c *ConnectionConfig) { c.PingPeriod = c.PongWait }},
+		{"zero max message size", func(c *ConnectionConfig) { c.MaxMessageSize = 0 }},
+		{"send buffer smaller than a replay", func(c *ConnectionConfig) { c.SendBufferSize = replayBufferSize }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConnectionConfig()
+			tt.change(&config)
+			if err := config.Validate(); err == nil {
+				t.Error("expected the config to be rejected")
+			}
+			hub := NewHub()
+			if err := hub.SetConnectionConfig(config); err == nil {
+				t.Error("expected the hub to refuse the config")
+			}
+			if hub.ConnectionConfig() != DefaultConnectionConfig() {
+				t.Error("expected a refused config to leave the hub's unchanged")
+			}
+		})
+	}
+}
+
+func TestConnection_UsesHubConfig(t *testing.T) {
+	hub := NewHub()
+	config := DefaultConnectionConfig()
+	config.SendBufferSize = replayBufferSize + 1
+	if err := hub.SetConnectionConfig(config); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+
+	conn := NewConnection(nil, hub)
+	for i := 0; i < config.SendBufferSize; i++ {
+		if err := conn.SendRaw([]byte("test")); err != nil {
+			t.Fatalf("unexpected error filling buffer: %v", err)
+		}
+	}
+	if err := conn.SendRaw([]byte("overflow")); err != ErrSendBufferFull {
+		t.Errorf("expected ErrSendBufferFull past the configured size, got %v", err)
+	}
+}
+
+func TestConnection_ReconnectTokenExpires(t *testing.T) {
+	hub := NewHub()
+	config := DefaultConnectionConfig()
+	config.ReconnectTokenDuration = 20 * time.Millisecond
+	if err := hub.SetConnectionConfig(config); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+
+	conn := NewConnection(nil, hub)
+	if _, err := conn.Authenticate("player-1", "ABC123"); err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	if expiry := conn.GetSessionExpiry(); time.Until(expiry) < config.SessionDuration-time.Minute {
+		t.Errorf("expected the session to last %v, expires at %v", config.SessionDuration, expiry)
+	}
+
+	token, err := conn.RefreshReconnectToken()
+	if err != nil {
+		t.Fatalf("failed to refresh token: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if conn.ConsumeReconnectToken(token) {
+		t.Error("expected the reconnect token to expire")
+	}
+
+	// A refreshed token is accepted for the duration again
+	token, err = conn.RefreshReconnectToken()
+	if err != nil {
+		t.Fatalf("failed to refresh token: %v", err)
+	}
+	if !conn.ConsumeReconnectToken(token) {
+		t.Error("expected the refreshed token to be accepted")
+	}
+}
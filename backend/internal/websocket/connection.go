@@ -1,9 +1,12 @@
 package websocket
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -26,6 +29,13 @@ const (
 type Connection struct {
 	mu sync.RWMutex
 
+	// Unique to the connection, for tracing it through logs and error details
+	id          string
+	connectedAt time.Time
+
+	// Logs with the connection's ID, and its player and lobby once it authenticates
+	logger *slog.Logger
+
 	// WebSocket connection
 	conn *websocket.Conn
 
@@ -40,53 +50,111 @@ type Connection struct {
 	outboundSeq    int64 // Next sequence number for outbound messages
 	lastReceivedSeq int64 // Last sequence number received from this client
 
-	// Reconnection
-	reconnectToken  string
-	sessionExpiry   time.Time
+	// The player's session once authenticated; it numbers outbound messages from then on and keeps them for replay
+	session *playerSession
+
+	// Reconnection; only the token's hash is kept, and it is accepted once
+	reconnectTokenHash string
+	reconnectExpiry    time.Time // When the reconnect token stops being accepted
+	sessionExpiry      time.Time
 
 	// Heartbeat tracking
 	lastHeartbeat time.Time
 
+	// Closes the connection if it has not authenticated in time, nil until started
+	authTimer *time.Timer
+
+	// Whether the client receives game_state_delta instead of full states
+	deltaUpdates bool
+
+	// Whether the client declared its capabilities without supports_compression; the capabilities themselves
+	// are kept on its session
+	compressionDeclined bool
+
+	// Whether the client plays in the lobby or watches it, set when it authenticates
+	role ConnectionRole
+
+	// Messages shorter than this many bytes are sent uncompressed; only applies if the client negotiated compression
+	compressionThreshold int
+
+	// How messages are encoded on the wire, as negotiated by subprotocol
+	codec wireCodec
+
+	// The protocol version the client authenticated with, 0 before then
+	protocolVersion int
+
+	// The client's IP, counted against the hub's per-IP limit until the connection is unregistered
+	remoteIP string
+
+	// The player the connection is counted against the per-player limit of, "" until it is admitted.
+	// Guarded by the hub's playersMu rather than mu.
+	admittedAs string
+
+	// The player the session token presented on upgrade was issued to, if one was
+	sessionPlayerID string
+
+	// Whether the client fell so far behind that its send buffer filled, and it is being disconnected to resync
+	degraded bool
+
+	// The close code and reason sent when the server closes the connection, 0 if it has not chosen one
+	closeCode   int
+	closeReason string
+
 	// Send channel for outbound messages
 	send chan []byte
 
-	// Hub reference for cleanup
-	hub *Hub
-}
+	// Outbound messages written ahead of those on send, and behind them, while the client is behind; see priority.go
+	critical chan []byte
+	low      chan []byte
 
-const (
-	// Time allowed to write a message to the peer
-	writeWait = 10 * time.Second
-
-	// Time allowed to read the next pong message from the peer
-	pongWait = 60 * time.Second
+	// The queue that orders sends to the connection's lobby, nil until it joins one
+	queue *lobbyQueue
 
-	// Send pings to peer with this period. Must be less than pongWait.
-	pingPeriod = (pongWait * 9) / 10
+	// Requests sent to the client that are waiting for its reply, keyed by correlation ID
+	requests map[string]*pendingRequest
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 8192
+	// Hub reference for cleanup
+	hub *Hub
 
-	// Size of send channel buffer
-	sendBufferSize = 256
+	// Keepalive, limits and session lifetimes, fixed when the connection is created
+	config ConnectionConfig
+}
 
-	// Session duration
-	sessionDuration = 24 * time.Hour
+// Compression defaults for clients that negotiate permessage-deflate
+const (
+	// DefaultCompressionLevel is the deflate level, flate.BestSpeed
+	DefaultCompressionLevel = 1
 
-	// Reconnect token duration
-	reconnectTokenDuration = 5 * time.Minute
+	// DefaultCompressionThreshold is the size in bytes below which messages are sent uncompressed,
+	// since deflate saves little on them
+	DefaultCompressionThreshold = 512
 )
 
 // NewConnection creates a new connection
 func NewConnection(conn *websocket.Conn, hub *Hub) *Connection {
-	return &Connection{
+	config := hub.ConnectionConfig()
+	id := newConnectionID()
+	c := &Connection{
+		id:            id,
+		connectedAt:   time.Now(),
+		logger:        hub.Logger().With("conn_id", id),
 		conn:          conn,
 		state:         ConnectionStatePending,
 		outboundSeq:   0,
 		lastHeartbeat: time.Now(),
-		send:          make(chan []byte, sendBufferSize),
+		send:          make(chan []byte, config.SendBufferSize+1), // The spare slot is for the slow consumer warning
+		critical:      make(chan []byte, config.SendBufferSize),
+		low:           make(chan []byte, config.SendBufferSize),
 		hub:           hub,
+		config:        config,
+
+		compressionThreshold: DefaultCompressionThreshold,
+		codec:                jsonCodec{},
+	}
+	if conn != nil {
+		c.codec = codecFor(conn.Subprotocol())
 	}
+	return c
 }
 
 // State returns the current connection state
@@ -117,30 +185,98 @@ func (c *Connection) LobbyCode() string {
 	return c.lobbyCode
 }
 
-// Authenticate sets the player credentials after successful authentication
-func (c *Connection) Authenticate(playerID, lobbyCode string) error {
+// Authenticate sets the player credentials after successful authentication, and returns the reconnect token
+// issued to the client
+func (c *Connection) Authenticate(playerID, lobbyCode string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	token, err := generateReconnectToken()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	c.playerID = playerID
 	c.lobbyCode = lobbyCode
 	c.state = ConnectionStateActive
-	c.reconnectToken = token
-	c.sessionExpiry = time.Now().Add(sessionDuration)
+	if c.authTimer != nil {
+		c.authTimer.Stop()
+	}
+	c.sessionExpiry = time.Now().Add(c.config.SessionDuration)
+	c.setReconnectTokenLocked(token)
+	c.logger = c.logger.With("player_id", playerID, "lobby_code", lobbyCode)
 
-	return nil
+	return token, nil
+}
+
+// startAuthTimer gives the connection the config's AuthTimeout to authenticate, after which it is sent
+// AUTH_REQUIRED and closed, rather than lingering until its read deadline
+func (c *Connection) startAuthTimer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authTimer = time.AfterFunc(c.config.AuthTimeout, c.reapUnauthenticated)
+}
+
+// reapUnauthenticated closes the connection if it is still waiting to authenticate
+func (c *Connection) reapUnauthenticated() {
+	if c.State() != ConnectionStatePending {
+		return
+	}
+	c.hub.metrics.countAuthTimeout()
+	c.SendError(ErrCodeAuthRequired, "Authentication timed out", "")
+	c.CloseWithError(ErrCodeAuthRequired, "Authentication timed out")
+}
+
+// SetDeltaUpdates sets whether the client receives game_state_delta instead of full states
+func (c *Connection) SetDeltaUpdates(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deltaUpdates = enabled
+}
+
+// DeltaUpdates returns true if the client receives game_state_delta instead of full states
+func (c *Connection) DeltaUpdates() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.deltaUpdates
+}
+
+// applyCapabilities puts what the client can handle into effect, nil if it declared nothing.
+// deltaUpdates is whether it asked for game_state_delta with the delta_updates flag instead.
+func (c *Connection) applyCapabilities(capabilities *ClientCapabilities, deltaUpdates bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deltaUpdates = deltaUpdates || (capabilities != nil && capabilities.SupportsDeltaState)
+	c.compressionDeclined = capabilities != nil && !capabilities.SupportsCompression
 }
 
-// GetReconnectToken returns the current reconnect token
-func (c *Connection) GetReconnectToken() string {
+// SetRole sets whether the client plays in the lobby or watches it
+func (c *Connection) SetRole(role ConnectionRole) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.role = role
+}
+
+// Role returns whether the client plays in the lobby or watches it, empty before it authenticates
+func (c *Connection) Role() ConnectionRole {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.reconnectToken
+	return c.role
+}
+
+// IsSpectator returns true if the client is watching the lobby rather than playing in it,
+// as a spectator or a waitlisted player
+func (c *Connection) IsSpectator() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.role == RoleSpectator || c.role == RoleWaitlisted
+}
+
+// reconnectTokenExpiry returns the hash of the current reconnect token and when it stops being accepted
+func (c *Connection) reconnectTokenExpiry() (string, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reconnectTokenHash, c.reconnectExpiry
 }
 
 // GetSessionExpiry returns the session expiry time
@@ -150,14 +286,19 @@ func (c *Connection) GetSessionExpiry() time.Time {
 	return c.sessionExpiry
 }
 
-// ValidateReconnectToken validates a reconnect token
-func (c *Connection) ValidateReconnectToken(token string) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.reconnectToken == token && time.Now().Before(c.sessionExpiry)
+// ConsumeReconnectToken returns true if the token is the connection's current reconnect token and has not expired.
+// A token is accepted only once, so it stops being accepted as soon as it has been.
+func (c *Connection) ConsumeReconnectToken(token string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token == "" || c.reconnectTokenHash == "" || hashReconnectToken(token) != c.reconnectTokenHash {
+		return false
+	}
+	c.reconnectTokenHash = ""
+	return time.Now().Before(c.reconnectExpiry)
 }
 
-// RefreshReconnectToken generates a new reconnect token
+// RefreshReconnectToken generates a new reconnect token, and the one issued before stops being accepted
 func (c *Connection) RefreshReconnectToken() (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -166,10 +307,20 @@ func (c *Connection) RefreshReconnectToken() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	c.reconnectToken = token
+	c.setReconnectTokenLocked(token)
 	return token, nil
 }
 
+// setReconnectTokenLocked issues the reconnect token, accepted for the reconnect token duration
+// but not past the session. The caller must hold c.mu.
+func (c *Connection) setReconnectTokenLocked(token string) {
+	c.reconnectTokenHash = hashReconnectToken(token)
+	c.reconnectExpiry = time.Now().Add(c.config.ReconnectTokenDuration)
+	if c.reconnectExpiry.After(c.sessionExpiry) {
+		c.reconnectExpiry = c.sessionExpiry
+	}
+}
+
 // NextSeq returns and increments the outbound sequence number
 func (c *Connection) NextSeq() int64 {
 	c.mu.Lock()
@@ -182,9 +333,43 @@ func (c *Connection) NextSeq() int64 {
 func (c *Connection) CurrentSeq() int64 {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	if c.session != nil {
+		return c.session.currentSeq()
+	}
 	return c.outboundSeq
 }
 
+// SetSession attaches the player's session, which numbers every later message after those already sent
+func (c *Connection) SetSession(session *playerSession) {
+	c.mu.Lock()
+	sent := c.outboundSeq
+	c.session = session
+	c.mu.Unlock()
+
+	session.skipTo(sent)
+}
+
+// ProtocolVersion returns the protocol version the client authenticated with, or 0 before authentication
+func (c *Connection) ProtocolVersion() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.protocolVersion
+}
+
+// SetProtocolVersion records the protocol version the client authenticated with
+func (c *Connection) SetProtocolVersion(version int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.protocolVersion = version
+}
+
+// Session returns the player's session, or nil before authentication
+func (c *Connection) Session() *playerSession {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.session
+}
+
 // UpdateLastReceivedSeq updates the last received sequence number
 func (c *Connection) UpdateLastReceivedSeq(seq int64) {
 	c.mu.Lock()
@@ -217,23 +402,48 @@ func (c *Connection) LastHeartbeat() time.Time {
 
 // SendMessage sends a message to the client with proper envelope
 func (c *Connection) SendMessage(msgType MessageType, payload interface{}) error {
-	seq := c.NextSeq()
-	env, err := NewEnvelopeWithSeq(msgType, seq, payload)
-	if err != nil {
-		return err
-	}
-	return c.SendEnvelope(env)
+	return c.SendMessageWithCorrelation(msgType, "", payload)
 }
 
 // SendMessageWithCorrelation sends a message with correlation ID
 func (c *Connection) SendMessageWithCorrelation(msgType MessageType, correlationID string, payload interface{}) error {
-	seq := c.NextSeq()
-	env, err := NewEnvelopeWithSeq(msgType, seq, payload)
+	env, err := NewEnvelope(msgType, payload)
+	if err != nil {
+		return err
+	}
+	msg, err := prepareEnvelope(env)
 	if err != nil {
 		return err
 	}
-	env.CorrelationID = correlationID
-	return c.SendEnvelope(env)
+	return c.sendPrepared(msg, correlationID)
+}
+
+// sendPrepared sends a prepared message in order with the broadcasts to the connection's lobby
+func (c *Connection) sendPrepared(msg *preparedEnvelope, correlationID string) error {
+	return c.inLobbyOrder(func() error {
+		return c.deliver(msg, correlationID)
+	})
+}
+
+// deliver sends a prepared message under the connection's next sequence number,
+// or its session's once it has one
+func (c *Connection) deliver(msg *preparedEnvelope, correlationID string) error {
+	var err error
+	if session := c.Session(); session != nil {
+		err = c.sendRawAt(session.stamp(msg, correlationID), priorityOf(msg.msgType))
+	} else {
+		err = c.sendRawAt(msg.marshal(c.NextSeq(), correlationID), priorityOf(msg.msgType))
+	}
+	if err == nil {
+		c.recordSent(msg.msgType, correlationID)
+	}
+	return err
+}
+
+// recordSent counts a message queued for the client and traces it in the debug log
+func (c *Connection) recordSent(msgType MessageType, correlationID string) {
+	c.hub.metrics.countSent(msgType, 1)
+	c.log().Debug("websocket message sent", "type", msgType, "correlation_id", correlationID)
 }
 
 // SendEnvelope sends a pre-built envelope
@@ -245,31 +455,63 @@ func (c *Connection) SendEnvelope(env *Envelope) error {
 	return c.SendRaw(data)
 }
 
-// SendRaw sends raw bytes to the client
+// SendRaw sends raw bytes to the client. A client whose send buffer is full has fallen too far behind to be
+// sent anything more, so rather than dropping messages and leaving it out of sync it is marked degraded,
+// warned, and disconnected once the buffer drains; it can then reconnect to have what it missed replayed.
 func (c *Connection) SendRaw(data []byte) error {
-	c.mu.RLock()
+	return c.sendRawAt(data, priorityNormal)
+}
+
+// sendRawAt sends raw bytes to the client as SendRaw does, queued at the priority
+func (c *Connection) sendRawAt(data []byte, priority sendPriority) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.state == ConnectionStateClosing {
-		c.mu.RUnlock()
 		return ErrConnectionClosing
 	}
-	c.mu.RUnlock()
-
-	select {
-	case c.send <- data:
-		return nil
-	default:
-		// Channel full, connection is too slow
+	buffered := c.bufferedLocked()
+	c.hub.metrics.bufferOccupancy.observe(float64(buffered))
+	if buffered >= c.config.SendBufferSize {
+		c.degradeLocked()
 		return ErrSendBufferFull
 	}
+	c.queueFor(priority) <- data // Cannot block: every send happens under the lock and leaves the spare slot free
+	return nil
+}
+
+// degradeLocked marks the connection degraded, queues a disconnect_warning in the buffer's spare slot
+// and closes the connection once the client has been sent what is already queued.
+// The caller must hold the lock.
+func (c *Connection) degradeLocked() {
+	c.logger.Warn("websocket slow consumer", "buffered", c.bufferedLocked())
+	c.degraded = true
+	c.state = ConnectionStateClosing
+	c.setCloseReasonLocked(CloseCodeSlowConsumer, "Too far behind; reconnect to resync")
+
+	// The warning carries no seq, so the client's last_seq still points at the last message it was sent
+	env, err := NewEnvelope(TypeDisconnectWarning, DisconnectWarningPayload{
+		Reason:    DisconnectWarningReasonSlowConsumer,
+		TimeoutAt: time.Now().UnixMilli(),
+	})
+	if err == nil {
+		if data, err := json.Marshal(env); err == nil {
+			c.send <- data
+		}
+	}
+	close(c.send)
+}
+
+// IsDegraded returns true if the connection is being disconnected for falling behind
+func (c *Connection) IsDegraded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.degraded
 }
 
 // SendError sends an error message
 func (c *Connection) SendError(code ErrorCode, message string, correlationID string) error {
-	payload := NewErrorPayload(code, message)
-	if correlationID != "" {
-		return c.SendMessageWithCorrelation(TypeError, correlationID, payload)
-	}
-	return c.SendMessage(TypeError, payload)
+	return c.sendError(NewErrorPayload(code, message), correlationID)
 }
 
 // SendErrorWithDetails sends an error message with details
@@ -279,12 +521,48 @@ func (c *Connection) SendErrorWithDetails(code ErrorCode, message string, detail
 		// Fall back to simple error if details can't be serialized
 		return c.SendError(code, message, correlationID)
 	}
+	return c.sendError(payload, correlationID)
+}
+
+// sendError logs the error and sends it with the connection's ID in its details
+func (c *Connection) sendError(payload ErrorPayload, correlationID string) error {
+	level := slog.LevelInfo
+	if !payload.Recoverable {
+		level = slog.LevelWarn
+	}
+	c.log().Log(context.Background(), level, "websocket error sent",
+		"code", payload.Code, "message", payload.Message, "correlation_id", correlationID)
+
+	payload.Details = withConnectionID(payload.Details, c.id)
 	if correlationID != "" {
 		return c.SendMessageWithCorrelation(TypeError, correlationID, payload)
 	}
 	return c.SendMessage(TypeError, payload)
 }
 
+// SetCompression sets the deflate level and the size in bytes below which messages are sent uncompressed.
+// Neither has any effect unless the client negotiated compression.
+func (c *Connection) SetCompression(level, threshold int) error {
+	if err := c.conn.SetCompressionLevel(level); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compressionThreshold = threshold
+	return nil
+}
+
+// shouldCompress returns true if a message of the given size is worth compressing.
+// A client that declared its capabilities without supports_compression gets none compressed.
+func (c *Connection) shouldCompress(size int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.compressionDeclined {
+		return false
+	}
+	return size >= c.compressionThreshold
+}
+
 // Close closes the connection
 func (c *Connection) Close() {
 	c.mu.Lock()
@@ -293,49 +571,108 @@ func (c *Connection) Close() {
 		return
 	}
 	c.state = ConnectionStateClosing
+	serverClosed := c.closeCode != 0
 	c.mu.Unlock()
 
 	close(c.send)
 	if c.conn != nil {
+		if serverClosed {
+			c.writeCloseFrame()
+		}
 		c.conn.Close()
 	}
 }
 
-// WritePump pumps messages from the hub to the websocket connection.
+// CloseAfterWrites closes the connection once the messages already queued for the client have been written.
+// The write pump drains the queue and sends a close frame, and the read pump then unregisters the connection.
+func (c *Connection) CloseAfterWrites() {
+	c.inLobbyOrder(func() error {
+		c.closeAfterWrites()
+		return nil
+	})
+}
+
+// closeAfterWrites is CloseAfterWrites without waiting for the sends queued for the lobby
+func (c *Connection) closeAfterWrites() {
+	c.mu.Lock()
+	if c.state == ConnectionStateClosing {
+		c.mu.Unlock()
+		return
+	}
+	c.state = ConnectionStateClosing
+	c.mu.Unlock()
+
+	close(c.send)
+}
+
+// WritePump pumps messages from the hub to the websocket connection, taking them in priority order
 func (c *Connection) WritePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.config.PingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
 	}()
 
 	for {
-		select {
-		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				// The hub closed the channel
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
+		message, ok, queued := c.nextQueued()
+		if !queued {
+			select {
+			case message = <-c.critical:
+			case message, ok = <-c.send:
+			case message = <-c.low:
+			case <-ticker.C:
+				c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
+				if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+				continue
 			}
+		}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
+		if !ok {
+			// The hub closed the channel, so what is left at other priorities goes out before the close frame
+			for unsent, left := c.nextUnsent(); left; unsent, left = c.nextUnsent() {
+				if !c.writeQueued(unsent) {
+					return
+				}
 			}
-			w.Write(message)
+			c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
+			c.conn.WriteMessage(websocket.CloseMessage, c.closeFrame())
+			return
+		}
+		if !c.writeQueued(message) {
+			return
+		}
+	}
+}
 
-			if err := w.Close(); err != nil {
-				return
-			}
+// writeQueued writes a message taken from the send buffer, returning false if the connection failed.
+// A message the codec cannot encode is dropped and counted, and the connection carries on.
+func (c *Connection) writeQueued(message []byte) bool {
+	c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
+	start := time.Now()
+	data, err := c.codec.encode(message)
+	if err != nil {
+		c.hub.metrics.countDropped()
+		c.log().Error("websocket message dropped, could not be encoded", "error", err, "bytes", len(message))
+		return true
+	}
 
-		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				return
-			}
-		}
+	c.conn.EnableWriteCompression(c.shouldCompress(len(data)))
+	w, err := c.conn.NextWriter(c.codec.frameType())
+	if err != nil {
+		return false
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return false
 	}
+
+	if err := w.Close(); err != nil {
+		return false
+	}
+	c.hub.metrics.writeLatency.observeSince(start)
+	return true
 }
 
 // ReadPump pumps messages from the websocket connection to the hub.
@@ -345,27 +682,34 @@ func (c *Connection) ReadPump(handler func(*Connection, *Envelope)) {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetReadLimit(c.config.MaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 		return nil
 	})
 
 	for {
 		_, message, err := c.conn.ReadMessage()
+		receivedAt := time.Now()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				// Log unexpected close
+				c.log().Warn("websocket closed unexpectedly", "error", err)
 			}
 			break
 		}
 
 		var env Envelope
-		if err := json.Unmarshal(message, &env); err != nil {
+		message, err = c.codec.decode(message)
+		if err == nil {
+			err = json.Unmarshal(message, &env)
+		}
+		if err != nil {
 			c.SendError(ErrCodeMalformedMessage, "Could not parse message envelope", "")
 			continue
 		}
+		env.receivedAt = receivedAt
+		c.log().Debug("websocket message received", "type", env.Type, "seq", env.Seq, "correlation_id", env.CorrelationID)
 
 		// Track sequence number if provided
 		if env.Seq > 0 {
@@ -402,3 +746,9 @@ func generateReconnectToken() (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// hashReconnectToken returns the hash a reconnect token is kept as, so a token cannot be read back from the server
+func hashReconnectToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
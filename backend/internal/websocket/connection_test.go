@@ -1,9 +1,16 @@
 package websocket
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // ========================================
@@ -149,7 +156,7 @@ func TestConnection_Authenticate(t *testing.T) {
 	hub := NewHub()
 	conn := NewConnection(nil, hub)
 
-	err := conn.Authenticate("player-1", "LOBBY1")
+	token, err := conn.Authenticate("player-1", "LOBBY1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -166,8 +173,13 @@ func TestConnection_Authenticate(t *testing.T) {
 		t.Errorf("expected lobby code 'LOBBY1', got %q", conn.LobbyCode())
 	}
 
-	if conn.GetReconnectToken() == "" {
-		t.Error("expected reconnect token to be set")
+	if token == "" {
+		t.Error("expected reconnect token to be issued")
+	}
+
+	// Only the token's hash is kept
+	if hash, _ := conn.reconnectTokenExpiry(); hash == token || hash != hashReconnectToken(token) {
+		t.Errorf("expected the token's hash to be kept, got %q", hash)
 	}
 
 	if conn.GetSessionExpiry().IsZero() {
@@ -183,13 +195,11 @@ func TestConnection_RefreshReconnectToken(t *testing.T) {
 	hub := NewHub()
 	conn := NewConnection(nil, hub)
 
-	err := conn.Authenticate("player-1", "LOBBY1")
+	originalToken, err := conn.Authenticate("player-1", "LOBBY1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	originalToken := conn.GetReconnectToken()
-
 	newToken, err := conn.RefreshReconnectToken()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -203,36 +213,78 @@ func TestConnection_RefreshReconnectToken(t *testing.T) {
 		t.Error("expected new token to be different from original")
 	}
 
-	if conn.GetReconnectToken() != newToken {
-		t.Error("expected GetReconnectToken to return new token")
+	// The original token stops being accepted as soon as it is replaced
+	if conn.ConsumeReconnectToken(originalToken) {
+		t.Error("expected the original token to be invalidated")
+	}
+	if !conn.ConsumeReconnectToken(newToken) {
+		t.Error("expected the new token to be accepted")
 	}
 }
 
-func TestConnection_ValidateReconnectToken(t *testing.T) {
+func TestConnection_ConsumeReconnectToken(t *testing.T) {
 	hub := NewHub()
 	conn := NewConnection(nil, hub)
 
-	err := conn.Authenticate("player-1", "LOBBY1")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	// No token is accepted before one is issued
+	if conn.ConsumeReconnectToken("") {
+		t.Error("expected empty token to fail validation")
 	}
 
-	token := conn.GetReconnectToken()
-
-	// Valid token should pass
-	if !conn.ValidateReconnectToken(token) {
-		t.Error("expected valid token to pass validation")
+	token, err := conn.Authenticate("player-1", "LOBBY1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Invalid token should fail
-	if conn.ValidateReconnectToken("invalid-token") {
+	if conn.ConsumeReconnectToken("invalid-token") {
 		t.Error("expected invalid token to fail validation")
 	}
 
 	// Empty token should fail
-	if conn.ValidateReconnectToken("") {
+	if conn.ConsumeReconnectToken("") {
 		t.Error("expected empty token to fail validation")
 	}
+
+	// Valid token should pass, once
+	if !conn.ConsumeReconnectToken(token) {
+		t.Error("expected valid token to pass validation")
+	}
+	if conn.ConsumeReconnectToken(token) {
+		t.Error("expected a used token to fail validation")
+	}
+}
+
+func TestConnection_Capabilities(t *testing.T) {
+	conn := NewConnection(nil, NewHub())
+
+	// A client that declares nothing gets compressed messages once they reach the threshold
+	if !conn.shouldCompress(DefaultCompressionThreshold) {
+		t.Error("expected a message at the threshold to be compressed")
+	}
+
+	// Declaring capabilities without supports_compression turns compression off
+	conn.applyCapabilities(&ClientCapabilities{SupportsDeltaState: true, ClientVersion: "web/1.4.2"}, false)
+	if conn.shouldCompress(DefaultCompressionThreshold * 10) {
+		t.Error("expected no compression without supports_compression")
+	}
+	if !conn.DeltaUpdates() {
+		t.Error("expected supports_delta_state to turn on delta updates")
+	}
+
+	conn.applyCapabilities(&ClientCapabilities{SupportsCompression: true}, false)
+	if !conn.shouldCompress(DefaultCompressionThreshold) {
+		t.Error("expected compression with supports_compression")
+	}
+	if conn.DeltaUpdates() {
+		t.Error("expected delta updates off once the client no longer declares them")
+	}
+
+	// delta_updates asks for them without declaring capabilities
+	conn.applyCapabilities(nil, true)
+	if !conn.DeltaUpdates() || !conn.shouldCompress(DefaultCompressionThreshold) {
+		t.Error("expected delta updates and compression for a client declaring only delta_updates")
+	}
 }
 
 // ========================================
@@ -265,7 +317,7 @@ func TestConnection_ErrSendBufferFull(t *testing.T) {
 	conn := NewConnection(nil, hub)
 
 	// Fill the send buffer
-	for i := 0; i < sendBufferSize; i++ {
+	for i := 0; i < DefaultSendBufferSize; i++ {
 		err := conn.SendRaw([]byte("test"))
 		if err != nil {
 			t.Fatalf("unexpected error filling buffer: %v", err)
@@ -279,6 +331,54 @@ func TestConnection_ErrSendBufferFull(t *testing.T) {
 	}
 }
 
+func TestConnection_SlowConsumer(t *testing.T) {
+	hub := NewHub()
+	conn := NewConnection(nil, hub)
+	session := &playerSession{lobbyCode: "ABC123"}
+	conn.SetSession(session)
+
+	for i := 0; i < DefaultSendBufferSize; i++ {
+		if err := conn.SendMessage(TypeHeartbeatAck, HeartbeatAckPayload{}); err != nil {
+			t.Fatalf("unexpected error filling buffer: %v", err)
+		}
+	}
+	if err := conn.SendMessage(TypeHeartbeatAck, HeartbeatAckPayload{}); err != ErrSendBufferFull {
+		t.Fatalf("expected ErrSendBufferFull, got %v", err)
+	}
+
+	if !conn.IsDegraded() || conn.State() != ConnectionStateClosing {
+		t.Error("expected an overflowing connection to be degraded and closing")
+	}
+	if conn.closeCode != CloseCodeSlowConsumer {
+		t.Errorf("expected close code %d, got %d", CloseCodeSlowConsumer, conn.closeCode)
+	}
+	if err := conn.SendRaw([]byte("late")); err != ErrConnectionClosing {
+		t.Errorf("expected nothing more to be queued, got %v", err)
+	}
+
+	// What was queued is still sent, followed by the warning
+	var last Envelope
+	count := 0
+	for data := range conn.send {
+		last = Envelope{}
+		json.Unmarshal(data, &last)
+		count++
+	}
+	if count != DefaultSendBufferSize+1 {
+		t.Errorf("expected the queued messages and the warning, got %d messages", count)
+	}
+	var warning DisconnectWarningPayload
+	last.ParsePayload(&warning)
+	if last.Type != TypeDisconnectWarning || warning.Reason != DisconnectWarningReasonSlowConsumer || last.Seq != 0 {
+		t.Errorf("expected an unsequenced slow_consumer warning last, got %+v", last)
+	}
+
+	// The message that did not fit is kept for replay after the client reconnects
+	if messages, complete := session.since(int64(DefaultSendBufferSize)); !complete || len(messages) != 1 {
+		t.Errorf("expected the dropped message to be replayable, got %d messages (complete %v)", len(messages), complete)
+	}
+}
+
 func TestConnection_ErrSendBufferFull_ErrorMessage(t *testing.T) {
 	err := ErrSendBufferFull
 
@@ -287,6 +387,149 @@ func TestConnection_ErrSendBufferFull_ErrorMessage(t *testing.T) {
 	}
 }
 
+// ========================================
+// Priority Tests
+// ========================================
+
+// queuePriorityMessages sends the connection one message of each class, the critical ones last
+func queuePriorityMessages(t *testing.T, conn *Connection) {
+	t.Helper()
+	for _, msgType := range []MessageType{TypeChatMessage, TypeLobbyUpdated, TypePlayerConnected, TypeTurnResult, TypeGameEnded} {
+		if err := conn.SendMessage(msgType, struct{}{}); err != nil {
+			t.Fatalf("failed to send %s: %v", msgType, err)
+		}
+	}
+}
+
+// priorityOrder is the order queuePriorityMessages' messages are written in once they have backed up
+var priorityOrder = []MessageType{TypeTurnResult, TypeGameEnded, TypeLobbyUpdated, TypeChatMessage, TypePlayerConnected}
+
+func TestConnection_PriorityOrder(t *testing.T) {
+	hub := NewHub()
+	conn := NewConnection(nil, hub)
+	queuePriorityMessages(t, conn)
+
+	var got []MessageType
+	var seqs []int64
+	for data, ok, queued := conn.nextQueued(); queued && ok; data, ok, queued = conn.nextQueued() {
+		var env Envelope
+		json.Unmarshal(data, &env)
+		got = append(got, env.Type)
+		seqs = append(seqs, env.Seq)
+	}
+	if !reflect.DeepEqual(got, priorityOrder) {
+		t.Errorf("expected %v, got %v", priorityOrder, got)
+	}
+
+	// Overtaking messages keep the seq they were sent under
+	if expected := []int64{4, 5, 2, 1, 3}; !reflect.DeepEqual(seqs, expected) {
+		t.Errorf("expected seqs %v, got %v", expected, seqs)
+	}
+}
+
+func TestConnection_PrioritySharesBuffer(t *testing.T) {
+	hub := NewHub()
+	conn := NewConnection(nil, hub)
+
+	for i := 0; i < DefaultSendBufferSize; i++ {
+		if err := conn.SendMessage(TypeChatMessage, struct{}{}); err != nil {
+			t.Fatalf("unexpected error filling buffer: %v", err)
+		}
+	}
+
+	// A critical message cannot jump a full buffer either
+	if err := conn.SendMessage(TypeTurnResult, struct{}{}); err != ErrSendBufferFull {
+		t.Errorf("expected ErrSendBufferFull, got %v", err)
+	}
+	if !conn.IsDegraded() {
+		t.Error("expected the connection to be degraded")
+	}
+}
+
+func TestConnection_WritePump_Priority(t *testing.T) {
+	hub := NewHub()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn := NewConnection(ws, hub)
+		queuePriorityMessages(t, conn)
+		conn.closeAfterWrites()
+		conn.WritePump()
+	}))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	// Everything queued is written in priority order before the connection closes
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	var got []MessageType
+	for {
+		var env Envelope
+		if err := client.ReadJSON(&env); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+				t.Errorf("expected the connection to be closed, got %v", err)
+			}
+			break
+		}
+		got = append(got, env.Type)
+	}
+	if !reflect.DeepEqual(got, priorityOrder) {
+		t.Errorf("expected %v, got %v", priorityOrder, got)
+	}
+}
+
+func TestConnection_WritePump_DropsUnencodable(t *testing.T) {
+	hub := NewHub()
+	upgrader := websocket.Upgrader{Subprotocols: []string{SubprotocolMsgpack}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn := NewConnection(ws, hub)
+		conn.send <- []byte("{not json")
+		if err := conn.SendMessage(TypeLobbyUpdated, struct{}{}); err != nil {
+			t.Errorf("failed to send: %v", err)
+		}
+		conn.closeAfterWrites()
+		conn.WritePump()
+	}))
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{SubprotocolMsgpack}}
+	client, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	// The message the codec cannot encode is skipped, and the one after it still arrives
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	frameType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("expected the second message, got %v", err)
+	}
+	decoded, err := msgpackCodec{}.decode(data)
+	if err != nil || frameType != websocket.BinaryMessage {
+		t.Fatalf("expected a MessagePack frame, got type %d: %v", frameType, err)
+	}
+	var env Envelope
+	if err := json.Unmarshal(decoded, &env); err != nil || env.Type != TypeLobbyUpdated {
+		t.Errorf("expected %s, got %+v (%v)", TypeLobbyUpdated, env, err)
+	}
+
+	if dropped := hub.Metrics().MessagesDropped; dropped != 1 {
+		t.Errorf("expected 1 message dropped, got %d", dropped)
+	}
+}
+
 // ========================================
 // Concurrent Access Tests
 // ========================================
@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConnectionInfo describes an open connection, for support to find a client's connection ID and trace it in the logs.
+// It leaves out the player ID and address, which would let whoever reads it act as the player or find them.
+type ConnectionInfo struct {
+	ID              string `json:"id"`
+	State           string `json:"state"`                 // pending, active or closing
+	LobbyCode       string `json:"lobby_code,omitempty"`  // Set once the connection has authenticated
+	Role            string `json:"role,omitempty"`        // player, spectator or waitlisted, once the connection has authenticated
+	Primary         bool   `json:"primary,omitempty"`     // The player's primary device, whose actions count
+	Subprotocol     string `json:"subprotocol,omitempty"` // msgpack, or empty for JSON
+	ProtocolVersion int    `json:"protocol_version"`      // 0 before the connection authenticates
+	ConnectedAt     int64  `json:"connected_at"`          // Unix ms
+	LastHeartbeat   int64  `json:"last_heartbeat"`        // Unix ms
+	SendBuffered    int    `json:"send_buffered"`         // Messages waiting to be written to the client
+	Degraded        bool   `json:"degraded,omitempty"`    // Being disconnected for falling behind
+
+	playerID string // Set once the connection has authenticated, for finding a player's connections
+}
+
+// connectionStateNames are the names ConnectionInfo gives connection states
+var connectionStateNames = map[ConnectionState]string{
+	ConnectionStatePending: "pending",
+	ConnectionStateActive:  "active",
+	ConnectionStateClosing: "closing",
+}
+
+// info describes the connection
+func (c *Connection) info() ConnectionInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info := ConnectionInfo{
+		ID:              c.id,
+		State:           connectionStateNames[c.state],
+		LobbyCode:       c.lobbyCode,
+		Role:            string(c.role),
+		ProtocolVersion: c.protocolVersion,
+		ConnectedAt:     c.connectedAt.UnixMilli(),
+		LastHeartbeat:   c.lastHeartbeat.UnixMilli(),
+		SendBuffered:    c.bufferedLocked(),
+		Degraded:        c.degraded,
+		playerID:        c.playerID,
+	}
+	if c.conn != nil {
+		info.Subprotocol = c.conn.Subprotocol()
+	}
+	return info
+}
+
+// Connections describes the hub's open connections, oldest first
+func (h *Hub) Connections() []ConnectionInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	infos := make([]ConnectionInfo, 0, len(h.connections))
+	for conn := range h.connections {
+		info := conn.info()
+		info.Primary = h.IsPrimary(conn)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ConnectedAt < infos[j].ConnectedAt
+	})
+	return infos
+}
+
+// Connection describes the open connection with the ID, returning false if there is none
+func (h *Hub) Connection(id string) (ConnectionInfo, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for conn := range h.connections {
+		if conn.id == id {
+			info := conn.info()
+			info.Primary = h.IsPrimary(conn)
+			return info, true
+		}
+	}
+	return ConnectionInfo{}, false
+}
+
+// HandleConnections serves the hub's open connections as JSON, optionally only those of the player_id
+// or lobby_code query parameters
+func (h *Handler) HandleConnections(c *gin.Context) {
+	playerID, lobbyCode := c.Query("player_id"), c.Query("lobby_code")
+	connections := make([]ConnectionInfo, 0)
+	for _, info := range h.hub.Connections() {
+		if (playerID == "" || info.playerID == playerID) && (lobbyCode == "" || info.LobbyCode == lobbyCode) {
+			connections = append(connections, info)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"connections": connections})
+}
+
+// HandleConnectionInfo serves the open connection with the :id path parameter as JSON
+func (h *Handler) HandleConnectionInfo(c *gin.Context) {
+	info, ok := h.hub.Connection(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "connection not found"})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
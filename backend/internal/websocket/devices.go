@@ -0,0 +1,149 @@
+package websocket
+
+// A player may be connected from several devices at once, such as a phone and a desktop. Everything sent to the
+// player reaches all of them, but only their primary device may act in the lobby; the others can follow along,
+// chat, and claim primary for themselves.
+
+// PrimaryReason says why a player's primary device changed
+type PrimaryReason string
+
+const (
+	PrimaryReasonAuthenticated PrimaryReason = "authenticated" // A device authenticated and took over
+	PrimaryReasonClaimed       PrimaryReason = "claimed"       // A device sent claim_primary
+	PrimaryReasonDisconnected  PrimaryReason = "disconnected"  // The primary disconnected and the newest other device took over
+)
+
+// secondaryMessageTypes are the only messages a player's devices other than the primary may send;
+// everything else acts in the lobby or the game
+var secondaryMessageTypes = map[MessageType]bool{
+	TypeHeartbeat:         true,
+	TypeTimeSync:          true,
+	TypeRequestLobbyState: true,
+	TypeRequestGameState:  true,
+	TypeChatMessage:       true,
+	TypeAck:               true,
+	TypeResyncRequest:     true,
+	TypeClaimPrimary:      true,
+}
+
+// addDevice adds an authenticated connection to the player's devices, as their primary if they have none
+func (h *Hub) addDevice(playerID string, conn *Connection) {
+	h.playersMu.Lock()
+	defer h.playersMu.Unlock()
+
+	for _, device := range h.devices[playerID] {
+		if device == conn {
+			return
+		}
+	}
+	h.devices[playerID] = append(h.devices[playerID], conn)
+	if h.players[playerID] == nil {
+		h.players[playerID] = conn
+	}
+}
+
+// removeDevice takes a connection off the player's devices. If it was their primary, their newest
+// remaining device becomes primary and is returned.
+func (h *Hub) removeDevice(playerID string, conn *Connection) (promoted *Connection) {
+	h.playersMu.Lock()
+	defer h.playersMu.Unlock()
+
+	devices := h.devices[playerID]
+	for i, device := range devices {
+		if device == conn {
+			devices = append(devices[:i:i], devices[i+1:]...)
+			break
+		}
+	}
+	if len(devices) == 0 {
+		delete(h.devices, playerID)
+	} else {
+		h.devices[playerID] = devices
+	}
+
+	if h.players[playerID] != conn {
+		return nil
+	}
+	if len(devices) == 0 {
+		delete(h.players, playerID)
+		return nil
+	}
+	promoted = devices[len(devices)-1]
+	h.players[playerID] = promoted
+	return promoted
+}
+
+// PlayerConnections returns every connection the player has authenticated on, oldest first
+func (h *Hub) PlayerConnections(playerID string) []*Connection {
+	h.playersMu.RLock()
+	defer h.playersMu.RUnlock()
+	return append([]*Connection(nil), h.devices[playerID]...)
+}
+
+// IsPrimary returns true if the connection is its player's primary device
+func (h *Hub) IsPrimary(conn *Connection) bool {
+	playerID := conn.PlayerID()
+	h.playersMu.RLock()
+	defer h.playersMu.RUnlock()
+	return playerID != "" && h.players[playerID] == conn
+}
+
+// ClaimPrimary makes an authenticated connection its player's primary device. If another device was primary,
+// all the player's devices are told with primary_changed.
+func (h *Hub) ClaimPrimary(conn *Connection, reason PrimaryReason) {
+	playerID := conn.PlayerID()
+
+	h.playersMu.Lock()
+	previous := h.players[playerID]
+	registered := false
+	for _, device := range h.devices[playerID] {
+		registered = registered || device == conn
+	}
+	if registered {
+		h.players[playerID] = conn
+	}
+	h.playersMu.Unlock()
+
+	if registered && previous != nil && previous != conn {
+		h.announcePrimary(playerID, conn, previous, reason)
+	}
+}
+
+// announcePrimary tells all the player's devices that primary moved from previous to primary
+func (h *Hub) announcePrimary(playerID string, primary, previous *Connection, reason PrimaryReason) {
+	h.SendToPlayer(playerID, TypePrimaryChanged, PrimaryChangedPayload{
+		ConnectionID:         primary.ID(),
+		PreviousConnectionID: previous.ID(),
+		Reason:               reason,
+	})
+}
+
+// PlayerDeltaUpdates returns true if every device the player is on receives game_state_delta instead of
+// full states. A message sent to the player reaches all of them, so one device wanting full states gets them for all.
+func (h *Hub) PlayerDeltaUpdates(playerID string) bool {
+	devices := h.PlayerConnections(playerID)
+	for _, conn := range devices {
+		if !conn.DeltaUpdates() {
+			return false
+		}
+	}
+	return len(devices) > 0
+}
+
+// handleClaimPrimary makes the connection its player's primary device, so its actions count from now on.
+// All the player's devices are told with primary_changed; a device that already was primary is answered with it alone.
+func (h *Handler) handleClaimPrimary(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	if h.hub.IsPrimary(conn) {
+		conn.SendMessageWithCorrelation(TypePrimaryChanged, env.CorrelationID, PrimaryChangedPayload{
+			ConnectionID: conn.ID(),
+			Reason:       PrimaryReasonClaimed,
+		})
+		return
+	}
+	h.hub.ClaimPrimary(conn, PrimaryReasonClaimed)
+}
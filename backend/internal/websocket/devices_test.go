@@ -0,0 +1,51 @@
+package websocket
+
+import "testing"
+
+func TestHub_Devices_PrimaryElection(t *testing.T) {
+	hub := NewHub()
+	phone := hubConn(t, hub, "player-1", "LOBBY1")
+	desktop := hubConn(t, hub, "player-1", "LOBBY1")
+
+	if !hub.IsPrimary(phone) || hub.IsPrimary(desktop) {
+		t.Fatal("expected the first device to be primary")
+	}
+	if devices := hub.PlayerConnections("player-1"); len(devices) != 2 || devices[0] != phone || devices[1] != desktop {
+		t.Fatalf("expected both devices oldest first, got %v", devices)
+	}
+
+	hub.ClaimPrimary(desktop, PrimaryReasonClaimed)
+	if hub.IsPrimary(phone) || !hub.IsPrimary(desktop) || hub.GetConnectionByPlayerID("player-1") != desktop {
+		t.Fatal("expected the claiming device to be primary")
+	}
+
+	// Losing the primary hands it to the newest device left
+	tablet := hubConn(t, hub, "player-1", "LOBBY1")
+	if promoted := hub.removeDevice("player-1", desktop); promoted != tablet {
+		t.Errorf("expected the newest device to be promoted, got %v", promoted)
+	}
+	if promoted := hub.removeDevice("player-1", phone); promoted != nil {
+		t.Errorf("expected no promotion when a secondary device leaves, got %v", promoted)
+	}
+	if promoted := hub.removeDevice("player-1", tablet); promoted != nil || hub.IsPlayerConnected("player-1") {
+		t.Error("expected the player to be gone with their last device")
+	}
+}
+
+func TestHub_PlayerDeltaUpdates(t *testing.T) {
+	hub := NewHub()
+	if hub.PlayerDeltaUpdates("player-1") {
+		t.Error("expected no delta updates for a player with no devices")
+	}
+
+	phone := hubConn(t, hub, "player-1", "LOBBY1")
+	phone.SetDeltaUpdates(true)
+	if !hub.PlayerDeltaUpdates("player-1") {
+		t.Error("expected delta updates when the only device wants them")
+	}
+
+	hubConn(t, hub, "player-1", "LOBBY1")
+	if hub.PlayerDeltaUpdates("player-1") {
+		t.Error("expected full states once a device does not want deltas")
+	}
+}
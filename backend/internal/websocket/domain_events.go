@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"poke-battles/internal/events"
+	"poke-battles/internal/services"
+)
+
+// Subscribe has the handler tell connected clients about the lobby and game changes published on the bus,
+// whether they were made over the websocket, over HTTP or by the server itself. It must be the bus the
+// handler's lobby and battle services publish on.
+func (h *Handler) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return bus.Subscribe(h.handleEvent)
+}
+
+// handleEvent broadcasts a published event to the lobby it happened in
+func (h *Handler) handleEvent(event events.Event) {
+	switch e := event.(type) {
+	case events.PlayerJoined:
+		h.BroadcastPlayerJoined(e.Lobby.Code, e.PlayerID, e.Username)
+	case events.PlayerLeft:
+		h.BroadcastPlayerLeft(e.Lobby.Code, e.PlayerID)
+	case events.SpectatorJoined:
+		h.BroadcastSpectatorJoined(e.Lobby.Code, e.SpectatorID, e.Username)
+	case events.SpectatorLeft:
+		h.BroadcastSpectatorLeft(e.Lobby.Code, e.SpectatorID)
+	case events.WaitlistJoined:
+		h.BroadcastWaitlistJoined(e.Lobby.Code, e.PlayerID, e.Username)
+	case events.WaitlistLeft:
+		h.BroadcastWaitlistLeft(e.Lobby.Code, e.PlayerID)
+	case events.WaitlistPromoted:
+		h.BroadcastWaitlistPromoted(e.Lobby, e.Promoted)
+	case events.TeamSubmitted:
+		h.BroadcastTeamSubmitted(e.Lobby, e.PlayerID)
+	case events.ReadyChanged:
+		h.BroadcastReadyChanged(e.Lobby, e.PlayerID, e.Ready)
+	case events.SettingsChanged:
+		h.BroadcastSettingsChanged(e.Lobby)
+	case events.GameStarting:
+		h.BroadcastGameStarting(e.Lobby.Code, 0)
+	case events.GameEnded:
+		h.finishGame(e.Lobby.Code, e.Battle, &services.TurnResult{
+			Outcome:  e.Outcome,
+			ReplayID: e.ReplayID,
+			Series:   e.Series,
+		})
+	case events.LobbyDeleted:
+		h.CloseLobby(e.Lobby)
+	case events.LobbyExpired:
+		h.HandleLobbyExpired(e.Lobby)
+	}
+}
@@ -21,6 +21,12 @@ const (
 	ErrCodeVersionMismatch   ErrorCode = "VERSION_MISMATCH"
 	ErrCodeInternalError     ErrorCode = "INTERNAL_ERROR"
 	ErrCodePlayerNotInLobby  ErrorCode = "PLAYER_NOT_IN_LOBBY"
+	ErrCodeSpectatorOnly     ErrorCode = "SPECTATOR_ONLY"
+	ErrCodeRateLimited       ErrorCode = "RATE_LIMITED"
+	ErrCodeTooManyConnections ErrorCode = "TOO_MANY_CONNECTIONS"
+	ErrCodeRequestTimeout    ErrorCode = "REQUEST_TIMEOUT"
+	ErrCodeNotPrimary        ErrorCode = "NOT_PRIMARY"
+	ErrCodePayloadTooLarge   ErrorCode = "PAYLOAD_TOO_LARGE"
 )
 
 // ErrorPayload is the payload for error messages
@@ -35,7 +41,8 @@ type ErrorPayload struct {
 func IsRecoverable(code ErrorCode) bool {
 	switch code {
 	case ErrCodeInvalidState, ErrCodeInvalidAction, ErrCodeNotYourTurn,
-		ErrCodeTurnMismatch, ErrCodeMalformedMessage:
+		ErrCodeTurnMismatch, ErrCodeMalformedMessage, ErrCodeSpectatorOnly,
+		ErrCodeRateLimited, ErrCodeRequestTimeout, ErrCodeNotPrimary, ErrCodePayloadTooLarge:
 		return true
 	default:
 		return false
@@ -1,9 +1,12 @@
 package websocket
 
 import (
+	"compress/flate"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"poke-battles/internal/game"
@@ -13,33 +16,159 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// defaultSwitchTimeout is how long a player has to replace a fainted creature before one is picked for them
+const defaultSwitchTimeout = 30 * time.Second
+
+// defaultDrawOfferTimeout is how long a player has to answer a draw offer before it is declined for them
+const defaultDrawOfferTimeout = time.Minute
+
+// defaultDraftPickTimeout is how long a player has for each draft ban or pick before the turn resolves without them
+const defaultDraftPickTimeout = 30 * time.Second
+
+// upgrader is the upgrade configuration shared by every handler, each of which checks origins against its own policy
 var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// TODO: Configure allowed origins for production
-		return true
-	},
+	ReadBufferSize:    1024,
+	WriteBufferSize:   1024,
+	EnableCompression: true, // Negotiates permessage-deflate with clients that offer it
+	Subprotocols:      []string{SubprotocolMsgpack, SubprotocolJSON},
 }
 
 // Handler handles WebSocket connections and messages
 type Handler struct {
-	hub          *Hub
-	lobbyService services.LobbyService
-	readyTracker *game.ReadyTracker
+	hub           *Hub
+	lobbyService  services.LobbyService
+	battleService services.BattleService
+	switchTimeout time.Duration
+
+	// rematchTracker records which players have requested a rematch after a game ends
+	rematchTracker *game.ReadyTracker
+
+	// draftPickTimeout is how long each draft turn lasts
+	draftPickTimeout time.Duration
+
+	// resumeCountdown is how long players are warned before a paused battle resumes
+	resumeCountdown time.Duration
+
+	// disconnectGrace is how long a player who drops out of a battle has to return before their opponent wins
+	disconnectGrace time.Duration
+
+	// drawOfferTimeout is how long a player has to answer a draw offer before it is declined for them
+	drawOfferTimeout time.Duration
+
+	// compressionLevel is the deflate level for clients that negotiate compression,
+	// and compressionThreshold the size in bytes below which their messages are sent uncompressed
+	compressionLevel     int
+	compressionThreshold int
+
+	// upgrader upgrades connections, checking their origin against originPolicy
+	upgrader     websocket.Upgrader
+	originPolicy OriginPolicy
+
+	// sessionAuth validates the session tokens players connect with
+	sessionAuth SessionAuth
+
+	// switchTimers holds the pending auto-switch timer for each player awaiting a forced switch,
+	// draftTimers the timer for each drafting lobby's current turn,
+	// pauseTimers the timer that resumes each paused game, keyed by game ID,
+	// startCountdowns the countdown to each lobby's game starting,
+	// and disconnectTimers the grace period of each player who dropped out of a battle
+	timersMu         sync.Mutex
+	switchTimers     map[string]*switchTimer
+	draftTimers      map[string]*draftTimer
+	pauseTimers      map[string]*pauseTimer
+	startCountdowns  map[string]*startCountdown
+	disconnectTimers map[string]*time.Timer
+
+	// stateViews holds the last battle state sent to each player, which deltas are computed against
+	viewsMu    sync.Mutex
+	stateViews map[string]GameStatePayload
+
+	// chatLimiter rate limits each sender's chat messages
+	chatLimiter *chatLimiter
+
+	// lobbyUpdates holds the open coalescing window of each lobby whose changes were recently broadcast,
+	// and lobbyUpdateWindow how long each lasts
+	lobbyUpdatesMu    sync.Mutex
+	lobbyUpdates      map[string]*lobbyUpdateWindow
+	lobbyUpdateWindow time.Duration
+}
+
+// draftTimer expires a draft turn at its deadline
+type draftTimer struct {
+	timer    *time.Timer
+	deadline time.Time
+}
+
+// switchTimer auto-switches for a player at its deadline. While the battle is paused
+// the timer is stopped and remaining holds the time that was left, see freezeSwitchTimers.
+type switchTimer struct {
+	timer     *time.Timer
+	deadline  time.Time
+	remaining time.Duration
+	fn        func()
 }
 
 // NewHandler creates a new WebSocket handler
-func NewHandler(hub *Hub, lobbyService services.LobbyService) *Handler {
+func NewHandler(hub *Hub, lobbyService services.LobbyService, battleService services.BattleService) *Handler {
 	h := &Handler{
-		hub:          hub,
-		lobbyService: lobbyService,
-		readyTracker: game.NewReadyTracker(),
+		hub:              hub,
+		lobbyService:     lobbyService,
+		battleService:    battleService,
+		rematchTracker:   game.NewReadyTracker(),
+		switchTimeout:    defaultSwitchTimeout,
+		switchTimers:     make(map[string]*switchTimer),
+		draftTimers:      make(map[string]*draftTimer),
+		pauseTimers:      make(map[string]*pauseTimer),
+		startCountdowns:  make(map[string]*startCountdown),
+		disconnectTimers: make(map[string]*time.Timer),
+		stateViews:       make(map[string]GameStatePayload),
+
+		draftPickTimeout: defaultDraftPickTimeout,
+		resumeCountdown:  defaultResumeCountdown,
+		disconnectGrace:  defaultDisconnectGrace,
+		drawOfferTimeout: defaultDrawOfferTimeout,
+		chatLimiter:      newChatLimiter(defaultChatRateLimit, defaultChatRateWindow),
+
+		lobbyUpdates:      make(map[string]*lobbyUpdateWindow),
+		lobbyUpdateWindow: defaultLobbyUpdateWindow,
+
+		compressionLevel:     DefaultCompressionLevel,
+		compressionThreshold: DefaultCompressionThreshold,
 	}
+	h.upgrader = upgrader
+	h.upgrader.CheckOrigin = h.checkOrigin
 	hub.SetOnDisconnect(h.HandlePlayerDisconnect)
+	hub.SetBeforeSend(h.flushLobbyUpdates)
 	return h
 }
 
+// SetCompression sets the deflate level, from flate.HuffmanOnly to flate.BestCompression, and the size in bytes
+// below which messages are sent uncompressed, for clients that negotiate compression.
+// It must be called before the handler serves any connection.
+func (h *Handler) SetCompression(level, threshold int) error {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return fmt.Errorf("compression level %d is not between %d and %d", level, flate.HuffmanOnly, flate.BestCompression)
+	}
+	if threshold < 0 {
+		return fmt.Errorf("compression threshold %d is negative", threshold)
+	}
+	h.compressionLevel = level
+	h.compressionThreshold = threshold
+	return nil
+}
+
+// SetDisconnectGrace sets how long a player who drops out of a battle has to return before their opponent wins.
+// It must be called before the handler serves any connection.
+func (h *Handler) SetDisconnectGrace(grace time.Duration) {
+	h.disconnectGrace = grace
+}
+
+// SetDrawOfferTimeout sets how long a player has to answer a draw offer before it is declined for them.
+// It must be called before the handler serves any connection.
+func (h *Handler) SetDrawOfferTimeout(timeout time.Duration) {
+	h.drawOfferTimeout = timeout
+}
+
 // HandleConnection handles a new WebSocket connection
 func (h *Handler) HandleConnection(c *gin.Context) {
 	lobbyCode := c.Param("code")
@@ -59,26 +188,91 @@ func (h *Handler) HandleConnection(c *gin.Context) {
 		return
 	}
 
+	// Turn away new clients while the server shuts down
+	if h.hub.ShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server shutting down"})
+		return
+	}
+
+	// Turn away clients whose session token is not valid before allocating anything for them
+	sessionPlayerID, err := h.authorizeUpgrade(c.Request)
+	if errors.Is(err, ErrSessionAuthUnavailable) {
+		h.hub.Logger().Error("refusing websocket upgrade: session tokens are required but no validator is set")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "session validation is not configured"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid session token"})
+		return
+	}
+
+	// Turn away clients whose address already holds as many connections as it may
+	ip := c.ClientIP()
+	if !h.hub.admitIP(ip) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many connections from this address"})
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket
-	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	wsConn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
+		h.hub.releaseIP(ip)
 		return // Upgrade already writes error response
 	}
 
 	// Create connection and register with hub
 	conn := NewConnection(wsConn, h.hub)
+	conn.remoteIP = ip
+	conn.sessionPlayerID = sessionPlayerID
+	conn.SetCompression(h.compressionLevel, h.compressionThreshold)
+	conn.log().Info("websocket connected", "remote_ip", ip, "lobby_code", lobbyCode, "subprotocol", wsConn.Subprotocol())
 	h.hub.Register(conn)
+	conn.startAuthTimer()
 
 	// Start read/write pumps
 	go conn.WritePump()
 	conn.ReadPump(h.handleMessage)
 }
 
+// spectatorMessageTypes are the only messages a spectator may send; everything else acts on the game
+var spectatorMessageTypes = map[MessageType]bool{
+	TypeHeartbeat:         true,
+	TypeTimeSync:          true,
+	TypeRequestLobbyState: true,
+	TypeLeaveGame:         true,
+	TypeChatMessage:       true,
+	TypeAck:               true,
+	TypeResyncRequest:     true,
+}
+
 // handleMessage routes incoming messages to appropriate handlers
 func (h *Handler) handleMessage(conn *Connection, env *Envelope) {
-	// Version check
-	if env.Version != ProtocolVersion {
-		conn.SendError(ErrCodeVersionMismatch, "Protocol version not supported", env.CorrelationID)
+	h.hub.metrics.countReceived(env.Type)
+
+	// Version check: authenticating picks the connection's version, which its later messages keep to
+	if version := conn.ProtocolVersion(); !supportsProtocolVersion(env.Version) || (version != 0 && env.Version != version) {
+		sendVersionMismatch(conn, env)
+		return
+	}
+	if !checkPayloadSize(conn, env) {
+		return
+	}
+	if err := upgradePayload(env); err != nil {
+		conn.SendError(ErrCodeMalformedMessage, "Invalid "+string(env.Type)+" payload", env.CorrelationID)
+		return
+	}
+
+	if conn.IsSpectator() && !spectatorMessageTypes[env.Type] {
+		conn.SendError(ErrCodeSpectatorOnly, "Spectators cannot send "+string(env.Type), env.CorrelationID)
+		return
+	}
+	if conn.Role() == RolePlayer && !secondaryMessageTypes[env.Type] && !h.hub.IsPrimary(conn) {
+		conn.SendError(ErrCodeNotPrimary, "Only the player's primary device can send "+string(env.Type), env.CorrelationID)
+		return
+	}
+
+	// A reply to a request from the server goes to whatever is waiting for it
+	if conn.resolveRequest(env) {
 		return
 	}
 
@@ -89,18 +283,42 @@ func (h *Handler) handleMessage(conn *Connection, env *Envelope) {
 		h.handleAuthenticate(conn, env)
 	case TypeHeartbeat:
 		h.handleHeartbeat(conn, env)
+	case TypeTimeSync:
+		h.handleTimeSync(conn, env)
+	case TypeClaimPrimary:
+		h.handleClaimPrimary(conn, env)
 
 	// Lobby Lifecycle
 	case TypeRequestLobbyState:
 		h.handleRequestLobbyState(conn, env)
 	case TypeSetReady:
 		h.handleSetReady(conn, env)
-
-	// Battle Lifecycle (placeholders for future implementation)
+	case TypeSubmitTeam:
+		h.handleSubmitTeam(conn, env)
+	case TypeSubmitPick:
+		h.handleSubmitPick(conn, env)
+	case TypeStartGame:
+		h.handleStartGame(conn, env)
+	case TypeCancelGameStart:
+		h.handleCancelGameStart(conn, env)
+
+	// Battle Lifecycle
+	case TypeChooseLead:
+		h.handleChooseLead(conn, env)
 	case TypeSubmitAction:
 		h.handleSubmitAction(conn, env)
 	case TypeRequestGameState:
 		h.handleRequestGameState(conn, env)
+	case TypeOfferDraw:
+		h.handleOfferDraw(conn, env)
+	case TypeRespondDraw:
+		h.handleRespondDraw(conn, env)
+	case TypeRequestPause:
+		h.handleRequestPause(conn, env)
+	case TypeRespondPause:
+		h.handleRespondPause(conn, env)
+	case TypeRequestResume:
+		h.handleRequestResume(conn, env)
 
 	// Post-Battle
 	case TypeRequestRematch:
@@ -108,6 +326,16 @@ func (h *Handler) handleMessage(conn *Connection, env *Envelope) {
 	case TypeLeaveGame:
 		h.handleLeaveGame(conn, env)
 
+	// Chat
+	case TypeChatMessage:
+		h.handleChatMessage(conn, env)
+
+	// Delivery
+	case TypeAck:
+		h.handleAck(conn, env)
+	case TypeResyncRequest:
+		h.handleResyncRequest(conn, env)
+
 	default:
 		conn.SendError(ErrCodeMalformedMessage, "Unknown message type", env.CorrelationID)
 	}
@@ -116,14 +344,13 @@ func (h *Handler) handleMessage(conn *Connection, env *Envelope) {
 // handleAuthenticate handles authentication requests
 func (h *Handler) handleAuthenticate(conn *Connection, env *Envelope) {
 	var payload AuthenticatePayload
-	if err := env.ParsePayload(&payload); err != nil {
-		conn.SendError(ErrCodeMalformedMessage, "Invalid authenticate payload", env.CorrelationID)
+	if !parsePayload(conn, env, &payload) {
 		return
 	}
 
-	// Validate required fields
-	if payload.PlayerID == "" || payload.LobbyCode == "" {
-		conn.SendError(ErrCodeAuthFailed, "player_id and lobby_code are required", env.CorrelationID)
+	// The connection's session token must belong to the player
+	if !h.authorizeSession(conn, payload) {
+		conn.SendError(ErrCodeAuthFailed, "Invalid session token", env.CorrelationID)
 		return
 	}
 
@@ -138,50 +365,171 @@ func (h *Handler) handleAuthenticate(conn *Connection, env *Envelope) {
 		return
 	}
 
+	// Spectators join the roster on their first connection unless they already did over REST
+	spectating := payload.Spectate || lobby.HasSpectator(payload.PlayerID)
+	if payload.Spectate && !lobby.HasSpectator(payload.PlayerID) {
+		if payload.Username == "" {
+			conn.SendError(ErrCodeAuthFailed, "username is required to spectate", env.CorrelationID)
+			return
+		}
+		if _, err := h.lobbyService.SpectateLobby(lobby.Code, payload.PlayerID, payload.Username); err != nil {
+			switch {
+			case errors.Is(err, game.ErrSpectatorsFull):
+				conn.SendError(ErrCodeLobbyFull, "No spectator slots left", env.CorrelationID)
+			case errors.Is(err, game.ErrPlayerAlreadyJoined):
+				conn.SendError(ErrCodeInvalidState, "Players cannot spectate their own lobby", env.CorrelationID)
+			default:
+				conn.SendError(ErrCodeInternalError, "Internal error", env.CorrelationID)
+			}
+			return
+		}
+	}
+
+	// Waitlisted players watch the lobby like spectators until a slot opens for them
+	waitlisted := !spectating && lobby.WaitlistPosition(payload.PlayerID) > 0
+
 	// Verify player is in lobby
-	if !lobby.HasPlayer(payload.PlayerID) {
+	if !spectating && !waitlisted && !lobby.HasPlayer(payload.PlayerID) {
 		conn.SendError(ErrCodePlayerNotInLobby, "Player not in lobby", env.CorrelationID)
 		return
 	}
 
 	// Verify lobby state allows connection
 	state := lobby.GetState()
-	if state != game.LobbyStateWaiting && state != game.LobbyStateReady && state != game.LobbyStateActive {
+	if state != game.LobbyStateWaiting && state != game.LobbyStateReady && state != game.LobbyStateActive && state != game.LobbyStateFinished {
 		conn.SendError(ErrCodeInvalidState, "Lobby not in valid state for connection", env.CorrelationID)
 		return
 	}
 
-	// TODO: Validate session_token against auth service
-	// For now, we trust the player_id if they're in the lobby
-
-	// Handle reconnection if token provided
+	// Handle reconnection if token provided, replacing the connection of the device the token was issued to.
+	// The token is used up doing so, and the new connection is issued another.
 	if payload.ReconnectToken != "" {
-		existingConn := h.hub.GetConnectionByPlayerID(payload.PlayerID)
-		if existingConn != nil && existingConn.ValidateReconnectToken(payload.ReconnectToken) {
-			// Valid reconnection - disconnect old connection
-			h.hub.Unregister(existingConn)
+		for _, existingConn := range h.hub.PlayerConnections(payload.PlayerID) {
+			if existingConn.ConsumeReconnectToken(payload.ReconnectToken) {
+				// It stops being one of the player's devices straight away, and stops counting against
+				// their connection limit, so the new connection takes its place
+				h.hub.removeDevice(payload.PlayerID, existingConn)
+				h.hub.releasePlayer(existingConn)
+				existingConn.setCloseReason(CloseCodeReplaced, "Replaced by a new connection")
+				h.hub.Unregister(existingConn)
+			}
 		}
 	}
 
+	// A player may only hold so many connections at once, not counting one just replaced
+	if !h.hub.admitPlayer(conn, payload.PlayerID) {
+		conn.SendError(ErrCodeTooManyConnections, "Too many connections for this player", env.CorrelationID)
+		conn.CloseWithError(ErrCodeTooManyConnections, "Too many connections for this player")
+		return
+	}
+
 	// Authenticate the connection
-	if err := conn.Authenticate(payload.PlayerID, payload.LobbyCode); err != nil {
+	reconnectToken, err := conn.Authenticate(payload.PlayerID, payload.LobbyCode)
+	if err != nil {
 		conn.SendError(ErrCodeInternalError, "Authentication failed", env.CorrelationID)
 		return
 	}
+	conn.SetProtocolVersion(env.Version)
+	role := RolePlayer
+	switch {
+	case waitlisted:
+		role = RoleWaitlisted
+	case spectating:
+		role = RoleSpectator
+	}
+	conn.SetRole(role)
+
+	// A new connection has no battle state yet, so it gets a complete one before any delta
+	h.resetStateViews(payload.PlayerID)
+
+	// Pick up the player's session
+	session, resumed := h.hub.openSession(conn, lobby.Code, payload.ReconnectToken)
+	conn.SetSession(session)
+
+	// What the client can handle is kept on its session, so a client reconnecting with its token
+	// keeps what it declared before unless it declares again. It applies before anything is replayed.
+	capabilities := payload.Capabilities
+	switch {
+	case capabilities != nil:
+		session.declare(capabilities)
+	case resumed && payload.ReconnectToken != "":
+		capabilities = session.capabilities()
+	}
+	conn.applyCapabilities(capabilities, payload.DeltaUpdates)
+	clientVersion := ""
+	if capabilities != nil {
+		clientVersion = capabilities.ClientVersion
+	}
+	h.hub.metrics.countClientVersion(clientVersion)
+
+	// Replay what the player missed since last_seq before any live message.
+	// With no session to resume, the lobby's broadcasts since last_lobby_seq are replayed instead.
+	var replayed int
+	var complete bool
+	if payload.LastLobbySeq > 0 && (!resumed || payload.LastSeq == 0) {
+		var missed []*preparedEnvelope
+		missed, complete = h.hub.lobbyBroadcastsSince(lobby.Code, payload.PlayerID, payload.LastLobbySeq)
+		replayed = session.catchUp(conn, missed)
+	} else {
+		lastSeq := payload.LastSeq
+		if !resumed {
+			lastSeq = 0 // A new session has nothing to replay
+		}
+		replayed, complete = session.resume(conn, lastSeq)
+		complete = complete && (resumed || payload.LastSeq == 0)
+	}
 
-	// Associate with lobby in hub
+	// Associate with lobby in hub. A player's new device takes over as primary unless it asked not to,
+	// and the devices they were already on are told once it has been sent its authenticated reply.
 	h.hub.AssociateWithLobby(conn)
+	takeOver := role == RolePlayer && !payload.Secondary && !h.hub.IsPrimary(conn)
 
 	// Send authenticated response
 	authPayload := AuthenticatedPayload{
 		PlayerID:         payload.PlayerID,
-		ReconnectToken:   conn.GetReconnectToken(),
+		ReconnectToken:   reconnectToken,
 		SessionExpiresAt: conn.GetSessionExpiry().UnixMilli(),
+		ProtocolVersion:  env.Version,
+		Replayed:         replayed,
+		ReplayIncomplete: !complete,
+		LobbySeq:         h.hub.LobbySeq(lobby.Code),
+		ConnectionID:     conn.ID(),
+		Primary:          takeOver || h.hub.IsPrimary(conn),
 	}
 	conn.SendMessageWithCorrelation(TypeAuthenticated, env.CorrelationID, authPayload)
+	if takeOver {
+		h.hub.ClaimPrimary(conn, PrimaryReasonAuthenticated)
+	}
+	conn.log().Info("websocket authenticated", "protocol_version", env.Version, "role", role,
+		"resumed", resumed, "replayed", replayed, "client_version", clientVersion)
 
 	// Send current lobby state
 	h.sendLobbyState(conn, lobby)
+
+	// Spectators and waitlisted players take no part in the battle or draft, so there is nothing to resume or start;
+	// they only need catching up on a battle already under way
+	if spectating || waitlisted {
+		if state == game.LobbyStateActive {
+			h.catchUpSpectator(conn, lobby.Code)
+		}
+		return
+	}
+
+	// Let the rest of the lobby know the player is here
+	h.hub.BroadcastToLobbyExcept(lobby.Code, payload.PlayerID, TypePlayerConnected, PlayerConnectedPayload{PlayerID: payload.PlayerID})
+
+	// Resume a battle paused while the player was away
+	if state == game.LobbyStateActive {
+		h.stopDisconnectTimer(payload.PlayerID)
+		h.resumeAfterReconnect(lobby.Code, payload.PlayerID)
+	}
+
+	// Catch a reconnecting player up on the draft, or start it now both players are here
+	if draft, ok := lobby.GetDraft(); ok && state != game.LobbyStateActive {
+		conn.SendMessage(TypeDraftState, buildDraftState(draft, h.draftDeadline(lobby.Code)))
+		return
+	}
+	h.checkAndStartDraft(lobby.Code)
 }
 
 // handleHeartbeat handles heartbeat messages
@@ -199,6 +547,29 @@ func (h *Handler) handleHeartbeat(conn *Connection, env *Envelope) {
 	conn.SendMessageWithCorrelation(TypeHeartbeatAck, env.CorrelationID, ackPayload)
 }
 
+// handleTimeSync answers a client working out how far its clock is from the server's
+func (h *Handler) handleTimeSync(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload TimeSyncPayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
+	receivedAt := env.receivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+	conn.SendMessageWithCorrelation(TypeTimeSyncResponse, env.CorrelationID, TimeSyncResponsePayload{
+		ClientSendTime:    payload.ClientSendTime,
+		ServerReceiveTime: receivedAt.UnixMilli(),
+		ServerSendTime:    time.Now().UnixMilli(),
+	})
+}
+
 // handleRequestLobbyState handles requests for current lobby state
 func (h *Handler) handleRequestLobbyState(conn *Connection, env *Envelope) {
 	if conn.State() != ConnectionStateActive {
@@ -206,12 +577,21 @@ func (h *Handler) handleRequestLobbyState(conn *Connection, env *Envelope) {
 		return
 	}
 
+	// The payload is optional, as it was before since_seq
+	var payload RequestLobbyStatePayload
+	if len(env.Payload) > 0 && !parsePayload(conn, env, &payload) {
+		return
+	}
+
 	lobby, err := h.lobbyService.GetLobby(conn.LobbyCode())
 	if err != nil {
 		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
 		return
 	}
 
+	if payload.SinceSeq > 0 && h.sendLobbyEventsSince(conn, env, lobby.Code, payload.SinceSeq) {
+		return
+	}
 	h.sendLobbyState(conn, lobby)
 }
 
@@ -223,244 +603,1310 @@ func (h *Handler) handleSetReady(conn *Connection, env *Envelope) {
 	}
 
 	var payload SetReadyPayload
-	if err := env.ParsePayload(&payload); err != nil {
-		conn.SendError(ErrCodeMalformedMessage, "Invalid set_ready payload", env.CorrelationID)
+	if !parsePayload(conn, env, &payload) {
 		return
 	}
 
-	lobbyCode := conn.LobbyCode()
-	playerID := conn.PlayerID()
-
-	// Track ready state
-	h.readyTracker.SetReady(lobbyCode, playerID, payload.Ready)
-
-	lobby, err := h.lobbyService.GetLobby(lobbyCode)
-	if err != nil {
-		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
-		return
+	// The lobby hears of the change, and the game starts if it is ready, once the lobby service publishes it
+	if _, err := h.lobbyService.SetReady(conn.LobbyCode(), conn.PlayerID(), payload.Ready); err != nil {
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		case errors.Is(err, game.ErrPlayerNotFound):
+			conn.SendError(ErrCodePlayerNotInLobby, "Player not in lobby", env.CorrelationID)
+		case errors.Is(err, game.ErrInvalidStateForReady):
+			conn.SendError(ErrCodeInvalidState, "Cannot change ready state after the game has started", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to set ready state", env.CorrelationID)
+		}
 	}
+}
 
-	// Broadcast updated state to all players
+// BroadcastReadyChanged tells a lobby's clients a player's ready state changed, over WS or HTTP,
+// and starts the draft or the game if everyone is now ready. A player who is no longer ready
+// calls off any game start countdown.
+func (h *Handler) BroadcastReadyChanged(lobby *game.Lobby, playerID string, ready bool) {
 	h.broadcastLobbyUpdate(lobby, LobbyEventPlayerReadyChanged, PlayerReadyChangedEventData{
 		PlayerID: playerID,
-		Ready:    payload.Ready,
+		Ready:    ready,
 	})
 
+	if !ready {
+		h.cancelStartCountdown(lobby.Code, GameStartCancelledReasonNotReady, playerID)
+	}
+
+	// Start the draft if draft mode was enabled after both players connected
+	h.checkAndStartDraft(lobby.Code)
+
 	// Check if game should start
-	h.checkAndStartGame(lobbyCode)
+	h.checkAndStartGame(lobby.Code)
 }
 
-// handleSubmitAction handles battle action submissions
-func (h *Handler) handleSubmitAction(conn *Connection, env *Envelope) {
+// handleSubmitTeam handles team submissions for the next game
+func (h *Handler) handleSubmitTeam(conn *Connection, env *Envelope) {
 	if conn.State() != ConnectionStateActive {
 		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
 		return
 	}
 
-	// TODO: Implement when battle system is added
-	// For now, return invalid state error
-	conn.SendError(ErrCodeInvalidState, "No active battle", env.CorrelationID)
-}
-
-// handleRequestGameState handles requests for game state
-func (h *Handler) handleRequestGameState(conn *Connection, env *Envelope) {
-	if conn.State() != ConnectionStateActive {
-		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+	var payload SubmitTeamPayload
+	if !parsePayload(conn, env, &payload) {
 		return
 	}
 
-	// TODO: Implement when battle system is added
-	conn.SendError(ErrCodeInvalidState, "No active battle", env.CorrelationID)
-}
+	team := make([]game.TeamMember, len(payload.Team))
+	for i, m := range payload.Team {
+		team[i] = game.TeamMember{
+			SpeciesID: m.SpeciesID,
+			Level:     m.Level,
+			Item:      m.Item,
+			Moves:     m.Moves,
+			Nickname:  m.Nickname,
+			Shiny:     m.Shiny,
+		}
+	}
 
-// handleRequestRematch handles rematch requests
-func (h *Handler) handleRequestRematch(conn *Connection, env *Envelope) {
-	if conn.State() != ConnectionStateActive {
-		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
-		return
+	lobbyCode := conn.LobbyCode()
+	playerID := conn.PlayerID()
+
+	// The lobby hears of the team, and the game starts if it was all it waited for, once the lobby service publishes it
+	if _, err := h.lobbyService.SubmitTeam(lobbyCode, playerID, team); err != nil {
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		case errors.Is(err, game.ErrInvalidTeam):
+			conn.SendErrorWithDetails(ErrCodeInvalidAction, "Invalid team", buildInvalidTeamDetails(err), env.CorrelationID)
+		case errors.Is(err, game.ErrInvalidStateForTeam):
+			conn.SendError(ErrCodeInvalidState, "Cannot change team in current state", env.CorrelationID)
+		case errors.Is(err, game.ErrDraftInProgress):
+			conn.SendError(ErrCodeInvalidState, "Team cannot be submitted until the draft is complete", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to submit team", env.CorrelationID)
+		}
 	}
+}
+
+// BroadcastTeamSubmitted tells the lobby a player submitted their team and starts the game if that was all it waited for
+func (h *Handler) BroadcastTeamSubmitted(lobby *game.Lobby, playerID string) {
+	h.broadcastLobbyUpdate(lobby, LobbyEventTeamSubmitted, TeamSubmittedEventData{PlayerID: playerID})
 
-	// TODO: Implement when battle system is added
-	conn.SendError(ErrCodeInvalidState, "No game to rematch", env.CorrelationID)
+	// Check if game should start
+	h.checkAndStartGame(lobby.Code)
 }
 
-// handleLeaveGame handles leave game requests
-func (h *Handler) handleLeaveGame(conn *Connection, env *Envelope) {
+// handleSubmitPick handles a ban or pick on the player's draft turn
+func (h *Handler) handleSubmitPick(conn *Connection, env *Envelope) {
 	if conn.State() != ConnectionStateActive {
 		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
 		return
 	}
 
+	var payload SubmitPickPayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
 	lobbyCode := conn.LobbyCode()
 	playerID := conn.PlayerID()
 
-	// Clean up ready state for this player
-	h.readyTracker.ClearPlayer(lobbyCode, playerID)
+	if _, err := h.lobbyService.SubmitDraftPick(lobbyCode, playerID, payload.SpeciesID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrLobbyNotFound):
+			conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		case errors.Is(err, game.ErrNotYourDraftTurn):
+			conn.SendError(ErrCodeInvalidAction, "Not your turn to draft", env.CorrelationID)
+		case errors.Is(err, game.ErrSpeciesNotAvailable):
+			conn.SendError(ErrCodeInvalidAction, "Species is not available", env.CorrelationID)
+		case errors.Is(err, game.ErrDraftNotStarted), errors.Is(err, game.ErrDraftComplete):
+			conn.SendError(ErrCodeInvalidState, "No draft turn to take", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to submit pick", env.CorrelationID)
+		}
+		return
+	}
+
+	h.advanceDraft(lobbyCode)
+}
 
-	// Remove player from lobby
-	err := h.lobbyService.LeaveLobby(lobbyCode, playerID)
-	if err != nil {
-		// Player may already be removed, that's okay
-		if !errors.Is(err, game.ErrPlayerNotFound) && !errors.Is(err, services.ErrLobbyNotFound) {
-			conn.SendError(ErrCodeInternalError, "Failed to leave lobby", env.CorrelationID)
+// checkAndStartDraft starts a draft-mode lobby's draft once both players are connected
+func (h *Handler) checkAndStartDraft(lobbyCode string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil || !lobby.DraftMode() {
+		return
+	}
+
+	for _, p := range lobby.GetPlayers() {
+		if !h.hub.IsPlayerConnected(p.ID) {
 			return
 		}
 	}
 
-	// Notify remaining players
+	if _, started, err := h.lobbyService.StartDraft(lobbyCode); err != nil || !started {
+		return
+	}
+	h.advanceDraft(lobbyCode)
+}
+
+// advanceDraft times the draft's next turn and broadcasts the draft to both players.
+// The timer is cleared once the draft is complete.
+func (h *Handler) advanceDraft(lobbyCode string) {
 	lobby, err := h.lobbyService.GetLobby(lobbyCode)
-	if err == nil {
-		h.broadcastLobbyUpdate(lobby, LobbyEventPlayerLeft, PlayerLeftEventData{
-			PlayerID: playerID,
+	if err != nil {
+		return
+	}
+	draft, ok := lobby.GetDraft()
+	if !ok {
+		return
+	}
+
+	var deadline time.Time
+	if draft.Complete() {
+		h.stopDraftTimer(lobbyCode)
+	} else {
+		step := draft.Step
+		deadline = h.startDraftTimer(lobbyCode, func() {
+			h.expireDraftTurn(lobbyCode, step)
 		})
 	}
 
-	// Close connection
-	h.hub.Unregister(conn)
+	h.hub.BroadcastToLobby(lobbyCode, TypeDraftState, buildDraftState(draft, deadline))
 }
 
-// sendLobbyState sends the current lobby state to a connection
-func (h *Handler) sendLobbyState(conn *Connection, lobby *game.Lobby) {
-	lobbyInfo := h.buildLobbyInfo(lobby)
-	payload := LobbyUpdatedPayload{
-		Lobby: lobbyInfo,
-		Event: LobbyEventStateChanged,
+// expireDraftTurn resolves a draft turn that timed out.
+// It does nothing if the player already chose or the draft was reset.
+func (h *Handler) expireDraftTurn(lobbyCode string, step int) {
+	if _, expired, err := h.lobbyService.ExpireDraftTurn(lobbyCode, step); err != nil || !expired {
+		return
 	}
-	conn.SendMessage(TypeLobbyUpdated, payload)
+	h.advanceDraft(lobbyCode)
 }
 
-// broadcastLobbyUpdate broadcasts a lobby update to all players in the lobby
-func (h *Handler) broadcastLobbyUpdate(lobby *game.Lobby, event LobbyEvent, eventData interface{}) {
-	lobbyInfo := h.buildLobbyInfo(lobby)
-	payload := LobbyUpdatedPayload{
-		Lobby: lobbyInfo,
-		Event: event,
-	}
+// startDraftTimer schedules a lobby's draft turn to expire, replacing any earlier timer, and returns its deadline
+func (h *Handler) startDraftTimer(lobbyCode string, fn func()) time.Time {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
 
-	if eventData != nil {
-		data, _ := lobbyInfo.MarshalEventData(eventData)
-		payload.EventData = data
+	if t, exists := h.draftTimers[lobbyCode]; exists {
+		t.timer.Stop()
 	}
-
-	h.hub.BroadcastToLobby(lobby.Code, TypeLobbyUpdated, payload)
+	deadline := time.Now().Add(h.draftPickTimeout)
+	h.draftTimers[lobbyCode] = &draftTimer{timer: time.AfterFunc(h.draftPickTimeout, fn), deadline: deadline}
+	return deadline
 }
 
-// buildLobbyInfo creates a LobbyInfo from a game.Lobby
-func (h *Handler) buildLobbyInfo(lobby *game.Lobby) LobbyInfo {
-	players := lobby.GetPlayers()
-	hostID := lobby.GetHostID()
+// stopDraftTimer cancels a lobby's pending draft turn expiry
+func (h *Handler) stopDraftTimer(lobbyCode string) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
 
-	playerInfos := make([]LobbyPlayerInfo, len(players))
-	for i, p := range players {
-		// Player is ready only if they have set ready AND are currently connected
-		isReady := h.readyTracker.IsReady(lobby.Code, p.ID) && h.hub.IsPlayerConnected(p.ID)
-		playerInfos[i] = LobbyPlayerInfo{
-			ID:       p.ID,
-			Username: p.Username,
-			IsHost:   p.ID == hostID,
-			IsReady:  isReady,
-		}
+	if t, exists := h.draftTimers[lobbyCode]; exists {
+		t.timer.Stop()
+		delete(h.draftTimers, lobbyCode)
 	}
+}
 
-	return LobbyInfo{
-		Code:    lobby.Code,
-		State:   lobby.GetState().String(),
-		Players: playerInfos,
+// draftDeadline returns when a lobby's current draft turn expires, or the zero time if none is running
+func (h *Handler) draftDeadline(lobbyCode string) time.Time {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	if t, exists := h.draftTimers[lobbyCode]; exists {
+		return t.deadline
 	}
+	return time.Time{}
 }
 
-// MarshalEventData marshals event data to JSON
-func (l *LobbyInfo) MarshalEventData(data interface{}) ([]byte, error) {
-	if data == nil {
-		return nil, nil
+// buildDraftState describes a draft for the draft_state message
+func buildDraftState(draft *game.Draft, deadline time.Time) DraftStatePayload {
+	payload := DraftStatePayload{
+		Pool:     draft.Pool,
+		Bans:     draft.Bans,
+		Picks:    draft.Picks,
+		Step:     draft.Step,
+		Complete: draft.Complete(),
 	}
-	return json.Marshal(data)
+	if turn, ok := draft.Current(); ok {
+		payload.CurrentTurn = &DraftTurnInfo{PlayerID: turn.PlayerID, Kind: string(turn.Kind)}
+		if !deadline.IsZero() {
+			payload.TurnDeadline = deadline.UnixMilli()
+		}
+	}
+	return payload
 }
 
-// BroadcastPlayerJoined broadcasts a player joined event
-func (h *Handler) BroadcastPlayerJoined(lobbyCode string, playerID, username string) {
-	lobby, err := h.lobbyService.GetLobby(lobbyCode)
-	if err != nil {
+// handleChooseLead handles lead choices during team preview.
+// Once both players have chosen, each is sent the game state for turn 1.
+func (h *Handler) handleChooseLead(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
 		return
 	}
-	h.broadcastLobbyUpdate(lobby, LobbyEventPlayerJoined, PlayerJoinedEventData{
-		PlayerID: playerID,
-		Username: username,
-	})
-}
 
-// BroadcastPlayerLeft broadcasts a player left event
-func (h *Handler) BroadcastPlayerLeft(lobbyCode string, playerID string) {
-	lobby, err := h.lobbyService.GetLobby(lobbyCode)
-	if err != nil {
+	var payload ChooseLeadPayload
+	if !parsePayload(conn, env, &payload) {
 		return
 	}
-	h.broadcastLobbyUpdate(lobby, LobbyEventPlayerLeft, PlayerLeftEventData{
-		PlayerID: playerID,
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		ready, err := h.battleService.ChooseLead(battle.ID, conn.PlayerID(), payload.Slot)
+		if err != nil {
+			switch {
+			case errors.Is(err, game.ErrInvalidLead):
+				conn.SendError(ErrCodeInvalidAction, "Invalid lead slot", env.CorrelationID)
+			case errors.Is(err, game.ErrLeadAlreadyChosen):
+				conn.SendError(ErrCodeInvalidAction, "Lead already chosen", env.CorrelationID)
+			case errors.Is(err, game.ErrBattlePaused):
+				conn.SendError(ErrCodeInvalidState, "Battle is paused", env.CorrelationID)
+			case errors.Is(err, game.ErrNotInTeamPreview), errors.Is(err, game.ErrBattleOver),
+				errors.Is(err, services.ErrBattleNotFound):
+				conn.SendError(ErrCodeInvalidState, "Battle is not in team preview", env.CorrelationID)
+			case errors.Is(err, game.ErrPlayerNotInBattle):
+				conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+			default:
+				conn.SendError(ErrCodeInternalError, "Failed to choose lead", env.CorrelationID)
+			}
+			return
+		}
+
+		if ready {
+			h.broadcastGameState(lobbyCode, battle)
+			h.playBots(lobbyCode, battle)
+		}
 	})
 }
 
-// BroadcastGameStarting broadcasts a game starting event
-func (h *Handler) BroadcastGameStarting(lobbyCode string, countdownSec int) {
-	startsAt := time.Now().Add(time.Duration(countdownSec) * time.Second).UnixMilli()
-	payload := GameStartingPayload{
-		StartsAt:     startsAt,
-		CountdownSec: countdownSec,
+// dispatchToLobbyGame queues cmd on the goroutine of the game in progress in the connection's lobby,
+// so the read loop never waits on turn resolution. The connection is told when there is no such game.
+func (h *Handler) dispatchToLobbyGame(conn *Connection, env *Envelope, cmd services.GameCommand) {
+	battle, err := h.battleService.GetLobbyBattle(conn.LobbyCode())
+	if err != nil {
+		conn.SendError(ErrCodeInvalidState, "No active battle", env.CorrelationID)
+		return
 	}
-	h.hub.BroadcastToLobby(lobbyCode, TypeGameStarting, payload)
-}
-
-// isPlayerReady checks if a player has set ready (used by tests)
-func (h *Handler) isPlayerReady(lobbyCode, playerID string) bool {
-	return h.readyTracker.IsReady(lobbyCode, playerID)
+	h.dispatchToGame(conn, env, battle.ID, cmd)
 }
 
-// HandlePlayerDisconnect handles cleanup when a player disconnects unexpectedly
-func (h *Handler) HandlePlayerDisconnect(playerID, lobbyCode string) {
-	h.readyTracker.ClearPlayer(lobbyCode, playerID)
+// dispatchToGame queues cmd on the game's goroutine, telling the connection if the game has ended
+func (h *Handler) dispatchToGame(conn *Connection, env *Envelope, gameID string, cmd services.GameCommand) {
+	if err := h.battleService.Dispatch(gameID, cmd); err != nil {
+		conn.SendError(ErrCodeInvalidState, "No active battle", env.CorrelationID)
+	}
 }
 
-// checkAndStartGame checks if conditions are met to start the game
-func (h *Handler) checkAndStartGame(lobbyCode string) {
-	lobby, err := h.lobbyService.GetLobby(lobbyCode)
-	if err != nil {
+// handleSubmitAction handles battle action submissions
+func (h *Handler) handleSubmitAction(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
 		return
 	}
 
-	players := lobby.GetPlayers()
-	if len(players) != 2 {
+	var payload SubmitActionPayload
+	if !parsePayload(conn, env, &payload) {
 		return
 	}
 
-	// Check both players connected
-	connCount := h.hub.LobbyConnectionCount(lobbyCode)
-	if connCount != 2 {
+	battle, err := h.battleService.GetLobbyBattle(conn.LobbyCode())
+	if err != nil {
+		conn.SendError(ErrCodeInvalidState, "No active battle", env.CorrelationID)
 		return
 	}
 
-	// Check both players ready AND connected
-	playerIDs := make([]string, len(players))
-	for i, p := range players {
-		playerIDs[i] = p.ID
-		if !h.hub.IsPlayerConnected(p.ID) {
+	var action game.Action
+	switch payload.ActionType {
+	case ActionTypeAttack:
+		var data AttackActionData
+		if err := json.Unmarshal(payload.ActionData, &data); err != nil {
+			conn.SendError(ErrCodeMalformedMessage, "Invalid attack action data", env.CorrelationID)
+			return
+		}
+		action = game.Action{Kind: game.ActionKindMove, MoveID: data.MoveID}
+	case ActionTypeSwitch:
+		var data SwitchActionData
+		if err := json.Unmarshal(payload.ActionData, &data); err != nil {
+			conn.SendError(ErrCodeMalformedMessage, "Invalid switch action data", env.CorrelationID)
 			return
 		}
+		action = game.Action{Kind: game.ActionKindSwitch, SwitchSlot: data.CreatureSlot}
+	case ActionTypeItem:
+		var data ItemActionData
+		if err := json.Unmarshal(payload.ActionData, &data); err != nil {
+			conn.SendError(ErrCodeMalformedMessage, "Invalid item action data", env.CorrelationID)
+			return
+		}
+		action = game.Action{Kind: game.ActionKindItem, ItemID: data.ItemID, ItemSlot: data.TargetSlot}
+	case ActionTypeForfeit:
+		// Needs no action data
+	default:
+		conn.SendError(ErrCodeInvalidAction, "Unsupported action type", env.CorrelationID)
+		return
 	}
 
-	if !h.readyTracker.AllReady(lobbyCode, playerIDs) {
+	h.dispatchToGame(conn, env, battle.ID, func(battle *game.Battle) {
+		switch {
+		case payload.GameID != "" && payload.GameID != battle.ID:
+			conn.SendError(ErrCodeInvalidState, "Action is for a game that is no longer in progress", env.CorrelationID)
+		case payload.ActionType == ActionTypeForfeit:
+			h.handleForfeit(conn, env, battle)
+		case payload.TurnNumber != battle.CurrentTurn():
+			conn.SendError(ErrCodeTurnMismatch,
+				fmt.Sprintf("Action is for turn %d, but the battle is on turn %d", payload.TurnNumber, battle.CurrentTurn()),
+				env.CorrelationID)
+		case action.Kind == game.ActionKindSwitch && containsPlayer(battle.PendingSwitches(), conn.PlayerID()):
+			h.handleForcedSwitch(conn, env, battle, action.SwitchSlot)
+		default:
+			h.submitAction(conn, env, battle, action)
+		}
+	})
+}
+
+// submitAction records the player's action for the turn, acknowledges it and publishes the turn once
+// it resolves. Resubmitting the same action for the turn is acknowledged again without effect.
+func (h *Handler) submitAction(conn *Connection, env *Envelope, battle *game.Battle, action game.Action) {
+	turn := battle.CurrentTurn()
+	_, resubmitted := battle.SubmittedAction(conn.PlayerID())
+	result, err := h.battleService.SubmitAction(battle.ID, conn.PlayerID(), action)
+	if err != nil {
+		switch {
+		case errors.Is(err, game.ErrNoPPLeft):
+			conn.SendError(ErrCodeInvalidAction, "Move has no PP left", env.CorrelationID)
+		case errors.Is(err, game.ErrMoveNotKnown):
+			conn.SendError(ErrCodeInvalidAction, "Active creature does not know that move", env.CorrelationID)
+		case errors.Is(err, game.ErrCreatureFainted):
+			conn.SendError(ErrCodeInvalidAction, "Active creature has fainted", env.CorrelationID)
+		case errors.Is(err, game.ErrActionAlreadySubmitted):
+			conn.SendError(ErrCodeInvalidAction, "Action already submitted this turn", env.CorrelationID)
+		case errors.Is(err, game.ErrInvalidSwitchTarget):
+			conn.SendError(ErrCodeInvalidAction, "Invalid switch target", env.CorrelationID)
+		case errors.Is(err, game.ErrUnknownBagItem), errors.Is(err, game.ErrItemNotInBag):
+			conn.SendError(ErrCodeInvalidAction, "Item not in bag", env.CorrelationID)
+		case errors.Is(err, game.ErrInvalidItemTarget):
+			conn.SendError(ErrCodeInvalidAction, "Invalid item target", env.CorrelationID)
+		case errors.Is(err, game.ErrItemHasNoEffect):
+			conn.SendError(ErrCodeInvalidAction, "Item would have no effect", env.CorrelationID)
+		case errors.Is(err, game.ErrAwaitingSwitch):
+			conn.SendError(ErrCodeInvalidState, "Waiting for a fainted creature to be replaced", env.CorrelationID)
+		case errors.Is(err, game.ErrInTeamPreview):
+			conn.SendError(ErrCodeInvalidState, "Waiting for both players to choose a lead", env.CorrelationID)
+		case errors.Is(err, game.ErrBattlePaused):
+			conn.SendError(ErrCodeInvalidState, "Battle is paused", env.CorrelationID)
+		case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+			conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+		case errors.Is(err, game.ErrPlayerNotInBattle):
+			conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to submit action", env.CorrelationID)
+		}
 		return
 	}
 
-	// Start game sequence
-	h.BroadcastGameStarting(lobbyCode, 0) // No countdown, immediate
-	h.broadcastGameStarted(lobbyCode)
-	h.readyTracker.ClearLobby(lobbyCode)
+	conn.SendMessageWithCorrelation(TypeActionAcknowledged, env.CorrelationID, ActionAcknowledgedPayload{TurnNumber: turn})
+	if result != nil {
+		h.publishTurnResult(conn.LobbyCode(), battle, result)
+		return
+	}
+	if !resubmitted {
+		h.notifyOpponentCommitted(battle, conn.PlayerID(), turn)
+	}
 }
 
-// broadcastGameStarted broadcasts that the game has started
-func (h *Handler) broadcastGameStarted(lobbyCode string) {
-	payload := GameStartedPayload{
-		GameID: lobbyCode, // Use lobby code as game ID for now
+// notifyOpponentCommitted tells the player's opponent that they are now waiting on the opponent alone
+func (h *Handler) notifyOpponentCommitted(battle *game.Battle, playerID string, turn int) {
+	for _, side := range battle.Sides {
+		if side.PlayerID != playerID {
+			h.hub.SendToPlayer(side.PlayerID, TypeOpponentCommitted, OpponentCommittedPayload{TurnNumber: turn})
+		}
+	}
+}
+
+// publishTurnResult broadcasts a resolved turn, then prompts forced switches and lets any bot act on the next turn.
+// A turn that ended the game is followed by game_ended once the battle service publishes GameEnded.
+func (h *Handler) publishTurnResult(lobbyCode string, battle *game.Battle, result *services.TurnResult) {
+	h.broadcastTurnResult(lobbyCode, battle, result.Turn, result.Events)
+	if result.Outcome != nil {
+		return
+	}
+	h.requestForcedSwitches(lobbyCode, battle)
+	h.playBots(lobbyCode, battle)
+}
+
+// handleForcedSwitch replaces the player's fainted creature and shares the switch with both players
+func (h *Handler) handleForcedSwitch(conn *Connection, env *Envelope, battle *game.Battle, slot int) {
+	result, err := h.battleService.SubmitForcedSwitch(battle.ID, conn.PlayerID(), slot)
+	if err != nil {
+		switch {
+		case errors.Is(err, game.ErrInvalidSwitchTarget):
+			conn.SendError(ErrCodeInvalidAction, "Invalid switch target", env.CorrelationID)
+		case errors.Is(err, game.ErrNoSwitchRequired):
+			conn.SendError(ErrCodeInvalidState, "No switch required", env.CorrelationID)
+		case errors.Is(err, game.ErrBattlePaused):
+			conn.SendError(ErrCodeInvalidState, "Battle is paused", env.CorrelationID)
+		case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+			conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to switch", env.CorrelationID)
+		}
+		return
+	}
+
+	h.stopSwitchTimer(conn.PlayerID())
+	h.publishTurnResult(conn.LobbyCode(), battle, result)
+}
+
+// handleForfeit ends the battle with the forfeiting player as the loser
+func (h *Handler) handleForfeit(conn *Connection, env *Envelope, battle *game.Battle) {
+	if _, err := h.battleService.Forfeit(battle.ID, conn.PlayerID()); err != nil {
+		switch {
+		case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+			conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+		default:
+			conn.SendError(ErrCodeInternalError, "Failed to forfeit", env.CorrelationID)
+		}
+	}
+}
+
+// handleOfferDraw proposes a draw to the opponent and announces the offer to both players
+func (h *Handler) handleOfferDraw(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		if err := h.battleService.OfferDraw(battle.ID, conn.PlayerID()); err != nil {
+			switch {
+			case errors.Is(err, game.ErrDrawAlreadyOffered):
+				conn.SendError(ErrCodeInvalidState, "Draw already offered", env.CorrelationID)
+			case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+				conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+			case errors.Is(err, game.ErrPlayerNotInBattle):
+				conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+			default:
+				conn.SendError(ErrCodeInternalError, "Failed to offer draw", env.CorrelationID)
+			}
+			return
+		}
+
+		h.offerDraw(lobbyCode, conn.PlayerID())
+		h.answerBotDrawOffers(lobbyCode, battle)
+	})
+}
+
+// offerDraw tells the lobby a player offered a draw. Their opponent is asked for an answer with draw_offered
+// as a request, and if none comes before the draw offer timeout the offer is declined for them.
+func (h *Handler) offerDraw(lobbyCode, playerID string) {
+	payload := DrawOfferedPayload{PlayerID: playerID}
+
+	var opponent *Connection
+	if lobby, err := h.lobbyService.GetLobby(lobbyCode); err == nil {
+		for _, id := range humanPlayerIDs(lobby.GetPlayers()) {
+			if id != playerID {
+				opponent = h.hub.GetConnectionByPlayerID(id)
+			}
+		}
+	}
+	if opponent == nil {
+		h.hub.BroadcastToLobby(lobbyCode, TypeDrawOffered, payload)
+		return
+	}
+
+	h.hub.BroadcastToLobbyExcept(lobbyCode, opponent.PlayerID(), TypeDrawOffered, payload)
+	opponent.Request(TypeDrawOffered, payload, TypeRespondDraw, h.drawOfferTimeout, func(reply *Envelope, err error) {
+		switch {
+		case err == nil:
+			h.handleRespondDraw(opponent, reply)
+		case errors.Is(err, ErrRequestTimeout):
+			h.expireDrawOffer(lobbyCode, opponent.PlayerID())
+		}
+	})
+}
+
+// expireDrawOffer declines the draw offer a player did not answer in time
+func (h *Handler) expireDrawOffer(lobbyCode, playerID string) {
+	battle, err := h.battleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		return
+	}
+	h.battleService.Dispatch(battle.ID, func(battle *game.Battle) {
+		if _, err := h.battleService.RespondDraw(battle.ID, playerID, false); err == nil {
+			h.hub.BroadcastToLobby(lobbyCode, TypeDrawDeclined, DrawDeclinedPayload{PlayerID: playerID})
+		}
+	})
+}
+
+// handleRespondDraw answers the opponent's draw offer, ending the game as a draw on acceptance
+func (h *Handler) handleRespondDraw(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload RespondDrawPayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+	conn.cancelRequests(TypeRespondDraw) // Answered without replying to draw_offered
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		result, err := h.battleService.RespondDraw(battle.ID, conn.PlayerID(), payload.Accept)
+		if err != nil {
+			switch {
+			case errors.Is(err, game.ErrNoDrawOffer):
+				conn.SendError(ErrCodeInvalidState, "No draw offer to respond to", env.CorrelationID)
+			case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+				conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+			case errors.Is(err, game.ErrPlayerNotInBattle):
+				conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+			default:
+				conn.SendError(ErrCodeInternalError, "Failed to respond to draw", env.CorrelationID)
+			}
+			return
+		}
+
+		if result == nil {
+			h.hub.BroadcastToLobby(lobbyCode, TypeDrawDeclined, DrawDeclinedPayload{PlayerID: conn.PlayerID()})
+		}
+	})
+}
+
+// finishGame announces the battle outcome and the lobby's transition out of active when the battle service
+// publishes GameEnded, on the game's goroutine
+func (h *Handler) finishGame(lobbyCode string, battle *game.Battle, result *services.TurnResult) {
+	h.stopPauseTimer(battle.ID)
+	for _, side := range battle.Sides {
+		h.stopDisconnectTimer(side.PlayerID)
+	}
+	series := buildSeriesInfo(result.Series)
+	h.broadcastGameEnded(battle, result.Outcome, result.ReplayID, series)
+	if series != nil && result.Series.IsOver() {
+		h.hub.BroadcastToLobby(lobbyCode, TypeSeriesEnded, SeriesEndedPayload{
+			WinnerID: result.Outcome.WinnerID,
+			LoserID:  result.Outcome.LoserID,
+			Series:   *series,
+		})
+	}
+
+	if lobby, err := h.lobbyService.GetLobby(lobbyCode); err == nil {
+		h.broadcastLobbyUpdate(lobby, LobbyEventStateChanged, StateChangedEventData{
+			OldState: game.LobbyStateActive.String(),
+			NewState: lobby.GetState().String(),
+		})
+	}
+}
+
+// broadcastGameEnded sends each player the outcome and their view of the final state.
+// The final state is always complete, and the next game's states start afresh.
+func (h *Handler) broadcastGameEnded(battle *game.Battle, outcome *game.BattleOutcome, replayID string, series *SeriesInfo) {
+	for _, side := range battle.Sides {
+		h.resetStateViews(side.PlayerID)
+		finalState := buildGameState(battle, side.PlayerID)
+		h.hub.SendToPlayer(side.PlayerID, TypeGameEnded, GameEndedPayload{
+			WinnerID:    outcome.WinnerID,
+			LoserID:     outcome.LoserID,
+			Reason:      GameEndReason(outcome.Reason),
+			Draw:        outcome.IsDraw(),
+			FinalState:  &finalState,
+			Seed:        outcome.Seed,
+			SeedSalt:    outcome.SeedSalt,
+			DataVersion: battle.Data.Version,
+			ReplayID:    replayID,
+			Series:      series,
+			Stats:       buildBattleStats(battle),
+		})
+	}
+}
+
+// broadcastGameState sends each player their view of the current state, and the lobby's spectators the full view
+func (h *Handler) broadcastGameState(lobbyCode string, battle *game.Battle) {
+	for _, side := range battle.Sides {
+		h.sendGameState(battle, side.PlayerID)
+	}
+	h.hub.BroadcastToSpectators(lobbyCode, TypeSpectatorState, buildSpectatorState(battle))
+}
+
+// broadcastTeamPreview sends each player their team and the opposing species
+func (h *Handler) broadcastTeamPreview(battle *game.Battle) {
+	for _, side := range battle.Sides {
+		h.hub.SendToPlayer(side.PlayerID, TypeTeamPreview, buildTeamPreview(battle, side.PlayerID))
+	}
+}
+
+// broadcastTurnResult sends each player the turn events and their view of the resulting state.
+// Players receiving delta updates get the state as a game_state_delta following the events,
+// and the lobby's spectators get the full view as a spectator_state following them.
+func (h *Handler) broadcastTurnResult(lobbyCode string, battle *game.Battle, turn int, battleEvents []game.BattleEvent) {
+	events := buildTurnEvents(battleEvents)
+	for _, side := range battle.Sides {
+		if !h.hub.IsPlayerConnected(side.PlayerID) {
+			continue
+		}
+
+		state, delta := h.recordStateView(side.PlayerID, buildGameState(battle, side.PlayerID))
+		if delta != nil && h.hub.PlayerDeltaUpdates(side.PlayerID) {
+			h.hub.SendToPlayer(side.PlayerID, TypeTurnResult, TurnResultPayload{TurnNumber: turn, Events: events})
+			h.hub.SendToPlayer(side.PlayerID, TypeGameStateDelta, delta)
+			continue
+		}
+		h.hub.SendToPlayer(side.PlayerID, TypeTurnResult, TurnResultPayload{
+			TurnNumber:     turn,
+			Events:         events,
+			ResultingState: &state,
+		})
+	}
+
+	h.hub.BroadcastToSpectators(lobbyCode, TypeTurnResult, TurnResultPayload{TurnNumber: turn, Events: events})
+	h.hub.BroadcastToSpectators(lobbyCode, TypeSpectatorState, buildSpectatorState(battle))
+}
+
+// requestForcedSwitches prompts each player whose creature fainted to pick a replacement.
+// Players who do not choose before the timeout get the first healthy creature.
+func (h *Handler) requestForcedSwitches(lobbyCode string, battle *game.Battle) {
+	turn := battle.CurrentTurn()
+	timeoutAt := time.Now().Add(h.switchTimeout).UnixMilli()
+
+	for _, playerID := range battle.PendingSwitches() {
+		slots, err := battle.SwitchTargets(playerID)
+		if err != nil {
+			continue
+		}
+		h.hub.SendToPlayer(playerID, TypeSwitchRequired, SwitchRequiredPayload{
+			Reason:         "fainted",
+			AvailableSlots: slots,
+			TimeoutAt:      timeoutAt,
+		})
+
+		playerID := playerID
+		h.startSwitchTimer(playerID, func() {
+			h.autoSwitch(lobbyCode, battle.ID, playerID, turn)
+		})
+	}
+}
+
+// startSwitchTimer schedules an auto-switch for a player, replacing any earlier timer
+func (h *Handler) startSwitchTimer(playerID string, fn func()) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	if t, exists := h.switchTimers[playerID]; exists && t.timer != nil {
+		t.timer.Stop()
+	}
+	h.switchTimers[playerID] = &switchTimer{
+		timer:    time.AfterFunc(h.switchTimeout, fn),
+		deadline: time.Now().Add(h.switchTimeout),
+		fn:       fn,
+	}
+}
+
+// stopSwitchTimer cancels a player's pending auto-switch
+func (h *Handler) stopSwitchTimer(playerID string) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	if t, exists := h.switchTimers[playerID]; exists {
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+		delete(h.switchTimers, playerID)
+	}
+}
+
+// autoSwitch picks a replacement for a player who ran out of time, on the game's goroutine.
+// It does nothing if the player already switched or the game moved on or ended.
+func (h *Handler) autoSwitch(lobbyCode, gameID, playerID string, turn int) {
+	h.battleService.Dispatch(gameID, func(battle *game.Battle) {
+		if battle.CurrentTurn() != turn {
+			return
+		}
+
+		slot, err := battle.AutoSwitchSlot(playerID)
+		if err != nil {
+			return
+		}
+
+		result, err := h.battleService.SubmitForcedSwitch(battle.ID, playerID, slot)
+		if err != nil {
+			return
+		}
+		h.publishTurnResult(lobbyCode, battle, result)
+	})
+}
+
+// containsPlayer checks if a player ID is in the list
+func containsPlayer(playerIDs []string, playerID string) bool {
+	for _, id := range playerIDs {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRequestGameState sends the requester their view of the battle. During team preview
+// that is the team preview; afterwards it is the game state, filtered so the opponent's side
+// only carries public information.
+func (h *Handler) handleRequestGameState(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		if !battle.HasPlayer(conn.PlayerID()) {
+			conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+			return
+		}
+
+		if battle.InTeamPreview() {
+			conn.SendMessageWithCorrelation(TypeTeamPreview, env.CorrelationID, buildTeamPreview(battle, conn.PlayerID()))
+			return
+		}
+		state, _ := h.recordStateView(conn.PlayerID(), buildGameState(battle, conn.PlayerID()))
+		conn.SendMessageWithCorrelation(TypeGameState, env.CorrelationID, state)
+	})
+}
+
+// handleRequestRematch handles rematch requests
+func (h *Handler) handleRequestRematch(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil || lobby.GetState() != game.LobbyStateFinished {
+		conn.SendError(ErrCodeInvalidState, "No game to rematch", env.CorrelationID)
+		return
+	}
+
+	if h.rematchTracker.IsReady(lobbyCode, conn.PlayerID()) {
+		conn.SendError(ErrCodeInvalidState, "Rematch already requested", env.CorrelationID)
+		return
+	}
+
+	h.rematchTracker.SetReady(lobbyCode, conn.PlayerID(), true)
+	h.hub.BroadcastToLobby(lobbyCode, TypeRematchRequested, RematchRequestedPayload{PlayerID: conn.PlayerID()})
+
+	h.checkAndStartRematch(lobbyCode)
+}
+
+// checkAndStartRematch starts the next game once both connected players have requested a rematch.
+// The series score going into the game is sent with rematch_starting. If the lobby asks for new
+// teams on a rematch, the lobby returns to ready instead and the game starts through the usual
+// submit_team and set_ready flow.
+func (h *Handler) checkAndStartRematch(lobbyCode string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	players := lobby.GetPlayers()
+	if len(players) != 2 {
+		return
+	}
+
+	// Bots always accept a rematch
+	playerIDs := humanPlayerIDs(players)
+	for _, playerID := range playerIDs {
+		if !h.hub.IsPlayerConnected(playerID) {
+			return
+		}
+	}
+
+	if !h.rematchTracker.AllReady(lobbyCode, playerIDs) {
+		return
+	}
+
+	if _, err := h.lobbyService.Rematch(lobbyCode); err != nil {
+		return
+	}
+	h.rematchTracker.ClearLobby(lobbyCode)
+
+	if !lobby.AllTeamsSubmitted() {
+		h.hub.BroadcastToLobby(lobbyCode, TypeRematchStarting, RematchStartingPayload{
+			StartsAt:     time.Now().UnixMilli(),
+			CountdownSec: 0,
+			Series:       buildSeriesInfo(lobby.GetSeries()),
+			NewTeams:     true,
+		})
+		h.broadcastLobbyUpdate(lobby, LobbyEventStateChanged, StateChangedEventData{
+			OldState: game.LobbyStateFinished.String(),
+			NewState: lobby.GetState().String(),
+		})
+		return
+	}
+
+	battle, err := h.battleService.StartBattle(lobby)
+	if err != nil {
+		return
+	}
+
+	h.hub.BroadcastToLobby(lobbyCode, TypeRematchStarting, RematchStartingPayload{
+		StartsAt:     time.Now().UnixMilli(),
+		CountdownSec: 0, // No countdown, immediate
+		Series:       buildSeriesInfo(lobby.GetSeries()),
+	})
+	h.broadcastGameStarted(lobbyCode, battle)
+	h.openGame(lobbyCode, battle.ID)
+}
+
+// handleLeaveGame handles leave game requests
+func (h *Handler) handleLeaveGame(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	playerID := conn.PlayerID()
+
+	if conn.IsSpectator() {
+		h.leaveAsSpectator(conn, env)
+		return
+	}
+
+	// Remove player from lobby; the remaining players are told and the connection closed once it is published
+	if _, err := h.lobbyService.LeaveLobby(lobbyCode, playerID); err != nil {
+		// Player may already be removed, that's okay, but the connection still closes
+		if !errors.Is(err, game.ErrPlayerNotFound) && !errors.Is(err, services.ErrLobbyNotFound) {
+			conn.SendError(ErrCodeInternalError, "Failed to leave lobby", env.CorrelationID)
+			return
+		}
+		h.disconnectFromLobby(lobbyCode, playerID)
+	}
+}
+
+// leaveAsSpectator takes a spectator off the lobby's roster, or a waitlisted player off its waitlist,
+// and closes their connection
+func (h *Handler) leaveAsSpectator(conn *Connection, env *Envelope) {
+	lobbyCode := conn.LobbyCode()
+	spectatorID := conn.PlayerID()
+
+	if _, err := h.lobbyService.LeaveLobby(lobbyCode, spectatorID); err != nil {
+		if !errors.Is(err, game.ErrPlayerNotFound) && !errors.Is(err, services.ErrLobbyNotFound) {
+			conn.SendError(ErrCodeInternalError, "Failed to leave lobby", env.CorrelationID)
+			return
+		}
+		h.disconnectFromLobby(lobbyCode, spectatorID)
+	}
+}
+
+// sendLobbyState sends the current lobby state to a connection
+func (h *Handler) sendLobbyState(conn *Connection, lobby *game.Lobby) {
+	lobbyInfo := h.buildLobbyInfo(lobby)
+	payload := LobbyUpdatedPayload{
+		Lobby: lobbyInfo,
+		Event: LobbyEventStateChanged,
+	}
+	conn.SendMessage(TypeLobbyUpdated, payload)
+}
+
+// buildLobbyInfo creates a LobbyInfo from a game.Lobby
+func (h *Handler) buildLobbyInfo(lobby *game.Lobby) LobbyInfo {
+	players := lobby.GetPlayers()
+	hostID := lobby.GetHostID()
+
+	playerInfos := make([]LobbyPlayerInfo, len(players))
+	for i, p := range players {
+		// Player is ready only if they have set ready AND are currently connected; bots are always both
+		isConnected := p.Bot || h.hub.IsPlayerConnected(p.ID)
+		isReady := p.Bot || p.Ready && isConnected
+		playerInfos[i] = LobbyPlayerInfo{
+			ID:            p.ID,
+			Username:      p.Username,
+			IsHost:        p.ID == hostID,
+			IsReady:       isReady,
+			IsConnected:   isConnected,
+			HasTeam:       lobby.HasTeam(p.ID),
+			IsBot:         p.Bot,
+			BotDifficulty: string(p.BotDifficulty),
+		}
+	}
+
+	spectators := lobby.GetSpectators()
+	spectatorInfos := make([]LobbySpectatorInfo, len(spectators))
+	for i, s := range spectators {
+		spectatorInfos[i] = LobbySpectatorInfo{ID: s.ID, Username: s.Username}
+	}
+
+	waitlist := lobby.GetWaitlist()
+	waitlistInfos := make([]LobbyWaitlistInfo, len(waitlist))
+	for i, w := range waitlist {
+		waitlistInfos[i] = LobbyWaitlistInfo{ID: w.ID, Username: w.Username, Position: i + 1}
+	}
+
+	return LobbyInfo{
+		Code:           lobby.Code,
+		State:          lobby.GetState().String(),
+		Ruleset:        lobby.GetRuleset().ID,
+		BestOf:         lobby.GetSeries().BestOf,
+		DraftMode:      lobby.DraftMode(),
+		RematchTeams:   string(lobby.GetRematchTeams()),
+		Players:        playerInfos,
+		Spectators:     spectatorInfos,
+		Waitlist:       waitlistInfos,
+		MaxSpectators:  lobby.Settings().MaxSpectators,
+		Tags:           tagStrings(lobby.GetTags()),
+		CreatedAt:      lobby.CreatedAt.UnixMilli(),
+		LastActivityAt: lobby.LastActivity().UnixMilli(),
+	}
+}
+
+// buildSeriesInfo describes a series score, or returns nil for single-game lobbies
+func buildSeriesInfo(series game.Series) *SeriesInfo {
+	if series.BestOf <= 1 {
+		return nil
+	}
+	return &SeriesInfo{
+		BestOf:      series.BestOf,
+		GamesPlayed: series.Games,
+		Wins:        series.Wins,
+	}
+}
+
+// buildInvalidTeamDetails lists the violations of a team validation error
+func buildInvalidTeamDetails(err error) InvalidTeamDetails {
+	details := InvalidTeamDetails{Violations: []TeamViolationInfo{}}
+
+	var teamErr *game.TeamError
+	if !errors.As(err, &teamErr) {
+		return details
+	}
+
+	for _, v := range teamErr.Violations {
+		info := TeamViolationInfo{Rule: string(v.Rule), Message: v.Err.Error()}
+		if v.Slot >= 0 {
+			slot := v.Slot
+			info.Slot = &slot
+		}
+		details.Violations = append(details.Violations, info)
+	}
+	return details
+}
+
+// MarshalEventData marshals event data to JSON
+func (l *LobbyInfo) MarshalEventData(data interface{}) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+// BroadcastPlayerJoined broadcasts a player joined event
+func (h *Handler) BroadcastPlayerJoined(lobbyCode string, playerID, username string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+	h.broadcastLobbyUpdate(lobby, LobbyEventPlayerJoined, PlayerJoinedEventData{
+		PlayerID: playerID,
+		Username: username,
+	})
+}
+
+// BroadcastPlayerLeft broadcasts a player left event. Leaving abandons the player's rematch request and any
+// draft or game start countdown, and closes the connection they still have to the lobby.
+func (h *Handler) BroadcastPlayerLeft(lobbyCode string, playerID string) {
+	h.rematchTracker.ClearPlayer(lobbyCode, playerID)
+	h.stopDraftTimer(lobbyCode)
+	h.cancelStartCountdown(lobbyCode, GameStartCancelledReasonPlayerLeft, playerID)
+
+	if lobby, err := h.lobbyService.GetLobby(lobbyCode); err == nil {
+		h.broadcastLobbyUpdate(lobby, LobbyEventPlayerLeft, PlayerLeftEventData{
+			PlayerID: playerID,
+		})
+	}
+	h.disconnectFromLobby(lobbyCode, playerID)
+}
+
+// BroadcastSpectatorJoined broadcasts a spectator joined event
+func (h *Handler) BroadcastSpectatorJoined(lobbyCode, spectatorID, username string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+	h.broadcastLobbyUpdate(lobby, LobbyEventSpectatorJoined, SpectatorJoinedEventData{
+		SpectatorID: spectatorID,
+		Username:    username,
+	})
+}
+
+// BroadcastSpectatorLeft broadcasts a spectator left event and closes the connection they still have to the lobby
+func (h *Handler) BroadcastSpectatorLeft(lobbyCode, spectatorID string) {
+	if lobby, err := h.lobbyService.GetLobby(lobbyCode); err == nil {
+		h.broadcastLobbyUpdate(lobby, LobbyEventSpectatorLeft, SpectatorLeftEventData{
+			SpectatorID: spectatorID,
+		})
+	}
+	h.disconnectFromLobby(lobbyCode, spectatorID)
+}
+
+// BroadcastWaitlistJoined broadcasts a waitlist joined event
+func (h *Handler) BroadcastWaitlistJoined(lobbyCode, playerID, username string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+	h.broadcastLobbyUpdate(lobby, LobbyEventWaitlistJoined, WaitlistJoinedEventData{
+		PlayerID: playerID,
+		Username: username,
+	})
+}
+
+// BroadcastWaitlistLeft broadcasts a waitlist left event and closes the connection the player still has to the lobby
+func (h *Handler) BroadcastWaitlistLeft(lobbyCode, playerID string) {
+	if lobby, err := h.lobbyService.GetLobby(lobbyCode); err == nil {
+		h.broadcastLobbyUpdate(lobby, LobbyEventWaitlistLeft, WaitlistLeftEventData{
+			PlayerID: playerID,
+		})
+	}
+	h.disconnectFromLobby(lobbyCode, playerID)
+}
+
+// disconnectFromLobby closes the player's connections to the given lobby and forgets their session there
+func (h *Handler) disconnectFromLobby(lobbyCode, playerID string) {
+	h.hub.sessions.drop(playerID, lobbyCode)
+	for _, conn := range h.hub.PlayerConnections(playerID) {
+		if conn.LobbyCode() == lobbyCode {
+			conn.setCloseReason(CloseCodeLeftLobby, "Left the lobby")
+			h.hub.Unregister(conn)
+		}
+	}
+}
+
+// BroadcastWaitlistPromoted tells players promoted off the waitlist that they now have a slot,
+// announces them to the lobby as having joined, and starts the draft if it was waiting for them
+func (h *Handler) BroadcastWaitlistPromoted(lobby *game.Lobby, promoted []game.WaitlistEntry) {
+	if len(promoted) == 0 {
+		return
+	}
+
+	for _, p := range promoted {
+		// A promoted player already connected was watching the lobby and now plays in it, on each device they are on
+		promoted := false
+		for _, conn := range h.hub.PlayerConnections(p.ID) {
+			if conn.LobbyCode() == lobby.Code {
+				conn.SetRole(RolePlayer)
+				promoted = true
+			}
+		}
+		if promoted {
+			h.hub.SendToPlayer(p.ID, TypeWaitlistPromoted, WaitlistPromotedPayload{LobbyCode: lobby.Code})
+		}
+		h.broadcastLobbyUpdate(lobby, LobbyEventPlayerJoined, PlayerJoinedEventData{
+			PlayerID: p.ID,
+			Username: p.Username,
+		})
+	}
+
+	h.checkAndStartDraft(lobby.Code)
+}
+
+// BroadcastSettingsChanged tells the lobby's connected clients that the host changed its settings
+func (h *Handler) BroadcastSettingsChanged(lobby *game.Lobby) {
+	settings := lobby.Settings()
+	h.broadcastLobbyUpdate(lobby, LobbyEventSettingsChanged, SettingsChangedEventData{
+		Ruleset:       settings.Ruleset.ID,
+		BestOf:        settings.BestOf,
+		TurnTimerSec:  int(settings.TurnTimer / time.Second),
+		CountdownSec:  int(settings.Countdown / time.Second),
+		StartMode:     string(settings.StartMode),
+		Visibility:    string(settings.Visibility),
+		MaxSpectators: settings.MaxSpectators,
+		Tags:          tagStrings(settings.Tags),
+	})
+}
+
+// tagStrings converts lobby tags to their string form, never returning nil
+func tagStrings(tags []game.LobbyTag) []string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = string(tag)
+	}
+	return values
+}
+
+// HandleLobbyExpired closes a lobby the janitor removed for being idle
+func (h *Handler) HandleLobbyExpired(lobby *game.Lobby) {
+	h.closeLobby(lobby, LobbyClosedReasonIdle)
+}
+
+// CloseLobby closes a lobby its host deleted
+func (h *Handler) CloseLobby(lobby *game.Lobby) {
+	h.closeLobby(lobby, LobbyClosedReasonHost)
+}
+
+// closeLobby cleans up after a lobby that has been removed: any game in progress is discarded with its
+// pause and switch timers, the lobby's rematch and draft state is forgotten, and every connection
+// is sent lobby_closed and then disconnected
+func (h *Handler) closeLobby(lobby *game.Lobby, reason LobbyClosedReason) {
+	if gameID, err := h.battleService.AbandonLobbyBattle(lobby.Code); err == nil {
+		h.stopPauseTimer(gameID)
+		for _, p := range lobby.GetPlayers() {
+			h.stopSwitchTimer(p.ID)
+			h.stopDisconnectTimer(p.ID)
+		}
+	}
+	h.rematchTracker.ClearLobby(lobby.Code)
+	h.stopDraftTimer(lobby.Code)
+	h.stopStartCountdown(lobby.Code)
+	h.stopLobbyUpdates(lobby.Code)
+	h.hub.sessions.dropLobby(lobby.Code)
+	h.hub.dropLobbyLog(lobby.Code)
+
+	payload := LobbyClosedPayload{Code: lobby.Code, Reason: reason}
+	for _, conn := range h.hub.GetLobbyConnections(lobby.Code) {
+		conn.SendMessage(TypeLobbyClosed, payload)
+		conn.CloseWithCode(CloseCodeLobbyClosed, "Lobby closed: "+string(reason))
+	}
+}
+
+// BroadcastGameStarting broadcasts a game starting event
+func (h *Handler) BroadcastGameStarting(lobbyCode string, countdownSec int) {
+	startsAt := time.Now().Add(time.Duration(countdownSec) * time.Second).UnixMilli()
+	payload := GameStartingPayload{
+		StartsAt:     startsAt,
+		CountdownSec: countdownSec,
+	}
+	h.hub.BroadcastToLobby(lobbyCode, TypeGameStarting, payload)
+}
+
+// isPlayerReady checks if a player has set ready (used by tests)
+func (h *Handler) isPlayerReady(lobbyCode, playerID string) bool {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	return err == nil && lobby.IsReady(playerID)
+}
+
+// HandlePlayerDisconnect handles cleanup when a player disconnects unexpectedly; the hub calls it for every
+// authenticated connection it drops. The rest of the lobby is told with player_disconnected, and a battle in
+// progress is paused until they reconnect, for as long as their pause budget lasts.
+func (h *Handler) HandlePlayerDisconnect(playerID, lobbyCode string) {
+	// A player who reconnected before their old connection was dropped hasn't gone anywhere
+	if h.hub.IsPlayerConnected(playerID) {
+		return
+	}
+
+	// Spectators and waitlisted players keep their place until they leave, and have no game state to clean up
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err == nil && (lobby.HasSpectator(playerID) || lobby.WaitlistPosition(playerID) > 0) {
+		return
+	}
+	// Players who left the lobby were already announced with player_left
+	if err == nil && lobby.HasPlayer(playerID) {
+		h.hub.BroadcastToLobby(lobbyCode, TypePlayerDisconnected, PlayerDisconnectedPayload{PlayerID: playerID})
+	}
+	h.rematchTracker.ClearPlayer(lobbyCode, playerID)
+	h.cancelStartCountdown(lobbyCode, GameStartCancelledReasonPlayerLeft, playerID)
+
+	// A player who drops is no longer ready, which the lobby hears of through the lobby service;
+	// this fails harmlessly once the game has started
+	if err == nil && lobby.IsReady(playerID) {
+		h.lobbyService.SetReady(lobbyCode, playerID, false)
+	}
+	h.pauseForDisconnect(lobbyCode, playerID)
+}
+
+// checkAndStartGame starts the game once its conditions are met, unless the lobby waits for the host to start it
+func (h *Handler) checkAndStartGame(lobbyCode string) {
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	if lobby.Settings().StartMode == game.StartModeHost || !h.canStartGame(lobby) {
+		return
+	}
+
+	h.beginGame(lobbyCode, lobby)
+}
+
+// handleStartGame lets the host start the game in a lobby that waits for them, once every player is ready
+func (h *Handler) handleStartGame(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		return
+	}
+
+	switch {
+	case !lobby.IsHost(conn.PlayerID()):
+		conn.SendError(ErrCodeInvalidAction, "Only the host can start the game", env.CorrelationID)
+	case lobby.Settings().StartMode != game.StartModeHost:
+		conn.SendError(ErrCodeInvalidState, "The game starts once every player is ready", env.CorrelationID)
+	case h.startCountdownRunning(lobbyCode):
+		conn.SendError(ErrCodeInvalidState, "The game is already starting", env.CorrelationID)
+	case !h.canStartGame(lobby):
+		conn.SendError(ErrCodeInvalidState, "Every player must be connected, ready and have submitted a team", env.CorrelationID)
+	default:
+		h.beginGame(lobbyCode, lobby)
+	}
+}
+
+// beginGame starts the lobby's game: after the lobby's countdown if it has one, otherwise immediately
+func (h *Handler) beginGame(lobbyCode string, lobby *game.Lobby) {
+	if countdown := lobby.Settings().Countdown; countdown > 0 {
+		h.beginStartCountdown(lobbyCode, countdown)
+		return
+	}
+
+	battle, err := h.battleService.StartBattle(lobby)
+	if err != nil {
+		return
+	}
+
+	// Start game sequence
+	h.BroadcastGameStarting(lobbyCode, 0) // No countdown, immediate
+	h.broadcastGameStarted(lobbyCode, battle)
+	h.openGame(lobbyCode, battle.ID)
+}
+
+// canStartGame checks the lobby has two players, every human one connected and ready, and all teams submitted
+func (h *Handler) canStartGame(lobby *game.Lobby) bool {
+	players := lobby.GetPlayers()
+	if len(players) != 2 {
+		return false
+	}
+
+	// Check human players ready AND connected; bots play without a connection and are always ready.
+	// Spectators and waitlisted players are connected too, so this goes by player rather than connection count.
+	for _, playerID := range humanPlayerIDs(players) {
+		if !h.hub.IsPlayerConnected(playerID) {
+			return false
+		}
+	}
+
+	return lobby.AllReady() && lobby.AllTeamsSubmitted()
+}
+
+// openGame shows the players the team preview, if the ruleset has one, and lets any bot make
+// its first choice, on the new game's goroutine
+func (h *Handler) openGame(lobbyCode, gameID string) {
+	h.battleService.Dispatch(gameID, func(battle *game.Battle) {
+		if battle.InTeamPreview() {
+			h.broadcastTeamPreview(battle)
+		}
+		h.playBots(lobbyCode, battle)
+	})
+}
+
+// broadcastGameStarted broadcasts that the game has started, with the ID actions can be addressed to
+// the game data it is played with and the commitment to its RNG seed
+func (h *Handler) broadcastGameStarted(lobbyCode string, battle *game.Battle) {
+	payload := GameStartedPayload{
+		GameID:         battle.ID,
+		DataVersion:    battle.Data.Version,
+		SeedCommitment: battle.SeedCommitment(),
+	}
+	if battle.Data.Ruleset != "" {
+		payload.TypeChart = buildTypeChart(battle.Data)
 	}
 	h.hub.BroadcastToLobby(lobbyCode, TypeGameStarted, payload)
 }
@@ -1,12 +1,118 @@
 package websocket
 
 import (
+	"compress/flate"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 const handlerTestTimeout = 2 * time.Second
 
+// ========================================
+// Wire Format Tests
+// ========================================
+
+func TestHandler_Compression(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	// Compress even the smallest message so the whole exchange goes through deflate
+	if err := ts.Handler.SetCompression(flate.BestCompression, 0); err != nil {
+		t.Fatalf("failed to set compression: %v", err)
+	}
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	client, extensions, err := NewCompressedTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+	if !strings.Contains(extensions, "permessage-deflate") {
+		t.Errorf("expected permessage-deflate to be negotiated, got %q", extensions)
+	}
+
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.AssertAuthSuccess(handlerTestTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeLobbyUpdated, handlerTestTimeout); err != nil {
+		t.Fatalf("failed to receive lobby state: %v", err)
+	}
+}
+
+func TestHandler_SetCompression_Invalid(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	if err := ts.Handler.SetCompression(flate.BestCompression+1, 0); err == nil {
+		t.Error("expected an out of range level to be rejected")
+	}
+	if err := ts.Handler.SetCompression(DefaultCompressionLevel, -1); err == nil {
+		t.Error("expected a negative threshold to be rejected")
+	}
+	if ts.Handler.compressionLevel != DefaultCompressionLevel || ts.Handler.compressionThreshold != DefaultCompressionThreshold {
+		t.Error("expected rejected settings to leave the defaults in place")
+	}
+}
+
+func TestHandler_MsgpackSubprotocol(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{SubprotocolMsgpack}
+	conn, _, err := dialer.Dial(ts.WebSocketURL(lobbyCode), nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	if conn.Subprotocol() != SubprotocolMsgpack {
+		t.Fatalf("expected msgpack to be negotiated, got %q", conn.Subprotocol())
+	}
+
+	env, _ := NewEnvelope(TypeAuthenticate, AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode})
+	data, _ := json.Marshal(env)
+	encoded, err := msgpackCodec{}.encode(data)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, encoded); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(handlerTestTimeout))
+	frameType, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	if frameType != websocket.BinaryMessage {
+		t.Errorf("expected a binary frame, got type %d", frameType)
+	}
+	decoded, err := msgpackCodec{}.decode(message)
+	if err != nil {
+		t.Fatalf("expected a MessagePack reply: %v", err)
+	}
+	var reply Envelope
+	if err := json.Unmarshal(decoded, &reply); err != nil || reply.Type != TypeAuthenticated {
+		t.Errorf("expected authenticated, got %s (%v)", decoded, err)
+	}
+}
+
 // ========================================
 // handleSubmitAction Tests
 // ========================================
@@ -28,7 +134,9 @@ func TestHandler_SubmitAction_RequiresAuth(t *testing.T) {
 
 	// Send submit_action without authenticating
 	env, _ := NewEnvelope(TypeSubmitAction, map[string]interface{}{
+		"turn_number": 1,
 		"action_type": "attack",
+		"action_data": map[string]string{"move_id": "tackle"},
 	})
 	if err := client.Send(env); err != nil {
 		t.Fatalf("failed to send: %v", err)
@@ -66,7 +174,9 @@ func TestHandler_SubmitAction_NoActiveBattle(t *testing.T) {
 
 	// Send submit_action when there is no active battle
 	env, _ := NewEnvelope(TypeSubmitAction, map[string]interface{}{
+		"turn_number": 1,
 		"action_type": "attack",
+		"action_data": map[string]string{"move_id": "tackle"},
 	})
 	if err := client.Send(env); err != nil {
 		t.Fatalf("failed to send: %v", err)
@@ -143,6 +253,81 @@ func TestHandler_RequestGameState_NoActiveBattle(t *testing.T) {
 	}
 }
 
+func TestHandler_RequestGameState_FogOfWar(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	client1.SendAttack(1, "swords-dance")
+	client2.SendAttack(1, "swords-dance")
+	if _, err := client1.ReceiveType(TypeTurnResult, handlerTestTimeout); err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+
+	if err := client1.SendRequestGameState(); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	env, err := client1.ReceiveType(TypeGameState, handlerTestTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_state: %v", err)
+	}
+	if env.CorrelationID != "state-player-1" {
+		t.Errorf("expected the request's correlation ID, got %q", env.CorrelationID)
+	}
+
+	var state GameStatePayload
+	if err := env.ParsePayload(&state); err != nil {
+		t.Fatalf("failed to parse game_state: %v", err)
+	}
+	if state.TurnNumber != 2 || state.Phase != GamePhaseActionSelection {
+		t.Errorf("expected action selection on turn 2, got %s on turn %d", state.Phase, state.TurnNumber)
+	}
+	if state.PlayerState.PlayerID != "player-1" || len(state.PlayerState.Team) != 3 {
+		t.Errorf("expected player-1's full team, got %+v", state.PlayerState)
+	}
+
+	opponent := state.OpponentState
+	if opponent.PlayerID != "player-2" || opponent.Team != nil || opponent.Bag != nil {
+		t.Errorf("expected the opponent's team to stay hidden, got %+v", opponent)
+	}
+	if opponent.BenchCount != 2 || opponent.ActiveHP == 0 || opponent.ActiveMaxHP == 0 {
+		t.Errorf("expected the opponent's public active and bench info, got %+v", opponent)
+	}
+	if len(opponent.Revealed) != 1 || !opponent.Revealed[0].IsActive {
+		t.Fatalf("expected only the opposing active to be revealed, got %+v", opponent.Revealed)
+	}
+	if moves := opponent.Revealed[0].Moves; len(moves) != 1 || moves[0].ID != "swords-dance" {
+		t.Errorf("expected only swords-dance to be revealed, got %+v", moves)
+	}
+}
+
+func TestHandler_RequestGameState_TeamPreview(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients, _ := startTeamPreview(t, ts)
+
+	if err := clients[0].SendRequestGameState(); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	env, err := clients[0].ReceiveType(TypeTeamPreview, handlerTestTimeout)
+	if err != nil {
+		t.Fatalf("expected team_preview while leads are being chosen: %v", err)
+	}
+
+	var preview TeamPreviewPayload
+	env.ParsePayload(&preview)
+	if len(preview.Opponent.Team) != 3 {
+		t.Errorf("expected the opposing species, got %+v", preview.Opponent)
+	}
+}
+
 // ========================================
 // handleRequestRematch Tests
 // ========================================
@@ -326,7 +511,7 @@ func TestHandler_BroadcastPlayerJoined(t *testing.T) {
 		t.Fatalf("failed to receive lobby update: %v", err)
 	}
 
-	if update.Event != LobbyEventPlayerJoined {
+	if !hasLobbyEvent(update, LobbyEventPlayerJoined) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerJoined, update.Event)
 	}
 }
@@ -381,11 +566,57 @@ func TestHandler_BroadcastPlayerLeft(t *testing.T) {
 		t.Fatalf("failed to receive lobby update: %v", err)
 	}
 
-	if update.Event != LobbyEventPlayerLeft {
+	if !hasLobbyEvent(update, LobbyEventPlayerLeft) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerLeft, update.Event)
 	}
 }
 
+func TestHandler_BroadcastPlayerLeft_DisconnectsLeaver(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make(map[string]*TestClient)
+	for _, id := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		if err := client.SendAuth(id, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(handlerTestTimeout); err != nil {
+			t.Fatalf("%s auth: %v", id, err)
+		}
+		clients[id] = client
+	}
+	clients["player-1"].Drain()
+
+	// The player leaves over HTTP while still connected
+	if _, err := ts.LobbyService.LeaveLobby(lobbyCode, "player-2"); err != nil {
+		t.Fatalf("failed to leave lobby: %v", err)
+	}
+
+	update, err := clients["player-1"].AssertLobbyUpdated(handlerTestTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby update: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventPlayerLeft) || len(update.Lobby.Players) != 1 {
+		t.Errorf("expected player_left with one player left, got %s with %d players", update.Event, len(update.Lobby.Players))
+	}
+	if !ts.WaitForPlayerDisconnected("player-2", handlerTestTimeout) {
+		t.Error("expected the leaver's connection to be closed")
+	}
+}
+
 // ========================================
 // BroadcastPlayerJoined / BroadcastPlayerLeft Edge Cases
 // ========================================
@@ -405,3 +636,31 @@ func TestHandler_BroadcastPlayerLeft_NonExistentLobby(t *testing.T) {
 	// Should not panic when lobby doesn't exist
 	ts.Handler.BroadcastPlayerLeft("NONEXISTENT", "player-1")
 }
+
+// ========================================
+// chatLimiter Tests
+// ========================================
+
+func TestChatLimiter(t *testing.T) {
+	limiter := newChatLimiter(2, time.Second)
+	now := time.Now()
+
+	if !limiter.allow("player-1", now) || !limiter.allow("player-1", now) {
+		t.Fatal("expected the first two messages to be allowed")
+	}
+	if limiter.allow("player-1", now.Add(500*time.Millisecond)) {
+		t.Error("expected a third message inside the window to be refused")
+	}
+	if !limiter.allow("player-2", now) {
+		t.Error("expected other senders to have their own allowance")
+	}
+	if !limiter.allow("player-1", now.Add(time.Second)) {
+		t.Error("expected the allowance to recover once the window has passed")
+	}
+
+	// Senders with nothing left in the window are forgotten
+	limiter.allow("player-1", now.Add(10*time.Second))
+	if _, ok := limiter.sent["player-2"]; ok {
+		t.Error("expected player-2's expired history to be dropped")
+	}
+}
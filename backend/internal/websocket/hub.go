@@ -1,21 +1,50 @@
 package websocket
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"log/slog"
 	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
-// Hub maintains the set of active connections and broadcasts messages to lobbies
+// lobbyShardCount is how many shards the hub's lobby index is split across,
+// so broadcasts to different lobbies rarely wait on the same lock
+const lobbyShardCount = 64
+
+// lobbyShard holds the connections of the lobbies whose codes hash to it, the queues their sends go through
+// and the logs numbering their broadcasts
+type lobbyShard struct {
+	mu      sync.RWMutex
+	lobbies map[string]map[*Connection]bool
+	queues  map[string]*lobbyQueue // A lobby has one while it has connections
+	logs    map[string]*lobbyLog   // A lobby has one from its first broadcast until it closes
+}
+
+// Hub maintains the set of active connections and broadcasts messages to lobbies.
+// Each index has its own lock. A goroutine that needs more than one takes mu first,
+// then a lobby shard's, then playersMu.
 type Hub struct {
+	// mu guards the connection registry, the connection limits, config and logger and the disconnect callback
 	mu sync.RWMutex
 
 	// All active connections indexed by connection pointer
 	connections map[*Connection]bool
 
-	// Connections grouped by lobby code
-	lobbies map[string]map[*Connection]bool
+	// Connections grouped by lobby code, sharded by the code's hash
+	lobbyShards [lobbyShardCount]lobbyShard
 
-	// Player ID to connection mapping (for targeted messages)
-	players map[string]*Connection
+	// Player ID to the primary connection, whose actions count, and to every connection the player
+	// has authenticated on, oldest first; see devices.go. Also how many connections each player
+	// is counted as holding against their limit; see limits.go.
+	playersMu         sync.RWMutex
+	players           map[string]*Connection
+	devices           map[string][]*Connection
+	playerConnections map[string]int
 
 	// Channels for connection lifecycle
 	register   chan *Connection
@@ -26,18 +55,85 @@ type Hub struct {
 
 	// Callback invoked when an authenticated player disconnects
 	onDisconnect func(playerID, lobbyCode string)
+
+	// Callback invoked before a message goes to a lobby or one of its players,
+	// so anything held back for the lobby can be sent ahead of it
+	beforeSend func(lobbyCode string)
+
+	// Player sessions, which outlive connections so messages can be replayed after a reconnect
+	sessions *sessionStore
+
+	// Caps on simultaneous connections, and how many are open from each remote IP
+	limits        ConnectionLimits
+	ipConnections map[string]int
+
+	// drained is made when the hub starts shutting down, and closed once its last connection is gone;
+	// reconnectAfter is how long clients are told to wait before reconnecting
+	drained        chan struct{}
+	reconnectAfter time.Duration
+
+	// Message counts and send measurements, see metrics.go
+	metrics *hubMetrics
+
+	// What new connections are configured with
+	connConfig ConnectionConfig
+
+	// Where the hub and its connections log, see logging.go
+	logger *slog.Logger
+
+	// Identifies the hub to the other instances it shares lobbies and sessions with
+	instanceID string
+
+	// What the hub shares its lobby broadcasts with other instances over, nil if it serves alone; see backplane.go
+	backplane *hubBackplane
+
+	// Where the hub keeps its players' sessions for other instances to pick up, nil if it keeps them to itself;
+	// see shared_sessions.go
+	shared *sharedSessions
 }
 
 // NewHub creates a new Hub
 func NewHub() *Hub {
-	return &Hub{
-		connections: make(map[*Connection]bool),
-		lobbies:     make(map[string]map[*Connection]bool),
-		players:     make(map[string]*Connection),
-		register:    make(chan *Connection),
-		unregister:  make(chan *Connection),
-		stop:        make(chan struct{}),
+	h := &Hub{
+		connections:       make(map[*Connection]bool),
+		players:           make(map[string]*Connection),
+		devices:           make(map[string][]*Connection),
+		playerConnections: make(map[string]int),
+		register:          make(chan *Connection),
+		unregister:        make(chan *Connection),
+		stop:              make(chan struct{}),
+		sessions:          newSessionStore(),
+		limits: ConnectionLimits{
+			PerPlayer: DefaultMaxConnectionsPerPlayer,
+			PerIP:     DefaultMaxConnectionsPerIP,
+		},
+		ipConnections:  make(map[string]int),
+		reconnectAfter: DefaultShutdownReconnectAfter,
+		metrics:        newHubMetrics(),
+		connConfig:     DefaultConnectionConfig(),
+		logger:         slog.Default(),
+		instanceID:     newInstanceID(),
 	}
+	for i := range h.lobbyShards {
+		h.lobbyShards[i].lobbies = make(map[string]map[*Connection]bool)
+		h.lobbyShards[i].queues = make(map[string]*lobbyQueue)
+		h.lobbyShards[i].logs = make(map[string]*lobbyLog)
+	}
+	return h
+}
+
+// newInstanceID generates a random ID for a hub
+func newInstanceID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes) // Never returns an error since Go 1.24
+	return "instance-" + hex.EncodeToString(bytes)
+}
+
+// lobbyShard returns the shard holding the lobby's connections
+func (h *Hub) lobbyShard(lobbyCode string) *lobbyShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(lobbyCode))
+	return &h.lobbyShards[hash.Sum32()%lobbyShardCount]
 }
 
 // SetOnDisconnect sets the callback invoked when an authenticated player disconnects
@@ -47,6 +143,23 @@ func (h *Hub) SetOnDisconnect(callback func(playerID, lobbyCode string)) {
 	h.onDisconnect = callback
 }
 
+// SetBeforeSend sets the callback invoked before a message goes to a lobby or one of its players
+func (h *Hub) SetBeforeSend(callback func(lobbyCode string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeSend = callback
+}
+
+// flushBeforeSend invokes the before-send callback for the lobby
+func (h *Hub) flushBeforeSend(lobbyCode string) {
+	h.mu.RLock()
+	callback := h.beforeSend
+	h.mu.RUnlock()
+	if callback != nil && lobbyCode != "" {
+		callback(lobbyCode)
+	}
+}
+
 // Run starts the hub's main loop
 func (h *Hub) Run() {
 	for {
@@ -66,6 +179,16 @@ func (h *Hub) Stop() {
 	close(h.stop)
 }
 
+// stopContext returns a context that is cancelled when the hub stops
+func (h *Hub) stopContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-h.stop
+		cancel()
+	}()
+	return ctx
+}
+
 // Register adds a connection to the hub
 func (h *Hub) Register(conn *Connection) {
 	h.register <- conn
@@ -79,6 +202,15 @@ func (h *Hub) Unregister(conn *Connection) {
 func (h *Hub) handleRegister(conn *Connection) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+
+	// A connection upgraded just as the hub started shutting down is turned away
+	if h.drained != nil {
+		if conn.remoteIP != "" {
+			h.releaseIPLocked(conn.remoteIP)
+		}
+		conn.CloseWithCode(websocket.CloseGoingAway, "Server shutting down")
+		return
+	}
 	h.connections[conn] = true
 }
 
@@ -91,30 +223,59 @@ func (h *Hub) handleUnregister(conn *Connection) {
 	}
 
 	delete(h.connections, conn)
+	if conn.remoteIP != "" {
+		h.releaseIPLocked(conn.remoteIP)
+	}
+	if h.drained != nil && len(h.connections) == 0 {
+		close(h.drained) // The last connection of a shutdown is gone
+	}
 
 	// Remove from lobby
 	lobbyCode := conn.LobbyCode()
 	if lobbyCode != "" {
-		if lobby, ok := h.lobbies[lobbyCode]; ok {
+		shard := h.lobbyShard(lobbyCode)
+		shard.mu.Lock()
+		if lobby, ok := shard.lobbies[lobbyCode]; ok {
 			delete(lobby, conn)
 			if len(lobby) == 0 {
-				delete(h.lobbies, lobbyCode)
+				delete(shard.lobbies, lobbyCode)
+				delete(shard.queues, lobbyCode) // Its worker finishes anything still queued
+				if h.backplane != nil {
+					h.backplane.resubscribe(lobbyCode)
+				}
 			}
 		}
+		shard.mu.Unlock()
 	}
 
-	// Remove from players map
+	// Remove from players map, handing primary to the player's newest other device
 	playerID := conn.PlayerID()
+	var promoted *Connection
 	if playerID != "" {
-		if h.players[playerID] == conn {
-			delete(h.players, playerID)
-		}
+		promoted = h.removeDevice(playerID, conn)
 	}
+	h.releasePlayer(conn)
 
 	// Capture callback before releasing lock
 	callback := h.onDisconnect
 	h.mu.Unlock()
 
+	// Messages for the player are held from now on until they reconnect
+	if session := conn.Session(); session != nil {
+		session.detach(conn)
+	}
+
+	// Nothing waiting on a reply from the client will get one now
+	conn.failRequests()
+
+	code, reason := conn.closeStatus()
+	conn.log().Info("websocket disconnected", "close_code", code, "close_reason", reason,
+		"duration_ms", time.Since(conn.connectedAt).Milliseconds())
+
+	if promoted != nil {
+		h.announcePrimary(playerID, promoted, conn, PrimaryReasonDisconnected)
+	}
+
 	// Invoke callback outside lock to prevent deadlock
 	if callback != nil && playerID != "" && lobbyCode != "" {
 		callback(playerID, lobbyCode)
@@ -125,8 +286,9 @@ func (h *Hub) handleUnregister(conn *Connection) {
 
 // AssociateWithLobby associates a connection with a lobby after authentication
 func (h *Hub) AssociateWithLobby(conn *Connection) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	// Holding mu keeps the connection from being unregistered until it is fully indexed
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 
 	lobbyCode := conn.LobbyCode()
 	playerID := conn.PlayerID()
@@ -136,28 +298,37 @@ func (h *Hub) AssociateWithLobby(conn *Connection) {
 	}
 
 	// Add to lobby map
-	if _, ok := h.lobbies[lobbyCode]; !ok {
-		h.lobbies[lobbyCode] = make(map[*Connection]bool)
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.Lock()
+	if _, ok := shard.lobbies[lobbyCode]; !ok {
+		shard.lobbies[lobbyCode] = make(map[*Connection]bool)
+		shard.queues[lobbyCode] = newLobbyQueue()
+		if h.backplane != nil {
+			h.backplane.resubscribe(lobbyCode)
+		}
 	}
-	h.lobbies[lobbyCode][conn] = true
+	shard.lobbies[lobbyCode][conn] = true
+	conn.setLobbyQueue(shard.queues[lobbyCode])
+	shard.mu.Unlock()
 
-	// Add to players map
-	h.players[playerID] = conn
+	// Add to players map, as the primary if the player has none
+	h.addDevice(playerID, conn)
 }
 
-// GetConnectionByPlayerID returns the connection for a player
+// GetConnectionByPlayerID returns the player's primary connection
 func (h *Hub) GetConnectionByPlayerID(playerID string) *Connection {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.playersMu.RLock()
+	defer h.playersMu.RUnlock()
 	return h.players[playerID]
 }
 
 // GetLobbyConnections returns all connections in a lobby
 func (h *Hub) GetLobbyConnections(lobbyCode string) []*Connection {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
 
-	lobby, ok := h.lobbies[lobbyCode]
+	lobby, ok := shard.lobbies[lobbyCode]
 	if !ok {
 		return nil
 	}
@@ -171,50 +342,116 @@ func (h *Hub) GetLobbyConnections(lobbyCode string) []*Connection {
 
 // BroadcastToLobby sends a message to all connections in a lobby
 func (h *Hub) BroadcastToLobby(lobbyCode string, msgType MessageType, payload interface{}) error {
-	conns := h.GetLobbyConnections(lobbyCode)
-	if len(conns) == 0 {
-		return nil
-	}
+	return h.BroadcastToLobbyExcept(lobbyCode, "", msgType, payload)
+}
 
-	// Each connection must receive its own sequence number.
-	// Do not optimize by reusing a single marshaled message.
-	for _, conn := range conns {
-		if conn.State() == ConnectionStateActive {
-			conn.SendMessage(msgType, payload)
-		}
+// BroadcastToLobbyExcept sends a message to all connections in a lobby except one. It is queued behind
+// the lobby's earlier sends and returns without waiting for them, so slow lobbies hold up no one else.
+// With a backplane set, the lobby's connections on other instances are sent it too.
+func (h *Hub) BroadcastToLobbyExcept(lobbyCode string, exceptPlayerID string, msgType MessageType, payload interface{}) error {
+	h.flushBeforeSend(lobbyCode)
+	return h.broadcastToLobby(lobbyCode, exceptPlayerID, msgType, payload)
+}
+
+// broadcastToLobby is BroadcastToLobbyExcept without sending anything held back for the lobby first
+func (h *Hub) broadcastToLobby(lobbyCode string, exceptPlayerID string, msgType MessageType, payload interface{}) error {
+	start := time.Now()
+
+	// The message is marshaled once; each connection only splices in its own sequence number
+	env, err := NewEnvelope(msgType, payload)
+	if err != nil {
+		return err
+	}
+	msg, err := prepareEnvelope(env)
+	if err != nil {
+		return err
 	}
 
+	if link := h.getBackplane(); link != nil {
+		h.publishRemote(link, lobbyCode, exceptPlayerID, env)
+	}
+	h.deliverToLobby(lobbyCode, exceptPlayerID, msg, lobbyUpdateEvents(payload), start)
 	return nil
 }
 
-// BroadcastToLobbyExcept sends a message to all connections in a lobby except one
-func (h *Hub) BroadcastToLobbyExcept(lobbyCode string, exceptPlayerID string, msgType MessageType, payload interface{}) error {
-	conns := h.GetLobbyConnections(lobbyCode)
-	if len(conns) == 0 {
-		return nil
+// deliverToLobby queues a broadcast made at start, reporting the lobby changes in events, for the hub's
+// connections in the lobby, behind the lobby's earlier sends
+func (h *Hub) deliverToLobby(lobbyCode string, exceptPlayerID string, msg *preparedEnvelope, events []LobbyEventRecord, start time.Time) {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	q := shard.queues[lobbyCode]
+	shard.mu.RUnlock()
+	if q == nil {
+		// With no one connected there is only holding it for the players who are away to do
+		h.fanOut(lobbyCode, exceptPlayerID, msg, events, start)
+		return
 	}
+	q.enqueue(func() {
+		h.fanOut(lobbyCode, exceptPlayerID, msg, events, start)
+	})
+}
 
-	// Each connection must receive its own sequence number.
-	// Do not optimize by reusing a single marshaled message.
-	for _, conn := range conns {
-		if conn.State() == ConnectionStateActive && conn.PlayerID() != exceptPlayerID {
-			conn.SendMessage(msgType, payload)
+// fanOut numbers a broadcast made at start in the lobby's sequence, logging it with the lobby changes it
+// reports, sends it to the lobby's connections, and holds it for replay to the players who are away
+func (h *Hub) fanOut(lobbyCode string, exceptPlayerID string, msg *preparedEnvelope, events []LobbyEventRecord, start time.Time) {
+	msg = h.logBroadcast(lobbyCode, exceptPlayerID, msg, events)
+
+	// A player's session sends it on to every device they are on, under one seq
+	reached := map[string]bool{exceptPlayerID: true}
+	sessions := make(map[*playerSession]bool)
+	for _, conn := range h.GetLobbyConnections(lobbyCode) {
+		if conn.State() != ConnectionStateActive || conn.PlayerID() == exceptPlayerID {
+			continue
+		}
+		if session := conn.Session(); session == nil {
+			conn.deliver(msg, "")
+		} else if !sessions[session] {
+			session.hold(msg)
+			sessions[session] = true
 		}
+		reached[conn.PlayerID()] = true
 	}
+	h.metrics.fanOut.observe(float64(len(reached) - 1))
+	h.metrics.broadcastLatency.observeSince(start)
 
-	return nil
+	// Players who are away get it on their session, to be replayed when they reconnect
+	for playerID, session := range h.sessions.inLobby(lobbyCode) {
+		if !reached[playerID] {
+			session.hold(msg)
+		}
+	}
 }
 
-// SendToPlayer sends a message to a specific player
+// SendToPlayer sends a message to every device a player is on, holding it for replay if they are away
 func (h *Hub) SendToPlayer(playerID string, msgType MessageType, payload interface{}) error {
+	if session := h.sessions.get(playerID); session != nil {
+		h.flushBeforeSend(session.lobbyCode)
+		return h.inLobbyOrder(session.lobbyCode, func() error {
+			return holdForReplay(session, msgType, payload)
+		})
+	}
 	conn := h.GetConnectionByPlayerID(playerID)
 	if conn == nil {
 		return nil // Player not connected
 	}
+	h.flushBeforeSend(conn.LobbyCode())
 	return conn.SendMessage(msgType, payload)
 }
 
-// SendToPlayerWithCorrelation sends a message to a specific player with correlation ID
+// holdForReplay hands a message for the player to their session, which sends it to each device they are on
+func holdForReplay(session *playerSession, msgType MessageType, payload interface{}) error {
+	env, err := NewEnvelope(msgType, payload)
+	if err != nil {
+		return err
+	}
+	msg, err := prepareEnvelope(env)
+	if err != nil {
+		return err
+	}
+	return session.hold(msg)
+}
+
+// SendToPlayerWithCorrelation sends a message to a specific player's primary connection with correlation ID
 func (h *Hub) SendToPlayerWithCorrelation(playerID string, msgType MessageType, correlationID string, payload interface{}) error {
 	conn := h.GetConnectionByPlayerID(playerID)
 	if conn == nil {
@@ -223,7 +460,7 @@ func (h *Hub) SendToPlayerWithCorrelation(playerID string, msgType MessageType,
 	return conn.SendMessageWithCorrelation(msgType, correlationID, payload)
 }
 
-// SendErrorToPlayer sends an error to a specific player
+// SendErrorToPlayer sends an error to a specific player's primary connection
 func (h *Hub) SendErrorToPlayer(playerID string, code ErrorCode, message string, correlationID string) error {
 	conn := h.GetConnectionByPlayerID(playerID)
 	if conn == nil {
@@ -241,9 +478,10 @@ func (h *Hub) ConnectionCount() int {
 
 // LobbyConnectionCount returns the number of connections in a lobby
 func (h *Hub) LobbyConnectionCount(lobbyCode string) int {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	if lobby, ok := h.lobbies[lobbyCode]; ok {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	if lobby, ok := shard.lobbies[lobbyCode]; ok {
 		return len(lobby)
 	}
 	return 0
@@ -251,16 +489,15 @@ func (h *Hub) LobbyConnectionCount(lobbyCode string) int {
 
 // IsPlayerConnected checks if a player is connected
 func (h *Hub) IsPlayerConnected(playerID string) bool {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.playersMu.RLock()
+	defer h.playersMu.RUnlock()
 	_, ok := h.players[playerID]
 	return ok
 }
 
-// DisconnectPlayer forcefully disconnects a player
+// DisconnectPlayer forcefully disconnects a player from every device they are on
 func (h *Hub) DisconnectPlayer(playerID string) {
-	conn := h.GetConnectionByPlayerID(playerID)
-	if conn != nil {
+	for _, conn := range h.PlayerConnections(playerID) {
 		h.Unregister(conn)
 	}
 }
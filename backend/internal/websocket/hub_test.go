@@ -0,0 +1,308 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"poke-battles/internal/backplane"
+)
+
+// ========================================
+// Hub Index Tests
+// ========================================
+
+// hubConn registers an authenticated connection for the player in the lobby, bypassing the hub's run loop
+func hubConn(tb testing.TB, hub *Hub, playerID, lobbyCode string) *Connection {
+	tb.Helper()
+	conn := NewConnection(nil, hub)
+	if _, err := conn.Authenticate(playerID, lobbyCode); err != nil {
+		tb.Fatalf("failed to authenticate: %v", err)
+	}
+	hub.handleRegister(conn)
+	hub.AssociateWithLobby(conn)
+	return conn
+}
+
+func TestHub_LobbyShards(t *testing.T) {
+	hub := NewHub()
+
+	const lobbies = 200
+	conns := make([]*Connection, 0, lobbies*2)
+	for i := 0; i < lobbies; i++ {
+		code := fmt.Sprintf("L%05d", i)
+		conns = append(conns, hubConn(t, hub, code+"-a", code), hubConn(t, hub, code+"-b", code))
+	}
+
+	used := 0
+	for i := range hub.lobbyShards {
+		if len(hub.lobbyShards[i].lobbies) > 0 {
+			used++
+		}
+	}
+	if used < lobbyShardCount/2 {
+		t.Errorf("expected lobbies spread across the shards, only %d of %d used", used, lobbyShardCount)
+	}
+
+	for i := 0; i < lobbies; i++ {
+		code := fmt.Sprintf("L%05d", i)
+		if n := hub.LobbyConnectionCount(code); n != 2 {
+			t.Fatalf("expected 2 connections in %s, got %d", code, n)
+		}
+	}
+
+	for _, conn := range conns {
+		hub.handleUnregister(conn)
+	}
+	for i := range hub.lobbyShards {
+		if n := len(hub.lobbyShards[i].lobbies); n != 0 {
+			t.Errorf("expected shard %d to be empty once every connection left, has %d lobbies", i, n)
+		}
+	}
+	if hub.ConnectionCount() != 0 || len(hub.players) != 0 {
+		t.Errorf("expected no connections or players left, got %d and %d", hub.ConnectionCount(), len(hub.players))
+	}
+}
+
+func TestHub_ConcurrentLobbies(t *testing.T) {
+	hub := NewHub()
+
+	// Registrations, broadcasts and unregistrations in many lobbies at once must stay consistent
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			code := fmt.Sprintf("L%05d", i)
+			conn := hubConn(t, hub, code+"-a", code)
+			hub.BroadcastToLobby(code, TypeHeartbeatAck, HeartbeatAckPayload{})
+			if !hub.IsPlayerConnected(code + "-a") {
+				t.Errorf("expected %s-a to be connected", code)
+			}
+			hub.handleUnregister(conn)
+		}(i)
+	}
+	wg.Wait()
+
+	if hub.ConnectionCount() != 0 {
+		t.Errorf("expected every connection to be gone, got %d", hub.ConnectionCount())
+	}
+}
+
+func TestHub_LobbySeq(t *testing.T) {
+	hub := NewHub()
+	msg, err := prepareEnvelope(&Envelope{Type: TypeLobbyUpdated, Version: ProtocolVersion})
+	if err != nil {
+		t.Fatalf("failed to prepare envelope: %v", err)
+	}
+
+	// Each broadcast takes the lobby's next number, whichever players it leaves out
+	for i := 1; i <= 3; i++ {
+		except := ""
+		if i == 2 {
+			except = "player-1"
+		}
+		if got := hub.logBroadcast("ABC123", except, msg, nil); got.lobbySeq != int64(i) {
+			t.Fatalf("expected lobby seq %d, got %d", i, got.lobbySeq)
+		}
+	}
+	if msg.lobbySeq != 0 {
+		t.Error("expected the broadcast being numbered to be left as it was")
+	}
+	if seq := hub.LobbySeq("ABC123"); seq != 3 {
+		t.Errorf("expected lobby seq 3, got %d", seq)
+	}
+	if seq := hub.LobbySeq("XYZ789"); seq != 0 {
+		t.Errorf("expected another lobby's numbering to be its own, got %d", seq)
+	}
+
+	// Replay skips what the player was never sent
+	missed, complete := hub.lobbyBroadcastsSince("ABC123", "player-1", 1)
+	if !complete || len(missed) != 1 || missed[0].lobbySeq != 3 {
+		t.Errorf("expected only lobby seq 3 to be replayed to player-1, got %d messages, complete %v", len(missed), complete)
+	}
+	missed, complete = hub.lobbyBroadcastsSince("ABC123", "player-2", 1)
+	if !complete || len(missed) != 2 {
+		t.Errorf("expected 2 messages replayed to player-2, got %d, complete %v", len(missed), complete)
+	}
+	if _, complete := hub.lobbyBroadcastsSince("ABC123", "player-2", 4); complete {
+		t.Error("expected a lobby seq ahead of the lobby to need a resync")
+	}
+
+	// Broadcasts that drop out of the log can no longer be replayed
+	for i := 0; i < lobbyLogSize; i++ {
+		hub.logBroadcast("ABC123", "", msg, nil)
+	}
+	if _, complete := hub.lobbyBroadcastsSince("ABC123", "player-2", 2); complete {
+		t.Error("expected a replay from before the log to be incomplete")
+	}
+	if missed, complete := hub.lobbyBroadcastsSince("ABC123", "player-2", 3); !complete || len(missed) != lobbyLogSize {
+		t.Errorf("expected the whole log replayed, got %d messages, complete %v", len(missed), complete)
+	}
+
+	hub.dropLobbyLog("ABC123")
+	if seq := hub.LobbySeq("ABC123"); seq != 0 {
+		t.Errorf("expected the numbering to go with the lobby, got %d", seq)
+	}
+}
+
+func TestHub_LobbyEventsSince(t *testing.T) {
+	hub := NewHub()
+	update := LobbyUpdatedPayload{
+		Event:         LobbyEventPlayerReadyChanged,
+		EarlierEvents: []LobbyEventRecord{{Event: LobbyEventPlayerJoined}},
+	}
+	for _, payload := range []interface{}{update, HeartbeatPayload{}} {
+		msg, err := prepareEnvelope(&Envelope{Type: TypeLobbyUpdated, Version: ProtocolVersion})
+		if err != nil {
+			t.Fatalf("failed to prepare envelope: %v", err)
+		}
+		hub.logBroadcast("ABC123", "", msg, lobbyUpdateEvents(payload))
+	}
+
+	// The changes logged with each lobby_updated are listed oldest first, and other broadcasts list none
+	missed, complete := hub.lobbyEventsSince("ABC123", "player-1", 0)
+	if !complete || len(missed) != 2 {
+		t.Fatalf("expected 2 changes, got %+v, complete %v", missed, complete)
+	}
+	if missed[0].Event != LobbyEventPlayerJoined || missed[1].Event != LobbyEventPlayerReadyChanged || missed[1].LobbySeq != 1 {
+		t.Errorf("expected player_joined then player_ready_changed at lobby seq 1, got %+v", missed)
+	}
+}
+
+func TestHub_AdmitPlayer(t *testing.T) {
+	hub := NewHub()
+	hub.SetConnectionLimits(ConnectionLimits{PerPlayer: 2})
+
+	// Connections authenticating at once never take more slots than the player has
+	conns := make([]*Connection, 10)
+	admitted := make([]bool, len(conns))
+	var wg sync.WaitGroup
+	for i := range conns {
+		conns[i] = NewConnection(nil, hub)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			admitted[i] = hub.admitPlayer(conns[i], "player-1")
+		}(i)
+	}
+	wg.Wait()
+
+	var in []*Connection
+	for i, ok := range admitted {
+		if ok {
+			in = append(in, conns[i])
+		}
+	}
+	if len(in) != 2 {
+		t.Fatalf("expected 2 connections admitted, got %d", len(in))
+	}
+	if !hub.admitPlayer(in[0], "player-1") {
+		t.Error("expected a connection already admitted to be admitted again")
+	}
+	if !hub.admitPlayer(NewConnection(nil, hub), "player-2") {
+		t.Error("expected another player's cap to be separate")
+	}
+
+	// Releasing a connection frees its slot, and only its slot however often it is released
+	hub.releasePlayer(in[0])
+	hub.releasePlayer(in[0])
+	if !hub.admitPlayer(NewConnection(nil, hub), "player-1") {
+		t.Error("expected the released slot to be free")
+	}
+	if hub.admitPlayer(NewConnection(nil, hub), "player-1") {
+		t.Error("expected the player to be at their cap again")
+	}
+}
+
+// ========================================
+// Hub Backplane Tests
+// ========================================
+
+// awaitSubscribed waits for the backplane's subscription to the lobby to be as expected
+func awaitSubscribed(t *testing.T, b *backplane.Memory, lobbyCode string, subscribed bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for b.Subscribed(lobbyCode) != subscribed {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected subscribed to %s to be %v", lobbyCode, subscribed)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// expectNoMessage checks nothing more is queued for the connection
+func expectNoMessage(t *testing.T, conn *Connection) {
+	t.Helper()
+	select {
+	case data := <-conn.send:
+		t.Errorf("expected no message, got %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_BackplaneSharesBroadcasts(t *testing.T) {
+	broker := backplane.NewMemoryBroker()
+	first, second := broker.Connect(), broker.Connect()
+	defer first.Close()
+	defer second.Close()
+	firstHub, secondHub := NewHub(), NewHub()
+	firstHub.SetBackplane(first)
+	secondHub.SetBackplane(second)
+	defer firstHub.Stop()
+	defer secondHub.Stop()
+
+	// Each instance subscribes to the lobby once it has a connection there
+	host := hubConn(t, firstHub, "player-1", "ABC123")
+	guest := hubConn(t, secondHub, "player-2", "ABC123")
+	awaitSubscribed(t, first, "ABC123", true)
+	awaitSubscribed(t, second, "ABC123", true)
+
+	// A broadcast reaches the lobby on both instances, and only once on the one that made it
+	firstHub.BroadcastToLobby("ABC123", TypeLobbyUpdated, LobbyUpdatedPayload{})
+	if msgType := receiveType(t, host); msgType != TypeLobbyUpdated {
+		t.Errorf("expected the local connection to get %s, got %s", TypeLobbyUpdated, msgType)
+	}
+	if msgType := receiveType(t, guest); msgType != TypeLobbyUpdated {
+		t.Errorf("expected the remote connection to get %s, got %s", TypeLobbyUpdated, msgType)
+	}
+	expectNoMessage(t, host)
+
+	// The player left out is left out on every instance
+	secondHub.BroadcastToLobbyExcept("ABC123", "player-1", TypeHeartbeatAck, HeartbeatAckPayload{})
+	if msgType := receiveType(t, guest); msgType != TypeHeartbeatAck {
+		t.Errorf("expected %s, got %s", TypeHeartbeatAck, msgType)
+	}
+	expectNoMessage(t, host)
+
+	// Other lobbies' broadcasts stay off an instance with no connections in them
+	secondHub.BroadcastToLobby("XYZ789", TypeLobbyUpdated, LobbyUpdatedPayload{})
+	expectNoMessage(t, host)
+
+	// The instance unsubscribes once its last connection in the lobby is gone
+	secondHub.handleUnregister(guest)
+	awaitSubscribed(t, second, "ABC123", false)
+	if !first.Subscribed("ABC123") {
+		t.Error("expected the instance still hosting the lobby to stay subscribed")
+	}
+}
+
+// BenchmarkHub_BroadcastToLobby broadcasts a lobby update to a full lobby's connections
+func BenchmarkHub_BroadcastToLobby(b *testing.B) {
+	hub := NewHub()
+	for i := 0; i < benchmarkLobbySize; i++ {
+		conn := hubConn(b, hub, fmt.Sprintf("player-%d", i), "ABC123")
+		go func() {
+			for range conn.send {
+			}
+		}()
+	}
+	payload := benchmarkLobbyUpdate()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hub.BroadcastToLobby("ABC123", TypeLobbyUpdated, payload)
+	}
+}
@@ -1,8 +1,20 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/services"
+
+	"github.com/gorilla/websocket"
 )
 
 const testTimeout = 2 * time.Second
@@ -155,6 +167,54 @@ func TestWS_Auth_VersionMismatch(t *testing.T) {
 	}
 }
 
+func TestWS_Auth_VersionMismatchDetails(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	auth, err := client.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	if auth.ProtocolVersion != ProtocolVersion {
+		t.Errorf("expected protocol version %d, got %d", ProtocolVersion, auth.ProtocolVersion)
+	}
+
+	// Once authenticated, the connection keeps to its version
+	env, _ := NewEnvelope(TypeHeartbeat, struct{}{})
+	env.Version = ProtocolVersion + 1
+	if err := client.Send(env); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	reply, err := client.ReceiveType(TypeError, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive error: %v", err)
+	}
+	var errPayload ErrorPayload
+	reply.ParsePayload(&errPayload)
+	var details VersionMismatchDetails
+	json.Unmarshal(errPayload.Details, &details)
+	if errPayload.Code != ErrCodeVersionMismatch {
+		t.Errorf("expected VERSION_MISMATCH, got %s", errPayload.Code)
+	}
+	if !reflect.DeepEqual(details.SupportedVersions, SupportedProtocolVersions()) ||
+		details.MinVersion != MinProtocolVersion || details.MaxVersion != ProtocolVersion || details.ConnectionVersion != ProtocolVersion {
+		t.Errorf("unexpected version details: %+v", details)
+	}
+}
+
 func TestWS_Auth_RequiresAuthForActions(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
@@ -377,7 +437,7 @@ func TestWS_Broadcast_PlayerReady(t *testing.T) {
 	if err != nil {
 		t.Fatalf("client1 failed to receive update: %v", err)
 	}
-	if update1.Event != LobbyEventPlayerReadyChanged {
+	if !hasLobbyEvent(update1, LobbyEventPlayerReadyChanged) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerReadyChanged, update1.Event)
 	}
 
@@ -385,7 +445,7 @@ func TestWS_Broadcast_PlayerReady(t *testing.T) {
 	if err != nil {
 		t.Fatalf("client2 failed to receive update: %v", err)
 	}
-	if update2.Event != LobbyEventPlayerReadyChanged {
+	if !hasLobbyEvent(update2, LobbyEventPlayerReadyChanged) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerReadyChanged, update2.Event)
 	}
 }
@@ -438,6 +498,14 @@ func TestWS_Broadcast_BothReady_GameStarts(t *testing.T) {
 	client1.Drain()
 	client2.Drain()
 
+	// Both players submit teams
+	if err := client1.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team for client1: %v", err)
+	}
+	if err := client2.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team for client2: %v", err)
+	}
+
 	// Both players set ready
 	if err := client1.SendReady(true); err != nil {
 		t.Fatalf("failed to send ready for client1: %v", err)
@@ -472,6 +540,77 @@ func TestWS_Broadcast_BothReady_GameStarts(t *testing.T) {
 	}
 }
 
+// TestWS_Ready_SetOverHTTP readies a player through the lobby service, as the REST endpoint does,
+// and checks connected clients hear about it and the game starts
+func TestWS_Ready_SetOverHTTP(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		clients[i] = client
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth failed: %v", err)
+		}
+		if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+			t.Fatalf("failed to submit team: %v", err)
+		}
+	}
+	if err := clients[0].SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if !waitFor(func() bool { return ts.Handler.isPlayerReady(lobbyCode, "player-1") }, testTimeout) {
+		t.Fatal("expected player-1 to be ready")
+	}
+
+	if _, err := ts.LobbyService.SetReady(lobbyCode, "player-2", true); err != nil {
+		t.Fatalf("failed to set ready: %v", err)
+	}
+
+	// Skip the updates from team submissions and player-1 readying up
+	for {
+		update, err := clients[0].AssertLobbyUpdated(testTimeout)
+		if err != nil {
+			t.Fatalf("ready broadcast: %v", err)
+		}
+		found := false
+		for _, record := range lobbyEvents(update, LobbyEventPlayerReadyChanged) {
+			var data PlayerReadyChangedEventData
+			if json.Unmarshal(record.EventData, &data) == nil && data.PlayerID == "player-2" {
+				if !data.Ready {
+					t.Error("expected player-2 to be reported ready")
+				}
+				found = true
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	for _, client := range clients {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("failed to receive game_started: %v", err)
+		}
+	}
+}
+
 // ========================================
 // Disconnect Tests
 // ========================================
@@ -538,6 +677,123 @@ func TestWS_Disconnect_ClearsReadyState(t *testing.T) {
 	}
 }
 
+func TestWS_Presence(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make(map[string]*TestClient)
+	for _, id := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		if err := client.SendAuth(id, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("%s auth: %v", id, err)
+		}
+		clients[id] = client
+	}
+
+	// The host hears player-2 arrive and sees both players connected
+	env, err := clients["player-1"].ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	var connected PlayerConnectedPayload
+	env.ParsePayload(&connected)
+	if connected.PlayerID != "player-2" {
+		t.Errorf("expected player-2 to be reported connected, got %q", connected.PlayerID)
+	}
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	for _, p := range ts.Handler.buildLobbyInfo(lobby).Players {
+		if !p.IsConnected {
+			t.Errorf("expected %s to be connected", p.ID)
+		}
+	}
+	clients["player-1"].Drain()
+
+	clients["player-2"].Close()
+
+	env, err = clients["player-1"].ReceiveType(TypePlayerDisconnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_disconnected: %v", err)
+	}
+	var payload PlayerDisconnectedPayload
+	env.ParsePayload(&payload)
+	if payload.PlayerID != "player-2" {
+		t.Errorf("expected player-2 to be reported disconnected, got %q", payload.PlayerID)
+	}
+	for _, p := range ts.Handler.buildLobbyInfo(lobby).Players {
+		if p.IsConnected != (p.ID == "player-1") {
+			t.Errorf("expected only player-1 to be connected, %s has is_connected %v", p.ID, p.IsConnected)
+		}
+	}
+}
+
+func TestWS_Disconnect_BroadcastsNotReady(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make(map[string]*TestClient)
+	for _, id := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		if err := client.SendAuth(id, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("%s auth: %v", id, err)
+		}
+		clients[id] = client
+	}
+	if err := clients["player-2"].SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if !waitFor(func() bool { return ts.Handler.isPlayerReady(lobbyCode, "player-2") }, testTimeout) {
+		t.Fatal("expected player-2 to be ready")
+	}
+	clients["player-1"].Drain()
+
+	clients["player-2"].Close()
+
+	// The rest of the lobby hears player-2 is no longer ready, as if they had unreadied themselves,
+	// once any update still on its way from them readying up has arrived
+	for unready := false; !unready; {
+		update, err := clients["player-1"].AssertLobbyUpdated(testTimeout)
+		if err != nil {
+			t.Fatalf("expected player-2 to be reported not ready: %v", err)
+		}
+		for _, record := range lobbyEvents(update, LobbyEventPlayerReadyChanged) {
+			var data PlayerReadyChangedEventData
+			if json.Unmarshal(record.EventData, &data) == nil && data.PlayerID == "player-2" {
+				unready = !data.Ready
+			}
+		}
+	}
+}
+
 // ========================================
 // Additional Auth Tests
 // ========================================
@@ -568,8 +824,12 @@ func TestWS_Auth_InvalidPayload(t *testing.T) {
 		t.Fatalf("failed to send: %v", err)
 	}
 
-	if err := client.ExpectError(ErrCodeAuthFailed, testTimeout); err != nil {
-		t.Fatalf("expected AUTH_FAILED error: %v", err)
+	fields, err := client.ExpectInvalidFields(testTimeout)
+	if err != nil {
+		t.Fatalf("expected MALFORMED_MESSAGE error: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Field != "player_id" || fields[1].Field != "lobby_code" {
+		t.Errorf("expected player_id and lobby_code to be listed, got %+v", fields)
 	}
 }
 
@@ -642,7 +902,7 @@ func TestWS_Ready_Toggle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to receive update after ready true: %v", err)
 	}
-	if update1.Event != LobbyEventPlayerReadyChanged {
+	if !hasLobbyEvent(update1, LobbyEventPlayerReadyChanged) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerReadyChanged, update1.Event)
 	}
 
@@ -667,7 +927,7 @@ func TestWS_Ready_Toggle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to receive update after ready false: %v", err)
 	}
-	if update2.Event != LobbyEventPlayerReadyChanged {
+	if !hasLobbyEvent(update2, LobbyEventPlayerReadyChanged) {
 		t.Errorf("expected event %s, got %s", LobbyEventPlayerReadyChanged, update2.Event)
 	}
 
@@ -708,10 +968,10 @@ func TestWS_Ready_RequiresAuth(t *testing.T) {
 }
 
 // ========================================
-// Error Handling Tests
+// Team Submission Tests
 // ========================================
 
-func TestWS_Error_UnknownMessageType(t *testing.T) {
+func TestWS_Team_SubmitBroadcastsUpdate(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
@@ -726,207 +986,336 @@ func TestWS_Error_UnknownMessageType(t *testing.T) {
 	}
 	defer client.Close()
 
-	// Authenticate first
 	if err := client.SendAuth("player-1", lobbyCode); err != nil {
 		t.Fatalf("failed to auth: %v", err)
 	}
-
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player not connected")
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
 	}
 	client.Drain()
 
-	// Send unknown message type
-	env := &Envelope{
-		Type:      MessageType("unknown_type"),
-		Version:   ProtocolVersion,
-		Timestamp: 1234567890,
-		Payload:   []byte("{}"),
+	if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
 	}
 
-	if err := client.Send(env); err != nil {
-		t.Fatalf("failed to send: %v", err)
+	update, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive update after team submission: %v", err)
 	}
-
-	if err := client.ExpectError(ErrCodeMalformedMessage, testTimeout); err != nil {
-		t.Fatalf("expected MALFORMED_MESSAGE error: %v", err)
+	if !hasLobbyEvent(update, LobbyEventTeamSubmitted) {
+		t.Errorf("expected event %s, got %s", LobbyEventTeamSubmitted, update.Event)
+	}
+	if len(update.Lobby.Players) != 1 || !update.Lobby.Players[0].HasTeam {
+		t.Errorf("expected player to have a team, got %+v", update.Lobby.Players)
 	}
 }
 
-// ========================================
-// Hub Integration Tests
-// ========================================
-
-func TestWS_BroadcastToLobbyExcept_ExcludedPlayerDoesNotReceive(t *testing.T) {
+func TestWS_Lobby_SettingsChangedBroadcast(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
-	// Create lobby with one player initially
 	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
 	if err != nil {
 		t.Fatalf("failed to create lobby: %v", err)
 	}
 
-	// Connect client1 first, before adding player-2
-	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
 	if err != nil {
-		t.Fatalf("failed to connect client1: %v", err)
+		t.Fatalf("failed to connect: %v", err)
 	}
-	defer client1.Close()
+	defer client.Close()
 
-	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
-		t.Fatalf("failed to auth client1: %v", err)
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
 	}
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player-1 not connected")
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
 	}
+	client.Drain()
 
-	// Receive client1's auth messages
-	if _, err := client1.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
-		t.Fatalf("client1 failed to receive authenticated: %v", err)
+	bestOf := 3
+	turnTimer := 45 * time.Second
+	_, err = ts.LobbyService.UpdateSettings(lobbyCode, "player-1", services.LobbySettingsUpdate{
+		LobbySettingsUpdate: game.LobbySettingsUpdate{BestOf: &bestOf, TurnTimer: &turnTimer},
+	})
+	if err != nil {
+		t.Fatalf("failed to update settings: %v", err)
 	}
-	if _, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
-		t.Fatalf("client1 failed to receive lobby_state: %v", err)
+
+	update, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive update after settings change: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventSettingsChanged) {
+		t.Errorf("expected event %s, got %s", LobbyEventSettingsChanged, update.Event)
 	}
 
-	// Now add and connect player-2
-	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
-		t.Fatalf("failed to join lobby: %v", err)
+	var data SettingsChangedEventData
+	if err := json.Unmarshal(update.EventData, &data); err != nil {
+		t.Fatalf("failed to parse event data: %v", err)
 	}
+	want := SettingsChangedEventData{Ruleset: game.DefaultRulesetID, BestOf: 3, TurnTimerSec: 45, StartMode: "auto", Visibility: "public", MaxSpectators: game.DefaultMaxSpectators, Tags: []string{}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("expected event data %+v, got %+v", want, data)
+	}
+}
 
-	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+func TestWS_Lobby_ExpiredLobbyClosed(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
 	if err != nil {
-		t.Fatalf("failed to connect client2: %v", err)
+		t.Fatalf("failed to create lobby: %v", err)
 	}
-	defer client2.Close()
 
-	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
-		t.Fatalf("failed to auth client2: %v", err)
-	}
-	if !ts.WaitForPlayerConnected("player-2", testTimeout) {
-		t.Fatal("player-2 not connected")
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
 	}
+	defer client.Close()
 
-	// Receive client2's auth messages
-	if _, err := client2.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
-		t.Fatalf("client2 failed to receive authenticated: %v", err)
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
 	}
-	if _, err := client2.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
-		t.Fatalf("client2 failed to receive lobby_state: %v", err)
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
 	}
+	client.Drain()
 
-	// Client1 may have received notifications about player-2 connecting - drain them
-	client1.Drain()
-	time.Sleep(50 * time.Millisecond)
-	client1.Drain()
-
-	// Clear client2's buffer too
-	client2.Drain()
-
-	// Send a unique message type to verify routing - use game_starting since it's distinctive
-	ts.Hub.BroadcastToLobbyExcept(lobbyCode, "player-1", TypeGameStarting, GameStartingPayload{
-		StartsAt:     12345,
-		CountdownSec: 3,
-	})
+	// Treat the player as gone so the lobby expires while their connection lingers
+	notConnected := func(string) bool { return false }
+	expired := ts.LobbyService.ExpireIdleLobbies(time.Now().Add(time.Hour), time.Minute, notConnected)
+	if len(expired) != 1 {
+		t.Fatalf("expected the lobby to expire, got %d lobbies", len(expired))
+	}
 
-	// Client2 should receive the broadcast
-	_, err = client2.ReceiveType(TypeGameStarting, testTimeout)
+	env, err := client.ReceiveType(TypeLobbyClosed, testTimeout)
 	if err != nil {
-		t.Fatalf("client2 should receive broadcast: %v", err)
+		t.Fatalf("failed to receive lobby_closed: %v", err)
+	}
+	var payload LobbyClosedPayload
+	if err := env.ParsePayload(&payload); err != nil {
+		t.Fatalf("failed to parse payload: %v", err)
+	}
+	if payload.Code != lobbyCode || payload.Reason != LobbyClosedReasonIdle {
+		t.Errorf("expected lobby %q closed for being idle, got %+v", lobbyCode, payload)
 	}
 
-	// Client1 should NOT receive the game_starting message
-	env, err := client1.Receive(200 * time.Millisecond)
-	if err == nil && env.Type == TypeGameStarting {
-		t.Error("client1 should NOT receive broadcast (was excluded)")
+	if _, err := ts.LobbyService.GetLobby(lobbyCode); !errors.Is(err, services.ErrLobbyNotFound) {
+		t.Errorf("expected the lobby to be removed, got %v", err)
 	}
 }
 
-func TestWS_SendToPlayer_OnlyTargetReceives(t *testing.T) {
+func TestWS_Lobby_Spectator(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
-	// Create lobby with one player initially
 	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
 	if err != nil {
 		t.Fatalf("failed to create lobby: %v", err)
 	}
 
-	// Connect client1 first
-	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	host, err := NewTestClient(ts.WebSocketURL(lobbyCode))
 	if err != nil {
-		t.Fatalf("failed to connect client1: %v", err)
+		t.Fatalf("failed to connect: %v", err)
 	}
-	defer client1.Close()
-
-	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
-		t.Fatalf("failed to auth client1: %v", err)
+	defer host.Close()
+	if err := host.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
 	}
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player-1 not connected")
+	if _, err := host.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("host auth: %v", err)
 	}
+	host.Drain()
 
-	// Receive client1's auth messages
-	if _, err := client1.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
-		t.Fatalf("client1 failed to receive authenticated: %v", err)
+	spectator, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
 	}
-	if _, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
-		t.Fatalf("client1 failed to receive lobby_state: %v", err)
+	defer spectator.Close()
+	if err := spectator.SendAuthAsSpectator("watcher-1", "Watcher", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := spectator.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("spectator auth: %v", err)
 	}
 
-	// Now add and connect player-2
+	// The host hears about the new spectator and sees them on the roster
+	update, err := host.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventSpectatorJoined) {
+		t.Errorf("expected event %q, got %q", LobbyEventSpectatorJoined, update.Event)
+	}
+	if len(update.Lobby.Spectators) != 1 || update.Lobby.Spectators[0].ID != "watcher-1" {
+		t.Errorf("expected watcher-1 on the spectator roster, got %+v", update.Lobby.Spectators)
+	}
+	if len(update.Lobby.Players) != 1 {
+		t.Errorf("expected the spectator not to count as a player, got %d players", len(update.Lobby.Players))
+	}
+	spectator.Drain()
+
+	// Spectators can't act on the game
+	if err := spectator.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if err := spectator.ExpectError(ErrCodeSpectatorOnly, testTimeout); err != nil {
+		t.Errorf("expected spectator ready to be rejected: %v", err)
+	}
+
+	// Leaving frees the spectator slot and tells the lobby
+	env, _ := NewEnvelope(TypeLeaveGame, map[string]interface{}{})
+	if err := spectator.Send(env); err != nil {
+		t.Fatalf("failed to send leave_game: %v", err)
+	}
+	update, err = host.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventSpectatorLeft) || len(update.Lobby.Spectators) != 0 {
+		t.Errorf("expected spectator_left with an empty roster, got %q with %d spectators", update.Event, len(update.Lobby.Spectators))
+	}
+
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if lobby.PlayerCount() != 1 || lobby.GetState() != game.LobbyStateWaiting {
+		t.Errorf("expected the host alone in a waiting lobby, got %d players in %s", lobby.PlayerCount(), lobby.GetState())
+	}
+}
+
+func TestWS_Lobby_WaitlistPromotion(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
 	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
 		t.Fatalf("failed to join lobby: %v", err)
 	}
+	if _, err := ts.LobbyService.JoinWaitlist(lobbyCode, "waiter-1", "Waiter"); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
 
-	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
-	if err != nil {
-		t.Fatalf("failed to connect client2: %v", err)
+	clients := make(map[string]*TestClient)
+	for _, id := range []string{"player-1", "player-2", "waiter-1"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		if err := client.SendAuth(id, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("%s auth: %v", id, err)
+		}
+		clients[id] = client
 	}
-	defer client2.Close()
+	host, leaver, waiter := clients["player-1"], clients["player-2"], clients["waiter-1"]
 
-	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
-		t.Fatalf("failed to auth client2: %v", err)
+	// The waitlisted player sees their place in line and can only watch until promoted
+	state, err := waiter.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby state: %v", err)
 	}
-	if !ts.WaitForPlayerConnected("player-2", testTimeout) {
-		t.Fatal("player-2 not connected")
+	want := []LobbyWaitlistInfo{{ID: "waiter-1", Username: "Waiter", Position: 1}}
+	if !reflect.DeepEqual(state.Lobby.Waitlist, want) {
+		t.Errorf("expected waitlist %+v, got %+v", want, state.Lobby.Waitlist)
 	}
+	if err := waiter.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if err := waiter.ExpectError(ErrCodeSpectatorOnly, testTimeout); err != nil {
+		t.Errorf("expected waitlisted ready to be rejected: %v", err)
+	}
+	host.Drain()
+	waiter.Drain()
 
-	// Receive client2's auth messages
-	if _, err := client2.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
-		t.Fatalf("client2 failed to receive authenticated: %v", err)
+	env, _ := NewEnvelope(TypeLeaveGame, map[string]interface{}{})
+	if err := leaver.Send(env); err != nil {
+		t.Fatalf("failed to send leave_game: %v", err)
 	}
-	if _, err := client2.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
-		t.Fatalf("client2 failed to receive lobby_state: %v", err)
+
+	promotedEnv, err := waiter.ReceiveType(TypeWaitlistPromoted, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive waitlist_promoted: %v", err)
+	}
+	var promoted WaitlistPromotedPayload
+	promotedEnv.ParsePayload(&promoted)
+	if promoted.LobbyCode != lobbyCode {
+		t.Errorf("expected lobby code %q, got %q", lobbyCode, promoted.LobbyCode)
 	}
 
-	// Drain any cross-player notifications
-	client1.Drain()
-	client2.Drain()
-	time.Sleep(50 * time.Millisecond)
-	client1.Drain()
-	client2.Drain()
+	// The lobby hears about the player leaving and the promoted player joining
+	sawLeft, sawJoined := false, false
+	for !sawLeft || !sawJoined {
+		update, err := host.AssertLobbyUpdated(testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive lobby_updated (left %v, joined %v): %v", sawLeft, sawJoined, err)
+		}
+		sawLeft = sawLeft || hasLobbyEvent(update, LobbyEventPlayerLeft)
+		sawJoined = sawJoined || hasLobbyEvent(update, LobbyEventPlayerJoined)
+	}
 
-	// Send game_starting only to player-1 (distinctive message type)
-	ts.Hub.SendToPlayer("player-1", TypeGameStarting, GameStartingPayload{
-		StartsAt:     12345,
-		CountdownSec: 3,
-	})
+	// The promoted player now plays in the lobby
+	waiter.Drain()
+	if err := waiter.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	update, err := host.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventPlayerReadyChanged) || len(update.Lobby.Waitlist) != 0 {
+		t.Errorf("expected player_ready_changed with an empty waitlist, got %q with %+v", update.Event, update.Lobby.Waitlist)
+	}
+}
 
-	// Client1 should receive the message
-	_, err = client1.ReceiveType(TypeGameStarting, testTimeout)
+func TestWS_Lobby_SpectatorRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
 	if err != nil {
-		t.Fatalf("client1 should receive message: %v", err)
+		t.Fatalf("failed to create lobby: %v", err)
 	}
+	maxSpectators := 0
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	lobby.UpdateSettings(game.LobbySettingsUpdate{MaxSpectators: &maxSpectators})
+
+	tests := []struct {
+		name     string
+		playerID string
+		username string
+		expected ErrorCode
+	}{
+		{"spectating turned off", "watcher-1", "Watcher", ErrCodeLobbyFull},
+		{"player spectating their own lobby", "player-1", "Player1", ErrCodeInvalidState},
+		{"missing username", "watcher-2", "", ErrCodeAuthFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+			if err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+			defer client.Close()
 
-	// Client2 should NOT receive game_starting
-	env, err := client2.Receive(200 * time.Millisecond)
-	if err == nil && env.Type == TypeGameStarting {
-		t.Error("client2 should NOT receive message (not targeted)")
+			if err := client.SendAuthAsSpectator(tt.playerID, tt.username, lobbyCode); err != nil {
+				t.Fatalf("failed to auth: %v", err)
+			}
+			if err := client.ExpectError(tt.expected, testTimeout); err != nil {
+				t.Error(err)
+			}
+		})
 	}
 }
 
-func TestWS_DisconnectPlayer_PlayerDisconnected(t *testing.T) {
+func TestWS_Lobby_Chat(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
@@ -935,36 +1324,66 @@ func TestWS_DisconnectPlayer_PlayerDisconnected(t *testing.T) {
 		t.Fatalf("failed to create lobby: %v", err)
 	}
 
-	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	host, err := NewTestClient(ts.WebSocketURL(lobbyCode))
 	if err != nil {
 		t.Fatalf("failed to connect: %v", err)
 	}
-	defer client.Close()
+	defer host.Close()
+	if err := host.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := host.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("host auth: %v", err)
+	}
 
-	// Authenticate
-	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+	spectator, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer spectator.Close()
+	if err := spectator.SendAuthAsSpectator("watcher-1", "Watcher", lobbyCode); err != nil {
 		t.Fatalf("failed to auth: %v", err)
 	}
+	if _, err := spectator.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("spectator auth: %v", err)
+	}
+	host.Drain()
+	spectator.Drain()
 
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player not connected")
+	receiveChat := func(client *TestClient) ChatMessageBroadcastPayload {
+		t.Helper()
+		env, err := client.ReceiveType(TypeChatMessage, testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive chat_message: %v", err)
+		}
+		var payload ChatMessageBroadcastPayload
+		if err := env.ParsePayload(&payload); err != nil {
+			t.Fatalf("failed to parse payload: %v", err)
+		}
+		return payload
 	}
-	client.Drain()
 
-	// Force disconnect via hub
-	ts.Hub.DisconnectPlayer("player-1")
+	// Everyone in the lobby, including the sender, receives the sanitized message
+	if err := host.SendChat("  good\tluck  "); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
+	}
+	for _, client := range []*TestClient{host, spectator} {
+		msg := receiveChat(client)
+		if msg.SenderID != "player-1" || msg.Username != "Player1" || msg.Spectator || msg.Text != "good luck" {
+			t.Errorf("unexpected chat message %+v", msg)
+		}
+	}
 
-	// Player should be disconnected
-	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
-		t.Error("expected player to be disconnected")
+	// Spectators can chat too
+	if err := spectator.SendChat("have fun"); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
+	}
+	if msg := receiveChat(host); msg.SenderID != "watcher-1" || !msg.Spectator {
+		t.Errorf("expected a message from spectator watcher-1, got %+v", msg)
 	}
 }
 
-// ========================================
-// Reconnection Flow Tests
-// ========================================
-
-func TestWS_Reconnect_ValidToken(t *testing.T) {
+func TestWS_Lobby_ChatRejected(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
@@ -973,71 +1392,96 @@ func TestWS_Reconnect_ValidToken(t *testing.T) {
 		t.Fatalf("failed to create lobby: %v", err)
 	}
 
-	// Connect and authenticate first time
-	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
 	if err != nil {
 		t.Fatalf("failed to connect: %v", err)
 	}
+	defer client.Close()
 
-	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
-		t.Fatalf("failed to auth: %v", err)
+	// Chat needs an authenticated connection
+	if err := client.SendChat("hello"); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
 	}
-
-	authPayload, err := client1.AssertAuthSuccess(testTimeout)
-	if err != nil {
-		t.Fatalf("auth failed: %v", err)
+	if err := client.ExpectError(ErrCodeAuthRequired, testTimeout); err != nil {
+		t.Error(err)
 	}
 
-	reconnectToken := authPayload.ReconnectToken
-	if reconnectToken == "" {
-		t.Fatal("expected reconnect token")
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
 	}
 
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player not connected")
+	for _, text := range []string{"   ", strings.Repeat("a", MaxChatMessageLength+1)} {
+		if err := client.SendChat(text); err != nil {
+			t.Fatalf("failed to send chat: %v", err)
+		}
+		if err := client.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+			t.Errorf("expected %d-character message to be rejected: %v", len(text), err)
+		}
 	}
 
-	// Close first connection
-	client1.Close()
-	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
-		t.Fatal("player still connected after close")
+	// Past the rate limit, messages are refused
+	for i := 0; i < defaultChatRateLimit; i++ {
+		if err := client.SendChat("spam"); err != nil {
+			t.Fatalf("failed to send chat: %v", err)
+		}
+		if _, err := client.ReceiveType(TypeChatMessage, testTimeout); err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
 	}
+	if err := client.SendChat("spam"); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
+	}
+	if err := client.ExpectError(ErrCodeRateLimited, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
 
-	// Reconnect with token
-	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+func TestWS_Lobby_HostDeletesDuringBattle(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
 	if err != nil {
-		t.Fatalf("failed to reconnect: %v", err)
+		t.Fatalf("failed to start battle: %v", err)
 	}
+	defer client1.Close()
 	defer client2.Close()
 
-	// Set PlayerID on client2 before sending auth
-	client2.PlayerID = "player-1"
-	client2.LobbyCode = lobbyCode
-
-	// Send auth with reconnect token
-	payload := AuthenticatePayload{
-		PlayerID:       "player-1",
-		LobbyCode:      lobbyCode,
-		ReconnectToken: reconnectToken,
-	}
-	env, _ := NewEnvelope(TypeAuthenticate, payload)
-	env.CorrelationID = "reconnect-auth"
-	if err := client2.Send(env); err != nil {
-		t.Fatalf("failed to send reconnect auth: %v", err)
+	if _, err := ts.LobbyService.DeleteLobby(lobbyCode, "player-1"); err != nil {
+		t.Fatalf("failed to delete lobby: %v", err)
 	}
 
-	// Should succeed
-	_, err = client2.AssertAuthSuccess(testTimeout)
-	if err != nil {
-		t.Fatalf("reconnect auth failed: %v", err)
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeLobbyClosed, testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive lobby_closed: %v", err)
+		}
+		var payload LobbyClosedPayload
+		if err := env.ParsePayload(&payload); err != nil {
+			t.Fatalf("failed to parse payload: %v", err)
+		}
+		if payload.Code != lobbyCode || payload.Reason != LobbyClosedReasonHost {
+			t.Errorf("expected lobby %q closed by the host, got %+v", lobbyCode, payload)
+		}
+		if err := client.ExpectClose(CloseCodeLobbyClosed, testTimeout); err != nil {
+			t.Error(err)
+		}
 	}
 
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Error("player should be connected after reconnect")
+	for _, playerID := range []string{"player-1", "player-2"} {
+		if !ts.WaitForPlayerDisconnected(playerID, testTimeout) {
+			t.Errorf("expected %s to be disconnected", playerID)
+		}
+	}
+	if _, err := ts.BattleService.GetLobbyBattle(lobbyCode); !errors.Is(err, services.ErrBattleNotFound) {
+		t.Errorf("expected the game in progress to be discarded, got %v", err)
 	}
 }
 
-func TestWS_Reconnect_InvalidToken(t *testing.T) {
+func TestWS_Team_IllegalTeamRejected(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
 
@@ -1046,56 +1490,4797 @@ func TestWS_Reconnect_InvalidToken(t *testing.T) {
 		t.Fatalf("failed to create lobby: %v", err)
 	}
 
-	// Connect and authenticate first time to establish session
-	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
 	if err != nil {
 		t.Fatalf("failed to connect: %v", err)
 	}
+	defer client.Close()
 
-	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
 		t.Fatalf("failed to auth: %v", err)
 	}
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+	client.Drain()
 
-	_, err = client1.AssertAuthSuccess(testTimeout)
+	// Pikachu cannot learn surf
+	team := []TeamMemberPayload{{SpeciesID: "pikachu", Moves: []string{"surf"}}}
+	if err := client.SendSubmitTeam(team); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+
+	env, err := client.ReceiveType(TypeError, testTimeout)
 	if err != nil {
-		t.Fatalf("auth failed: %v", err)
+		t.Fatalf("expected error: %v", err)
+	}
+	var errPayload ErrorPayload
+	if err := env.ParsePayload(&errPayload); err != nil {
+		t.Fatalf("failed to parse error: %v", err)
+	}
+	if errPayload.Code != ErrCodeInvalidAction {
+		t.Errorf("expected code %s, got %s", ErrCodeInvalidAction, errPayload.Code)
 	}
 
-	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
-		t.Fatal("player not connected")
+	var details InvalidTeamDetails
+	if err := json.Unmarshal(errPayload.Details, &details); err != nil {
+		t.Fatalf("failed to parse error details: %v", err)
+	}
+	if len(details.Violations) != 1 || details.Violations[0].Rule != string(game.RuleMoves) {
+		t.Errorf("expected a single moves violation, got %+v", details.Violations)
 	}
 
-	// Try to reconnect with invalid token while still connected
-	// This tests the reconnection validation path
-	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	lobby, err := ts.LobbyService.GetLobby(lobbyCode)
 	if err != nil {
-		t.Fatalf("failed to connect second client: %v", err)
+		t.Fatalf("failed to get lobby: %v", err)
 	}
-	defer client2.Close()
+	if lobby.HasTeam("player-1") {
+		t.Error("expected illegal team not to be stored")
+	}
+}
 
-	// Set PlayerID on client2 before sending auth
-	client2.PlayerID = "player-1"
-	client2.LobbyCode = lobbyCode
+func TestWS_Team_GameWaitsForBothTeams(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
 
-	// Send auth with invalid reconnect token - should still work as new auth
-	// (the token is just ignored if invalid, and we proceed with regular auth)
-	payload := AuthenticatePayload{
-		PlayerID:       "player-1",
-		LobbyCode:      lobbyCode,
-		ReconnectToken: "invalid-token-that-does-not-exist",
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
 	}
-	env, _ := NewEnvelope(TypeAuthenticate, payload)
-	env.CorrelationID = "invalid-reconnect"
-	if err := client2.Send(env); err != nil {
-		t.Fatalf("failed to send: %v", err)
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
 	}
 
-	// Should still succeed (new session replaces old)
-	_, err = client2.AssertAuthSuccess(testTimeout)
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect %s: %v", playerID, err)
+		}
+		defer client.Close()
+		clients[i] = client
+
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth %s: %v", playerID, err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth %s: %v", playerID, err)
+		}
+	}
+
+	// Both ready, only player-1 has a team
+	if err := clients[0].SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	for _, client := range clients {
+		if err := client.SendReady(true); err != nil {
+			t.Fatalf("failed to send ready: %v", err)
+		}
+	}
+
+	if _, err := clients[0].ReceiveType(TypeGameStarted, 200*time.Millisecond); err == nil {
+		t.Fatal("expected game not to start before both teams are submitted")
+	}
+
+	// The last team submission starts the game
+	if err := clients[1].SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	for _, client := range clients {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("failed to receive game_started: %v", err)
+		}
+	}
+}
+
+// ========================================
+// Team Preview Tests
+// ========================================
+
+// startTeamPreview connects two players to a competitive lobby, submits starter teams and readies both.
+// It returns the clients once each has received team_preview.
+func startTeamPreview(t *testing.T, ts *TestServer) ([]*TestClient, []TeamPreviewPayload) {
+	t.Helper()
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
 	if err != nil {
-		t.Fatalf("auth should succeed even with invalid reconnect token: %v", err)
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	if _, err := ts.LobbyService.SetRuleset(lobbyCode, "player-1", "competitive"); err != nil {
+		t.Fatalf("failed to set ruleset: %v", err)
 	}
 
-	// Clean up first client
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect %s: %v", playerID, err)
+		}
+		t.Cleanup(func() { client.Close() })
+		clients[i] = client
+
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth %s: %v", playerID, err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth %s: %v", playerID, err)
+		}
+	}
+
+	for _, client := range clients {
+		if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+			t.Fatalf("failed to submit team: %v", err)
+		}
+		if err := client.SendReady(true); err != nil {
+			t.Fatalf("failed to send ready: %v", err)
+		}
+	}
+
+	previews := make([]TeamPreviewPayload, 2)
+	for i, client := range clients {
+		env, err := client.ReceiveType(TypeTeamPreview, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive team_preview: %v", client.PlayerID, err)
+		}
+		if err := env.ParsePayload(&previews[i]); err != nil {
+			t.Fatalf("failed to parse team_preview: %v", err)
+		}
+		client.Drain()
+	}
+	return clients, previews
+}
+
+func TestWS_TeamPreview_RevealsOpposingSpecies(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients, previews := startTeamPreview(t, ts)
+
+	for i, preview := range previews {
+		if preview.PlayerState.PlayerID != clients[i].PlayerID {
+			t.Errorf("expected own state for %s, got %s", clients[i].PlayerID, preview.PlayerState.PlayerID)
+		}
+		if len(preview.PlayerState.Team) != 3 || len(preview.PlayerState.Team[0].Moves) == 0 {
+			t.Error("expected own team with moves")
+		}
+		if preview.Opponent.PlayerID == clients[i].PlayerID {
+			t.Error("expected opponent to be the other player")
+		}
+		if len(preview.Opponent.Team) != 3 || preview.Opponent.Team[0].SpeciesID != "venusaur" {
+			t.Errorf("expected opposing starter species, got %+v", preview.Opponent.Team)
+		}
+	}
+}
+
+// readyLobbyWithCountdown creates a lobby with the given start countdown and two connected players who
+// have submitted teams. Neither is ready yet; both clients are drained.
+func readyLobbyWithCountdown(t *testing.T, ts *TestServer, countdown time.Duration) (string, *TestClient, *TestClient) {
+	t.Helper()
+
+	update := services.LobbySettingsUpdate{}
+	update.Countdown = &countdown
+	return readyLobbyWithSettings(t, ts, update)
+}
+
+// readyLobbyWithSettings is readyLobbyWithCountdown for a lobby configured with any settings
+func readyLobbyWithSettings(t *testing.T, ts *TestServer, update services.LobbySettingsUpdate) (string, *TestClient, *TestClient) {
+	t.Helper()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if _, err := ts.LobbyService.UpdateSettings(lobbyCode, "player-1", update); err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect %s: %v", playerID, err)
+		}
+		clients[i] = client
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth %s: %v", playerID, err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("%s failed to authenticate: %v", playerID, err)
+		}
+		if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+			t.Fatalf("failed to submit team for %s: %v", playerID, err)
+		}
+	}
+	for _, client := range clients {
+		if _, err := client.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive lobby_updated: %v", client.PlayerID, err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	clients[0].Drain()
+	clients[1].Drain()
+
+	return lobbyCode, clients[0], clients[1]
+}
+
+func TestWS_StartCountdown_DelaysGameStarted(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2 := readyLobbyWithCountdown(t, ts, time.Second)
+	defer client1.Close()
+	defer client2.Close()
+
+	client1.SendReady(true)
+	client2.SendReady(true)
+
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeGameStarting, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_starting: %v", client.PlayerID, err)
+		}
+		var starting GameStartingPayload
+		env.ParsePayload(&starting)
+		if starting.CountdownSec != 1 {
+			t.Errorf("expected countdown_sec 1, got %d", starting.CountdownSec)
+		}
+	}
+
+	if _, err := client1.ReceiveType(TypeGameStarted, 500*time.Millisecond); err == nil {
+		t.Fatal("expected game_started to wait for the countdown")
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive game_started after the countdown: %v", client.PlayerID, err)
+		}
+	}
+}
+
+func TestWS_StartCountdown_HostCancels(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2 := readyLobbyWithCountdown(t, ts, time.Second)
+	defer client1.Close()
+	defer client2.Close()
+
+	// Only the host may cancel, and only while a countdown is running
+	client1.SendCancelGameStart()
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE without a countdown: %v", err)
+	}
+
+	client1.SendReady(true)
+	client2.SendReady(true)
+	if _, err := client2.ReceiveType(TypeGameStarting, testTimeout); err != nil {
+		t.Fatalf("failed to receive game_starting: %v", err)
+	}
+
+	client2.SendCancelGameStart()
+	if err := client2.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_ACTION for a non-host: %v", err)
+	}
+
+	client1.SendCancelGameStart()
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeGameStartCancelled, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_start_cancelled: %v", client.PlayerID, err)
+		}
+		var cancelled GameStartCancelledPayload
+		env.ParsePayload(&cancelled)
+		if cancelled.Reason != GameStartCancelledReasonHost || cancelled.PlayerID != "player-1" {
+			t.Errorf("unexpected game_start_cancelled payload: %+v", cancelled)
+		}
+	}
+
+	if _, err := client1.ReceiveType(TypeGameStarted, 1500*time.Millisecond); err == nil {
+		t.Error("expected the game not to start after the host cancelled")
+	}
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if lobby.GetState() == game.LobbyStateActive {
+		t.Error("expected the lobby not to be active")
+	}
+}
+
+func TestWS_StartCountdown_CancelledWhenPlayerUnreadies(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2 := readyLobbyWithCountdown(t, ts, time.Second)
+	defer client1.Close()
+	defer client2.Close()
+
+	client1.SendReady(true)
+	client2.SendReady(true)
+	if _, err := client1.ReceiveType(TypeGameStarting, testTimeout); err != nil {
+		t.Fatalf("failed to receive game_starting: %v", err)
+	}
+
+	client2.SendReady(false)
+	env, err := client1.ReceiveType(TypeGameStartCancelled, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_start_cancelled: %v", err)
+	}
+	var cancelled GameStartCancelledPayload
+	env.ParsePayload(&cancelled)
+	if cancelled.Reason != GameStartCancelledReasonNotReady || cancelled.PlayerID != "player-2" {
+		t.Errorf("unexpected game_start_cancelled payload: %+v", cancelled)
+	}
+}
+
+func TestWS_HostStartMode_WaitsForHost(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	startMode := game.StartModeHost
+	update := services.LobbySettingsUpdate{}
+	update.StartMode = &startMode
+	_, client1, client2 := readyLobbyWithSettings(t, ts, update)
+	defer client1.Close()
+	defer client2.Close()
+
+	// The host can't start before everyone is ready
+	client1.SendStartGame()
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE before players are ready: %v", err)
+	}
+
+	client1.SendReady(true)
+	client2.SendReady(true)
+	if _, err := client1.ReceiveType(TypeGameStarting, 300*time.Millisecond); err == nil {
+		t.Fatal("expected the game to wait for the host once everyone is ready")
+	}
+
+	client2.SendStartGame()
+	if err := client2.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_ACTION for a non-host: %v", err)
+	}
+
+	client1.SendStartGame()
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive game_started: %v", client.PlayerID, err)
+		}
+	}
+}
+
+func TestWS_AutoStartMode_RejectsStartGame(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2 := readyLobbyWithCountdown(t, ts, 0)
+	defer client1.Close()
+	defer client2.Close()
+
+	client1.SendStartGame()
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE in an auto-start lobby: %v", err)
+	}
+}
+
+func TestWS_TeamPreview_LeadsStartTurnOne(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients, _ := startTeamPreview(t, ts)
+
+	if err := clients[0].SendChooseLead(2); err != nil {
+		t.Fatalf("failed to send choose_lead: %v", err)
+	}
+	if _, err := clients[0].ReceiveType(TypeGameState, 200*time.Millisecond); err == nil {
+		t.Fatal("expected no game_state until both leads are chosen")
+	}
+	if err := clients[1].SendChooseLead(1); err != nil {
+		t.Fatalf("failed to send choose_lead: %v", err)
+	}
+
+	leads := []int{2, 1}
+	for i, client := range clients {
+		env, err := client.ReceiveType(TypeGameState, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_state: %v", client.PlayerID, err)
+		}
+		var state GameStatePayload
+		if err := env.ParsePayload(&state); err != nil {
+			t.Fatalf("failed to parse game_state: %v", err)
+		}
+		if state.Phase != GamePhaseActionSelection || state.TurnNumber != 1 {
+			t.Errorf("expected action selection on turn 1, got %s on turn %d", state.Phase, state.TurnNumber)
+		}
+		if state.PlayerState.ActiveSlot != leads[i] {
+			t.Errorf("expected slot %d to lead, got %d", leads[i], state.PlayerState.ActiveSlot)
+		}
+		if state.OpponentState.ActiveSlot != leads[1-i] {
+			t.Errorf("expected opponent slot %d to lead, got %d", leads[1-i], state.OpponentState.ActiveSlot)
+		}
+	}
+}
+
+func TestWS_TeamPreview_ActionRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients, _ := startTeamPreview(t, ts)
+
+	if err := clients[0].SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := clients[0].ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWS_TeamPreview_InvalidLeadRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients, _ := startTeamPreview(t, ts)
+
+	if err := clients[0].SendChooseLead(6); err != nil {
+		t.Fatalf("failed to send choose_lead: %v", err)
+	}
+	if err := clients[0].ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+// ========================================
+// Draft Tests
+// ========================================
+
+// startDraft connects two players to a draft-mode lobby and returns the clients once each has
+// received the opening draft_state
+func startDraft(t *testing.T, ts *TestServer) []*TestClient {
+	t.Helper()
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	if _, err := ts.LobbyService.SetDraftMode(lobbyCode, "player-1", true); err != nil {
+		t.Fatalf("failed to enable draft mode: %v", err)
+	}
+
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect %s: %v", playerID, err)
+		}
+		t.Cleanup(func() { client.Close() })
+		clients[i] = client
+
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth %s: %v", playerID, err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth %s: %v", playerID, err)
+		}
+	}
+
+	for _, client := range clients {
+		env, err := client.ReceiveType(TypeDraftState, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive draft_state: %v", client.PlayerID, err)
+		}
+		var state DraftStatePayload
+		env.ParsePayload(&state)
+		if state.CurrentTurn == nil || state.CurrentTurn.PlayerID != "player-1" || state.CurrentTurn.Kind != "ban" {
+			t.Fatalf("expected host to ban first, got %+v", state.CurrentTurn)
+		}
+		if state.TurnDeadline == 0 {
+			t.Error("expected the opening turn to have a deadline")
+		}
+	}
+	return clients
+}
+
+func TestWS_Draft_FullDraftThenSubmitTeam(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients := startDraft(t, ts)
+	host, guest := clients[0], clients[1]
+
+	picks := []struct {
+		client    *TestClient
+		speciesID string
+	}{
+		{host, "gengar"}, {guest, "alakazam"},
+		{host, "venusaur"}, {guest, "machamp"}, {guest, "pikachu"},
+		{host, "charizard"}, {host, "blastoise"}, {guest, "snorlax"},
+	}
+
+	var state DraftStatePayload
+	for _, p := range picks {
+		if err := p.client.SendSubmitPick(p.speciesID); err != nil {
+			t.Fatalf("failed to send pick: %v", err)
+		}
+		for _, client := range clients {
+			env, err := client.ReceiveType(TypeDraftState, testTimeout)
+			if err != nil {
+				t.Fatalf("%s failed to receive draft_state after %s: %v", client.PlayerID, p.speciesID, err)
+			}
+			state = DraftStatePayload{}
+			env.ParsePayload(&state)
+		}
+	}
+
+	if !state.Complete || state.CurrentTurn != nil {
+		t.Fatalf("expected a complete draft, got %+v", state)
+	}
+	if len(state.Picks["player-1"]) != 3 || len(state.Bans["player-2"]) != 1 {
+		t.Errorf("unexpected picks %v and bans %v", state.Picks, state.Bans)
+	}
+
+	if err := host.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	env, err := host.ReceiveType(TypeLobbyUpdated, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	var update LobbyUpdatedPayload
+	env.ParsePayload(&update)
+	if !hasLobbyEvent(&update, LobbyEventTeamSubmitted) || !update.Lobby.DraftMode {
+		t.Errorf("expected team_submitted in a draft-mode lobby, got %s (draft_mode %v)", update.Event, update.Lobby.DraftMode)
+	}
+
+	if err := guest.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	if err := guest.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Errorf("expected undrafted team to be rejected: %v", err)
+	}
+}
+
+func TestWS_Draft_RejectsOutOfTurnPicksAndEarlyTeams(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	clients := startDraft(t, ts)
+	host, guest := clients[0], clients[1]
+
+	if err := guest.SendSubmitPick("snorlax"); err != nil {
+		t.Fatalf("failed to send pick: %v", err)
+	}
+	if err := guest.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Errorf("expected out-of-turn pick to be rejected: %v", err)
+	}
+
+	if err := host.SendSubmitPick("missingno"); err != nil {
+		t.Fatalf("failed to send pick: %v", err)
+	}
+	if err := host.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Errorf("expected unknown species to be rejected: %v", err)
+	}
+
+	if err := host.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	if err := host.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Errorf("expected team to be rejected mid-draft: %v", err)
+	}
+}
+
+func TestWS_Draft_TurnTimesOut(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.draftPickTimeout = 50 * time.Millisecond
+
+	clients := startDraft(t, ts)
+
+	// Every turn expires: bans are skipped and picks take the first available species
+	for {
+		env, err := clients[1].ReceiveType(TypeDraftState, testTimeout)
+		if err != nil {
+			t.Fatalf("expected the draft to advance on its own: %v", err)
+		}
+		var state DraftStatePayload
+		env.ParsePayload(&state)
+		if !state.Complete {
+			continue
+		}
+		if len(state.Bans["player-1"]) != 0 || len(state.Picks["player-1"]) != 3 || len(state.Picks["player-2"]) != 3 {
+			t.Errorf("unexpected bans %v and picks %v", state.Bans, state.Picks)
+		}
+		if state.Picks["player-1"][0] != "alakazam" {
+			t.Errorf("expected the first pool species to be auto-picked, got %s", state.Picks["player-1"][0])
+		}
+		return
+	}
+}
+
+// ========================================
+// Bot Tests
+// ========================================
+
+// startBotBattle connects player-1 to a lobby with a bot of the given difficulty under the given
+// ruleset, submits the starter team and readies up. It returns the client once game_started has been received.
+func startBotBattle(t *testing.T, ts *TestServer, rulesetID string, difficulty game.BotDifficulty) (string, *TestClient) {
+	t.Helper()
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if _, err := ts.LobbyService.SetRuleset(lobbyCode, "player-1", rulesetID); err != nil {
+		t.Fatalf("failed to set ruleset: %v", err)
+	}
+	if _, err := ts.LobbyService.AddBot(lobbyCode, "player-1", difficulty); err != nil {
+		t.Fatalf("failed to add bot: %v", err)
+	}
+
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+	update, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby state: %v", err)
+	}
+	if bot := update.Lobby.Players[1]; !bot.IsBot || !bot.IsReady || !bot.HasTeam {
+		t.Errorf("expected a ready bot with a team, got %+v", bot)
+	}
+
+	if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+		t.Fatalf("failed to submit team: %v", err)
+	}
+	if err := client.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+		t.Fatalf("failed to receive game_started: %v", err)
+	}
+	return lobbyCode, client
+}
+
+// firstUsableMove returns the first move of the player's active creature with PP left
+func firstUsableMove(state PlayerBattleState) string {
+	for _, m := range state.Team[state.ActiveSlot].Moves {
+		if m.PP > 0 {
+			return m.ID
+		}
+	}
+	return ""
+}
+
+func TestWS_Bot_CommitsBeforeHuman(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := startBotBattle(t, ts, "standard", game.DefaultBotDifficulty)
+
+	if _, err := client.ReceiveType(TypeOpponentCommitted, testTimeout); err != nil {
+		t.Fatalf("expected the bot's turn 1 action to be announced: %v", err)
+	}
+}
+
+func TestWS_Bot_AnswersEachTurn(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client := startBotBattle(t, ts, "standard", game.DefaultBotDifficulty)
+
+	if err := client.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	env, err := client.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("expected the bot's action to complete the turn: %v", err)
+	}
+
+	var result TurnResultPayload
+	env.ParsePayload(&result)
+	botActed := false
+	for _, e := range result.Events {
+		if e.Actor == game.BotPlayerID(lobbyCode) {
+			botActed = true
+		}
+	}
+	if !botActed {
+		t.Errorf("expected a turn event from the bot, got %+v", result.Events)
+	}
+}
+
+func TestWS_Bot_ChoosesLeadInTeamPreview(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := startBotBattle(t, ts, "competitive", game.DefaultBotDifficulty)
+
+	if _, err := client.ReceiveType(TypeTeamPreview, testTimeout); err != nil {
+		t.Fatalf("failed to receive team_preview: %v", err)
+	}
+	if err := client.SendChooseLead(0); err != nil {
+		t.Fatalf("failed to choose lead: %v", err)
+	}
+	env, err := client.ReceiveType(TypeGameState, testTimeout)
+	if err != nil {
+		t.Fatalf("expected the bot's lead to end team preview: %v", err)
+	}
+	var state GameStatePayload
+	env.ParsePayload(&state)
+	if state.Phase != GamePhaseActionSelection {
+		t.Errorf("expected action_selection, got %s", state.Phase)
+	}
+
+	if err := client.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Errorf("expected the bot to act on turn 1: %v", err)
+	}
+}
+
+func TestWS_Bot_PlaysFullGame(t *testing.T) {
+	for _, difficulty := range game.ListBotDifficulties() {
+		t.Run(string(difficulty), func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+
+			_, client := startBotBattle(t, ts, "standard", difficulty)
+
+			turn := 1
+			if err := client.SendAttack(turn, "razor-leaf"); err != nil {
+				t.Fatalf("failed to send attack: %v", err)
+			}
+
+			// Attack whenever a turn is open and replace fainted creatures until the game ends;
+			// the bot has to choose its own replacements for the game to finish
+			for i := 0; i < 500; i++ {
+				env, err := client.Receive(testTimeout)
+				if err != nil {
+					t.Fatalf("game stalled on turn %d: %v", turn, err)
+				}
+
+				switch env.Type {
+				case TypeTurnResult:
+					var result TurnResultPayload
+					env.ParsePayload(&result)
+					turn = result.ResultingState.TurnNumber
+					if result.ResultingState.Phase != GamePhaseActionSelection {
+						continue
+					}
+					client.SendAttack(turn, firstUsableMove(result.ResultingState.PlayerState))
+				case TypeSwitchRequired:
+					var req SwitchRequiredPayload
+					env.ParsePayload(&req)
+					client.SendAction(turn, ActionTypeSwitch, SwitchActionData{CreatureSlot: req.AvailableSlots[0]})
+				case TypeGameEnded:
+					return
+				}
+			}
+			t.Fatal("expected the game to end")
+		})
+	}
+}
+
+// ========================================
+// Error Handling Tests
+// ========================================
+
+func TestWS_Error_UnknownMessageType(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// Authenticate first
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player not connected")
+	}
+	client.Drain()
+
+	// Send unknown message type
+	env := &Envelope{
+		Type:      MessageType("unknown_type"),
+		Version:   ProtocolVersion,
+		Timestamp: 1234567890,
+		Payload:   []byte("{}"),
+	}
+
+	if err := client.Send(env); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+
+	if err := client.ExpectError(ErrCodeMalformedMessage, testTimeout); err != nil {
+		t.Fatalf("expected MALFORMED_MESSAGE error: %v", err)
+	}
+}
+
+func TestWS_Error_InvalidPayloadFields(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := connectHost(t, ts)
+	defer client.Close()
+	client.Drain()
+
+	// Every invalid field is listed, including those in the action's data
+	if err := client.SendAction(-1, ActionTypeAttack, AttackActionData{}); err != nil {
+		t.Fatalf("failed to send action: %v", err)
+	}
+	fields, err := client.ExpectInvalidFields(testTimeout)
+	if err != nil {
+		t.Fatalf("expected MALFORMED_MESSAGE error: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Field != "turn_number" || fields[1].Field != "action_data.move_id" {
+		t.Errorf("expected turn_number and action_data.move_id to be listed, got %+v", fields)
+	}
+
+	// A value of the wrong JSON type is named by its field
+	env := &Envelope{Type: TypeChooseLead, Version: ProtocolVersion, Payload: []byte(`{"slot":"first"}`)}
+	if err := client.Send(env); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	fields, err = client.ExpectInvalidFields(testTimeout)
+	if err != nil {
+		t.Fatalf("expected MALFORMED_MESSAGE error: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Field != "slot" {
+		t.Errorf("expected slot to be listed, got %+v", fields)
+	}
+}
+
+// ========================================
+// Hub Integration Tests
+// ========================================
+
+func TestWS_BroadcastToLobbyExcept_ExcludedPlayerDoesNotReceive(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	// Create lobby with one player initially
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// Connect client1 first, before adding player-2
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect client1: %v", err)
+	}
+	defer client1.Close()
+
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth client1: %v", err)
+	}
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player-1 not connected")
+	}
+
+	// Receive client1's auth messages
+	if _, err := client1.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("client1 failed to receive authenticated: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+		t.Fatalf("client1 failed to receive lobby_state: %v", err)
+	}
+
+	// Now add and connect player-2
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect client2: %v", err)
+	}
+	defer client2.Close()
+
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth client2: %v", err)
+	}
+	if !ts.WaitForPlayerConnected("player-2", testTimeout) {
+		t.Fatal("player-2 not connected")
+	}
+
+	// Receive client2's auth messages
+	if _, err := client2.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("client2 failed to receive authenticated: %v", err)
+	}
+	if _, err := client2.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+		t.Fatalf("client2 failed to receive lobby_state: %v", err)
+	}
+
+	// Client1 may have received notifications about player-2 connecting - drain them
+	client1.Drain()
+	time.Sleep(50 * time.Millisecond)
+	client1.Drain()
+
+	// Clear client2's buffer too
+	client2.Drain()
+
+	// Send a unique message type to verify routing - use game_starting since it's distinctive
+	ts.Hub.BroadcastToLobbyExcept(lobbyCode, "player-1", TypeGameStarting, GameStartingPayload{
+		StartsAt:     12345,
+		CountdownSec: 3,
+	})
+
+	// Client2 should receive the broadcast
+	_, err = client2.ReceiveType(TypeGameStarting, testTimeout)
+	if err != nil {
+		t.Fatalf("client2 should receive broadcast: %v", err)
+	}
+
+	// Client1 should NOT receive the game_starting message
+	env, err := client1.Receive(200 * time.Millisecond)
+	if err == nil && env.Type == TypeGameStarting {
+		t.Error("client1 should NOT receive broadcast (was excluded)")
+	}
+}
+
+func TestWS_SendToPlayer_OnlyTargetReceives(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	// Create lobby with one player initially
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// Connect client1 first
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect client1: %v", err)
+	}
+	defer client1.Close()
+
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth client1: %v", err)
+	}
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player-1 not connected")
+	}
+
+	// Receive client1's auth messages
+	if _, err := client1.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("client1 failed to receive authenticated: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+		t.Fatalf("client1 failed to receive lobby_state: %v", err)
+	}
+
+	// Now add and connect player-2
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect client2: %v", err)
+	}
+	defer client2.Close()
+
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth client2: %v", err)
+	}
+	if !ts.WaitForPlayerConnected("player-2", testTimeout) {
+		t.Fatal("player-2 not connected")
+	}
+
+	// Receive client2's auth messages
+	if _, err := client2.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("client2 failed to receive authenticated: %v", err)
+	}
+	if _, err := client2.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+		t.Fatalf("client2 failed to receive lobby_state: %v", err)
+	}
+
+	// Drain any cross-player notifications
+	client1.Drain()
+	client2.Drain()
+	time.Sleep(50 * time.Millisecond)
+	client1.Drain()
+	client2.Drain()
+
+	// Send game_starting only to player-1 (distinctive message type)
+	ts.Hub.SendToPlayer("player-1", TypeGameStarting, GameStartingPayload{
+		StartsAt:     12345,
+		CountdownSec: 3,
+	})
+
+	// Client1 should receive the message
+	_, err = client1.ReceiveType(TypeGameStarting, testTimeout)
+	if err != nil {
+		t.Fatalf("client1 should receive message: %v", err)
+	}
+
+	// Client2 should NOT receive game_starting
+	env, err := client2.Receive(200 * time.Millisecond)
+	if err == nil && env.Type == TypeGameStarting {
+		t.Error("client2 should NOT receive message (not targeted)")
+	}
+}
+
+func TestWS_DisconnectPlayer_PlayerDisconnected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// Authenticate
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player not connected")
+	}
+	client.Drain()
+
+	// Force disconnect via hub
+	ts.Hub.DisconnectPlayer("player-1")
+
+	// Player should be disconnected
+	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
+		t.Error("expected player to be disconnected")
+	}
+}
+
+// ========================================
+// Connection Limit Tests
+// ========================================
+
+func TestWS_ConnectionLimits_PerIP(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Hub.SetConnectionLimits(ConnectionLimits{PerIP: 1})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	first, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	_, resp, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), nil)
+	if err == nil {
+		t.Fatal("expected a second connection from the same address to be refused")
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %v", resp)
+	}
+
+	// Closing the first connection frees the address's slot
+	first.Close()
+	released := waitFor(func() bool {
+		ts.Hub.mu.RLock()
+		defer ts.Hub.mu.RUnlock()
+		return len(ts.Hub.ipConnections) == 0
+	}, testTimeout)
+	if !released {
+		t.Fatal("expected the address's connection to be released")
+	}
+	second, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("expected to connect once the first connection closed: %v", err)
+	}
+	second.Close()
+}
+
+func TestWS_ConnectionLimits_PerIP_IgnoresForwardedFor(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Hub.SetConnectionLimits(ConnectionLimits{PerIP: 1})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// A client claiming a different address on each connection is still counted by the one it connects from
+	header := http.Header{"X-Forwarded-For": {"203.0.113.1"}}
+	first, _, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), header)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer first.Close()
+
+	header = http.Header{"X-Forwarded-For": {"203.0.113.2"}}
+	_, resp, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), header)
+	if err == nil {
+		t.Fatal("expected a spoofed X-Forwarded-For not to get past the address's cap")
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %v", resp)
+	}
+}
+
+func TestWS_ConnectionLimits_PerPlayer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Hub.SetConnectionLimits(ConnectionLimits{PerPlayer: 1})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	first, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer first.Close()
+	if err := first.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := first.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	second, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer second.Close()
+	if err := second.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := second.ExpectError(ErrCodeTooManyConnections, testTimeout); err != nil {
+		t.Fatalf("expected TOO_MANY_CONNECTIONS: %v", err)
+	}
+	if err := second.ExpectClose(CloseCodeTooManyConnections, testTimeout); err != nil {
+		t.Error(err)
+	}
+	if ts.Hub.GetConnectionByPlayerID("player-1") == nil {
+		t.Error("expected the first connection to stay")
+	}
+}
+
+func TestWS_ConnectionLimits_PerPlayer_Reconnect(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Hub.SetConnectionLimits(ConnectionLimits{PerPlayer: 1})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	first, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer first.Close()
+	if err := first.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	auth, err := first.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	// The connection a reconnect token replaces doesn't count against the player's cap
+	second, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer second.Close()
+	if err := second.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, ReconnectToken: auth.ReconnectToken}); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := second.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("expected the reconnect to be admitted at the cap: %v", err)
+	}
+	if err := first.ExpectClose(CloseCodeReplaced, testTimeout); err != nil {
+		t.Error(err)
+	}
+
+	// The replacement holds the player's only slot
+	third, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer third.Close()
+	if err := third.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := third.ExpectError(ErrCodeTooManyConnections, testTimeout); err != nil {
+		t.Errorf("expected TOO_MANY_CONNECTIONS: %v", err)
+	}
+}
+
+func TestWS_OriginCheck(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetOriginPolicy(OriginPolicy{AllowedOrigins: []string{"https://*.example.com"}})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	dial := func(origin string) (*http.Response, error) {
+		conn, resp, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), http.Header{"Origin": {origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return resp, err
+	}
+
+	if _, err := dial("https://play.example.com"); err != nil {
+		t.Errorf("expected an allowed subdomain to connect: %v", err)
+	}
+	resp, err := dial("https://evil.test")
+	if err == nil {
+		t.Fatal("expected a connection from a disallowed origin to be refused")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403, got %v", resp)
+	}
+
+	// The dev-mode bypass lets any origin through
+	ts.Handler.SetOriginPolicy(OriginPolicy{AllowAny: true})
+	if _, err := dial("https://evil.test"); err != nil {
+		t.Errorf("expected any origin to connect with the bypass on: %v", err)
+	}
+}
+
+// testSessionValidator accepts "token-<player ID>" for player-1 and player-2
+func testSessionValidator(token string) (string, error) {
+	switch token {
+	case "token-player-1":
+		return "player-1", nil
+	case "token-player-2":
+		return "player-2", nil
+	}
+	return "", ErrInvalidSessionToken
+}
+
+func TestWS_SessionAuth_OnUpgrade(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetSessionAuth(SessionAuth{Validate: testSessionValidator, RequireOnUpgrade: true})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	// Upgrades without a valid token are refused before a connection exists
+	for _, url := range []string{ts.WebSocketURL(lobbyCode), ts.WebSocketURL(lobbyCode) + "?token=bogus"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+		if err == nil {
+			t.Fatalf("expected the upgrade to %s to be refused", url)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected 401 for %s, got %v", url, resp)
+		}
+	}
+	if ts.Hub.ConnectionCount() != 0 {
+		t.Errorf("expected no connections to be allocated, got %d", ts.Hub.ConnectionCount())
+	}
+
+	// A token in the query parameter only authenticates the player it was issued to
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode) + "?token=token-player-1")
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+	if err := client.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := client.ExpectError(ErrCodeAuthFailed, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_FAILED authenticating as another player: %v", err)
+	}
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("expected authenticated: %v", err)
+	}
+
+	// So does a bearer token in the Authorization header
+	conn, _, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), http.Header{"Authorization": {"Bearer token-player-2"}})
+	if err != nil {
+		t.Fatalf("expected a bearer token to be accepted: %v", err)
+	}
+	conn.Close()
+}
+
+func TestWS_SessionAuth_OnAuthenticate(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetSessionAuth(SessionAuth{Validate: testSessionValidator})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("expected an upgrade without a token to be allowed: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.SendAuthWithSessionToken("player-1", lobbyCode, "token-player-2"); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := client.ExpectError(ErrCodeAuthFailed, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_FAILED for another player's token: %v", err)
+	}
+	if err := client.SendAuthWithSessionToken("player-1", lobbyCode, "token-player-1"); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeAuthenticated, testTimeout); err != nil {
+		t.Fatalf("expected authenticated: %v", err)
+	}
+}
+
+func TestWS_SessionAuth_RequiredWithoutValidator(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetSessionAuth(SessionAuth{Required: true})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// With nothing to check tokens against, no client is trusted, whatever it presents
+	for _, url := range []string{ts.WebSocketURL(lobbyCode), ts.WebSocketURL(lobbyCode) + "?token=token-player-1"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+		if err == nil {
+			t.Fatalf("expected the upgrade to %s to be refused", url)
+		}
+		if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 for %s, got %v", url, resp)
+		}
+	}
+	if ts.Hub.ConnectionCount() != 0 {
+		t.Errorf("expected no connections to be allocated, got %d", ts.Hub.ConnectionCount())
+	}
+}
+
+func TestWS_SessionAuth_SignedTokens(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	validate, err := NewSignedSessionValidator(testSessionSecret)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	ts.Handler.SetSessionAuth(SessionAuth{Validate: validate, Required: true})
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+
+	// Forged, expired and unsigned tokens are refused on upgrade
+	rejected := []string{
+		SignSessionToken([]byte("fedcba9876543210fedcba9876543210"), "player-1", expiresAt),
+		SignSessionToken(testSessionSecret, "player-1", time.Now().Add(-time.Minute)),
+		"player-1",
+	}
+	for _, token := range rejected {
+		_, resp, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode)+"?token="+token, nil)
+		if err == nil {
+			t.Fatalf("expected the upgrade with %q to be refused", token)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected 401 for %q, got %v", token, resp)
+		}
+	}
+
+	// A signed token on upgrade only authenticates the player it was issued to
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode) + "?token=" + SignSessionToken(testSessionSecret, "player-1", expiresAt))
+	if err != nil {
+		t.Fatalf("expected a signed token to be accepted: %v", err)
+	}
+	defer client.Close()
+	if err := client.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := client.ExpectError(ErrCodeAuthFailed, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_FAILED authenticating as another player: %v", err)
+	}
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("expected authenticated: %v", err)
+	}
+
+	// Without one on upgrade, authenticate's session_token must be signed for the player
+	other, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("expected an upgrade without a token to be allowed: %v", err)
+	}
+	defer other.Close()
+	if err := other.SendAuthWithSessionToken("player-2", lobbyCode, SignSessionToken(testSessionSecret, "player-1", expiresAt)); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := other.ExpectError(ErrCodeAuthFailed, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_FAILED for another player's token: %v", err)
+	}
+	if err := other.SendAuthWithSessionToken("player-2", lobbyCode, SignSessionToken(testSessionSecret, "player-2", expiresAt)); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := other.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("expected authenticated: %v", err)
+	}
+}
+
+func TestWS_ConnectionConfig_MaxMessageSize(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	config := DefaultConnectionConfig()
+	config.MaxMessageSize = 256
+	if err := ts.Hub.SetConnectionConfig(config); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+
+	_, client := connectHost(t, ts)
+	defer client.Close()
+
+	// A message over the configured size ends the connection
+	if err := client.SendChat(strings.Repeat("a", 300)); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
+	}
+	if err := client.ExpectClose(websocket.CloseMessageTooBig, testTimeout); err != nil {
+		t.Fatalf("expected the connection to close: %v", err)
+	}
+}
+
+// ========================================
+// Lobby Update Coalescing Tests
+// ========================================
+
+// connectHost creates a lobby and connects its host, draining the messages sent on connecting
+func connectHost(t *testing.T, ts *TestServer) (string, *TestClient) {
+	t.Helper()
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := client.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client.AssertLobbyUpdated(testTimeout); err != nil {
+		t.Fatalf("expected lobby state: %v", err)
+	}
+	client.Drain()
+	return lobbyCode, client
+}
+
+func TestWS_LobbyUpdates_Coalesced(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetLobbyUpdateWindow(50 * time.Millisecond)
+
+	lobbyCode, client := connectHost(t, ts)
+	defer client.Close()
+
+	for _, id := range []string{"spectator-1", "spectator-2", "spectator-3"} {
+		ts.Handler.BroadcastSpectatorJoined(lobbyCode, id, id)
+	}
+
+	// The first change goes out at once, and the two after it together once the window closes
+	first, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("expected the first update: %v", err)
+	}
+	if len(first.EarlierEvents) != 0 {
+		t.Errorf("expected the first update on its own, got %d earlier events", len(first.EarlierEvents))
+	}
+
+	coalesced, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("expected the coalesced update: %v", err)
+	}
+	var latest SpectatorJoinedEventData
+	json.Unmarshal(coalesced.EventData, &latest)
+	if latest.SpectatorID != "spectator-3" {
+		t.Errorf("expected the latest change to be the update's event, got %+v", latest)
+	}
+	if len(coalesced.EarlierEvents) != 1 {
+		t.Fatalf("expected 1 earlier event, got %d", len(coalesced.EarlierEvents))
+	}
+	var earlier SpectatorJoinedEventData
+	json.Unmarshal(coalesced.EarlierEvents[0].EventData, &earlier)
+	if coalesced.EarlierEvents[0].Event != LobbyEventSpectatorJoined || earlier.SpectatorID != "spectator-2" {
+		t.Errorf("expected spectator-2 joining as the earlier event, got %+v", coalesced.EarlierEvents[0])
+	}
+
+	if _, err := client.ReceiveType(TypeLobbyUpdated, 150*time.Millisecond); err == nil {
+		t.Error("expected no further updates")
+	}
+}
+
+func TestWS_LobbyUpdates_FlushedBeforeOtherMessages(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetLobbyUpdateWindow(time.Hour)
+
+	lobbyCode, client := connectHost(t, ts)
+	defer client.Close()
+
+	ts.Handler.BroadcastSpectatorJoined(lobbyCode, "spectator-1", "Spectator1")
+	ts.Handler.BroadcastSpectatorJoined(lobbyCode, "spectator-2", "Spectator2")
+	ts.Handler.BroadcastGameStarting(lobbyCode, 3)
+
+	// The held back change is sent ahead of the message that would otherwise overtake it
+	want := []MessageType{TypeLobbyUpdated, TypeLobbyUpdated, TypeGameStarting}
+	for i, msgType := range want {
+		env, err := client.Receive(testTimeout)
+		if err != nil {
+			t.Fatalf("expected message %d: %v", i, err)
+		}
+		if env.Type != msgType {
+			t.Fatalf("expected message %d to be %s, got %s", i, msgType, env.Type)
+		}
+	}
+}
+
+func TestWS_CloseCode_Replaced(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	first, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer first.Close()
+	if err := first.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	auth, err := first.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	// Taking over the session with the reconnect token closes the old connection for good
+	second, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer second.Close()
+	if err := second.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, ReconnectToken: auth.ReconnectToken}); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if err := first.ExpectClose(CloseCodeReplaced, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+// ========================================
+// Shutdown Tests
+// ========================================
+
+func TestWS_Shutdown(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Hub.SetShutdownReconnectAfter(3 * time.Second)
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	clients := make([]*TestClient, 0, 2)
+	for _, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer client.Close()
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth failed: %v", err)
+		}
+		clients = append(clients, client)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := ts.Hub.Shutdown(ctx); err != nil {
+		t.Fatalf("expected every connection to drain, got %v", err)
+	}
+	if ts.Hub.ConnectionCount() != 0 {
+		t.Errorf("expected no connections left, got %d", ts.Hub.ConnectionCount())
+	}
+
+	for _, client := range clients {
+		env, err := client.ReceiveType(TypeServerShutdown, testTimeout)
+		if err != nil {
+			t.Fatalf("expected server_shutdown: %v", err)
+		}
+		var payload ServerShutdownPayload
+		env.ParsePayload(&payload)
+		if payload.ReconnectAfterMs != 3000 {
+			t.Errorf("expected a reconnect hint of 3000ms, got %d", payload.ReconnectAfterMs)
+		}
+		if err := client.ExpectClose(websocket.CloseGoingAway, testTimeout); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// New connections are refused from now on
+	_, resp, err := websocket.DefaultDialer.Dial(ts.WebSocketURL(lobbyCode), nil)
+	if err == nil {
+		t.Fatal("expected a connection during shutdown to be refused")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %v", resp)
+	}
+}
+
+func TestWS_Metrics(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetLobbyUpdateWindow(0)
+
+	lobbyCode, client1 := connectHost(t, ts)
+	defer client1.Close()
+	if err := client1.SendHeartbeat(); err != nil {
+		t.Fatalf("failed to send heartbeat: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeHeartbeatAck, testTimeout); err != nil {
+		t.Fatalf("failed to receive heartbeat_ack: %v", err)
+	}
+	if err := ts.Hub.BroadcastToLobby(lobbyCode, TypeChatMessage, ChatMessagePayload{Text: "hi"}); err != nil {
+		t.Fatalf("failed to broadcast: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeChatMessage, testTimeout); err != nil {
+		t.Fatalf("failed to receive chat_message: %v", err)
+	}
+
+	resp, err := http.Get(ts.Server.URL + "/api/v1/ws/metrics")
+	if err != nil {
+		t.Fatalf("failed to get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	var metrics MetricsSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		t.Fatalf("failed to decode metrics: %v", err)
+	}
+
+	if metrics.MessagesReceived[TypeAuthenticate] != 1 || metrics.MessagesReceived[TypeHeartbeat] != 1 {
+		t.Errorf("expected an authenticate and a heartbeat received, got %v", metrics.MessagesReceived)
+	}
+	if metrics.MessagesSent[TypeAuthenticated] != 1 || metrics.MessagesSent[TypeHeartbeatAck] != 1 || metrics.MessagesSent[TypeChatMessage] != 1 {
+		t.Errorf("expected authenticated, heartbeat_ack and chat_message sent, got %v", metrics.MessagesSent)
+	}
+	if metrics.BroadcastFanOut.Count == 0 || metrics.BroadcastLatencyMs.Count != metrics.BroadcastFanOut.Count {
+		t.Errorf("expected every broadcast measured, got fan-out %+v and latency %+v", metrics.BroadcastFanOut, metrics.BroadcastLatencyMs)
+	}
+	if metrics.SendBufferMessages.Count < 3 {
+		t.Errorf("expected buffer occupancy sampled on every send, got %+v", metrics.SendBufferMessages)
+	}
+
+	// Writes are measured once they reach the socket
+	deadline := time.Now().Add(testTimeout)
+	for ts.Hub.Metrics().WriteLatencyMs.Count < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the writes measured, got %+v", ts.Hub.Metrics().WriteLatencyMs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWS_Connections(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client := connectHost(t, ts)
+	defer client.Close()
+
+	// Errors tell the client its connection ID
+	if err := client.SendSubmitPick(""); err != nil {
+		t.Fatalf("failed to send pick: %v", err)
+	}
+	env, err := client.ReceiveType(TypeError, testTimeout)
+	if err != nil {
+		t.Fatalf("expected an error: %v", err)
+	}
+	var errPayload ErrorPayload
+	env.ParsePayload(&errPayload)
+	var details struct {
+		ConnectionID string           `json:"connection_id"`
+		Fields       []FieldErrorInfo `json:"fields"`
+	}
+	if err := json.Unmarshal(errPayload.Details, &details); err != nil || details.ConnectionID == "" || len(details.Fields) != 1 {
+		t.Fatalf("expected the connection ID alongside the invalid fields, got %s", errPayload.Details)
+	}
+
+	// Only operators can list connections
+	resp, err := http.Get(ts.Server.URL + "/api/v1/ws/connections")
+	if err != nil {
+		t.Fatalf("failed to list connections: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the admin token, got %d", resp.StatusCode)
+	}
+
+	// Which finds the connection, without saying who the player is or where they connect from
+	resp, err = adminGet(ts.Server.URL + "/api/v1/ws/connections?player_id=player-1")
+	if err != nil {
+		t.Fatalf("failed to list connections: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read connections: %v", err)
+	}
+	if strings.Contains(string(body), "player-1") || strings.Contains(string(body), "127.0.0.1") {
+		t.Errorf("expected no player ID or address in the connections, got %s", body)
+	}
+	var list struct {
+		Connections []ConnectionInfo `json:"connections"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		t.Fatalf("failed to decode connections: %v", err)
+	}
+	if len(list.Connections) != 1 || list.Connections[0].ID != details.ConnectionID {
+		t.Fatalf("expected the player's connection %s, got %+v", details.ConnectionID, list.Connections)
+	}
+	info := list.Connections[0]
+	if info.State != "active" || info.LobbyCode != lobbyCode || info.ProtocolVersion != ProtocolVersion {
+		t.Errorf("unexpected connection info: %+v", info)
+	}
+
+	resp, err = adminGet(ts.Server.URL + "/api/v1/ws/connections/" + details.ConnectionID)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for the connection, got %d", resp.StatusCode)
+	}
+	resp, err = adminGet(ts.Server.URL + "/api/v1/ws/connections/conn-unknown")
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown connection, got %d", resp.StatusCode)
+	}
+}
+
+// ========================================
+// Reconnection Flow Tests
+// ========================================
+
+func TestWS_Reconnect_ValidToken(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// Connect and authenticate first time
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	authPayload, err := client1.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	reconnectToken := authPayload.ReconnectToken
+	if reconnectToken == "" {
+		t.Fatal("expected reconnect token")
+	}
+
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player not connected")
+	}
+
+	// Close first connection
+	client1.Close()
+	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
+		t.Fatal("player still connected after close")
+	}
+
+	// Reconnect with token
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+	defer client2.Close()
+
+	// Set PlayerID on client2 before sending auth
+	client2.PlayerID = "player-1"
+	client2.LobbyCode = lobbyCode
+
+	// Send auth with reconnect token
+	payload := AuthenticatePayload{
+		PlayerID:       "player-1",
+		LobbyCode:      lobbyCode,
+		ReconnectToken: reconnectToken,
+	}
+	env, _ := NewEnvelope(TypeAuthenticate, payload)
+	env.CorrelationID = "reconnect-auth"
+	if err := client2.Send(env); err != nil {
+		t.Fatalf("failed to send reconnect auth: %v", err)
+	}
+
+	// Should succeed
+	_, err = client2.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("reconnect auth failed: %v", err)
+	}
+
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Error("player should be connected after reconnect")
+	}
+}
+
+func TestWS_Reconnect_InvalidToken(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// Connect and authenticate first time to establish session
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	_, err = client1.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	if !ts.WaitForPlayerConnected("player-1", testTimeout) {
+		t.Fatal("player not connected")
+	}
+
+	// Try to reconnect with invalid token while still connected
+	// This tests the reconnection validation path
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect second client: %v", err)
+	}
+	defer client2.Close()
+
+	// Set PlayerID on client2 before sending auth
+	client2.PlayerID = "player-1"
+	client2.LobbyCode = lobbyCode
+
+	// Send auth with invalid reconnect token - should still work as new auth
+	// (the token is just ignored if invalid, and we proceed with regular auth)
+	payload := AuthenticatePayload{
+		PlayerID:       "player-1",
+		LobbyCode:      lobbyCode,
+		ReconnectToken: "invalid-token-that-does-not-exist",
+	}
+	env, _ := NewEnvelope(TypeAuthenticate, payload)
+	env.CorrelationID = "invalid-reconnect"
+	if err := client2.Send(env); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+
+	// Should still succeed (new session replaces old)
+	_, err = client2.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth should succeed even with invalid reconnect token: %v", err)
+	}
+
+	// Clean up first client
+	client1.Close()
+}
+
+func TestWS_Reconnect_ReplaysMissedMessages(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	// The last message player-1 saw before dropping
+	env, err := client1.ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	lastSeq := env.Seq
+
+	client1.Close()
+	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
+		t.Fatal("player still connected after close")
+	}
+
+	// player-2 readies up while player-1 is away
+	client2.Drain()
+	if err := client2.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	if _, err := client2.AssertLobbyUpdated(testTimeout); err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+
+	reconnected, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+	defer reconnected.Close()
+	if err := reconnected.SendAuthWithLastSeq("player-1", lobbyCode, lastSeq); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	// Missed messages come first, in order and with their original seq, then the authenticated reply
+	var replayed []*Envelope
+	for {
+		env, err := reconnected.Receive(testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		if env.Type == TypeAuthenticated {
+			var auth AuthenticatedPayload
+			env.ParsePayload(&auth)
+			if auth.Replayed != len(replayed) || auth.ReplayIncomplete {
+				t.Errorf("expected %d messages replayed in full, got %+v", len(replayed), auth)
+			}
+			if len(replayed) > 0 && env.Seq <= replayed[len(replayed)-1].Seq {
+				t.Errorf("expected authenticated to follow the replay, got seq %d", env.Seq)
+			}
+			break
+		}
+		replayed = append(replayed, env)
+	}
+
+	sawReady := false
+	for i, env := range replayed {
+		if env.Seq != lastSeq+int64(i)+1 {
+			t.Errorf("expected replayed message %d to have seq %d, got %d", i, lastSeq+int64(i)+1, env.Seq)
+		}
+		if env.Type == TypeLobbyUpdated {
+			var update LobbyUpdatedPayload
+			env.ParsePayload(&update)
+			sawReady = sawReady || hasLobbyEvent(&update, LobbyEventPlayerReadyChanged)
+		}
+	}
+	if !sawReady {
+		t.Error("expected the missed player_ready_changed update to be replayed")
+	}
+}
+
+func TestWS_Reconnect_ReplayIncomplete(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// A client claiming to have seen messages the server holds no session for must resync
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+	if err := client.SendAuthWithLastSeq("player-1", lobbyCode, 5); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	auth, err := client.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	if !auth.ReplayIncomplete || auth.Replayed != 0 {
+		t.Errorf("expected an incomplete replay of nothing, got %+v", auth)
+	}
+}
+
+func TestWS_Reconnect_ReplayByLobbySeq(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	// The last broadcast player-1 saw before dropping
+	env, err := client1.ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	lastLobbySeq := env.LobbySeq
+	if lastLobbySeq == 0 {
+		t.Fatal("expected the broadcast to carry a lobby_seq")
+	}
+
+	client1.Close()
+	if !ts.WaitForPlayerDisconnected("player-1", testTimeout) {
+		t.Fatal("player still connected after close")
+	}
+
+	// player-2 readies up while player-1 is away; the broadcast is numbered after the last one player-1 saw
+	client2.Drain()
+	if err := client2.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	update, err := client2.ReceiveType(TypeLobbyUpdated, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if update.LobbySeq <= lastLobbySeq {
+		t.Fatalf("expected lobby_seq after %d, got %d", lastLobbySeq, update.LobbySeq)
+	}
+
+	// Reconnecting with only the lobby seq replays the lobby's broadcasts after it
+	reconnected, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+	defer reconnected.Close()
+	if err := reconnected.SendAuthWithLastLobbySeq("player-1", lobbyCode, lastLobbySeq); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+
+	var replayed []*Envelope
+	for {
+		env, err := reconnected.Receive(testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		if env.Type == TypeAuthenticated {
+			var auth AuthenticatedPayload
+			env.ParsePayload(&auth)
+			if auth.Replayed != len(replayed) || auth.ReplayIncomplete {
+				t.Errorf("expected %d messages replayed in full, got %+v", len(replayed), auth)
+			}
+			if auth.LobbySeq < update.LobbySeq {
+				t.Errorf("expected the lobby's latest lobby_seq of at least %d, got %d", update.LobbySeq, auth.LobbySeq)
+			}
+			break
+		}
+		replayed = append(replayed, env)
+	}
+
+	sawUpdate := false
+	for i, env := range replayed {
+		if env.LobbySeq <= lastLobbySeq || (i > 0 && env.LobbySeq <= replayed[i-1].LobbySeq) {
+			t.Errorf("expected replayed lobby_seq in order after %d, got %d", lastLobbySeq, env.LobbySeq)
+		}
+		sawUpdate = sawUpdate || env.LobbySeq == update.LobbySeq
+	}
+	if !sawUpdate {
+		t.Errorf("expected the broadcast with lobby_seq %d to be replayed", update.LobbySeq)
+	}
+}
+
+func TestWS_Resync(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client1.Close()
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	connected, err := client1.ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	if err := client2.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	update, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+
+	// Claiming to have missed everything after player_connected gets the update again with a snapshot
+	client1.Drain()
+	if err := client1.SendResyncRequest(connected.LobbySeq); err != nil {
+		t.Fatalf("failed to send resync_request: %v", err)
+	}
+	env, err := client1.ReceiveType(TypeResync, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive resync: %v", err)
+	}
+	if env.CorrelationID != "resync-player-1" {
+		t.Errorf("expected the request's correlation ID, got %q", env.CorrelationID)
+	}
+	var resync ResyncPayload
+	if err := env.ParsePayload(&resync); err != nil {
+		t.Fatalf("failed to parse resync: %v", err)
+	}
+	if resync.Incomplete || resync.LobbySeq < update.LobbySeq || len(resync.Lobby.Players) != 2 {
+		t.Errorf("expected a complete resync with both players as of lobby_seq %d, got %+v", update.LobbySeq, resync)
+	}
+	sawUpdate := false
+	for _, data := range resync.Missed {
+		var missed Envelope
+		if err := json.Unmarshal(data, &missed); err != nil {
+			t.Fatalf("failed to unmarshal missed broadcast: %v", err)
+		}
+		if missed.LobbySeq <= connected.LobbySeq {
+			t.Errorf("expected only broadcasts after lobby_seq %d, got %d", connected.LobbySeq, missed.LobbySeq)
+		}
+		sawUpdate = sawUpdate || (missed.Type == TypeLobbyUpdated && missed.LobbySeq == update.LobbySeq)
+	}
+	if !sawUpdate {
+		t.Errorf("expected the lobby_updated with lobby_seq %d among the missed broadcasts", update.LobbySeq)
+	}
+
+	// A lobby_seq the lobby has not reached means the client is confused and can only go on the snapshot
+	if err := client1.SendResyncRequest(update.LobbySeq + 1000); err != nil {
+		t.Fatalf("failed to send resync_request: %v", err)
+	}
+	env, err = client1.ReceiveType(TypeResync, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive resync: %v", err)
+	}
+	env.ParsePayload(&resync)
+	if !resync.Incomplete || len(resync.Missed) != 0 {
+		t.Errorf("expected an incomplete resync with nothing missed, got %+v", resync)
+	}
+}
+
+func TestWS_Ack_TrimsReplay(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client1.Close()
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	connected, err := client1.ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	session := ts.Hub.sessions.get("player-1")
+	if messages, complete := session.since(0); !complete || len(messages) == 0 {
+		t.Fatalf("expected the session to keep what it sent, got %d messages", len(messages))
+	}
+
+	// Acking the broadcast drops it and everything before it from the replay buffer
+	if err := client1.SendAck(connected.LobbySeq); err != nil {
+		t.Fatalf("failed to send ack: %v", err)
+	}
+	deadline := time.Now().Add(testTimeout)
+	for {
+		messages, _ := session.since(0)
+		if seqs := seqsOf(t, messages); len(seqs) == 0 || seqs[0] > connected.Seq {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the messages up to seq %d to be dropped, kept %v", connected.Seq, seqsOf(t, messages))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, complete := session.since(connected.Seq); !complete {
+		t.Error("expected replay after the acked broadcast to still be complete")
+	}
+}
+
+// ========================================
+// Battle Action Tests
+// ========================================
+
+func TestWS_Battle_BothActionsResolveTurn(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeTurnResult, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive turn_result: %v", client.PlayerID, err)
+		}
+
+		var result TurnResultPayload
+		if err := env.ParsePayload(&result); err != nil {
+			t.Fatalf("failed to parse turn_result: %v", err)
+		}
+		if result.TurnNumber != 1 {
+			t.Errorf("expected turn 1, got %d", result.TurnNumber)
+		}
+		if len(result.Events) == 0 {
+			t.Error("expected turn events")
+		}
+		if result.ResultingState.TurnNumber != 2 {
+			t.Errorf("expected resulting state at turn 2, got %d", result.ResultingState.TurnNumber)
+		}
+		if result.ResultingState.PlayerState.PlayerID != client.PlayerID {
+			t.Errorf("expected player state for %s, got %s", client.PlayerID, result.ResultingState.PlayerState.PlayerID)
+		}
+		if len(result.ResultingState.OpponentState.Team) != 0 {
+			t.Error("expected opponent team details to be hidden")
+		}
+	}
+}
+
+func TestWS_Battle_TurnMismatchRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	for _, turn := range []int{0, 2} {
+		if err := client1.SendAttack(turn, "razor-leaf"); err != nil {
+			t.Fatalf("failed to send attack: %v", err)
+		}
+		if err := client1.ExpectError(ErrCodeTurnMismatch, testTimeout); err != nil {
+			t.Fatalf("expected TURN_MISMATCH for turn %d: %v", turn, err)
+		}
+	}
+
+	// Neither rejected action was registered, so turn 1 is still open
+	if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeActionAcknowledged, testTimeout); err != nil {
+		t.Fatalf("expected the turn 1 action to be acknowledged: %v", err)
+	}
+}
+
+func TestWS_Battle_ResubmittedActionIsIdempotent(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+			t.Fatalf("failed to send attack: %v", err)
+		}
+		env, err := client1.ReceiveType(TypeActionAcknowledged, testTimeout)
+		if err != nil {
+			t.Fatalf("expected submission %d to be acknowledged: %v", i+1, err)
+		}
+		var ack ActionAcknowledgedPayload
+		env.ParsePayload(&ack)
+		if ack.TurnNumber != 1 {
+			t.Errorf("expected acknowledgement for turn 1, got %d", ack.TurnNumber)
+		}
+	}
+
+	if err := client1.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected a different action to be rejected: %v", err)
+	}
+
+	client2.SendAttack(1, "swords-dance")
+	if _, err := client1.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Fatalf("player-1 failed to receive turn_result: %v", err)
+	}
+}
+
+func TestWS_Battle_OpponentToldOfCommittedAction(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeActionAcknowledged, testTimeout); err != nil {
+		t.Fatalf("expected the action to be acknowledged: %v", err)
+	}
+	env, err := client2.ReceiveType(TypeOpponentCommitted, testTimeout)
+	if err != nil {
+		t.Fatalf("expected player-2 to be told player-1 committed: %v", err)
+	}
+	var committed OpponentCommittedPayload
+	env.ParsePayload(&committed)
+	if committed.TurnNumber != 1 {
+		t.Errorf("expected turn 1, got %d", committed.TurnNumber)
+	}
+
+	// A resubmission is not news to the opponent
+	client1.SendAttack(1, "razor-leaf")
+	if _, err := client2.ReceiveType(TypeOpponentCommitted, 100*time.Millisecond); err == nil {
+		t.Error("expected no second opponent_committed for a resubmitted action")
+	}
+
+	// The action that completes the turn is answered by the turn result instead
+	client2.SendAttack(1, "swords-dance")
+	if _, err := client2.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Fatalf("player-2 failed to receive turn_result: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeOpponentCommitted, 100*time.Millisecond); err == nil {
+		t.Error("expected no opponent_committed once the turn resolved")
+	}
+}
+
+func TestWS_Battle_MoveWithoutPPRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	battle.Sides[0].Active().PP["razor-leaf"] = 0
+
+	if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_ACTION: %v", err)
+	}
+}
+
+func TestWS_Battle_RejectedActionAudited(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAttack(1, "hyper-beam"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_ACTION: %v", err)
+	}
+
+	rejected := ts.AuditTrail.ByPlayer("player-1")
+	if len(rejected) != 1 || rejected[0].LobbyCode != lobbyCode || rejected[0].Action.MoveID != "hyper-beam" {
+		t.Errorf("expected the unknown move in the audit trail, got %+v", rejected)
+	}
+}
+
+func TestWS_Battle_StruggleWhenAllPPExhausted(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	active := battle.Sides[0].Active()
+	for moveID := range active.PP {
+		active.PP[moveID] = 0
+	}
+
+	if err := client1.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	env, err := client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	if err := env.ParsePayload(&result); err != nil {
+		t.Fatalf("failed to parse turn_result: %v", err)
+	}
+
+	var struggled, recoiled bool
+	for _, e := range result.Events {
+		if e.Type == TurnEventMoveUsed && e.Actor == "player-1" {
+			var data MoveUsedEventData
+			json.Unmarshal(e.Data, &data)
+			struggled = data.MoveID == "struggle"
+		}
+		if e.Type == TurnEventRecoilDamage && e.Actor == "player-1" {
+			recoiled = true
+		}
+	}
+	if !struggled {
+		t.Error("expected player-1 to be forced to struggle")
+	}
+	if !recoiled {
+		t.Error("expected struggle recoil event")
+	}
+}
+
+func TestWS_Battle_VoluntarySwitch(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAction(1, ActionTypeSwitch, SwitchActionData{CreatureSlot: 2}); err != nil {
+		t.Fatalf("failed to send switch: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	env, err := client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	if err := env.ParsePayload(&result); err != nil {
+		t.Fatalf("failed to parse turn_result: %v", err)
+	}
+	if result.Events[0].Type != TurnEventCreatureSwitched || result.Events[0].Actor != "player-1" {
+		t.Errorf("expected switch to resolve first, got %+v", result.Events[0])
+	}
+	if result.ResultingState.PlayerState.ActiveSlot != 2 {
+		t.Errorf("expected active slot 2, got %d", result.ResultingState.PlayerState.ActiveSlot)
+	}
+}
+
+func TestWS_Battle_EntryHazardsOnSwitch(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	battle.Sides[0].Hazards.StealthRock = true
+
+	if err := client1.SendAction(1, ActionTypeSwitch, SwitchActionData{CreatureSlot: 2}); err != nil {
+		t.Fatalf("failed to send switch: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	env, err := client2.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	if err := env.ParsePayload(&result); err != nil {
+		t.Fatalf("failed to parse turn_result: %v", err)
+	}
+
+	if len(result.Events) < 2 || result.Events[1].Type != TurnEventHazardDamage {
+		t.Fatalf("expected hazard_damage after the switch, got %+v", result.Events)
+	}
+	var data HazardDamageEventData
+	if err := json.Unmarshal(result.Events[1].Data, &data); err != nil {
+		t.Fatalf("failed to parse hazard_damage data: %v", err)
+	}
+	if data.Hazard != string(game.HazardStealthRock) || data.Damage <= 0 {
+		t.Errorf("unexpected hazard_damage data: %+v", data)
+	}
+
+	hazards := result.ResultingState.OpponentState.Hazards
+	if hazards == nil || !hazards.StealthRock {
+		t.Errorf("expected opponent side to show stealth rock, got %+v", hazards)
+	}
+}
+
+func TestWS_Battle_TerrainInGameState(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	battle.Field = game.Field{Terrain: game.TerrainGrassy, TerrainTurns: 3}
+
+	if err := client1.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	env, err := client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	if err := env.ParsePayload(&result); err != nil {
+		t.Fatalf("failed to parse turn_result: %v", err)
+	}
+
+	field := result.ResultingState.Field
+	if field.Terrain != string(game.TerrainGrassy) || field.TerrainTurns != 2 {
+		t.Errorf("expected grassy terrain with 2 turns left, got %+v", field)
+	}
+}
+
+func TestWS_Battle_ItemUsedFromBag(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattleWithRuleset("casual")
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, _ := ts.BattleService.GetLobbyBattle(lobbyCode)
+	battle.Sides[0].Team[1].CurrentHP = 0
+
+	if err := client1.SendItem(1, "revive", 1); err != nil {
+		t.Fatalf("failed to send item: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	env, err := client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	if err := env.ParsePayload(&result); err != nil {
+		t.Fatalf("failed to parse turn_result: %v", err)
+	}
+
+	first := result.Events[0]
+	if first.Type != TurnEventItemUsed || first.Actor != "player-1" {
+		t.Fatalf("expected item_used first, got %+v", first)
+	}
+	var data ItemUsedEventData
+	json.Unmarshal(first.Data, &data)
+	if data.ItemID != "revive" || data.Healed == 0 {
+		t.Errorf("unexpected item_used data: %+v", data)
+	}
+	if result.ResultingState.PlayerState.Bag["revive"] != 0 {
+		t.Errorf("expected revive to be consumed, got bag %v", result.ResultingState.PlayerState.Bag)
+	}
+}
+
+func TestWS_Battle_ItemWithoutEffectRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattleWithRuleset("casual")
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendItem(1, "potion", 0); err != nil {
+		t.Fatalf("failed to send item: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWS_Battle_OpponentSeesOnlyRevealedInformation(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattleWithRuleset("casual")
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, _ := ts.BattleService.GetLobbyBattle(lobbyCode)
+	battle.Sides[0].Team[0].CurrentHP -= 50
+
+	// Turn 1: player-1 switches out its lead, player-2 reveals a move
+	client1.SendAction(1, ActionTypeSwitch, SwitchActionData{CreatureSlot: 1})
+	client2.SendAttack(1, "swords-dance")
+	if _, err := client1.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	if _, err := client2.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+
+	// Turn 2: player-1 uses a potion on its benched lead
+	client1.SendItem(2, "potion", 0)
+	client2.SendAttack(2, "swords-dance")
+	env, err := client2.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	env.ParsePayload(&result)
+
+	opponent := result.ResultingState.OpponentState
+	if opponent.Team != nil || opponent.Bag != nil {
+		t.Errorf("expected the opponent's team and bag to stay hidden, got %+v", opponent)
+	}
+	if len(opponent.Revealed) != 2 {
+		t.Fatalf("expected the lead and the switch-in to be revealed, got %+v", opponent.Revealed)
+	}
+	lead, active := opponent.Revealed[0], opponent.Revealed[1]
+	if lead.Slot != 0 || lead.IsActive || lead.Moves != nil {
+		t.Errorf("expected the benched lead without moves, got %+v", lead)
+	}
+	if active.Slot != 1 || !active.IsActive {
+		t.Errorf("expected the switch-in to be active, got %+v", active)
+	}
+	if opponent.ItemsUsed["potion"] != 1 || len(opponent.ItemsUsed) != 1 {
+		t.Errorf("expected only the potion to be revealed, got %v", opponent.ItemsUsed)
+	}
+
+	env, err = client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	result = TurnResultPayload{}
+	env.ParsePayload(&result)
+	revealed := result.ResultingState.OpponentState.Revealed
+	if len(revealed) != 1 || len(revealed[0].Moves) != 1 || revealed[0].Moves[0].ID != "swords-dance" {
+		t.Errorf("expected player-2's lead with swords-dance revealed, got %+v", revealed)
+	}
+}
+
+func TestWS_Battle_InvalidSwitchRejected(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAction(1, ActionTypeSwitch, SwitchActionData{CreatureSlot: 0}); err != nil {
+		t.Fatalf("failed to send switch: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidAction, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_ACTION: %v", err)
+	}
+}
+
+// faintOpponentLead starts a battle and resolves a turn in which player-2's lead faints
+func faintOpponentLead(t *testing.T, ts *TestServer) (*TestClient, *TestClient) {
+	t.Helper()
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	battle.Sides[1].Active().CurrentHP = 1
+
+	if err := client1.SendAttack(1, "earthquake"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	return client1, client2
+}
+
+func TestWS_Battle_ForcedSwitchOnFaint(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	client1, client2 := faintOpponentLead(t, ts)
+	defer client1.Close()
+	defer client2.Close()
+
+	env, err := client2.ReceiveType(TypeSwitchRequired, testTimeout)
+	if err != nil {
+		t.Fatalf("player-2 failed to receive switch_required: %v", err)
+	}
+	var required SwitchRequiredPayload
+	if err := env.ParsePayload(&required); err != nil {
+		t.Fatalf("failed to parse switch_required: %v", err)
+	}
+	if required.Reason != "fainted" || len(required.AvailableSlots) != 2 {
+		t.Errorf("unexpected switch_required payload: %+v", required)
+	}
+
+	env, err = client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("player-1 failed to receive turn_result: %v", err)
+	}
+	var result TurnResultPayload
+	env.ParsePayload(&result)
+	if result.ResultingState.Phase != GamePhaseSwitchSelection {
+		t.Errorf("expected switch_selection phase, got %s", result.ResultingState.Phase)
+	}
+
+	// Turn progression is paused until the replacement is chosen
+	if err := client1.SendAttack(2, "earthquake"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE while awaiting switch: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeSwitchRequired, 100*time.Millisecond); err == nil {
+		t.Error("expected switch_required to be sent only to the affected player")
+	}
+
+	if err := client2.SendAction(2, ActionTypeSwitch, SwitchActionData{CreatureSlot: 1}); err != nil {
+		t.Fatalf("failed to send switch: %v", err)
+	}
+	env, err = client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("player-1 failed to receive forced switch result: %v", err)
+	}
+	env.ParsePayload(&result)
+	if len(result.Events) != 1 || result.Events[0].Type != TurnEventCreatureSwitched {
+		t.Errorf("expected a single creature_switched event, got %+v", result.Events)
+	}
+	if result.ResultingState.Phase != GamePhaseActionSelection {
+		t.Errorf("expected action_selection phase after replacement, got %s", result.ResultingState.Phase)
+	}
+}
+
+func TestWS_Battle_ForcedSwitchTimesOut(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.switchTimeout = 50 * time.Millisecond
+
+	client1, client2 := faintOpponentLead(t, ts)
+	defer client1.Close()
+	defer client2.Close()
+
+	if _, err := client2.ReceiveType(TypeSwitchRequired, testTimeout); err != nil {
+		t.Fatalf("player-2 failed to receive switch_required: %v", err)
+	}
+
+	// The turn result is followed by the auto-picked switch
+	for {
+		env, err := client2.ReceiveType(TypeTurnResult, testTimeout)
+		if err != nil {
+			t.Fatalf("expected an automatic switch: %v", err)
+		}
+		var result TurnResultPayload
+		env.ParsePayload(&result)
+		if len(result.Events) == 1 && result.Events[0].Type == TurnEventCreatureSwitched {
+			if result.ResultingState.PlayerState.ActiveSlot != 1 {
+				t.Errorf("expected first healthy slot to be picked, got %d", result.ResultingState.PlayerState.ActiveSlot)
+			}
+			return
+		}
+	}
+}
+
+func TestWS_Battle_GameEndedCarriesStats(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	playTurn(t, client1, client2, 1, "earthquake", "swords-dance")
+
+	ended := forfeitGame(t, client2, client1, client2)
+	stats1, stats2 := ended.Stats["player-1"], ended.Stats["player-2"]
+	if stats1.DamageDealt == 0 || stats1.DamageDealt != stats2.DamageTaken {
+		t.Errorf("expected player-1's damage to be taken by player-2, got %+v and %+v", stats1, stats2)
+	}
+	if stats2.DamageDealt != 0 {
+		t.Errorf("expected no damage from a status move, got %d", stats2.DamageDealt)
+	}
+	if stats1.MostUsedMove != "earthquake" || stats2.MostUsedMove != "swords-dance" {
+		t.Errorf("unexpected most used moves: %q and %q", stats1.MostUsedMove, stats2.MostUsedMove)
+	}
+	if stats1.TurnsSurvived != 1 || stats2.TurnsSurvived != 1 {
+		t.Errorf("expected both players to survive turn 1, got %d and %d", stats1.TurnsSurvived, stats2.TurnsSurvived)
+	}
+	if ended.DataVersion != game.CurrentDataVersion {
+		t.Errorf("expected game_ended to carry data version %q, got %q", game.CurrentDataVersion, ended.DataVersion)
+	}
+}
+
+func TestWS_Battle_Forfeit(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendAction(1, ActionTypeForfeit, struct{}{}); err != nil {
+		t.Fatalf("failed to send forfeit: %v", err)
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeGameEnded, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_ended: %v", client.PlayerID, err)
+		}
+		var ended GameEndedPayload
+		if err := env.ParsePayload(&ended); err != nil {
+			t.Fatalf("failed to parse game_ended: %v", err)
+		}
+		if ended.Reason != GameEndReasonForfeit || ended.LoserID != "player-1" || ended.WinnerID != "player-2" {
+			t.Errorf("unexpected game_ended payload: %+v", ended)
+		}
+		if ended.FinalState == nil || ended.FinalState.Phase != GamePhaseEnded {
+			t.Errorf("expected final state in ended phase, got %+v", ended.FinalState)
+		}
+		if ended.FinalState.PlayerState.PlayerID != client.PlayerID {
+			t.Errorf("expected final state from %s's perspective", client.PlayerID)
+		}
+		if _, err := ts.ReplayStore.Get(ended.ReplayID); err != nil {
+			t.Errorf("expected game_ended to reference a saved replay, got %v", err)
+		}
+	}
+
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if lobby.GetState() == game.LobbyStateActive {
+		t.Error("expected lobby to leave the active state")
+	}
+	if _, err := ts.BattleService.GetLobbyBattle(lobbyCode); err == nil {
+		t.Error("expected battle state to be cleaned up")
+	}
+
+	if err := client2.SendAttack(2, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE after the game ended: %v", err)
+	}
+}
+
+// ========================================
+// Draw Offer Tests
+// ========================================
+
+func TestWS_Draw_AcceptedEndsGameAsDraw(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeDrawOffered, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive draw_offered: %v", client.PlayerID, err)
+		}
+		var offered DrawOfferedPayload
+		env.ParsePayload(&offered)
+		if offered.PlayerID != "player-1" {
+			t.Errorf("expected the offer from player-1, got %+v", offered)
+		}
+	}
+
+	// The player who offered cannot accept their own offer
+	if err := client1.SendRespondDraw(true); err != nil {
+		t.Fatalf("failed to respond to draw: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE for answering an own offer: %v", err)
+	}
+
+	if err := client2.SendRespondDraw(true); err != nil {
+		t.Fatalf("failed to respond to draw: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeGameEnded, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_ended: %v", client.PlayerID, err)
+		}
+		var ended GameEndedPayload
+		if err := env.ParsePayload(&ended); err != nil {
+			t.Fatalf("failed to parse game_ended: %v", err)
+		}
+		if ended.Reason != GameEndReasonDraw || !ended.Draw || ended.WinnerID != "" || ended.LoserID != "" {
+			t.Errorf("unexpected game_ended payload: %+v", ended)
+		}
+	}
+
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if series := lobby.GetSeries(); series.Games != 1 || len(series.Wins) != 0 {
+		t.Errorf("expected the draw to count as a game without a win, got %+v", series)
+	}
+}
+
+func TestWS_Draw_Declined(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+	if err := client1.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE for a second offer: %v", err)
+	}
+
+	if err := client2.SendRespondDraw(false); err != nil {
+		t.Fatalf("failed to respond to draw: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeDrawDeclined, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive draw_declined: %v", client.PlayerID, err)
+		}
+		var declined DrawDeclinedPayload
+		env.ParsePayload(&declined)
+		if declined.PlayerID != "player-2" {
+			t.Errorf("expected player-2 to decline, got %+v", declined)
+		}
+	}
+
+	// The battle goes on and the declined offer can no longer be accepted
+	if err := client2.SendRespondDraw(true); err != nil {
+		t.Fatalf("failed to respond to draw: %v", err)
+	}
+	if err := client2.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE without an offer: %v", err)
+	}
+}
+
+func TestWS_Draw_AnsweredByCorrelation(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+
+	// The opponent is asked for an answer under a correlation ID of the server's, and replies with it
+	offer, err := client2.ReceiveType(TypeDrawOffered, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive draw_offered: %v", err)
+	}
+	if !strings.HasPrefix(offer.CorrelationID, "srv-") {
+		t.Fatalf("expected draw_offered to be a request from the server, got correlation ID %q", offer.CorrelationID)
+	}
+	reply, err := NewEnvelope(TypeRespondDraw, RespondDrawPayload{Accept: true})
+	if err != nil {
+		t.Fatalf("failed to build reply: %v", err)
+	}
+	reply.CorrelationID = offer.CorrelationID
+	if err := client2.Send(reply); err != nil {
+		t.Fatalf("failed to reply: %v", err)
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeGameEnded, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive game_ended: %v", client.PlayerID, err)
+		}
+	}
+}
+
+func TestWS_Draw_OfferExpires(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetDrawOfferTimeout(100 * time.Millisecond)
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+	offer, err := client2.ReceiveType(TypeDrawOffered, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive draw_offered: %v", err)
+	}
+
+	// With no answer the opponent is told the request timed out and the offer is declined for them
+	env, err := client2.ReceiveType(TypeError, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive the timeout: %v", err)
+	}
+	var timeout ErrorPayload
+	env.ParsePayload(&timeout)
+	if timeout.Code != ErrCodeRequestTimeout || env.CorrelationID != offer.CorrelationID {
+		t.Errorf("expected REQUEST_TIMEOUT for %q, got %s for %q", offer.CorrelationID, timeout.Code, env.CorrelationID)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeDrawDeclined, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive draw_declined: %v", client.PlayerID, err)
+		}
+		var declined DrawDeclinedPayload
+		env.ParsePayload(&declined)
+		if declined.PlayerID != "player-2" {
+			t.Errorf("expected the offer declined for player-2, got %+v", declined)
+		}
+	}
+}
+
+func TestWS_Draw_BotDeclines(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client := startBotBattle(t, ts, "standard", game.DefaultBotDifficulty)
+
+	if err := client.SendOfferDraw(); err != nil {
+		t.Fatalf("failed to offer draw: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeDrawDeclined, testTimeout); err != nil {
+		t.Fatalf("expected the bot to decline: %v", err)
+	}
+	if _, err := ts.BattleService.GetLobbyBattle(lobbyCode); err != nil {
+		t.Errorf("expected the battle to go on, got %v", err)
+	}
+}
+
+// ========================================
+// Pause Tests
+// ========================================
+
+func TestWS_Pause_AgreedAndResumed(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.resumeCountdown = 20 * time.Millisecond
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	if err := client1.SendRequestPause(); err != nil {
+		t.Fatalf("failed to request pause: %v", err)
+	}
+	env, err := client2.ReceiveType(TypePauseRequested, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive pause_requested: %v", err)
+	}
+	var requested PauseRequestedPayload
+	env.ParsePayload(&requested)
+	if requested.PlayerID != "player-1" || requested.BudgetRemainingMs != game.MaxPauseBudget.Milliseconds() {
+		t.Errorf("unexpected pause_requested payload: %+v", requested)
+	}
+
+	if err := client2.SendRespondPause(true); err != nil {
+		t.Fatalf("failed to respond to pause: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeBattlePaused, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive battle_paused: %v", client.PlayerID, err)
+		}
+		var paused BattlePausedPayload
+		env.ParsePayload(&paused)
+		if paused.PlayerID != "player-1" || paused.Reason != string(game.PauseReasonRequested) || paused.ExpiresAt == 0 {
+			t.Errorf("unexpected battle_paused payload: %+v", paused)
+		}
+	}
+
+	// Choices wait until the battle resumes
+	if err := client1.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE while paused: %v", err)
+	}
+
+	if err := client2.SendRequestResume(); err != nil {
+		t.Fatalf("failed to request resume: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeBattleResuming, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive battle_resuming: %v", client.PlayerID, err)
+		}
+		env, err := client.ReceiveType(TypeBattleResumed, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive battle_resumed: %v", client.PlayerID, err)
+		}
+		var resumed BattleResumedPayload
+		env.ParsePayload(&resumed)
+		if resumed.BudgetsRemainingMs["player-1"] >= game.MaxPauseBudget.Milliseconds() {
+			t.Errorf("expected the pause to be charged to player-1, got %+v", resumed)
+		}
+		if resumed.BudgetsRemainingMs["player-2"] != game.MaxPauseBudget.Milliseconds() {
+			t.Errorf("expected player-2's budget untouched, got %+v", resumed)
+		}
+	}
+
+	playTurn(t, client1, client2, 1, "swords-dance", "swords-dance")
+}
+
+func TestWS_Pause_Declined(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	// Only the opponent can answer a pause request, and nothing can be resumed yet
+	if err := client1.SendRequestPause(); err != nil {
+		t.Fatalf("failed to request pause: %v", err)
+	}
+	if err := client1.SendRespondPause(true); err != nil {
+		t.Fatalf("failed to respond to pause: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE answering an own request: %v", err)
+	}
+	if err := client1.SendRequestResume(); err != nil {
+		t.Fatalf("failed to request resume: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE resuming an unpaused battle: %v", err)
+	}
+
+	if err := client2.SendRespondPause(false); err != nil {
+		t.Fatalf("failed to respond to pause: %v", err)
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypePauseDeclined, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive pause_declined: %v", client.PlayerID, err)
+		}
+		var declined PauseDeclinedPayload
+		env.ParsePayload(&declined)
+		if declined.PlayerID != "player-2" {
+			t.Errorf("expected player-2 to decline, got %+v", declined)
+		}
+	}
+
+	playTurn(t, client1, client2, 1, "swords-dance", "swords-dance")
+}
+
+func TestWS_Pause_DisconnectAndReconnect(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.resumeCountdown = 20 * time.Millisecond
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client2.Close()
+
+	client1.Close()
+	env, err := client2.ReceiveType(TypeBattlePaused, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive battle_paused: %v", err)
+	}
+	var paused BattlePausedPayload
+	env.ParsePayload(&paused)
+	if paused.PlayerID != "player-1" || paused.Reason != string(game.PauseReasonDisconnect) {
+		t.Errorf("unexpected battle_paused payload: %+v", paused)
+	}
+
+	// Only the disconnected player's return ends the pause
+	if err := client2.SendRequestResume(); err != nil {
+		t.Fatalf("failed to request resume: %v", err)
+	}
+	if err := client2.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE resuming a disconnect pause: %v", err)
+	}
+
+	reconnected, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+	defer reconnected.Close()
+	if err := reconnected.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	for _, client := range []*TestClient{reconnected, client2} {
+		if _, err := client.ReceiveType(TypeBattleResuming, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive battle_resuming: %v", client.PlayerID, err)
+		}
+		if _, err := client.ReceiveType(TypeBattleResumed, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive battle_resumed: %v", client.PlayerID, err)
+		}
+	}
+
+	playTurn(t, reconnected, client2, 1, "swords-dance", "swords-dance")
+}
+
+func TestWS_Pause_DisconnectGraceExpires(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.SetDisconnectGrace(50 * time.Millisecond)
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client2.Close()
+
+	client1.Close()
+	env, err := client2.ReceiveType(TypeOpponentDisconnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive opponent_disconnected: %v", err)
+	}
+	var disconnected OpponentDisconnectedPayload
+	env.ParsePayload(&disconnected)
+	if disconnected.PlayerID != "player-1" || disconnected.GraceExpiresAt == 0 {
+		t.Errorf("unexpected opponent_disconnected payload: %+v", disconnected)
+	}
+
+	env, err = client2.ReceiveType(TypeGameEnded, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_ended: %v", err)
+	}
+	var ended GameEndedPayload
+	env.ParsePayload(&ended)
+	if ended.WinnerID != "player-2" || ended.LoserID != "player-1" || ended.Reason != GameEndReasonOpponentDisconnect {
+		t.Errorf("expected player-2 to win by opponent_disconnect, got %+v", ended)
+	}
+}
+
+func TestWS_Pause_ReconnectWithinGrace(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.Handler.resumeCountdown = 20 * time.Millisecond
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client2.Close()
+
 	client1.Close()
+	if _, err := client2.ReceiveType(TypeOpponentDisconnected, testTimeout); err != nil {
+		t.Fatalf("failed to receive opponent_disconnected: %v", err)
+	}
+
+	reconnected, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+	defer reconnected.Close()
+	if err := reconnected.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.ReceiveType(TypeBattleResumed, testTimeout); err != nil {
+		t.Fatalf("failed to receive battle_resumed: %v", err)
+	}
+
+	ts.Handler.timersMu.Lock()
+	_, pending := ts.Handler.disconnectTimers["player-1"]
+	ts.Handler.timersMu.Unlock()
+	if pending {
+		t.Error("expected the grace period to end when the player returned")
+	}
+}
+
+func TestWS_Pause_BotAccepts(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := startBotBattle(t, ts, "standard", game.DefaultBotDifficulty)
+
+	if err := client.SendRequestPause(); err != nil {
+		t.Fatalf("failed to request pause: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeBattlePaused, testTimeout); err != nil {
+		t.Fatalf("expected the bot to accept: %v", err)
+	}
+}
+
+// ========================================
+// Delta Update Tests
+// ========================================
+
+// startDeltaBattle starts a battle in which player-1 receives delta updates and player-2 full states.
+// Both clients are drained once the game has started.
+func startDeltaBattle(t *testing.T, ts *TestServer) (*TestClient, *TestClient) {
+	t.Helper()
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		clients[i] = client
+
+		if i == 0 {
+			err = client.SendAuthWithDeltaUpdates(playerID, lobbyCode)
+		} else {
+			err = client.SendAuth(playerID, lobbyCode)
+		}
+		if err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			t.Fatalf("auth: %v", err)
+		}
+	}
+
+	for _, client := range clients {
+		client.SendSubmitTeam(starterTeamPayload())
+		client.SendReady(true)
+	}
+	for _, client := range clients {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("failed to receive game_started: %v", err)
+		}
+		client.Drain()
+	}
+	return clients[0], clients[1]
+}
+
+// playTurn submits an attack for each player and returns player-1's turn_result
+func playTurn(t *testing.T, client1, client2 *TestClient, turn int, move1, move2 string) TurnResultPayload {
+	t.Helper()
+	client1.SendAttack(turn, move1)
+	client2.SendAttack(turn, move2)
+
+	env, err := client1.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result for turn %d: %v", turn, err)
+	}
+	var result TurnResultPayload
+	env.ParsePayload(&result)
+	return result
+}
+
+// receiveDelta waits for the next game_state_delta
+func receiveDelta(t *testing.T, client *TestClient) GameStateDeltaPayload {
+	t.Helper()
+	env, err := client.ReceiveType(TypeGameStateDelta, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_state_delta: %v", err)
+	}
+	var delta GameStateDeltaPayload
+	env.ParsePayload(&delta)
+	return delta
+}
+
+func TestWS_Delta_TurnResultCarriesOnlyChanges(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	client1, client2 := startDeltaBattle(t, ts)
+
+	// The first state after connecting is complete
+	first := playTurn(t, client1, client2, 1, "swords-dance", "swords-dance")
+	if first.ResultingState == nil || first.ResultingState.Revision != 1 {
+		t.Fatalf("expected a complete state at revision 1, got %+v", first.ResultingState)
+	}
+
+	second := playTurn(t, client1, client2, 2, "earthquake", "swords-dance")
+	if second.ResultingState != nil {
+		t.Error("expected the turn result to omit the resulting state for a delta client")
+	}
+	if len(second.Events) == 0 {
+		t.Error("expected the turn events to be sent to the delta client")
+	}
+
+	delta := receiveDelta(t, client1)
+	if delta.BaseRevision != 1 || delta.Revision != 2 || delta.TurnNumber != 3 || delta.Phase != GamePhaseActionSelection {
+		t.Errorf("unexpected delta header: %+v", delta)
+	}
+	if delta.Player == nil || len(delta.Player.Creatures) != 1 {
+		t.Fatalf("expected one own creature to change, got %+v", delta.Player)
+	}
+	lead := delta.Player.Creatures[0]
+	if lead.Slot != 0 || lead.CurrentHP != nil || lead.PP["earthquake"] != first.ResultingState.PlayerState.Team[0].Moves[3].PP-1 {
+		t.Errorf("expected only earthquake's PP to change, got %+v", lead)
+	}
+	if delta.Opponent == nil || delta.Opponent.ActiveHP == nil || delta.Opponent.ActiveSlot != nil {
+		t.Errorf("expected the opposing active's HP to change, got %+v", delta.Opponent)
+	}
+	if delta.Field != nil {
+		t.Errorf("expected the field to be unchanged, got %+v", delta.Field)
+	}
+
+	// Clients without delta updates keep receiving complete states
+	env, err := client2.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive turn_result: %v", err)
+	}
+	var full TurnResultPayload
+	env.ParsePayload(&full)
+	if full.TurnNumber != 1 || full.ResultingState == nil {
+		t.Errorf("expected a complete state for player-2, got %+v", full)
+	}
+}
+
+func TestWS_Delta_RequestGameStateResynchronises(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	client1, client2 := startDeltaBattle(t, ts)
+
+	if err := client1.SendRequestGameState(); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	env, err := client1.ReceiveType(TypeGameState, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_state: %v", err)
+	}
+	var state GameStatePayload
+	env.ParsePayload(&state)
+	if state.Revision != 1 {
+		t.Fatalf("expected revision 1, got %d", state.Revision)
+	}
+
+	playTurn(t, client1, client2, 1, "swords-dance", "swords-dance")
+	if delta := receiveDelta(t, client1); delta.BaseRevision != 1 || delta.Revision != 2 {
+		t.Errorf("expected the delta to build on the requested state, got %+v", delta)
+	}
+}
+
+// ========================================
+// Series Tests
+// ========================================
+
+// forfeitGame has the loser forfeit and returns the game_ended payload both clients receive
+func forfeitGame(t *testing.T, loser *TestClient, clients ...*TestClient) GameEndedPayload {
+	t.Helper()
+	if err := loser.SendForfeit(1); err != nil {
+		t.Fatalf("failed to send forfeit: %v", err)
+	}
+
+	var ended GameEndedPayload
+	for _, client := range clients {
+		env, err := client.ReceiveType(TypeGameEnded, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_ended: %v", client.PlayerID, err)
+		}
+		if err := env.ParsePayload(&ended); err != nil {
+			t.Fatalf("failed to parse game_ended: %v", err)
+		}
+	}
+	return ended
+}
+
+// rematch has both clients request a rematch and returns the rematch_starting payload
+func rematch(t *testing.T, clients ...*TestClient) RematchStartingPayload {
+	t.Helper()
+	for _, client := range clients {
+		if err := client.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+
+	var starting RematchStartingPayload
+	for _, client := range clients {
+		env, err := client.ReceiveType(TypeRematchStarting, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive rematch_starting: %v", client.PlayerID, err)
+		}
+		if err := env.ParsePayload(&starting); err != nil {
+			t.Fatalf("failed to parse rematch_starting: %v", err)
+		}
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive game_started: %v", client.PlayerID, err)
+		}
+		client.Drain()
+	}
+	return starting
+}
+
+func TestWS_Series_BestOfThree(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartSeriesBattle(3)
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	ended := forfeitGame(t, client1, client1, client2)
+	if ended.Series == nil || ended.Series.GamesPlayed != 1 || ended.Series.Wins["player-2"] != 1 {
+		t.Fatalf("expected game_ended to carry a 0-1 series score, got %+v", ended.Series)
+	}
+	if _, err := client1.ReceiveType(TypeSeriesEnded, 200*time.Millisecond); err == nil {
+		t.Fatal("expected no series_ended before the series is decided")
+	}
+
+	starting := rematch(t, client1, client2)
+	if starting.Series == nil || starting.Series.BestOf != 3 || starting.Series.Wins["player-2"] != 1 {
+		t.Errorf("expected rematch_starting to carry the series score, got %+v", starting.Series)
+	}
+
+	forfeitGame(t, client1, client1, client2)
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeSeriesEnded, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive series_ended: %v", client.PlayerID, err)
+		}
+		var seriesEnded SeriesEndedPayload
+		if err := env.ParsePayload(&seriesEnded); err != nil {
+			t.Fatalf("failed to parse series_ended: %v", err)
+		}
+		if seriesEnded.WinnerID != "player-2" || seriesEnded.LoserID != "player-1" || seriesEnded.Series.Wins["player-2"] != 2 {
+			t.Errorf("unexpected series_ended payload: %+v", seriesEnded)
+		}
+	}
+}
+
+func TestWS_Series_SingleGameHasNoSeriesEvents(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	ended := forfeitGame(t, client1, client1, client2)
+	if ended.Series != nil {
+		t.Errorf("expected no series score for a single game, got %+v", ended.Series)
+	}
+	if _, err := client1.ReceiveType(TypeSeriesEnded, 200*time.Millisecond); err == nil {
+		t.Error("expected no series_ended for a single game")
+	}
+
+	starting := rematch(t, client1, client2)
+	if starting.Series != nil {
+		t.Errorf("expected no series score in rematch_starting, got %+v", starting.Series)
+	}
+}
+
+func TestWS_Series_RematchWaitsForBothPlayers(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartSeriesBattle(3)
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	forfeitGame(t, client1, client1, client2)
+
+	if err := client1.SendRequestRematch(); err != nil {
+		t.Fatalf("failed to request rematch: %v", err)
+	}
+	env, err := client2.ReceiveType(TypeRematchRequested, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive rematch_requested: %v", err)
+	}
+	var requested RematchRequestedPayload
+	env.ParsePayload(&requested)
+	if requested.PlayerID != "player-1" {
+		t.Errorf("expected rematch request from player-1, got %q", requested.PlayerID)
+	}
+	if _, err := client1.ReceiveType(TypeRematchStarting, 200*time.Millisecond); err == nil {
+		t.Error("expected rematch to wait for the second player")
+	}
+}
+
+func TestWS_Rematch_NewGameID(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	first, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	forfeitGame(t, client1, client1, client2)
+
+	for _, client := range []*TestClient{client1, client2} {
+		if err := client.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+	env, err := client1.ReceiveType(TypeGameStarted, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_started: %v", err)
+	}
+	var started GameStartedPayload
+	env.ParsePayload(&started)
+
+	second, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	if started.GameID != second.ID || second.ID == first.ID || second.ID == lobbyCode {
+		t.Errorf("expected game_started to carry the rematch's own game ID, got %q (first game %q)", started.GameID, first.ID)
+	}
+
+	// An action addressed to the finished game does not reach the rematch
+	client1.Drain()
+	if err := client1.SendGameAction(first.ID, 1, ActionTypeAttack, AttackActionData{MoveID: "razor-leaf"}); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE for the finished game: %v", err)
+	}
+	if err := client1.SendGameAction(second.ID, 1, ActionTypeAttack, AttackActionData{MoveID: "razor-leaf"}); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "razor-leaf"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if _, err := client1.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+		t.Errorf("expected the action for the rematch to be accepted: %v", err)
+	}
+}
+
+func TestWS_Rematch_GameStartedCarriesRulesetTypeChart(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattleWithRuleset("inverse")
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	forfeitGame(t, client1, client1, client2)
+	for _, client := range []*TestClient{client1, client2} {
+		if err := client.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+	env, err := client1.ReceiveType(TypeGameStarted, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_started: %v", err)
+	}
+	var started GameStartedPayload
+	env.ParsePayload(&started)
+
+	if started.DataVersion != game.CurrentDataVersion {
+		t.Errorf("expected data version %q, got %q", game.CurrentDataVersion, started.DataVersion)
+	}
+	if started.TypeChart["normal"]["ghost"] != 2 || started.TypeChart["fire"]["grass"] != 0.5 {
+		t.Errorf("expected game_started to carry the inverse type chart, got %v", started.TypeChart)
+	}
+}
+
+func TestWS_Rematch_SeedCommitmentRevealedInGameEnded(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	forfeitGame(t, client1, client1, client2)
+	for _, client := range []*TestClient{client1, client2} {
+		if err := client.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+	env, err := client1.ReceiveType(TypeGameStarted, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive game_started: %v", err)
+	}
+	var started GameStartedPayload
+	env.ParsePayload(&started)
+	if started.SeedCommitment == "" {
+		t.Fatal("expected game_started to carry a seed commitment")
+	}
+
+	ended := forfeitGame(t, client1, client1, client2)
+	if !game.VerifySeedCommitment(started.SeedCommitment, ended.Seed, ended.SeedSalt) {
+		t.Errorf("expected game_ended to reveal the committed seed, got seed %d and salt %q", ended.Seed, ended.SeedSalt)
+	}
+}
+
+func TestWS_Rematch_RejectsDuplicateRequest(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	forfeitGame(t, client1, client1, client2)
+
+	for i := 0; i < 2; i++ {
+		if err := client1.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+	if err := client1.ExpectError(ErrCodeInvalidState, testTimeout); err != nil {
+		t.Fatalf("expected INVALID_STATE for a repeated request: %v", err)
+	}
+}
+
+func TestWS_Rematch_NewTeams(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	forfeitGame(t, client1, client1, client2)
+	if _, err := ts.LobbyService.SetRematchTeams(lobbyCode, "player-1", game.RematchTeamsNew); err != nil {
+		t.Fatalf("failed to set rematch teams: %v", err)
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		if err := client.SendRequestRematch(); err != nil {
+			t.Fatalf("failed to request rematch: %v", err)
+		}
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		env, err := client.ReceiveType(TypeRematchStarting, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive rematch_starting: %v", client.PlayerID, err)
+		}
+		var starting RematchStartingPayload
+		env.ParsePayload(&starting)
+		if !starting.NewTeams {
+			t.Errorf("expected rematch_starting to ask for new teams, got %+v", starting)
+		}
+	}
+	if _, err := client1.ReceiveType(TypeGameStarted, 200*time.Millisecond); err == nil {
+		t.Fatal("expected the rematch to wait for new teams")
+	}
+
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if lobby.GetState() != game.LobbyStateReady || lobby.HasTeam("player-1") {
+		t.Fatalf("expected a ready lobby without teams, got %v", lobby.GetState())
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+			t.Fatalf("failed to submit team: %v", err)
+		}
+		if err := client.SendReady(true); err != nil {
+			t.Fatalf("failed to send ready: %v", err)
+		}
+	}
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive game_started: %v", client.PlayerID, err)
+		}
+	}
+}
+
+func TestWS_Battle_VictoryEndsGame(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client1, client2, err := ts.StartBattle()
+	if err != nil {
+		t.Fatalf("failed to start battle: %v", err)
+	}
+	defer client1.Close()
+	defer client2.Close()
+
+	battle, err := ts.BattleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		t.Fatalf("battle not found: %v", err)
+	}
+	for _, c := range battle.Sides[1].Team {
+		c.CurrentHP = 0
+	}
+	battle.Sides[1].Active().CurrentHP = 1
+
+	if err := client1.SendAttack(1, "earthquake"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+	if err := client2.SendAttack(1, "swords-dance"); err != nil {
+		t.Fatalf("failed to send attack: %v", err)
+	}
+
+	for _, client := range []*TestClient{client1, client2} {
+		if _, err := client.ReceiveType(TypeTurnResult, testTimeout); err != nil {
+			t.Fatalf("%s failed to receive turn_result: %v", client.PlayerID, err)
+		}
+		env, err := client.ReceiveType(TypeGameEnded, testTimeout)
+		if err != nil {
+			t.Fatalf("%s failed to receive game_ended: %v", client.PlayerID, err)
+		}
+		var ended GameEndedPayload
+		if err := env.ParsePayload(&ended); err != nil {
+			t.Fatalf("failed to parse game_ended: %v", err)
+		}
+		if ended.Reason != GameEndReasonVictory || ended.WinnerID != "player-1" || ended.LoserID != "player-2" {
+			t.Errorf("unexpected game_ended payload: %+v", ended)
+		}
+	}
+
+	lobby, _ := ts.LobbyService.GetLobby(lobbyCode)
+	if lobby.GetState() != game.LobbyStateFinished {
+		t.Errorf("expected lobby state finished, got %v", lobby.GetState())
+	}
+	if _, err := client2.ReceiveType(TypeSwitchRequired, 100*time.Millisecond); err == nil {
+		t.Error("expected no switch_required once the battle is over")
+	}
+}
+
+// ========================================
+// Spectator View Tests
+// ========================================
+
+// receiveSpectatorState waits for the next spectator_state
+func receiveSpectatorState(t *testing.T, client *TestClient) SpectatorStatePayload {
+	t.Helper()
+	env, err := client.ReceiveType(TypeSpectatorState, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive spectator_state: %v", err)
+	}
+	var state SpectatorStatePayload
+	if err := env.ParsePayload(&state); err != nil {
+		t.Fatalf("failed to parse spectator_state: %v", err)
+	}
+	return state
+}
+
+func TestWS_Spectator_FullView(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	client1, client2 := startDeltaBattle(t, ts)
+
+	spectator, err := NewTestClient(ts.WebSocketURL(client1.LobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer spectator.Close()
+	if err := spectator.SendAuthAsSpectator("watcher-1", "Watcher", client1.LobbyCode); err != nil {
+		t.Fatalf("failed to send auth: %v", err)
+	}
+	if _, err := spectator.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+
+	// A spectator arriving mid-battle is caught up with both teams in full
+	state := receiveSpectatorState(t, spectator)
+	if state.TurnNumber != 1 || len(state.Sides) != 2 {
+		t.Fatalf("expected both sides on turn 1, got %+v", state)
+	}
+	for i, playerID := range []string{"player-1", "player-2"} {
+		if state.Sides[i].PlayerID != playerID || len(state.Sides[i].Team) == 0 {
+			t.Errorf("expected %s's full team, got %+v", playerID, state.Sides[i])
+		}
+	}
+
+	roles := make(map[string]string)
+	for _, info := range ts.Hub.Connections() {
+		roles[info.playerID] = info.Role
+	}
+	if roles["watcher-1"] != string(RoleSpectator) || roles["player-1"] != string(RolePlayer) {
+		t.Errorf("expected watcher-1 tagged spectator and player-1 player, got %v", roles)
+	}
+
+	// Spectators get each turn's events followed by the full view, while players keep their fog of war
+	playTurn(t, client1, client2, 1, "swords-dance", "swords-dance")
+	env, err := spectator.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("spectator failed to receive turn_result: %v", err)
+	}
+	var spectated TurnResultPayload
+	env.ParsePayload(&spectated)
+	if spectated.TurnNumber != 1 || len(spectated.Events) == 0 || spectated.ResultingState != nil {
+		t.Errorf("expected turn 1's events without a player's state, got %+v", spectated)
+	}
+	if state := receiveSpectatorState(t, spectator); state.TurnNumber != 2 || len(state.Sides[1].Team) == 0 {
+		t.Errorf("expected the full view on turn 2, got %+v", state)
+	}
+
+	env, err = client2.ReceiveType(TypeTurnResult, testTimeout)
+	if err != nil {
+		t.Fatalf("player-2 failed to receive turn_result: %v", err)
+	}
+	var played TurnResultPayload
+	env.ParsePayload(&played)
+	if played.ResultingState == nil || len(played.ResultingState.OpponentState.Team) != 0 {
+		t.Errorf("expected player-2 to see only their own team, got %+v", played.ResultingState)
+	}
+	if _, err := client2.ReceiveType(TypeSpectatorState, 100*time.Millisecond); err == nil {
+		t.Error("expected players not to receive spectator_state")
+	}
+}
+
+// ========================================
+// Multiple Device Tests
+// ========================================
+
+// receivePrimaryChanged waits for the next primary_changed
+func receivePrimaryChanged(t *testing.T, client *TestClient) PrimaryChangedPayload {
+	t.Helper()
+	env, err := client.ReceiveType(TypePrimaryChanged, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive primary_changed: %v", err)
+	}
+	var changed PrimaryChangedPayload
+	if err := env.ParsePayload(&changed); err != nil {
+		t.Fatalf("failed to parse primary_changed: %v", err)
+	}
+	return changed
+}
+
+// connectDevice connects another device for the player, as primary unless secondary is set, and drains it
+func connectDevice(t *testing.T, ts *TestServer, playerID, lobbyCode string, secondary bool) (*TestClient, *AuthenticatedPayload) {
+	t.Helper()
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	if secondary {
+		err = client.SendAuthAsSecondary(playerID, lobbyCode)
+	} else {
+		err = client.SendAuth(playerID, lobbyCode)
+	}
+	if err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	auth, err := client.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+	if _, err := client.AssertLobbyUpdated(testTimeout); err != nil {
+		t.Fatalf("expected lobby state: %v", err)
+	}
+	return client, auth
+}
+
+func TestWS_Devices_PrimaryTakeover(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, phone := connectHost(t, ts)
+	defer phone.Close()
+	phoneID := ts.Hub.GetConnectionByPlayerID("player-1").ID()
+
+	// A new device takes over as primary and the old one is told
+	desktop, auth := connectDevice(t, ts, "player-1", lobbyCode, false)
+	if !auth.Primary || auth.ConnectionID == "" || auth.ConnectionID == phoneID {
+		t.Fatalf("expected the desktop to authenticate as primary, got %+v", auth)
+	}
+	changed := receivePrimaryChanged(t, phone)
+	if changed.ConnectionID != auth.ConnectionID || changed.PreviousConnectionID != phoneID || changed.Reason != PrimaryReasonAuthenticated {
+		t.Errorf("unexpected primary_changed: %+v", changed)
+	}
+	phone.Drain()
+	desktop.Drain()
+
+	// Only the primary's actions count, but both devices get what is sent to the player under the same seq
+	phone.SendReady(true)
+	if err := phone.ExpectError(ErrCodeNotPrimary, testTimeout); err != nil {
+		t.Errorf("expected NOT_PRIMARY for the phone: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	var seqs []int64
+	for _, client := range []*TestClient{phone, desktop} {
+		env, err := client.ReceiveType(TypeLobbyUpdated, testTimeout)
+		if err != nil {
+			t.Fatalf("failed to receive lobby_updated: %v", err)
+		}
+		seqs = append(seqs, env.Seq)
+	}
+	if seqs[0] == 0 || seqs[0] != seqs[1] {
+		t.Errorf("expected both devices to get the update under one seq, got %v", seqs)
+	}
+
+	// A secondary device joins without taking over, and the phone can claim primary back
+	tablet, tabletAuth := connectDevice(t, ts, "player-1", lobbyCode, true)
+	if tabletAuth.Primary {
+		t.Error("expected the secondary device not to be primary")
+	}
+	phone.SendClaimPrimary()
+	for _, client := range []*TestClient{phone, desktop, tablet} {
+		if changed := receivePrimaryChanged(t, client); changed.ConnectionID != phoneID || changed.Reason != PrimaryReasonClaimed {
+			t.Errorf("expected the phone to have claimed primary, got %+v", changed)
+		}
+	}
+
+	// When the primary disconnects, the newest device left takes over
+	phone.Close()
+	for _, client := range []*TestClient{desktop, tablet} {
+		changed := receivePrimaryChanged(t, client)
+		if changed.ConnectionID != tabletAuth.ConnectionID || changed.Reason != PrimaryReasonDisconnected {
+			t.Errorf("expected the tablet to take over, got %+v", changed)
+		}
+	}
+	if ts.Hub.GetConnectionByPlayerID("player-1").ID() != tabletAuth.ConnectionID {
+		t.Error("expected the tablet to be the player's primary connection")
+	}
+}
+
+// ========================================
+// Domain Event Tests
+// ========================================
+
+func TestWS_Events_ServiceChangesReachClients(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, client := connectHost(t, ts)
+	defer client.Close()
+
+	// Changes made through the lobby service directly, as HTTP requests make them, are broadcast like any other
+	if _, err := ts.LobbyService.SetRuleset(lobbyCode, "player-1", "competitive"); err != nil {
+		t.Fatalf("failed to set ruleset: %v", err)
+	}
+	update, err := client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventSettingsChanged) {
+		t.Errorf("expected event %q, got %q", LobbyEventSettingsChanged, update.Event)
+	}
+
+	if _, err := ts.LobbyService.AddBot(lobbyCode, "player-1", game.BotDifficultyRandom); err != nil {
+		t.Fatalf("failed to add bot: %v", err)
+	}
+	update, err = client.AssertLobbyUpdated(testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+	if !hasLobbyEvent(update, LobbyEventPlayerJoined) || len(update.Lobby.Players) != 2 {
+		t.Errorf("expected the bot to be announced as joining, got %q with %d players", update.Event, len(update.Lobby.Players))
+	}
+}
+
+// ========================================
+// Time Sync Tests
+// ========================================
+
+func TestWS_TimeSync(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := connectHost(t, ts)
+	defer client.Close()
+
+	// A client whose clock runs five seconds slow gets the server's times to work out the offset
+	before := time.Now().UnixMilli()
+	clientSendTime := before - 5000
+	if err := client.SendTimeSync(clientSendTime); err != nil {
+		t.Fatalf("failed to send time_sync: %v", err)
+	}
+	env, err := client.ReceiveType(TypeTimeSyncResponse, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive time_sync_response: %v", err)
+	}
+	after := time.Now().UnixMilli()
+	if env.CorrelationID != "time-sync-1" {
+		t.Errorf("expected the request's correlation ID, got %q", env.CorrelationID)
+	}
+
+	var payload TimeSyncResponsePayload
+	if err := env.ParsePayload(&payload); err != nil {
+		t.Fatalf("failed to parse payload: %v", err)
+	}
+	if payload.ClientSendTime != clientSendTime {
+		t.Errorf("expected client_send_time %d echoed, got %d", clientSendTime, payload.ClientSendTime)
+	}
+	if payload.ServerReceiveTime < before || payload.ServerReceiveTime > payload.ServerSendTime || payload.ServerSendTime > after {
+		t.Errorf("expected %d <= server_receive_time %d <= server_send_time %d <= %d",
+			before, payload.ServerReceiveTime, payload.ServerSendTime, after)
+	}
+	offset := ((payload.ServerReceiveTime - payload.ClientSendTime) + (payload.ServerSendTime - (after - 5000))) / 2
+	if offset < 4000 || offset > 6000 {
+		t.Errorf("expected an offset of about 5000ms, got %d", offset)
+	}
+
+	// A send time before the epoch is rejected
+	if err := client.SendTimeSync(-1); err != nil {
+		t.Fatalf("failed to send time_sync: %v", err)
+	}
+	fields, err := client.ExpectInvalidFields(testTimeout)
+	if err != nil {
+		t.Fatalf("expected MALFORMED_MESSAGE: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Field != "client_send_time" {
+		t.Errorf("expected client_send_time to be invalid, got %+v", fields)
+	}
+}
+
+func TestWS_TimeSync_RequiresAuth(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.SendTimeSync(time.Now().UnixMilli()); err != nil {
+		t.Fatalf("failed to send time_sync: %v", err)
+	}
+	if err := client.ExpectError(ErrCodeAuthRequired, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_REQUIRED error: %v", err)
+	}
+}
+
+// ========================================
+// Auth Timeout Tests
+// ========================================
+
+func TestWS_AuthTimeout_ClosesPendingConnection(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	config := DefaultConnectionConfig()
+	config.AuthTimeout = 100 * time.Millisecond
+	if err := ts.Hub.SetConnectionConfig(config); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// A client that never authenticates is told why and closed
+	if err := client.ExpectError(ErrCodeAuthRequired, testTimeout); err != nil {
+		t.Fatalf("expected AUTH_REQUIRED error: %v", err)
+	}
+	if err := client.ExpectClose(CloseCodeAuthRequired, testTimeout); err != nil {
+		t.Fatalf("expected the connection to close: %v", err)
+	}
+	if timeouts := ts.Hub.Metrics().AuthTimeouts; timeouts != 1 {
+		t.Errorf("expected 1 auth timeout counted, got %d", timeouts)
+	}
+}
+
+func TestWS_AuthTimeout_KeepsAuthenticatedConnection(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	config := DefaultConnectionConfig()
+	config.AuthTimeout = 100 * time.Millisecond
+	if err := ts.Hub.SetConnectionConfig(config); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+
+	_, client := connectHost(t, ts)
+	defer client.Close()
+
+	// Well past the timeout, the authenticated connection is still served
+	time.Sleep(200 * time.Millisecond)
+	if err := client.SendHeartbeat(); err != nil {
+		t.Fatalf("failed to send heartbeat: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeHeartbeatAck, testTimeout); err != nil {
+		t.Fatalf("expected heartbeat_ack: %v", err)
+	}
+	if timeouts := ts.Hub.Metrics().AuthTimeouts; timeouts != 0 {
+		t.Errorf("expected no auth timeouts counted, got %d", timeouts)
+	}
+}
+
+// ========================================
+// Reconnect Token Rotation Tests
+// ========================================
+
+func TestWS_ReconnectToken_OneTime(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	// connect authenticates a new connection for player-1 with the reconnect token
+	connect := func(reconnectToken string) (*TestClient, *AuthenticatedPayload) {
+		t.Helper()
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		if err := client.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, ReconnectToken: reconnectToken}); err != nil {
+			t.Fatalf("failed to auth: %v", err)
+		}
+		auth, err := client.AssertAuthSuccess(testTimeout)
+		if err != nil {
+			t.Fatalf("auth failed: %v", err)
+		}
+		return client, auth
+	}
+
+	first, firstAuth := connect("")
+	defer first.Close()
+
+	// Reconnecting with the token replaces the connection and issues a new token
+	second, secondAuth := connect(firstAuth.ReconnectToken)
+	defer second.Close()
+	if err := first.ExpectClose(CloseCodeReplaced, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if secondAuth.ReconnectToken == "" || secondAuth.ReconnectToken == firstAuth.ReconnectToken {
+		t.Fatalf("expected a new reconnect token, got %q", secondAuth.ReconnectToken)
+	}
+
+	// The used token replaces nothing when presented again
+	third, _ := connect(firstAuth.ReconnectToken)
+	defer third.Close()
+	second.Drain()
+	if err := second.SendHeartbeat(); err != nil {
+		t.Fatalf("failed to send heartbeat: %v", err)
+	}
+	if _, err := second.ReceiveType(TypeHeartbeatAck, testTimeout); err != nil {
+		t.Fatalf("expected the connection to stay open: %v", err)
+	}
+
+	// The new token is accepted in its place
+	fourth, _ := connect(secondAuth.ReconnectToken)
+	defer fourth.Close()
+	if err := second.ExpectClose(CloseCodeReplaced, testTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+// ========================================
+// Payload Size Limit Tests
+// ========================================
+
+func TestWS_PayloadSizeLimit(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	_, client := connectHost(t, ts)
+	defer client.Close()
+
+	// A chat payload over its limit is refused before it is decoded
+	if err := client.SendChat(strings.Repeat("a", maxChatPayloadSize)); err != nil {
+		t.Fatalf("failed to send chat: %v", err)
+	}
+	env, err := client.ReceiveType(TypeError, testTimeout)
+	if err != nil {
+		t.Fatalf("expected an error: %v", err)
+	}
+	var payload ErrorPayload
+	env.ParsePayload(&payload)
+	if payload.Code != ErrCodePayloadTooLarge || !payload.Recoverable {
+		t.Fatalf("expected a recoverable PAYLOAD_TOO_LARGE, got %+v", payload)
+	}
+	var details PayloadTooLargeDetails
+	json.Unmarshal(payload.Details, &details)
+	if details.Limit != maxChatPayloadSize || details.Size != maxChatPayloadSize+len(`{"text":""}`) {
+		t.Errorf("expected the size and limit in the details, got %+v", details)
+	}
+
+	// The connection is still served
+	if err := client.SendHeartbeat(); err != nil {
+		t.Fatalf("failed to send heartbeat: %v", err)
+	}
+	if _, err := client.ReceiveType(TypeHeartbeatAck, testTimeout); err != nil {
+		t.Fatalf("expected heartbeat_ack: %v", err)
+	}
+}
+
+// ========================================
+// Lobby Catch-Up Tests
+// ========================================
+
+func TestWS_RequestLobbyState_SinceSeq(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client1.Close()
+	if err := client1.SendAuth("player-1", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	connected, err := client1.ReceiveType(TypePlayerConnected, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive player_connected: %v", err)
+	}
+	if err := client2.SendReady(true); err != nil {
+		t.Fatalf("failed to send ready: %v", err)
+	}
+	update, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_updated: %v", err)
+	}
+
+	// Asking for the changes after player_connected gets the ready change alone, not the whole lobby
+	client1.Drain()
+	if err := client1.SendRequestLobbyStateSince(connected.LobbySeq); err != nil {
+		t.Fatalf("failed to send request_lobby_state: %v", err)
+	}
+	env, err := client1.ReceiveType(TypeLobbyEvents, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_events: %v", err)
+	}
+	if env.CorrelationID != "lobby-state-player-1" {
+		t.Errorf("expected the request's correlation ID, got %q", env.CorrelationID)
+	}
+	var events LobbyEventsPayload
+	if err := env.ParsePayload(&events); err != nil {
+		t.Fatalf("failed to parse lobby_events: %v", err)
+	}
+	if events.SinceSeq != connected.LobbySeq || events.LobbySeq < update.LobbySeq {
+		t.Errorf("expected the events after lobby_seq %d up to %d, got %+v", connected.LobbySeq, update.LobbySeq, events)
+	}
+	if len(events.Events) != 1 || events.Events[0].Event != LobbyEventPlayerReadyChanged || events.Events[0].LobbySeq != update.LobbySeq {
+		t.Errorf("expected the ready change with lobby_seq %d, got %+v", update.LobbySeq, events.Events)
+	}
+
+	// Being up to date gets no events
+	client1.SendRequestLobbyStateSince(update.LobbySeq)
+	env, err = client1.ReceiveType(TypeLobbyEvents, testTimeout)
+	if err != nil {
+		t.Fatalf("failed to receive lobby_events: %v", err)
+	}
+	env.ParsePayload(&events)
+	if len(events.Events) != 0 {
+		t.Errorf("expected no events, got %+v", events.Events)
+	}
+
+	// A lobby_seq the lobby has not reached can only be answered with the whole lobby
+	client1.SendRequestLobbyStateSince(update.LobbySeq + 1000)
+	if _, err := client1.ReceiveType(TypeLobbyUpdated, testTimeout); err != nil {
+		t.Fatalf("expected lobby_updated with the whole lobby: %v", err)
+	}
+
+	// A negative since_seq is invalid
+	client1.SendRequestLobbyStateSince(-1)
+	if err := client1.ExpectError(ErrCodeMalformedMessage, testTimeout); err != nil {
+		t.Fatalf("expected a malformed message error: %v", err)
+	}
+}
+
+// ========================================
+// Client Capability Tests
+// ========================================
+
+func TestWS_Capabilities(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		t.Fatalf("failed to join lobby: %v", err)
+	}
+
+	client1, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client1.Close()
+	capabilities := &ClientCapabilities{SupportsDeltaState: true, ClientVersion: "web/1.4.2"}
+	if err := client1.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, Capabilities: capabilities}); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client1.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	client2, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client2.Close()
+	if err := client2.SendAuth("player-2", lobbyCode); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := client2.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	// supports_delta_state opts into game_state_delta as delta_updates does
+	conns := ts.Hub.PlayerConnections("player-1")
+	if len(conns) != 1 {
+		t.Fatalf("expected player-1 to have one connection, got %d", len(conns))
+	}
+	if got := conns[0].Session().capabilities(); got == nil || *got != *capabilities {
+		t.Errorf("expected the declared capabilities kept, got %+v", got)
+	}
+	if !conns[0].DeltaUpdates() || ts.Hub.PlayerDeltaUpdates("player-2") {
+		t.Error("expected only player-1 to receive game_state_delta")
+	}
+
+	// Each authentication is counted under the client version declared
+	versions := ts.Hub.Metrics().ClientVersions
+	if versions["web/1.4.2"] != 1 || versions[unknownClientVersion] != 1 {
+		t.Errorf("expected one web/1.4.2 client and one unknown, got %+v", versions)
+	}
+
+	// An overlong client_version is rejected
+	client3, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer client3.Close()
+	client3.sendAuth(AuthenticatePayload{PlayerID: "player-2", LobbyCode: lobbyCode,
+		Capabilities: &ClientCapabilities{ClientVersion: strings.Repeat("v", maxVersionLength+1)}})
+	if err := client3.ExpectError(ErrCodeMalformedMessage, testTimeout); err != nil {
+		t.Fatalf("expected a malformed message error: %v", err)
+	}
+}
+
+func TestWS_Capabilities_KeptOnResume(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		t.Fatalf("failed to create lobby: %v", err)
+	}
+
+	first, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer first.Close()
+	capabilities := &ClientCapabilities{SupportsDeltaState: true, ClientVersion: "web/1.4.2"}
+	if err := first.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, Capabilities: capabilities}); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	auth, err := first.AssertAuthSuccess(testTimeout)
+	if err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+	first.Close()
+	if !waitFor(func() bool { return !ts.Hub.IsPlayerConnected("player-1") }, testTimeout) {
+		t.Fatal("expected the first connection to be gone")
+	}
+
+	// Reconnecting with the token without declaring anything keeps what was declared before
+	second, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer second.Close()
+	if err := second.sendAuth(AuthenticatePayload{PlayerID: "player-1", LobbyCode: lobbyCode, ReconnectToken: auth.ReconnectToken}); err != nil {
+		t.Fatalf("failed to auth: %v", err)
+	}
+	if _, err := second.AssertAuthSuccess(testTimeout); err != nil {
+		t.Fatalf("auth failed: %v", err)
+	}
+
+	conn := ts.Hub.GetConnectionByPlayerID("player-1")
+	if conn == nil {
+		t.Fatal("expected player-1 to be connected")
+	}
+	if !conn.DeltaUpdates() || conn.shouldCompress(DefaultCompressionThreshold*10) {
+		t.Error("expected the resumed connection to get delta updates and no compression, as declared before")
+	}
+	if got := conn.Session().capabilities(); got == nil || *got != *capabilities {
+		t.Errorf("expected the declared capabilities kept, got %+v", got)
+	}
+	if versions := ts.Hub.Metrics().ClientVersions; versions["web/1.4.2"] != 2 {
+		t.Errorf("expected both authentications counted as web/1.4.2, got %+v", versions)
+	}
 }
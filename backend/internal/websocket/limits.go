@@ -0,0 +1,94 @@
+package websocket
+
+// Default caps on simultaneous connections
+const (
+	DefaultMaxConnectionsPerPlayer = 3
+	DefaultMaxConnectionsPerIP     = 20
+)
+
+// ConnectionLimits caps how many connections may be open at once, to protect against connection exhaustion.
+// A cap of 0 means no limit.
+type ConnectionLimits struct {
+	PerPlayer int // Authenticated connections per player ID
+	PerIP     int // Connections per remote IP, authenticated or not
+}
+
+// SetConnectionLimits sets the caps on simultaneous connections
+func (h *Hub) SetConnectionLimits(limits ConnectionLimits) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.limits = limits
+}
+
+// admitIP counts a new connection from the IP, returning false without counting it if the IP is at its cap
+func (h *Hub) admitIP(ip string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.limits.PerIP > 0 && h.ipConnections[ip] >= h.limits.PerIP {
+		return false
+	}
+	h.ipConnections[ip]++
+	return true
+}
+
+// releaseIP stops counting a connection from the IP
+func (h *Hub) releaseIP(ip string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.releaseIPLocked(ip)
+}
+
+// releaseIPLocked is releaseIP for a caller that already holds the lock
+func (h *Hub) releaseIPLocked(ip string) {
+	if h.ipConnections[ip] <= 1 {
+		delete(h.ipConnections, ip)
+		return
+	}
+	h.ipConnections[ip]--
+}
+
+// admitPlayer counts conn against the player's cap, returning false without counting it if they are at it.
+// Checking and counting happen together, so connections authenticating at once cannot both take the last slot.
+// The connection is counted until it is unregistered or replaced by a reconnect, and admitting it again
+// as the same player counts it once.
+func (h *Hub) admitPlayer(conn *Connection, playerID string) bool {
+	h.mu.RLock()
+	limit := h.limits.PerPlayer
+	h.mu.RUnlock()
+
+	h.playersMu.Lock()
+	defer h.playersMu.Unlock()
+
+	if conn.admittedAs == playerID {
+		return true
+	}
+	if limit > 0 && h.playerConnections[playerID] >= limit {
+		return false
+	}
+	h.releasePlayerLocked(conn)
+	h.playerConnections[playerID]++
+	conn.admittedAs = playerID
+	return true
+}
+
+// releasePlayer stops counting conn against the cap of the player it was admitted as, if any
+func (h *Hub) releasePlayer(conn *Connection) {
+	h.playersMu.Lock()
+	defer h.playersMu.Unlock()
+	h.releasePlayerLocked(conn)
+}
+
+// releasePlayerLocked is releasePlayer for a caller that already holds playersMu
+func (h *Hub) releasePlayerLocked(conn *Connection) {
+	playerID := conn.admittedAs
+	if playerID == "" {
+		return
+	}
+	conn.admittedAs = ""
+	if h.playerConnections[playerID] <= 1 {
+		delete(h.playerConnections, playerID)
+		return
+	}
+	h.playerConnections[playerID]--
+}
@@ -0,0 +1,116 @@
+package websocket
+
+// lobbyLogSize is how many of a lobby's latest broadcasts are kept to replay to players with no session to resume
+const lobbyLogSize = replayBufferSize
+
+// lobbyLog numbers a lobby's broadcasts and keeps the latest. Unlike a session's seq, which starts over
+// when a player leaves and joins again, the lobby's numbering runs for as long as the lobby does.
+type lobbyLog struct {
+	lastSeq    int64
+	broadcasts []loggedBroadcast // Oldest first, at most lobbyLogSize
+}
+
+// loggedBroadcast is a broadcast kept for replay, with the player it was not sent to
+type loggedBroadcast struct {
+	msg            *preparedEnvelope
+	exceptPlayerID string
+	events         []LobbyEventRecord // The lobby changes a lobby_updated reported, oldest first
+}
+
+// lobbyUpdateEvents returns the lobby changes a lobby_updated payload reports, oldest first,
+// or nil for any other payload
+func lobbyUpdateEvents(payload interface{}) []LobbyEventRecord {
+	update, ok := payload.(LobbyUpdatedPayload)
+	if !ok {
+		return nil
+	}
+	events := make([]LobbyEventRecord, 0, len(update.EarlierEvents)+1)
+	events = append(events, update.EarlierEvents...)
+	return append(events, LobbyEventRecord{Event: update.Event, EventData: update.EventData})
+}
+
+// logBroadcast gives the broadcast the lobby's next lobby seq and keeps it for replay, along with the lobby
+// changes it reports, returning it numbered for sending
+func (h *Hub) logBroadcast(lobbyCode, exceptPlayerID string, msg *preparedEnvelope, events []LobbyEventRecord) *preparedEnvelope {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	log, ok := shard.logs[lobbyCode]
+	if !ok {
+		log = &lobbyLog{}
+		shard.logs[lobbyCode] = log
+	}
+	log.lastSeq++
+	numbered := msg.withLobbySeq(log.lastSeq)
+
+	log.broadcasts = append(log.broadcasts, loggedBroadcast{msg: numbered, exceptPlayerID: exceptPlayerID, events: events})
+	if len(log.broadcasts) > lobbyLogSize {
+		log.broadcasts = log.broadcasts[len(log.broadcasts)-lobbyLogSize:]
+	}
+	return numbered
+}
+
+// LobbySeq returns the lobby seq of the lobby's latest broadcast, 0 if it has had none
+func (h *Hub) LobbySeq(lobbyCode string) int64 {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	if log, ok := shard.logs[lobbyCode]; ok {
+		return log.lastSeq
+	}
+	return 0
+}
+
+// lobbyBroadcastsSince returns the lobby's broadcasts to the player after lastLobbySeq, oldest first.
+// complete is false if some of them have already dropped out of the log, or lastLobbySeq is ahead of the lobby.
+func (h *Hub) lobbyBroadcastsSince(lobbyCode, playerID string, lastLobbySeq int64) (missed []*preparedEnvelope, complete bool) {
+	logged, complete := h.loggedBroadcastsSince(lobbyCode, playerID, lastLobbySeq)
+	for _, b := range logged {
+		missed = append(missed, b.msg)
+	}
+	return missed, complete
+}
+
+// lobbyEventsSince returns the lobby changes broadcast to the player after lastLobbySeq, oldest first,
+// with complete false as for lobbyBroadcastsSince
+func (h *Hub) lobbyEventsSince(lobbyCode, playerID string, lastLobbySeq int64) (missed []MissedLobbyEvent, complete bool) {
+	logged, complete := h.loggedBroadcastsSince(lobbyCode, playerID, lastLobbySeq)
+	for _, b := range logged {
+		for _, record := range b.events {
+			missed = append(missed, MissedLobbyEvent{LobbySeq: b.msg.lobbySeq, Event: record.Event, EventData: record.EventData})
+		}
+	}
+	return missed, complete
+}
+
+// loggedBroadcastsSince returns the logged broadcasts to the player after lastLobbySeq, oldest first,
+// with complete false as for lobbyBroadcastsSince
+func (h *Hub) loggedBroadcastsSince(lobbyCode, playerID string, lastLobbySeq int64) (missed []loggedBroadcast, complete bool) {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	log, ok := shard.logs[lobbyCode]
+	if !ok {
+		return nil, lastLobbySeq == 0 // The lobby has broadcast nothing yet
+	}
+	if lastLobbySeq > log.lastSeq {
+		return nil, false
+	}
+	complete = lastLobbySeq == log.lastSeq || (len(log.broadcasts) > 0 && log.broadcasts[0].msg.lobbySeq <= lastLobbySeq+1)
+	for _, b := range log.broadcasts {
+		if b.msg.lobbySeq > lastLobbySeq && b.exceptPlayerID != playerID {
+			missed = append(missed, b)
+		}
+	}
+	return missed, complete
+}
+
+// dropLobbyLog forgets the lobby's broadcasts and numbering once it has closed
+func (h *Hub) dropLobbyLog(lobbyCode string) {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.logs, lobbyCode)
+}
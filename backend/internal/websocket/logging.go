@@ -0,0 +1,60 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+)
+
+// SetLogger sets the logger for the hub and the connections it serves from now on.
+// Each connection's entries carry its conn_id, and its player_id and lobby_code once it has authenticated.
+func (h *Hub) SetLogger(logger *slog.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger = logger
+}
+
+// Logger returns the hub's logger
+func (h *Hub) Logger() *slog.Logger {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.logger
+}
+
+// ID returns the connection's ID, unique to it and fixed when it is created
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// log returns the connection's logger
+func (c *Connection) log() *slog.Logger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.logger
+}
+
+// newConnectionID generates a random connection ID
+func newConnectionID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes) // Never returns an error since Go 1.24
+	return "conn-" + hex.EncodeToString(bytes)
+}
+
+// withConnectionID adds the connection ID to an error's details, so a client can quote it when reporting a problem.
+// Details that are not a JSON object are left as they are.
+func withConnectionID(details json.RawMessage, connectionID string) json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	if len(details) > 0 {
+		if err := json.Unmarshal(details, &fields); err != nil {
+			return details
+		}
+	}
+	id, _ := json.Marshal(connectionID)
+	fields["connection_id"] = id
+	stamped, err := json.Marshal(fields)
+	if err != nil {
+		return details
+	}
+	return stamped
+}
@@ -0,0 +1,74 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithConnectionID(t *testing.T) {
+	tests := []struct {
+		name    string
+		details string
+		want    string
+	}{
+		{"no details", "", `{"connection_id":"conn-1"}`},
+		{"object", `{"fields":[]}`, `{"connection_id":"conn-1","fields":[]}`},
+		{"not an object", `[1,2]`, `[1,2]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var details json.RawMessage
+			if tt.details != "" {
+				details = json.RawMessage(tt.details)
+			}
+			if got := string(withConnectionID(details, "conn-1")); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConnection_Logging(t *testing.T) {
+	var buf bytes.Buffer
+	hub := NewHub()
+	hub.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	conn := NewConnection(nil, hub)
+	other := NewConnection(nil, hub)
+	if conn.ID() == "" || conn.ID() == other.ID() {
+		t.Fatalf("expected unique connection IDs, got %q and %q", conn.ID(), other.ID())
+	}
+	if _, err := conn.Authenticate("player-1", "ABC123"); err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	conn.SendError(ErrCodeInvalidAction, "Not allowed", "req-1")
+
+	// Every entry carries the connection's ID, and its player and lobby once authenticated
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry["conn_id"] != conn.ID() {
+			t.Errorf("expected conn_id %s, got %v", conn.ID(), entry)
+		}
+		if entry["msg"] == "websocket error sent" {
+			found = true
+			if entry["player_id"] != "player-1" || entry["lobby_code"] != "ABC123" ||
+				entry["code"] != string(ErrCodeInvalidAction) || entry["correlation_id"] != "req-1" {
+				t.Errorf("unexpected error entry: %v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected the error to be logged, got %v", entries)
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"time"
 )
 
-// Protocol version
-const ProtocolVersion = 1
+// Protocol versions the server accepts, see versions.go.
+// ProtocolVersion is the newest, which every message is handled and sent in.
+const (
+	MinProtocolVersion = 1
+	ProtocolVersion    = 1
+)
 
 // MessageType represents the type of WebSocket message
 type MessageType string
@@ -16,45 +20,92 @@ const (
 	// Connection & Authentication
 	TypeAuthenticate     MessageType = "authenticate"
 	TypeHeartbeat        MessageType = "heartbeat"
+	TypeTimeSync         MessageType = "time_sync"
+	TypeClaimPrimary     MessageType = "claim_primary"
 
 	// Lobby Lifecycle
 	TypeRequestLobbyState MessageType = "request_lobby_state"
 	TypeSetReady          MessageType = "set_ready"
+	TypeSubmitTeam        MessageType = "submit_team"
+	TypeSubmitPick        MessageType = "submit_pick"
+	TypeStartGame         MessageType = "start_game"
+	TypeCancelGameStart   MessageType = "cancel_game_start"
 
 	// Battle Lifecycle
+	TypeChooseLead       MessageType = "choose_lead"
 	TypeSubmitAction     MessageType = "submit_action"
 	TypeRequestGameState MessageType = "request_game_state"
+	TypeOfferDraw        MessageType = "offer_draw"
+	TypeRespondDraw      MessageType = "respond_draw"
+	TypeRequestPause     MessageType = "request_pause"
+	TypeRespondPause     MessageType = "respond_pause"
+	TypeRequestResume    MessageType = "request_resume"
 
 	// Post-Battle
 	TypeRequestRematch MessageType = "request_rematch"
 	TypeLeaveGame      MessageType = "leave_game"
+
+	// Chat; the server relays chat_message to the whole lobby with the same type
+	TypeChatMessage MessageType = "chat_message"
+
+	// Delivery
+	TypeAck           MessageType = "ack"
+	TypeResyncRequest MessageType = "resync_request"
 )
 
 // Server -> Client message types
 const (
 	// Connection & Authentication
-	TypeAuthenticated MessageType = "authenticated"
-	TypeHeartbeatAck  MessageType = "heartbeat_ack"
+	TypeAuthenticated  MessageType = "authenticated"
+	TypeHeartbeatAck     MessageType = "heartbeat_ack"
+	TypeTimeSyncResponse MessageType = "time_sync_response"
+	TypePrimaryChanged   MessageType = "primary_changed"
 
 	// Lobby Lifecycle
-	TypeLobbyUpdated  MessageType = "lobby_updated"
-	TypeGameStarting  MessageType = "game_starting"
-	TypeGameStarted   MessageType = "game_started"
+	TypeLobbyUpdated       MessageType = "lobby_updated"
+	TypeGameStarting       MessageType = "game_starting"
+	TypeGameStartCancelled MessageType = "game_start_cancelled"
+	TypeGameStarted        MessageType = "game_started"
+	TypeDraftState         MessageType = "draft_state"
+	TypeLobbyClosed        MessageType = "lobby_closed"
+	TypeWaitlistPromoted   MessageType = "waitlist_promoted"
+
+	// Presence
+	TypePlayerConnected      MessageType = "player_connected"
+	TypePlayerDisconnected   MessageType = "player_disconnected"
+	TypeOpponentDisconnected MessageType = "opponent_disconnected"
 
 	// Battle Lifecycle
+	TypeTeamPreview        MessageType = "team_preview"
 	TypeGameState          MessageType = "game_state"
+	TypeGameStateDelta     MessageType = "game_state_delta"
+	TypeSpectatorState     MessageType = "spectator_state"
 	TypeActionAcknowledged MessageType = "action_acknowledged"
+	TypeOpponentCommitted  MessageType = "opponent_committed"
 	TypeTurnResult         MessageType = "turn_result"
 	TypeSwitchRequired     MessageType = "switch_required"
 	TypeGameEnded          MessageType = "game_ended"
+	TypeDrawOffered        MessageType = "draw_offered"
+	TypeDrawDeclined       MessageType = "draw_declined"
+	TypePauseRequested     MessageType = "pause_requested"
+	TypePauseDeclined      MessageType = "pause_declined"
+	TypeBattlePaused       MessageType = "battle_paused"
+	TypeBattleResuming     MessageType = "battle_resuming"
+	TypeBattleResumed      MessageType = "battle_resumed"
 
 	// Rematch Flow
 	TypeRematchRequested MessageType = "rematch_requested"
 	TypeRematchStarting  MessageType = "rematch_starting"
+	TypeSeriesEnded      MessageType = "series_ended"
+
+	// Delivery
+	TypeResync      MessageType = "resync"
+	TypeLobbyEvents MessageType = "lobby_events"
 
 	// Errors
 	TypeError            MessageType = "error"
 	TypeDisconnectWarning MessageType = "disconnect_warning"
+	TypeServerShutdown    MessageType = "server_shutdown"
 )
 
 // Envelope is the standard message wrapper for all WebSocket messages
@@ -64,7 +115,10 @@ type Envelope struct {
 	Timestamp     int64           `json:"timestamp"`
 	CorrelationID string          `json:"correlation_id,omitempty"`
 	Seq           int64           `json:"seq,omitempty"`
+	LobbySeq      int64           `json:"lobby_seq,omitempty"` // Numbers the lobby's broadcasts, across all its players and their connections
 	Payload       json.RawMessage `json:"payload"`
+
+	receivedAt time.Time // When the server read the message, zero for messages it sends
 }
 
 // NewEnvelope creates a new envelope with current timestamp and protocol version
@@ -113,19 +167,118 @@ type AuthenticatePayload struct {
 	LobbyCode      string `json:"lobby_code"`
 	ReconnectToken string `json:"reconnect_token,omitempty"`
 	LastSeq        int64  `json:"last_seq,omitempty"`
+	LastLobbySeq   int64  `json:"last_lobby_seq,omitempty"` // Replay the lobby's broadcasts after this when there is no session to resume
+	DeltaUpdates   bool   `json:"delta_updates,omitempty"`  // Receive game_state_delta instead of full states
+	Spectate       bool   `json:"spectate,omitempty"`       // Join the lobby's spectator roster instead of playing
+	Username       string `json:"username,omitempty"`       // Name shown to the lobby; required to join as a spectator
+	Secondary      bool   `json:"secondary,omitempty"`      // Join the player's other devices without taking over as primary
+
+	// What the client can handle, so the server can tailor what it sends; clients that leave it out
+	// get what they asked for above and negotiated on the upgrade
+	Capabilities *ClientCapabilities `json:"capabilities,omitempty"`
+}
+
+// ClientCapabilities declares what a client can handle and which build of it is connecting
+type ClientCapabilities struct {
+	SupportsDeltaState  bool   `json:"supports_delta_state,omitempty"` // Receive game_state_delta instead of full states, as delta_updates does
+	SupportsCompression bool   `json:"supports_compression,omitempty"` // Without it, messages are sent uncompressed even if permessage-deflate was negotiated
+	ClientVersion       string `json:"client_version,omitempty"`       // Free-form, such as "web/1.4.2"; counted in the metrics
 }
 
 // HeartbeatPayload is sent by clients to keep connection alive
 type HeartbeatPayload struct{}
 
-// RequestLobbyStatePayload is sent to get current lobby state
-type RequestLobbyStatePayload struct{}
+// ClaimPrimaryPayload is sent by one of a player's devices to take over as primary
+type ClaimPrimaryPayload struct{}
+
+// TimeSyncPayload asks for the server's clock, so the client can work out how far its own is off
+// and show turn timers and starts_at times as the server means them
+type TimeSyncPayload struct {
+	ClientSendTime int64 `json:"client_send_time"` // The client's clock when it sent the request, in Unix milliseconds
+}
+
+// RequestLobbyStatePayload is sent to get current lobby state. A client that was briefly offline can send
+// the lobby_seq it last had as since_seq to be sent only the lobby changes after it, as lobby_events.
+type RequestLobbyStatePayload struct {
+	SinceSeq int64 `json:"since_seq,omitempty"` // The highest lobby_seq received with none missing before it
+}
+
+// AckPayload tells the server the client has every broadcast up to lobby_seq, so it need not keep them for replay
+type AckPayload struct {
+	LobbySeq int64 `json:"lobby_seq"` // The highest lobby_seq received with none missing before it
+}
+
+// ResyncRequestPayload is sent by a client that found a gap in the broadcasts it received
+type ResyncRequestPayload struct {
+	LastLobbySeq int64 `json:"last_lobby_seq"` // The highest lobby_seq received with none missing before it
+}
 
 // SetReadyPayload is sent to signal ready status
 type SetReadyPayload struct {
 	Ready bool `json:"ready"`
 }
 
+// TeamMemberPayload is one creature in a submitted team
+type TeamMemberPayload struct {
+	SpeciesID string   `json:"species_id"`
+	Level     int      `json:"level,omitempty"`
+	Item      string   `json:"item,omitempty"`
+	Moves     []string `json:"moves"`
+	Nickname  string   `json:"nickname,omitempty"`
+	Shiny     bool     `json:"shiny,omitempty"`
+}
+
+// SubmitTeamPayload is sent to choose the team for the next game
+type SubmitTeamPayload struct {
+	Team []TeamMemberPayload `json:"team"`
+}
+
+// TeamViolationInfo describes one rule a submitted team breaks.
+// Slot is omitted for violations that apply to the whole team.
+type TeamViolationInfo struct {
+	Slot    *int   `json:"slot,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// InvalidTeamDetails is the error detail sent when a submitted team is rejected
+type InvalidTeamDetails struct {
+	Violations []TeamViolationInfo `json:"violations"`
+}
+
+// FieldErrorInfo describes one invalid field of a payload
+type FieldErrorInfo struct {
+	Field  string `json:"field"` // Path to the field, such as "team[1].species_id"; empty when the payload as a whole is invalid
+	Reason string `json:"reason"`
+}
+
+// InvalidPayloadDetails is the error detail sent when a payload fails validation
+type InvalidPayloadDetails struct {
+	Fields []FieldErrorInfo `json:"fields"`
+}
+
+// PayloadTooLargeDetails is the error detail sent when a payload is over its message type's size limit
+type PayloadTooLargeDetails struct {
+	Size  int `json:"size"`  // The payload's size in bytes, as JSON
+	Limit int `json:"limit"` // The most bytes a payload of its type may have
+}
+
+// SubmitPickPayload is sent on the player's draft turn to ban or pick a species
+type SubmitPickPayload struct {
+	SpeciesID string `json:"species_id"`
+}
+
+// StartGamePayload is sent by the host to start the game in a lobby whose start mode is host
+type StartGamePayload struct{}
+
+// CancelGameStartPayload is sent by the host to call off a game start during its countdown
+type CancelGameStartPayload struct{}
+
+// ChooseLeadPayload is sent during team preview to pick the creature sent out first
+type ChooseLeadPayload struct {
+	Slot int `json:"slot"`
+}
+
 // ActionType represents the type of battle action
 type ActionType string
 
@@ -138,6 +291,7 @@ const (
 
 // SubmitActionPayload is sent during battle
 type SubmitActionPayload struct {
+	GameID     string          `json:"game_id,omitempty"` // Optional; rejects the action unless it is the lobby's game in progress
 	TurnNumber int             `json:"turn_number"`
 	ActionType ActionType      `json:"action_type"`
 	ActionData json.RawMessage `json:"action_data"`
@@ -165,12 +319,36 @@ type RequestGameStatePayload struct {
 	IncludeHistory bool `json:"include_history"`
 }
 
+// OfferDrawPayload proposes a draw to the opponent
+type OfferDrawPayload struct{}
+
+// RespondDrawPayload answers the opponent's draw offer
+type RespondDrawPayload struct {
+	Accept bool `json:"accept"`
+}
+
+// RequestPausePayload asks the opponent to pause the battle
+type RequestPausePayload struct{}
+
+// RespondPausePayload answers the opponent's pause request
+type RespondPausePayload struct {
+	Accept bool `json:"accept"`
+}
+
+// RequestResumePayload ends an agreed pause early
+type RequestResumePayload struct{}
+
 // RequestRematchPayload is sent after game ends
 type RequestRematchPayload struct{}
 
 // LeaveGamePayload is sent to exit game/lobby
 type LeaveGamePayload struct{}
 
+// ChatMessagePayload is sent to say something to the rest of the lobby
+type ChatMessagePayload struct {
+	Text string `json:"text"`
+}
+
 // ========================================
 // Server -> Client Payloads
 // ========================================
@@ -178,8 +356,44 @@ type LeaveGamePayload struct{}
 // AuthenticatedPayload confirms authentication
 type AuthenticatedPayload struct {
 	PlayerID         string `json:"player_id"`
-	ReconnectToken   string `json:"reconnect_token"`
+	ReconnectToken   string `json:"reconnect_token"` // Accepted for one reconnect; each authentication issues a new one
 	SessionExpiresAt int64  `json:"session_expires_at"`
+	ProtocolVersion  int    `json:"protocol_version"`            // The version the connection authenticated with, which its later messages must use
+	Replayed         int    `json:"replayed,omitempty"`          // Messages after last_seq or last_lobby_seq sent again before this one
+	ReplayIncomplete bool   `json:"replay_incomplete,omitempty"` // Some missed messages could not be replayed, so the client should resync
+	LobbySeq         int64  `json:"lobby_seq,omitempty"`         // The lobby_seq of the lobby's latest broadcast
+	ConnectionID     string `json:"connection_id"`
+	Primary          bool   `json:"primary"` // Whether this is the player's primary device, whose actions count
+}
+
+// PrimaryChangedPayload tells all a player's devices which of them is now primary
+type PrimaryChangedPayload struct {
+	ConnectionID         string        `json:"connection_id"`
+	PreviousConnectionID string        `json:"previous_connection_id,omitempty"`
+	Reason               PrimaryReason `json:"reason"`
+}
+
+// ResyncPayload answers resync_request with the lobby as it is now and the broadcasts missed since last_lobby_seq
+type ResyncPayload struct {
+	Lobby      LobbyInfo         `json:"lobby"`
+	Missed     []json.RawMessage `json:"missed"`               // The broadcasts after last_lobby_seq meant for the client, oldest first, as they were sent
+	LobbySeq   int64             `json:"lobby_seq"`            // The lobby_seq of the lobby's latest broadcast, which the snapshot is as of
+	Incomplete bool              `json:"incomplete,omitempty"` // Some missed broadcasts could no longer be sent, so the snapshot is all there is to go on
+}
+
+// LobbyEventsPayload answers request_lobby_state with since_seq with the lobby changes made after it. If some
+// can no longer be sent, a lobby_updated with the whole lobby is sent instead.
+type LobbyEventsPayload struct {
+	SinceSeq int64              `json:"since_seq"`
+	Events   []MissedLobbyEvent `json:"events"`    // Oldest first
+	LobbySeq int64              `json:"lobby_seq"` // The lobby_seq of the lobby's latest broadcast
+}
+
+// MissedLobbyEvent is a lobby change made while the client was away, with the lobby_seq of the update it was sent in
+type MissedLobbyEvent struct {
+	LobbySeq  int64           `json:"lobby_seq"`
+	Event     LobbyEvent      `json:"event"`
+	EventData json.RawMessage `json:"event_data,omitempty"`
 }
 
 // HeartbeatAckPayload acknowledges heartbeat
@@ -187,6 +401,15 @@ type HeartbeatAckPayload struct {
 	ServerTime int64 `json:"server_time"`
 }
 
+// TimeSyncResponsePayload answers time_sync with the server's clock, in Unix milliseconds. With the time t it
+// arrives, the client's clock is behind by ((server_receive_time - client_send_time) + (server_send_time - t)) / 2,
+// give or take half the round trip spent outside the server.
+type TimeSyncResponsePayload struct {
+	ClientSendTime    int64 `json:"client_send_time"`    // Echoed from the request
+	ServerReceiveTime int64 `json:"server_receive_time"` // When the server read the request
+	ServerSendTime    int64 `json:"server_send_time"`    // When the server sent the response
+}
+
 // LobbyEvent represents types of lobby updates
 type LobbyEvent string
 
@@ -196,26 +419,67 @@ const (
 	LobbyEventPlayerReadyChanged LobbyEvent = "player_ready_changed"
 	LobbyEventHostChanged       LobbyEvent = "host_changed"
 	LobbyEventStateChanged      LobbyEvent = "state_changed"
+	LobbyEventTeamSubmitted     LobbyEvent = "team_submitted"
+	LobbyEventSettingsChanged   LobbyEvent = "settings_changed"
+	LobbyEventSpectatorJoined   LobbyEvent = "spectator_joined"
+	LobbyEventSpectatorLeft     LobbyEvent = "spectator_left"
+	LobbyEventWaitlistJoined    LobbyEvent = "waitlist_joined"
+	LobbyEventWaitlistLeft      LobbyEvent = "waitlist_left"
 )
 
 // LobbyPlayerInfo represents a player in the lobby
 type LobbyPlayerInfo struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	IsHost        bool   `json:"is_host"`
+	IsReady       bool   `json:"is_ready"`
+	IsConnected   bool   `json:"is_connected"` // Bots are always connected
+	HasTeam       bool   `json:"has_team"`
+	IsBot         bool   `json:"is_bot"`
+	BotDifficulty string `json:"bot_difficulty,omitempty"`
+}
+
+// LobbySpectatorInfo represents a spectator watching the lobby
+type LobbySpectatorInfo struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
-	IsHost   bool   `json:"is_host"`
-	IsReady  bool   `json:"is_ready"`
+}
+
+// LobbyWaitlistInfo represents a player queued for the lobby's next free slot
+type LobbyWaitlistInfo struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Position int    `json:"position"` // 1 is next in line
 }
 
 // LobbyInfo represents the lobby state
 type LobbyInfo struct {
-	Code    string            `json:"code"`
-	State   string            `json:"state"`
-	Players []LobbyPlayerInfo `json:"players"`
+	Code           string               `json:"code"`
+	State          string               `json:"state"`
+	Ruleset        string               `json:"ruleset"`
+	BestOf         int                  `json:"best_of"`
+	DraftMode      bool                 `json:"draft_mode"`
+	RematchTeams   string               `json:"rematch_teams"`
+	Players        []LobbyPlayerInfo    `json:"players"`
+	Spectators     []LobbySpectatorInfo `json:"spectators"`
+	Waitlist       []LobbyWaitlistInfo  `json:"waitlist"`
+	MaxSpectators  int                  `json:"max_spectators"`
+	Tags           []string             `json:"tags"`
+	CreatedAt      int64                `json:"created_at"`       // Unix ms
+	LastActivityAt int64                `json:"last_activity_at"` // Unix ms
 }
 
-// LobbyUpdatedPayload notifies of lobby state changes
+// LobbyUpdatedPayload notifies of lobby state changes. Changes in quick succession are coalesced into one
+// update: Lobby is as it was after the latest, which is Event, and EarlierEvents lists the rest in order.
 type LobbyUpdatedPayload struct {
-	Lobby     LobbyInfo       `json:"lobby"`
+	Lobby         LobbyInfo          `json:"lobby"`
+	Event         LobbyEvent         `json:"event"`
+	EventData     json.RawMessage    `json:"event_data,omitempty"`
+	EarlierEvents []LobbyEventRecord `json:"earlier_events,omitempty"`
+}
+
+// LobbyEventRecord is one of the changes coalesced into a lobby update
+type LobbyEventRecord struct {
 	Event     LobbyEvent      `json:"event"`
 	EventData json.RawMessage `json:"event_data,omitempty"`
 }
@@ -231,6 +495,28 @@ type PlayerLeftEventData struct {
 	PlayerID string `json:"player_id"`
 }
 
+// SpectatorJoinedEventData is event data for spectator_joined
+type SpectatorJoinedEventData struct {
+	SpectatorID string `json:"spectator_id"`
+	Username    string `json:"username"`
+}
+
+// SpectatorLeftEventData is event data for spectator_left
+type SpectatorLeftEventData struct {
+	SpectatorID string `json:"spectator_id"`
+}
+
+// WaitlistJoinedEventData is event data for waitlist_joined
+type WaitlistJoinedEventData struct {
+	PlayerID string `json:"player_id"`
+	Username string `json:"username"`
+}
+
+// WaitlistLeftEventData is event data for waitlist_left
+type WaitlistLeftEventData struct {
+	PlayerID string `json:"player_id"`
+}
+
 // PlayerReadyChangedEventData is event data for player_ready_changed
 type PlayerReadyChangedEventData struct {
 	PlayerID string `json:"player_id"`
@@ -242,27 +528,117 @@ type HostChangedEventData struct {
 	NewHostID string `json:"new_host_id"`
 }
 
+// TeamSubmittedEventData is event data for team_submitted
+type TeamSubmittedEventData struct {
+	PlayerID string `json:"player_id"`
+}
+
+// SettingsChangedEventData is event data for settings_changed
+type SettingsChangedEventData struct {
+	Ruleset       string   `json:"ruleset"`
+	BestOf        int      `json:"best_of"`
+	TurnTimerSec  int      `json:"turn_timer_sec"`
+	CountdownSec  int      `json:"countdown_sec"`
+	StartMode     string   `json:"start_mode"`
+	Visibility    string   `json:"visibility"`
+	MaxSpectators int      `json:"max_spectators"`
+	Tags          []string `json:"tags"`
+}
+
 // StateChangedEventData is event data for state_changed
 type StateChangedEventData struct {
 	OldState string `json:"old_state"`
 	NewState string `json:"new_state"`
 }
 
+// DraftTurnInfo identifies whose draft turn it is and whether they ban or pick
+type DraftTurnInfo struct {
+	PlayerID string `json:"player_id"`
+	Kind     string `json:"kind"`
+}
+
+// DraftStatePayload is sent whenever the draft changes.
+// CurrentTurn and TurnDeadline are omitted once the draft is complete.
+type DraftStatePayload struct {
+	Pool         []string            `json:"pool"`
+	Bans         map[string][]string `json:"bans"`
+	Picks        map[string][]string `json:"picks"`
+	Step         int                 `json:"step"`
+	CurrentTurn  *DraftTurnInfo      `json:"current_turn,omitempty"`
+	TurnDeadline int64               `json:"turn_deadline,omitempty"`
+	Complete     bool                `json:"complete"`
+}
+
+// LobbyClosedReason explains why the server closed a lobby
+type LobbyClosedReason string
+
+const (
+	LobbyClosedReasonIdle LobbyClosedReason = "idle" // No activity and no connected players for the idle TTL
+	LobbyClosedReasonHost LobbyClosedReason = "host" // The host deleted the lobby
+)
+
+// LobbyClosedPayload notifies the lobby's connections that it no longer exists; they are disconnected after it
+type LobbyClosedPayload struct {
+	Code   string            `json:"code"`
+	Reason LobbyClosedReason `json:"reason"`
+}
+
+// WaitlistPromotedPayload tells a waitlisted player they now have a slot in the lobby
+type WaitlistPromotedPayload struct {
+	LobbyCode string `json:"lobby_code"`
+}
+
+// PlayerConnectedPayload tells the rest of the lobby a player connected or reconnected
+type PlayerConnectedPayload struct {
+	PlayerID string `json:"player_id"`
+}
+
+// PlayerDisconnectedPayload tells the lobby a player lost their connection without leaving
+type PlayerDisconnectedPayload struct {
+	PlayerID string `json:"player_id"`
+}
+
+// OpponentDisconnectedPayload tells a player their opponent dropped out of the battle
+type OpponentDisconnectedPayload struct {
+	PlayerID       string `json:"player_id"`
+	GraceExpiresAt int64  `json:"grace_expires_at"` // Unix ms at which the player wins if their opponent has not returned
+}
+
 // GameStartingPayload notifies that game countdown begins
 type GameStartingPayload struct {
 	StartsAt     int64 `json:"starts_at"`
 	CountdownSec int   `json:"countdown_sec"`
 }
 
+// GameStartCancelledReason explains why a game start countdown was called off
+type GameStartCancelledReason string
+
+const (
+	GameStartCancelledReasonHost       GameStartCancelledReason = "host"        // The host cancelled it
+	GameStartCancelledReasonNotReady   GameStartCancelledReason = "not_ready"   // A player was no longer ready, or the lobby could no longer start when it ended
+	GameStartCancelledReasonPlayerLeft GameStartCancelledReason = "player_left" // A player left or disconnected
+)
+
+// GameStartCancelledPayload notifies that the game will not start after all
+type GameStartCancelledPayload struct {
+	Reason   GameStartCancelledReason `json:"reason"`
+	PlayerID string                   `json:"player_id,omitempty"` // The player whose action cancelled it, if any
+}
+
 // GameStartedPayload notifies that the game has started
 type GameStartedPayload struct {
-	GameID string `json:"game_id,omitempty"`
+	GameID         string                        `json:"game_id,omitempty"`
+	DataVersion    string                        `json:"data_version"`
+	TypeChart      map[string]map[string]float64 `json:"type_chart,omitempty"`      // Set when the ruleset changes the type chart: attacker -> defender -> multiplier, unlisted matchups neutral
+	SeedCommitment string                        `json:"seed_commitment,omitempty"` // Hex SHA-256 of "<seed_salt>:<seed>", both revealed in game_ended
 }
 
 // CreatureInfo represents a creature in battle
 type CreatureInfo struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
+	Nickname    string `json:"nickname,omitempty"` // Shown in place of the name when set
+	Shiny       bool   `json:"shiny,omitempty"`
 	CurrentHP   int    `json:"current_hp"`
 	MaxHP       int    `json:"max_hp"`
 	Status      string `json:"status,omitempty"`
@@ -288,34 +664,135 @@ type DetailedCreatureInfo struct {
 
 // PlayerBattleState represents a player's battle state
 type PlayerBattleState struct {
-	PlayerID     string                 `json:"player_id"`
-	Username     string                 `json:"username"`
-	Team         []DetailedCreatureInfo `json:"team,omitempty"`         // Only for own team
-	ActiveSlot   int                    `json:"active_slot"`
-	BenchCount   int                    `json:"bench_count,omitempty"` // For opponent
-	ActiveHP     int                    `json:"active_hp,omitempty"`   // For opponent's active
-	ActiveMaxHP  int                    `json:"active_max_hp,omitempty"`
-	ActiveStatus string                 `json:"active_status,omitempty"`
+	PlayerID      string                 `json:"player_id"`
+	Username      string                 `json:"username"`
+	Team          []DetailedCreatureInfo `json:"team,omitempty"`         // Only for own team
+	ActiveSlot    int                    `json:"active_slot"`
+	BenchCount    int                    `json:"bench_count,omitempty"` // For opponent
+	ActiveHP      int                    `json:"active_hp,omitempty"`   // For opponent's active
+	ActiveMaxHP   int                    `json:"active_max_hp,omitempty"`
+	ActiveStatus  string                 `json:"active_status,omitempty"`
+	Revealed      []RevealedCreatureInfo `json:"revealed,omitempty"`   // Opposing creatures seen so far, with the moves they used
+	ItemsUsed     map[string]int         `json:"items_used,omitempty"` // Bag items the opponent has used
+	Hazards       *HazardsInfo           `json:"hazards,omitempty"`    // Entry hazards on this side of the field
+	Bag           map[string]int         `json:"bag,omitempty"`        // Remaining bag items, only for own side
+}
+
+// RevealedCreatureInfo is an opposing creature that has been seen on the field
+type RevealedCreatureInfo struct {
+	CreatureInfo
+	Slot  int                `json:"slot"`
+	Moves []RevealedMoveInfo `json:"moves,omitempty"` // Only moves it has used
+}
+
+// RevealedMoveInfo describes an opposing move seen in battle; its remaining PP stays hidden
+type RevealedMoveInfo struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Power    int    `json:"power,omitempty"`
+	Accuracy int    `json:"accuracy,omitempty"`
+}
+
+// HazardsInfo describes the entry hazards laid on a side of the field
+type HazardsInfo struct {
+	StealthRock bool `json:"stealth_rock,omitempty"`
+	Spikes      int  `json:"spikes,omitempty"`
+	ToxicSpikes int  `json:"toxic_spikes,omitempty"`
 }
 
 // GamePhase represents the current phase of the game
 type GamePhase string
 
 const (
+	GamePhaseTeamPreview     GamePhase = "team_preview"
 	GamePhaseActionSelection GamePhase = "action_selection"
 	GamePhaseTurnResolution  GamePhase = "turn_resolution"
+	GamePhaseSwitchSelection GamePhase = "switch_selection"
 	GamePhaseEnded           GamePhase = "ended"
 )
 
 // GameStatePayload contains full game snapshot
 type GameStatePayload struct {
+	Revision      int               `json:"revision"` // Increases with every state sent to the player
 	TurnNumber    int               `json:"turn_number"`
 	Phase         GamePhase         `json:"phase"`
 	PlayerState   PlayerBattleState `json:"player_state"`
 	OpponentState PlayerBattleState `json:"opponent_state"`
+	Field         FieldInfo         `json:"field"`
 	TurnTimer     *TurnTimerInfo    `json:"turn_timer,omitempty"`
 }
 
+// SpectatorStatePayload is the full view of the battle sent to spectators, who see both sides as their players do
+type SpectatorStatePayload struct {
+	TurnNumber int                 `json:"turn_number"`
+	Phase      GamePhase           `json:"phase"`
+	Sides      []PlayerBattleState `json:"sides"` // In battle order, the same for every spectator
+	Field      FieldInfo           `json:"field"`
+}
+
+// TeamPreviewPayload is sent to each player when a battle starts with team preview.
+// The opponent's species are revealed, but not their moves or lead.
+type TeamPreviewPayload struct {
+	PlayerState PlayerBattleState `json:"player_state"`
+	Opponent    PreviewSideInfo   `json:"opponent"`
+}
+
+// PreviewSideInfo lists the creatures an opponent brought
+type PreviewSideInfo struct {
+	PlayerID string                `json:"player_id"`
+	Team     []PreviewCreatureInfo `json:"team"`
+}
+
+// PreviewCreatureInfo is the part of an opposing creature revealed during team preview
+type PreviewCreatureInfo struct {
+	SpeciesID string `json:"species_id"`
+	Name      string `json:"name"`
+	Nickname  string `json:"nickname,omitempty"`
+	Shiny     bool   `json:"shiny,omitempty"`
+	Level     int    `json:"level"`
+}
+
+// GameStateDeltaPayload carries only what changed since the state with BaseRevision.
+// A client whose last state has another revision has missed an update and should send request_game_state.
+type GameStateDeltaPayload struct {
+	BaseRevision int        `json:"base_revision"`
+	Revision     int        `json:"revision"`
+	TurnNumber   int        `json:"turn_number"`
+	Phase        GamePhase  `json:"phase"`
+	Player       *SideDelta `json:"player,omitempty"`
+	Opponent     *SideDelta `json:"opponent,omitempty"`
+	Field        *FieldInfo `json:"field,omitempty"` // The whole field when it changes
+}
+
+// SideDelta lists what changed on one side of the field; unchanged fields are omitted
+type SideDelta struct {
+	ActiveSlot     *int                   `json:"active_slot,omitempty"`
+	Creatures      []CreatureDelta        `json:"creatures,omitempty"`     // Own side only
+	Bag            map[string]int         `json:"bag,omitempty"`           // Own side only, the whole bag when it changes
+	ActiveHP       *int                   `json:"active_hp,omitempty"`     // Opponent only
+	ActiveMaxHP    *int                   `json:"active_max_hp,omitempty"` // Opponent only
+	ActiveStatus   *string                `json:"active_status,omitempty"` // Opponent only; empty when cured
+	Revealed       []RevealedCreatureInfo `json:"revealed,omitempty"`      // Opponent only, the whole list when it changes
+	ItemsUsed      map[string]int         `json:"items_used,omitempty"`    // Opponent only, all items when they change
+	Hazards        *HazardsInfo           `json:"hazards,omitempty"`
+	HazardsCleared bool                   `json:"hazards_cleared,omitempty"`
+}
+
+// CreatureDelta lists what changed for one of the player's own creatures
+type CreatureDelta struct {
+	Slot      int            `json:"slot"`
+	CurrentHP *int           `json:"current_hp,omitempty"`
+	Status    *string        `json:"status,omitempty"` // Empty when cured
+	PP        map[string]int `json:"pp,omitempty"`     // Remaining PP of the moves that changed
+}
+
+// FieldInfo describes conditions affecting both sides of the field
+type FieldInfo struct {
+	Terrain      string `json:"terrain,omitempty"`       // electric, grassy, psychic, misty
+	TerrainTurns int    `json:"terrain_turns,omitempty"` // Turns remaining, including the current one
+}
+
 // TurnTimerInfo contains timer information
 type TurnTimerInfo struct {
 	ExpiresAt int64 `json:"expires_at"`
@@ -327,6 +804,12 @@ type ActionAcknowledgedPayload struct {
 	TurnNumber int `json:"turn_number"`
 }
 
+// OpponentCommittedPayload tells a player their opponent has chosen an action for the turn,
+// without revealing what it is
+type OpponentCommittedPayload struct {
+	TurnNumber int `json:"turn_number"`
+}
+
 // TurnEventType represents types of turn events
 type TurnEventType string
 
@@ -339,6 +822,17 @@ const (
 	TurnEventStatChanged     TurnEventType = "stat_changed"
 	TurnEventMoveFailed      TurnEventType = "move_failed"
 	TurnEventActionTimeout   TurnEventType = "action_timeout"
+	TurnEventStatusEnded     TurnEventType = "status_ended"
+	TurnEventConfusionSelfHit TurnEventType = "confusion_self_hit"
+	TurnEventLeechSeedDrain  TurnEventType = "leech_seed_drain"
+	TurnEventRecoilDamage    TurnEventType = "recoil_damage"
+	TurnEventStatusDamage    TurnEventType = "status_damage"
+	TurnEventHazardSet       TurnEventType = "hazard_set"
+	TurnEventHazardDamage    TurnEventType = "hazard_damage"
+	TurnEventHazardCleared   TurnEventType = "hazard_cleared"
+	TurnEventTerrainSet      TurnEventType = "terrain_set"
+	TurnEventTerrainEnded    TurnEventType = "terrain_ended"
+	TurnEventItemUsed        TurnEventType = "item_used"
 )
 
 // TurnEvent represents a single event in turn resolution
@@ -353,7 +847,7 @@ type TurnEvent struct {
 type TurnResultPayload struct {
 	TurnNumber     int              `json:"turn_number"`
 	Events         []TurnEvent      `json:"events"`
-	ResultingState GameStatePayload `json:"resulting_state"`
+	ResultingState *GameStatePayload `json:"resulting_state,omitempty"` // Omitted for delta clients, who get game_state_delta
 }
 
 // MoveUsedEventData for move_used event
@@ -371,10 +865,77 @@ type DamageDealtEventData struct {
 
 // StatusAppliedEventData for status_applied event
 type StatusAppliedEventData struct {
+	Target    string `json:"target"`
+	Status    string `json:"status"`
+	Secondary bool   `json:"secondary,omitempty"` // Triggered by a move's secondary effect chance
+}
+
+// StatusEndedEventData for status_ended event
+type StatusEndedEventData struct {
 	Target string `json:"target"`
 	Status string `json:"status"`
 }
 
+// ConfusionSelfHitEventData for confusion_self_hit event
+type ConfusionSelfHitEventData struct {
+	Target string `json:"target"`
+	Damage int    `json:"damage"`
+}
+
+// LeechSeedDrainEventData for leech_seed_drain event
+type LeechSeedDrainEventData struct {
+	Target    string `json:"target"`
+	Damage    int    `json:"damage"`
+	Recipient string `json:"recipient,omitempty"`
+	Healed    int    `json:"healed,omitempty"`
+}
+
+// RecoilDamageEventData for recoil_damage event
+type RecoilDamageEventData struct {
+	Target string `json:"target"`
+	Damage int    `json:"damage"`
+}
+
+// StatusDamageEventData for status_damage event
+type StatusDamageEventData struct {
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Damage int    `json:"damage"`
+}
+
+// HazardSetEventData for hazard_set event
+type HazardSetEventData struct {
+	Side   string `json:"side"` // Player whose side of the field holds the hazard
+	Hazard string `json:"hazard"`
+	Layers int    `json:"layers"`
+}
+
+// HazardDamageEventData for hazard_damage event
+type HazardDamageEventData struct {
+	Target string `json:"target"`
+	Hazard string `json:"hazard"`
+	Damage int    `json:"damage"`
+}
+
+// HazardClearedEventData for hazard_cleared event
+type HazardClearedEventData struct {
+	Side   string `json:"side"`
+	Hazard string `json:"hazard"`
+}
+
+// TerrainEventData for terrain_set and terrain_ended events
+type TerrainEventData struct {
+	Terrain string `json:"terrain"`
+}
+
+// ItemUsedEventData for item_used event
+type ItemUsedEventData struct {
+	ItemID      string `json:"item_id"`
+	Target      string `json:"target"`
+	Healed      int    `json:"healed,omitempty"`
+	CuredStatus string `json:"cured_status,omitempty"`
+}
+
 // CreatureFaintedEventData for creature_fainted event
 type CreatureFaintedEventData struct {
 	CreatureID string `json:"creature_id"`
@@ -389,9 +950,10 @@ type CreatureSwitchedEventData struct {
 
 // StatChangedEventData for stat_changed event
 type StatChangedEventData struct {
-	Target string `json:"target"`
-	Stat   string `json:"stat"`
-	Stages int    `json:"stages"` // positive or negative
+	Target    string `json:"target"`
+	Stat      string `json:"stat"`
+	Stages    int    `json:"stages"` // positive or negative
+	Secondary bool   `json:"secondary,omitempty"`
 }
 
 // MoveFailedEventData for move_failed event
@@ -413,16 +975,89 @@ type GameEndReason string
 const (
 	GameEndReasonVictory            GameEndReason = "victory"
 	GameEndReasonForfeit            GameEndReason = "forfeit"
+	GameEndReasonDraw               GameEndReason = "draw"
 	GameEndReasonOpponentDisconnect GameEndReason = "opponent_disconnect"
 	GameEndReasonTimeout            GameEndReason = "timeout"
+	GameEndReasonTurnLimit          GameEndReason = "turn_limit"
+	GameEndReasonEndlessBattle      GameEndReason = "endless_battle"
 )
 
 // GameEndedPayload announces game conclusion
 type GameEndedPayload struct {
-	WinnerID    string            `json:"winner_id"`
-	LoserID     string            `json:"loser_id"`
-	Reason      GameEndReason     `json:"reason"`
-	FinalState  *GameStatePayload `json:"final_state,omitempty"`
+	WinnerID    string                     `json:"winner_id"` // Empty for a draw, as is LoserID
+	LoserID     string                     `json:"loser_id"`
+	Reason      GameEndReason              `json:"reason"`
+	Draw        bool                       `json:"draw,omitempty"`
+	FinalState  *GameStatePayload          `json:"final_state,omitempty"`
+	Seed        int64                      `json:"seed"`         // Battle RNG seed, for deterministic re-simulation
+	SeedSalt    string                     `json:"seed_salt"`    // Salt of the seed commitment sent in game_started
+	DataVersion string                     `json:"data_version"` // Game data pack the battle was played with
+	ReplayID    string                     `json:"replay_id,omitempty"`
+	Series      *SeriesInfo                `json:"series,omitempty"` // Series score including this game, for best-of-N lobbies
+	Stats       map[string]BattleStatsInfo `json:"stats"`            // Each player's battle summary, keyed by player ID
+}
+
+// BattleStatsInfo summarises a player's battle for the end screen
+type BattleStatsInfo struct {
+	DamageDealt   int    `json:"damage_dealt"` // HP their moves took from opposing creatures
+	DamageTaken   int    `json:"damage_taken"` // HP their creatures lost, from any source
+	KOs           int    `json:"kos"`
+	TurnsSurvived int    `json:"turns_survived"` // Turns they ended with a creature still standing
+	MostUsedMove  string `json:"most_used_move,omitempty"`
+}
+
+// SeriesInfo is the score of a best-of-N series
+type SeriesInfo struct {
+	BestOf      int            `json:"best_of"`
+	GamesPlayed int            `json:"games_played"`
+	Wins        map[string]int `json:"wins"`
+}
+
+// SeriesEndedPayload announces the winner of a best-of-N series after its final game
+type SeriesEndedPayload struct {
+	WinnerID string     `json:"winner_id"`
+	LoserID  string     `json:"loser_id"`
+	Series   SeriesInfo `json:"series"`
+}
+
+// DrawOfferedPayload notifies both players of a draw offer
+type DrawOfferedPayload struct {
+	PlayerID string `json:"player_id"`
+}
+
+// DrawDeclinedPayload notifies both players that a draw offer was declined
+type DrawDeclinedPayload struct {
+	PlayerID string `json:"player_id"` // The player who declined
+}
+
+// PauseRequestedPayload notifies both players of a pause request
+type PauseRequestedPayload struct {
+	PlayerID          string `json:"player_id"`
+	BudgetRemainingMs int64  `json:"budget_remaining_ms"` // Pause time the requesting player has left
+}
+
+// PauseDeclinedPayload notifies both players that a pause request was declined
+type PauseDeclinedPayload struct {
+	PlayerID string `json:"player_id"` // The player who declined
+}
+
+// BattlePausedPayload announces that the battle is paused and every timer frozen
+type BattlePausedPayload struct {
+	PlayerID          string `json:"player_id"` // The player whose pause budget is being used
+	Reason            string `json:"reason"`    // "requested" or "disconnect"
+	BudgetRemainingMs int64  `json:"budget_remaining_ms"`
+	ExpiresAt         int64  `json:"expires_at"` // Unix ms at which the budget runs out and the battle starts resuming
+}
+
+// BattleResumingPayload announces the countdown to a paused battle resuming
+type BattleResumingPayload struct {
+	CountdownSec int   `json:"countdown_sec"`
+	ResumesAt    int64 `json:"resumes_at"` // Unix ms
+}
+
+// BattleResumedPayload announces that the battle and its timers are running again
+type BattleResumedPayload struct {
+	BudgetsRemainingMs map[string]int64 `json:"budgets_remaining_ms"` // Pause time each player has left, keyed by player ID
 }
 
 // RematchRequestedPayload notifies of rematch request
@@ -432,12 +1067,36 @@ type RematchRequestedPayload struct {
 
 // RematchStartingPayload announces rematch countdown
 type RematchStartingPayload struct {
-	StartsAt     int64 `json:"starts_at"`
-	CountdownSec int   `json:"countdown_sec"`
+	StartsAt     int64       `json:"starts_at"`
+	CountdownSec int         `json:"countdown_sec"`
+	Series       *SeriesInfo `json:"series,omitempty"`    // Score going into the next game, for best-of-N lobbies
+	NewTeams     bool        `json:"new_teams,omitempty"` // Players must submit new teams and ready up before the game starts
+}
+
+// ChatMessageBroadcastPayload relays a chat message to everyone in the lobby, including its sender
+type ChatMessageBroadcastPayload struct {
+	SenderID  string `json:"sender_id"`
+	Username  string `json:"username"`
+	Spectator bool   `json:"spectator"`
+	Text      string `json:"text"`
+	SentAt    int64  `json:"sent_at"` // Unix ms
 }
 
+// DisconnectWarningReason explains why the server is about to disconnect a client
+type DisconnectWarningReason string
+
+const (
+	// The client fell too far behind on its messages; it should reconnect with last_seq to have the rest replayed
+	DisconnectWarningReasonSlowConsumer DisconnectWarningReason = "slow_consumer"
+)
+
 // DisconnectWarningPayload warns of impending disconnect
 type DisconnectWarningPayload struct {
-	Reason   string `json:"reason"`
-	TimeoutAt int64 `json:"timeout_at"`
+	Reason    DisconnectWarningReason `json:"reason"`
+	TimeoutAt int64                   `json:"timeout_at"`
+}
+
+// ServerShutdownPayload is sent to every client before the server closes their connection to shut down
+type ServerShutdownPayload struct {
+	ReconnectAfterMs int64 `json:"reconnect_after_ms"` // How long to wait before reconnecting, to give the server time to come back
 }
@@ -32,10 +32,21 @@ func TestMessageTypes(t *testing.T) {
 		TypeHeartbeat,
 		TypeRequestLobbyState,
 		TypeSetReady,
+		TypeSubmitTeam,
+		TypeSubmitPick,
+		TypeChooseLead,
 		TypeSubmitAction,
 		TypeRequestGameState,
+		TypeOfferDraw,
+		TypeRespondDraw,
+		TypeRequestPause,
+		TypeRespondPause,
+		TypeRequestResume,
 		TypeRequestRematch,
 		TypeLeaveGame,
+		TypeChatMessage,
+		TypeAck,
+		TypeResyncRequest,
 	}
 
 	serverToClient := []MessageType{
@@ -44,15 +55,31 @@ func TestMessageTypes(t *testing.T) {
 		TypeLobbyUpdated,
 		TypeGameStarting,
 		TypeGameStarted,
+		TypeDraftState,
+		TypeLobbyClosed,
+		TypeTeamPreview,
 		TypeGameState,
+		TypeGameStateDelta,
 		TypeActionAcknowledged,
+		TypeOpponentCommitted,
 		TypeTurnResult,
 		TypeSwitchRequired,
 		TypeGameEnded,
+		TypeDrawOffered,
+		TypeDrawDeclined,
+		TypePauseRequested,
+		TypePauseDeclined,
+		TypeBattlePaused,
+		TypeBattleResuming,
+		TypeBattleResumed,
 		TypeRematchRequested,
 		TypeRematchStarting,
+		TypeSeriesEnded,
+		TypeResync,
+		TypeLobbyEvents,
 		TypeError,
 		TypeDisconnectWarning,
+		TypeServerShutdown,
 	}
 
 	for _, msgType := range clientToServer {
@@ -79,6 +106,10 @@ func TestErrorCodeRecoverability(t *testing.T) {
 		ErrCodeNotYourTurn,
 		ErrCodeTurnMismatch,
 		ErrCodeMalformedMessage,
+		ErrCodeSpectatorOnly,
+		ErrCodeRateLimited,
+		ErrCodeRequestTimeout,
+		ErrCodePayloadTooLarge,
 	}
 
 	nonRecoverableCodes := []ErrorCode{
@@ -206,6 +237,9 @@ func TestLobbyEventTypes(t *testing.T) {
 		LobbyEventPlayerReadyChanged,
 		LobbyEventHostChanged,
 		LobbyEventStateChanged,
+		LobbyEventSettingsChanged,
+		LobbyEventSpectatorJoined,
+		LobbyEventSpectatorLeft,
 	}
 
 	for _, event := range events {
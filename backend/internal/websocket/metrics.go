@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxMetricTypes caps how many message types are counted separately, so clients sending made-up types
+// cannot grow the counters without bound; any beyond it are counted as otherMessageType
+const maxMetricTypes = 128
+
+// otherMessageType is what messages of types beyond maxMetricTypes are counted as
+const otherMessageType MessageType = "other"
+
+// maxClientVersions caps how many client versions are counted separately, as maxMetricTypes does message types;
+// any beyond it are counted as otherClientVersion, and clients that declare none as unknownClientVersion
+const maxClientVersions = 64
+
+const (
+	otherClientVersion   = "other"
+	unknownClientVersion = "unknown"
+)
+
+// Histogram bucket upper bounds
+var (
+	fanOutBuckets    = []float64{0, 1, 2, 4, 8, 16, 32, 64, 128}
+	latencyBuckets   = []float64{0.1, 0.5, 1, 5, 10, 50, 100, 500, 1000} // Milliseconds
+	occupancyBuckets = []float64{0, 1, 4, 16, 64, 128, 256, DefaultSendBufferSize}
+)
+
+// hubMetrics counts the messages through a hub and measures how its sends perform
+type hubMetrics struct {
+	mu           sync.Mutex
+	received     map[MessageType]int64
+	sent         map[MessageType]int64
+	authTimeouts int64 // Connections closed for not authenticating in time
+	dropped      int64 // Queued messages dropped because the connection's codec could not encode them
+
+	clientVersions map[string]int64 // Authentications by the client_version declared
+
+	fanOut           *histogram // Connections each broadcast reached
+	broadcastLatency *histogram // From a broadcast being made to every connection having it queued
+	writeLatency     *histogram // Encoding and writing a message to the socket
+	bufferOccupancy  *histogram // Messages already waiting in a connection's send buffer when another is queued
+}
+
+// MetricsSnapshot is a copy of a hub's metrics at one moment
+type MetricsSnapshot struct {
+	MessagesReceived   map[MessageType]int64 `json:"messages_received"`
+	MessagesSent       map[MessageType]int64 `json:"messages_sent"`
+	AuthTimeouts       int64                 `json:"auth_timeouts"`
+	MessagesDropped    int64                 `json:"messages_dropped"`
+	ClientVersions     map[string]int64      `json:"client_versions"`
+	BroadcastFanOut    HistogramSnapshot     `json:"broadcast_fan_out"`
+	BroadcastLatencyMs HistogramSnapshot     `json:"broadcast_latency_ms"`
+	WriteLatencyMs     HistogramSnapshot     `json:"write_latency_ms"`
+	SendBufferMessages HistogramSnapshot     `json:"send_buffer_messages"`
+}
+
+// newHubMetrics creates empty hub metrics
+func newHubMetrics() *hubMetrics {
+	return &hubMetrics{
+		received:         make(map[MessageType]int64),
+		sent:             make(map[MessageType]int64),
+		clientVersions:   make(map[string]int64),
+		fanOut:           newHistogram(fanOutBuckets),
+		broadcastLatency: newHistogram(latencyBuckets),
+		writeLatency:     newHistogram(latencyBuckets),
+		bufferOccupancy:  newHistogram(occupancyBuckets),
+	}
+}
+
+// countReceived counts a message from a client
+func (m *hubMetrics) countReceived(msgType MessageType) {
+	m.count(m.received, msgType, 1)
+}
+
+// countSent counts messages of a type queued for clients
+func (m *hubMetrics) countSent(msgType MessageType, n int) {
+	m.count(m.sent, msgType, n)
+}
+
+// countAuthTimeout counts a connection closed for not authenticating in time
+func (m *hubMetrics) countAuthTimeout() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authTimeouts++
+}
+
+// countDropped counts a queued message dropped because it could not be encoded
+func (m *hubMetrics) countDropped() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped++
+}
+
+// countClientVersion counts a connection authenticating with the client version it declared, "" if none
+func (m *hubMetrics) countClientVersion(version string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version == "" {
+		version = unknownClientVersion
+	}
+	if _, ok := m.clientVersions[version]; !ok && len(m.clientVersions) >= maxClientVersions {
+		version = otherClientVersion
+	}
+	m.clientVersions[version]++
+}
+
+// count adds n to a message type's counter
+func (m *hubMetrics) count(counters map[MessageType]int64, msgType MessageType, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := counters[msgType]; !ok && len(counters) >= maxMetricTypes {
+		msgType = otherMessageType
+	}
+	counters[msgType] += int64(n)
+}
+
+// snapshot copies the metrics
+func (m *hubMetrics) snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	received := make(map[MessageType]int64, len(m.received))
+	for msgType, n := range m.received {
+		received[msgType] = n
+	}
+	sent := make(map[MessageType]int64, len(m.sent))
+	for msgType, n := range m.sent {
+		sent[msgType] = n
+	}
+	authTimeouts := m.authTimeouts
+	dropped := m.dropped
+	clientVersions := make(map[string]int64, len(m.clientVersions))
+	for version, n := range m.clientVersions {
+		clientVersions[version] = n
+	}
+	m.mu.Unlock()
+
+	return MetricsSnapshot{
+		MessagesReceived:   received,
+		MessagesSent:       sent,
+		AuthTimeouts:       authTimeouts,
+		MessagesDropped:    dropped,
+		ClientVersions:     clientVersions,
+		BroadcastFanOut:    m.fanOut.snapshot(),
+		BroadcastLatencyMs: m.broadcastLatency.snapshot(),
+		WriteLatencyMs:     m.writeLatency.snapshot(),
+		SendBufferMessages: m.bufferOccupancy.snapshot(),
+	}
+}
+
+// histogram counts observations into buckets by upper bound, with one more for anything above the last
+type histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+// HistogramSnapshot is a copy of a histogram. Counts[i] is how many observations were at most Bounds[i],
+// and not within an earlier bucket; the last count is of those above every bound.
+type HistogramSnapshot struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+}
+
+// newHistogram creates an empty histogram with the given ascending bucket bounds
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]int64, len(bounds)+1)}
+}
+
+// observe records a value
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for i < len(h.bounds) && value > h.bounds[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += value
+}
+
+// observeSince records the milliseconds since start
+func (h *histogram) observeSince(start time.Time) {
+	h.observe(float64(time.Since(start).Microseconds()) / 1000)
+}
+
+// snapshot copies the histogram
+func (h *histogram) snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return HistogramSnapshot{
+		Bounds: append([]float64(nil), h.bounds...),
+		Counts: append([]int64(nil), h.counts...),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
+
+// Metrics returns the hub's message counts by type, broadcast fan-out and latency, write latency
+// and send buffer occupancy since it was created
+func (h *Hub) Metrics() MetricsSnapshot {
+	return h.metrics.snapshot()
+}
+
+// HandleMetrics serves the hub's metrics as JSON
+func (h *Handler) HandleMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.Metrics())
+}
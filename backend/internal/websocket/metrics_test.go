@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"fmt"
+	"testing"
+)
+
+// ========================================
+// Metrics Tests
+// ========================================
+
+func TestHistogram_Observe(t *testing.T) {
+	h := newHistogram([]float64{1, 5, 10})
+	for _, value := range []float64{0, 1, 3, 5, 7, 100} {
+		h.observe(value)
+	}
+
+	snapshot := h.snapshot()
+	want := []int64{2, 2, 1, 1}
+	for i, count := range want {
+		if snapshot.Counts[i] != count {
+			t.Fatalf("expected counts %v, got %v", want, snapshot.Counts)
+		}
+	}
+	if snapshot.Count != 6 || snapshot.Sum != 116 {
+		t.Errorf("expected 6 observations summing to 116, got %d summing to %v", snapshot.Count, snapshot.Sum)
+	}
+}
+
+func TestHubMetrics_TypeCap(t *testing.T) {
+	m := newHubMetrics()
+	for i := 0; i < maxMetricTypes+10; i++ {
+		m.countReceived(MessageType(fmt.Sprintf("made_up_%d", i)))
+	}
+	m.countReceived("made_up_0")
+
+	received := m.snapshot().MessagesReceived
+	if len(received) != maxMetricTypes+1 {
+		t.Errorf("expected %d types counted separately and the rest together, got %d", maxMetricTypes, len(received))
+	}
+	if received[otherMessageType] != 10 || received["made_up_0"] != 2 {
+		t.Errorf("expected 10 other messages and 2 of made_up_0, got %d and %d", received[otherMessageType], received["made_up_0"])
+	}
+}
+
+func TestHubMetrics_ClientVersionCap(t *testing.T) {
+	m := newHubMetrics()
+	for i := 0; i < maxClientVersions+5; i++ {
+		m.countClientVersion(fmt.Sprintf("web/1.%d", i))
+	}
+	m.countClientVersion("web/1.0")
+	m.countClientVersion("")
+
+	versions := m.snapshot().ClientVersions
+	if versions[otherClientVersion] != 6 || versions["web/1.0"] != 2 {
+		t.Errorf("expected 6 other versions and 2 of web/1.0, got %d and %d", versions[otherClientVersion], versions["web/1.0"])
+	}
+	if _, ok := versions[unknownClientVersion]; ok {
+		t.Errorf("expected clients declaring no version to fall under other once the cap is reached, got %+v", versions)
+	}
+}
@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OriginPolicy decides which browser origins may open a websocket. It takes the same origins as CORS:
+// exact origins such as "https://example.com", or a "*" wildcard for subdomains such as "https://*.example.com".
+// Requests without an Origin header do not come from a browser page and are always allowed.
+type OriginPolicy struct {
+	AllowedOrigins []string
+	AllowAny       bool // Dev-mode bypass accepting every origin
+}
+
+// SetOriginPolicy sets which origins may open a websocket
+func (h *Handler) SetOriginPolicy(policy OriginPolicy) {
+	h.originPolicy = policy
+}
+
+// checkOrigin reports whether the upgrade request's origin is allowed by the handler's policy
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || h.originPolicy.AllowAny {
+		return true
+	}
+	for _, allowed := range h.originPolicy.AllowedOrigins {
+		if originMatches(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// originMatches reports whether origin is the allowed origin or, for a wildcard, one of its subdomains.
+// Schemes and hosts are compared case-insensitively and ports exactly.
+func originMatches(allowed, origin string) bool {
+	want, err := url.Parse(strings.ToLower(strings.TrimSpace(allowed)))
+	if err != nil {
+		return false
+	}
+	got, err := url.Parse(strings.ToLower(origin))
+	if err != nil || got.Scheme != want.Scheme {
+		return false
+	}
+	if domain, ok := strings.CutPrefix(want.Host, "*."); ok {
+		return strings.HasSuffix(got.Host, "."+domain)
+	}
+	return got.Host == want.Host
+}
@@ -0,0 +1,31 @@
+package websocket
+
+import "testing"
+
+// ========================================
+// Origin Tests
+// ========================================
+
+func TestOriginMatches(t *testing.T) {
+	tests := []struct {
+		allowed string
+		origin  string
+		want    bool
+	}{
+		{"http://localhost:5173", "http://localhost:5173", true},
+		{"http://localhost:5173", "http://localhost:3000", false},
+		{"http://localhost:5173", "https://localhost:5173", false},
+		{"https://Example.com", "https://example.COM", true},
+		{"https://example.com", "https://evil-example.com", false},
+		{"https://*.example.com", "https://play.example.com", true},
+		{"https://*.example.com", "https://eu.play.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "http://play.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := originMatches(tt.allowed, tt.origin); got != tt.want {
+			t.Errorf("originMatches(%q, %q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package websocket
+
+import (
+	"errors"
+	"time"
+
+	"poke-battles/internal/game"
+	"poke-battles/internal/services"
+)
+
+// defaultResumeCountdown is how long players are warned before a paused battle resumes
+const defaultResumeCountdown = 3 * time.Second
+
+// defaultDisconnectGrace is how long a player who drops out of a battle has to return before their opponent wins
+const defaultDisconnectGrace = 2 * time.Minute
+
+// pauseTimer resumes a paused game: first when the paused player's budget runs out,
+// then at the end of the resume countdown
+type pauseTimer struct {
+	timer    *time.Timer
+	resuming bool // The resume countdown is running
+}
+
+// handleRequestPause asks the opponent to pause the battle and announces the request to both players
+func (h *Handler) handleRequestPause(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		if err := h.battleService.RequestPause(battle.ID, conn.PlayerID()); err != nil {
+			sendPauseError(conn, env, err, "Failed to request pause")
+			return
+		}
+
+		h.hub.BroadcastToLobby(lobbyCode, TypePauseRequested, PauseRequestedPayload{
+			PlayerID:          conn.PlayerID(),
+			BudgetRemainingMs: battle.PauseBudget(conn.PlayerID(), time.Now()).Milliseconds(),
+		})
+		h.answerBotPauseRequests(lobbyCode, battle)
+	})
+}
+
+// handleRespondPause answers the opponent's pause request, pausing the battle on acceptance
+func (h *Handler) handleRespondPause(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload RespondPausePayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		pause, err := h.battleService.RespondPause(battle.ID, conn.PlayerID(), payload.Accept)
+		if err != nil {
+			sendPauseError(conn, env, err, "Failed to respond to pause")
+			return
+		}
+
+		if pause == nil {
+			h.hub.BroadcastToLobby(lobbyCode, TypePauseDeclined, PauseDeclinedPayload{PlayerID: conn.PlayerID()})
+			return
+		}
+		h.startPause(lobbyCode, battle, pause)
+	})
+}
+
+// handleRequestResume ends an agreed pause early. Either player may resume it;
+// a pause for a disconnected player lasts until they return or their budget runs out.
+func (h *Handler) handleRequestResume(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	h.dispatchToLobbyGame(conn, env, func(battle *game.Battle) {
+		pause := battle.Paused()
+		switch {
+		case !battle.HasPlayer(conn.PlayerID()):
+			conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+		case pause == nil:
+			conn.SendError(ErrCodeInvalidState, "Battle is not paused", env.CorrelationID)
+		case pause.Reason != game.PauseReasonRequested:
+			conn.SendError(ErrCodeInvalidState, "Battle is paused until the disconnected player returns", env.CorrelationID)
+		case h.pauseResuming(battle.ID):
+			conn.SendError(ErrCodeInvalidState, "Battle is already resuming", env.CorrelationID)
+		default:
+			h.startResumeCountdown(lobbyCode, battle.ID)
+		}
+	})
+}
+
+// sendPauseError reports why a pause request or response was rejected
+func sendPauseError(conn *Connection, env *Envelope, err error, fallback string) {
+	switch {
+	case errors.Is(err, game.ErrPauseAlreadyRequested):
+		conn.SendError(ErrCodeInvalidState, "Pause already requested", env.CorrelationID)
+	case errors.Is(err, game.ErrNoPauseRequest):
+		conn.SendError(ErrCodeInvalidState, "No pause request to respond to", env.CorrelationID)
+	case errors.Is(err, game.ErrBattlePaused):
+		conn.SendError(ErrCodeInvalidState, "Battle is already paused", env.CorrelationID)
+	case errors.Is(err, game.ErrPauseBudgetExhausted):
+		conn.SendError(ErrCodeInvalidState, "No pause time left", env.CorrelationID)
+	case errors.Is(err, game.ErrBattleOver), errors.Is(err, services.ErrBattleNotFound):
+		conn.SendError(ErrCodeInvalidState, "Battle is already over", env.CorrelationID)
+	case errors.Is(err, game.ErrPlayerNotInBattle):
+		conn.SendError(ErrCodePlayerNotInLobby, "Player not in battle", env.CorrelationID)
+	default:
+		conn.SendError(ErrCodeInternalError, fallback, env.CorrelationID)
+	}
+}
+
+// pauseForDisconnect pauses the lobby's battle for a player who lost their connection and starts their grace period.
+// A player who has already reconnected is left alone, and one with no pause time left is not given a pause.
+func (h *Handler) pauseForDisconnect(lobbyCode, playerID string) {
+	battle, err := h.battleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	h.battleService.Dispatch(battle.ID, func(battle *game.Battle) {
+		if h.hub.IsPlayerConnected(playerID) || !battle.HasPlayer(playerID) || battle.Outcome() != nil {
+			return
+		}
+		h.startDisconnectGrace(lobbyCode, battle, playerID)
+
+		pause, err := h.battleService.PauseForDisconnect(battle.ID, playerID)
+		if err != nil {
+			return
+		}
+		h.startPause(lobbyCode, battle, pause)
+	})
+}
+
+// resumeAfterReconnect starts resuming the lobby's battle if it was paused for the returning player
+func (h *Handler) resumeAfterReconnect(lobbyCode, playerID string) {
+	battle, err := h.battleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	h.battleService.Dispatch(battle.ID, func(battle *game.Battle) {
+		pause := battle.Paused()
+		if pause == nil || pause.Reason != game.PauseReasonDisconnect || pause.PlayerID != playerID || h.pauseResuming(battle.ID) {
+			return
+		}
+		h.startResumeCountdown(lobbyCode, battle.ID)
+	})
+}
+
+// startDisconnectGrace tells the player's opponent they dropped out and, unless they return in time,
+// ends the battle in the opponent's favour once the grace period runs out
+func (h *Handler) startDisconnectGrace(lobbyCode string, battle *game.Battle, playerID string) {
+	gameID := battle.ID
+	expiresAt := time.Now().Add(h.disconnectGrace)
+
+	h.timersMu.Lock()
+	if t, exists := h.disconnectTimers[playerID]; exists {
+		t.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(h.disconnectGrace, func() {
+		h.battleService.Dispatch(gameID, func(battle *game.Battle) {
+			h.timersMu.Lock()
+			current := h.disconnectTimers[playerID] == timer
+			if current {
+				delete(h.disconnectTimers, playerID)
+			}
+			h.timersMu.Unlock()
+			if !current || h.hub.IsPlayerConnected(playerID) {
+				return
+			}
+
+			h.battleService.ForfeitForDisconnect(gameID, playerID)
+		})
+	})
+	h.disconnectTimers[playerID] = timer
+	h.timersMu.Unlock()
+
+	for _, side := range battle.Sides {
+		if side.PlayerID != playerID {
+			h.hub.SendToPlayer(side.PlayerID, TypeOpponentDisconnected, OpponentDisconnectedPayload{
+				PlayerID:       playerID,
+				GraceExpiresAt: expiresAt.UnixMilli(),
+			})
+		}
+	}
+}
+
+// stopDisconnectTimer cancels a player's grace period, once they return or their battle ends
+func (h *Handler) stopDisconnectTimer(playerID string) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	if t, exists := h.disconnectTimers[playerID]; exists {
+		t.Stop()
+		delete(h.disconnectTimers, playerID)
+	}
+}
+
+// startPause freezes the battle's switch timers, announces the pause and schedules the battle
+// to start resuming once the paused player's budget runs out
+func (h *Handler) startPause(lobbyCode string, battle *game.Battle, pause *game.Pause) {
+	h.freezeSwitchTimers(battle)
+
+	budget := battle.PauseBudget(pause.PlayerID, time.Now())
+	h.timersMu.Lock()
+	if t, exists := h.pauseTimers[battle.ID]; exists {
+		t.timer.Stop()
+	}
+	gameID := battle.ID
+	h.pauseTimers[gameID] = &pauseTimer{timer: time.AfterFunc(budget, func() {
+		h.battleService.Dispatch(gameID, func(battle *game.Battle) {
+			if battle.Paused() != nil && !h.pauseResuming(gameID) {
+				h.startResumeCountdown(lobbyCode, gameID)
+			}
+		})
+	})}
+	h.timersMu.Unlock()
+
+	h.hub.BroadcastToLobby(lobbyCode, TypeBattlePaused, BattlePausedPayload{
+		PlayerID:          pause.PlayerID,
+		Reason:            string(pause.Reason),
+		BudgetRemainingMs: budget.Milliseconds(),
+		ExpiresAt:         time.Now().Add(budget).UnixMilli(),
+	})
+}
+
+// startResumeCountdown announces that the battle resumes shortly and resumes it when the countdown ends
+func (h *Handler) startResumeCountdown(lobbyCode, gameID string) {
+	resumesAt := time.Now().Add(h.resumeCountdown)
+
+	h.timersMu.Lock()
+	if t, exists := h.pauseTimers[gameID]; exists {
+		t.timer.Stop()
+	}
+	h.pauseTimers[gameID] = &pauseTimer{resuming: true, timer: time.AfterFunc(h.resumeCountdown, func() {
+		h.battleService.Dispatch(gameID, func(battle *game.Battle) {
+			h.resume(lobbyCode, battle)
+		})
+	})}
+	h.timersMu.Unlock()
+
+	h.hub.BroadcastToLobby(lobbyCode, TypeBattleResuming, BattleResumingPayload{
+		CountdownSec: int(h.resumeCountdown / time.Second),
+		ResumesAt:    resumesAt.UnixMilli(),
+	})
+}
+
+// resume ends the battle's pause, restarts its frozen switch timers and lets any bot act
+func (h *Handler) resume(lobbyCode string, battle *game.Battle) {
+	h.stopPauseTimer(battle.ID)
+	if err := h.battleService.Resume(battle.ID); err != nil {
+		return
+	}
+
+	now := time.Now()
+	budgets := make(map[string]int64, len(battle.Sides))
+	for _, side := range battle.Sides {
+		budgets[side.PlayerID] = battle.PauseBudget(side.PlayerID, now).Milliseconds()
+	}
+	h.hub.BroadcastToLobby(lobbyCode, TypeBattleResumed, BattleResumedPayload{BudgetsRemainingMs: budgets})
+
+	h.thawSwitchTimers(battle)
+	h.playBots(lobbyCode, battle)
+}
+
+// pauseResuming reports whether a game's resume countdown is running
+func (h *Handler) pauseResuming(gameID string) bool {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	t, exists := h.pauseTimers[gameID]
+	return exists && t.resuming
+}
+
+// stopPauseTimer cancels a game's pending resume
+func (h *Handler) stopPauseTimer(gameID string) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	if t, exists := h.pauseTimers[gameID]; exists {
+		t.timer.Stop()
+		delete(h.pauseTimers, gameID)
+	}
+}
+
+// freezeSwitchTimers stops the battle's pending auto-switches, keeping the time each player had left
+func (h *Handler) freezeSwitchTimers(battle *game.Battle) {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	for _, side := range battle.Sides {
+		t, exists := h.switchTimers[side.PlayerID]
+		if !exists || t.timer == nil {
+			continue
+		}
+		t.timer.Stop()
+		t.timer = nil
+		t.remaining = max(time.Until(t.deadline), 0)
+	}
+}
+
+// thawSwitchTimers restarts the battle's frozen auto-switches with the time each player had left,
+// and sends those players a fresh switch_required with their new deadline
+func (h *Handler) thawSwitchTimers(battle *game.Battle) {
+	for _, playerID := range battle.PendingSwitches() {
+		h.timersMu.Lock()
+		t, exists := h.switchTimers[playerID]
+		if !exists || t.timer != nil {
+			h.timersMu.Unlock()
+			continue
+		}
+		t.deadline = time.Now().Add(t.remaining)
+		t.timer = time.AfterFunc(t.remaining, t.fn)
+		h.timersMu.Unlock()
+
+		slots, err := battle.SwitchTargets(playerID)
+		if err != nil {
+			continue
+		}
+		h.hub.SendToPlayer(playerID, TypeSwitchRequired, SwitchRequiredPayload{
+			Reason:         "fainted",
+			AvailableSlots: slots,
+			TimeoutAt:      t.deadline.UnixMilli(),
+		})
+	}
+}
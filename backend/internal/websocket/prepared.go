@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// preparedEnvelope is an envelope marshaled once to be sent to any number of connections. Only the seq
+// and correlation ID differ between them, so they are spliced in between the shared header and payload.
+type preparedEnvelope struct {
+	msgType  MessageType
+	head     []byte // The opening brace through the timestamp
+	lobbySeq int64  // The same for every connection, but only known once the broadcast is sent
+	tail     []byte // The payload through the closing brace
+}
+
+// prepareEnvelope marshals the envelope for sending, leaving out its seq, lobby seq and correlation ID
+func prepareEnvelope(env *Envelope) (*preparedEnvelope, error) {
+	msgType, err := json.Marshal(env.Type)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(env.Payload) // Compacts the payload, and turns a missing one into null
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 0, len(msgType)+64)
+	head = append(head, `{"type":`...)
+	head = append(head, msgType...)
+	head = append(head, `,"version":`...)
+	head = strconv.AppendInt(head, int64(env.Version), 10)
+	head = append(head, `,"timestamp":`...)
+	head = strconv.AppendInt(head, env.Timestamp, 10)
+
+	tail := make([]byte, 0, len(payload)+len(`,"payload":}`))
+	tail = append(tail, `,"payload":`...)
+	tail = append(tail, payload...)
+	tail = append(tail, '}')
+
+	return &preparedEnvelope{msgType: env.Type, head: head, lobbySeq: env.LobbySeq, tail: tail}, nil
+}
+
+// withLobbySeq returns a copy of the message numbered as the lobby's broadcast
+func (p *preparedEnvelope) withLobbySeq(lobbySeq int64) *preparedEnvelope {
+	numbered := *p
+	numbered.lobbySeq = lobbySeq
+	return &numbered
+}
+
+// marshal returns the envelope with the seq and correlation ID, exactly as json.Marshal would encode it
+func (p *preparedEnvelope) marshal(seq int64, correlationID string) []byte {
+	data := make([]byte, 0, len(p.head)+len(p.tail)+len(correlationID)+48)
+	data = append(data, p.head...)
+	if correlationID != "" {
+		id, _ := json.Marshal(correlationID) // Encoding a string cannot fail
+		data = append(data, `,"correlation_id":`...)
+		data = append(data, id...)
+	}
+	if seq != 0 {
+		data = append(data, `,"seq":`...)
+		data = strconv.AppendInt(data, seq, 10)
+	}
+	if p.lobbySeq != 0 {
+		data = append(data, `,"lobby_seq":`...)
+		data = strconv.AppendInt(data, p.lobbySeq, 10)
+	}
+	return append(data, p.tail...)
+}
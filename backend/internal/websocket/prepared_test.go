@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// benchmarkLobbySize is how many connections the broadcast benchmarks send to
+const benchmarkLobbySize = 8
+
+// benchmarkLobbyUpdate is a lobby update of a full lobby, a typical broadcast
+func benchmarkLobbyUpdate() LobbyUpdatedPayload {
+	info := LobbyInfo{Code: "ABC123", State: "waiting", Ruleset: "standard", BestOf: 1, Tags: []string{"casual"}}
+	for i := 0; i < benchmarkLobbySize; i++ {
+		info.Players = append(info.Players, LobbyPlayerInfo{ID: fmt.Sprintf("player-%d", i), Username: fmt.Sprintf("Player%d", i), IsConnected: true})
+	}
+	data, _ := json.Marshal(PlayerReadyChangedEventData{PlayerID: "player-1", Ready: true})
+	return LobbyUpdatedPayload{Lobby: info, Event: LobbyEventPlayerReadyChanged, EventData: data}
+}
+
+// ========================================
+// Prepared Envelope Tests
+// ========================================
+
+func TestPreparedEnvelope_MatchesMarshal(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       interface{}
+		seq           int64
+		lobbySeq      int64
+		correlationID string
+	}{
+		{"seq and correlation", ChatMessagePayload{Text: "gg <3 & \"thanks\""}, 42, 0, "req-1"},
+		{"no seq", HeartbeatAckPayload{}, 0, 0, "req-2"},
+		{"no correlation", benchmarkLobbyUpdate(), 7, 0, ""},
+		{"escaped correlation", nil, 1, 0, "<script>\"\\"},
+		{"lobby seq", benchmarkLobbyUpdate(), 7, 3, ""},
+		{"lobby seq without seq", HeartbeatAckPayload{}, 0, 12, "req-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewEnvelope(TypeChatMessage, tt.payload)
+			if err != nil {
+				t.Fatalf("failed to build envelope: %v", err)
+			}
+			msg, err := prepareEnvelope(env)
+			if err != nil {
+				t.Fatalf("failed to prepare envelope: %v", err)
+			}
+
+			msg = msg.withLobbySeq(tt.lobbySeq)
+
+			env.Seq = tt.seq
+			env.LobbySeq = tt.lobbySeq
+			env.CorrelationID = tt.correlationID
+			want, _ := json.Marshal(env)
+			if got := msg.marshal(tt.seq, tt.correlationID); !bytes.Equal(got, want) {
+				t.Errorf("prepared envelope differs from json.Marshal:\n got  %s\n want %s", got, want)
+			}
+		})
+	}
+}
+
+// BenchmarkBroadcast_MarshalPerConnection marshals the whole envelope for each connection, as broadcasts used to
+func BenchmarkBroadcast_MarshalPerConnection(b *testing.B) {
+	payload := benchmarkLobbyUpdate()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for seq := int64(1); seq <= benchmarkLobbySize; seq++ {
+			env, _ := NewEnvelopeWithSeq(TypeLobbyUpdated, seq, payload)
+			json.Marshal(env)
+		}
+	}
+}
+
+// BenchmarkBroadcast_Prepared marshals the envelope once and splices in each connection's seq
+func BenchmarkBroadcast_Prepared(b *testing.B) {
+	payload := benchmarkLobbyUpdate()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		env, _ := NewEnvelope(TypeLobbyUpdated, payload)
+		msg, _ := prepareEnvelope(env)
+		for seq := int64(1); seq <= benchmarkLobbySize; seq++ {
+			msg.marshal(seq, "")
+		}
+	}
+}
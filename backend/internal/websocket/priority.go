@@ -0,0 +1,89 @@
+package websocket
+
+// sendPriority is the class a message is queued in on its way to the client. While the client keeps up,
+// every message is written as soon as it is sent. Once its send buffer backs up, critical messages are
+// written ahead of everything else waiting, and low priority ones only when nothing else is.
+type sendPriority int
+
+const (
+	// priorityNormal is for every message not given another class
+	priorityNormal sendPriority = iota
+
+	// priorityCritical is for the messages a battle cannot go on without
+	priorityCritical
+
+	// priorityLow is for chat and presence, which can wait behind everything else
+	priorityLow
+)
+
+// messagePriorities are the classes of the messages not sent at normal priority
+var messagePriorities = map[MessageType]sendPriority{
+	TypeTurnResult:     priorityCritical,
+	TypeSwitchRequired: priorityCritical,
+	TypeGameEnded:      priorityCritical,
+
+	TypeChatMessage:        priorityLow,
+	TypePlayerConnected:    priorityLow,
+	TypePlayerDisconnected: priorityLow,
+}
+
+// priorityOf returns the class a message of the type is queued in
+func priorityOf(msgType MessageType) sendPriority {
+	return messagePriorities[msgType]
+}
+
+// queueFor returns the channel messages of the priority wait on
+func (c *Connection) queueFor(priority sendPriority) chan []byte {
+	switch priority {
+	case priorityCritical:
+		return c.critical
+	case priorityLow:
+		return c.low
+	default:
+		return c.send
+	}
+}
+
+// bufferedLocked returns how many messages are waiting to be written, whatever their priority.
+// The caller must hold the lock.
+func (c *Connection) bufferedLocked() int {
+	return len(c.critical) + len(c.send) + len(c.low)
+}
+
+// nextQueued takes the next message to write without waiting for one: critical messages first, then normal
+// ones, then low priority ones. ok is false once the send channel has been closed, and queued is false if
+// nothing is waiting.
+func (c *Connection) nextQueued() (message []byte, ok, queued bool) {
+	select {
+	case message = <-c.critical:
+		return message, true, true
+	default:
+	}
+	select {
+	case message, ok = <-c.send:
+		return message, ok, true
+	default:
+	}
+	select {
+	case message = <-c.low:
+		return message, true, true
+	default:
+	}
+	return nil, true, false
+}
+
+// nextUnsent takes whatever critical or low priority message is left after the send channel was closed,
+// the critical ones first. ok is false once there are none.
+func (c *Connection) nextUnsent() (message []byte, ok bool) {
+	select {
+	case message = <-c.critical:
+		return message, true
+	default:
+	}
+	select {
+	case message = <-c.low:
+		return message, true
+	default:
+	}
+	return nil, false
+}
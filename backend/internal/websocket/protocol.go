@@ -0,0 +1,128 @@
+package websocket
+
+//go:generate go run ../../cmd/protocol-gen -src . -ts ../../../frontend/src/types/protocol.ts -schema ../../../frontend/src/types/protocol.schema.json
+
+// ProtocolMessage is a message type and the payload it carries, for describing the protocol to clients
+type ProtocolMessage struct {
+	Type    MessageType
+	Payload interface{} // The zero value of the payload
+}
+
+// ClientMessages lists every message clients can send, with its payload
+func ClientMessages() []ProtocolMessage {
+	return []ProtocolMessage{
+		{TypeAuthenticate, AuthenticatePayload{}},
+		{TypeHeartbeat, HeartbeatPayload{}},
+		{TypeTimeSync, TimeSyncPayload{}},
+		{TypeClaimPrimary, ClaimPrimaryPayload{}},
+		{TypeRequestLobbyState, RequestLobbyStatePayload{}},
+		{TypeSetReady, SetReadyPayload{}},
+		{TypeSubmitTeam, SubmitTeamPayload{}},
+		{TypeSubmitPick, SubmitPickPayload{}},
+		{TypeStartGame, StartGamePayload{}},
+		{TypeCancelGameStart, CancelGameStartPayload{}},
+		{TypeChooseLead, ChooseLeadPayload{}},
+		{TypeSubmitAction, SubmitActionPayload{}},
+		{TypeRequestGameState, RequestGameStatePayload{}},
+		{TypeOfferDraw, OfferDrawPayload{}},
+		{TypeRespondDraw, RespondDrawPayload{}},
+		{TypeRequestPause, RequestPausePayload{}},
+		{TypeRespondPause, RespondPausePayload{}},
+		{TypeRequestResume, RequestResumePayload{}},
+		{TypeRequestRematch, RequestRematchPayload{}},
+		{TypeLeaveGame, LeaveGamePayload{}},
+		{TypeChatMessage, ChatMessagePayload{}},
+		{TypeAck, AckPayload{}},
+		{TypeResyncRequest, ResyncRequestPayload{}},
+	}
+}
+
+// ServerMessages lists every message the server can send, with its payload
+func ServerMessages() []ProtocolMessage {
+	return []ProtocolMessage{
+		{TypeAuthenticated, AuthenticatedPayload{}},
+		{TypeHeartbeatAck, HeartbeatAckPayload{}},
+		{TypeTimeSyncResponse, TimeSyncResponsePayload{}},
+		{TypePrimaryChanged, PrimaryChangedPayload{}},
+		{TypeLobbyUpdated, LobbyUpdatedPayload{}},
+		{TypeGameStarting, GameStartingPayload{}},
+		{TypeGameStartCancelled, GameStartCancelledPayload{}},
+		{TypeGameStarted, GameStartedPayload{}},
+		{TypeDraftState, DraftStatePayload{}},
+		{TypeLobbyClosed, LobbyClosedPayload{}},
+		{TypeWaitlistPromoted, WaitlistPromotedPayload{}},
+		{TypePlayerConnected, PlayerConnectedPayload{}},
+		{TypePlayerDisconnected, PlayerDisconnectedPayload{}},
+		{TypeOpponentDisconnected, OpponentDisconnectedPayload{}},
+		{TypeTeamPreview, TeamPreviewPayload{}},
+		{TypeGameState, GameStatePayload{}},
+		{TypeGameStateDelta, GameStateDeltaPayload{}},
+		{TypeSpectatorState, SpectatorStatePayload{}},
+		{TypeActionAcknowledged, ActionAcknowledgedPayload{}},
+		{TypeOpponentCommitted, OpponentCommittedPayload{}},
+		{TypeTurnResult, TurnResultPayload{}},
+		{TypeSwitchRequired, SwitchRequiredPayload{}},
+		{TypeGameEnded, GameEndedPayload{}},
+		{TypeDrawOffered, DrawOfferedPayload{}},
+		{TypeDrawDeclined, DrawDeclinedPayload{}},
+		{TypePauseRequested, PauseRequestedPayload{}},
+		{TypePauseDeclined, PauseDeclinedPayload{}},
+		{TypeBattlePaused, BattlePausedPayload{}},
+		{TypeBattleResuming, BattleResumingPayload{}},
+		{TypeBattleResumed, BattleResumedPayload{}},
+		{TypeRematchRequested, RematchRequestedPayload{}},
+		{TypeRematchStarting, RematchStartingPayload{}},
+		{TypeSeriesEnded, SeriesEndedPayload{}},
+		{TypeChatMessage, ChatMessageBroadcastPayload{}},
+		{TypeResync, ResyncPayload{}},
+		{TypeLobbyEvents, LobbyEventsPayload{}},
+		{TypeError, ErrorPayload{}},
+		{TypeDisconnectWarning, DisconnectWarningPayload{}},
+		{TypeServerShutdown, ServerShutdownPayload{}},
+	}
+}
+
+// ProtocolTypes lists the types carried inside payloads as raw JSON, whose shape depends on a field beside
+// them: the event data of lobby and turn events, the data of each action and the details of errors
+func ProtocolTypes() []interface{} {
+	return []interface{}{
+		PlayerJoinedEventData{},
+		PlayerLeftEventData{},
+		SpectatorJoinedEventData{},
+		SpectatorLeftEventData{},
+		WaitlistJoinedEventData{},
+		WaitlistLeftEventData{},
+		PlayerReadyChangedEventData{},
+		HostChangedEventData{},
+		TeamSubmittedEventData{},
+		SettingsChangedEventData{},
+		StateChangedEventData{},
+
+		AttackActionData{},
+		SwitchActionData{},
+		ItemActionData{},
+
+		MoveUsedEventData{},
+		DamageDealtEventData{},
+		StatusAppliedEventData{},
+		StatusEndedEventData{},
+		ConfusionSelfHitEventData{},
+		LeechSeedDrainEventData{},
+		RecoilDamageEventData{},
+		StatusDamageEventData{},
+		HazardSetEventData{},
+		HazardDamageEventData{},
+		HazardClearedEventData{},
+		TerrainEventData{},
+		ItemUsedEventData{},
+		CreatureFaintedEventData{},
+		CreatureSwitchedEventData{},
+		StatChangedEventData{},
+		MoveFailedEventData{},
+
+		InvalidTeamDetails{},
+		InvalidPayloadDetails{},
+		PayloadTooLargeDetails{},
+		VersionMismatchDetails{},
+	}
+}
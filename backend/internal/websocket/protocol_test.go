@@ -0,0 +1,37 @@
+package websocket
+
+import "testing"
+
+// ========================================
+// Protocol Description Tests
+// ========================================
+
+func TestProtocolMessages(t *testing.T) {
+	// Every message a client can send is listed once, and held to a payload size budget
+	seen := make(map[MessageType]bool)
+	for _, msg := range ClientMessages() {
+		if seen[msg.Type] {
+			t.Errorf("client message %s listed twice", msg.Type)
+		}
+		seen[msg.Type] = true
+		if _, ok := payloadSizeLimits[msg.Type]; !ok {
+			t.Errorf("client message %s has no payload size limit", msg.Type)
+		}
+	}
+	for msgType := range payloadSizeLimits {
+		if !seen[msgType] {
+			t.Errorf("client message %s is not listed", msgType)
+		}
+	}
+
+	seen = make(map[MessageType]bool)
+	for _, msg := range ServerMessages() {
+		if seen[msg.Type] {
+			t.Errorf("server message %s listed twice", msg.Type)
+		}
+		seen[msg.Type] = true
+		if msg.Payload == nil {
+			t.Errorf("server message %s has no payload", msg.Type)
+		}
+	}
+}
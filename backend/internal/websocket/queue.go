@@ -0,0 +1,135 @@
+package websocket
+
+import "sync"
+
+// lobbyQueueCapacity is how many sends may wait in a lobby's queue before whoever sends next has to wait
+const lobbyQueueCapacity = 256
+
+// lobbyQueue runs the sends to a lobby in order on a worker of its own, so broadcasting to a lobby with
+// slow clients never holds up anyone else. Broadcasts always go through it; a message for a single
+// connection skips it while nothing is queued, since nothing can then be sent ahead of it.
+type lobbyQueue struct {
+	mu        sync.Mutex
+	spaceFree *sync.Cond // Signalled when a send is taken off a full queue
+	sends     []func()   // Waiting to run, oldest first
+	running   bool       // A worker is running sends, or about to
+	stats     LobbyQueueStats
+}
+
+// LobbyQueueStats shows the back-pressure on a lobby's sends
+type LobbyQueueStats struct {
+	Depth     int   // Sends waiting now
+	HighWater int   // Most sends ever waiting at once
+	Capacity  int   // Most sends that may wait before senders are held up
+	Stalls    int64 // Times a sender was held up by a full queue
+}
+
+// newLobbyQueue creates an empty lobby queue
+func newLobbyQueue() *lobbyQueue {
+	q := &lobbyQueue{stats: LobbyQueueStats{Capacity: lobbyQueueCapacity}}
+	q.spaceFree = sync.NewCond(&q.mu)
+	return q
+}
+
+// enqueue adds a send to the back of the queue, waiting for space if it is full
+func (q *lobbyQueue) enqueue(send func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.sends) >= lobbyQueueCapacity {
+		q.stats.Stalls++
+		for len(q.sends) >= lobbyQueueCapacity {
+			q.spaceFree.Wait()
+		}
+	}
+	q.sends = append(q.sends, send)
+	if len(q.sends) > q.stats.HighWater {
+		q.stats.HighWater = len(q.sends)
+	}
+	if !q.running {
+		q.running = true
+		go q.work()
+	}
+}
+
+// sendNow runs a send straight away if nothing is queued or running, returning its error, and queues it otherwise
+func (q *lobbyQueue) sendNow(send func() error) error {
+	q.mu.Lock()
+	if !q.running {
+		defer q.mu.Unlock()
+		return send() // Holding the lock keeps anything from being sent ahead of it
+	}
+	q.mu.Unlock()
+	q.enqueue(func() { send() })
+	return nil
+}
+
+// work runs the queued sends in order until there are none left
+func (q *lobbyQueue) work() {
+	for {
+		q.mu.Lock()
+		if len(q.sends) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		send := q.sends[0]
+		q.sends[0] = nil
+		q.sends = q.sends[1:]
+		q.spaceFree.Signal()
+		q.mu.Unlock()
+
+		send()
+	}
+}
+
+// snapshot returns the queue's current stats
+func (q *lobbyQueue) snapshot() LobbyQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Depth = len(q.sends)
+	return stats
+}
+
+// LobbyQueueStats returns the back-pressure on the lobby's sends, and false if it has no queue
+func (h *Hub) LobbyQueueStats(lobbyCode string) (LobbyQueueStats, bool) {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	q, ok := shard.queues[lobbyCode]
+	shard.mu.RUnlock()
+	if !ok {
+		return LobbyQueueStats{}, false
+	}
+	return q.snapshot(), true
+}
+
+// inLobbyOrder sends in order with the lobby's broadcasts, straight away if the lobby has none queued
+func (h *Hub) inLobbyOrder(lobbyCode string, send func() error) error {
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	q := shard.queues[lobbyCode]
+	shard.mu.RUnlock()
+	if q == nil {
+		return send()
+	}
+	return q.sendNow(send)
+}
+
+// setLobbyQueue sets the queue that orders sends to the connection's lobby
+func (c *Connection) setLobbyQueue(q *lobbyQueue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue = q
+}
+
+// inLobbyOrder sends in order with the broadcasts to the connection's lobby, straight away if it is in none
+func (c *Connection) inLobbyOrder(send func() error) error {
+	c.mu.RLock()
+	q := c.queue
+	c.mu.RUnlock()
+	if q == nil {
+		return send()
+	}
+	return q.sendNow(send)
+}
@@ -0,0 +1,175 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ========================================
+// Lobby Queue Tests
+// ========================================
+
+// blockQueue holds up the queue's worker until the returned function is called
+func blockQueue(q *lobbyQueue) (release func()) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	q.enqueue(func() {
+		close(started)
+		<-unblock
+	})
+	<-started
+	return func() { close(unblock) }
+}
+
+// waitForIdle waits for the queue's worker to run everything queued
+func waitForIdle(t *testing.T, q *lobbyQueue) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		running := q.running
+		q.mu.Unlock()
+		if !running {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the queue to drain")
+}
+
+// receiveType reads the next message queued for the connection and returns its type
+func receiveType(t *testing.T, conn *Connection) MessageType {
+	t.Helper()
+	select {
+	case data := <-conn.send:
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			t.Fatalf("failed to unmarshal message: %v", err)
+		}
+		return env.Type
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+		return ""
+	}
+}
+
+func TestLobbyQueue_Order(t *testing.T) {
+	q := newLobbyQueue()
+	release := blockQueue(q)
+
+	var mu sync.Mutex
+	var order []int
+	record := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, i)
+	}
+
+	// Sends made while the worker is busy go behind what is already queued, whichever way they are made
+	for i := 0; i < 10; i++ {
+		i := i
+		if i%2 == 0 {
+			q.enqueue(func() { record(i) })
+		} else {
+			q.sendNow(func() error { record(i); return nil })
+		}
+	}
+	release()
+	waitForIdle(t, q)
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("expected sends in the order they were made, got %v", order)
+		}
+	}
+	if len(order) != 10 {
+		t.Fatalf("expected 10 sends, got %d", len(order))
+	}
+
+	// Once idle, a send runs straight away
+	ran := false
+	q.sendNow(func() error { ran = true; return nil })
+	if !ran {
+		t.Error("expected a send on an idle queue to run straight away")
+	}
+}
+
+func TestLobbyQueue_BackPressure(t *testing.T) {
+	q := newLobbyQueue()
+	release := blockQueue(q)
+
+	for i := 0; i < lobbyQueueCapacity; i++ {
+		q.enqueue(func() {})
+	}
+
+	stalled := make(chan struct{})
+	go func() {
+		q.enqueue(func() {})
+		close(stalled)
+	}()
+	select {
+	case <-stalled:
+		t.Fatal("expected a send to a full queue to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	stats := q.snapshot()
+	if stats.Depth != lobbyQueueCapacity || stats.HighWater != lobbyQueueCapacity || stats.Capacity != lobbyQueueCapacity {
+		t.Errorf("expected a full queue, got %+v", stats)
+	}
+	if stats.Stalls != 1 {
+		t.Errorf("expected 1 stall, got %d", stats.Stalls)
+	}
+
+	release()
+	select {
+	case <-stalled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the stalled send to be queued once there was room")
+	}
+	waitForIdle(t, q)
+	if depth := q.snapshot().Depth; depth != 0 {
+		t.Errorf("expected an empty queue, got depth %d", depth)
+	}
+}
+
+func TestHub_LobbyQueues(t *testing.T) {
+	hub := NewHub()
+	slow := hubConn(t, hub, "player-slow", "SLOW01")
+	other := hubConn(t, hub, "player-other", "FAST01")
+
+	slowQueue := hub.lobbyShard("SLOW01").queues["SLOW01"]
+	release := blockQueue(slowQueue)
+
+	// A lobby whose sends are held up delays no other lobby
+	hub.BroadcastToLobby("SLOW01", TypeLobbyUpdated, LobbyUpdatedPayload{})
+	hub.BroadcastToLobby("FAST01", TypeLobbyUpdated, LobbyUpdatedPayload{})
+	if got := receiveType(t, other); got != TypeLobbyUpdated {
+		t.Errorf("expected %s, got %s", TypeLobbyUpdated, got)
+	}
+
+	// A message straight to a connection waits behind its lobby's broadcasts
+	slow.SendMessage(TypeHeartbeatAck, HeartbeatAckPayload{})
+	if len(slow.send) != 0 {
+		t.Fatalf("expected nothing sent while the lobby's queue is held up, got %d", len(slow.send))
+	}
+	if stats, ok := hub.LobbyQueueStats("SLOW01"); !ok || stats.Depth != 2 {
+		t.Errorf("expected 2 sends queued for the lobby, got %+v", stats)
+	}
+
+	release()
+	if got := receiveType(t, slow); got != TypeLobbyUpdated {
+		t.Errorf("expected %s first, got %s", TypeLobbyUpdated, got)
+	}
+	if got := receiveType(t, slow); got != TypeHeartbeatAck {
+		t.Errorf("expected %s second, got %s", TypeHeartbeatAck, got)
+	}
+
+	// The queue goes with the lobby's last connection
+	hub.handleUnregister(slow)
+	if _, ok := hub.LobbyQueueStats("SLOW01"); ok {
+		t.Error("expected no queue for a lobby with no connections")
+	}
+}
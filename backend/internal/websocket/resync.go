@@ -0,0 +1,82 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"poke-battles/internal/game"
+)
+
+// handleAck trims the replay buffer of the player's session to what they have not yet received
+func (h *Handler) handleAck(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload AckPayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
+	if session := conn.Session(); session != nil {
+		session.ack(payload.LobbySeq)
+	}
+}
+
+// handleResyncRequest answers a client that found a gap in its broadcasts with a snapshot of the lobby and the
+// broadcasts it missed. A player in a battle is then sent their view of it, as request_game_state would.
+func (h *Handler) handleResyncRequest(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	var payload ResyncRequestPayload
+	if !parsePayload(conn, env, &payload) {
+		return
+	}
+
+	lobby, err := h.lobbyService.GetLobby(conn.LobbyCode())
+	if err != nil {
+		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		return
+	}
+
+	// Held back lobby changes go out first, so the snapshot is no newer than the broadcasts
+	h.flushLobbyUpdates(lobby.Code)
+
+	missed, complete := h.hub.lobbyBroadcastsSince(lobby.Code, conn.PlayerID(), payload.LastLobbySeq)
+	resync := ResyncPayload{
+		Lobby:      h.buildLobbyInfo(lobby),
+		Missed:     make([]json.RawMessage, len(missed)),
+		LobbySeq:   h.hub.LobbySeq(lobby.Code),
+		Incomplete: !complete,
+	}
+	for i, msg := range missed {
+		resync.Missed[i] = msg.marshal(0, "")
+	}
+	conn.SendMessageWithCorrelation(TypeResync, env.CorrelationID, resync)
+
+	if lobby.GetState() == game.LobbyStateActive && !conn.IsSpectator() {
+		h.handleRequestGameState(conn, env)
+	}
+}
+
+// sendLobbyEventsSince answers request_lobby_state with the lobby changes broadcast to the client after sinceSeq.
+// It sends nothing and returns false if some can no longer be sent, so the whole lobby has to be.
+func (h *Handler) sendLobbyEventsSince(conn *Connection, env *Envelope, lobbyCode string, sinceSeq int64) bool {
+	// Held back lobby changes go out first, so none are left out
+	h.flushLobbyUpdates(lobbyCode)
+
+	missed, complete := h.hub.lobbyEventsSince(lobbyCode, conn.PlayerID(), sinceSeq)
+	if !complete {
+		return false
+	}
+	events := LobbyEventsPayload{
+		SinceSeq: sinceSeq,
+		Events:   append([]MissedLobbyEvent{}, missed...),
+		LobbySeq: h.hub.LobbySeq(lobbyCode),
+	}
+	conn.SendMessageWithCorrelation(TypeLobbyEvents, env.CorrelationID, events)
+	return true
+}
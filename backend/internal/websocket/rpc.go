@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// ReplyFunc receives the client's reply to a request, or the error that ended the wait for one:
+// ErrRequestTimeout if none came in time, ErrConnectionClosing if the connection closed first
+type ReplyFunc func(reply *Envelope, err error)
+
+// pendingRequest is a request sent to the client that is waiting for its reply
+type pendingRequest struct {
+	replyType MessageType // What answers it
+	onReply   ReplyFunc
+	timer     *time.Timer
+}
+
+// ErrRequestTimeout is returned when the client does not reply to a request in time
+var ErrRequestTimeout = &RequestTimeoutError{}
+
+type RequestTimeoutError struct{}
+
+func (e *RequestTimeoutError) Error() string {
+	return "request timed out"
+}
+
+// Request sends a message under a new correlation ID and calls onReply with the first message of replyType the
+// client sends back with that ID, which is not handled any further. If none comes within timeout the client is
+// sent a REQUEST_TIMEOUT error with the ID and onReply gets ErrRequestTimeout. onReply is called exactly once,
+// on the read pump for a reply and on its own goroutine otherwise, and never if sending the request fails.
+func (c *Connection) Request(msgType MessageType, payload interface{}, replyType MessageType, timeout time.Duration, onReply ReplyFunc) (correlationID string, err error) {
+	correlationID, err = newRequestID()
+	if err != nil {
+		return "", err
+	}
+
+	req := &pendingRequest{replyType: replyType, onReply: onReply}
+	c.mu.Lock()
+	if c.requests == nil {
+		c.requests = make(map[string]*pendingRequest)
+	}
+	c.requests[correlationID] = req
+	req.timer = time.AfterFunc(timeout, func() {
+		if c.takeRequest(correlationID) == req {
+			c.SendError(ErrCodeRequestTimeout, "No reply to "+string(msgType)+" in time", correlationID)
+			onReply(nil, ErrRequestTimeout)
+		}
+	})
+	c.mu.Unlock()
+
+	if err := c.SendMessageWithCorrelation(msgType, correlationID, payload); err != nil {
+		if c.takeRequest(correlationID) == req {
+			req.timer.Stop()
+		}
+		return "", err
+	}
+	return correlationID, nil
+}
+
+// Call is Request for a caller that waits for the reply. It must not be called on the connection's read pump,
+// which the reply arrives on.
+func (c *Connection) Call(msgType MessageType, payload interface{}, replyType MessageType, timeout time.Duration) (*Envelope, error) {
+	type result struct {
+		reply *Envelope
+		err   error
+	}
+	done := make(chan result, 1)
+	if _, err := c.Request(msgType, payload, replyType, timeout, func(reply *Envelope, err error) {
+		done <- result{reply, err}
+	}); err != nil {
+		return nil, err
+	}
+	r := <-done
+	return r.reply, r.err
+}
+
+// resolveRequest hands a reply to the request waiting for it, returning false if it answers none
+func (c *Connection) resolveRequest(env *Envelope) bool {
+	if env.CorrelationID == "" {
+		return false
+	}
+	c.mu.Lock()
+	req, ok := c.requests[env.CorrelationID]
+	if !ok || req.replyType != env.Type {
+		c.mu.Unlock()
+		return false
+	}
+	delete(c.requests, env.CorrelationID)
+	c.mu.Unlock()
+
+	req.timer.Stop()
+	req.onReply(env, nil)
+	return true
+}
+
+// cancelRequests stops waiting for replies of replyType, without calling their ReplyFuncs.
+// It is for when the client has answered some other way.
+func (c *Connection) cancelRequests(replyType MessageType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, req := range c.requests {
+		if req.replyType == replyType {
+			req.timer.Stop()
+			delete(c.requests, id)
+		}
+	}
+}
+
+// failRequests ends the wait for every reply once the connection has closed
+func (c *Connection) failRequests() {
+	c.mu.Lock()
+	requests := c.requests
+	c.requests = nil
+	c.mu.Unlock()
+
+	for _, req := range requests {
+		req.timer.Stop()
+		req.onReply(nil, ErrConnectionClosing)
+	}
+}
+
+// takeRequest removes and returns the request waiting under the correlation ID, nil if there is none
+func (c *Connection) takeRequest(correlationID string) *pendingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	req, ok := c.requests[correlationID]
+	if !ok {
+		return nil
+	}
+	delete(c.requests, correlationID)
+	return req
+}
+
+// newRequestID generates a random correlation ID for a request from the server
+func newRequestID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "srv-" + hex.EncodeToString(bytes), nil
+}
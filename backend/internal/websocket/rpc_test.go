@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// ========================================
+// Request Tests
+// ========================================
+
+// sentRequest reads the request just queued for the connection
+func sentRequest(t *testing.T, conn *Connection) *Envelope {
+	t.Helper()
+	select {
+	case data := <-conn.send:
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			t.Fatalf("failed to unmarshal request: %v", err)
+		}
+		return &env
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the request")
+		return nil
+	}
+}
+
+// replyTo builds the client's reply to a request
+func replyTo(t *testing.T, req *Envelope, msgType MessageType) *Envelope {
+	t.Helper()
+	env, err := NewEnvelope(msgType, RespondDrawPayload{Accept: true})
+	if err != nil {
+		t.Fatalf("failed to build reply: %v", err)
+	}
+	env.CorrelationID = req.CorrelationID
+	return env
+}
+
+func TestConnection_Request_Reply(t *testing.T) {
+	conn := NewConnection(nil, NewHub())
+
+	replies := make(chan error, 1)
+	id, err := conn.Request(TypeDrawOffered, DrawOfferedPayload{PlayerID: "player-1"}, TypeRespondDraw, time.Minute, func(reply *Envelope, err error) {
+		if err == nil && reply.Type != TypeRespondDraw {
+			err = errors.New("wrong reply")
+		}
+		replies <- err
+	})
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	req := sentRequest(t, conn)
+	if req.CorrelationID != id || req.Type != TypeDrawOffered {
+		t.Fatalf("expected draw_offered under %q, got %s under %q", id, req.Type, req.CorrelationID)
+	}
+
+	// Only a message of the reply type with the request's correlation ID answers it
+	if conn.resolveRequest(replyTo(t, req, TypeHeartbeat)) {
+		t.Error("expected a message of another type not to answer the request")
+	}
+	if conn.resolveRequest(&Envelope{Type: TypeRespondDraw, CorrelationID: "draw-player-2"}) {
+		t.Error("expected a message with another correlation ID not to answer the request")
+	}
+	if !conn.resolveRequest(replyTo(t, req, TypeRespondDraw)) {
+		t.Fatal("expected the reply to answer the request")
+	}
+	if err := <-replies; err != nil {
+		t.Errorf("expected the reply, got %v", err)
+	}
+
+	// It is answered once
+	if conn.resolveRequest(replyTo(t, req, TypeRespondDraw)) {
+		t.Error("expected a second reply not to answer the request")
+	}
+}
+
+func TestConnection_Request_Timeout(t *testing.T) {
+	conn := NewConnection(nil, NewHub())
+
+	replies := make(chan error, 1)
+	id, err := conn.Request(TypeDrawOffered, DrawOfferedPayload{}, TypeRespondDraw, 20*time.Millisecond, func(reply *Envelope, err error) {
+		replies <- err
+	})
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	sentRequest(t, conn)
+
+	select {
+	case err := <-replies:
+		if !errors.Is(err, ErrRequestTimeout) {
+			t.Errorf("expected ErrRequestTimeout, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the request to time out")
+	}
+
+	// The client is told its answer is no longer wanted
+	env := sentRequest(t, conn)
+	var payload ErrorPayload
+	env.ParsePayload(&payload)
+	if env.Type != TypeError || payload.Code != ErrCodeRequestTimeout || env.CorrelationID != id {
+		t.Errorf("expected REQUEST_TIMEOUT under %q, got %s %s under %q", id, env.Type, payload.Code, env.CorrelationID)
+	}
+}
+
+func TestConnection_Request_CancelAndClose(t *testing.T) {
+	conn := NewConnection(nil, NewHub())
+
+	called := make(chan error, 2)
+	onReply := func(reply *Envelope, err error) { called <- err }
+	if _, err := conn.Request(TypeDrawOffered, DrawOfferedPayload{}, TypeRespondDraw, 20*time.Millisecond, onReply); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if _, err := conn.Request(TypePauseRequested, PauseRequestedPayload{}, TypeRespondPause, time.Minute, onReply); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	// A cancelled request never hears back, not even when it would have timed out
+	conn.cancelRequests(TypeRespondDraw)
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-called:
+		t.Fatalf("expected a cancelled request not to be answered, got %v", err)
+	default:
+	}
+
+	// The rest are ended by the connection closing
+	conn.failRequests()
+	select {
+	case err := <-called:
+		if !errors.Is(err, ErrConnectionClosing) {
+			t.Errorf("expected ErrConnectionClosing, got %v", err)
+		}
+	default:
+		t.Fatal("expected the open request to be ended")
+	}
+}
+
+func TestConnection_Call(t *testing.T) {
+	conn := NewConnection(nil, NewHub())
+
+	go func() {
+		var req Envelope
+		json.Unmarshal(<-conn.send, &req)
+		reply, _ := NewEnvelope(TypeRespondDraw, RespondDrawPayload{Accept: true})
+		reply.CorrelationID = req.CorrelationID
+		conn.resolveRequest(reply)
+	}()
+	reply, err := conn.Call(TypeDrawOffered, DrawOfferedPayload{}, TypeRespondDraw, time.Second)
+	if err != nil {
+		t.Fatalf("expected a reply, got %v", err)
+	}
+	var payload RespondDrawPayload
+	if err := reply.ParsePayload(&payload); err != nil || !payload.Accept {
+		t.Errorf("expected the reply's payload, got %+v", payload)
+	}
+}
@@ -0,0 +1,316 @@
+package websocket
+
+import (
+	"sync"
+)
+
+// replayBufferSize is how many of a player's latest messages are kept to replay after a reconnect
+const replayBufferSize = 256
+
+// playerSession outlives a player's connections to a lobby so a client that reconnects can pick up where it
+// left off. It numbers the player's outbound messages across connections and keeps the latest for replay.
+// A player on several devices has one session, so each message they are all sent has the same seq on each.
+type playerSession struct {
+	mu        sync.Mutex
+	playerID  string
+	lobbyCode string
+	lastSeq   int64
+	buffer    []sentMessage        // Oldest first, at most replayBufferSize
+	conns     map[*Connection]bool // The connections the player is on, none while they are away
+	declared  *ClientCapabilities  // What the player's client last declared it can handle, nil if it declared nothing
+	shared    *sharedSessions      // Where the session is also kept for other instances, nil if it is not
+}
+
+// sentMessage is a marshaled envelope kept for replay
+type sentMessage struct {
+	seq      int64
+	lobbySeq int64 // 0 unless it was a broadcast to the lobby
+	data     []byte
+}
+
+// stamp gives the message the session's next sequence number and returns it marshaled, keeping a copy for replay
+func (s *playerSession) stamp(msg *preparedEnvelope, correlationID string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stampLocked(msg, correlationID)
+}
+
+// stampLocked is stamp for a caller that already holds the lock
+func (s *playerSession) stampLocked(msg *preparedEnvelope, correlationID string) []byte {
+	s.lastSeq++
+	data := msg.marshal(s.lastSeq, correlationID)
+
+	sent := sentMessage{seq: s.lastSeq, lobbySeq: msg.lobbySeq, data: data}
+	s.buffer = append(s.buffer, sent)
+	s.persistStampedLocked(sent)
+	if len(s.buffer) > replayBufferSize {
+		s.buffer = s.buffer[len(s.buffer)-replayBufferSize:]
+	}
+	return data
+}
+
+// ack drops the messages up to the broadcast numbered lobbySeq, which the client has received.
+// Everything sent before that broadcast went down the same connection ahead of it, so the client has those too.
+func (s *playerSession) ack(lobbySeq int64) (dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.buffer) - 1; i >= 0; i-- {
+		if s.buffer[i].lobbySeq != 0 && s.buffer[i].lobbySeq <= lobbySeq {
+			s.persistTrimLocked(s.buffer[i].seq)
+			s.buffer = s.buffer[i+1:]
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// skipTo moves the session's sequence number on to seq if it is behind, so numbers are never reused
+func (s *playerSession) skipTo(seq int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq > s.lastSeq {
+		s.lastSeq = seq
+		s.persistSkipLocked(seq)
+	}
+}
+
+// currentSeq returns the sequence number of the last message stamped
+func (s *playerSession) currentSeq() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeq
+}
+
+// hold stamps a message for the player, sending it to every connection they are on and keeping it
+// for replay, so a player who is away gets it when they return
+func (s *playerSession) hold(msg *preparedEnvelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := s.stampLocked(msg, "")
+	var err error
+	for conn := range s.conns {
+		if sendErr := conn.sendRawAt(data, priorityOf(msg.msgType)); sendErr == nil {
+			conn.recordSent(msg.msgType, "")
+		} else {
+			err = sendErr
+		}
+	}
+	return err
+}
+
+// resume adds conn to the connections the session is on, first sending it every message after lastSeq
+// so nothing sent in the meantime can overtake them. A lastSeq of 0 means the client wants no replay.
+// complete is false if the client missed messages that can no longer be replayed.
+func (s *playerSession) resume(conn *Connection, lastSeq int64) (replayed int, complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attachLocked(conn)
+	if lastSeq == 0 {
+		return 0, true
+	}
+	messages, complete := s.sinceLocked(lastSeq)
+	for _, data := range messages {
+		conn.SendRaw(data)
+	}
+	return len(messages), complete
+}
+
+// catchUp adds conn to the connections the session is on, first sending it the lobby broadcasts it missed. They are new to the
+// session, so they go out under its next sequence numbers, keeping their lobby seq.
+func (s *playerSession) catchUp(conn *Connection, missed []*preparedEnvelope) (replayed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attachLocked(conn)
+	for _, msg := range missed {
+		conn.SendRaw(s.stampLocked(msg, ""))
+	}
+	return len(missed)
+}
+
+// declare records what the player's client declared it can handle, for a client resuming the session to keep
+func (s *playerSession) declare(capabilities *ClientCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	declared := *capabilities
+	s.declared = &declared
+}
+
+// capabilities returns a copy of what the player's client last declared it can handle, nil if it declared nothing
+func (s *playerSession) capabilities() *ClientCapabilities {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.declared == nil {
+		return nil
+	}
+	declared := *s.declared
+	return &declared
+}
+
+// attachLocked adds conn to the connections the session is on
+func (s *playerSession) attachLocked(conn *Connection) {
+	if s.conns == nil {
+		s.conns = make(map[*Connection]bool)
+	}
+	s.conns[conn] = true
+}
+
+// detach takes conn off the connections the session is on, leaving the player away once none are left
+func (s *playerSession) detach(conn *Connection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// connected returns true if the player is on at least one connection
+func (s *playerSession) connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns) > 0
+}
+
+// since returns the messages sent after lastSeq, oldest first. complete is false if some of them have
+// already dropped out of the buffer, or lastSeq is ahead of the session, so the client must resync.
+func (s *playerSession) since(lastSeq int64) (messages [][]byte, complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sinceLocked(lastSeq)
+}
+
+// sinceLocked is since for a caller that already holds the lock
+func (s *playerSession) sinceLocked(lastSeq int64) (messages [][]byte, complete bool) {
+	if lastSeq > s.lastSeq {
+		return nil, false
+	}
+	complete = lastSeq == s.lastSeq || (len(s.buffer) > 0 && s.buffer[0].seq <= lastSeq+1)
+	for _, m := range s.buffer {
+		if m.seq > lastSeq {
+			messages = append(messages, m.data)
+		}
+	}
+	return messages, complete
+}
+
+// sessionStore holds the session of every player who authenticated to a lobby, until they leave it.
+// Sessions are indexed by lobby too, so a broadcast finds its lobby's without looking through every other.
+type sessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*playerSession            // Keyed by player ID
+	lobbies  map[string]map[string]*playerSession // Keyed by lobby code, then player ID
+}
+
+// newSessionStore creates an empty session store
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*playerSession),
+		lobbies:  make(map[string]map[string]*playerSession),
+	}
+}
+
+// setLocked makes the session its player's, replacing any they had, for a caller holding the lock
+func (s *sessionStore) setLocked(session *playerSession) {
+	if previous, ok := s.sessions[session.playerID]; ok {
+		s.removeLocked(previous)
+	}
+	s.sessions[session.playerID] = session
+	lobby, ok := s.lobbies[session.lobbyCode]
+	if !ok {
+		lobby = make(map[string]*playerSession)
+		s.lobbies[session.lobbyCode] = lobby
+	}
+	lobby[session.playerID] = session
+}
+
+// removeLocked forgets the session, which must be its player's, for a caller holding the lock
+func (s *sessionStore) removeLocked(session *playerSession) {
+	delete(s.sessions, session.playerID)
+	if lobby, ok := s.lobbies[session.lobbyCode]; ok {
+		delete(lobby, session.playerID)
+		if len(lobby) == 0 {
+			delete(s.lobbies, session.lobbyCode)
+		}
+	}
+}
+
+// open returns the player's session in the lobby, starting a new one if they have none there.
+// resumed is true if the session already existed.
+func (s *sessionStore) open(playerID, lobbyCode string) (session *playerSession, resumed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.sessions[playerID]; ok && session.lobbyCode == lobbyCode {
+		return session, true
+	}
+	session = &playerSession{playerID: playerID, lobbyCode: lobbyCode}
+	s.setLocked(session)
+	return session, false
+}
+
+// put makes the session its player's, replacing any they had
+func (s *sessionStore) put(session *playerSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setLocked(session)
+}
+
+// forget forgets the session unless its player has since started another
+func (s *sessionStore) forget(session *playerSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions[session.playerID] == session {
+		s.removeLocked(session)
+	}
+}
+
+// get returns the player's session, or nil if they have none
+func (s *sessionStore) get(playerID string) *playerSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions[playerID]
+}
+
+// inLobby returns the sessions of the lobby's players, keyed by player ID
+func (s *sessionStore) inLobby(lobbyCode string) map[string]*playerSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := make(map[string]*playerSession, len(s.lobbies[lobbyCode]))
+	for playerID, session := range s.lobbies[lobbyCode] {
+		sessions[playerID] = session
+	}
+	return sessions
+}
+
+// drop forgets the player's session in the lobby, here and in any store it is shared in
+func (s *sessionStore) drop(playerID, lobbyCode string) {
+	s.mu.Lock()
+	session, ok := s.sessions[playerID]
+	if ok && session.lobbyCode == lobbyCode {
+		s.removeLocked(session)
+	}
+	s.mu.Unlock()
+
+	if ok && session.lobbyCode == lobbyCode {
+		session.unshare()
+	}
+}
+
+// dropLobby forgets every session in the lobby, here and in any store they are shared in
+func (s *sessionStore) dropLobby(lobbyCode string) {
+	s.mu.Lock()
+	var dropped []*playerSession
+	for _, session := range s.lobbies[lobbyCode] {
+		dropped = append(dropped, session)
+	}
+	for _, session := range dropped {
+		s.removeLocked(session)
+	}
+	s.mu.Unlock()
+
+	for _, session := range dropped {
+		session.unshare()
+	}
+}
@@ -0,0 +1,269 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"poke-battles/internal/sessions"
+)
+
+// stampN stamps n heartbeat acks on the session
+func stampN(t *testing.T, session *playerSession, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		env, err := NewEnvelope(TypeHeartbeatAck, HeartbeatAckPayload{})
+		if err != nil {
+			t.Fatalf("failed to build envelope: %v", err)
+		}
+		msg, err := prepareEnvelope(env)
+		if err != nil {
+			t.Fatalf("failed to prepare envelope: %v", err)
+		}
+		session.stamp(msg, "")
+	}
+}
+
+// seqsOf returns the sequence numbers of marshaled envelopes
+func seqsOf(t *testing.T, messages [][]byte) []int64 {
+	t.Helper()
+	seqs := make([]int64, 0, len(messages))
+	for _, data := range messages {
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			t.Fatalf("failed to unmarshal replayed message: %v", err)
+		}
+		seqs = append(seqs, env.Seq)
+	}
+	return seqs
+}
+
+// ========================================
+// Player Session Tests
+// ========================================
+
+func TestPlayerSession_Since(t *testing.T) {
+	session := &playerSession{lobbyCode: "ABC123"}
+	stampN(t, session, 5)
+
+	messages, complete := session.since(2)
+	if !complete {
+		t.Error("expected replay after seq 2 to be complete")
+	}
+	if seqs := seqsOf(t, messages); len(seqs) != 3 || seqs[0] != 3 || seqs[2] != 5 {
+		t.Errorf("expected seqs 3 to 5, got %v", seqs)
+	}
+
+	if messages, complete := session.since(5); !complete || len(messages) != 0 {
+		t.Errorf("expected nothing to replay for an up to date client, got %d messages (complete %v)", len(messages), complete)
+	}
+	if _, complete := session.since(9); complete {
+		t.Error("expected a last_seq ahead of the session to need a resync")
+	}
+}
+
+func TestPlayerSession_SinceTrimmed(t *testing.T) {
+	session := &playerSession{lobbyCode: "ABC123"}
+	stampN(t, session, replayBufferSize+10)
+
+	if len(session.buffer) != replayBufferSize {
+		t.Fatalf("expected buffer capped at %d, got %d", replayBufferSize, len(session.buffer))
+	}
+
+	messages, complete := session.since(5)
+	if complete {
+		t.Error("expected replay to be incomplete once missed messages are trimmed")
+	}
+	if len(messages) != replayBufferSize {
+		t.Errorf("expected every buffered message to be replayed, got %d", len(messages))
+	}
+
+	if _, complete := session.since(10); !complete {
+		t.Error("expected replay from the oldest buffered message to be complete")
+	}
+}
+
+func TestPlayerSession_SkipTo(t *testing.T) {
+	session := &playerSession{lobbyCode: "ABC123"}
+	session.skipTo(3)
+	stampN(t, session, 1)
+
+	if session.currentSeq() != 4 {
+		t.Errorf("expected numbering to carry on from 3, got %d", session.currentSeq())
+	}
+
+	session.skipTo(1)
+	if session.currentSeq() != 4 {
+		t.Errorf("expected skipTo never to move backwards, got %d", session.currentSeq())
+	}
+}
+
+func TestPlayerSession_Ack(t *testing.T) {
+	session := &playerSession{lobbyCode: "ABC123"}
+	msg, err := prepareEnvelope(&Envelope{Type: TypeLobbyUpdated, Version: ProtocolVersion})
+	if err != nil {
+		t.Fatalf("failed to prepare envelope: %v", err)
+	}
+
+	// seq 1-2 direct, 3 lobby seq 10, 4 direct, 5 lobby seq 11, 6 direct
+	stampN(t, session, 2)
+	session.stamp(msg.withLobbySeq(10), "")
+	stampN(t, session, 1)
+	session.stamp(msg.withLobbySeq(11), "")
+	stampN(t, session, 1)
+
+	if dropped := session.ack(9); dropped != 0 {
+		t.Errorf("expected nothing dropped before the first broadcast, dropped %d", dropped)
+	}
+
+	// Acking lobby seq 10 drops it and everything sent before it, but not the direct message after it
+	if dropped := session.ack(10); dropped != 3 {
+		t.Errorf("expected 3 messages dropped, got %d", dropped)
+	}
+	messages, complete := session.since(3)
+	if !complete {
+		t.Error("expected replay after the acked broadcast to be complete")
+	}
+	if seqs := seqsOf(t, messages); len(seqs) != 3 || seqs[0] != 4 {
+		t.Errorf("expected seqs 4-6 kept, got %v", seqs)
+	}
+	if _, complete := session.since(1); complete {
+		t.Error("expected replay from before the acked broadcast to be incomplete")
+	}
+
+	// An ack past the latest broadcast keeps what followed it
+	if dropped := session.ack(100); dropped != 2 {
+		t.Errorf("expected 2 messages dropped, got %d", dropped)
+	}
+	if messages, complete := session.since(5); !complete || len(messages) != 1 {
+		t.Errorf("expected only seq 6 kept, got %d messages, complete %v", len(messages), complete)
+	}
+}
+
+func TestSessionStore_Open(t *testing.T) {
+	store := newSessionStore()
+
+	first, resumed := store.open("player-1", "ABC123")
+	if resumed {
+		t.Error("expected a new session on first open")
+	}
+	if again, resumed := store.open("player-1", "ABC123"); !resumed || again != first {
+		t.Error("expected the same session to be resumed in the same lobby")
+	}
+	if other, resumed := store.open("player-1", "XYZ789"); resumed || other == first {
+		t.Error("expected a new session in a different lobby")
+	}
+
+	store.drop("player-1", "XYZ789")
+	if store.get("player-1") != nil {
+		t.Error("expected the session to be dropped")
+	}
+}
+
+func TestSessionStore_InLobby(t *testing.T) {
+	store := newSessionStore()
+	store.open("player-1", "ABC123")
+	store.open("player-2", "ABC123")
+	store.open("player-3", "XYZ789")
+
+	if sessions := store.inLobby("ABC123"); len(sessions) != 2 || sessions["player-1"] == nil || sessions["player-2"] == nil {
+		t.Errorf("expected player-1 and player-2 in ABC123, got %v", sessions)
+	}
+
+	// A player who moves lobbies is only found in the new one
+	store.open("player-2", "XYZ789")
+	if sessions := store.inLobby("ABC123"); len(sessions) != 1 || sessions["player-1"] == nil {
+		t.Errorf("expected only player-1 left in ABC123, got %v", sessions)
+	}
+	if sessions := store.inLobby("XYZ789"); len(sessions) != 2 {
+		t.Errorf("expected 2 sessions in XYZ789, got %v", sessions)
+	}
+
+	store.dropLobby("XYZ789")
+	if sessions := store.inLobby("XYZ789"); len(sessions) != 0 || store.get("player-3") != nil {
+		t.Errorf("expected the lobby's sessions to be dropped, got %v", sessions)
+	}
+	if store.get("player-1") == nil {
+		t.Error("expected another lobby's sessions to be kept")
+	}
+}
+
+// ========================================
+// Shared Session Tests
+// ========================================
+
+// flushSessionWrites waits for the writes the hub has queued for its session store to be made
+func flushSessionWrites(t *testing.T, hub *Hub) {
+	t.Helper()
+	done := make(chan struct{})
+	hub.shared.queue(nil, func(context.Context, sessions.Store, string) error {
+		close(done)
+		return nil
+	})
+	<-done
+}
+
+// openSharedSession authenticates a connection for player-1 in ABC123 and opens its session,
+// returning the reconnect token the connection was issued
+func openSharedSession(t *testing.T, hub *Hub, reconnectToken string) (*Connection, *playerSession, bool, string) {
+	t.Helper()
+	conn := NewConnection(nil, hub)
+	issued, err := conn.Authenticate("player-1", "ABC123")
+	if err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	session, resumed := hub.openSession(conn, "ABC123", reconnectToken)
+	conn.SetSession(session)
+	session.resume(conn, 0)
+	return conn, session, resumed, issued
+}
+
+func TestHub_SharedSessionResumesOnAnotherInstance(t *testing.T) {
+	store := sessions.NewMemoryStore()
+	first, second := NewHub(), NewHub()
+	first.SetSessionStore(store)
+	second.SetSessionStore(store)
+	defer first.Stop()
+	defer second.Stop()
+
+	// The player is sent three messages on the first instance, then drops
+	conn, session, resumed, token := openSharedSession(t, first, "")
+	if resumed {
+		t.Error("expected a new session on first connecting")
+	}
+	stampN(t, session, 3)
+	session.detach(conn)
+	flushSessionWrites(t, first)
+
+	// With the reconnect token they pick up where they left off on the second instance
+	_, moved, resumed, _ := openSharedSession(t, second, token)
+	if !resumed {
+		t.Fatal("expected the session to be resumed on the second instance")
+	}
+	messages, complete := moved.since(1)
+	if seqs := seqsOf(t, messages); !complete || !reflect.DeepEqual(seqs, []int64{2, 3}) {
+		t.Errorf("expected seqs 2 and 3 replayed, got %v, complete %v", seqs, complete)
+	}
+	stampN(t, moved, 1)
+	if seq := moved.currentSeq(); seq != 4 {
+		t.Errorf("expected numbering to carry on at 4, got %d", seq)
+	}
+
+	// The first instance forgets the session once it finds it has been taken over
+	stampN(t, session, 1)
+	flushSessionWrites(t, first)
+	if first.sessions.get("player-1") != nil {
+		t.Error("expected the first instance to forget the session claimed from it")
+	}
+
+	// Without a valid token, a player with no session on an instance starts a new one there.
+	// The token they resumed with was used up, so it is no longer valid either.
+	if _, _, resumed, _ := openSharedSession(t, first, "not-a-token"); resumed {
+		t.Error("expected a new session without a valid reconnect token")
+	}
+	first.sessions.forget(first.sessions.get("player-1"))
+	if _, _, resumed, _ := openSharedSession(t, first, token); resumed {
+		t.Error("expected a used reconnect token to be refused")
+	}
+}
@@ -0,0 +1,222 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"poke-battles/internal/sessions"
+)
+
+const (
+	// sessionStoreTimeout bounds each call the hub makes to its session store
+	sessionStoreTimeout = 5 * time.Second
+
+	// sessionWriteQueueSize is how many session writes can wait for the store before more are dropped
+	sessionWriteQueueSize = 4096
+)
+
+// sharedSessions links a hub to the store it keeps its players' sessions in
+type sharedSessions struct {
+	hub   *Hub
+	store sessions.Store
+
+	// One session is claimed at a time, so a player's devices authenticating at once agree on it
+	claimMu sync.Mutex
+
+	// Writes reach the store in the order they were made, on one goroutine, so a slow store holds up no one sending
+	writes chan sessionWrite
+}
+
+// sessionWrite is a write waiting to reach the store, made for a session or, for reconnect tokens, for none
+type sessionWrite struct {
+	session *playerSession
+	write   func(ctx context.Context, store sessions.Store, owner string) error
+}
+
+// SetSessionStore has the hub keep its players' sessions in the store as well as in memory: the last seq each was
+// sent, the messages kept for replay and the reconnect tokens issued. A player can then reconnect to another
+// instance sharing the store and resume there, sending last_seq and the reconnect_token they were last issued.
+// The instance a session is resumed on takes it over, and the one it came from forgets it unless the player is
+// still connected there. It must be called before the hub's first connection.
+func (h *Hub) SetSessionStore(store sessions.Store) {
+	shared := &sharedSessions{
+		hub:    h,
+		store:  store,
+		writes: make(chan sessionWrite, sessionWriteQueueSize),
+	}
+
+	h.mu.Lock()
+	h.shared = shared
+	h.mu.Unlock()
+
+	go h.writeSessions(h.stopContext(), shared)
+}
+
+// getSharedSessions returns the hub's link to its session store, nil if it has none
+func (h *Hub) getSharedSessions() *sharedSessions {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.shared
+}
+
+// queue has the write made to the store, dropping it if too many are waiting
+func (shared *sharedSessions) queue(session *playerSession, write func(ctx context.Context, store sessions.Store, owner string) error) {
+	select {
+	case shared.writes <- sessionWrite{session: session, write: write}:
+	default:
+		shared.hub.Logger().Warn("session write dropped, write queue full")
+	}
+}
+
+// writeSessions makes queued writes to the store until ctx is done. A session claimed by another instance
+// is no longer written to, and is forgotten once the player has no connections left to it here.
+func (h *Hub) writeSessions(ctx context.Context, shared *sharedSessions) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-shared.writes:
+			callCtx, cancel := context.WithTimeout(ctx, sessionStoreTimeout)
+			err := queued.write(callCtx, shared.store, h.instanceID)
+			cancel()
+			switch {
+			case errors.Is(err, sessions.ErrNotOwner) && queued.session != nil:
+				h.releaseSession(queued.session)
+			case err != nil && ctx.Err() == nil:
+				h.Logger().Warn("session write failed", "error", err)
+			}
+		}
+	}
+}
+
+// releaseSession stops sharing a session another instance has claimed, forgetting it if the player is away
+func (h *Hub) releaseSession(session *playerSession) {
+	session.mu.Lock()
+	wasShared := session.shared != nil
+	session.shared = nil
+	away := len(session.conns) == 0
+	session.mu.Unlock()
+
+	if !wasShared {
+		return
+	}
+	if away {
+		h.sessions.forget(session)
+	}
+	h.Logger().Info("session claimed by another instance", "player_id", session.playerID,
+		"lobby_code", session.lobbyCode, "forgotten", away)
+}
+
+// openSession returns the session of the connection's player in the lobby and whether it already existed,
+// as sessionStore.open does. With a session store, a player who is not connected here can also resume the
+// session they had on another instance, if they have a session here or present the reconnect token they
+// were issued there; the hub takes the session over. The token presented is used up, and the connection's
+// new reconnect token is shared in its place.
+func (h *Hub) openSession(conn *Connection, lobbyCode, reconnectToken string) (session *playerSession, resumed bool) {
+	playerID := conn.PlayerID()
+	shared := h.getSharedSessions()
+	if shared == nil {
+		return h.sessions.open(playerID, lobbyCode)
+	}
+
+	shared.claimMu.Lock()
+	defer shared.claimMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+	tokenPlayer := ""
+	if reconnectToken != "" {
+		var err error
+		tokenPlayer, err = shared.store.ConsumeReconnectToken(ctx, hashReconnectToken(reconnectToken))
+		if err != nil {
+			conn.log().Warn("reconnect token not checked", "error", err)
+		}
+	}
+	shared.shareReconnectToken(conn)
+
+	// A player already connected here keeps the session their other devices are on
+	local := h.sessions.get(playerID)
+	if local != nil && local.lobbyCode != lobbyCode {
+		local = nil
+	}
+	if local != nil && local.connected() {
+		return local, true
+	}
+
+	resume := local != nil || tokenPlayer == playerID
+	stored, previousOwner, err := shared.store.Claim(ctx, playerID, lobbyCode, h.instanceID, resume)
+	if err != nil {
+		conn.log().Warn("session not claimed, keeping it on this instance", "error", err)
+		return h.sessions.open(playerID, lobbyCode)
+	}
+
+	// The session here is the latest unless another instance has had it since
+	if local != nil && previousOwner == h.instanceID {
+		return local, true
+	}
+
+	session = &playerSession{playerID: playerID, lobbyCode: lobbyCode, shared: shared}
+	if stored != nil {
+		session.lastSeq = stored.LastSeq
+		for _, msg := range stored.Messages {
+			session.buffer = append(session.buffer, sentMessage{seq: msg.Seq, lobbySeq: msg.LobbySeq, data: msg.Data})
+		}
+	}
+	h.sessions.put(session)
+	return session, stored != nil
+}
+
+// shareReconnectToken has the connection's reconnect token accepted once by the other instances until it expires
+func (shared *sharedSessions) shareReconnectToken(conn *Connection) {
+	playerID := conn.PlayerID()
+	tokenHash, expiry := conn.reconnectTokenExpiry()
+	shared.queue(nil, func(ctx context.Context, store sessions.Store, owner string) error {
+		return store.SaveReconnectToken(ctx, tokenHash, playerID, expiry)
+	})
+}
+
+// persistLocked queues a write of the session to the store, if the hub shares it. The caller must hold s.mu.
+func (s *playerSession) persistLocked(write func(ctx context.Context, store sessions.Store, owner string) error) {
+	if s.shared != nil {
+		s.shared.queue(s, write)
+	}
+}
+
+// persistStampedLocked queues the message just stamped to be kept in the store. The caller must hold s.mu.
+func (s *playerSession) persistStampedLocked(sent sentMessage) {
+	playerID := s.playerID
+	s.persistLocked(func(ctx context.Context, store sessions.Store, owner string) error {
+		msg := sessions.Message{Seq: sent.seq, LobbySeq: sent.lobbySeq, Data: sent.data}
+		return store.Append(ctx, playerID, owner, msg, replayBufferSize)
+	})
+}
+
+// persistTrimLocked queues the messages up to seq to be forgotten by the store. The caller must hold s.mu.
+func (s *playerSession) persistTrimLocked(seq int64) {
+	playerID := s.playerID
+	s.persistLocked(func(ctx context.Context, store sessions.Store, owner string) error {
+		return store.Trim(ctx, playerID, owner, seq)
+	})
+}
+
+// persistSkipLocked queues the session's last seq moving on to seq in the store. The caller must hold s.mu.
+func (s *playerSession) persistSkipLocked(seq int64) {
+	playerID := s.playerID
+	s.persistLocked(func(ctx context.Context, store sessions.Store, owner string) error {
+		return store.SkipTo(ctx, playerID, owner, seq)
+	})
+}
+
+// unshare stops sharing the session and has the store forget it, once the player has left its lobby
+func (s *playerSession) unshare() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playerID, lobbyCode := s.playerID, s.lobbyCode
+	s.persistLocked(func(ctx context.Context, store sessions.Store, owner string) error {
+		return store.Drop(ctx, playerID, lobbyCode)
+	})
+	s.shared = nil
+}
@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultShutdownReconnectAfter is how long clients are told to wait before reconnecting when the server shuts down
+const DefaultShutdownReconnectAfter = 5 * time.Second
+
+// SetShutdownReconnectAfter sets how long clients are told to wait before reconnecting when the server shuts down
+func (h *Hub) SetShutdownReconnectAfter(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reconnectAfter = d
+}
+
+// ShuttingDown returns true once Shutdown has been called, after which no new connections are accepted
+func (h *Hub) ShuttingDown() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.drained != nil
+}
+
+// Shutdown stops accepting connections, tells every client the server is shutting down and when to reconnect,
+// and closes their connections once what is queued for them has been sent. It returns when every connection
+// has closed, or when ctx is done, in which case the rest are closed at once and ctx's error is returned.
+// The hub's main loop keeps running so connections can finish unregistering.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	if h.drained != nil {
+		drained := h.drained
+		h.mu.Unlock()
+		return h.waitForDrain(ctx, drained)
+	}
+	h.drained = make(chan struct{})
+	drained := h.drained
+	if len(h.connections) == 0 {
+		close(drained)
+	}
+	conns := make([]*Connection, 0, len(h.connections))
+	for conn := range h.connections {
+		conns = append(conns, conn)
+	}
+	payload := ServerShutdownPayload{ReconnectAfterMs: h.reconnectAfter.Milliseconds()}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.SendMessage(TypeServerShutdown, payload)
+		conn.CloseWithCode(websocket.CloseGoingAway, "Server shutting down")
+	}
+	return h.waitForDrain(ctx, drained)
+}
+
+// waitForDrain waits for every connection to unregister, closing any left when ctx is done
+func (h *Hub) waitForDrain(ctx context.Context, drained chan struct{}) error {
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+	}
+
+	h.mu.RLock()
+	conns := make([]*Connection, 0, len(h.connections))
+	for conn := range h.connections {
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+	for _, conn := range conns {
+		if conn.conn != nil {
+			conn.conn.Close() // Ends the read pump, which unregisters the connection
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,66 @@
+package websocket
+
+import (
+	"time"
+
+	"poke-battles/internal/game"
+)
+
+// ConnectionRole is whether a connection plays in its lobby or watches it
+type ConnectionRole string
+
+const (
+	RolePlayer     ConnectionRole = "player"
+	RoleSpectator  ConnectionRole = "spectator"
+	RoleWaitlisted ConnectionRole = "waitlisted" // Watches the lobby like a spectator until a slot opens
+)
+
+// BroadcastToSpectators sends a message to the connections watching a lobby, spectators and waitlisted players,
+// so they can be shown what its players are not. It is queued behind the lobby's earlier sends like
+// BroadcastToLobby, but is not numbered in the lobby's sequence or held for anyone away, since the next one
+// supersedes it.
+func (h *Hub) BroadcastToSpectators(lobbyCode string, msgType MessageType, payload interface{}) error {
+	h.flushBeforeSend(lobbyCode)
+	start := time.Now()
+
+	env, err := NewEnvelope(msgType, payload)
+	if err != nil {
+		return err
+	}
+	msg, err := prepareEnvelope(env)
+	if err != nil {
+		return err
+	}
+
+	shard := h.lobbyShard(lobbyCode)
+	shard.mu.RLock()
+	q := shard.queues[lobbyCode]
+	shard.mu.RUnlock()
+	if q == nil {
+		return nil // No one connected to watch
+	}
+	q.enqueue(func() {
+		reached := 0
+		for _, conn := range h.GetLobbyConnections(lobbyCode) {
+			if conn.State() == ConnectionStateActive && conn.IsSpectator() {
+				conn.deliver(msg, "")
+				reached++
+			}
+		}
+		h.metrics.fanOut.observe(float64(reached))
+		h.metrics.broadcastLatency.observeSince(start)
+	})
+	return nil
+}
+
+// catchUpSpectator sends a connection that has just started watching a lobby the full view of its battle
+func (h *Handler) catchUpSpectator(conn *Connection, lobbyCode string) {
+	battle, err := h.battleService.GetLobbyBattle(lobbyCode)
+	if err != nil {
+		return
+	}
+
+	h.battleService.Dispatch(battle.ID, func(battle *game.Battle) {
+		conn.SendMessage(TypeSpectatorState, buildSpectatorState(battle))
+	})
+}
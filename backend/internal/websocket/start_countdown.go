@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"time"
+)
+
+// startCountdown starts a lobby's game when its countdown ends
+type startCountdown struct {
+	timer *time.Timer
+}
+
+// handleCancelGameStart lets the host call off a game start while its countdown is running
+func (h *Handler) handleCancelGameStart(conn *Connection, env *Envelope) {
+	if conn.State() != ConnectionStateActive {
+		conn.SendError(ErrCodeAuthRequired, "Authentication required", env.CorrelationID)
+		return
+	}
+
+	lobbyCode := conn.LobbyCode()
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		conn.SendError(ErrCodeLobbyNotFound, "Lobby not found", env.CorrelationID)
+		return
+	}
+	if !lobby.IsHost(conn.PlayerID()) {
+		conn.SendError(ErrCodeInvalidAction, "Only the host can cancel the game start", env.CorrelationID)
+		return
+	}
+
+	if !h.cancelStartCountdown(lobbyCode, GameStartCancelledReasonHost, conn.PlayerID()) {
+		conn.SendError(ErrCodeInvalidState, "No game start countdown to cancel", env.CorrelationID)
+	}
+}
+
+// beginStartCountdown announces that the lobby's game starts after the countdown and starts it then.
+// It does nothing if a countdown is already running.
+func (h *Handler) beginStartCountdown(lobbyCode string, countdown time.Duration) {
+	h.timersMu.Lock()
+	if _, exists := h.startCountdowns[lobbyCode]; exists {
+		h.timersMu.Unlock()
+		return
+	}
+	c := &startCountdown{}
+	c.timer = time.AfterFunc(countdown, func() {
+		h.finishStartCountdown(lobbyCode, c)
+	})
+	h.startCountdowns[lobbyCode] = c
+	h.timersMu.Unlock()
+
+	h.BroadcastGameStarting(lobbyCode, int(countdown/time.Second))
+}
+
+// finishStartCountdown starts the game at the end of its countdown, if the lobby is still ready to start.
+// Otherwise the start is called off with game_start_cancelled.
+func (h *Handler) finishStartCountdown(lobbyCode string, c *startCountdown) {
+	h.timersMu.Lock()
+	if h.startCountdowns[lobbyCode] != c {
+		// Cancelled, or replaced by a later countdown, just as the timer fired
+		h.timersMu.Unlock()
+		return
+	}
+	delete(h.startCountdowns, lobbyCode)
+	h.timersMu.Unlock()
+
+	lobby, err := h.lobbyService.GetLobby(lobbyCode)
+	if err != nil {
+		return
+	}
+	if !h.canStartGame(lobby) {
+		h.hub.BroadcastToLobby(lobbyCode, TypeGameStartCancelled, GameStartCancelledPayload{Reason: GameStartCancelledReasonNotReady})
+		return
+	}
+
+	battle, err := h.battleService.StartBattle(lobby)
+	if err != nil {
+		return
+	}
+	h.broadcastGameStarted(lobbyCode, battle)
+	h.openGame(lobbyCode, battle.ID)
+}
+
+// cancelStartCountdown calls off the lobby's pending game start, telling its clients why.
+// It returns false if no countdown was running.
+func (h *Handler) cancelStartCountdown(lobbyCode string, reason GameStartCancelledReason, playerID string) bool {
+	if !h.stopStartCountdown(lobbyCode) {
+		return false
+	}
+	h.hub.BroadcastToLobby(lobbyCode, TypeGameStartCancelled, GameStartCancelledPayload{
+		Reason:   reason,
+		PlayerID: playerID,
+	})
+	return true
+}
+
+// stopStartCountdown cancels a lobby's pending game start without telling anyone, returning false if none was running
+func (h *Handler) stopStartCountdown(lobbyCode string) bool {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	c, exists := h.startCountdowns[lobbyCode]
+	if !exists {
+		return false
+	}
+	c.timer.Stop()
+	delete(h.startCountdowns, lobbyCode)
+	return true
+}
+
+// startCountdownRunning reports whether a lobby's game start countdown is running
+func (h *Handler) startCountdownRunning(lobbyCode string) bool {
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+
+	_, exists := h.startCountdowns[lobbyCode]
+	return exists
+}
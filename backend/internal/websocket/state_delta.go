@@ -0,0 +1,159 @@
+package websocket
+
+import (
+	"reflect"
+
+	"poke-battles/internal/game"
+)
+
+// recordStateView stamps a state about to be sent to a player with the next revision and keeps it
+// as their latest view. It returns the stamped state and the delta from the player's previous
+// view, or nil if they have not received a state since it was last reset.
+func (h *Handler) recordStateView(playerID string, state GameStatePayload) (GameStatePayload, *GameStateDeltaPayload) {
+	h.viewsMu.Lock()
+	defer h.viewsMu.Unlock()
+
+	prev, ok := h.stateViews[playerID]
+	state.Revision = prev.Revision + 1
+	h.stateViews[playerID] = state
+	if !ok {
+		return state, nil
+	}
+
+	delta := diffGameState(prev, state)
+	return state, &delta
+}
+
+// resetStateViews forgets the states sent to the players, so the next state each receives is complete
+func (h *Handler) resetStateViews(playerIDs ...string) {
+	h.viewsMu.Lock()
+	defer h.viewsMu.Unlock()
+
+	for _, playerID := range playerIDs {
+		delete(h.stateViews, playerID)
+	}
+}
+
+// sendGameState sends a player their view of the battle on every device they are on: a game_state_delta
+// against the last state they received if they opted into delta updates, otherwise the full game_state
+func (h *Handler) sendGameState(battle *game.Battle, playerID string) {
+	if !h.hub.IsPlayerConnected(playerID) {
+		return
+	}
+
+	state, delta := h.recordStateView(playerID, buildGameState(battle, playerID))
+	if delta != nil && h.hub.PlayerDeltaUpdates(playerID) {
+		h.hub.SendToPlayer(playerID, TypeGameStateDelta, delta)
+		return
+	}
+	h.hub.SendToPlayer(playerID, TypeGameState, state)
+}
+
+// diffGameState lists what changed between two views of the battle sent to the same player
+func diffGameState(prev, next GameStatePayload) GameStateDeltaPayload {
+	delta := GameStateDeltaPayload{
+		BaseRevision: prev.Revision,
+		Revision:     next.Revision,
+		TurnNumber:   next.TurnNumber,
+		Phase:        next.Phase,
+		Player:       diffOwnSide(prev.PlayerState, next.PlayerState),
+		Opponent:     diffOpponentSide(prev.OpponentState, next.OpponentState),
+	}
+	if prev.Field != next.Field {
+		field := next.Field
+		delta.Field = &field
+	}
+	return delta
+}
+
+// diffOwnSide lists what changed on the player's own side, or nil if nothing did
+func diffOwnSide(prev, next PlayerBattleState) *SideDelta {
+	var delta SideDelta
+	if prev.ActiveSlot != next.ActiveSlot {
+		delta.ActiveSlot = intPtr(next.ActiveSlot)
+	}
+
+	for i, c := range next.Team {
+		if i >= len(prev.Team) {
+			break
+		}
+		old := prev.Team[i]
+		change := CreatureDelta{Slot: i}
+		if old.CurrentHP != c.CurrentHP {
+			change.CurrentHP = intPtr(c.CurrentHP)
+		}
+		if old.Status != c.Status {
+			change.Status = stringPtr(c.Status)
+		}
+		for j, m := range c.Moves {
+			if j < len(old.Moves) && old.Moves[j].PP != m.PP {
+				if change.PP == nil {
+					change.PP = make(map[string]int)
+				}
+				change.PP[m.ID] = m.PP
+			}
+		}
+		if change.CurrentHP != nil || change.Status != nil || change.PP != nil {
+			delta.Creatures = append(delta.Creatures, change)
+		}
+	}
+
+	if !reflect.DeepEqual(prev.Bag, next.Bag) {
+		delta.Bag = next.Bag
+	}
+	diffHazards(&delta, prev.Hazards, next.Hazards)
+	return nonEmpty(delta)
+}
+
+// diffOpponentSide lists what changed in the public view of the opposing side, or nil if nothing did
+func diffOpponentSide(prev, next PlayerBattleState) *SideDelta {
+	var delta SideDelta
+	if prev.ActiveSlot != next.ActiveSlot {
+		delta.ActiveSlot = intPtr(next.ActiveSlot)
+	}
+	if prev.ActiveHP != next.ActiveHP {
+		delta.ActiveHP = intPtr(next.ActiveHP)
+	}
+	if prev.ActiveMaxHP != next.ActiveMaxHP {
+		delta.ActiveMaxHP = intPtr(next.ActiveMaxHP)
+	}
+	if prev.ActiveStatus != next.ActiveStatus {
+		delta.ActiveStatus = stringPtr(next.ActiveStatus)
+	}
+	if !reflect.DeepEqual(prev.Revealed, next.Revealed) {
+		delta.Revealed = next.Revealed
+	}
+	if !reflect.DeepEqual(prev.ItemsUsed, next.ItemsUsed) {
+		delta.ItemsUsed = next.ItemsUsed
+	}
+	diffHazards(&delta, prev.Hazards, next.Hazards)
+	return nonEmpty(delta)
+}
+
+// diffHazards records a change to a side's entry hazards
+func diffHazards(delta *SideDelta, prev, next *HazardsInfo) {
+	if reflect.DeepEqual(prev, next) {
+		return
+	}
+	if next == nil {
+		delta.HazardsCleared = true
+		return
+	}
+	delta.Hazards = next
+}
+
+// nonEmpty returns the delta, or nil if it records no changes
+func nonEmpty(delta SideDelta) *SideDelta {
+	if reflect.DeepEqual(delta, SideDelta{}) {
+		return nil
+	}
+	return &delta
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
@@ -2,13 +2,22 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"poke-battles/internal/audit"
+	"poke-battles/internal/events"
+	"poke-battles/internal/game"
+	"poke-battles/internal/middleware"
+	"poke-battles/internal/replay"
 	"poke-battles/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -21,33 +30,66 @@ import (
 
 // TestServer wraps an httptest.Server with WebSocket infrastructure
 type TestServer struct {
-	Server       *httptest.Server
-	Handler      *Handler
-	Hub          *Hub
-	LobbyService services.LobbyService
+	Server        *httptest.Server
+	Handler       *Handler
+	Hub           *Hub
+	LobbyService  services.LobbyService
+	BattleService services.BattleService
+	ReplayStore   replay.Store
+	AuditTrail    audit.Trail
 
 	mu       sync.Mutex
 	shutdown bool
 }
 
+// testAdminToken is the admin token the test server's operator-only routes require
+const testAdminToken = "test-admin-token"
+
+// adminGet makes a GET request to an operator-only route of the test server
+func adminGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return http.DefaultClient.Do(req)
+}
+
 // NewTestServer creates a new test server with WebSocket support
 func NewTestServer() *TestServer {
 	gin.SetMode(gin.TestMode)
 
 	hub := NewHub()
-	lobbyService := services.NewLobbyService()
-	handler := NewHandler(hub, lobbyService)
+	hub.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	bus := events.NewBus()
+	lobbyService := services.NewLobbyServiceWithConfig(services.LobbyServiceConfig{
+		MaxLobbiesPerPlayer: services.DefaultMaxLobbiesPerPlayer,
+		Events:              bus,
+	})
+	replayStore := replay.NewMemoryStore()
+	auditTrail := audit.NewMemoryTrail(audit.DefaultCapacity)
+	battleService := services.NewBattleService(replayStore, auditTrail, bus)
+	handler := NewHandler(hub, lobbyService, battleService)
+	handler.Subscribe(bus)
 
 	router := gin.New()
+	router.SetTrustedProxies(nil) // As cmd/api does without TRUSTED_PROXIES
 	router.GET("/api/v1/ws/game/:code", handler.HandleConnection)
+	router.GET("/api/v1/ws/metrics", handler.HandleMetrics)
+	admin := router.Group("/api/v1/ws", middleware.AdminToken(testAdminToken))
+	admin.GET("/connections", handler.HandleConnections)
+	admin.GET("/connections/:id", handler.HandleConnectionInfo)
 
 	server := httptest.NewServer(router)
 
 	ts := &TestServer{
-		Server:       server,
-		Handler:      handler,
-		Hub:          hub,
-		LobbyService: lobbyService,
+		Server:        server,
+		Handler:       handler,
+		Hub:           hub,
+		LobbyService:  lobbyService,
+		BattleService: battleService,
+		ReplayStore:   replayStore,
+		AuditTrail:    auditTrail,
 	}
 
 	go hub.Run()
@@ -76,7 +118,7 @@ func (ts *TestServer) WebSocketURL(lobbyCode string) string {
 
 // CreateLobby creates a lobby and returns its code
 func (ts *TestServer) CreateLobby(hostID, username string) (string, error) {
-	lobby, err := ts.LobbyService.CreateLobby(hostID, username)
+	lobby, err := ts.LobbyService.CreateLobby(hostID, username, game.DefaultMaxPlayers, game.LobbyVisibilityPublic)
 	if err != nil {
 		return "", err
 	}
@@ -89,6 +131,75 @@ func (ts *TestServer) JoinLobby(code, playerID, username string) error {
 	return err
 }
 
+// StartBattle creates a lobby with two connected, ready players using the starter team and waits for the game to start.
+// Both clients are drained before returning.
+func (ts *TestServer) StartBattle() (string, *TestClient, *TestClient, error) {
+	return ts.startBattle(func(string) error { return nil })
+}
+
+// StartBattleWithRuleset is StartBattle for a lobby playing under the given ruleset.
+// The ruleset must not use team preview.
+func (ts *TestServer) StartBattleWithRuleset(rulesetID string) (string, *TestClient, *TestClient, error) {
+	return ts.startBattle(func(lobbyCode string) error {
+		_, err := ts.LobbyService.SetRuleset(lobbyCode, "player-1", rulesetID)
+		return err
+	})
+}
+
+// StartSeriesBattle is StartBattle for a lobby configured as a best-of-N series
+func (ts *TestServer) StartSeriesBattle(bestOf int) (string, *TestClient, *TestClient, error) {
+	return ts.startBattle(func(lobbyCode string) error {
+		_, err := ts.LobbyService.SetSeriesLength(lobbyCode, "player-1", bestOf)
+		return err
+	})
+}
+
+// startBattle runs the StartBattle flow, calling configure on the full lobby before players connect
+func (ts *TestServer) startBattle(configure func(lobbyCode string) error) (string, *TestClient, *TestClient, error) {
+	lobbyCode, err := ts.CreateLobby("player-1", "Player1")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if err := ts.JoinLobby(lobbyCode, "player-2", "Player2"); err != nil {
+		return "", nil, nil, err
+	}
+	if err := configure(lobbyCode); err != nil {
+		return "", nil, nil, err
+	}
+
+	clients := make([]*TestClient, 2)
+	for i, playerID := range []string{"player-1", "player-2"} {
+		client, err := NewTestClient(ts.WebSocketURL(lobbyCode))
+		if err != nil {
+			return "", nil, nil, err
+		}
+		clients[i] = client
+		if err := client.SendAuth(playerID, lobbyCode); err != nil {
+			return "", nil, nil, err
+		}
+		if _, err := client.AssertAuthSuccess(testTimeout); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	for _, client := range clients {
+		if err := client.SendSubmitTeam(starterTeamPayload()); err != nil {
+			return "", nil, nil, err
+		}
+		if err := client.SendReady(true); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	for _, client := range clients {
+		if _, err := client.ReceiveType(TypeGameStarted, testTimeout); err != nil {
+			return "", nil, nil, err
+		}
+		client.Drain()
+	}
+
+	return lobbyCode, clients[0], clients[1], nil
+}
+
 // WaitForPlayerConnected waits for a player to be connected
 func (ts *TestServer) WaitForPlayerConnected(playerID string, timeout time.Duration) bool {
 	return waitFor(func() bool {
@@ -117,13 +228,28 @@ type TestClient struct {
 	received chan *Envelope
 	done     chan struct{}
 	closed   bool
+	closeErr error // Why the read loop stopped, readable once done is closed
 }
 
 // NewTestClient creates a test client connected to the server
 func NewTestClient(serverURL string) (*TestClient, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+	tc, _, err := dialTestClient(websocket.DefaultDialer, serverURL)
+	return tc, err
+}
+
+// NewCompressedTestClient creates a test client that offers permessage-deflate,
+// returning the extensions the server agreed to
+func NewCompressedTestClient(serverURL string) (*TestClient, string, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = true
+	return dialTestClient(&dialer, serverURL)
+}
+
+// dialTestClient connects a test client with the dialer, returning the negotiated extensions
+func dialTestClient(dialer *websocket.Dialer, serverURL string) (*TestClient, string, error) {
+	conn, resp, err := dialer.Dial(serverURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("dial failed: %w", err)
+		return nil, "", fmt.Errorf("dial failed: %w", err)
 	}
 
 	tc := &TestClient{
@@ -134,7 +260,7 @@ func NewTestClient(serverURL string) (*TestClient, error) {
 
 	go tc.readLoop()
 
-	return tc, nil
+	return tc, resp.Header.Get("Sec-WebSocket-Extensions"), nil
 }
 
 // readLoop reads messages from the WebSocket and queues them
@@ -144,6 +270,7 @@ func (tc *TestClient) readLoop() {
 	for {
 		_, message, err := tc.conn.ReadMessage()
 		if err != nil {
+			tc.closeErr = err
 			return
 		}
 
@@ -191,19 +318,49 @@ func (tc *TestClient) Send(env *Envelope) error {
 
 // SendAuth sends an authentication message
 func (tc *TestClient) SendAuth(playerID, lobbyCode string) error {
-	tc.PlayerID = playerID
-	tc.LobbyCode = lobbyCode
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode})
+}
 
-	payload := AuthenticatePayload{
-		PlayerID:  playerID,
-		LobbyCode: lobbyCode,
-	}
+// SendAuthWithDeltaUpdates sends an authentication message opting into game_state_delta updates
+func (tc *TestClient) SendAuthWithDeltaUpdates(playerID, lobbyCode string) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, DeltaUpdates: true})
+}
+
+// SendAuthAsSpectator sends an authentication message joining the lobby's spectator roster
+func (tc *TestClient) SendAuthAsSpectator(playerID, username, lobbyCode string) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, Spectate: true, Username: username})
+}
+
+// SendAuthWithLastSeq sends an authentication message asking for the messages after lastSeq to be replayed
+func (tc *TestClient) SendAuthWithLastSeq(playerID, lobbyCode string, lastSeq int64) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, LastSeq: lastSeq})
+}
+
+// SendAuthWithLastLobbySeq sends an authentication message asking for the lobby's broadcasts after lastLobbySeq to be replayed
+func (tc *TestClient) SendAuthWithLastLobbySeq(playerID, lobbyCode string, lastLobbySeq int64) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, LastLobbySeq: lastLobbySeq})
+}
+
+// SendAuthWithSessionToken sends an authentication message carrying a session token
+func (tc *TestClient) SendAuthWithSessionToken(playerID, lobbyCode, sessionToken string) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, SessionToken: sessionToken})
+}
+
+// SendAuthAsSecondary sends an authentication message joining the player's other devices without taking over as primary
+func (tc *TestClient) SendAuthAsSecondary(playerID, lobbyCode string) error {
+	return tc.sendAuth(AuthenticatePayload{PlayerID: playerID, LobbyCode: lobbyCode, Secondary: true})
+}
+
+// sendAuth sends an authentication message with the given payload
+func (tc *TestClient) sendAuth(payload AuthenticatePayload) error {
+	tc.PlayerID = payload.PlayerID
+	tc.LobbyCode = payload.LobbyCode
 
 	env, err := NewEnvelope(TypeAuthenticate, payload)
 	if err != nil {
 		return err
 	}
-	env.CorrelationID = "auth-" + playerID
+	env.CorrelationID = "auth-" + payload.PlayerID
 
 	return tc.Send(env)
 }
@@ -219,6 +376,199 @@ func (tc *TestClient) SendReady(ready bool) error {
 	return tc.Send(env)
 }
 
+// SendSubmitTeam sends a submit_team message
+func (tc *TestClient) SendSubmitTeam(team []TeamMemberPayload) error {
+	env, err := NewEnvelope(TypeSubmitTeam, SubmitTeamPayload{Team: team})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "team-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendChooseLead sends a choose_lead message
+func (tc *TestClient) SendChooseLead(slot int) error {
+	env, err := NewEnvelope(TypeChooseLead, ChooseLeadPayload{Slot: slot})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = fmt.Sprintf("lead-%s-%d", tc.PlayerID, slot)
+	return tc.Send(env)
+}
+
+// SendSubmitPick sends a submit_pick message
+func (tc *TestClient) SendSubmitPick(speciesID string) error {
+	env, err := NewEnvelope(TypeSubmitPick, SubmitPickPayload{SpeciesID: speciesID})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "pick-" + tc.PlayerID + "-" + speciesID
+	return tc.Send(env)
+}
+
+// SendStartGame sends a start_game message
+func (tc *TestClient) SendStartGame() error {
+	env, err := NewEnvelope(TypeStartGame, StartGamePayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "start-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendCancelGameStart sends a cancel_game_start message
+func (tc *TestClient) SendCancelGameStart() error {
+	env, err := NewEnvelope(TypeCancelGameStart, CancelGameStartPayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "cancel-start-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// starterTeamPayload returns the starter team as a submit_team payload
+func starterTeamPayload() []TeamMemberPayload {
+	starter := game.StarterTeam()
+	team := make([]TeamMemberPayload, len(starter))
+	for i, m := range starter {
+		team[i] = TeamMemberPayload{SpeciesID: m.SpeciesID, Level: m.Level, Moves: m.Moves}
+	}
+	return team
+}
+
+// SendForfeit sends a forfeit action
+func (tc *TestClient) SendForfeit(turn int) error {
+	return tc.SendAction(turn, ActionTypeForfeit, struct{}{})
+}
+
+// SendOfferDraw sends an offer_draw message
+func (tc *TestClient) SendOfferDraw() error {
+	env, err := NewEnvelope(TypeOfferDraw, OfferDrawPayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "draw-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRespondDraw sends a respond_draw message
+func (tc *TestClient) SendRespondDraw(accept bool) error {
+	env, err := NewEnvelope(TypeRespondDraw, RespondDrawPayload{Accept: accept})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "draw-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRequestRematch sends a request_rematch message
+func (tc *TestClient) SendRequestRematch() error {
+	env, err := NewEnvelope(TypeRequestRematch, RequestRematchPayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "rematch-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRequestPause sends a request_pause message
+func (tc *TestClient) SendRequestPause() error {
+	env, err := NewEnvelope(TypeRequestPause, RequestPausePayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "pause-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRespondPause sends a respond_pause message
+func (tc *TestClient) SendRespondPause(accept bool) error {
+	env, err := NewEnvelope(TypeRespondPause, RespondPausePayload{Accept: accept})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "pause-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRequestResume sends a request_resume message
+func (tc *TestClient) SendRequestResume() error {
+	env, err := NewEnvelope(TypeRequestResume, RequestResumePayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "resume-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRequestGameState sends a request_game_state message
+func (tc *TestClient) SendRequestGameState() error {
+	env, err := NewEnvelope(TypeRequestGameState, RequestGameStatePayload{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "state-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendChat sends a chat_message
+func (tc *TestClient) SendChat(text string) error {
+	env, err := NewEnvelope(TypeChatMessage, ChatMessagePayload{Text: text})
+	if err != nil {
+		return err
+	}
+	return tc.Send(env)
+}
+
+// SendTimeSync asks for the server's clock, sent at clientSendTime
+func (tc *TestClient) SendTimeSync(clientSendTime int64) error {
+	env, err := NewEnvelope(TypeTimeSync, TimeSyncPayload{ClientSendTime: clientSendTime})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "time-sync-1"
+	return tc.Send(env)
+}
+
+// SendAck sends an ack for the broadcasts up to lobbySeq
+func (tc *TestClient) SendAck(lobbySeq int64) error {
+	env, err := NewEnvelope(TypeAck, AckPayload{LobbySeq: lobbySeq})
+	if err != nil {
+		return err
+	}
+	return tc.Send(env)
+}
+
+// SendResyncRequest sends a resync_request for the broadcasts after lastLobbySeq
+func (tc *TestClient) SendResyncRequest(lastLobbySeq int64) error {
+	env, err := NewEnvelope(TypeResyncRequest, ResyncRequestPayload{LastLobbySeq: lastLobbySeq})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "resync-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendRequestLobbyStateSince sends a request_lobby_state message asking for the lobby changes after sinceSeq
+func (tc *TestClient) SendRequestLobbyStateSince(sinceSeq int64) error {
+	env, err := NewEnvelope(TypeRequestLobbyState, RequestLobbyStatePayload{SinceSeq: sinceSeq})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "lobby-state-" + tc.PlayerID
+	return tc.Send(env)
+}
+
+// SendClaimPrimary sends a claim_primary message
+func (tc *TestClient) SendClaimPrimary() error {
+	env, err := NewEnvelope(TypeClaimPrimary, struct{}{})
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = "claim-" + tc.PlayerID
+	return tc.Send(env)
+}
+
 // SendHeartbeat sends a heartbeat message
 func (tc *TestClient) SendHeartbeat() error {
 	env, err := NewEnvelope(TypeHeartbeat, HeartbeatPayload{})
@@ -229,6 +579,41 @@ func (tc *TestClient) SendHeartbeat() error {
 	return tc.Send(env)
 }
 
+// SendAction sends a submit_action message with the given action data
+func (tc *TestClient) SendAction(turn int, actionType ActionType, data interface{}) error {
+	return tc.SendGameAction("", turn, actionType, data)
+}
+
+// SendGameAction sends a battle action addressed to a specific game
+func (tc *TestClient) SendGameAction(gameID string, turn int, actionType ActionType, data interface{}) error {
+	actionData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload := SubmitActionPayload{
+		GameID:     gameID,
+		TurnNumber: turn,
+		ActionType: actionType,
+		ActionData: actionData,
+	}
+	env, err := NewEnvelope(TypeSubmitAction, payload)
+	if err != nil {
+		return err
+	}
+	env.CorrelationID = fmt.Sprintf("action-%s-%d", tc.PlayerID, turn)
+	return tc.Send(env)
+}
+
+// SendAttack sends an attack action for the given move
+func (tc *TestClient) SendAttack(turn int, moveID string) error {
+	return tc.SendAction(turn, ActionTypeAttack, AttackActionData{MoveID: moveID})
+}
+
+// SendItem sends an item action targeting a team slot
+func (tc *TestClient) SendItem(turn int, itemID string, slot int) error {
+	return tc.SendAction(turn, ActionTypeItem, ItemActionData{ItemID: itemID, TargetSlot: slot})
+}
+
 // Receive waits for any message with timeout
 func (tc *TestClient) Receive(timeout time.Duration) (*Envelope, error) {
 	select {
@@ -260,7 +645,17 @@ func (tc *TestClient) ReceiveType(msgType MessageType, timeout time.Duration) (*
 		case <-time.After(remaining):
 			return nil, fmt.Errorf("timeout waiting for %s after %v", msgType, timeout)
 		case <-tc.done:
-			return nil, fmt.Errorf("connection closed while waiting for %s", msgType)
+			// Messages read before the server closed the connection still count
+			for {
+				select {
+				case env := <-tc.received:
+					if env.Type == msgType {
+						return env, nil
+					}
+				default:
+					return nil, fmt.Errorf("connection closed while waiting for %s", msgType)
+				}
+			}
 		}
 	}
 
@@ -286,6 +681,28 @@ func (tc *TestClient) ExpectError(code ErrorCode, timeout time.Duration) error {
 	return nil
 }
 
+// ExpectInvalidFields waits for a MALFORMED_MESSAGE error and returns the invalid fields it lists
+func (tc *TestClient) ExpectInvalidFields(timeout time.Duration) ([]FieldErrorInfo, error) {
+	env, err := tc.ReceiveType(TypeError, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var errPayload ErrorPayload
+	if err := env.ParsePayload(&errPayload); err != nil {
+		return nil, fmt.Errorf("failed to parse error payload: %w", err)
+	}
+	if errPayload.Code != ErrCodeMalformedMessage {
+		return nil, fmt.Errorf("expected error code %s, got %s: %s", ErrCodeMalformedMessage, errPayload.Code, errPayload.Message)
+	}
+
+	var details InvalidPayloadDetails
+	if err := json.Unmarshal(errPayload.Details, &details); err != nil {
+		return nil, fmt.Errorf("failed to parse error details: %w", err)
+	}
+	return details.Fields, nil
+}
+
 // Drain clears all pending messages from the receive buffer
 func (tc *TestClient) Drain() {
 	for {
@@ -297,6 +714,24 @@ func (tc *TestClient) Drain() {
 	}
 }
 
+// ExpectClose waits for the server to close the connection and checks its close code
+func (tc *TestClient) ExpectClose(code int, timeout time.Duration) error {
+	select {
+	case <-tc.done:
+	case <-time.After(timeout):
+		return fmt.Errorf("timeout waiting for close %d after %v", code, timeout)
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(tc.closeErr, &closeErr) {
+		return fmt.Errorf("expected close %d, connection ended with %v", code, tc.closeErr)
+	}
+	if closeErr.Code != code {
+		return fmt.Errorf("expected close %d, got %d (%s)", code, closeErr.Code, closeErr.Text)
+	}
+	return nil
+}
+
 // PendingCount returns the number of pending messages
 func (tc *TestClient) PendingCount() int {
 	return len(tc.received)
@@ -358,3 +793,23 @@ func (tc *TestClient) AssertLobbyUpdated(timeout time.Duration) (*LobbyUpdatedPa
 
 	return &payload, nil
 }
+
+// lobbyEvents returns the changes of a kind that a lobby update reports, oldest first. Changes made within the
+// coalescing window arrive in one update, so the one a test waits for may be among its earlier events.
+func lobbyEvents(update *LobbyUpdatedPayload, event LobbyEvent) []LobbyEventRecord {
+	var records []LobbyEventRecord
+	for _, record := range update.EarlierEvents {
+		if record.Event == event {
+			records = append(records, record)
+		}
+	}
+	if update.Event == event {
+		records = append(records, LobbyEventRecord{Event: update.Event, EventData: update.EventData})
+	}
+	return records
+}
+
+// hasLobbyEvent reports whether a lobby update reports a change of the kind
+func hasLobbyEvent(update *LobbyUpdatedPayload, event LobbyEvent) bool {
+	return len(lobbyEvents(update, event)) > 0
+}
@@ -0,0 +1,269 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// Longest strings accepted in client payloads, in characters
+const (
+	maxIDLength      = 64   // Player IDs, lobby codes, game IDs and catalogue IDs such as species and moves
+	maxNameLength    = 64   // Usernames and nicknames, before sanitizing
+	maxTokenLength   = 1024 // Session and reconnect tokens
+	maxVersionLength = 64   // Client versions
+)
+
+// Largest payloads accepted for each message type, in bytes of JSON, well within the connection's overall
+// message size limit. Types not listed are only held to that.
+const (
+	maxSmallPayloadSize  = 256  // Messages carrying a few numbers or IDs
+	maxChatPayloadSize   = 500  // A chat message, so text in multi-byte scripts may be held to fewer characters
+	maxActionPayloadSize = 2048 // A battle action and its data
+	maxAuthPayloadSize   = 4096 // IDs, a username and two tokens
+	maxTeamPayloadSize   = 4096 // A full team with nicknames, items and moves
+)
+
+// payloadSizeLimits are the largest payloads accepted for each message type, in bytes
+var payloadSizeLimits = map[MessageType]int{
+	TypeAuthenticate:      maxAuthPayloadSize,
+	TypeHeartbeat:         maxSmallPayloadSize,
+	TypeTimeSync:          maxSmallPayloadSize,
+	TypeClaimPrimary:      maxSmallPayloadSize,
+	TypeRequestLobbyState: maxSmallPayloadSize,
+	TypeSetReady:          maxSmallPayloadSize,
+	TypeSubmitTeam:        maxTeamPayloadSize,
+	TypeSubmitPick:        maxSmallPayloadSize,
+	TypeStartGame:         maxSmallPayloadSize,
+	TypeCancelGameStart:   maxSmallPayloadSize,
+	TypeChooseLead:        maxSmallPayloadSize,
+	TypeSubmitAction:      maxActionPayloadSize,
+	TypeRequestGameState:  maxSmallPayloadSize,
+	TypeOfferDraw:         maxSmallPayloadSize,
+	TypeRespondDraw:       maxSmallPayloadSize,
+	TypeRequestPause:      maxSmallPayloadSize,
+	TypeRespondPause:      maxSmallPayloadSize,
+	TypeRequestResume:     maxSmallPayloadSize,
+	TypeRequestRematch:    maxSmallPayloadSize,
+	TypeLeaveGame:         maxSmallPayloadSize,
+	TypeChatMessage:       maxChatPayloadSize,
+	TypeAck:               maxSmallPayloadSize,
+	TypeResyncRequest:     maxSmallPayloadSize,
+}
+
+// checkPayloadSize tells the connection with a PAYLOAD_TOO_LARGE error if the envelope's payload is over its
+// type's size limit, before any of it is decoded. It returns false if the payload must not be handled.
+func checkPayloadSize(conn *Connection, env *Envelope) bool {
+	limit, ok := payloadSizeLimits[env.Type]
+	if !ok || len(env.Payload) <= limit {
+		return true
+	}
+	conn.SendErrorWithDetails(ErrCodePayloadTooLarge,
+		fmt.Sprintf("%s payload is %d bytes, over its limit of %d", env.Type, len(env.Payload), limit),
+		PayloadTooLargeDetails{Size: len(env.Payload), Limit: limit}, env.CorrelationID)
+	return false
+}
+
+// payloadValidator is a client payload with rules beyond what decoding it checks
+type payloadValidator interface {
+	validate(v *fieldValidator)
+}
+
+// fieldValidator collects a payload's invalid fields
+type fieldValidator struct {
+	fields []FieldErrorInfo
+}
+
+// invalid records a field as invalid
+func (v *fieldValidator) invalid(field, reason string) {
+	v.fields = append(v.fields, FieldErrorInfo{Field: field, Reason: reason})
+}
+
+// required records the field as invalid if it is empty
+func (v *fieldValidator) required(field, value string) {
+	if value == "" {
+		v.invalid(field, "is required")
+	}
+}
+
+// maxLength records the field as invalid if it is longer than max characters
+func (v *fieldValidator) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.invalid(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+// id records the field as invalid if it is longer than an ID can be, or empty when required
+func (v *fieldValidator) id(field, value string, required bool) {
+	if required {
+		v.required(field, value)
+	}
+	v.maxLength(field, value, maxIDLength)
+}
+
+// atLeast records the field as invalid if it is below min
+func (v *fieldValidator) atLeast(field string, value, min int64) {
+	if value < min {
+		v.invalid(field, fmt.Sprintf("must be at least %d", min))
+	}
+}
+
+// parsePayload decodes and validates the envelope's payload, telling the connection which fields are invalid
+// with a MALFORMED_MESSAGE error if any are. It returns false if the payload must not be handled.
+func parsePayload(conn *Connection, env *Envelope, payload interface{}) bool {
+	fields := decodePayload(env, payload)
+	if len(fields) == 0 {
+		return true
+	}
+	conn.SendErrorWithDetails(ErrCodeMalformedMessage, "Invalid "+string(env.Type)+" payload",
+		InvalidPayloadDetails{Fields: fields}, env.CorrelationID)
+	return false
+}
+
+// decodePayload decodes the envelope's payload, returning its invalid fields
+func decodePayload(env *Envelope, payload interface{}) []FieldErrorInfo {
+	if err := env.ParsePayload(payload); err != nil {
+		return []FieldErrorInfo{decodeFieldError(err)}
+	}
+	p, ok := payload.(payloadValidator)
+	if !ok {
+		return nil
+	}
+	var v fieldValidator
+	p.validate(&v)
+	return v.fields
+}
+
+// decodeFieldError describes why a payload failed to decode
+func decodeFieldError(err error) FieldErrorInfo {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return FieldErrorInfo{Field: typeErr.Field, Reason: "must be " + jsonKind(typeErr.Type) + ", not " + typeErr.Value}
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return FieldErrorInfo{Reason: "is not valid JSON"}
+	}
+	return FieldErrorInfo{Reason: err.Error()}
+}
+
+// jsonKind names the JSON value a Go type decodes from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+func (p *AuthenticatePayload) validate(v *fieldValidator) {
+	v.id("player_id", p.PlayerID, true)
+	v.id("lobby_code", p.LobbyCode, true)
+	v.maxLength("session_token", p.SessionToken, maxTokenLength)
+	v.maxLength("reconnect_token", p.ReconnectToken, maxTokenLength)
+	v.atLeast("last_seq", p.LastSeq, 0)
+	v.atLeast("last_lobby_seq", p.LastLobbySeq, 0)
+	v.maxLength("username", p.Username, maxNameLength)
+	if p.Capabilities != nil {
+		v.maxLength("capabilities.client_version", p.Capabilities.ClientVersion, maxVersionLength)
+	}
+}
+
+func (p *TimeSyncPayload) validate(v *fieldValidator) {
+	v.atLeast("client_send_time", p.ClientSendTime, 0)
+}
+
+func (p *RequestLobbyStatePayload) validate(v *fieldValidator) {
+	v.atLeast("since_seq", p.SinceSeq, 0)
+}
+
+func (p *AckPayload) validate(v *fieldValidator) {
+	v.atLeast("lobby_seq", p.LobbySeq, 0)
+}
+
+func (p *ResyncRequestPayload) validate(v *fieldValidator) {
+	v.atLeast("last_lobby_seq", p.LastLobbySeq, 0)
+}
+
+func (p *SubmitTeamPayload) validate(v *fieldValidator) {
+	if p.Team == nil {
+		v.invalid("team", "is required")
+	}
+	for i, m := range p.Team {
+		field := fmt.Sprintf("team[%d].", i)
+		v.id(field+"species_id", m.SpeciesID, true)
+		v.atLeast(field+"level", int64(m.Level), 0)
+		v.id(field+"item", m.Item, false)
+		v.maxLength(field+"nickname", m.Nickname, maxNameLength)
+		for j, move := range m.Moves {
+			v.id(fmt.Sprintf("%smoves[%d]", field, j), move, true)
+		}
+	}
+}
+
+func (p *SubmitPickPayload) validate(v *fieldValidator) {
+	v.id("species_id", p.SpeciesID, true)
+}
+
+func (p *ChooseLeadPayload) validate(v *fieldValidator) {
+	v.atLeast("slot", int64(p.Slot), 0)
+}
+
+// validate checks the action's data as well, against the rules of its action type
+func (p *SubmitActionPayload) validate(v *fieldValidator) {
+	v.id("game_id", p.GameID, false)
+	v.atLeast("turn_number", int64(p.TurnNumber), 0)
+	v.required("action_type", string(p.ActionType))
+
+	var data payloadValidator
+	switch p.ActionType {
+	case ActionTypeAttack:
+		data = &AttackActionData{}
+	case ActionTypeSwitch:
+		data = &SwitchActionData{}
+	case ActionTypeItem:
+		data = &ItemActionData{}
+	default:
+		return
+	}
+	if err := json.Unmarshal(p.ActionData, data); err != nil {
+		fieldErr := decodeFieldError(err)
+		if fieldErr.Field == "" {
+			fieldErr.Field = "action_data"
+		} else {
+			fieldErr.Field = "action_data." + fieldErr.Field
+		}
+		v.fields = append(v.fields, fieldErr)
+		return
+	}
+	nested := fieldValidator{}
+	data.validate(&nested)
+	for _, f := range nested.fields {
+		v.invalid("action_data."+f.Field, f.Reason)
+	}
+}
+
+func (d *AttackActionData) validate(v *fieldValidator) {
+	v.id("move_id", d.MoveID, true)
+}
+
+func (d *SwitchActionData) validate(v *fieldValidator) {
+	v.atLeast("creature_slot", int64(d.CreatureSlot), 0)
+}
+
+func (d *ItemActionData) validate(v *fieldValidator) {
+	v.id("item_id", d.ItemID, true)
+	v.atLeast("target_slot", int64(d.TargetSlot), 0)
+}
@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		msgType MessageType
+		payload string
+		into    interface{}
+		fields  []string
+	}{
+		{"valid", TypeAuthenticate, `{"player_id":"player-1","lobby_code":"ABC123"}`, &AuthenticatePayload{}, nil},
+		{"missing fields", TypeAuthenticate, `{}`, &AuthenticatePayload{}, []string{"player_id", "lobby_code"}},
+		{"negative seqs", TypeAuthenticate, `{"player_id":"p","lobby_code":"c","last_seq":-1,"last_lobby_seq":-2}`, &AuthenticatePayload{}, []string{"last_seq", "last_lobby_seq"}},
+		{"long id", TypeSubmitPick, `{"species_id":"` + strings.Repeat("a", maxIDLength+1) + `"}`, &SubmitPickPayload{}, []string{"species_id"}},
+		{"team members", TypeSubmitTeam, `{"team":[{"species_id":"pikachu","moves":["thunderbolt"]},{"level":-1,"moves":[""]}]}`, &SubmitTeamPayload{},
+			[]string{"team[1].species_id", "team[1].level", "team[1].moves[0]"}},
+		{"missing team", TypeSubmitTeam, `{}`, &SubmitTeamPayload{}, []string{"team"}},
+		{"action data", TypeSubmitAction, `{"turn_number":1,"action_type":"item","action_data":{"target_slot":-1}}`, &SubmitActionPayload{},
+			[]string{"action_data.item_id", "action_data.target_slot"}},
+		{"action data type", TypeSubmitAction, `{"turn_number":1,"action_type":"switch","action_data":{"creature_slot":"2"}}`, &SubmitActionPayload{},
+			[]string{"action_data.creature_slot"}},
+		{"forfeit needs no data", TypeSubmitAction, `{"turn_number":1,"action_type":"forfeit"}`, &SubmitActionPayload{}, nil},
+		{"wrong type", TypeAck, `{"lobby_seq":"5"}`, &AckPayload{}, []string{"lobby_seq"}},
+		{"not json", TypeAck, `{"lobby_seq":`, &AckPayload{}, []string{""}},
+		{"no rules", TypeSetReady, `{"ready":true}`, &SetReadyPayload{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Envelope{Type: tt.msgType, Payload: json.RawMessage(tt.payload)}
+			got := decodePayload(env, tt.into)
+			if len(got) != len(tt.fields) {
+				t.Fatalf("expected fields %v, got %+v", tt.fields, got)
+			}
+			for i, f := range got {
+				if f.Field != tt.fields[i] || f.Reason == "" {
+					t.Errorf("expected field %q with a reason, got %+v", tt.fields[i], f)
+				}
+			}
+		})
+	}
+}
+
+func TestPayloadSizeLimits(t *testing.T) {
+	// Every message a client may send has a limit
+	for _, msgType := range []MessageType{TypeAuthenticate, TypeSubmitTeam, TypeSubmitAction, TypeChatMessage, TypeAck} {
+		if _, ok := payloadSizeLimits[msgType]; !ok {
+			t.Errorf("expected %s to have a size limit", msgType)
+		}
+	}
+
+	// The largest legitimate payloads fit their limits
+	chat, _ := json.Marshal(ChatMessagePayload{Text: strings.Repeat("a", MaxChatMessageLength)})
+	if len(chat) > payloadSizeLimits[TypeChatMessage] {
+		t.Errorf("expected a full chat message of %d bytes to fit", len(chat))
+	}
+	chat, _ = json.Marshal(ChatMessagePayload{Text: strings.Repeat("🔥", 100)})
+	if len(chat) > payloadSizeLimits[TypeChatMessage] {
+		t.Errorf("expected a chat message of 100 four-byte characters, %d bytes, to fit", len(chat))
+	}
+	member := TeamMemberPayload{
+		SpeciesID: strings.Repeat("s", 24),
+		Level:     100,
+		Item:      strings.Repeat("i", 24),
+		Nickname:  strings.Repeat("n", maxNameLength),
+		Moves:     []string{strings.Repeat("m", 24), strings.Repeat("m", 24), strings.Repeat("m", 24), strings.Repeat("m", 24)},
+	}
+	team, _ := json.Marshal(SubmitTeamPayload{Team: []TeamMemberPayload{member, member, member, member, member, member}})
+	if len(team) > payloadSizeLimits[TypeSubmitTeam] {
+		t.Errorf("expected a full team of %d bytes to fit", len(team))
+	}
+}
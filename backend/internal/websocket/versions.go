@@ -0,0 +1,63 @@
+package websocket
+
+import "encoding/json"
+
+// A client picks its protocol version with the envelope of its authenticate message and keeps it for the
+// rest of the connection. Server code only ever deals in ProtocolVersion: payloads from older clients are
+// upgraded to it before they are handled, so a version bump that changes a client message's payload must
+// register an upgrade for it. Server messages may only gain fields, which older clients ignore.
+
+// payloadUpgrade rewrites a payload sent under one protocol version into the shape of the next
+type payloadUpgrade func(payload json.RawMessage) (json.RawMessage, error)
+
+// payloadUpgrades holds, for each version below ProtocolVersion, the upgrades of the client messages
+// whose payload changed in the version after it
+var payloadUpgrades = map[int]map[MessageType]payloadUpgrade{}
+
+// VersionMismatchDetails is the error detail sent when a message's protocol version is not accepted
+type VersionMismatchDetails struct {
+	SupportedVersions []int `json:"supported_versions"` // Oldest first
+	MinVersion        int   `json:"min_version"`
+	MaxVersion        int   `json:"max_version"`
+	ConnectionVersion int   `json:"connection_version,omitempty"` // The version the connection authenticated with, if it has
+}
+
+// SupportedProtocolVersions returns every protocol version the server accepts, oldest first
+func SupportedProtocolVersions() []int {
+	versions := make([]int, 0, ProtocolVersion-MinProtocolVersion+1)
+	for v := MinProtocolVersion; v <= ProtocolVersion; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// supportsProtocolVersion returns true if the server accepts messages in the version
+func supportsProtocolVersion(version int) bool {
+	return version >= MinProtocolVersion && version <= ProtocolVersion
+}
+
+// upgradePayload rewrites the envelope's payload from the version it was sent in to ProtocolVersion
+func upgradePayload(env *Envelope) error {
+	for v := env.Version; v < ProtocolVersion; v++ {
+		upgrade, ok := payloadUpgrades[v][env.Type]
+		if !ok {
+			continue
+		}
+		payload, err := upgrade(env.Payload)
+		if err != nil {
+			return err
+		}
+		env.Payload = payload
+	}
+	return nil
+}
+
+// sendVersionMismatch tells the client which protocol versions it may use instead
+func sendVersionMismatch(conn *Connection, env *Envelope) {
+	conn.SendErrorWithDetails(ErrCodeVersionMismatch, "Protocol version not supported", VersionMismatchDetails{
+		SupportedVersions: SupportedProtocolVersions(),
+		MinVersion:        MinProtocolVersion,
+		MaxVersion:        ProtocolVersion,
+		ConnectionVersion: conn.ProtocolVersion(),
+	}, env.CorrelationID)
+}
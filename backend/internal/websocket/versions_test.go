@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// ========================================
+// Protocol Version Tests
+// ========================================
+
+func TestSupportedProtocolVersions(t *testing.T) {
+	versions := SupportedProtocolVersions()
+	if versions[0] != MinProtocolVersion || versions[len(versions)-1] != ProtocolVersion {
+		t.Errorf("expected versions %d to %d, got %v", MinProtocolVersion, ProtocolVersion, versions)
+	}
+	for _, v := range versions {
+		if !supportsProtocolVersion(v) {
+			t.Errorf("expected version %d to be supported", v)
+		}
+	}
+	if supportsProtocolVersion(MinProtocolVersion-1) || supportsProtocolVersion(ProtocolVersion+1) {
+		t.Error("expected versions outside the range to be rejected")
+	}
+}
+
+func TestUpgradePayload(t *testing.T) {
+	// An old version whose set_ready sent the flag as "is_ready"
+	old := ProtocolVersion - 1
+	payloadUpgrades[old] = map[MessageType]payloadUpgrade{
+		TypeSetReady: func(payload json.RawMessage) (json.RawMessage, error) {
+			var v struct {
+				IsReady bool `json:"is_ready"`
+			}
+			if err := json.Unmarshal(payload, &v); err != nil {
+				return nil, err
+			}
+			return json.Marshal(SetReadyPayload{Ready: v.IsReady})
+		},
+	}
+	defer delete(payloadUpgrades, old)
+
+	env := &Envelope{Type: TypeSetReady, Version: old, Payload: json.RawMessage(`{"is_ready":true}`)}
+	if err := upgradePayload(env); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var payload SetReadyPayload
+	if err := env.ParsePayload(&payload); err != nil || !payload.Ready {
+		t.Errorf("expected the payload in the current shape, got %s", env.Payload)
+	}
+
+	current := &Envelope{Type: TypeSetReady, Version: ProtocolVersion, Payload: json.RawMessage(`{"ready":true}`)}
+	upgradePayload(current)
+	if !reflect.DeepEqual(current.Payload, json.RawMessage(`{"ready":true}`)) {
+		t.Errorf("expected a current payload to be left alone, got %s", current.Payload)
+	}
+}
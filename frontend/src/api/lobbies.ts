@@ -1,8 +1,19 @@
 import { get, post } from "./http";
-import type { Lobby, CreateLobbyRequest, JoinLobbyRequest } from "../types/lobby";
+import type {
+  Lobby,
+  LobbyListResponse,
+  CreateLobbyRequest,
+  JoinLobbyRequest,
+} from "../types/lobby";
+
+export async function fetchLobbyPage(cursor?: string): Promise<LobbyListResponse> {
+  const query = cursor ? `?cursor=${encodeURIComponent(cursor)}` : "";
+  return get<LobbyListResponse>(`/lobbies${query}`);
+}
 
 export async function fetchLobbies(): Promise<Lobby[]> {
-  return get<Lobby[]>("/lobbies");
+  const page = await fetchLobbyPage();
+  return page.lobbies;
 }
 
 export async function fetchLobbyByCode(code: string): Promise<Lobby> {
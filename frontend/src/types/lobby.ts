@@ -13,6 +13,11 @@ export interface Lobby {
   max_players: number;
 }
 
+export interface LobbyListResponse {
+  lobbies: Lobby[];
+  next_cursor?: string;
+}
+
 export interface CreateLobbyRequest {
   player_id: string;
   username: string;
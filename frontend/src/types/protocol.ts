@@ -0,0 +1,1199 @@
+// Code generated by protocol-gen from backend/internal/websocket. DO NOT EDIT.
+// Run `go generate ./internal/websocket` in backend to update it.
+
+/** Envelope is the standard message wrapper for all WebSocket messages */
+export interface Envelope<T extends string = string, P = unknown> {
+  type: T;
+  version: number;
+  timestamp: number;
+  correlation_id?: string;
+  seq?: number;
+  /** Numbers the lobby's broadcasts, across all its players and their connections */
+  lobby_seq?: number;
+  payload: P;
+}
+
+/** The payload of each message clients send, by message type */
+export interface ClientPayloads {
+  authenticate: AuthenticatePayload;
+  heartbeat: HeartbeatPayload;
+  time_sync: TimeSyncPayload;
+  claim_primary: ClaimPrimaryPayload;
+  request_lobby_state: RequestLobbyStatePayload;
+  set_ready: SetReadyPayload;
+  submit_team: SubmitTeamPayload;
+  submit_pick: SubmitPickPayload;
+  start_game: StartGamePayload;
+  cancel_game_start: CancelGameStartPayload;
+  choose_lead: ChooseLeadPayload;
+  submit_action: SubmitActionPayload;
+  request_game_state: RequestGameStatePayload;
+  offer_draw: OfferDrawPayload;
+  respond_draw: RespondDrawPayload;
+  request_pause: RequestPausePayload;
+  respond_pause: RespondPausePayload;
+  request_resume: RequestResumePayload;
+  request_rematch: RequestRematchPayload;
+  leave_game: LeaveGamePayload;
+  chat_message: ChatMessagePayload;
+  ack: AckPayload;
+  resync_request: ResyncRequestPayload;
+}
+
+export type ClientMessageType = keyof ClientPayloads;
+
+/** A message clients send */
+export type ClientMessage = {
+  [K in ClientMessageType]: Envelope<K, ClientPayloads[K]>;
+}[ClientMessageType];
+
+/** The payload of each message the server sends, by message type */
+export interface ServerPayloads {
+  authenticated: AuthenticatedPayload;
+  heartbeat_ack: HeartbeatAckPayload;
+  time_sync_response: TimeSyncResponsePayload;
+  primary_changed: PrimaryChangedPayload;
+  lobby_updated: LobbyUpdatedPayload;
+  game_starting: GameStartingPayload;
+  game_start_cancelled: GameStartCancelledPayload;
+  game_started: GameStartedPayload;
+  draft_state: DraftStatePayload;
+  lobby_closed: LobbyClosedPayload;
+  waitlist_promoted: WaitlistPromotedPayload;
+  player_connected: PlayerConnectedPayload;
+  player_disconnected: PlayerDisconnectedPayload;
+  opponent_disconnected: OpponentDisconnectedPayload;
+  team_preview: TeamPreviewPayload;
+  game_state: GameStatePayload;
+  game_state_delta: GameStateDeltaPayload;
+  spectator_state: SpectatorStatePayload;
+  action_acknowledged: ActionAcknowledgedPayload;
+  opponent_committed: OpponentCommittedPayload;
+  turn_result: TurnResultPayload;
+  switch_required: SwitchRequiredPayload;
+  game_ended: GameEndedPayload;
+  draw_offered: DrawOfferedPayload;
+  draw_declined: DrawDeclinedPayload;
+  pause_requested: PauseRequestedPayload;
+  pause_declined: PauseDeclinedPayload;
+  battle_paused: BattlePausedPayload;
+  battle_resuming: BattleResumingPayload;
+  battle_resumed: BattleResumedPayload;
+  rematch_requested: RematchRequestedPayload;
+  rematch_starting: RematchStartingPayload;
+  series_ended: SeriesEndedPayload;
+  chat_message: ChatMessageBroadcastPayload;
+  resync: ResyncPayload;
+  lobby_events: LobbyEventsPayload;
+  error: ErrorPayload;
+  disconnect_warning: DisconnectWarningPayload;
+  server_shutdown: ServerShutdownPayload;
+}
+
+export type ServerMessageType = keyof ServerPayloads;
+
+/** A message the server sends */
+export type ServerMessage = {
+  [K in ServerMessageType]: Envelope<K, ServerPayloads[K]>;
+}[ServerMessageType];
+
+/** MessageType represents the type of WebSocket message */
+export type MessageType =
+  | "authenticate"
+  | "heartbeat"
+  | "time_sync"
+  | "claim_primary"
+  | "request_lobby_state"
+  | "set_ready"
+  | "submit_team"
+  | "submit_pick"
+  | "start_game"
+  | "cancel_game_start"
+  | "choose_lead"
+  | "submit_action"
+  | "request_game_state"
+  | "offer_draw"
+  | "respond_draw"
+  | "request_pause"
+  | "respond_pause"
+  | "request_resume"
+  | "request_rematch"
+  | "leave_game"
+  | "chat_message"
+  | "ack"
+  | "resync_request"
+  | "authenticated"
+  | "heartbeat_ack"
+  | "time_sync_response"
+  | "primary_changed"
+  | "lobby_updated"
+  | "game_starting"
+  | "game_start_cancelled"
+  | "game_started"
+  | "draft_state"
+  | "lobby_closed"
+  | "waitlist_promoted"
+  | "player_connected"
+  | "player_disconnected"
+  | "opponent_disconnected"
+  | "team_preview"
+  | "game_state"
+  | "game_state_delta"
+  | "spectator_state"
+  | "action_acknowledged"
+  | "opponent_committed"
+  | "turn_result"
+  | "switch_required"
+  | "game_ended"
+  | "draw_offered"
+  | "draw_declined"
+  | "pause_requested"
+  | "pause_declined"
+  | "battle_paused"
+  | "battle_resuming"
+  | "battle_resumed"
+  | "rematch_requested"
+  | "rematch_starting"
+  | "series_ended"
+  | "resync"
+  | "lobby_events"
+  | "error"
+  | "disconnect_warning"
+  | "server_shutdown";
+
+/** AuthenticatePayload is sent by clients to establish identity */
+export interface AuthenticatePayload {
+  player_id: string;
+  session_token: string;
+  lobby_code: string;
+  reconnect_token?: string;
+  last_seq?: number;
+  /** Replay the lobby's broadcasts after this when there is no session to resume */
+  last_lobby_seq?: number;
+  /** Receive game_state_delta instead of full states */
+  delta_updates?: boolean;
+  /** Join the lobby's spectator roster instead of playing */
+  spectate?: boolean;
+  /** Name shown to the lobby; required to join as a spectator */
+  username?: string;
+  /** Join the player's other devices without taking over as primary */
+  secondary?: boolean;
+  /** What the client can handle, so the server can tailor what it sends; clients that leave it out get what they asked for above and negotiated on the upgrade */
+  capabilities?: ClientCapabilities;
+}
+
+/** ClientCapabilities declares what a client can handle and which build of it is connecting */
+export interface ClientCapabilities {
+  /** Receive game_state_delta instead of full states, as delta_updates does */
+  supports_delta_state?: boolean;
+  /** Without it, messages are sent uncompressed even if permessage-deflate was negotiated */
+  supports_compression?: boolean;
+  /** Free-form, such as "web/1.4.2"; counted in the metrics */
+  client_version?: string;
+}
+
+/** HeartbeatPayload is sent by clients to keep connection alive */
+export type HeartbeatPayload = Record<string, never>;
+
+/** TimeSyncPayload asks for the server's clock, so the client can work out how far its own is off and show turn timers and starts_at times as the server means them */
+export interface TimeSyncPayload {
+  /** The client's clock when it sent the request, in Unix milliseconds */
+  client_send_time: number;
+}
+
+/** ClaimPrimaryPayload is sent by one of a player's devices to take over as primary */
+export type ClaimPrimaryPayload = Record<string, never>;
+
+/** RequestLobbyStatePayload is sent to get current lobby state. A client that was briefly offline can send the lobby_seq it last had as since_seq to be sent only the lobby changes after it, as lobby_events. */
+export interface RequestLobbyStatePayload {
+  /** The highest lobby_seq received with none missing before it */
+  since_seq?: number;
+}
+
+/** SetReadyPayload is sent to signal ready status */
+export interface SetReadyPayload {
+  ready: boolean;
+}
+
+/** SubmitTeamPayload is sent to choose the team for the next game */
+export interface SubmitTeamPayload {
+  team: TeamMemberPayload[];
+}
+
+/** TeamMemberPayload is one creature in a submitted team */
+export interface TeamMemberPayload {
+  species_id: string;
+  level?: number;
+  item?: string;
+  moves: string[];
+  nickname?: string;
+  shiny?: boolean;
+}
+
+/** SubmitPickPayload is sent on the player's draft turn to ban or pick a species */
+export interface SubmitPickPayload {
+  species_id: string;
+}
+
+/** StartGamePayload is sent by the host to start the game in a lobby whose start mode is host */
+export type StartGamePayload = Record<string, never>;
+
+/** CancelGameStartPayload is sent by the host to call off a game start during its countdown */
+export type CancelGameStartPayload = Record<string, never>;
+
+/** ChooseLeadPayload is sent during team preview to pick the creature sent out first */
+export interface ChooseLeadPayload {
+  slot: number;
+}
+
+/** SubmitActionPayload is sent during battle */
+export interface SubmitActionPayload {
+  /** Optional; rejects the action unless it is the lobby's game in progress */
+  game_id?: string;
+  turn_number: number;
+  action_type: ActionType;
+  action_data: unknown;
+}
+
+/** ActionType represents the type of battle action */
+export type ActionType =
+  | "attack"
+  | "switch"
+  | "item"
+  | "forfeit";
+
+/** RequestGameStatePayload is sent to request full game snapshot */
+export interface RequestGameStatePayload {
+  include_history: boolean;
+}
+
+/** OfferDrawPayload proposes a draw to the opponent */
+export type OfferDrawPayload = Record<string, never>;
+
+/** RespondDrawPayload answers the opponent's draw offer */
+export interface RespondDrawPayload {
+  accept: boolean;
+}
+
+/** RequestPausePayload asks the opponent to pause the battle */
+export type RequestPausePayload = Record<string, never>;
+
+/** RespondPausePayload answers the opponent's pause request */
+export interface RespondPausePayload {
+  accept: boolean;
+}
+
+/** RequestResumePayload ends an agreed pause early */
+export type RequestResumePayload = Record<string, never>;
+
+/** RequestRematchPayload is sent after game ends */
+export type RequestRematchPayload = Record<string, never>;
+
+/** LeaveGamePayload is sent to exit game/lobby */
+export type LeaveGamePayload = Record<string, never>;
+
+/** ChatMessagePayload is sent to say something to the rest of the lobby */
+export interface ChatMessagePayload {
+  text: string;
+}
+
+/** AckPayload tells the server the client has every broadcast up to lobby_seq, so it need not keep them for replay */
+export interface AckPayload {
+  /** The highest lobby_seq received with none missing before it */
+  lobby_seq: number;
+}
+
+/** ResyncRequestPayload is sent by a client that found a gap in the broadcasts it received */
+export interface ResyncRequestPayload {
+  /** The highest lobby_seq received with none missing before it */
+  last_lobby_seq: number;
+}
+
+/** AuthenticatedPayload confirms authentication */
+export interface AuthenticatedPayload {
+  player_id: string;
+  /** Accepted for one reconnect; each authentication issues a new one */
+  reconnect_token: string;
+  session_expires_at: number;
+  /** The version the connection authenticated with, which its later messages must use */
+  protocol_version: number;
+  /** Messages after last_seq or last_lobby_seq sent again before this one */
+  replayed?: number;
+  /** Some missed messages could not be replayed, so the client should resync */
+  replay_incomplete?: boolean;
+  /** The lobby_seq of the lobby's latest broadcast */
+  lobby_seq?: number;
+  connection_id: string;
+  /** Whether this is the player's primary device, whose actions count */
+  primary: boolean;
+}
+
+/** HeartbeatAckPayload acknowledges heartbeat */
+export interface HeartbeatAckPayload {
+  server_time: number;
+}
+
+/** TimeSyncResponsePayload answers time_sync with the server's clock, in Unix milliseconds. With the time t it arrives, the client's clock is behind by ((server_receive_time - client_send_time) + (server_send_time - t)) / 2, give or take half the round trip spent outside the server. */
+export interface TimeSyncResponsePayload {
+  /** Echoed from the request */
+  client_send_time: number;
+  /** When the server read the request */
+  server_receive_time: number;
+  /** When the server sent the response */
+  server_send_time: number;
+}
+
+/** PrimaryChangedPayload tells all a player's devices which of them is now primary */
+export interface PrimaryChangedPayload {
+  connection_id: string;
+  previous_connection_id?: string;
+  reason: PrimaryReason;
+}
+
+/** PrimaryReason says why a player's primary device changed */
+export type PrimaryReason =
+  | "authenticated"
+  | "claimed"
+  | "disconnected";
+
+/** LobbyUpdatedPayload notifies of lobby state changes. Changes in quick succession are coalesced into one update: Lobby is as it was after the latest, which is Event, and EarlierEvents lists the rest in order. */
+export interface LobbyUpdatedPayload {
+  lobby: LobbyInfo;
+  event: LobbyEvent;
+  event_data?: unknown;
+  earlier_events?: LobbyEventRecord[];
+}
+
+/** LobbyInfo represents the lobby state */
+export interface LobbyInfo {
+  code: string;
+  state: string;
+  ruleset: string;
+  best_of: number;
+  draft_mode: boolean;
+  rematch_teams: string;
+  players: LobbyPlayerInfo[];
+  spectators: LobbySpectatorInfo[];
+  waitlist: LobbyWaitlistInfo[];
+  max_spectators: number;
+  tags: string[];
+  /** Unix ms */
+  created_at: number;
+  /** Unix ms */
+  last_activity_at: number;
+}
+
+/** LobbyPlayerInfo represents a player in the lobby */
+export interface LobbyPlayerInfo {
+  id: string;
+  username: string;
+  is_host: boolean;
+  is_ready: boolean;
+  /** Bots are always connected */
+  is_connected: boolean;
+  has_team: boolean;
+  is_bot: boolean;
+  bot_difficulty?: string;
+}
+
+/** LobbySpectatorInfo represents a spectator watching the lobby */
+export interface LobbySpectatorInfo {
+  id: string;
+  username: string;
+}
+
+/** LobbyWaitlistInfo represents a player queued for the lobby's next free slot */
+export interface LobbyWaitlistInfo {
+  id: string;
+  username: string;
+  /** 1 is next in line */
+  position: number;
+}
+
+/** LobbyEvent represents types of lobby updates */
+export type LobbyEvent =
+  | "player_joined"
+  | "player_left"
+  | "player_ready_changed"
+  | "host_changed"
+  | "state_changed"
+  | "team_submitted"
+  | "settings_changed"
+  | "spectator_joined"
+  | "spectator_left"
+  | "waitlist_joined"
+  | "waitlist_left";
+
+/** LobbyEventRecord is one of the changes coalesced into a lobby update */
+export interface LobbyEventRecord {
+  event: LobbyEvent;
+  event_data?: unknown;
+}
+
+/** GameStartingPayload notifies that game countdown begins */
+export interface GameStartingPayload {
+  starts_at: number;
+  countdown_sec: number;
+}
+
+/** GameStartCancelledPayload notifies that the game will not start after all */
+export interface GameStartCancelledPayload {
+  reason: GameStartCancelledReason;
+  /** The player whose action cancelled it, if any */
+  player_id?: string;
+}
+
+/** GameStartCancelledReason explains why a game start countdown was called off */
+export type GameStartCancelledReason =
+  | "host"
+  | "not_ready"
+  | "player_left";
+
+/** GameStartedPayload notifies that the game has started */
+export interface GameStartedPayload {
+  game_id?: string;
+  data_version: string;
+  /** Set when the ruleset changes the type chart: attacker -> defender -> multiplier, unlisted matchups neutral */
+  type_chart?: Record<string, Record<string, number>>;
+  /** Hex SHA-256 of "<seed_salt>:<seed>", both revealed in game_ended */
+  seed_commitment?: string;
+}
+
+/** DraftStatePayload is sent whenever the draft changes. CurrentTurn and TurnDeadline are omitted once the draft is complete. */
+export interface DraftStatePayload {
+  pool: string[];
+  bans: Record<string, string[]>;
+  picks: Record<string, string[]>;
+  step: number;
+  current_turn?: DraftTurnInfo;
+  turn_deadline?: number;
+  complete: boolean;
+}
+
+/** DraftTurnInfo identifies whose draft turn it is and whether they ban or pick */
+export interface DraftTurnInfo {
+  player_id: string;
+  kind: string;
+}
+
+/** LobbyClosedPayload notifies the lobby's connections that it no longer exists; they are disconnected after it */
+export interface LobbyClosedPayload {
+  code: string;
+  reason: LobbyClosedReason;
+}
+
+/** LobbyClosedReason explains why the server closed a lobby */
+export type LobbyClosedReason =
+  | "idle"
+  | "host";
+
+/** WaitlistPromotedPayload tells a waitlisted player they now have a slot in the lobby */
+export interface WaitlistPromotedPayload {
+  lobby_code: string;
+}
+
+/** PlayerConnectedPayload tells the rest of the lobby a player connected or reconnected */
+export interface PlayerConnectedPayload {
+  player_id: string;
+}
+
+/** PlayerDisconnectedPayload tells the lobby a player lost their connection without leaving */
+export interface PlayerDisconnectedPayload {
+  player_id: string;
+}
+
+/** OpponentDisconnectedPayload tells a player their opponent dropped out of the battle */
+export interface OpponentDisconnectedPayload {
+  player_id: string;
+  /** Unix ms at which the player wins if their opponent has not returned */
+  grace_expires_at: number;
+}
+
+/** TeamPreviewPayload is sent to each player when a battle starts with team preview. The opponent's species are revealed, but not their moves or lead. */
+export interface TeamPreviewPayload {
+  player_state: PlayerBattleState;
+  opponent: PreviewSideInfo;
+}
+
+/** PlayerBattleState represents a player's battle state */
+export interface PlayerBattleState {
+  player_id: string;
+  username: string;
+  /** Only for own team */
+  team?: DetailedCreatureInfo[];
+  active_slot: number;
+  /** For opponent */
+  bench_count?: number;
+  /** For opponent's active */
+  active_hp?: number;
+  active_max_hp?: number;
+  active_status?: string;
+  /** Opposing creatures seen so far, with the moves they used */
+  revealed?: RevealedCreatureInfo[];
+  /** Bag items the opponent has used */
+  items_used?: Record<string, number>;
+  /** Entry hazards on this side of the field */
+  hazards?: HazardsInfo;
+  /** Remaining bag items, only for own side */
+  bag?: Record<string, number>;
+}
+
+/** DetailedCreatureInfo includes full details (for player's own team) */
+export interface DetailedCreatureInfo {
+  id: string;
+  name: string;
+  /** Shown in place of the name when set */
+  nickname?: string;
+  shiny?: boolean;
+  current_hp: number;
+  max_hp: number;
+  status?: string;
+  is_active: boolean;
+  moves?: MoveInfo[];
+}
+
+/** MoveInfo represents a move (only sent for player's own creatures) */
+export interface MoveInfo {
+  id: string;
+  name: string;
+  type: string;
+  pp: number;
+  max_pp: number;
+  power?: number;
+  accuracy?: number;
+}
+
+/** RevealedCreatureInfo is an opposing creature that has been seen on the field */
+export interface RevealedCreatureInfo {
+  id: string;
+  name: string;
+  /** Shown in place of the name when set */
+  nickname?: string;
+  shiny?: boolean;
+  current_hp: number;
+  max_hp: number;
+  status?: string;
+  is_active: boolean;
+  slot: number;
+  /** Only moves it has used */
+  moves?: RevealedMoveInfo[];
+}
+
+/** RevealedMoveInfo describes an opposing move seen in battle; its remaining PP stays hidden */
+export interface RevealedMoveInfo {
+  id: string;
+  name: string;
+  type: string;
+  power?: number;
+  accuracy?: number;
+}
+
+/** HazardsInfo describes the entry hazards laid on a side of the field */
+export interface HazardsInfo {
+  stealth_rock?: boolean;
+  spikes?: number;
+  toxic_spikes?: number;
+}
+
+/** PreviewSideInfo lists the creatures an opponent brought */
+export interface PreviewSideInfo {
+  player_id: string;
+  team: PreviewCreatureInfo[];
+}
+
+/** PreviewCreatureInfo is the part of an opposing creature revealed during team preview */
+export interface PreviewCreatureInfo {
+  species_id: string;
+  name: string;
+  nickname?: string;
+  shiny?: boolean;
+  level: number;
+}
+
+/** GameStatePayload contains full game snapshot */
+export interface GameStatePayload {
+  /** Increases with every state sent to the player */
+  revision: number;
+  turn_number: number;
+  phase: GamePhase;
+  player_state: PlayerBattleState;
+  opponent_state: PlayerBattleState;
+  field: FieldInfo;
+  turn_timer?: TurnTimerInfo;
+}
+
+/** GamePhase represents the current phase of the game */
+export type GamePhase =
+  | "team_preview"
+  | "action_selection"
+  | "turn_resolution"
+  | "switch_selection"
+  | "ended";
+
+/** FieldInfo describes conditions affecting both sides of the field */
+export interface FieldInfo {
+  /** electric, grassy, psychic, misty */
+  terrain?: string;
+  /** Turns remaining, including the current one */
+  terrain_turns?: number;
+}
+
+/** TurnTimerInfo contains timer information */
+export interface TurnTimerInfo {
+  expires_at: number;
+  duration_sec: number;
+}
+
+/** GameStateDeltaPayload carries only what changed since the state with BaseRevision. A client whose last state has another revision has missed an update and should send request_game_state. */
+export interface GameStateDeltaPayload {
+  base_revision: number;
+  revision: number;
+  turn_number: number;
+  phase: GamePhase;
+  player?: SideDelta;
+  opponent?: SideDelta;
+  /** The whole field when it changes */
+  field?: FieldInfo;
+}
+
+/** SideDelta lists what changed on one side of the field; unchanged fields are omitted */
+export interface SideDelta {
+  active_slot?: number;
+  /** Own side only */
+  creatures?: CreatureDelta[];
+  /** Own side only, the whole bag when it changes */
+  bag?: Record<string, number>;
+  /** Opponent only */
+  active_hp?: number;
+  /** Opponent only */
+  active_max_hp?: number;
+  /** Opponent only; empty when cured */
+  active_status?: string;
+  /** Opponent only, the whole list when it changes */
+  revealed?: RevealedCreatureInfo[];
+  /** Opponent only, all items when they change */
+  items_used?: Record<string, number>;
+  hazards?: HazardsInfo;
+  hazards_cleared?: boolean;
+}
+
+/** CreatureDelta lists what changed for one of the player's own creatures */
+export interface CreatureDelta {
+  slot: number;
+  current_hp?: number;
+  /** Empty when cured */
+  status?: string;
+  /** Remaining PP of the moves that changed */
+  pp?: Record<string, number>;
+}
+
+/** SpectatorStatePayload is the full view of the battle sent to spectators, who see both sides as their players do */
+export interface SpectatorStatePayload {
+  turn_number: number;
+  phase: GamePhase;
+  /** In battle order, the same for every spectator */
+  sides: PlayerBattleState[];
+  field: FieldInfo;
+}
+
+/** ActionAcknowledgedPayload confirms action received */
+export interface ActionAcknowledgedPayload {
+  turn_number: number;
+}
+
+/** OpponentCommittedPayload tells a player their opponent has chosen an action for the turn, without revealing what it is */
+export interface OpponentCommittedPayload {
+  turn_number: number;
+}
+
+/** TurnResultPayload contains turn resolution with events */
+export interface TurnResultPayload {
+  turn_number: number;
+  events: TurnEvent[];
+  /** Omitted for delta clients, who get game_state_delta */
+  resulting_state?: GameStatePayload;
+}
+
+/** TurnEvent represents a single event in turn resolution */
+export interface TurnEvent {
+  order: number;
+  type: TurnEventType;
+  actor?: string;
+  data: unknown;
+}
+
+/** TurnEventType represents types of turn events */
+export type TurnEventType =
+  | "move_used"
+  | "damage_dealt"
+  | "status_applied"
+  | "creature_fainted"
+  | "creature_switched"
+  | "stat_changed"
+  | "move_failed"
+  | "action_timeout"
+  | "status_ended"
+  | "confusion_self_hit"
+  | "leech_seed_drain"
+  | "recoil_damage"
+  | "status_damage"
+  | "hazard_set"
+  | "hazard_damage"
+  | "hazard_cleared"
+  | "terrain_set"
+  | "terrain_ended"
+  | "item_used";
+
+/** SwitchRequiredPayload prompts forced switch */
+export interface SwitchRequiredPayload {
+  /** fainted, move_effect */
+  reason: string;
+  available_slots: number[];
+  timeout_at: number;
+}
+
+/** GameEndedPayload announces game conclusion */
+export interface GameEndedPayload {
+  /** Empty for a draw, as is LoserID */
+  winner_id: string;
+  loser_id: string;
+  reason: GameEndReason;
+  draw?: boolean;
+  final_state?: GameStatePayload;
+  /** Battle RNG seed, for deterministic re-simulation */
+  seed: number;
+  /** Salt of the seed commitment sent in game_started */
+  seed_salt: string;
+  /** Game data pack the battle was played with */
+  data_version: string;
+  replay_id?: string;
+  /** Series score including this game, for best-of-N lobbies */
+  series?: SeriesInfo;
+  /** Each player's battle summary, keyed by player ID */
+  stats: Record<string, BattleStatsInfo>;
+}
+
+/** GameEndReason represents why the game ended */
+export type GameEndReason =
+  | "victory"
+  | "forfeit"
+  | "draw"
+  | "opponent_disconnect"
+  | "timeout"
+  | "turn_limit"
+  | "endless_battle";
+
+/** SeriesInfo is the score of a best-of-N series */
+export interface SeriesInfo {
+  best_of: number;
+  games_played: number;
+  wins: Record<string, number>;
+}
+
+/** BattleStatsInfo summarises a player's battle for the end screen */
+export interface BattleStatsInfo {
+  /** HP their moves took from opposing creatures */
+  damage_dealt: number;
+  /** HP their creatures lost, from any source */
+  damage_taken: number;
+  kos: number;
+  /** Turns they ended with a creature still standing */
+  turns_survived: number;
+  most_used_move?: string;
+}
+
+/** DrawOfferedPayload notifies both players of a draw offer */
+export interface DrawOfferedPayload {
+  player_id: string;
+}
+
+/** DrawDeclinedPayload notifies both players that a draw offer was declined */
+export interface DrawDeclinedPayload {
+  /** The player who declined */
+  player_id: string;
+}
+
+/** PauseRequestedPayload notifies both players of a pause request */
+export interface PauseRequestedPayload {
+  player_id: string;
+  /** Pause time the requesting player has left */
+  budget_remaining_ms: number;
+}
+
+/** PauseDeclinedPayload notifies both players that a pause request was declined */
+export interface PauseDeclinedPayload {
+  /** The player who declined */
+  player_id: string;
+}
+
+/** BattlePausedPayload announces that the battle is paused and every timer frozen */
+export interface BattlePausedPayload {
+  /** The player whose pause budget is being used */
+  player_id: string;
+  /** "requested" or "disconnect" */
+  reason: string;
+  budget_remaining_ms: number;
+  /** Unix ms at which the budget runs out and the battle starts resuming */
+  expires_at: number;
+}
+
+/** BattleResumingPayload announces the countdown to a paused battle resuming */
+export interface BattleResumingPayload {
+  countdown_sec: number;
+  /** Unix ms */
+  resumes_at: number;
+}
+
+/** BattleResumedPayload announces that the battle and its timers are running again */
+export interface BattleResumedPayload {
+  /** Pause time each player has left, keyed by player ID */
+  budgets_remaining_ms: Record<string, number>;
+}
+
+/** RematchRequestedPayload notifies of rematch request */
+export interface RematchRequestedPayload {
+  player_id: string;
+}
+
+/** RematchStartingPayload announces rematch countdown */
+export interface RematchStartingPayload {
+  starts_at: number;
+  countdown_sec: number;
+  /** Score going into the next game, for best-of-N lobbies */
+  series?: SeriesInfo;
+  /** Players must submit new teams and ready up before the game starts */
+  new_teams?: boolean;
+}
+
+/** SeriesEndedPayload announces the winner of a best-of-N series after its final game */
+export interface SeriesEndedPayload {
+  winner_id: string;
+  loser_id: string;
+  series: SeriesInfo;
+}
+
+/** ChatMessageBroadcastPayload relays a chat message to everyone in the lobby, including its sender */
+export interface ChatMessageBroadcastPayload {
+  sender_id: string;
+  username: string;
+  spectator: boolean;
+  text: string;
+  /** Unix ms */
+  sent_at: number;
+}
+
+/** ResyncPayload answers resync_request with the lobby as it is now and the broadcasts missed since last_lobby_seq */
+export interface ResyncPayload {
+  lobby: LobbyInfo;
+  /** The broadcasts after last_lobby_seq meant for the client, oldest first, as they were sent */
+  missed: unknown[];
+  /** The lobby_seq of the lobby's latest broadcast, which the snapshot is as of */
+  lobby_seq: number;
+  /** Some missed broadcasts could no longer be sent, so the snapshot is all there is to go on */
+  incomplete?: boolean;
+}
+
+/** LobbyEventsPayload answers request_lobby_state with since_seq with the lobby changes made after it. If some can no longer be sent, a lobby_updated with the whole lobby is sent instead. */
+export interface LobbyEventsPayload {
+  since_seq: number;
+  /** Oldest first */
+  events: MissedLobbyEvent[];
+  /** The lobby_seq of the lobby's latest broadcast */
+  lobby_seq: number;
+}
+
+/** MissedLobbyEvent is a lobby change made while the client was away, with the lobby_seq of the update it was sent in */
+export interface MissedLobbyEvent {
+  lobby_seq: number;
+  event: LobbyEvent;
+  event_data?: unknown;
+}
+
+/** ErrorPayload is the payload for error messages */
+export interface ErrorPayload {
+  code: ErrorCode;
+  message: string;
+  details?: unknown;
+  recoverable: boolean;
+}
+
+/** ErrorCode represents a protocol error code */
+export type ErrorCode =
+  | "AUTH_REQUIRED"
+  | "AUTH_FAILED"
+  | "SESSION_EXPIRED"
+  | "LOBBY_NOT_FOUND"
+  | "LOBBY_FULL"
+  | "INVALID_STATE"
+  | "INVALID_ACTION"
+  | "NOT_YOUR_TURN"
+  | "TURN_MISMATCH"
+  | "ACTION_TIMEOUT"
+  | "MALFORMED_MESSAGE"
+  | "VERSION_MISMATCH"
+  | "INTERNAL_ERROR"
+  | "PLAYER_NOT_IN_LOBBY"
+  | "SPECTATOR_ONLY"
+  | "RATE_LIMITED"
+  | "TOO_MANY_CONNECTIONS"
+  | "REQUEST_TIMEOUT"
+  | "NOT_PRIMARY"
+  | "PAYLOAD_TOO_LARGE";
+
+/** DisconnectWarningPayload warns of impending disconnect */
+export interface DisconnectWarningPayload {
+  reason: DisconnectWarningReason;
+  timeout_at: number;
+}
+
+/** DisconnectWarningReason explains why the server is about to disconnect a client */
+export type DisconnectWarningReason =
+  | "slow_consumer";
+
+/** ServerShutdownPayload is sent to every client before the server closes their connection to shut down */
+export interface ServerShutdownPayload {
+  /** How long to wait before reconnecting, to give the server time to come back */
+  reconnect_after_ms: number;
+}
+
+/** PlayerJoinedEventData is event data for player_joined */
+export interface PlayerJoinedEventData {
+  player_id: string;
+  username: string;
+}
+
+/** PlayerLeftEventData is event data for player_left */
+export interface PlayerLeftEventData {
+  player_id: string;
+}
+
+/** SpectatorJoinedEventData is event data for spectator_joined */
+export interface SpectatorJoinedEventData {
+  spectator_id: string;
+  username: string;
+}
+
+/** SpectatorLeftEventData is event data for spectator_left */
+export interface SpectatorLeftEventData {
+  spectator_id: string;
+}
+
+/** WaitlistJoinedEventData is event data for waitlist_joined */
+export interface WaitlistJoinedEventData {
+  player_id: string;
+  username: string;
+}
+
+/** WaitlistLeftEventData is event data for waitlist_left */
+export interface WaitlistLeftEventData {
+  player_id: string;
+}
+
+/** PlayerReadyChangedEventData is event data for player_ready_changed */
+export interface PlayerReadyChangedEventData {
+  player_id: string;
+  ready: boolean;
+}
+
+/** HostChangedEventData is event data for host_changed */
+export interface HostChangedEventData {
+  new_host_id: string;
+}
+
+/** TeamSubmittedEventData is event data for team_submitted */
+export interface TeamSubmittedEventData {
+  player_id: string;
+}
+
+/** SettingsChangedEventData is event data for settings_changed */
+export interface SettingsChangedEventData {
+  ruleset: string;
+  best_of: number;
+  turn_timer_sec: number;
+  countdown_sec: number;
+  start_mode: string;
+  visibility: string;
+  max_spectators: number;
+  tags: string[];
+}
+
+/** StateChangedEventData is event data for state_changed */
+export interface StateChangedEventData {
+  old_state: string;
+  new_state: string;
+}
+
+/** AttackActionData contains data for an attack action */
+export interface AttackActionData {
+  move_id: string;
+  target_slot: number;
+}
+
+/** SwitchActionData contains data for a switch action */
+export interface SwitchActionData {
+  creature_slot: number;
+}
+
+/** ItemActionData contains data for an item action */
+export interface ItemActionData {
+  item_id: string;
+  target_slot: number;
+}
+
+/** MoveUsedEventData for move_used event */
+export interface MoveUsedEventData {
+  move_id: string;
+}
+
+/** DamageDealtEventData for damage_dealt event */
+export interface DamageDealtEventData {
+  target: string;
+  damage: number;
+  /** super_effective, not_very_effective, normal, no_effect */
+  effectiveness: string;
+  critical?: boolean;
+}
+
+/** StatusAppliedEventData for status_applied event */
+export interface StatusAppliedEventData {
+  target: string;
+  status: string;
+  /** Triggered by a move's secondary effect chance */
+  secondary?: boolean;
+}
+
+/** StatusEndedEventData for status_ended event */
+export interface StatusEndedEventData {
+  target: string;
+  status: string;
+}
+
+/** ConfusionSelfHitEventData for confusion_self_hit event */
+export interface ConfusionSelfHitEventData {
+  target: string;
+  damage: number;
+}
+
+/** LeechSeedDrainEventData for leech_seed_drain event */
+export interface LeechSeedDrainEventData {
+  target: string;
+  damage: number;
+  recipient?: string;
+  healed?: number;
+}
+
+/** RecoilDamageEventData for recoil_damage event */
+export interface RecoilDamageEventData {
+  target: string;
+  damage: number;
+}
+
+/** StatusDamageEventData for status_damage event */
+export interface StatusDamageEventData {
+  target: string;
+  status: string;
+  damage: number;
+}
+
+/** HazardSetEventData for hazard_set event */
+export interface HazardSetEventData {
+  /** Player whose side of the field holds the hazard */
+  side: string;
+  hazard: string;
+  layers: number;
+}
+
+/** HazardDamageEventData for hazard_damage event */
+export interface HazardDamageEventData {
+  target: string;
+  hazard: string;
+  damage: number;
+}
+
+/** HazardClearedEventData for hazard_cleared event */
+export interface HazardClearedEventData {
+  side: string;
+  hazard: string;
+}
+
+/** TerrainEventData for terrain_set and terrain_ended events */
+export interface TerrainEventData {
+  terrain: string;
+}
+
+/** ItemUsedEventData for item_used event */
+export interface ItemUsedEventData {
+  item_id: string;
+  target: string;
+  healed?: number;
+  cured_status?: string;
+}
+
+/** CreatureFaintedEventData for creature_fainted event */
+export interface CreatureFaintedEventData {
+  creature_id: string;
+  owner: string;
+}
+
+/** CreatureSwitchedEventData for creature_switched event */
+export interface CreatureSwitchedEventData {
+  from_slot: number;
+  to_slot: number;
+}
+
+/** StatChangedEventData for stat_changed event */
+export interface StatChangedEventData {
+  target: string;
+  stat: string;
+  /** positive or negative */
+  stages: number;
+  secondary?: boolean;
+}
+
+/** MoveFailedEventData for move_failed event */
+export interface MoveFailedEventData {
+  move_id: string;
+  reason: string;
+}
+
+/** InvalidTeamDetails is the error detail sent when a submitted team is rejected */
+export interface InvalidTeamDetails {
+  violations: TeamViolationInfo[];
+}
+
+/** TeamViolationInfo describes one rule a submitted team breaks. Slot is omitted for violations that apply to the whole team. */
+export interface TeamViolationInfo {
+  slot?: number;
+  rule: string;
+  message: string;
+}
+
+/** InvalidPayloadDetails is the error detail sent when a payload fails validation */
+export interface InvalidPayloadDetails {
+  fields: FieldErrorInfo[];
+}
+
+/** FieldErrorInfo describes one invalid field of a payload */
+export interface FieldErrorInfo {
+  /** Path to the field, such as "team[1].species_id"; empty when the payload as a whole is invalid */
+  field: string;
+  reason: string;
+}
+
+/** PayloadTooLargeDetails is the error detail sent when a payload is over its message type's size limit */
+export interface PayloadTooLargeDetails {
+  /** The payload's size in bytes, as JSON */
+  size: number;
+  /** The most bytes a payload of its type may have */
+  limit: number;
+}
+
+/** VersionMismatchDetails is the error detail sent when a message's protocol version is not accepted */
+export interface VersionMismatchDetails {
+  /** Oldest first */
+  supported_versions: number[];
+  min_version: number;
+  max_version: number;
+  /** The version the connection authenticated with, if it has */
+  connection_version?: number;
+}